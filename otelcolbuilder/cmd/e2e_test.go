@@ -0,0 +1,220 @@
+// Copyright 2022 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/service"
+)
+
+const (
+	// e2eEndpointEnv is the environment variable which is set to the mock backend URL,
+	// use it as the sumologicexporter's endpoint in the tested configuration files.
+	e2eEndpointEnv = "SUMO_MOCK_ENDPOINT"
+	// e2eConfigEnv can be set to a configuration file which should be run against the mock backend.
+	e2eConfigEnv = "E2E_CONFIG"
+	// e2eDurationEnv can be set to specify how long the configuration from e2eConfigEnv should run.
+	e2eDurationEnv = "E2E_DURATION"
+
+	e2eDefaultDuration = 10 * time.Second
+	e2eWaitTimeout     = 30 * time.Second
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files of end-to-end tests")
+
+// TestE2EGolden runs full pipelines (receiver -> processors -> sumologicexporter)
+// against the mock backend and compares the received data with golden files.
+//
+// Each test case directory in testdata/e2e contains:
+// - config.yaml - collector configuration using ${SUMO_MOCK_ENDPOINT} as the exporter's endpoint
+// - input.log - data read by the receiver
+// - expected.txt - sorted lines expected to be received by the backend
+//
+// Run `go test -run TestE2EGolden -update` to regenerate expected.txt files.
+func TestE2EGolden(t *testing.T) {
+	testcases := []struct {
+		name string
+		dir  string
+		// expectedHeaders are checked on every request received by the backend
+		expectedHeaders map[string]string
+	}{
+		{
+			name: "filelog with sumologicexporter sending text logs with gzip compression",
+			dir:  "testdata/e2e/filelog_text_gzip",
+			expectedHeaders: map[string]string{
+				"Content-Type":     "application/x-www-form-urlencoded",
+				"Content-Encoding": "gzip",
+				"X-Sumo-Category":  "e2e/text",
+			},
+		},
+		{
+			name: "filelog with sumologicexporter sending json logs with deflate compression",
+			dir:  "testdata/e2e/filelog_json_deflate",
+			expectedHeaders: map[string]string{
+				"Content-Type":     "application/x-www-form-urlencoded",
+				"Content-Encoding": "deflate",
+			},
+		},
+		{
+			name: "filelog with sumologicexporter sending json_array logs with gzip compression",
+			dir:  "testdata/e2e/filelog_json_array_gzip",
+			expectedHeaders: map[string]string{
+				"Content-Type":     "application/json",
+				"Content-Encoding": "gzip",
+			},
+		},
+		{
+			name: "filelog with sumologicsyslogprocessor and sumologicexporter sending fields",
+			dir:  "testdata/e2e/filelog_syslog_fields",
+			expectedHeaders: map[string]string{
+				"Content-Type":     "application/x-www-form-urlencoded",
+				"Content-Encoding": "gzip",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			expectedFile := filepath.Join(tc.dir, "expected.txt")
+			expected := readGoldenLines(t, expectedFile)
+
+			backend := newMockBackend(t)
+			runCollector(t, filepath.Join(tc.dir, "config.yaml"), backend, func() bool {
+				return len(backend.Lines()) >= len(expected)
+			}, e2eWaitTimeout)
+
+			require.Empty(t, backend.Errors(), "mock backend received invalid requests")
+			for _, req := range backend.Requests() {
+				for header, value := range tc.expectedHeaders {
+					assert.Equal(t, value, req.Headers.Get(header), "unexpected %s header", header)
+				}
+			}
+
+			lines := backend.Lines()
+			sort.Strings(lines)
+
+			if *updateGolden {
+				content := strings.Join(lines, "\n") + "\n"
+				require.NoError(t, os.WriteFile(expectedFile, []byte(content), 0600))
+				return
+			}
+
+			assert.Equal(t, expected, lines)
+		})
+	}
+}
+
+// TestE2EUserConfig runs the configuration file provided with the E2E_CONFIG
+// environment variable against the mock backend. This allows users to check
+// their own configuration files, e.g.:
+//
+//	E2E_CONFIG=/path/to/config.yaml E2E_DURATION=30s go test -run TestE2EUserConfig -v .
+//
+// The configuration should use ${SUMO_MOCK_ENDPOINT} as the sumologicexporter's endpoint.
+func TestE2EUserConfig(t *testing.T) {
+	configFile := os.Getenv(e2eConfigEnv)
+	if configFile == "" {
+		t.Skipf("%s is not set, skipping", e2eConfigEnv)
+	}
+
+	duration := e2eDefaultDuration
+	if d := os.Getenv(e2eDurationEnv); d != "" {
+		var err error
+		duration, err = time.ParseDuration(d)
+		require.NoError(t, err, "invalid %s", e2eDurationEnv)
+	}
+
+	backend := newMockBackend(t)
+	runCollector(t, configFile, backend, func() bool { return false }, duration)
+
+	for _, err := range backend.Errors() {
+		t.Errorf("mock backend received invalid request: %v", err)
+	}
+
+	requests := backend.Requests()
+	assert.NotEmpty(t, requests, "mock backend didn't receive any requests")
+	for _, req := range requests {
+		t.Logf("Received request: path=%s content-type=%s category=%q fields=%q lines=%d",
+			req.Path,
+			req.Headers.Get("Content-Type"),
+			req.Headers.Get("X-Sumo-Category"),
+			req.Headers.Get("X-Sumo-Fields"),
+			strings.Count(string(req.Body), "\n")+1,
+		)
+	}
+}
+
+// runCollector runs the collector with the provided configuration file until done returns true
+// or the timeout elapses.
+func runCollector(t *testing.T, configFile string, backend *mockBackend, done func() bool, timeout time.Duration) {
+	t.Setenv(e2eEndpointEnv, backend.URL())
+
+	factories, err := components()
+	require.NoError(t, err)
+
+	cp := service.MustNewDefaultConfigProvider([]string{configFile}, nil)
+
+	app, err := service.New(service.CollectorSettings{
+		BuildInfo:      component.NewDefaultBuildInfo(),
+		Factories:      factories,
+		ConfigProvider: cp,
+	})
+	require.NoError(t, err)
+
+	go func() {
+		deadline := time.Now().Add(timeout)
+		for app.GetState() != service.Running && time.Now().Before(deadline) {
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		for !done() && time.Now().Before(deadline) {
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		t.Log("Calling .Shutdown()...")
+		app.Shutdown()
+	}()
+
+	require.NoError(t, app.Run(context.Background()))
+}
+
+func readGoldenLines(t *testing.T, path string) []string {
+	content, err := os.ReadFile(path)
+	if *updateGolden && os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
@@ -0,0 +1,232 @@
+// Copyright 2022 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	// Limits enforced by the Sumo Logic ingestion endpoints.
+	mockMaxPayloadSize  = 1024 * 1024
+	mockMaxFieldsCount  = 30
+	mockMaxFieldKeySize = 255
+	mockMaxFieldValSize = 200
+)
+
+// mockContentTypeJSON is the content type of the logs sent as a single JSON array
+const mockContentTypeJSON = "application/json"
+
+var mockContentTypes = map[string]struct{}{
+	"application/x-www-form-urlencoded":    {},
+	mockContentTypeJSON:                    {},
+	"application/vnd.sumologic.prometheus": {},
+	"application/vnd.sumologic.carbon2":    {},
+	"application/vnd.sumologic.graphite":   {},
+	"application/x-protobuf":               {},
+}
+
+// mockRequest is a single request received by the mock backend,
+// with the body already decompressed.
+type mockRequest struct {
+	Path    string
+	Headers http.Header
+	Body    []byte
+}
+
+// mockBackend is a mock Sumo Logic ingestion server which validates incoming
+// requests the same way the real backend would, i.e. it checks headers, formats,
+// compression, payload size and field limits.
+type mockBackend struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	requests []mockRequest
+	errors   []error
+}
+
+func newMockBackend(t *testing.T) *mockBackend {
+	mb := &mockBackend{}
+	mb.srv = httptest.NewServer(http.HandlerFunc(mb.handle))
+	t.Cleanup(mb.srv.Close)
+	return mb
+}
+
+// URL returns the URL which should be used as the exporter's endpoint.
+func (mb *mockBackend) URL() string {
+	return mb.srv.URL
+}
+
+// Requests returns all the valid requests received so far.
+func (mb *mockBackend) Requests() []mockRequest {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	ret := make([]mockRequest, len(mb.requests))
+	copy(ret, mb.requests)
+	return ret
+}
+
+// Errors returns validation errors of all the invalid requests received so far.
+func (mb *mockBackend) Errors() []error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	ret := make([]error, len(mb.errors))
+	copy(ret, mb.errors)
+	return ret
+}
+
+// Lines returns all non-empty lines of all the valid requests' bodies.
+// The JSON array bodies are split into their elements, one per line.
+func (mb *mockBackend) Lines() []string {
+	var lines []string
+	for _, req := range mb.Requests() {
+		if req.Headers.Get("Content-Type") == mockContentTypeJSON {
+			// the body has been validated to be a JSON array
+			var elements []json.RawMessage
+			_ = json.Unmarshal(req.Body, &elements)
+			for _, element := range elements {
+				lines = append(lines, string(element))
+			}
+			continue
+		}
+		for _, line := range strings.Split(string(req.Body), "\n") {
+			if line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
+func (mb *mockBackend) handle(w http.ResponseWriter, req *http.Request) {
+	body, err := validateMockRequest(req)
+
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	if err != nil {
+		mb.errors = append(mb.errors, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	mb.requests = append(mb.requests, mockRequest{
+		Path:    req.URL.Path,
+		Headers: req.Header.Clone(),
+		Body:    body,
+	})
+	w.WriteHeader(http.StatusOK)
+}
+
+func validateMockRequest(req *http.Request) ([]byte, error) {
+	if req.Method != http.MethodPost {
+		return nil, fmt.Errorf("unexpected method: %s", req.Method)
+	}
+
+	if req.Header.Get("X-Sumo-Client") == "" {
+		return nil, fmt.Errorf("missing X-Sumo-Client header")
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	if _, ok := mockContentTypes[contentType]; !ok {
+		return nil, fmt.Errorf("unexpected content type: %q", contentType)
+	}
+
+	if err := validateMockFields(req.Header.Get("X-Sumo-Fields")); err != nil {
+		return nil, err
+	}
+
+	body, err := decompressMockBody(req.Header.Get("Content-Encoding"), req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) > mockMaxPayloadSize {
+		return nil, fmt.Errorf("payload too large: %d bytes, limit is %d bytes", len(body), mockMaxPayloadSize)
+	}
+
+	if contentType == mockContentTypeJSON {
+		var elements []json.RawMessage
+		if err := json.Unmarshal(body, &elements); err != nil {
+			return nil, fmt.Errorf("invalid JSON array payload: %w", err)
+		}
+	}
+
+	return body, nil
+}
+
+func validateMockFields(fields string) error {
+	if fields == "" {
+		return nil
+	}
+
+	pairs := strings.Split(fields, ", ")
+	if len(pairs) > mockMaxFieldsCount {
+		return fmt.Errorf("too many fields: %d, limit is %d", len(pairs), mockMaxFieldsCount)
+	}
+
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf("invalid field: %q", pair)
+		}
+		if len(kv[0]) > mockMaxFieldKeySize {
+			return fmt.Errorf("field key too long: %q", kv[0])
+		}
+		if len(kv[1]) > mockMaxFieldValSize {
+			return fmt.Errorf("field value too long for key %q", kv[0])
+		}
+	}
+
+	return nil
+}
+
+func decompressMockBody(encoding string, body io.Reader) ([]byte, error) {
+	var r io.Reader
+	switch encoding {
+	case "":
+		r = body
+	case "gzip":
+		gr, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip payload: %w", err)
+		}
+		defer gr.Close()
+		r = gr
+	case "deflate":
+		fr := flate.NewReader(body)
+		defer fr.Close()
+		r = fr
+	default:
+		return nil, fmt.Errorf("unexpected content encoding: %q", encoding)
+	}
+
+	var buf bytes.Buffer
+	// Read one byte more than the limit so that too large payloads can be detected.
+	if _, err := io.Copy(&buf, io.LimitReader(r, mockMaxPayloadSize+1)); err != nil {
+		return nil, fmt.Errorf("failed to read %q payload: %w", encoding, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// componentKind is the key of the components list in the builder config
+type componentKind string
+
+const (
+	extensionKind componentKind = "extensions"
+	receiverKind  componentKind = "receivers"
+	processorKind componentKind = "processors"
+	exporterKind  componentKind = "exporters"
+)
+
+var componentKinds = []componentKind{extensionKind, receiverKind, processorKind, exporterKind}
+
+// builderModule is a single entry of the components list in the builder config
+type builderModule struct {
+	GoMod  string `yaml:"gomod"`
+	Import string `yaml:"import"`
+	Name   string `yaml:"name"`
+	Path   string `yaml:"path"`
+}
+
+// importPath returns the path of the package with the component's factory,
+// which defaults to the module path
+func (m builderModule) importPath() string {
+	if m.Import != "" {
+		return m.Import
+	}
+	if fields := strings.Fields(m.GoMod); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// name returns the name of the component, the same as the builder uses
+// for the import alias, i.e. the last element of the import path by default
+func (m builderModule) name() string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return path.Base(m.importPath())
+}
+
+// component is a component of the distribution
+type component struct {
+	Kind       componentKind
+	Name       string
+	ImportPath string
+}
+
+// parseComponents returns the components listed in the builder config
+func parseComponents(config []byte) ([]component, error) {
+	var cfg struct {
+		Extensions []builderModule `yaml:"extensions"`
+		Receivers  []builderModule `yaml:"receivers"`
+		Processors []builderModule `yaml:"processors"`
+		Exporters  []builderModule `yaml:"exporters"`
+	}
+	if err := yaml.Unmarshal(config, &cfg); err != nil {
+		return nil, fmt.Errorf("failed parsing builder config: %w", err)
+	}
+	modules := map[componentKind][]builderModule{
+		extensionKind: cfg.Extensions,
+		receiverKind:  cfg.Receivers,
+		processorKind: cfg.Processors,
+		exporterKind:  cfg.Exporters,
+	}
+
+	var ret []component
+	seen := map[string]componentKind{}
+	for _, kind := range componentKinds {
+		for _, m := range modules[kind] {
+			if m.importPath() == "" {
+				return nil, fmt.Errorf("%s: neither gomod nor import is set", kind)
+			}
+
+			name := m.name()
+			if !token.IsIdentifier(name) {
+				return nil, fmt.Errorf("%s: component name %q is not a valid Go identifier, set it with the name key", kind, name)
+			}
+			if k, ok := seen[name]; ok {
+				return nil, fmt.Errorf("%s: component name %q is already used in %s", kind, name, k)
+			}
+			seen[name] = kind
+
+			ret = append(ret, component{
+				Kind:       kind,
+				Name:       name,
+				ImportPath: m.importPath(),
+			})
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseComponents(t *testing.T) {
+	config, err := os.ReadFile("testdata/builder.yaml")
+	require.NoError(t, err)
+
+	components, err := parseComponents(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, []component{
+		{Kind: extensionKind, Name: "filestorage", ImportPath: "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/filestorage"},
+		{Kind: receiverKind, Name: "filelogreceiver", ImportPath: "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filelogreceiver"},
+		{Kind: receiverKind, Name: "jaegerreceiver", ImportPath: "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/jaegerreceiver"},
+		{Kind: receiverKind, Name: "otlpreceiver", ImportPath: "go.opentelemetry.io/collector/receiver/otlpreceiver"},
+		{Kind: processorKind, Name: "batchprocessor", ImportPath: "go.opentelemetry.io/collector/processor/batchprocessor"},
+		{Kind: processorKind, Name: "spanmetricsprocessor", ImportPath: "github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor"},
+		{Kind: exporterKind, Name: "sumologicexporter", ImportPath: "github.com/SumoLogic/sumologic-otel-collector/pkg/exporter/sumologicexporter"},
+		{Kind: exporterKind, Name: "otlpexporter", ImportPath: "go.opentelemetry.io/collector/exporter/otlpexporter"},
+	}, components)
+}
+
+func TestParseComponentsErrors(t *testing.T) {
+	testcases := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name: "duplicated name",
+			config: `
+receivers:
+  - gomod: "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filelogreceiver v0.46.0"
+exporters:
+  - import: github.com/example/filelogreceiver
+    gomod: github.com/example v0.1.0
+`,
+			err: `exporters: component name "filelogreceiver" is already used in receivers`,
+		},
+		{
+			name: "invalid name",
+			config: `
+receivers:
+  - gomod: "github.com/example/file-log-receiver v0.1.0"
+`,
+			err: `receivers: component name "file-log-receiver" is not a valid Go identifier, set it with the name key`,
+		},
+		{
+			name: "no module",
+			config: `
+receivers:
+  - path: ./../pkg/receiver/filelogreceiver
+`,
+			err: "receivers: neither gomod nor import is set",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseComponents([]byte(tc.config))
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
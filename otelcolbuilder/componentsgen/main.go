@@ -0,0 +1,94 @@
+// componentsgen prepares the sources of the collector distribution generated
+// by opentelemetry-collector-builder, so the components can be left out of the binary:
+//
+//   - `componentsgen filter` writes the builder config with only the components
+//     selected by a components manifest,
+//   - `componentsgen registry` replaces the components.go generated by the builder
+//     with a registry, to which every component is added by a separate file,
+//     excluded from the build with the `exclude_<component name>` build tag.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "filter":
+		err = runFilter(os.Args[2:])
+	case "registry":
+		err = runRegistry(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "componentsgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: componentsgen filter -config <builder config> -manifest <manifest> -output <filtered builder config>")
+	fmt.Fprintln(os.Stderr, "       componentsgen registry -config <builder config> -output-dir <generated sources directory>")
+}
+
+func runFilter(args []string) error {
+	fs := flag.NewFlagSet("filter", flag.ExitOnError)
+	configPath := fs.String("config", ".otelcol-builder.yaml", "path of the builder config")
+	manifestPath := fs.String("manifest", "", "path of the components manifest")
+	outputPath := fs.String("output", "", "path the filtered builder config is written to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *manifestPath == "" || *outputPath == "" {
+		return fmt.Errorf("both -manifest and -output have to be specified")
+	}
+
+	config, err := os.ReadFile(*configPath)
+	if err != nil {
+		return err
+	}
+
+	m, err := readManifest(*manifestPath)
+	if err != nil {
+		return err
+	}
+
+	filtered, err := filterConfig(config, m)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(*outputPath, filtered, 0o644)
+}
+
+func runRegistry(args []string) error {
+	fs := flag.NewFlagSet("registry", flag.ExitOnError)
+	configPath := fs.String("config", ".otelcol-builder.yaml", "path of the builder config")
+	outputDir := fs.String("output-dir", "./cmd", "directory with the sources generated by the builder")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	config, err := os.ReadFile(*configPath)
+	if err != nil {
+		return err
+	}
+
+	components, err := parseComponents(config)
+	if err != nil {
+		return err
+	}
+
+	return writeRegistry(components, *outputDir)
+}
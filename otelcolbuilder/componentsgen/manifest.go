@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// manifest selects the components of the distribution by their names.
+// When the include list of a kind is not empty, only the listed components
+// of the kind are kept. The components on the exclude list are always removed.
+type manifest struct {
+	Include map[componentKind][]string `yaml:"include"`
+	Exclude map[componentKind][]string `yaml:"exclude"`
+}
+
+func readManifest(path string) (manifest, error) {
+	var m manifest
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		return m, fmt.Errorf("failed parsing manifest %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// keep returns true if the component is selected by the manifest
+func (m manifest) keep(c component) bool {
+	if include := m.Include[c.Kind]; len(include) > 0 && !contains(include, c.Name) {
+		return false
+	}
+	return !contains(m.Exclude[c.Kind], c.Name)
+}
+
+// validate checks that all the components listed in the manifest are in the builder config,
+// so a typo doesn't silently leave a component in the distribution
+func (m manifest) validate(components []component) error {
+	known := map[componentKind][]string{}
+	for _, c := range components {
+		known[c.Kind] = append(known[c.Kind], c.Name)
+	}
+
+	for _, lists := range []map[componentKind][]string{m.Include, m.Exclude} {
+		kinds := make([]string, 0, len(lists))
+		for kind := range lists {
+			kinds = append(kinds, string(kind))
+		}
+		sort.Strings(kinds)
+
+		for _, kind := range kinds {
+			if !isComponentKind(componentKind(kind)) {
+				return fmt.Errorf("unknown component kind: %s", kind)
+			}
+			for _, name := range lists[componentKind(kind)] {
+				if !contains(known[componentKind(kind)], name) {
+					return fmt.Errorf("%s: unknown component: %s", kind, name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// filterConfig returns the builder config with only the components selected by the manifest.
+// The rest of the config, including the comments, is kept as it is.
+func filterConfig(config []byte, m manifest) ([]byte, error) {
+	components, err := parseComponents(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.validate(components); err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(config, &doc); err != nil {
+		return nil, fmt.Errorf("failed parsing builder config: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("builder config is not a mapping")
+	}
+
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		kind := componentKind(root.Content[i].Value)
+		list := root.Content[i+1]
+		if !isComponentKind(kind) || list.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		kept := list.Content[:0]
+		for _, item := range list.Content {
+			var bm builderModule
+			if err := item.Decode(&bm); err != nil {
+				return nil, fmt.Errorf("%s: %w", kind, err)
+			}
+			if m.keep(component{Kind: kind, Name: bm.name(), ImportPath: bm.importPath()}) {
+				kept = append(kept, item)
+			}
+		}
+		list.Content = kept
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func isComponentKind(kind componentKind) bool {
+	for _, k := range componentKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
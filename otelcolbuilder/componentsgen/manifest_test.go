@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func componentNames(components []component) []string {
+	names := make([]string, 0, len(components))
+	for _, c := range components {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestFilterConfig(t *testing.T) {
+	config, err := os.ReadFile("testdata/builder.yaml")
+	require.NoError(t, err)
+
+	m, err := readManifest("testdata/logs.yaml")
+	require.NoError(t, err)
+
+	filtered, err := filterConfig(config, m)
+	require.NoError(t, err)
+
+	components, err := parseComponents(filtered)
+	require.NoError(t, err)
+	assert.Equal(t,
+		[]string{"filestorage", "filelogreceiver", "otlpreceiver", "batchprocessor", "sumologicexporter", "otlpexporter"},
+		componentNames(components),
+	)
+
+	// the rest of the config is kept
+	assert.Contains(t, string(filtered), "output_path: ./cmd")
+	assert.Contains(t, string(filtered), "path: ./../pkg/exporter/sumologicexporter")
+	assert.Contains(t, string(filtered), "# Replacement paths are relative to the output_path (location of source files)")
+	assert.Contains(t, string(filtered), "internal/stanza => github.com/open-telemetry/opentelemetry-collector-contrib/internal/stanza v0.46.0")
+}
+
+func TestFilterConfigUnknownComponent(t *testing.T) {
+	config, err := os.ReadFile("testdata/builder.yaml")
+	require.NoError(t, err)
+
+	m := manifest{
+		Exclude: map[componentKind][]string{
+			receiverKind: {"filelogreceiver", "fileloggreceiver"},
+		},
+	}
+	_, err = filterConfig(config, m)
+	assert.EqualError(t, err, "receivers: unknown component: fileloggreceiver")
+
+	m = manifest{
+		Include: map[componentKind][]string{
+			"connectors": {"forward"},
+		},
+	}
+	_, err = filterConfig(config, m)
+	assert.EqualError(t, err, "unknown component kind: connectors")
+}
+
+func TestReadManifestUnknownField(t *testing.T) {
+	path := t.TempDir() + "/manifest.yaml"
+	require.NoError(t, os.WriteFile(path, []byte("excluded:\n  receivers: [filelogreceiver]\n"), 0o600))
+
+	_, err := readManifest(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const generatedHeader = "// Code generated by componentsgen. DO NOT EDIT."
+
+var registryTemplate = template.Must(template.New("components.go").Parse(generatedHeader + `
+
+package main
+
+import (
+	"go.opentelemetry.io/collector/component"
+)
+
+// The factories are added by the components_<component name>.go files,
+// which are excluded from the build with the exclude_<component name> build tags.
+var (
+	extensionFactories []component.ExtensionFactory
+	receiverFactories  []component.ReceiverFactory
+	processorFactories []component.ProcessorFactory
+	exporterFactories  []component.ExporterFactory
+)
+
+func components() (component.Factories, error) {
+	var err error
+	factories := component.Factories{}
+
+	factories.Extensions, err = component.MakeExtensionFactoryMap(extensionFactories...)
+	if err != nil {
+		return component.Factories{}, err
+	}
+
+	factories.Receivers, err = component.MakeReceiverFactoryMap(receiverFactories...)
+	if err != nil {
+		return component.Factories{}, err
+	}
+
+	factories.Processors, err = component.MakeProcessorFactoryMap(processorFactories...)
+	if err != nil {
+		return component.Factories{}, err
+	}
+
+	factories.Exporters, err = component.MakeExporterFactoryMap(exporterFactories...)
+	if err != nil {
+		return component.Factories{}, err
+	}
+
+	return factories, nil
+}
+`))
+
+var componentTemplate = template.Must(template.New("component").Parse(generatedHeader + `
+
+//go:build !{{ .BuildTag }}
+// +build !{{ .BuildTag }}
+
+package main
+
+import (
+	{{ .Name }} "{{ .ImportPath }}"
+)
+
+func init() {
+	{{ .Registry }} = append({{ .Registry }}, {{ .Name }}.NewFactory())
+}
+`))
+
+var registries = map[componentKind]string{
+	extensionKind: "extensionFactories",
+	receiverKind:  "receiverFactories",
+	processorKind: "processorFactories",
+	exporterKind:  "exporterFactories",
+}
+
+// buildTag returns the build tag excluding the component from the build
+func buildTag(c component) string {
+	return "exclude_" + c.Name
+}
+
+// writeRegistry writes components.go with the registry of the factories
+// and a components_<component name>.go file for every component to dir.
+// The files written by the previous runs are removed first.
+func writeRegistry(components []component, dir string) error {
+	if err := removeGeneratedFiles(dir); err != nil {
+		return err
+	}
+
+	if err := writeSource(filepath.Join(dir, "components.go"), registryTemplate, nil); err != nil {
+		return err
+	}
+
+	for _, c := range components {
+		data := struct {
+			Name       string
+			ImportPath string
+			BuildTag   string
+			Registry   string
+		}{
+			Name:       c.Name,
+			ImportPath: c.ImportPath,
+			BuildTag:   buildTag(c),
+			Registry:   registries[c.Kind],
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("components_%s.go", c.Name))
+		if err := writeSource(path, componentTemplate, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSource(path string, tmpl *template.Template, data interface{}) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed formatting %s: %w", path, err)
+	}
+
+	return os.WriteFile(path, src, 0o644)
+}
+
+// removeGeneratedFiles removes the components_*.go files generated by componentsgen,
+// so the components removed from the builder config don't stay in the registry
+func removeGeneratedFiles(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "components_*.go"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(string(b), generatedHeader) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
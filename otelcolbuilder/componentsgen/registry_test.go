@@ -0,0 +1,63 @@
+package main
+
+import (
+	"go/build/constraint"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildConstraint returns the //go:build constraint of the source
+func buildConstraint(t *testing.T, src string) constraint.Expr {
+	for _, line := range strings.Split(src, "\n") {
+		if constraint.IsGoBuild(line) {
+			expr, err := constraint.Parse(line)
+			require.NoError(t, err)
+			return expr
+		}
+	}
+	t.Fatal("no build constraint")
+	return nil
+}
+
+func TestWriteRegistry(t *testing.T) {
+	dir := t.TempDir()
+
+	// files left by the previous run and the files not generated by componentsgen
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "components_jaegerreceiver.go"), []byte(generatedHeader+"\n\npackage main\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "components_custom.go"), []byte("package main\n"), 0o600))
+
+	components := []component{
+		{Kind: receiverKind, Name: "filelogreceiver", ImportPath: "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filelogreceiver"},
+		{Kind: exporterKind, Name: "sumologicexporter", ImportPath: "github.com/SumoLogic/sumologic-otel-collector/pkg/exporter/sumologicexporter"},
+	}
+	require.NoError(t, writeRegistry(components, dir))
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	require.NoError(t, err)
+	for i := range paths {
+		paths[i] = filepath.Base(paths[i])
+	}
+	assert.ElementsMatch(t,
+		[]string{"components.go", "components_custom.go", "components_filelogreceiver.go", "components_sumologicexporter.go"},
+		paths,
+	)
+
+	registry, err := os.ReadFile(filepath.Join(dir, "components.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(registry), "func components() (component.Factories, error) {")
+
+	src, err := os.ReadFile(filepath.Join(dir, "components_sumologicexporter.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(src), `sumologicexporter "github.com/SumoLogic/sumologic-otel-collector/pkg/exporter/sumologicexporter"`)
+	assert.Contains(t, string(src), "exporterFactories = append(exporterFactories, sumologicexporter.NewFactory())")
+
+	expr := buildConstraint(t, string(src))
+	assert.True(t, expr.Eval(func(tag string) bool { return tag == "enable_unstable" }))
+	assert.False(t, expr.Eval(func(tag string) bool { return tag == "exclude_sumologicexporter" }))
+	assert.True(t, expr.Eval(func(tag string) bool { return tag == "exclude_filelogreceiver" }))
+}
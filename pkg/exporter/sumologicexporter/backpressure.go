@@ -0,0 +1,185 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+// ErrBackpressure is returned to the receivers instead of accepting the data
+// while sending the data of the pipeline keeps failing. It's not a permanent error,
+// so the receivers honoring backpressure can ask the producers to retry later.
+var ErrBackpressure = errors.New("sumologic exporter is applying backpressure, sending data keeps failing")
+
+// backpressure tracks the send failures of the pipelines and rejects the new data
+// of the pipeline which has been failing for at least the failure duration.
+// The pipeline recovers after a successful send, or when no send failed
+// for longer than the failure duration, e.g. because the sending queue was drained.
+type backpressure struct {
+	logger          *zap.Logger
+	failureDuration time.Duration
+	now             func() time.Time
+
+	lock     sync.Mutex
+	failures map[PipelineType]failurePeriod
+	active   map[PipelineType]bool
+}
+
+// failurePeriod is the period of the consecutive send failures of the pipeline
+type failurePeriod struct {
+	first time.Time
+	last  time.Time
+}
+
+func newBackpressure(cfg BackpressureConfig, logger *zap.Logger) *backpressure {
+	return &backpressure{
+		logger:          logger.Named("backpressure"),
+		failureDuration: cfg.FailureDuration,
+		now:             time.Now,
+		failures:        make(map[PipelineType]failurePeriod),
+		active:          make(map[PipelineType]bool),
+	}
+}
+
+// onPushed records the result of sending the data of the pipeline. Only the failures
+// which mean that the backend can't accept the data are counted, other errors
+// leave the state unchanged.
+func (b *backpressure) onPushed(ctx context.Context, pipeline PipelineType, err error) {
+	if b == nil {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch {
+	case err == nil:
+		delete(b.failures, pipeline)
+		b.setActive(pipeline, false)
+	case isRetryableError(ctx, err), errors.Is(err, errCircuitBreakerOpen), errors.Is(err, errThrottled),
+		errors.Is(err, errRetryBudgetExhausted):
+		now := b.now()
+		period, ok := b.failures[pipeline]
+		if !ok || now.Sub(period.last) > b.failureDuration {
+			period.first = now
+		}
+		period.last = now
+		b.failures[pipeline] = period
+		b.update(pipeline)
+	}
+}
+
+// check returns ErrBackpressure when the pipeline has been failing
+// for at least the failure duration
+func (b *backpressure) check(pipeline PipelineType) error {
+	if b == nil {
+		return nil
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.update(pipeline) {
+		recordBackpressureRejection(pipeline)
+		return ErrBackpressure
+	}
+	return nil
+}
+
+// update activates the backpressure of the pipeline when it has been failing
+// for the failure duration, deactivates it when it has not failed recently
+// and returns whether it's active. It has to be called with the lock held.
+func (b *backpressure) update(pipeline PipelineType) bool {
+	period, ok := b.failures[pipeline]
+	now := b.now()
+	switch {
+	case !ok:
+	case now.Sub(period.last) > b.failureDuration:
+		delete(b.failures, pipeline)
+		b.setActive(pipeline, false)
+	case period.last.Sub(period.first) >= b.failureDuration:
+		b.setActive(pipeline, true)
+	}
+	return b.active[pipeline]
+}
+
+// setActive changes the state of the pipeline and records it, it has to be called with the lock held
+func (b *backpressure) setActive(pipeline PipelineType, active bool) {
+	if b.active[pipeline] == active {
+		return
+	}
+	b.active[pipeline] = active
+
+	if active {
+		b.logger.Warn("Sending data keeps failing, rejecting new data",
+			zap.String("pipeline", string(pipeline)),
+			zap.Duration("failure_duration", b.failureDuration),
+		)
+	} else {
+		b.logger.Info("Sending data recovered, accepting new data",
+			zap.String("pipeline", string(pipeline)),
+		)
+	}
+	recordBackpressureState(pipeline, active)
+}
+
+// backpressureLogsExporter rejects the logs with ErrBackpressure
+// before they are put in the sending queue
+type backpressureLogsExporter struct {
+	component.LogsExporter
+	backpressure *backpressure
+}
+
+func (e *backpressureLogsExporter) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	if err := e.backpressure.check(LogsPipeline); err != nil {
+		return err
+	}
+	return e.LogsExporter.ConsumeLogs(ctx, ld)
+}
+
+// backpressureMetricsExporter rejects the metrics with ErrBackpressure
+// before they are put in the sending queue
+type backpressureMetricsExporter struct {
+	component.MetricsExporter
+	backpressure *backpressure
+}
+
+func (e *backpressureMetricsExporter) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	if err := e.backpressure.check(MetricsPipeline); err != nil {
+		return err
+	}
+	return e.MetricsExporter.ConsumeMetrics(ctx, md)
+}
+
+// backpressureTracesExporter rejects the traces with ErrBackpressure
+// before they are put in the sending queue
+type backpressureTracesExporter struct {
+	component.TracesExporter
+	backpressure *backpressure
+}
+
+func (e *backpressureTracesExporter) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if err := e.backpressure.check(TracesPipeline); err != nil {
+		return err
+	}
+	return e.TracesExporter.ConsumeTraces(ctx, td)
+}
@@ -0,0 +1,108 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+func TestBackpressure(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	bp := newBackpressure(BackpressureConfig{FailureDuration: time.Minute}, zap.NewNop())
+	bp.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	serverErr := &statusError{statusCode: http.StatusServiceUnavailable, err: errors.New("unavailable")}
+
+	bp.onPushed(ctx, LogsPipeline, serverErr)
+	assert.NoError(t, bp.check(LogsPipeline))
+
+	// client errors don't interrupt the failure period
+	now = now.Add(30 * time.Second)
+	bp.onPushed(ctx, LogsPipeline, &statusError{statusCode: http.StatusBadRequest, err: errors.New("bad request")})
+	bp.onPushed(ctx, LogsPipeline, errCircuitBreakerOpen)
+	assert.NoError(t, bp.check(LogsPipeline))
+
+	now = now.Add(30 * time.Second)
+	bp.onPushed(ctx, LogsPipeline, serverErr)
+	assert.ErrorIs(t, bp.check(LogsPipeline), ErrBackpressure)
+	assert.NoError(t, bp.check(MetricsPipeline))
+
+	// a successful send ends the backpressure
+	bp.onPushed(ctx, LogsPipeline, nil)
+	assert.NoError(t, bp.check(LogsPipeline))
+}
+
+func TestBackpressureWithoutRecentFailures(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	bp := newBackpressure(BackpressureConfig{FailureDuration: time.Minute}, zap.NewNop())
+	bp.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	serverErr := &statusError{statusCode: http.StatusBadGateway, err: errors.New("bad gateway")}
+
+	bp.onPushed(ctx, TracesPipeline, serverErr)
+	now = now.Add(time.Minute)
+	bp.onPushed(ctx, TracesPipeline, serverErr)
+	assert.ErrorIs(t, bp.check(TracesPipeline), ErrBackpressure)
+
+	// nothing failed for longer than the failure duration, e.g. the sending queue was drained
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, bp.check(TracesPipeline))
+
+	// the failures separated by more than the failure duration start a new period
+	bp.onPushed(ctx, TracesPipeline, serverErr)
+	assert.NoError(t, bp.check(TracesPipeline))
+}
+
+// logsSinkExporter is the logs exporter storing the consumed logs
+type logsSinkExporter struct {
+	consumertest.LogsSink
+}
+
+func (e *logsSinkExporter) Start(context.Context, component.Host) error { return nil }
+
+func (e *logsSinkExporter) Shutdown(context.Context) error { return nil }
+
+func TestBackpressureLogsExporter(t *testing.T) {
+	bp := newBackpressure(BackpressureConfig{FailureDuration: time.Minute}, zap.NewNop())
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	bp.now = func() time.Time { return now }
+
+	sink := &logsSinkExporter{}
+	exp := &backpressureLogsExporter{LogsExporter: sink, backpressure: bp}
+
+	require.NoError(t, exp.ConsumeLogs(context.Background(), pdata.NewLogs()))
+	assert.Len(t, sink.AllLogs(), 1)
+
+	serverErr := &statusError{statusCode: http.StatusInternalServerError, err: errors.New("internal server error")}
+	bp.onPushed(context.Background(), LogsPipeline, serverErr)
+	now = now.Add(time.Minute)
+	bp.onPushed(context.Background(), LogsPipeline, serverErr)
+
+	assert.ErrorIs(t, exp.ConsumeLogs(context.Background(), pdata.NewLogs()), ErrBackpressure)
+	assert.Len(t, sink.AllLogs(), 1)
+}
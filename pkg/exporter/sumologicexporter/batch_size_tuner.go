@@ -0,0 +1,152 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// minAdaptiveBatchingWindowSize is the lowest number of requests the p95 is computed from
+	minAdaptiveBatchingWindowSize = 20
+	// maxBatchSizeAdjustment limits how much the limit changes after a single window,
+	// so a window of untypical requests doesn't move it too far
+	maxBatchSizeAdjustment = 2
+)
+
+// batchSizeTuner tunes the request body size limits of the pipelines, so the p95
+// of the request payload sizes gets to the target. As the batches are flushed when
+// they reach the limit, the payload sizes follow it, scaled by the compression ratio.
+type batchSizeTuner struct {
+	logger *zap.Logger
+	target int64
+	window int
+	min    int
+	max    int
+
+	lock      sync.Mutex
+	pipelines map[PipelineType]*batchSizeTunerState
+}
+
+type batchSizeTunerState struct {
+	limit int
+	sizes []int64
+}
+
+func newBatchSizeTuner(cfg AdaptiveBatchingConfig, max int, logger *zap.Logger) *batchSizeTuner {
+	min := minRequestBodySize
+	if max < min {
+		min = max
+	}
+	return &batchSizeTuner{
+		logger:    logger,
+		target:    int64(cfg.TargetPayloadSize),
+		window:    cfg.WindowSize,
+		min:       min,
+		max:       max,
+		pipelines: make(map[PipelineType]*batchSizeTunerState),
+	}
+}
+
+// get returns the tuned request body size limit of the pipeline
+func (t *batchSizeTuner) get(pipeline PipelineType) int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if state, ok := t.pipelines[pipeline]; ok {
+		return state.limit
+	}
+	return t.max
+}
+
+// observe records the payload size of the request sent in the pipeline
+// and tunes its limit when the window is full
+func (t *batchSizeTuner) observe(pipeline PipelineType, payloadSize int64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	state, ok := t.pipelines[pipeline]
+	if !ok {
+		state = &batchSizeTunerState{
+			limit: t.max,
+			sizes: make([]int64, 0, t.window),
+		}
+		t.pipelines[pipeline] = state
+	}
+
+	state.sizes = append(state.sizes, payloadSize)
+	if len(state.sizes) < t.window {
+		return
+	}
+
+	p95 := percentile(state.sizes, 0.95)
+	state.sizes = state.sizes[:0]
+	if p95 <= 0 {
+		return
+	}
+
+	limit := t.adjust(state.limit, p95)
+	if limit == state.limit {
+		return
+	}
+
+	t.logger.Debug("Tuned the request body size limit",
+		zap.String("pipeline", string(pipeline)),
+		zap.Int64("p95_payload_size", p95),
+		zap.Int("previous_limit", state.limit),
+		zap.Int("limit", limit),
+	)
+	state.limit = limit
+	recordRequestBodySizeLimit(pipeline, limit)
+}
+
+// adjust scales the limit by the ratio of the target and the p95 of the payload sizes,
+// by at most maxBatchSizeAdjustment times and within the min and max limits
+func (t *batchSizeTuner) adjust(limit int, p95 int64) int {
+	scaled := int64(float64(limit) * float64(t.target) / float64(p95))
+
+	if lower := int64(limit / maxBatchSizeAdjustment); scaled < lower {
+		scaled = lower
+	}
+	if upper := int64(limit) * maxBatchSizeAdjustment; scaled > upper {
+		scaled = upper
+	}
+	if scaled < int64(t.min) {
+		scaled = int64(t.min)
+	}
+	if scaled > int64(t.max) {
+		scaled = int64(t.max)
+	}
+	return int(scaled)
+}
+
+// percentile returns the p-th percentile of the values using the nearest-rank method,
+// the values are sorted in place
+func percentile(values []int64, p float64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	rank := int(math.Ceil(p * float64(len(values))))
+	if rank < 1 {
+		rank = 1
+	}
+	return values[rank-1]
+}
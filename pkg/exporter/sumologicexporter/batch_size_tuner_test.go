@@ -0,0 +1,94 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestPercentile(t *testing.T) {
+	values := make([]int64, 100)
+	for i := range values {
+		values[i] = int64(100 - i)
+	}
+
+	assert.EqualValues(t, 95, percentile(values, 0.95))
+	assert.EqualValues(t, 50, percentile(values, 0.5))
+	assert.EqualValues(t, 1, percentile(values, 0))
+	assert.EqualValues(t, 100, percentile(values, 1))
+	assert.EqualValues(t, 0, percentile(nil, 0.95))
+	assert.EqualValues(t, 7, percentile([]int64{7}, 0.95))
+}
+
+func TestBatchSizeTuner(t *testing.T) {
+	const window = 20
+	tuner := newBatchSizeTuner(AdaptiveBatchingConfig{
+		Enabled:           true,
+		TargetPayloadSize: 200_000,
+		WindowSize:        window,
+	}, 1_000_000, zap.NewNop())
+
+	observe := func(pipeline PipelineType, size int64) {
+		for i := 0; i < window; i++ {
+			tuner.observe(pipeline, size)
+		}
+	}
+
+	assert.Equal(t, 1_000_000, tuner.get(LogsPipeline))
+
+	// the limit is lowered by at most half after a window
+	observe(LogsPipeline, 800_000)
+	assert.Equal(t, 500_000, tuner.get(LogsPipeline))
+	assert.Equal(t, 1_000_000, tuner.get(MetricsPipeline), "other pipelines are not tuned")
+
+	// the payloads are compressed 2 times, so the limit is 2 times the target
+	observe(LogsPipeline, 500_000)
+	assert.Equal(t, 250_000, tuner.get(LogsPipeline))
+	observe(LogsPipeline, 125_000)
+	assert.Equal(t, 400_000, tuner.get(LogsPipeline))
+	observe(LogsPipeline, 200_000)
+	assert.Equal(t, 400_000, tuner.get(LogsPipeline))
+
+	// the limit is not raised above the max
+	observe(LogsPipeline, 10_000)
+	assert.Equal(t, 800_000, tuner.get(LogsPipeline))
+	observe(LogsPipeline, 10_000)
+	assert.Equal(t, 1_000_000, tuner.get(LogsPipeline))
+
+	// nor lowered below the min
+	for i := 0; i < 10; i++ {
+		observe(LogsPipeline, 10_000_000)
+	}
+	assert.Equal(t, minRequestBodySize, tuner.get(LogsPipeline))
+}
+
+func TestBatchSizeTunerWindow(t *testing.T) {
+	tuner := newBatchSizeTuner(AdaptiveBatchingConfig{
+		Enabled:           true,
+		TargetPayloadSize: 100,
+		WindowSize:        20,
+	}, 1_000_000, zap.NewNop())
+
+	// the outliers above p95 don't lower the limit
+	for i := 0; i < 19; i++ {
+		tuner.observe(TracesPipeline, 100)
+	}
+	assert.Equal(t, 1_000_000, tuner.get(TracesPipeline), "window is not full yet")
+	tuner.observe(TracesPipeline, 10_000)
+	assert.Equal(t, 1_000_000, tuner.get(TracesPipeline))
+}
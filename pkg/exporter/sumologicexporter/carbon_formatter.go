@@ -107,3 +107,16 @@ func carbon2Metric2String(record metricPair) string {
 
 	return strings.Join(nextLines, "\n")
 }
+
+// carbon2Formatter implements MetricFormatter for the carbon2 metric format
+type carbon2Formatter struct{}
+
+// Format returns the metric as carbon2 formatted lines
+func (carbon2Formatter) Format(metric pdata.Metric, attributes pdata.AttributeMap) (string, error) {
+	return carbon2Metric2String(metricPair{metric: metric, attributes: attributes}), nil
+}
+
+// ContentType returns content type of the carbon2 metrics
+func (carbon2Formatter) ContentType() string {
+	return contentTypeCarbon2
+}
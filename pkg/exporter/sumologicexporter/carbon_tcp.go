@@ -0,0 +1,167 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// errCarbonUnavailable is returned by the writes made while waiting to reconnect
+var errCarbonUnavailable = errors.New("carbon endpoint is unavailable")
+
+// carbonTCPClient writes the metrics in graphite format directly to a Carbon
+// plaintext TCP endpoint. The connection is kept open between the writes.
+// When connecting fails, the next attempt is made after the reconnect interval,
+// which doubles with every consecutive failure, and the writes in the meantime
+// fail without connecting.
+type carbonTCPClient struct {
+	cfg    GraphiteTCPConfig
+	logger *zap.Logger
+	dial   func(ctx context.Context, network, address string) (net.Conn, error)
+
+	mu                sync.Mutex
+	conn              net.Conn
+	reconnectInterval time.Duration
+	nextReconnect     time.Time
+}
+
+func newCarbonTCPClient(cfg GraphiteTCPConfig, logger *zap.Logger) *carbonTCPClient {
+	return &carbonTCPClient{
+		cfg:    cfg,
+		logger: logger.Named("carbon_tcp"),
+		dial:   (&net.Dialer{}).DialContext,
+	}
+}
+
+// send writes the newline separated metrics from body to the Carbon endpoint
+func (c *carbonTCPClient) send(ctx context.Context, body io.Reader) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	// Carbon plaintext protocol requires every line to be terminated with a newline
+	if data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	conn, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(c.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetWriteDeadline(deadline); err != nil {
+		c.closeConn()
+		return err
+	}
+
+	if _, err := conn.Write(data); err != nil {
+		c.logger.Warn("Failed writing to Carbon endpoint, the connection is going to be reestablished",
+			zap.String("endpoint", c.cfg.Endpoint),
+			zap.Error(err),
+		)
+		c.closeConn()
+		return err
+	}
+	return nil
+}
+
+// connect returns the open connection or establishes a new one, unless
+// the previous attempt failed and the reconnect interval has not passed yet.
+// It has to be called with the lock held.
+func (c *carbonTCPClient) connect(ctx context.Context) (net.Conn, error) {
+	if c.conn != nil {
+		return c.conn, nil
+	}
+
+	if now := time.Now(); now.Before(c.nextReconnect) {
+		return nil, fmt.Errorf(
+			"%w, reconnecting to %s in %s",
+			errCarbonUnavailable, c.cfg.Endpoint, c.nextReconnect.Sub(now).Round(time.Millisecond),
+		)
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	conn, err := c.dial(dialCtx, "tcp", c.cfg.Endpoint)
+	if err != nil {
+		if c.reconnectInterval == 0 {
+			c.reconnectInterval = c.cfg.InitialReconnectInterval
+		} else {
+			c.reconnectInterval *= 2
+			if c.reconnectInterval > c.cfg.MaxReconnectInterval {
+				c.reconnectInterval = c.cfg.MaxReconnectInterval
+			}
+		}
+		c.nextReconnect = time.Now().Add(c.reconnectInterval)
+
+		c.logger.Warn("Failed connecting to Carbon endpoint",
+			zap.String("endpoint", c.cfg.Endpoint),
+			zap.Duration("reconnect_interval", c.reconnectInterval),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if c.reconnectInterval > 0 {
+		c.logger.Info("Reconnected to Carbon endpoint", zap.String("endpoint", c.cfg.Endpoint))
+	}
+	c.reconnectInterval = 0
+	c.nextReconnect = time.Time{}
+	c.conn = conn
+	return conn, nil
+}
+
+// closeConn closes the connection, so the next write reconnects.
+// It has to be called with the lock held.
+func (c *carbonTCPClient) closeConn() {
+	if c.conn == nil {
+		return
+	}
+	if err := c.conn.Close(); err != nil {
+		c.logger.Debug("Failed closing connection to Carbon endpoint", zap.Error(err))
+	}
+	c.conn = nil
+}
+
+func (c *carbonTCPClient) shutdown() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
@@ -0,0 +1,177 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+func testCarbonTCPConfig(endpoint string) GraphiteTCPConfig {
+	return GraphiteTCPConfig{
+		Endpoint:                 endpoint,
+		Timeout:                  time.Second,
+		InitialReconnectInterval: time.Second,
+		MaxReconnectInterval:     4 * time.Second,
+	}
+}
+
+// startCarbonServer starts a TCP server which sends the received lines to the returned channel
+func startCarbonServer(t *testing.T) (string, <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	lines := make(chan string, 100)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}()
+		}
+	}()
+
+	return ln.Addr().String(), lines
+}
+
+func receiveLines(t *testing.T, lines <-chan string, n int) []string {
+	var ret []string
+	for i := 0; i < n; i++ {
+		select {
+		case l := <-lines:
+			ret = append(ret, l)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for line %d", i+1)
+		}
+	}
+	return ret
+}
+
+func TestCarbonTCPClientSend(t *testing.T) {
+	endpoint, lines := startCarbonServer(t)
+	c := newCarbonTCPClient(testCarbonTCPConfig(endpoint), zap.NewNop())
+	t.Cleanup(func() { assert.NoError(t, c.shutdown()) })
+
+	require.NoError(t, c.send(context.Background(), strings.NewReader("a.b 1 1605534165\na.c 2 1605534165")))
+	require.NoError(t, c.send(context.Background(), strings.NewReader("a.d 3 1605534166\n")))
+
+	assert.Equal(t,
+		[]string{"a.b 1 1605534165", "a.c 2 1605534165", "a.d 3 1605534166"},
+		receiveLines(t, lines, 3),
+	)
+}
+
+func TestCarbonTCPClientReconnectBackoff(t *testing.T) {
+	dials := 0
+	c := newCarbonTCPClient(testCarbonTCPConfig("localhost:2003"), zap.NewNop())
+	c.dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		dials++
+		return nil, &net.OpError{Op: "dial", Net: network, Err: errors.New("connection refused")}
+	}
+
+	err := c.send(context.Background(), strings.NewReader("a.b 1 1605534165"))
+	assert.EqualError(t, err, "dial tcp: connection refused")
+	assert.Equal(t, 1, dials)
+	assert.Equal(t, time.Second, c.reconnectInterval)
+
+	// the writes fail without connecting until the reconnect interval passes
+	err = c.send(context.Background(), strings.NewReader("a.b 1 1605534165"))
+	assert.ErrorIs(t, err, errCarbonUnavailable)
+	assert.Equal(t, 1, dials)
+
+	for _, expected := range []time.Duration{2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		c.nextReconnect = time.Now().Add(-time.Millisecond)
+		assert.Error(t, c.send(context.Background(), strings.NewReader("a.b 1 1605534165")))
+		assert.Equal(t, expected, c.reconnectInterval)
+	}
+	assert.Equal(t, 4, dials)
+
+	// successful connection resets the backoff
+	client, server := net.Pipe()
+	t.Cleanup(func() { server.Close() })
+	go func() {
+		_, _ = bufio.NewReader(server).ReadString('\n')
+	}()
+	c.dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return client, nil
+	}
+	c.nextReconnect = time.Now().Add(-time.Millisecond)
+	require.NoError(t, c.send(context.Background(), strings.NewReader("a.b 1 1605534165")))
+	assert.Zero(t, c.reconnectInterval)
+	assert.NoError(t, c.shutdown())
+}
+
+func TestCarbonTCPClientReconnectsAfterWriteError(t *testing.T) {
+	dials := 0
+	c := newCarbonTCPClient(testCarbonTCPConfig("localhost:2003"), zap.NewNop())
+	c.dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		dials++
+		client, server := net.Pipe()
+		// the server closes the connection, so the write fails
+		server.Close()
+		return client, nil
+	}
+
+	assert.Error(t, c.send(context.Background(), strings.NewReader("a.b 1 1605534165")))
+	assert.Nil(t, c.conn)
+	assert.Error(t, c.send(context.Background(), strings.NewReader("a.b 1 1605534165")))
+	assert.Equal(t, 2, dials)
+}
+
+func TestSendMetricsCarbonTCP(t *testing.T) {
+	endpoint, lines := startCarbonServer(t)
+
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){})
+	test.s.config.MetricFormat = GraphiteFormat
+	test.s.metricFormatter = getTestMetricFormatter(t, test.s.config)
+	test.s.carbonTCP = newCarbonTCPClient(testCarbonTCPConfig(endpoint), zap.NewNop())
+	t.Cleanup(func() { assert.NoError(t, test.s.carbonTCP.shutdown()) })
+	test.s.metricBuffer = []metricPair{
+		exampleIntMetric(),
+		exampleIntGaugeMetric(),
+	}
+
+	_, err := test.s.sendMetrics(context.Background(), newFields(pdata.NewAttributeMap()))
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]string{
+			"test_metric_data 14500 1605534165",
+			"gauge_metric_name 124 1608124661",
+			"gauge_metric_name 245 1608124662",
+		},
+		receiveLines(t, lines, 3),
+	)
+	assert.EqualValues(t, 0, *test.reqCounter)
+}
@@ -0,0 +1,175 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var errCircuitBreakerOpen = errors.New("circuit breaker is open, request not sent")
+
+// circuitBreakerState represents the state of the circuit breaker
+type circuitBreakerState int64
+
+const (
+	// circuitBreakerClosed means that the requests are sent
+	circuitBreakerClosed circuitBreakerState = iota
+	// circuitBreakerOpen means that the requests fail without being sent
+	circuitBreakerOpen
+	// circuitBreakerHalfOpen means that a single probe request is sent
+	// to check whether the endpoint recovered
+	circuitBreakerHalfOpen
+)
+
+func (s circuitBreakerState) String() string {
+	switch s {
+	case circuitBreakerClosed:
+		return "closed"
+	case circuitBreakerOpen:
+		return "open"
+	case circuitBreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreakers keeps the circuit breakers of the data URLs, which are shared
+// by all the senders of the exporter
+type circuitBreakers struct {
+	logger           *zap.Logger
+	failureThreshold int
+	probeInterval    time.Duration
+	now              func() time.Time
+
+	lock     sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+func newCircuitBreakers(cfg CircuitBreakerConfig, logger *zap.Logger) *circuitBreakers {
+	return &circuitBreakers{
+		logger:           logger,
+		failureThreshold: cfg.FailureThreshold,
+		probeInterval:    cfg.ProbeInterval,
+		now:              time.Now,
+		breakers:         make(map[string]*circuitBreaker),
+	}
+}
+
+// get returns the circuit breaker of the URL the data of the pipeline is sent to
+func (cbs *circuitBreakers) get(pipeline PipelineType, url string) *circuitBreaker {
+	cbs.lock.Lock()
+	defer cbs.lock.Unlock()
+
+	cb, ok := cbs.breakers[url]
+	if !ok {
+		cb = &circuitBreaker{
+			// the URL may contain the HTTP source token, so only the pipeline is logged
+			logger:           cbs.logger.With(zap.String("pipeline", string(pipeline))),
+			pipeline:         pipeline,
+			failureThreshold: cbs.failureThreshold,
+			probeInterval:    cbs.probeInterval,
+			now:              cbs.now,
+		}
+		cbs.breakers[url] = cb
+	}
+	return cb
+}
+
+// circuitBreaker stops sending the requests to the URL after failureThreshold
+// consecutive failures and sends a probe request every probeInterval until
+// one of them succeeds
+type circuitBreaker struct {
+	logger           *zap.Logger
+	pipeline         PipelineType
+	failureThreshold int
+	probeInterval    time.Duration
+	now              func() time.Time
+
+	// lock guards the fields below
+	lock     sync.Mutex
+	state    circuitBreakerState
+	failures int
+	openedAt time.Time
+}
+
+// allow returns whether the request can be sent, when the circuit breaker
+// is open and probeInterval passed, the request is allowed as a probe
+func (cb *circuitBreaker) allow() bool {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	switch cb.state {
+	case circuitBreakerClosed:
+		return true
+	case circuitBreakerOpen:
+		if cb.now().Sub(cb.openedAt) >= cb.probeInterval {
+			cb.setState(circuitBreakerHalfOpen)
+			return true
+		}
+	}
+
+	recordCircuitBreakerRejection(cb.pipeline)
+	return false
+}
+
+// onSuccess closes the circuit breaker after the endpoint responded
+func (cb *circuitBreaker) onSuccess() {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	cb.failures = 0
+	if cb.state != circuitBreakerClosed {
+		cb.logger.Info("Endpoint recovered, circuit breaker closed")
+		cb.setState(circuitBreakerClosed)
+	}
+}
+
+// onFailure counts the failure and opens the circuit breaker when the threshold
+// is reached or the probe request failed
+func (cb *circuitBreaker) onFailure() {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	cb.failures++
+	switch {
+	case cb.state == circuitBreakerHalfOpen:
+		cb.openedAt = cb.now()
+		cb.setState(circuitBreakerOpen)
+	case cb.state == circuitBreakerClosed && cb.failures >= cb.failureThreshold:
+		cb.logger.Warn("Endpoint keeps failing, circuit breaker opened",
+			zap.Int("failures", cb.failures),
+			zap.Duration("probe_interval", cb.probeInterval),
+		)
+		cb.openedAt = cb.now()
+		cb.setState(circuitBreakerOpen)
+	}
+}
+
+// setState changes the state and records it, it has to be called with the lock held
+func (cb *circuitBreaker) setState(state circuitBreakerState) {
+	cb.state = state
+	recordCircuitBreakerState(cb.pipeline, state)
+}
+
+// isFailureStatusCode returns whether the response status code means
+// that the endpoint is failing, as opposed to rejecting the request
+func isFailureStatusCode(statusCode int) bool {
+	return statusCode >= 500
+}
@@ -0,0 +1,111 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	cbs := newCircuitBreakers(CircuitBreakerConfig{
+		FailureThreshold: 3,
+		ProbeInterval:    time.Minute,
+	}, zap.NewNop())
+	cbs.now = func() time.Time { return now }
+
+	cb := cbs.get(LogsPipeline, "https://example.com/logs")
+	assert.Same(t, cb, cbs.get(LogsPipeline, "https://example.com/logs"))
+	assert.NotSame(t, cb, cbs.get(MetricsPipeline, "https://example.com/metrics"))
+
+	// a success resets the consecutive failures
+	cb.onFailure()
+	cb.onFailure()
+	cb.onSuccess()
+	cb.onFailure()
+	cb.onFailure()
+	assert.True(t, cb.allow())
+	assert.Equal(t, circuitBreakerClosed, cb.state)
+
+	cb.onFailure()
+	assert.Equal(t, circuitBreakerOpen, cb.state)
+	assert.False(t, cb.allow())
+
+	// a single probe is allowed after the probe interval, its failure opens the circuit breaker again
+	now = now.Add(time.Minute)
+	assert.True(t, cb.allow())
+	assert.Equal(t, circuitBreakerHalfOpen, cb.state)
+	assert.False(t, cb.allow())
+	cb.onFailure()
+	assert.Equal(t, circuitBreakerOpen, cb.state)
+	assert.False(t, cb.allow())
+
+	now = now.Add(time.Minute)
+	assert.True(t, cb.allow())
+	cb.onSuccess()
+	assert.Equal(t, circuitBreakerClosed, cb.state)
+	assert.True(t, cb.allow())
+}
+
+func TestSendWithCircuitBreaker(t *testing.T) {
+	failure := func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		failure,
+		failure,
+		// the probe request
+		func(w http.ResponseWriter, req *http.Request) {},
+	})
+
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	test.s.circuitBreakers = newCircuitBreakers(CircuitBreakerConfig{
+		FailureThreshold: 2,
+		ProbeInterval:    time.Minute,
+	}, zap.NewNop())
+	test.s.circuitBreakers.now = func() time.Time { return now }
+
+	send := func() error {
+		return test.s.send(context.Background(), LogsPipeline, strings.NewReader("Example log"), newFields(pdata.NewAttributeMap()))
+	}
+
+	require.Error(t, send())
+	require.Error(t, send())
+	assert.EqualValues(t, 2, *test.reqCounter)
+
+	assert.ErrorIs(t, send(), errCircuitBreakerOpen)
+	assert.EqualValues(t, 2, *test.reqCounter)
+
+	now = now.Add(time.Minute)
+	assert.NoError(t, send())
+	assert.EqualValues(t, 3, *test.reqCounter)
+}
+
+func TestIsFailureStatusCode(t *testing.T) {
+	assert.True(t, isFailureStatusCode(http.StatusInternalServerError))
+	assert.True(t, isFailureStatusCode(http.StatusServiceUnavailable))
+	assert.False(t, isFailureStatusCode(http.StatusOK))
+	assert.False(t, isFailureStatusCode(http.StatusBadRequest))
+	assert.False(t, isFailureStatusCode(http.StatusUnauthorized))
+}
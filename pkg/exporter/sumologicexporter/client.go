@@ -0,0 +1,117 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+// Client sends logs, metrics and traces to Sumo Logic the same way the exporter does,
+// but without the collector pipeline, so it can be used by other Go programs,
+// e.g. test harnesses pushing ad-hoc payloads.
+//
+// The data is sent synchronously, without the sending queue and the retries.
+// Client is safe for concurrent use.
+type Client struct {
+	exp *sumologicexporter
+}
+
+// NewClientConfig returns the default exporter configuration sending the data to the endpoint,
+// it can be adjusted before it's passed to NewClient.
+func NewClientConfig(endpoint string) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.HTTPClientSettings.Endpoint = endpoint
+	cfg.HTTPClientSettings.Auth = nil
+	return cfg
+}
+
+// NewClient creates a client sending the data with the given configuration.
+// The endpoint has to be set, as the sumologic extension is not available outside of the collector.
+func NewClient(ctx context.Context, cfg *Config, logger *zap.Logger) (*Client, error) {
+	if cfg.HTTPClientSettings.Endpoint == "" {
+		return nil, errors.New("endpoint has to be specified")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	exp, err := initExporter(cfg, component.ExporterCreateSettings{
+		TelemetrySettings: component.TelemetrySettings{
+			Logger: logger,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := exp.start(ctx, clientHost{logger: logger}); err != nil {
+		return nil, err
+	}
+
+	return &Client{exp: exp}, nil
+}
+
+// SendLogs sends the logs, the returned error contains the logs which were not sent
+// (see consumererror.Logs).
+func (c *Client) SendLogs(ctx context.Context, ld pdata.Logs) error {
+	return c.exp.trackedPushLogsData(ctx, ld)
+}
+
+// SendMetrics sends the metrics, the returned error contains the metrics which were not sent
+// (see consumererror.Metrics).
+func (c *Client) SendMetrics(ctx context.Context, md pdata.Metrics) error {
+	return c.exp.trackedPushMetricsData(ctx, md)
+}
+
+// SendTraces sends the traces, the returned error contains the traces which were not sent
+// (see consumererror.Traces).
+func (c *Client) SendTraces(ctx context.Context, td pdata.Traces) error {
+	return c.exp.trackedPushTracesData(ctx, td)
+}
+
+// Close waits for the sends in progress, at most for shutdown_timeout,
+// and releases the resources of the client.
+func (c *Client) Close(ctx context.Context) error {
+	return c.exp.shutdown(ctx)
+}
+
+// clientHost is the component.Host of the exporter used by Client,
+// which has no extensions and no other components
+type clientHost struct {
+	logger *zap.Logger
+}
+
+func (h clientHost) ReportFatalError(err error) {
+	h.logger.Error("Fatal error reported", zap.Error(err))
+}
+
+func (clientHost) GetFactory(component.Kind, config.Type) component.Factory {
+	return nil
+}
+
+func (clientHost) GetExtensions() map[config.ComponentID]component.Extension {
+	return nil
+}
+
+func (clientHost) GetExporters() map[config.DataType]map[config.ComponentID]component.Exporter {
+	return nil
+}
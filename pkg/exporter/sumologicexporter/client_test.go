@@ -0,0 +1,85 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+func TestClient(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch req.Header.Get("Content-Type") {
+		case "application/x-www-form-urlencoded":
+			assert.Equal(t, "Example log", extractBody(t, req))
+		case "application/vnd.sumologic.carbon2":
+			assert.Equal(t, "test=test_value test2=second_value metric=test.metric.data unit=bytes  14500 1605534165", extractBody(t, req))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := NewClientConfig(srv.URL)
+	cfg.CompressEncoding = NoCompression
+	cfg.LogFormat = TextFormat
+	cfg.MetricFormat = Carbon2Format
+
+	client, err := NewClient(context.Background(), cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.NoError(t, client.SendLogs(context.Background(), LogRecordsToLogs(exampleLog())))
+	metric := exampleIntMetric()
+	assert.NoError(t, client.SendMetrics(context.Background(), metricPairToMetrics([]metricPair{metric})))
+	assert.EqualValues(t, 2, atomic.LoadInt32(&requests))
+
+	assert.NoError(t, client.Close(context.Background()))
+}
+
+func TestClientReturnsUnsentData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(context.Background(), NewClientConfig(srv.URL), zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, client.Close(context.Background())) })
+
+	err = client.SendLogs(context.Background(), LogRecordsToLogs(exampleTwoLogs()))
+	var logsErr consumererror.Logs
+	require.ErrorAs(t, err, &logsErr)
+	assert.Equal(t, 2, logsErr.GetLogs().LogRecordCount())
+}
+
+func TestNewClientInvalidConfig(t *testing.T) {
+	_, err := NewClient(context.Background(), NewClientConfig(""), zap.NewNop())
+	assert.EqualError(t, err, "endpoint has to be specified")
+
+	cfg := NewClientConfig("http://localhost")
+	cfg.LogFormat = "invalid"
+	_, err = NewClient(context.Background(), cfg, zap.NewNop())
+	assert.EqualError(t, err, "invalid configuration: unexpected log format: invalid")
+}
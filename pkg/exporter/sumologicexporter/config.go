@@ -17,13 +17,17 @@ package sumologicexporter
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
+	"regexp"
 	"time"
 
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/extension/sumologicextension"
 )
 
 // Config defines configuration for Sumo Logic exporter.
@@ -46,6 +50,26 @@ type Config struct {
 	//   * json - Logs will appear in Sumo Logic in json format.
 	//   * otlp - Logs will be send in otlp format and will appear in Sumo Logic in text format.
 	LogFormat LogFormatType `mapstructure:"log_format"`
+	// TextLogTemplate is the template of the lines sent with the text log format,
+	// e.g. `%{timestamp} %{severity} %{k8s.pod.name} %{body}`.
+	// Placeholders `%{attr_name}` will be replaced with attribute value for attr_name.
+	// By default only the body is sent.
+	TextLogTemplate string `mapstructure:"text_log_template"`
+	// TextFormatBodyHandling defines how the map and array bodies are sent with
+	// the text log format:
+	//   * string - the bodies are rendered as strings, which is the default,
+	//   * json - the bodies are serialized to JSON,
+	//   * drop - the records are dropped,
+	//   * attributes - the entries of the map bodies are moved to the record
+	//     attributes, apart from the one under json_logs.log_key which is sent
+	//     as the line, while the array bodies are serialized to JSON.
+	TextFormatBodyHandling TextFormatBodyHandlingType `mapstructure:"text_format_body_handling"`
+	// MaxLogRecordSize is the maximum size of the log record's body in bytes,
+	// longer string bodies are truncated and end with LogTruncationMarker.
+	// Zero means no limit.
+	MaxLogRecordSize int `mapstructure:"max_log_record_size"`
+	// LogTruncationMarker is the suffix of the truncated log record's body.
+	LogTruncationMarker string `mapstructure:"log_truncation_marker"`
 
 	// Metrics related configuration
 	// The format of metrics you will be sending, either graphite or carbon2, otlp or prometheus (Default is prometheus)
@@ -54,9 +78,23 @@ type Config struct {
 	// Graphite template.
 	// Placeholders `%{attr_name}` will be replaced with attribute value for attr_name.
 	GraphiteTemplate string `mapstructure:"graphite_template"`
+	// PrometheusCreatedSeries enables sending the start timestamps of the cumulative
+	// data points as the _created series with the prometheus format, so the counter
+	// resets can be detected by Sumo Logic.
+	// By default this is false.
+	PrometheusCreatedSeries bool `mapstructure:"prometheus_created_series"`
+	// MetricFormatWorkers is the number of goroutines formatting the metrics of
+	// a single request with the text metric formats (e.g. prometheus or carbon2).
+	// By default the metrics are formatted sequentially.
+	MetricFormatWorkers int `mapstructure:"metric_format_workers"`
+
+	// MetricFilters defines the metrics dropped before they are formatted,
+	// by their names and by the values of their labels.
+	MetricFilters MetricFiltersConfig `mapstructure:"metric_filters"`
 
 	// Traces related configuration
-	// The format of traces you will be sending, currently only otlp format is supported
+	// The format of traces you will be sending, either otlp or text,
+	// which renders the spans as human-readable lines ingested as logs
 	TraceFormat TraceFormatType `mapstructure:"trace_format"`
 
 	// Specifies whether attributes should be translated
@@ -76,6 +114,15 @@ type Config struct {
 	// Useful if you want to override the source category configured for the source.
 	// Placeholders `%{attr_name}` will be replaced with attribute value for attr_name.
 	SourceCategory string `mapstructure:"source_category"`
+	// SourceCategoryPrefix is prepended to the source category resolved from
+	// the source_category template, e.g. to add the organization-wide `prod/emea/` prefix
+	// without repeating it in every exporter's template.
+	// By default this is empty.
+	SourceCategoryPrefix string `mapstructure:"source_category_prefix"`
+	// SourceCategoryReplaceDash replaces the dashes in the source category,
+	// including the prefix, after the template is resolved.
+	// By default this is empty, which keeps the dashes.
+	SourceCategoryReplaceDash string `mapstructure:"source_category_replace_dash"`
 	// Desired source name.
 	// Useful if you want to override the source name configured for the source.
 	// Placeholders `%{attr_name}` will be replaced with attribute value for attr_name.
@@ -84,8 +131,21 @@ type Config struct {
 	// Useful if you want to override the source host configured for the source.
 	// Placeholders `%{attr_name}` will be replaced with attribute value for attr_name.
 	SourceHost string `mapstructure:"source_host"`
+	// SourceHostFallback configures falling back to the machine's FQDN
+	// as the source host when source_host is not set.
+	SourceHostFallback SourceHostFallbackConfig `mapstructure:"source_host_fallback"`
+	// AddSourceResourceAttributes defines whether the source category, name and host
+	// are also added as `_sourceCategory`, `_sourceName` and `_sourceHost` resource
+	// attributes when sending data in OTLP format. When disabled, they are sent
+	// in the `X-Sumo-...` headers only.
+	// By default this is true.
+	AddSourceResourceAttributes bool `mapstructure:"add_source_resource_attributes"`
 	// Name of the client
 	Client string `mapstructure:"client"`
+	// UserAgent is sent in the User-Agent header of the requests.
+	// By default this is empty, which means it is generated on startup
+	// from the collector version, the OS and architecture and the enabled features.
+	UserAgent string `mapstructure:"user_agent"`
 
 	// ClearTimestamp defines if timestamp for logs should be set to 0.
 	// It indicates that backend will extract timestamp from logs.
@@ -94,6 +154,414 @@ type Config struct {
 	ClearLogsTimestamp bool `mapstructure:"clear_logs_timestamp"`
 
 	JSONLogs `mapstructure:"json_logs"`
+
+	// PayloadSampling configures the diagnostics mode in which the outgoing
+	// request bodies are sampled into local files.
+	PayloadSampling PayloadSamplingConfig `mapstructure:"payload_sampling"`
+
+	// UsageCounters configures the daily counters of bytes sent per source category.
+	UsageCounters UsageCountersConfig `mapstructure:"usage_counters"`
+
+	// Routes is the routing table which sends the data to different endpoints
+	// depending on its source category, e.g. to HTTP sources of different
+	// Sumo Logic organizations. The first matching route is used, the data
+	// not matching any of them is sent to the default endpoint.
+	Routes []RouteConfig `mapstructure:"routes"`
+
+	// IdempotencyKeyHeader is the name of the header in which a deterministic hash
+	// of the request is sent. The hash is the same for every retry of the same batch,
+	// so a server or a proxy can deduplicate the data which was sent more than once,
+	// e.g. after a timeout of the request which actually succeeded.
+	// By default this is empty, which means the header is not sent.
+	IdempotencyKeyHeader string `mapstructure:"idempotency_key_header"`
+
+	// PropagateTraceContext defines whether the W3C trace context of the exported data,
+	// i.e. the traceparent and tracestate headers, is injected into the requests,
+	// so they can be traced end-to-end through the proxies.
+	// By default this is false.
+	PropagateTraceContext bool `mapstructure:"propagate_trace_context"`
+
+	// CircuitBreaker configures the circuit breaker which stops sending
+	// the requests to the endpoint which keeps failing.
+	CircuitBreaker CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+
+	// RetryBudget configures limiting the retries in progress, shared by the logs,
+	// metrics and traces pipelines, so the failing pipeline can't starve the others.
+	RetryBudget RetryBudgetConfig `mapstructure:"retry_budget"`
+
+	// ShutdownTimeout defines how long the shutdown, including draining the sending queue,
+	// waits for the requests in progress to flush the buffered data. After that they are aborted
+	// and the number of the records which could not be flushed is logged.
+	// By default this is 10s.
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+
+	// DropAudit configures the audit trail of the records which were dropped,
+	// summarized per interval, pipeline and reason.
+	DropAudit DropAuditConfig `mapstructure:"drop_audit"`
+
+	// GraphiteTCP configures writing the metrics in graphite format directly
+	// to a Carbon plaintext TCP endpoint instead of sending them over HTTP.
+	GraphiteTCP GraphiteTCPConfig `mapstructure:"graphite_tcp"`
+
+	// Backpressure configures rejecting the new data, so the receivers can slow down
+	// the producers, while sending the data keeps failing.
+	Backpressure BackpressureConfig `mapstructure:"backpressure"`
+
+	// Throttling configures the handling of the requests rejected by the backend
+	// with 429 Too Many Requests.
+	Throttling ThrottlingConfig `mapstructure:"throttling"`
+
+	// AdaptiveBatching configures tuning the request body size limit,
+	// so the p95 of the request payload sizes reaches the target.
+	AdaptiveBatching AdaptiveBatchingConfig `mapstructure:"adaptive_batching"`
+
+	// PayloadSizeEstimation configures comparing the estimated size of the request,
+	// i.e. the body after compression and the headers, against the request body size
+	// limit when the data is batched, instead of the size of the body before compression.
+	PayloadSizeEstimation PayloadSizeEstimationConfig `mapstructure:"payload_size_estimation"`
+
+	// TracesRetry configures retrying the failed trace requests in the exporter,
+	// before the failure is handled by retry_on_failure.
+	TracesRetry TracesRetryConfig `mapstructure:"traces_retry"`
+
+	// EndpointDiscovery configures resolving the hosts the data is sent to
+	// with a DNS SRV record, instead of the host of the endpoint.
+	EndpointDiscovery EndpointDiscoveryConfig `mapstructure:"endpoint_discovery"`
+
+	// DryRun defines whether the data is only validated against the Sumo Logic
+	// constraints (payload size, fields limits, metric names) instead of being sent.
+	// The violations are logged and counted in the collector's metrics.
+	// Neither the endpoint nor the auth extension is required then.
+	// By default this is false.
+	DryRun bool `mapstructure:"dry_run"`
+
+	// Logs, Metrics and Traces allow disabling sending the data of the given signal,
+	// e.g. when the exporter is used in multiple pipelines, without changing the pipelines.
+	Logs    SignalConfig `mapstructure:"logs"`
+	Metrics SignalConfig `mapstructure:"metrics"`
+	Traces  SignalConfig `mapstructure:"traces"`
+
+	// TLSPinning configures validating the public keys of the certificates
+	// presented by the endpoints against the list of the pinned keys.
+	TLSPinning TLSPinningConfig `mapstructure:"tls_pinning"`
+
+	// Debug configures simulating the responses of the Sumo Logic receiver
+	// instead of sending the data, e.g. to test retries and drops in integration tests.
+	Debug DebugConfig `mapstructure:"debug"`
+}
+
+// SignalConfig defines configuration of sending the data of a single signal.
+type SignalConfig struct {
+	// Enabled defines whether the data of the signal is sent. When disabled,
+	// the data is discarded and counted in the sumologic_exporter_disabled_signal_records metric.
+	// By default this is true.
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// TLSPinningConfig defines configuration of the public key pinning.
+// The connection is accepted when any certificate of the verified chain matches
+// any of the pins, so the pins of both the current and the next key (or CA)
+// can be listed when the certificates are rotated.
+type TLSPinningConfig struct {
+	// SPKISHA256 is the list of the base64 encoded SHA-256 hashes
+	// of the DER encoded SubjectPublicKeyInfo of the pinned keys.
+	// By default this is empty, which disables the pinning.
+	SPKISHA256 []string `mapstructure:"spki_sha256"`
+}
+
+// DebugConfig defines configuration of the debug mode, in which the requests
+// are not sent and the configured responses are returned for them instead.
+// The responses are handled the same way as the real ones, so the retries,
+// the drops and the other features relying on them can be tested without Sumo Logic.
+type DebugConfig struct {
+	// Enabled defines whether the responses are simulated.
+	// By default this is false.
+	Enabled bool `mapstructure:"enabled"`
+	// Responses are returned for the consecutive requests in order.
+	Responses []DebugResponseConfig `mapstructure:"responses"`
+	// Loop defines whether the responses start over after the last one,
+	// otherwise the last response is returned for all the subsequent requests.
+	// By default this is false.
+	Loop bool `mapstructure:"loop"`
+}
+
+// DebugResponseConfig defines a simulated response of the Sumo Logic receiver.
+type DebugResponseConfig struct {
+	// StatusCode is the HTTP status code of the response.
+	// By default (when it's 0) this is 200.
+	StatusCode int `mapstructure:"status_code"`
+	// Body is the body of the response, e.g. the JSON error returned by the receiver.
+	// By default this is empty.
+	Body string `mapstructure:"body"`
+	// Headers are the headers of the response, e.g. Retry-After.
+	Headers map[string]string `mapstructure:"headers"`
+	// Delay defines how long it takes to respond, to simulate slow responses.
+	// The request fails when it's longer than the timeout of the HTTP client.
+	// By default this is 0.
+	Delay time.Duration `mapstructure:"delay"`
+	// Count defines for how many consecutive requests the response is returned.
+	// By default (when it's 0) this is 1.
+	Count int `mapstructure:"count"`
+}
+
+// RouteConfig defines the endpoint the data with the matching source category is sent to
+type RouteConfig struct {
+	// SourceCategory is the regex which has to match the whole source category
+	// of the data, i.e. the value of the `source_category` template with
+	// `source_category_prefix` and `source_category_replace_dash` applied.
+	SourceCategory string `mapstructure:"source_category"`
+	// Endpoint is the URL the matching data is sent to, e.g. the URL of the HTTP source.
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+// SourceHostFallbackConfig defines configuration of the source host fallback.
+// The FQDN is resolved from the OS hostname with a reverse DNS lookup
+// of its addresses and the result is cached for the refresh interval.
+// When the FQDN can't be resolved, the OS hostname is used.
+type SourceHostFallbackConfig struct {
+	// Enabled defines whether the source host falls back to the machine's FQDN.
+	// By default this is false.
+	Enabled bool `mapstructure:"enabled"`
+	// RefreshInterval defines how long the resolved FQDN is cached.
+	// By default this is 1h.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+}
+
+// MetricFiltersConfig defines which metrics and data points are dropped before
+// they are formatted. All the patterns are regular expressions, which match
+// when they match any part of the name or the value.
+type MetricFiltersConfig struct {
+	// Include keeps only the metrics with names matching any of the patterns.
+	// When empty, all the metrics are kept.
+	Include []string `mapstructure:"include"`
+	// Exclude drops the metrics with names matching any of the patterns.
+	Exclude []string `mapstructure:"exclude"`
+	// ExcludeLabels drops the data points matching any of the label matchers.
+	// The metrics with all the data points dropped are dropped as well.
+	ExcludeLabels []LabelMatcherConfig `mapstructure:"exclude_labels"`
+}
+
+// LabelMatcherConfig matches the data points by the value of a label, taken from
+// the data point's attributes or, when it's not there, from the resource attributes
+type LabelMatcherConfig struct {
+	// Name is the name of the label.
+	Name string `mapstructure:"name"`
+	// Value is the pattern the label's value has to match.
+	Value string `mapstructure:"value"`
+}
+
+// CircuitBreakerConfig defines configuration of the circuit breaker, which opens
+// after a number of consecutive failures (connection errors or 5xx responses) of the data URL.
+// While it's open the requests fail without being sent, except for a probe request
+// sent every probe interval, which closes the circuit breaker when it succeeds.
+type CircuitBreakerConfig struct {
+	// Enabled defines whether the circuit breaker is turned on.
+	// By default this is false.
+	Enabled bool `mapstructure:"enabled"`
+	// FailureThreshold defines after how many consecutive failures the circuit breaker opens.
+	// By default this is 5.
+	FailureThreshold int `mapstructure:"failure_threshold"`
+	// ProbeInterval defines how often the probe request is sent while the circuit breaker is open.
+	// By default this is 30s.
+	ProbeInterval time.Duration `mapstructure:"probe_interval"`
+}
+
+// RetryBudgetConfig defines configuration of the retry budget. The requests
+// of the pipeline are considered retries while its previous request failed,
+// and they are only sent when the budget shared by all the pipelines allows it.
+type RetryBudgetConfig struct {
+	// Enabled defines whether the retries are limited.
+	// By default this is false.
+	Enabled bool `mapstructure:"enabled"`
+	// MaxConcurrentRetries defines how many retries can be in progress at once.
+	// By default this is 4.
+	MaxConcurrentRetries int `mapstructure:"max_concurrent_retries"`
+	// MaxRetryBytes defines the total size of the bodies (after compression)
+	// of the retries in progress.
+	// By default this is 8MiB.
+	MaxRetryBytes int `mapstructure:"max_retry_bytes"`
+}
+
+// DropAuditConfig defines configuration of the audit trail of the dropped records.
+// Every interval a summary of how many records were dropped per pipeline and reason
+// is written, either to the collector's log or to a file as a JSON line.
+type DropAuditConfig struct {
+	// Enabled defines whether the drop audit is turned on.
+	// By default this is false.
+	Enabled bool `mapstructure:"enabled"`
+	// Interval defines how often the summary is written.
+	// By default this is 1m.
+	Interval time.Duration `mapstructure:"interval"`
+	// Path is the file the summaries are appended to.
+	// When empty, the summaries are written to the collector's log.
+	Path string `mapstructure:"path"`
+}
+
+// GraphiteTCPConfig defines configuration of the Carbon plaintext TCP transport
+// of the metrics in graphite format. The connection is kept open between the writes
+// and reestablished with exponential backoff when it fails.
+type GraphiteTCPConfig struct {
+	// Endpoint is the address of the Carbon plaintext TCP endpoint, e.g. graphite-relay:2003.
+	// When empty, the metrics are sent over HTTP.
+	Endpoint string `mapstructure:"endpoint"`
+	// Timeout defines the timeout of connecting to the endpoint and of a single write.
+	// By default this is 5s.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// InitialReconnectInterval defines how long to wait before reconnecting
+	// after the first failed connection attempt.
+	// By default this is 1s.
+	InitialReconnectInterval time.Duration `mapstructure:"initial_reconnect_interval"`
+	// MaxReconnectInterval defines the upper bound of the wait before reconnecting,
+	// which doubles with every failed connection attempt.
+	// By default this is 1m.
+	MaxReconnectInterval time.Duration `mapstructure:"max_reconnect_interval"`
+}
+
+// BackpressureConfig defines configuration of the backpressure signal. While sending
+// the data of the pipeline keeps failing for the failure duration, the new data
+// is rejected with ErrBackpressure instead of being put in the sending queue.
+type BackpressureConfig struct {
+	// Enabled defines whether the new data is rejected while sending keeps failing.
+	// By default this is false.
+	Enabled bool `mapstructure:"enabled"`
+	// FailureDuration defines for how long sending has to keep failing
+	// before the new data is rejected.
+	// By default this is 1m.
+	FailureDuration time.Duration `mapstructure:"failure_duration"`
+}
+
+// AdaptiveBatchingConfig defines configuration of the adaptive batching. The request
+// body size limit of every pipeline is tuned after each window of requests, so the p95
+// of their payload sizes (after compression) gets to the target, between 64KiB
+// and max_request_body_size.
+type AdaptiveBatchingConfig struct {
+	// Enabled defines whether the request body size limit is tuned.
+	// By default this is false.
+	Enabled bool `mapstructure:"enabled"`
+	// TargetPayloadSize defines the target p95 of the request payload sizes in bytes.
+	// By default this is 512KiB.
+	TargetPayloadSize int `mapstructure:"target_payload_size"`
+	// WindowSize defines the number of requests the p95 is computed from.
+	// By default this is 100.
+	WindowSize int `mapstructure:"window_size"`
+}
+
+// PayloadSizeEstimationConfig defines configuration of the payload size estimation.
+// The size of the request is estimated from the size of the body before compression,
+// scaled by the compression ratio of the previous requests of the pipeline,
+// and the size of the headers of the previous request.
+type PayloadSizeEstimationConfig struct {
+	// Enabled defines whether the batches are flushed when their estimated request size
+	// reaches max_request_body_size. The body before compression is still limited
+	// to 10 times max_request_body_size.
+	// By default this is false.
+	Enabled bool `mapstructure:"enabled"`
+	// InitialCompressionRatio defines the ratio of the compressed and uncompressed body
+	// sizes used until the first request of the pipeline is sent.
+	// By default this is 1, i.e. the body is assumed not to be compressible.
+	InitialCompressionRatio float64 `mapstructure:"initial_compression_ratio"`
+}
+
+// ThrottlingConfig defines configuration of the handling of the requests rejected
+// with 429 Too Many Requests. The pipeline stays throttled until the backend
+// accepts its request, which is exposed as the sumologic_exporter_throttled metric.
+type ThrottlingConfig struct {
+	// PauseSends defines whether sending the data of the throttled pipeline is paused
+	// for the throttle period, while the other pipelines continue to send.
+	// By default this is false.
+	PauseSends bool `mapstructure:"pause_sends"`
+	// DefaultPeriod defines the throttle period used when the response
+	// has no valid Retry-After header.
+	// By default this is 30s.
+	DefaultPeriod time.Duration `mapstructure:"default_period"`
+	// MaxPeriod defines the upper bound of the throttle period
+	// taken from the Retry-After header.
+	// By default this is 5m.
+	MaxPeriod time.Duration `mapstructure:"max_period"`
+}
+
+// EndpointDiscoveryConfig defines configuration of the endpoint discovery.
+// The host and port of every request sent by the exporter are replaced with the targets
+// of the SRV record, the requests are balanced across them according to their weights.
+type EndpointDiscoveryConfig struct {
+	// Enabled defines whether the endpoint discovery is used.
+	// By default this is false.
+	Enabled bool `mapstructure:"enabled"`
+	// SRVRecord is the name of the SRV record, e.g. _ingest._tcp.sumo.example.com.
+	SRVRecord string `mapstructure:"srv_record"`
+	// RefreshInterval defines how often the SRV record is resolved again.
+	// By default this is 30s.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+}
+
+// TracesRetryConfig defines configuration of the retries of the trace requests
+// with jittered exponential backoff. The max elapsed time is shared by all the requests
+// a batch of traces is split into.
+type TracesRetryConfig struct {
+	// Enabled defines whether the trace requests are retried by the exporter.
+	// By default this is false.
+	Enabled bool `mapstructure:"enabled"`
+	// InitialInterval defines how long to wait before the first retry.
+	// By default this is 500ms.
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+	// MaxInterval defines the upper bound of the wait between the retries,
+	// which doubles with every retry.
+	// By default this is 5s.
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+	// MaxElapsedTime defines how long the requests of a single batch are retried.
+	// By default this is 30s.
+	MaxElapsedTime time.Duration `mapstructure:"max_elapsed_time"`
+	// RandomizationFactor defines by which fraction the wait is randomly
+	// lowered or raised, to avoid retrying many requests at the same time.
+	// By default this is 0.5.
+	RandomizationFactor float64 `mapstructure:"randomization_factor"`
+}
+
+// UsageCountersConfig defines configuration of the daily counters of bytes
+// sent per source category, which are persisted with a storage extension
+// and exposed with a local HTTP endpoint.
+type UsageCountersConfig struct {
+	// Enabled defines whether the usage counters are turned on.
+	// By default this is false.
+	Enabled bool `mapstructure:"enabled"`
+	// StorageID is the ID of the storage extension used to persist the counters
+	// across restarts. When not set, the counters are kept in memory only.
+	StorageID *config.ComponentID `mapstructure:"storage"`
+	// Endpoint is the address on which the counters are exposed as JSON,
+	// e.g. localhost:8099. When empty, the counters are not exposed.
+	Endpoint string `mapstructure:"endpoint"`
+	// RetentionDays defines for how many days (including the current one)
+	// the counters are kept.
+	// By default this is 31.
+	RetentionDays int `mapstructure:"retention_days"`
+	// FlushInterval defines how often the counters are persisted.
+	// By default this is 1m.
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+}
+
+// PayloadSamplingConfig defines configuration of the diagnostics mode
+// which stores every N-th outgoing request (decompressed, with secrets redacted)
+// in a bounded ring of files in a local directory.
+type PayloadSamplingConfig struct {
+	// Enabled defines whether the payload sampling is turned on.
+	// By default this is false.
+	Enabled bool `mapstructure:"enabled"`
+	// Directory where the sampled payloads are stored. Required when enabled.
+	Directory string `mapstructure:"directory"`
+	// SampleRate defines that 1 in SampleRate requests is stored.
+	// By default this is 100.
+	SampleRate int `mapstructure:"sample_rate"`
+	// MaxFiles defines how many files are kept in the directory,
+	// the oldest ones are overwritten.
+	// By default this is 10.
+	MaxFiles int `mapstructure:"max_files"`
+	// MaxFileSize defines the maximum number of bytes of the payload stored
+	// in a single file, exceeding bytes are truncated.
+	// By default this is 1MB.
+	MaxFileSize int `mapstructure:"max_file_size"`
+	// RedactPatterns is a list of additional regexes which matches are replaced
+	// with a redaction marker before the payload is stored.
+	RedactPatterns []string `mapstructure:"redact_patterns"`
 }
 
 type JSONLogs struct {
@@ -114,6 +582,15 @@ type JSONLogs struct {
 	// log's body is going to be flattened and `log_key` won't be used
 	// By default this is false.
 	FlattenBody bool `mapstructure:"flatten_body"`
+	// MaxAttributes defines the maximum number of attributes which are sent
+	// with a single JSON log. When a record has more attributes, the exceeding
+	// ones are removed and the record is marked with `sumo.truncated=true`.
+	// By default this is 0 which means no limit.
+	MaxAttributes int `mapstructure:"max_attributes"`
+	// PreserveTypes defines whether the attribute values are sent with their
+	// native JSON types, including nested maps and arrays at any depth.
+	// By default this is false.
+	PreserveTypes bool `mapstructure:"preserve_types"`
 }
 
 // CreateDefaultHTTPClientSettings returns default http client settings
@@ -130,22 +607,50 @@ func (cfg *Config) Validate() error {
 	switch cfg.LogFormat {
 	case OTLPLogFormat:
 	case JSONFormat:
+	case JSONArrayFormat:
 	case TextFormat:
 	default:
 		return fmt.Errorf("unexpected log format: %s", cfg.LogFormat)
 	}
 
-	switch cfg.MetricFormat {
-	case OTLPMetricFormat:
-	case GraphiteFormat:
-	case Carbon2Format:
-	case PrometheusFormat:
+	if cfg.TextLogTemplate != "" && cfg.LogFormat != TextFormat {
+		return fmt.Errorf("text_log_template can only be used with log_format: %s", TextFormat)
+	}
+
+	switch cfg.TextFormatBodyHandling {
+	case "", TextFormatBodyString:
+	case TextFormatBodyJSON, TextFormatBodyDrop, TextFormatBodyAttributes:
+		if cfg.LogFormat != TextFormat {
+			return fmt.Errorf("text_format_body_handling can only be used with log_format: %s", TextFormat)
+		}
 	default:
+		return fmt.Errorf("unexpected text_format_body_handling: %s", cfg.TextFormatBodyHandling)
+	}
+
+	if cfg.MaxLogRecordSize < 0 {
+		return fmt.Errorf("max_log_record_size cannot be negative: %d", cfg.MaxLogRecordSize)
+	}
+	if cfg.MaxLogRecordSize > 0 && len(cfg.LogTruncationMarker) >= cfg.MaxLogRecordSize {
+		return fmt.Errorf("log_truncation_marker has to be shorter than max_log_record_size: %d", cfg.MaxLogRecordSize)
+	}
+
+	if cfg.MetricFormat != OTLPMetricFormat && !isMetricFormatRegistered(cfg.MetricFormat) {
 		return fmt.Errorf("unexpected metric format: %s", cfg.MetricFormat)
 	}
+	if cfg.MetricFormatWorkers < 0 {
+		return fmt.Errorf("metric_format_workers cannot be negative: %d", cfg.MetricFormatWorkers)
+	}
+
+	if err := cfg.SourceHostFallback.Validate(); err != nil {
+		return fmt.Errorf("source_host_fallback has invalid configuration: %w", err)
+	}
+
+	if err := cfg.MetricFilters.Validate(); err != nil {
+		return fmt.Errorf("metric_filters has invalid configuration: %w", err)
+	}
 
 	switch cfg.TraceFormat {
-	case OTLPTraceFormat:
+	case OTLPTraceFormat, TextTraceFormat:
 	default:
 		return fmt.Errorf("unexpected trace format: %s", cfg.TraceFormat)
 	}
@@ -158,7 +663,7 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("unexpected compression encoding: %s", cfg.CompressEncoding)
 	}
 
-	if len(cfg.HTTPClientSettings.Endpoint) == 0 && cfg.HTTPClientSettings.Auth == nil {
+	if len(cfg.HTTPClientSettings.Endpoint) == 0 && cfg.HTTPClientSettings.Auth == nil && !cfg.DryRun && !cfg.Debug.Enabled {
 		return errors.New("no endpoint and no auth extension specified")
 	}
 
@@ -168,6 +673,116 @@ func (cfg *Config) Validate() error {
 		)
 	}
 
+	if sumologicextension.FIPSMode {
+		if err := sumologicextension.ValidateFIPSTLSSettings(cfg.HTTPClientSettings.TLSSetting); err != nil {
+			return fmt.Errorf("invalid tls settings: %w", err)
+		}
+	}
+
+	if cfg.JSONLogs.MaxAttributes < 0 {
+		return fmt.Errorf("json_logs.max_attributes cannot be negative: %d", cfg.JSONLogs.MaxAttributes)
+	}
+
+	if err := cfg.PayloadSampling.Validate(); err != nil {
+		return fmt.Errorf("payload_sampling has invalid configuration: %w", err)
+	}
+
+	if err := cfg.UsageCounters.Validate(); err != nil {
+		return fmt.Errorf("usage_counters has invalid configuration: %w", err)
+	}
+
+	if cfg.SourceCategoryPrefix != "" && cfg.SourceCategory == "" {
+		return errors.New("source_category_prefix requires source_category to be set")
+	}
+
+	if len(cfg.Routes) > 0 && cfg.SourceCategory == "" {
+		return errors.New("routes require source_category to be set")
+	}
+
+	for i, route := range cfg.Routes {
+		if err := route.Validate(); err != nil {
+			return fmt.Errorf("route %d has invalid configuration: %w", i, err)
+		}
+	}
+
+	if cfg.IdempotencyKeyHeader != "" && !isValidHeaderName(cfg.IdempotencyKeyHeader) {
+		return fmt.Errorf("invalid idempotency_key_header: %q", cfg.IdempotencyKeyHeader)
+	}
+
+	if cfg.ShutdownTimeout < 0 {
+		return fmt.Errorf("shutdown_timeout cannot be negative: %s", cfg.ShutdownTimeout)
+	}
+
+	if err := cfg.CircuitBreaker.Validate(); err != nil {
+		return fmt.Errorf("circuit_breaker has invalid configuration: %w", err)
+	}
+
+	if err := cfg.RetryBudget.Validate(); err != nil {
+		return fmt.Errorf("retry_budget has invalid configuration: %w", err)
+	}
+
+	if err := cfg.DropAudit.Validate(); err != nil {
+		return fmt.Errorf("drop_audit has invalid configuration: %w", err)
+	}
+
+	if err := cfg.GraphiteTCP.Validate(); err != nil {
+		return fmt.Errorf("graphite_tcp has invalid configuration: %w", err)
+	}
+
+	if err := cfg.Backpressure.Validate(); err != nil {
+		return fmt.Errorf("backpressure has invalid configuration: %w", err)
+	}
+
+	if err := cfg.Throttling.Validate(); err != nil {
+		return fmt.Errorf("throttling has invalid configuration: %w", err)
+	}
+
+	if err := cfg.AdaptiveBatching.Validate(); err != nil {
+		return fmt.Errorf("adaptive_batching has invalid configuration: %w", err)
+	}
+
+	if err := cfg.PayloadSizeEstimation.Validate(); err != nil {
+		return fmt.Errorf("payload_size_estimation has invalid configuration: %w", err)
+	}
+
+	if err := cfg.TracesRetry.Validate(); err != nil {
+		return fmt.Errorf("traces_retry has invalid configuration: %w", err)
+	}
+
+	if err := cfg.EndpointDiscovery.Validate(); err != nil {
+		return fmt.Errorf("endpoint_discovery has invalid configuration: %w", err)
+	}
+
+	if err := cfg.TLSPinning.Validate(); err != nil {
+		return fmt.Errorf("tls_pinning has invalid configuration: %w", err)
+	}
+
+	if len(cfg.TLSPinning.SPKISHA256) > 0 {
+		if u, _ := url.Parse(cfg.HTTPClientSettings.Endpoint); u != nil && u.Scheme == "http" {
+			return errors.New("tls_pinning cannot be used with a plain HTTP endpoint")
+		}
+		if len(cfg.HTTPClientSettings.Headers) > 0 || cfg.HTTPClientSettings.Compression != "" {
+			return errors.New("tls_pinning cannot be used together with headers or compression of the HTTP client")
+		}
+	}
+
+	if err := cfg.Debug.Validate(); err != nil {
+		return fmt.Errorf("debug has invalid configuration: %w", err)
+	}
+
+	if cfg.Debug.Enabled {
+		if cfg.DryRun {
+			return errors.New("debug cannot be used together with dry_run")
+		}
+		if cfg.GraphiteTCP.Endpoint != "" {
+			return errors.New("debug cannot be used together with graphite_tcp")
+		}
+	}
+
+	if cfg.GraphiteTCP.Endpoint != "" && cfg.MetricFormat != GraphiteFormat {
+		return fmt.Errorf("graphite_tcp requires metric_format to be %s, got: %s", GraphiteFormat, cfg.MetricFormat)
+	}
+
 	if err := cfg.QueueSettings.Validate(); err != nil {
 		return fmt.Errorf("queue settings has invalid configuration: %w", err)
 	}
@@ -175,9 +790,318 @@ func (cfg *Config) Validate() error {
 	return nil
 }
 
+// Validate checks if the route configuration is valid
+func (cfg *RouteConfig) Validate() error {
+	if cfg.SourceCategory == "" {
+		return errors.New("source_category has to be specified")
+	}
+
+	if _, err := regexp.Compile(cfg.SourceCategory); err != nil {
+		return fmt.Errorf("invalid source_category pattern %q: %w", cfg.SourceCategory, err)
+	}
+
+	if cfg.Endpoint == "" {
+		return errors.New("endpoint has to be specified")
+	}
+
+	if _, err := url.Parse(cfg.Endpoint); err != nil {
+		return fmt.Errorf("failed parsing endpoint URL: %s; err: %w", cfg.Endpoint, err)
+	}
+
+	return nil
+}
+
+// Validate checks if the circuit breaker configuration is valid
+func (cfg *CircuitBreakerConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.FailureThreshold < 1 {
+		return fmt.Errorf("failure_threshold has to be positive: %d", cfg.FailureThreshold)
+	}
+
+	if cfg.ProbeInterval <= 0 {
+		return fmt.Errorf("probe_interval has to be positive: %s", cfg.ProbeInterval)
+	}
+
+	return nil
+}
+
+// Validate checks if the retry budget configuration is valid
+func (cfg *RetryBudgetConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.MaxConcurrentRetries <= 0 {
+		return fmt.Errorf("max_concurrent_retries has to be positive: %d", cfg.MaxConcurrentRetries)
+	}
+
+	if cfg.MaxRetryBytes <= 0 {
+		return fmt.Errorf("max_retry_bytes has to be positive: %d", cfg.MaxRetryBytes)
+	}
+
+	return nil
+}
+
+// Validate checks if the drop audit configuration is valid
+func (cfg *DropAuditConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.Interval <= 0 {
+		return fmt.Errorf("interval has to be positive: %s", cfg.Interval)
+	}
+
+	return nil
+}
+
+// Validate checks if the source host fallback configuration is valid
+func (cfg *SourceHostFallbackConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.RefreshInterval <= 0 {
+		return fmt.Errorf("refresh_interval has to be positive: %s", cfg.RefreshInterval)
+	}
+
+	return nil
+}
+
+// Validate checks if the metric filters configuration is valid
+func (cfg *MetricFiltersConfig) Validate() error {
+	for _, l := range cfg.ExcludeLabels {
+		if l.Name == "" {
+			return errors.New("exclude_labels: name has to be specified")
+		}
+	}
+
+	_, err := newMetricFilter(*cfg)
+	return err
+}
+
+// Validate checks if the graphite TCP transport configuration is valid
+func (cfg *GraphiteTCPConfig) Validate() error {
+	if cfg.Endpoint == "" {
+		return nil
+	}
+
+	if _, _, err := net.SplitHostPort(cfg.Endpoint); err != nil {
+		return fmt.Errorf("invalid endpoint %q: %w", cfg.Endpoint, err)
+	}
+
+	if cfg.Timeout <= 0 {
+		return fmt.Errorf("timeout has to be positive: %s", cfg.Timeout)
+	}
+
+	if cfg.InitialReconnectInterval <= 0 {
+		return fmt.Errorf("initial_reconnect_interval has to be positive: %s", cfg.InitialReconnectInterval)
+	}
+
+	if cfg.MaxReconnectInterval < cfg.InitialReconnectInterval {
+		return fmt.Errorf(
+			"max_reconnect_interval (%s) cannot be lower than initial_reconnect_interval (%s)",
+			cfg.MaxReconnectInterval, cfg.InitialReconnectInterval,
+		)
+	}
+
+	return nil
+}
+
+// Validate checks if the TLS pinning configuration is valid
+func (cfg *TLSPinningConfig) Validate() error {
+	_, err := newSPKIPins(cfg.SPKISHA256)
+	return err
+}
+
+// Validate checks if the debug configuration is valid
+func (cfg *DebugConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if len(cfg.Responses) == 0 {
+		return errors.New("responses have to be specified")
+	}
+
+	for i, r := range cfg.Responses {
+		if r.StatusCode != 0 && (r.StatusCode < 100 || r.StatusCode > 599) {
+			return fmt.Errorf("response %d has invalid status_code: %d", i, r.StatusCode)
+		}
+		if r.Delay < 0 {
+			return fmt.Errorf("response %d has negative delay: %s", i, r.Delay)
+		}
+		if r.Count < 0 {
+			return fmt.Errorf("response %d has negative count: %d", i, r.Count)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks if the backpressure configuration is valid
+func (cfg *BackpressureConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.FailureDuration <= 0 {
+		return fmt.Errorf("failure_duration has to be positive: %s", cfg.FailureDuration)
+	}
+
+	return nil
+}
+
+// Validate checks if the adaptive batching configuration is valid
+func (cfg *AdaptiveBatchingConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.TargetPayloadSize <= 0 {
+		return fmt.Errorf("target_payload_size has to be positive: %d", cfg.TargetPayloadSize)
+	}
+	if cfg.WindowSize < minAdaptiveBatchingWindowSize {
+		return fmt.Errorf("window_size has to be at least %d: %d", minAdaptiveBatchingWindowSize, cfg.WindowSize)
+	}
+
+	return nil
+}
+
+// Validate checks if the endpoint discovery configuration is valid
+func (cfg *EndpointDiscoveryConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.SRVRecord == "" {
+		return errors.New("srv_record has to be specified")
+	}
+	if cfg.RefreshInterval <= 0 {
+		return fmt.Errorf("refresh_interval has to be positive: %s", cfg.RefreshInterval)
+	}
+
+	return nil
+}
+
+// Validate checks if the payload size estimation configuration is valid
+func (cfg *PayloadSizeEstimationConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.InitialCompressionRatio < minEstimatedCompressionRatio || cfg.InitialCompressionRatio > 1 {
+		return fmt.Errorf(
+			"initial_compression_ratio has to be between %g and 1: %g",
+			minEstimatedCompressionRatio, cfg.InitialCompressionRatio,
+		)
+	}
+
+	return nil
+}
+
+// Validate checks if the throttling configuration is valid
+func (cfg *ThrottlingConfig) Validate() error {
+	if !cfg.PauseSends {
+		return nil
+	}
+
+	if cfg.DefaultPeriod <= 0 {
+		return fmt.Errorf("default_period has to be positive: %s", cfg.DefaultPeriod)
+	}
+
+	if cfg.MaxPeriod < cfg.DefaultPeriod {
+		return fmt.Errorf("max_period (%s) cannot be lower than default_period (%s)", cfg.MaxPeriod, cfg.DefaultPeriod)
+	}
+
+	return nil
+}
+
+// Validate checks if the traces retry configuration is valid
+func (cfg *TracesRetryConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.InitialInterval <= 0 {
+		return fmt.Errorf("initial_interval has to be positive: %s", cfg.InitialInterval)
+	}
+
+	if cfg.MaxInterval < cfg.InitialInterval {
+		return fmt.Errorf(
+			"max_interval (%s) cannot be lower than initial_interval (%s)",
+			cfg.MaxInterval, cfg.InitialInterval,
+		)
+	}
+
+	if cfg.MaxElapsedTime <= 0 {
+		return fmt.Errorf("max_elapsed_time has to be positive: %s", cfg.MaxElapsedTime)
+	}
+
+	if cfg.RandomizationFactor < 0 || cfg.RandomizationFactor > 1 {
+		return fmt.Errorf("randomization_factor has to be between 0 and 1: %v", cfg.RandomizationFactor)
+	}
+
+	return nil
+}
+
+// Validate checks if the usage counters configuration is valid
+func (cfg *UsageCountersConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.RetentionDays < 1 {
+		return fmt.Errorf("retention_days has to be positive: %d", cfg.RetentionDays)
+	}
+
+	if cfg.FlushInterval <= 0 {
+		return fmt.Errorf("flush_interval has to be positive: %s", cfg.FlushInterval)
+	}
+
+	return nil
+}
+
+// Validate checks if the payload sampling configuration is valid
+func (cfg *PayloadSamplingConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.Directory == "" {
+		return errors.New("directory has to be specified")
+	}
+
+	if cfg.SampleRate < 1 {
+		return fmt.Errorf("sample_rate has to be positive: %d", cfg.SampleRate)
+	}
+
+	if cfg.MaxFiles < 1 {
+		return fmt.Errorf("max_files has to be positive: %d", cfg.MaxFiles)
+	}
+
+	if cfg.MaxFileSize < 0 {
+		return fmt.Errorf("max_file_size cannot be negative: %d", cfg.MaxFileSize)
+	}
+
+	for _, p := range cfg.RedactPatterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("invalid redact pattern %q: %w", p, err)
+		}
+	}
+
+	return nil
+}
+
 // LogFormatType represents log_format
 type LogFormatType string
 
+// TextFormatBodyHandlingType represents text_format_body_handling
+type TextFormatBodyHandlingType string
+
 // MetricFormatType represents metric_format
 type MetricFormatType string
 
@@ -195,8 +1119,18 @@ const (
 	TextFormat LogFormatType = "text"
 	// JSONFormat represents log_format: json
 	JSONFormat LogFormatType = "json"
+	// JSONArrayFormat represents log_format: json_array
+	JSONArrayFormat LogFormatType = "json_array"
 	// OTLPLogFormat represents log_format: otlp
 	OTLPLogFormat LogFormatType = "otlp"
+	// TextFormatBodyString represents text_format_body_handling: string
+	TextFormatBodyString TextFormatBodyHandlingType = "string"
+	// TextFormatBodyJSON represents text_format_body_handling: json
+	TextFormatBodyJSON TextFormatBodyHandlingType = "json"
+	// TextFormatBodyDrop represents text_format_body_handling: drop
+	TextFormatBodyDrop TextFormatBodyHandlingType = "drop"
+	// TextFormatBodyAttributes represents text_format_body_handling: attributes
+	TextFormatBodyAttributes TextFormatBodyHandlingType = "attributes"
 	// GraphiteFormat represents metric_format: graphite
 	GraphiteFormat MetricFormatType = "graphite"
 	// Carbon2Format represents metric_format: carbon2
@@ -207,6 +1141,8 @@ const (
 	OTLPMetricFormat MetricFormatType = "otlp"
 	// OTLPTraceFormat represents trace_format: otlp
 	OTLPTraceFormat TraceFormatType = "otlp"
+	// TextTraceFormat represents trace_format: text
+	TextTraceFormat TraceFormatType = "text"
 	// GZIPCompression represents compress_encoding: gzip
 	GZIPCompression CompressEncodingType = "gzip"
 	// DeflateCompression represents compress_encoding: deflate
@@ -233,10 +1169,16 @@ const (
 	DefaultMetricFormat MetricFormatType = OTLPMetricFormat
 	// DefaultSourceCategory defines default SourceCategory
 	DefaultSourceCategory string = ""
+	// DefaultSourceCategoryPrefix defines default SourceCategoryPrefix
+	DefaultSourceCategoryPrefix string = ""
+	// DefaultSourceCategoryReplaceDash defines default SourceCategoryReplaceDash
+	DefaultSourceCategoryReplaceDash string = ""
 	// DefaultSourceName defines default SourceName
 	DefaultSourceName string = ""
 	// DefaultSourceHost defines default SourceHost
 	DefaultSourceHost string = ""
+	// DefaultAddSourceResourceAttributes defines default AddSourceResourceAttributes value
+	DefaultAddSourceResourceAttributes bool = true
 	// DefaultClient defines default Client
 	DefaultClient string = "otelcol"
 	// DefaultGraphiteTemplate defines default template for Graphite
@@ -255,4 +1197,76 @@ const (
 	DefaultTimestampKey string = "timestamp"
 	// DefaultFlattenBody defines default FlattenBody value
 	DefaultFlattenBody bool = false
+	// DefaultMaxAttributes defines default MaxAttributes value
+	DefaultMaxAttributes int = 0
+	// DefaultPreserveTypes defines default PreserveTypes value
+	DefaultPreserveTypes bool = false
+	// DefaultPayloadSamplingRate defines default PayloadSampling.SampleRate value
+	DefaultPayloadSamplingRate int = 100
+	// DefaultPayloadSamplingMaxFiles defines default PayloadSampling.MaxFiles value
+	DefaultPayloadSamplingMaxFiles int = 10
+	// DefaultPayloadSamplingMaxFileSize defines default PayloadSampling.MaxFileSize value
+	DefaultPayloadSamplingMaxFileSize int = 1 * 1024 * 1024
+	// DefaultUsageCountersRetentionDays defines default UsageCounters.RetentionDays value
+	DefaultUsageCountersRetentionDays int = 31
+	// DefaultUsageCountersFlushInterval defines default UsageCounters.FlushInterval value
+	DefaultUsageCountersFlushInterval time.Duration = time.Minute
+	// DefaultCircuitBreakerFailureThreshold defines default CircuitBreaker.FailureThreshold value
+	DefaultCircuitBreakerFailureThreshold int = 5
+	// DefaultCircuitBreakerProbeInterval defines default CircuitBreaker.ProbeInterval value
+	DefaultCircuitBreakerProbeInterval time.Duration = 30 * time.Second
+	// DefaultRetryBudgetMaxConcurrentRetries defines default RetryBudget.MaxConcurrentRetries value
+	DefaultRetryBudgetMaxConcurrentRetries int = 4
+	// DefaultRetryBudgetMaxRetryBytes defines default RetryBudget.MaxRetryBytes value
+	DefaultRetryBudgetMaxRetryBytes int = 8 * 1024 * 1024
+	// DefaultShutdownTimeout defines default ShutdownTimeout value
+	DefaultShutdownTimeout time.Duration = 10 * time.Second
+	// DefaultDropAuditInterval defines default DropAudit.Interval value
+	DefaultDropAuditInterval time.Duration = time.Minute
+	// DefaultGraphiteTCPTimeout defines default GraphiteTCP.Timeout value
+	DefaultGraphiteTCPTimeout time.Duration = 5 * time.Second
+	// DefaultGraphiteTCPInitialReconnectInterval defines default GraphiteTCP.InitialReconnectInterval value
+	DefaultGraphiteTCPInitialReconnectInterval time.Duration = time.Second
+	// DefaultGraphiteTCPMaxReconnectInterval defines default GraphiteTCP.MaxReconnectInterval value
+	DefaultGraphiteTCPMaxReconnectInterval time.Duration = time.Minute
+	// DefaultBackpressureFailureDuration defines default Backpressure.FailureDuration value
+	DefaultBackpressureFailureDuration time.Duration = time.Minute
+	// DefaultThrottlingDefaultPeriod defines default Throttling.DefaultPeriod value
+	DefaultThrottlingDefaultPeriod time.Duration = 30 * time.Second
+	// DefaultThrottlingMaxPeriod defines default Throttling.MaxPeriod value
+	DefaultThrottlingMaxPeriod time.Duration = 5 * time.Minute
+	// DefaultAdaptiveBatchingTargetPayloadSize defines default AdaptiveBatching.TargetPayloadSize value
+	DefaultAdaptiveBatchingTargetPayloadSize int = 512 * 1024
+	// DefaultAdaptiveBatchingWindowSize defines default AdaptiveBatching.WindowSize value
+	DefaultAdaptiveBatchingWindowSize int = 100
+	// DefaultPayloadSizeEstimationInitialCompressionRatio defines default PayloadSizeEstimation.InitialCompressionRatio value
+	DefaultPayloadSizeEstimationInitialCompressionRatio float64 = 1
+	// DefaultTracesRetryInitialInterval defines default TracesRetry.InitialInterval value
+	DefaultTracesRetryInitialInterval time.Duration = 500 * time.Millisecond
+	// DefaultTracesRetryMaxInterval defines default TracesRetry.MaxInterval value
+	DefaultTracesRetryMaxInterval time.Duration = 5 * time.Second
+	// DefaultTracesRetryMaxElapsedTime defines default TracesRetry.MaxElapsedTime value
+	DefaultTracesRetryMaxElapsedTime time.Duration = 30 * time.Second
+	// DefaultTracesRetryRandomizationFactor defines default TracesRetry.RandomizationFactor value
+	DefaultTracesRetryRandomizationFactor float64 = 0.5
+	// DefaultEndpointDiscoveryRefreshInterval defines default EndpointDiscovery.RefreshInterval value
+	DefaultEndpointDiscoveryRefreshInterval time.Duration = 30 * time.Second
+	// DefaultSourceHostFallbackRefreshInterval defines default SourceHostFallback.RefreshInterval value
+	DefaultSourceHostFallbackRefreshInterval time.Duration = time.Hour
+	// DefaultPropagateTraceContext defines default PropagateTraceContext value
+	DefaultPropagateTraceContext bool = false
+	// DefaultMetricFormatWorkers defines default MetricFormatWorkers value
+	DefaultMetricFormatWorkers int = 1
+	// DefaultTextLogTemplate defines default TextLogTemplate value
+	DefaultTextLogTemplate string = ""
+	// DefaultTextFormatBodyHandling defines default TextFormatBodyHandling value
+	DefaultTextFormatBodyHandling TextFormatBodyHandlingType = TextFormatBodyString
+	// DefaultMaxLogRecordSize defines default MaxLogRecordSize value
+	DefaultMaxLogRecordSize int = 0
+	// DefaultLogTruncationMarker defines default LogTruncationMarker value
+	DefaultLogTruncationMarker string = "...[truncated]"
+	// DefaultDryRun defines default DryRun value
+	DefaultDryRun bool = false
+	// DefaultSignalEnabled defines default Enabled value of Logs, Metrics and Traces
+	DefaultSignalEnabled bool = true
 )
@@ -3,6 +3,7 @@ package sumologicexporter
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/config/confighttp"
@@ -43,11 +44,106 @@ func TestInitExporterInvalidLogFormat(t *testing.T) {
 		},
 		{
 			name:          "unexpected trace format",
-			expectedError: errors.New("unexpected trace format: text"),
+			expectedError: errors.New("unexpected trace format: test_format"),
 			cfg: &Config{
 				LogFormat:    "json",
 				MetricFormat: "carbon2",
-				TraceFormat:  "text",
+				TraceFormat:  "test_format",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				CompressEncoding: "gzip",
+			},
+		},
+		{
+			name:          "text log template with json log format",
+			expectedError: errors.New("text_log_template can only be used with log_format: text"),
+			cfg: &Config{
+				LogFormat:       "json",
+				TextLogTemplate: "%{severity} %{body}",
+				MetricFormat:    "carbon2",
+				TraceFormat:     "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				CompressEncoding: "gzip",
+			},
+		},
+		{
+			name:          "text format body handling with json log format",
+			expectedError: errors.New("text_format_body_handling can only be used with log_format: text"),
+			cfg: &Config{
+				LogFormat:              "json",
+				TextFormatBodyHandling: "json",
+				MetricFormat:           "carbon2",
+				TraceFormat:            "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				CompressEncoding: "gzip",
+			},
+		},
+		{
+			name:          "unexpected text format body handling",
+			expectedError: errors.New("unexpected text_format_body_handling: yaml"),
+			cfg: &Config{
+				LogFormat:              "text",
+				TextFormatBodyHandling: "yaml",
+				MetricFormat:           "carbon2",
+				TraceFormat:            "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				CompressEncoding: "gzip",
+			},
+		},
+		{
+			name:          "negative metric format workers",
+			expectedError: errors.New("metric_format_workers cannot be negative: -1"),
+			cfg: &Config{
+				LogFormat:           "json",
+				MetricFormat:        "carbon2",
+				MetricFormatWorkers: -1,
+				TraceFormat:         "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				CompressEncoding: "gzip",
+			},
+		},
+		{
+			name:          "truncation marker longer than max log record size",
+			expectedError: errors.New("log_truncation_marker has to be shorter than max_log_record_size: 10"),
+			cfg: &Config{
+				LogFormat:           "json",
+				MaxLogRecordSize:    10,
+				LogTruncationMarker: "...[truncated]",
+				MetricFormat:        "carbon2",
+				TraceFormat:         "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				CompressEncoding: "gzip",
+			},
+		},
+		{
+			name:          "adaptive batching with too small window",
+			expectedError: errors.New("adaptive_batching has invalid configuration: window_size has to be at least 20: 10"),
+			cfg: &Config{
+				LogFormat:    "json",
+				MetricFormat: "carbon2",
+				TraceFormat:  "otlp",
+				AdaptiveBatching: AdaptiveBatchingConfig{
+					Enabled:           true,
+					TargetPayloadSize: DefaultAdaptiveBatchingTargetPayloadSize,
+					WindowSize:        10,
+				},
 				HTTPClientSettings: confighttp.HTTPClientSettings{
 					Timeout:  defaultTimeout,
 					Endpoint: "test_endpoint",
@@ -69,6 +165,520 @@ func TestInitExporterInvalidLogFormat(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:          "metric filters with invalid exclude pattern",
+			expectedError: errors.New("metric_filters has invalid configuration: invalid exclude pattern: error parsing regexp: missing closing ]: `[`"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				MetricFilters: MetricFiltersConfig{
+					Exclude: []string{"["},
+				},
+			},
+		},
+		{
+			name:          "metric filters with label matcher without name",
+			expectedError: errors.New("metric_filters has invalid configuration: exclude_labels: name has to be specified"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				MetricFilters: MetricFiltersConfig{
+					ExcludeLabels: []LabelMatcherConfig{{Value: "foo"}},
+				},
+			},
+		},
+		{
+			name:          "negative json logs max attributes",
+			expectedError: errors.New("json_logs.max_attributes cannot be negative: -1"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				JSONLogs: JSONLogs{
+					MaxAttributes: -1,
+				},
+			},
+		},
+		{
+			name:          "payload sampling without directory",
+			expectedError: errors.New("payload_sampling has invalid configuration: directory has to be specified"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				PayloadSampling: PayloadSamplingConfig{
+					Enabled:    true,
+					SampleRate: 1,
+					MaxFiles:   1,
+				},
+			},
+		},
+		{
+			name:          "payload sampling with invalid redact pattern",
+			expectedError: errors.New("payload_sampling has invalid configuration: invalid redact pattern \"[\": error parsing regexp: missing closing ]: `[`"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				PayloadSampling: PayloadSamplingConfig{
+					Enabled:        true,
+					Directory:      "/tmp",
+					SampleRate:     1,
+					MaxFiles:       1,
+					RedactPatterns: []string{"["},
+				},
+			},
+		},
+		{
+			name:          "usage counters with invalid retention",
+			expectedError: errors.New("usage_counters has invalid configuration: retention_days has to be positive: 0"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				UsageCounters: UsageCountersConfig{
+					Enabled:       true,
+					FlushInterval: time.Minute,
+				},
+			},
+		},
+		{
+			name:          "routes without source category",
+			expectedError: errors.New("routes require source_category to be set"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				Routes: []RouteConfig{
+					{SourceCategory: "prod/.*", Endpoint: "https://example.com"},
+				},
+			},
+		},
+		{
+			name:          "route without endpoint",
+			expectedError: errors.New("route 0 has invalid configuration: endpoint has to be specified"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				SourceCategory: "%{env}/app",
+				Routes: []RouteConfig{
+					{SourceCategory: "prod/.*"},
+				},
+			},
+		},
+		{
+			name:          "source category prefix without source category",
+			expectedError: errors.New("source_category_prefix requires source_category to be set"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				SourceCategoryPrefix: "prod/",
+			},
+		},
+		{
+			name: "debug without endpoint",
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout: defaultTimeout,
+				},
+				Debug: DebugConfig{
+					Enabled:   true,
+					Responses: []DebugResponseConfig{{StatusCode: 429, Count: 3}, {}},
+				},
+			},
+		},
+		{
+			name:          "debug without responses",
+			expectedError: errors.New("debug has invalid configuration: responses have to be specified"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				Debug: DebugConfig{
+					Enabled: true,
+				},
+			},
+		},
+		{
+			name:          "debug with invalid status code",
+			expectedError: errors.New("debug has invalid configuration: response 1 has invalid status_code: 42"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				Debug: DebugConfig{
+					Enabled:   true,
+					Responses: []DebugResponseConfig{{}, {StatusCode: 42}},
+				},
+			},
+		},
+		{
+			name:          "debug with negative delay",
+			expectedError: errors.New("debug has invalid configuration: response 0 has negative delay: -1s"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				Debug: DebugConfig{
+					Enabled:   true,
+					Responses: []DebugResponseConfig{{Delay: -time.Second}},
+				},
+			},
+		},
+		{
+			name:          "debug with dry run",
+			expectedError: errors.New("debug cannot be used together with dry_run"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				DryRun: true,
+				Debug: DebugConfig{
+					Enabled:   true,
+					Responses: []DebugResponseConfig{{}},
+				},
+			},
+		},
+		{
+			name:          "invalid idempotency key header",
+			expectedError: errors.New("invalid idempotency_key_header: \"Idempotency Key\""),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				IdempotencyKeyHeader: "Idempotency Key",
+			},
+		},
+		{
+			name:          "negative shutdown timeout",
+			expectedError: errors.New("shutdown_timeout cannot be negative: -1s"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				ShutdownTimeout: -time.Second,
+			},
+		},
+		{
+			name:          "circuit breaker with invalid probe interval",
+			expectedError: errors.New("circuit_breaker has invalid configuration: probe_interval has to be positive: 0s"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				CircuitBreaker: CircuitBreakerConfig{
+					Enabled:          true,
+					FailureThreshold: 3,
+				},
+			},
+		},
+		{
+			name:          "drop audit with invalid interval",
+			expectedError: errors.New("drop_audit has invalid configuration: interval has to be positive: 0s"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				DropAudit: DropAuditConfig{
+					Enabled: true,
+				},
+			},
+		},
+		{
+			name:          "graphite tcp with invalid endpoint",
+			expectedError: errors.New(`graphite_tcp has invalid configuration: invalid endpoint "graphite-relay": address graphite-relay: missing port in address`),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "graphite",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				GraphiteTCP: GraphiteTCPConfig{
+					Endpoint:                 "graphite-relay",
+					Timeout:                  time.Second,
+					InitialReconnectInterval: time.Second,
+					MaxReconnectInterval:     time.Minute,
+				},
+			},
+		},
+		{
+			name:          "graphite tcp with non graphite metric format",
+			expectedError: errors.New("graphite_tcp requires metric_format to be graphite, got: carbon2"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				GraphiteTCP: GraphiteTCPConfig{
+					Endpoint:                 "graphite-relay:2003",
+					Timeout:                  time.Second,
+					InitialReconnectInterval: time.Second,
+					MaxReconnectInterval:     time.Minute,
+				},
+			},
+		},
+		{
+			name:          "backpressure with zero failure duration",
+			expectedError: errors.New("backpressure has invalid configuration: failure_duration has to be positive: 0s"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				Backpressure: BackpressureConfig{
+					Enabled: true,
+				},
+			},
+		},
+		{
+			name:          "throttling with max period lower than default period",
+			expectedError: errors.New("throttling has invalid configuration: max_period (10s) cannot be lower than default_period (30s)"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				Throttling: ThrottlingConfig{
+					PauseSends:    true,
+					DefaultPeriod: 30 * time.Second,
+					MaxPeriod:     10 * time.Second,
+				},
+			},
+		},
+		{
+			name:          "traces retry with max interval lower than initial interval",
+			expectedError: errors.New("traces_retry has invalid configuration: max_interval (1s) cannot be lower than initial_interval (2s)"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				TracesRetry: TracesRetryConfig{
+					Enabled:         true,
+					InitialInterval: 2 * time.Second,
+					MaxInterval:     time.Second,
+					MaxElapsedTime:  time.Minute,
+				},
+			},
+		},
+		{
+			name:          "invalid payload size estimation initial compression ratio",
+			expectedError: errors.New("payload_size_estimation has invalid configuration: initial_compression_ratio has to be between 0.1 and 1: 0.05"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				PayloadSizeEstimation: PayloadSizeEstimationConfig{
+					Enabled:                 true,
+					InitialCompressionRatio: 0.05,
+				},
+			},
+		},
+		{
+			name:          "retry budget without concurrent retries",
+			expectedError: errors.New("retry_budget has invalid configuration: max_concurrent_retries has to be positive: 0"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				RetryBudget: RetryBudgetConfig{
+					Enabled:       true,
+					MaxRetryBytes: 1024,
+				},
+			},
+		},
+		{
+			name:          "endpoint discovery without srv record",
+			expectedError: errors.New("endpoint_discovery has invalid configuration: srv_record has to be specified"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "test_endpoint",
+				},
+				EndpointDiscovery: EndpointDiscoveryConfig{
+					Enabled:         true,
+					RefreshInterval: time.Minute,
+				},
+			},
+		},
+		{
+			name:          "invalid tls pin",
+			expectedError: errors.New(`tls_pinning has invalid configuration: invalid spki_sha256 pin "AAAA": expected 32 bytes, got 3`),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "https://test_endpoint",
+				},
+				TLSPinning: TLSPinningConfig{
+					SPKISHA256: []string{"AAAA"},
+				},
+			},
+		},
+		{
+			name:          "tls pinning with plain http endpoint",
+			expectedError: errors.New("tls_pinning cannot be used with a plain HTTP endpoint"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "http://test_endpoint",
+				},
+				TLSPinning: TLSPinningConfig{
+					SPKISHA256: []string{"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="},
+				},
+			},
+		},
+		{
+			name:          "tls pinning with client headers",
+			expectedError: errors.New("tls_pinning cannot be used together with headers or compression of the HTTP client"),
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:  defaultTimeout,
+					Endpoint: "https://test_endpoint",
+					Headers:  map[string]string{"X-Test": "test"},
+				},
+				TLSPinning: TLSPinningConfig{
+					SPKISHA256: []string{"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="},
+				},
+			},
+		},
 		{
 			name:          "no endpoint and no auth extension specified",
 			expectedError: errors.New("no endpoint and no auth extension specified"),
@@ -82,6 +692,19 @@ func TestInitExporterInvalidLogFormat(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "no endpoint and no auth extension specified in dry run",
+			cfg: &Config{
+				LogFormat:        "json",
+				MetricFormat:     "carbon2",
+				CompressEncoding: "gzip",
+				TraceFormat:      "otlp",
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout: defaultTimeout,
+				},
+				DryRun: true,
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -0,0 +1,114 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// debugEndpoint is the endpoint the data is "sent" to in the debug mode when the endpoint is not configured
+const debugEndpoint = "https://sumologic-exporter-debug.invalid/receiver/v1/http/debug"
+
+// debugTransport is the http.RoundTripper used in the debug mode. Instead of sending
+// the requests, it returns the configured responses for them in order.
+type debugTransport struct {
+	responses []DebugResponseConfig
+	loop      bool
+
+	mu sync.Mutex
+	// current is the index of the response returned for the next request
+	current int
+	// served is the number of requests the current response has been returned for
+	served int
+}
+
+func newDebugTransport(cfg DebugConfig) *debugTransport {
+	return &debugTransport{
+		responses: cfg.Responses,
+		loop:      cfg.Loop,
+	}
+}
+
+// next returns the response for the next request
+func (t *debugTransport) next() DebugResponseConfig {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r := t.responses[t.current]
+	t.served++
+
+	count := r.Count
+	if count == 0 {
+		count = 1
+	}
+	if t.served >= count {
+		switch {
+		case t.current+1 < len(t.responses):
+			t.current++
+			t.served = 0
+		case t.loop:
+			t.current = 0
+			t.served = 0
+		}
+	}
+	return r
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		// the body is read the same way as when it's sent
+		_, _ = io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	r := t.next()
+
+	if r.Delay > 0 {
+		timer := time.NewTimer(r.Delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	statusCode := r.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	header := make(http.Header, len(r.Headers))
+	for k, v := range r.Headers {
+		header.Set(k, v)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}, nil
+}
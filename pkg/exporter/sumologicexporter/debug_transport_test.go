@@ -0,0 +1,172 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func TestDebugTransportResponses(t *testing.T) {
+	testcases := []struct {
+		name     string
+		cfg      DebugConfig
+		expected []int
+	}{
+		{
+			name: "last response is repeated",
+			cfg: DebugConfig{
+				Responses: []DebugResponseConfig{
+					{StatusCode: http.StatusTooManyRequests, Count: 2},
+					{},
+				},
+			},
+			expected: []int{429, 429, 200, 200, 200},
+		},
+		{
+			name: "loop",
+			cfg: DebugConfig{
+				Responses: []DebugResponseConfig{
+					{StatusCode: http.StatusOK, Count: 2},
+					{StatusCode: http.StatusServiceUnavailable},
+				},
+				Loop: true,
+			},
+			expected: []int{200, 200, 503, 200, 200, 503},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := newDebugTransport(tc.cfg)
+
+			var statusCodes []int
+			for range tc.expected {
+				req, err := http.NewRequest(http.MethodPost, debugEndpoint, strings.NewReader("data"))
+				require.NoError(t, err)
+				resp, err := transport.RoundTrip(req)
+				require.NoError(t, err)
+				statusCodes = append(statusCodes, resp.StatusCode)
+			}
+			assert.Equal(t, tc.expected, statusCodes)
+		})
+	}
+}
+
+func TestDebugTransportResponse(t *testing.T) {
+	transport := newDebugTransport(DebugConfig{
+		Responses: []DebugResponseConfig{
+			{
+				StatusCode: http.StatusBadRequest,
+				Body:       `{"id":"ABC","code":"bad.request","message":"Invalid data"}`,
+				Headers:    map[string]string{"Content-Type": "application/json"},
+			},
+		},
+	})
+
+	req, err := http.NewRequest(http.MethodPost, debugEndpoint, strings.NewReader("data"))
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, "400 Bad Request", resp.Status)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"ABC","code":"bad.request","message":"Invalid data"}`, string(body))
+	assert.EqualValues(t, len(body), resp.ContentLength)
+}
+
+func TestDebugTransportDelay(t *testing.T) {
+	client := &http.Client{
+		Transport: newDebugTransport(DebugConfig{
+			Responses: []DebugResponseConfig{{Delay: time.Minute}},
+		}),
+		Timeout: 50 * time.Millisecond,
+	}
+
+	start := time.Now()
+	_, err := client.Post(debugEndpoint, "text/plain", strings.NewReader("data"))
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestDebugMode(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.HTTPClientSettings.Auth = nil
+	cfg.Debug = DebugConfig{
+		Enabled: true,
+		Responses: []DebugResponseConfig{
+			{
+				StatusCode: http.StatusBadRequest,
+				Body:       `{"id":"ABC","code":"bad.request","message":"Invalid data"}`,
+			},
+			{},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	exp, err := initExporter(cfg, createExporterCreateSettings())
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+	logs, _, _ := exp.getDataURLs()
+	assert.Equal(t, debugEndpoint, logs)
+
+	err = exp.pushLogsData(context.Background(), LogRecordsToLogs(exampleLog()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status: 400 Bad Request, id: ABC, code: bad.request, message: Invalid data")
+
+	assert.NoError(t, exp.pushLogsData(context.Background(), LogRecordsToLogs(exampleLog())))
+}
+
+func TestDebugModeUnauthorized(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer server.Close()
+
+	cfg := createTestConfig()
+	cfg.HTTPClientSettings.Endpoint = server.URL
+	cfg.HTTPClientSettings.Auth = nil
+	cfg.Debug = DebugConfig{
+		Enabled:   true,
+		Responses: []DebugResponseConfig{{StatusCode: http.StatusUnauthorized}},
+	}
+	require.NoError(t, cfg.Validate())
+
+	exp, err := initExporter(cfg, createExporterCreateSettings())
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+	for i := 0; i < 2; i++ {
+		err = exp.pushLogsData(context.Background(), LogRecordsToLogs(exampleLog()))
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errUnauthorized)
+	}
+	assert.EqualValues(t, 0, atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,247 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+	"go.uber.org/zap"
+)
+
+// dropReason describes why the records were not sent
+type dropReason string
+
+const (
+	dropReasonFormatError        dropReason = "format_error"
+	dropReasonClientError        dropReason = "http_4xx"
+	dropReasonServerError        dropReason = "http_5xx"
+	dropReasonConnectionError    dropReason = "connection_error"
+	dropReasonCircuitBreakerOpen dropReason = "circuit_breaker_open"
+	dropReasonRetryBudget        dropReason = "retry_budget_exhausted"
+	dropReasonThrottled          dropReason = "throttled"
+	dropReasonAborted            dropReason = "aborted"
+	dropReasonUnsupportedBody    dropReason = "unsupported_body"
+	dropReasonOther              dropReason = "other"
+)
+
+// statusError is returned when the backend responds with an error status code
+type statusError struct {
+	statusCode int
+	err        error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+// dropReasonOf classifies the error returned when sending the data
+func dropReasonOf(err error) dropReason {
+	var (
+		se *statusError
+		ue *url.Error
+		oe *net.OpError
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return dropReasonAborted
+	case errors.Is(err, errCircuitBreakerOpen):
+		return dropReasonCircuitBreakerOpen
+	case errors.Is(err, errThrottled):
+		return dropReasonThrottled
+	case errors.Is(err, errRetryBudgetExhausted):
+		return dropReasonRetryBudget
+	case errors.Is(err, errUnauthorized):
+		return dropReasonClientError
+	case errors.As(err, &se):
+		if se.statusCode >= 500 {
+			return dropReasonServerError
+		}
+		if se.statusCode == http.StatusTooManyRequests {
+			return dropReasonThrottled
+		}
+		return dropReasonClientError
+	case errors.As(err, &ue), errors.As(err, &oe), errors.Is(err, errCarbonUnavailable):
+		return dropReasonConnectionError
+	default:
+		return dropReasonOther
+	}
+}
+
+type dropAuditKey struct {
+	pipeline PipelineType
+	reason   dropReason
+}
+
+// dropAuditEntry is the summary of the records dropped in a single interval
+type dropAuditEntry struct {
+	Exporter      string                                `json:"exporter"`
+	IntervalStart time.Time                             `json:"interval_start"`
+	IntervalEnd   time.Time                             `json:"interval_end"`
+	Dropped       map[PipelineType]map[dropReason]int64 `json:"dropped"`
+}
+
+// dropAudit counts the records which could not be sent per pipeline and reason,
+// and writes the summary every interval, either to the collector's log or to a file.
+type dropAudit struct {
+	logger   *zap.Logger
+	path     string
+	interval time.Duration
+	now      func() time.Time
+
+	exporter string
+	file     *os.File
+
+	// lock guards counts and intervalStart
+	lock          sync.Mutex
+	counts        map[dropAuditKey]int64
+	intervalStart time.Time
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newDropAudit(cfg DropAuditConfig, logger *zap.Logger) *dropAudit {
+	return &dropAudit{
+		logger:   logger.Named("drop_audit"),
+		path:     cfg.Path,
+		interval: cfg.Interval,
+		now:      time.Now,
+		counts:   make(map[dropAuditKey]int64),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// start opens the audit file and starts writing the summaries
+func (da *dropAudit) start(id config.ComponentID) error {
+	da.exporter = id.String()
+	if da.path != "" {
+		file, err := os.OpenFile(da.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open drop audit file: %w", err)
+		}
+		da.file = file
+	}
+
+	da.lock.Lock()
+	da.intervalStart = da.now()
+	da.lock.Unlock()
+
+	da.wg.Add(1)
+	go da.writeLoop()
+	return nil
+}
+
+// shutdown writes the summary of the last interval and closes the audit file
+func (da *dropAudit) shutdown() error {
+	close(da.stopCh)
+	da.wg.Wait()
+
+	err := da.write()
+	if da.file != nil {
+		if closeErr := da.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// add counts the records of the pipeline dropped for the reason
+func (da *dropAudit) add(pipeline PipelineType, reason dropReason, count int) {
+	if da == nil || count <= 0 {
+		return
+	}
+
+	da.lock.Lock()
+	defer da.lock.Unlock()
+	da.counts[dropAuditKey{pipeline: pipeline, reason: reason}] += int64(count)
+}
+
+// addError counts the records of the pipeline dropped because of the error
+func (da *dropAudit) addError(pipeline PipelineType, err error, count int) {
+	if da == nil {
+		return
+	}
+	da.add(pipeline, dropReasonOf(err), count)
+}
+
+func (da *dropAudit) writeLoop() {
+	defer da.wg.Done()
+
+	ticker := time.NewTicker(da.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := da.write(); err != nil {
+				da.logger.Error("Failed to write drop audit entry", zap.Error(err))
+			}
+		case <-da.stopCh:
+			return
+		}
+	}
+}
+
+// write writes the summary of the current interval, even if nothing was dropped,
+// so the audit trail accounts for every interval, and starts the next one
+func (da *dropAudit) write() error {
+	da.lock.Lock()
+	entry := dropAuditEntry{
+		Exporter:      da.exporter,
+		IntervalStart: da.intervalStart,
+		IntervalEnd:   da.now(),
+		Dropped:       make(map[PipelineType]map[dropReason]int64),
+	}
+	for key, count := range da.counts {
+		if entry.Dropped[key.pipeline] == nil {
+			entry.Dropped[key.pipeline] = make(map[dropReason]int64)
+		}
+		entry.Dropped[key.pipeline][key.reason] = count
+	}
+	da.counts = make(map[dropAuditKey]int64)
+	da.intervalStart = entry.IntervalEnd
+	da.lock.Unlock()
+
+	if da.file == nil {
+		da.logger.Info("Dropped data summary",
+			zap.String("exporter", entry.Exporter),
+			zap.Time("interval_start", entry.IntervalStart),
+			zap.Time("interval_end", entry.IntervalEnd),
+			zap.Any("dropped", entry.Dropped),
+		)
+		return nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = da.file.Write(append(line, '\n'))
+	return err
+}
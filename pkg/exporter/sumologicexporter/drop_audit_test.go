@@ -0,0 +1,132 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+func TestDropReasonOf(t *testing.T) {
+	testcases := []struct {
+		err      error
+		expected dropReason
+	}{
+		{err: context.Canceled, expected: dropReasonAborted},
+		{err: errCircuitBreakerOpen, expected: dropReasonCircuitBreakerOpen},
+		{err: errUnauthorized, expected: dropReasonClientError},
+		{err: &statusError{statusCode: 400, err: errors.New("bad request")}, expected: dropReasonClientError},
+		{err: &statusError{statusCode: 429, err: errors.New("too many requests")}, expected: dropReasonThrottled},
+		{err: exporterhelper.NewThrottleRetry(errThrottled, time.Second), expected: dropReasonThrottled},
+		{err: &statusError{statusCode: 503, err: errors.New("unavailable")}, expected: dropReasonServerError},
+		{err: &url.Error{Op: "Post", URL: "http://localhost", Err: errors.New("connection refused")}, expected: dropReasonConnectionError},
+		{err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, expected: dropReasonConnectionError},
+		{err: fmt.Errorf("%w, reconnecting", errCarbonUnavailable), expected: dropReasonConnectionError},
+		{err: fmt.Errorf("wrapped: %w", &statusError{statusCode: 404, err: errors.New("not found")}), expected: dropReasonClientError},
+		{err: errors.New("unexpected"), expected: dropReasonOther},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tc.expected, dropReasonOf(tc.err))
+		})
+	}
+}
+
+func TestDropAuditWritesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drop_audit.json")
+	da := newDropAudit(DropAuditConfig{Interval: time.Hour, Path: path}, zap.NewNop())
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	da.now = func() time.Time { return now }
+	require.NoError(t, da.start(config.NewComponentID(typeStr)))
+
+	da.add(LogsPipeline, dropReasonFormatError, 2)
+	da.addError(LogsPipeline, &statusError{statusCode: 400, err: errors.New("bad request")}, 3)
+	da.addError(MetricsPipeline, errCircuitBreakerOpen, 5)
+	da.add(MetricsPipeline, dropReasonFormatError, 0)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, da.write())
+
+	now = now.Add(time.Minute)
+	require.NoError(t, da.shutdown())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+
+	var entry dropAuditEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "sumologic", entry.Exporter)
+	assert.Equal(t, time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC), entry.IntervalStart)
+	assert.Equal(t, time.Date(2022, 5, 1, 12, 1, 0, 0, time.UTC), entry.IntervalEnd)
+	assert.Equal(t, map[PipelineType]map[dropReason]int64{
+		LogsPipeline: {
+			dropReasonFormatError: 2,
+			dropReasonClientError: 3,
+		},
+		MetricsPipeline: {
+			dropReasonCircuitBreakerOpen: 5,
+		},
+	}, entry.Dropped)
+
+	// the interval without dropped records is written as well
+	entry = dropAuditEntry{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, time.Date(2022, 5, 1, 12, 1, 0, 0, time.UTC), entry.IntervalStart)
+	assert.Equal(t, time.Date(2022, 5, 1, 12, 2, 0, 0, time.UTC), entry.IntervalEnd)
+	assert.Empty(t, entry.Dropped)
+}
+
+func TestSendLogsWithDropAudit(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(500)
+		},
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(404)
+		},
+	})
+	test.s.config.MaxRequestBodySize = 10
+	test.s.config.LogFormat = TextFormat
+	test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+	test.s.dropAudit = newDropAudit(DropAuditConfig{Interval: time.Hour}, zap.NewNop())
+
+	_, err := test.s.sendLogs(context.Background(), newFields(pdata.NewAttributeMap()))
+	require.Error(t, err)
+
+	assert.Equal(t, map[dropAuditKey]int64{
+		{pipeline: LogsPipeline, reason: dropReasonServerError}: 1,
+		{pipeline: LogsPipeline, reason: dropReasonClientError}: 1,
+	}, test.s.dropAudit.counts)
+}
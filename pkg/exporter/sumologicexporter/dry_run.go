@@ -0,0 +1,153 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+// Sumo Logic constraints the data is validated against in the dry run mode
+const (
+	// dryRunMaxPayloadSize is the maximum size of the request body before compression
+	dryRunMaxPayloadSize int = 1 * 1024 * 1024
+	// dryRunMaxFields is the maximum number of fields sent with a single request
+	dryRunMaxFields int = 30
+	// dryRunMaxFieldKeyLength is the maximum length of the field name
+	dryRunMaxFieldKeyLength int = 255
+	// dryRunMaxFieldValueLength is the maximum length of the field value
+	dryRunMaxFieldValueLength int = 200
+)
+
+// dryRunMetricNameRegex matches the metric names which are accepted by Sumo Logic as they are
+var dryRunMetricNameRegex = regexp.MustCompile(`^[0-9a-zA-Z\./_:\-]+$`)
+
+// violationType represents the kind of the Sumo Logic constraint which was violated
+type violationType string
+
+const (
+	violationPayloadSize      violationType = "payload_size"
+	violationFieldsCount      violationType = "fields_count"
+	violationFieldKeyLength   violationType = "field_key_length"
+	violationFieldValueLength violationType = "field_value_length"
+	violationMetricName       violationType = "metric_name"
+)
+
+// violation describes the data which would not be accepted by Sumo Logic as it is
+type violation struct {
+	kind    violationType
+	message string
+}
+
+// validatePayloadSize returns the violation if the body is too large to be sent in a single request
+func validatePayloadSize(size int) []violation {
+	if size <= dryRunMaxPayloadSize {
+		return nil
+	}
+	return []violation{{
+		kind:    violationPayloadSize,
+		message: fmt.Sprintf("payload size %d exceeds the limit of %d bytes", size, dryRunMaxPayloadSize),
+	}}
+}
+
+// validateFields returns the violations of the fields sent along with the data,
+// the source related attributes and the empty fields are skipped the same way as when they are sent
+func validateFields(flds fields) []violation {
+	var (
+		violations []violation
+		count      int
+	)
+
+	flds.orig.Range(func(k string, v pdata.AttributeValue) bool {
+		if k == attributeKeySourceCategory || k == attributeKeySourceHost || k == attributeKeySourceName {
+			return true
+		}
+		sv := v.AsString()
+		if len(sv) == 0 {
+			return true
+		}
+
+		count++
+		if len(k) > dryRunMaxFieldKeyLength {
+			violations = append(violations, violation{
+				kind:    violationFieldKeyLength,
+				message: fmt.Sprintf("field name %q exceeds the limit of %d characters", k, dryRunMaxFieldKeyLength),
+			})
+		}
+		if len(sv) > dryRunMaxFieldValueLength {
+			violations = append(violations, violation{
+				kind:    violationFieldValueLength,
+				message: fmt.Sprintf("value of field %q exceeds the limit of %d characters", k, dryRunMaxFieldValueLength),
+			})
+		}
+		return true
+	})
+
+	if count > dryRunMaxFields {
+		violations = append(violations, violation{
+			kind:    violationFieldsCount,
+			message: fmt.Sprintf("number of fields %d exceeds the limit of %d", count, dryRunMaxFields),
+		})
+	}
+
+	return violations
+}
+
+// validateMetricName returns the violation if the metric name contains characters not accepted by Sumo Logic
+func validateMetricName(name string) []violation {
+	if dryRunMetricNameRegex.MatchString(name) {
+		return nil
+	}
+	return []violation{{
+		kind:    violationMetricName,
+		message: fmt.Sprintf("metric name %q contains characters other than letters, digits and `./_:-`", name),
+	}}
+}
+
+// validate checks the request which would be sent in the dry run mode
+// and reports the violations instead of sending it
+func (s *sender) validate(pipeline PipelineType, body io.Reader, flds fields) error {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	violations := validatePayloadSize(len(b))
+	violations = append(violations, validateFields(flds)...)
+	s.reportViolations(pipeline, violations)
+
+	s.logger.Debug("Dry run, data not sent",
+		zap.String("pipeline", string(pipeline)),
+		zap.Int("size", len(b)),
+		zap.Int("violations", len(violations)),
+	)
+	return nil
+}
+
+// reportViolations logs the violations and records them in the collector's metrics
+func (s *sender) reportViolations(pipeline PipelineType, violations []violation) {
+	for _, v := range violations {
+		s.logger.Warn("Data violates Sumo Logic constraints",
+			zap.String("pipeline", string(pipeline)),
+			zap.String("violation", string(v.kind)),
+			zap.String("message", v.message),
+		)
+		recordViolation(pipeline, v.kind)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+)
+
+// violationsCount returns the number of the violations recorded for the pipeline and the violation type
+func violationsCount(t *testing.T, pipeline PipelineType, kind violationType) float64 {
+	rows, err := view.RetrieveData(viewDryRunViolations.Name)
+	require.NoError(t, err)
+
+	for _, row := range rows {
+		var pipelineMatches, kindMatches bool
+		for _, tag := range row.Tags {
+			pipelineMatches = pipelineMatches || (tag.Key == tagPipelineKey && tag.Value == string(pipeline))
+			kindMatches = kindMatches || (tag.Key == tagViolationKey && tag.Value == string(kind))
+		}
+		if pipelineMatches && kindMatches {
+			return row.Data.(*view.SumData).Value
+		}
+	}
+	return 0
+}
+
+func TestValidatePayloadSize(t *testing.T) {
+	assert.Empty(t, validatePayloadSize(dryRunMaxPayloadSize))
+
+	violations := validatePayloadSize(dryRunMaxPayloadSize + 1)
+	require.Len(t, violations, 1)
+	assert.Equal(t, violationPayloadSize, violations[0].kind)
+}
+
+func TestValidateFields(t *testing.T) {
+	flds := map[string]string{
+		"_sourceCategory": strings.Repeat("c", dryRunMaxFieldValueLength+1),
+		"empty":           "",
+		"long_value":      strings.Repeat("v", dryRunMaxFieldValueLength+1),
+		strings.Repeat("k", dryRunMaxFieldKeyLength+1): "value",
+	}
+	for i := 0; i < dryRunMaxFields; i++ {
+		flds[fmt.Sprintf("key%d", i)] = "value"
+	}
+
+	var kinds []violationType
+	for _, v := range validateFields(fieldsFromMap(flds)) {
+		kinds = append(kinds, v.kind)
+	}
+	assert.ElementsMatch(t, []violationType{violationFieldKeyLength, violationFieldValueLength, violationFieldsCount}, kinds)
+
+	assert.Empty(t, validateFields(fieldsFromMap(map[string]string{"key": "value"})))
+}
+
+func TestValidateMetricName(t *testing.T) {
+	assert.Empty(t, validateMetricName("k8s.pod/cpu_usage:rate-5m"))
+	assert.Len(t, validateMetricName("cpu usage"), 1)
+	assert.Len(t, validateMetricName("cpu=usage"), 1)
+	assert.Len(t, validateMetricName(""), 1)
+}
+
+func TestDryRunLogs(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			assert.Fail(t, "no request should be sent in the dry run mode")
+		},
+	}, func(cfg *Config) {
+		cfg.DryRun = true
+	})
+
+	before := violationsCount(t, LogsPipeline, violationFieldValueLength)
+
+	test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+	_, err := test.s.sendLogs(context.Background(), fieldsFromMap(map[string]string{
+		"key": strings.Repeat("v", dryRunMaxFieldValueLength+1),
+	}))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, *test.reqCounter)
+	assert.Equal(t, before+1, violationsCount(t, LogsPipeline, violationFieldValueLength))
+}
+
+func TestDryRunMetrics(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			assert.Fail(t, "no request should be sent in the dry run mode")
+		},
+	}, func(cfg *Config) {
+		cfg.DryRun = true
+	})
+
+	before := violationsCount(t, MetricsPipeline, violationMetricName)
+
+	invalid := exampleIntMetric()
+	invalid.metric.SetName("test metric")
+	for _, mp := range []metricPair{exampleIntMetric(), invalid} {
+		_, err := test.s.batchMetric(context.Background(), mp, newFields(mp.attributes))
+		require.NoError(t, err)
+	}
+	_, err := test.s.sendMetrics(context.Background(), fieldsFromMap(map[string]string{"key": "value"}))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, *test.reqCounter)
+	assert.Equal(t, before+1, violationsCount(t, MetricsPipeline, violationMetricName))
+}
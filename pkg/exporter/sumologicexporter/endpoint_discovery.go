@@ -0,0 +1,234 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// srvLookupTimeout limits the time of resolving the SRV record
+const srvLookupTimeout = 5 * time.Second
+
+// endpointDiscovery resolves the hosts the data is sent to with a DNS SRV record
+// and balances the requests across them, using smooth weighted round-robin.
+// The record is resolved again in the background every refresh interval.
+type endpointDiscovery struct {
+	logger          *zap.Logger
+	record          string
+	refreshInterval time.Duration
+	lookupSRV       func(ctx context.Context, name string) ([]*net.SRV, error)
+
+	// mu guards targets
+	mu      sync.Mutex
+	targets []*srvTarget
+
+	// cancel stops resolving the record, it's nil until the discovery is started
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type srvTarget struct {
+	// host is the target's host and port, used as the host of the data URLs
+	host   string
+	weight int
+	// current is the target's current weight in the smooth weighted round-robin
+	current int
+}
+
+func newEndpointDiscovery(cfg EndpointDiscoveryConfig, logger *zap.Logger) *endpointDiscovery {
+	return &endpointDiscovery{
+		logger:          logger.Named("endpoint_discovery"),
+		record:          cfg.SRVRecord,
+		refreshInterval: cfg.RefreshInterval,
+		lookupSRV:       lookupSRV,
+	}
+}
+
+func lookupSRV(ctx context.Context, name string) ([]*net.SRV, error) {
+	_, records, err := net.DefaultResolver.LookupSRV(ctx, "", "", name)
+	return records, err
+}
+
+// start resolves the record in the background, right away and then every refresh interval
+func (d *endpointDiscovery) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.wg.Add(1)
+	go d.refreshLoop(ctx)
+}
+
+// shutdown stops resolving the record
+func (d *endpointDiscovery) shutdown() {
+	if d.cancel == nil {
+		return
+	}
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *endpointDiscovery) refreshLoop(ctx context.Context) {
+	defer d.wg.Done()
+
+	d.refresh(ctx)
+
+	ticker := time.NewTicker(d.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.refresh(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// next returns the host the next request is sent to.
+// It returns false when no host was ever resolved.
+func (d *endpointDiscovery) next() (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.targets) == 0 {
+		return "", false
+	}
+
+	var (
+		total int
+		best  *srvTarget
+	)
+	for _, t := range d.targets {
+		t.current += t.weight
+		total += t.weight
+		if best == nil || t.current > best.current {
+			best = t
+		}
+	}
+	best.current -= total
+	return best.host, true
+}
+
+// refresh resolves the record and replaces the targets. When the resolution fails,
+// the previously resolved targets are kept.
+func (d *endpointDiscovery) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, srvLookupTimeout)
+	defer cancel()
+
+	records, err := d.lookupSRV(ctx, d.record)
+	if err != nil {
+		d.logger.Warn("Failed to resolve the SRV record", zap.String("record", d.record), zap.Error(err))
+		return
+	}
+
+	targets := srvTargets(records)
+	if len(targets) == 0 {
+		d.logger.Warn("The SRV record has no targets", zap.String("record", d.record))
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !sameTargets(d.targets, targets) {
+		hosts := make([]string, 0, len(targets))
+		for _, t := range targets {
+			hosts = append(hosts, t.host)
+		}
+		d.logger.Info("Discovered the endpoint hosts", zap.String("record", d.record), zap.Strings("hosts", hosts))
+		d.targets = targets
+	}
+}
+
+// srvTargets returns the targets with the lowest priority, the others are
+// only meant to be used when those are unreachable. The targets with zero weight
+// are only used when all the targets have zero weight, following RFC 2782.
+func srvTargets(records []*net.SRV) []*srvTarget {
+	if len(records) == 0 {
+		return nil
+	}
+
+	minPriority := records[0].Priority
+	for _, r := range records {
+		if r.Priority < minPriority {
+			minPriority = r.Priority
+		}
+	}
+
+	var targets, zeroWeightTargets []*srvTarget
+	for _, r := range records {
+		if r.Priority != minPriority {
+			continue
+		}
+		t := &srvTarget{
+			host:   net.JoinHostPort(strings.TrimSuffix(r.Target, "."), strconv.Itoa(int(r.Port))),
+			weight: int(r.Weight),
+		}
+		if t.weight == 0 {
+			t.weight = 1
+			zeroWeightTargets = append(zeroWeightTargets, t)
+		} else {
+			targets = append(targets, t)
+		}
+	}
+	if len(targets) == 0 {
+		targets = zeroWeightTargets
+	}
+
+	// DNS servers may rotate the records, so keep the order stable
+	sort.Slice(targets, func(i, j int) bool { return targets[i].host < targets[j].host })
+	return targets
+}
+
+func sameTargets(a, b []*srvTarget) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].host != b[i].host || a[i].weight != b[i].weight {
+			return false
+		}
+	}
+	return true
+}
+
+// roundTripper returns the round tripper sending the requests to the discovered hosts.
+func (d *endpointDiscovery) roundTripper(base http.RoundTripper) http.RoundTripper {
+	return &endpointDiscoveryRoundTripper{base: base, discovery: d}
+}
+
+type endpointDiscoveryRoundTripper struct {
+	base      http.RoundTripper
+	discovery *endpointDiscovery
+}
+
+// RoundTrip replaces the host of the request's URL with the next discovered host.
+// The request is sent as it is when no host was ever resolved.
+func (rt *endpointDiscoveryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if host, ok := rt.discovery.next(); ok {
+		req = req.Clone(req.Context())
+		req.URL.Host = host
+		req.Host = ""
+	}
+	return rt.base.RoundTrip(req)
+}
@@ -0,0 +1,189 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testSRVLookup struct {
+	records []*net.SRV
+	err     error
+	calls   int
+}
+
+func (l *testSRVLookup) lookup(context.Context, string) ([]*net.SRV, error) {
+	l.calls++
+	return l.records, l.err
+}
+
+func newTestEndpointDiscovery(lookup *testSRVLookup) *endpointDiscovery {
+	return &endpointDiscovery{
+		logger:          zap.NewNop(),
+		record:          "_ingest._tcp.example.com",
+		refreshInterval: time.Minute,
+		lookupSRV:       lookup.lookup,
+	}
+}
+
+func countHosts(t *testing.T, d *endpointDiscovery, n int) map[string]int {
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		host, ok := d.next()
+		require.True(t, ok)
+		counts[host]++
+	}
+	return counts
+}
+
+func TestEndpointDiscoveryWeights(t *testing.T) {
+	lookup := &testSRVLookup{records: []*net.SRV{
+		{Target: "a.example.com.", Port: 443, Priority: 10, Weight: 3},
+		{Target: "b.example.com.", Port: 443, Priority: 10, Weight: 1},
+		{Target: "c.example.com.", Port: 443, Priority: 10, Weight: 0},
+		// only used when the targets with lower priority are unavailable
+		{Target: "backup.example.com.", Port: 443, Priority: 20, Weight: 5},
+	}}
+	d := newTestEndpointDiscovery(lookup)
+	d.refresh(context.Background())
+
+	assert.Equal(t, map[string]int{
+		"a.example.com:443": 300,
+		"b.example.com:443": 100,
+	}, countHosts(t, d, 400))
+	assert.Equal(t, 1, lookup.calls)
+}
+
+func TestEndpointDiscoveryZeroWeights(t *testing.T) {
+	lookup := &testSRVLookup{records: []*net.SRV{
+		{Target: "a.example.com.", Port: 443},
+		{Target: "b.example.com.", Port: 8443},
+	}}
+	d := newTestEndpointDiscovery(lookup)
+	d.refresh(context.Background())
+
+	assert.Equal(t, map[string]int{
+		"a.example.com:443":  2,
+		"b.example.com:8443": 2,
+	}, countHosts(t, d, 4))
+}
+
+func TestEndpointDiscoveryRefresh(t *testing.T) {
+	lookup := &testSRVLookup{err: errors.New("no such host")}
+	d := newTestEndpointDiscovery(lookup)
+
+	d.refresh(context.Background())
+	_, ok := d.next()
+	assert.False(t, ok)
+
+	lookup.records, lookup.err = []*net.SRV{{Target: "a.example.com.", Port: 443, Weight: 1}}, nil
+	d.refresh(context.Background())
+	host, ok := d.next()
+	assert.True(t, ok)
+	assert.Equal(t, "a.example.com:443", host)
+
+	// the previously resolved targets are kept
+	lookup.records, lookup.err = nil, errors.New("no such host")
+	d.refresh(context.Background())
+	host, ok = d.next()
+	assert.True(t, ok)
+	assert.Equal(t, "a.example.com:443", host)
+
+	lookup.records, lookup.err = []*net.SRV{}, nil
+	d.refresh(context.Background())
+	host, ok = d.next()
+	assert.True(t, ok)
+	assert.Equal(t, "a.example.com:443", host)
+
+	lookup.records = []*net.SRV{{Target: "b.example.com.", Port: 443, Weight: 1}}
+	d.refresh(context.Background())
+	host, ok = d.next()
+	assert.True(t, ok)
+	assert.Equal(t, "b.example.com:443", host)
+	assert.Equal(t, 5, lookup.calls)
+}
+
+func TestEndpointDiscoveryStartShutdown(t *testing.T) {
+	resolved := make(chan struct{})
+	lookupCtx := make(chan context.Context, 1)
+	d := &endpointDiscovery{
+		logger:          zap.NewNop(),
+		record:          "_ingest._tcp.example.com",
+		refreshInterval: time.Millisecond,
+		lookupSRV: func(ctx context.Context, name string) ([]*net.SRV, error) {
+			select {
+			case <-resolved:
+				// the next lookup blocks until the discovery is shut down
+				lookupCtx <- ctx
+				<-ctx.Done()
+				return nil, ctx.Err()
+			default:
+				close(resolved)
+				return []*net.SRV{{Target: "a.example.com.", Port: 443, Weight: 1}}, nil
+			}
+		},
+	}
+
+	_, ok := d.next()
+	assert.False(t, ok)
+
+	d.start()
+	<-lookupCtx
+
+	// the hosts are still returned while the record is being resolved again
+	host, ok := d.next()
+	assert.True(t, ok)
+	assert.Equal(t, "a.example.com:443", host)
+
+	d.shutdown()
+}
+
+func TestEndpointDiscoveryRoundTripper(t *testing.T) {
+	var receivedHost string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		receivedHost = req.Host
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(serverURL.Port())
+	require.NoError(t, err)
+
+	lookup := &testSRVLookup{records: []*net.SRV{{Target: "127.0.0.1.", Port: uint16(port), Weight: 1}}}
+	d := newTestEndpointDiscovery(lookup)
+	d.refresh(context.Background())
+	client := &http.Client{
+		Transport: d.roundTripper(http.DefaultTransport),
+	}
+
+	resp, err := client.Post("http://ingest.example.invalid/receiver/v1/http", "text/plain", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, serverURL.Host, receivedHost)
+}
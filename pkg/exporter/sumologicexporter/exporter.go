@@ -21,8 +21,12 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/model/pdata"
@@ -32,6 +36,13 @@ import (
 	"github.com/SumoLogic/sumologic-otel-collector/pkg/extension/sumologicextension"
 )
 
+// exportReporter is implemented by the extensions which track the results of the exports,
+// like sumologichealthcheckextension. It's matched by the method, so the exporter
+// doesn't depend on the extension.
+type exportReporter interface {
+	ReportExport(exporter config.ComponentID, pipeline string, err error)
+}
+
 const (
 	logsDataUrl    = "/api/v1/collector/logs"
 	metricsDataUrl = "/api/v1/collector/metrics"
@@ -47,9 +58,16 @@ type sumologicexporter struct {
 	clientLock sync.RWMutex
 	client     *http.Client
 
-	filter              filter
-	prometheusFormatter prometheusFormatter
-	graphiteFormatter   graphiteFormatter
+	filter          filter
+	metricFormatter MetricFormatter
+	backpressure    *backpressure
+	// senderDeps are passed to the sender of every push
+	senderDeps
+	// endpointDiscovery is nil unless the endpoint discovery is enabled
+	endpointDiscovery *endpointDiscovery
+	// exportReporters are the extensions the results of the exports are reported to,
+	// e.g. the sumologic_health_check extension
+	exportReporters []exportReporter
 
 	// Lock around data URLs is needed because the reconfiguration of the exporter
 	// can happen asynchronously whenever the exporter is re registering.
@@ -57,6 +75,21 @@ type sumologicexporter struct {
 	dataUrlMetrics string
 	dataUrlLogs    string
 	dataUrlTraces  string
+
+	// inFlight tracks the pushes in progress, so the shutdown can wait
+	// until they flush the buffered data.
+	inFlight sync.WaitGroup
+	// abortCh is closed when the shutdown timeout is exceeded,
+	// which cancels the contexts of the pushes in progress.
+	abortCh   chan struct{}
+	abortOnce sync.Once
+	// unflushed counts the records dropped by the pushes aborted on shutdown.
+	unflushed int64
+
+	// baseUrlCallbackOnce ensures that the callback updating data URLs
+	// is registered in sumologicextension only once, even though configure()
+	// can be called multiple times.
+	baseUrlCallbackOnce sync.Once
 }
 
 func initExporter(cfg *Config, createSettings component.ExporterCreateSettings) (*sumologicexporter, error) {
@@ -69,6 +102,9 @@ func initExporter(cfg *Config, createSettings component.ExporterCreateSettings)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.SourceHostFallback.Enabled && !sfs.host.isSet() {
+		sfs.hostFallback = newFQDNResolver(cfg.SourceHostFallback, createSettings.Logger)
+	}
 
 	cfg.MetadataAttributes = addSourceMetadataFields(cfg.MetadataAttributes)
 	f, err := newFilter(cfg.MetadataAttributes)
@@ -76,24 +112,126 @@ func initExporter(cfg *Config, createSettings component.ExporterCreateSettings)
 		return nil, err
 	}
 
-	pf, err := newPrometheusFormatter()
+	mf, err := newMetricFormatter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var ps *payloadSampler
+	if cfg.PayloadSampling.Enabled {
+		ps, err = newPayloadSampler(cfg.PayloadSampling)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var uc *usageCounters
+	if cfg.UsageCounters.Enabled {
+		uc = newUsageCounters(cfg.UsageCounters, createSettings.Logger)
+	}
+
+	rs, err := newRoutes(cfg.Routes)
 	if err != nil {
 		return nil, err
 	}
 
-	gf, err := newGraphiteFormatter(cfg.GraphiteTemplate)
+	var da *dropAudit
+	if cfg.DropAudit.Enabled {
+		da = newDropAudit(cfg.DropAudit, createSettings.Logger)
+	}
+
+	var ct *carbonTCPClient
+	if cfg.GraphiteTCP.Endpoint != "" {
+		ct = newCarbonTCPClient(cfg.GraphiteTCP, createSettings.Logger)
+	}
+
+	mfl, err := newMetricFilter(cfg.MetricFilters)
 	if err != nil {
 		return nil, err
 	}
 
+	var cbs *circuitBreakers
+	if cfg.CircuitBreaker.Enabled {
+		cbs = newCircuitBreakers(cfg.CircuitBreaker, createSettings.Logger)
+	}
+
+	var bp *backpressure
+	if cfg.Backpressure.Enabled {
+		bp = newBackpressure(cfg.Backpressure, createSettings.Logger)
+	}
+
+	var bst *batchSizeTuner
+	if cfg.AdaptiveBatching.Enabled {
+		bst = newBatchSizeTuner(cfg.AdaptiveBatching, cfg.MaxRequestBodySize, createSettings.Logger)
+	}
+
+	var pse *payloadSizeEstimator
+	if cfg.PayloadSizeEstimation.Enabled {
+		pse = newPayloadSizeEstimator(cfg.PayloadSizeEstimation)
+	}
+
+	var rb *sharedRetryBudget
+	if cfg.RetryBudget.Enabled {
+		rb = getSharedRetryBudget(cfg.ID(), cfg.RetryBudget, createSettings.Logger)
+	}
+
+	var ed *endpointDiscovery
+	if cfg.EndpointDiscovery.Enabled {
+		ed = newEndpointDiscovery(cfg.EndpointDiscovery, createSettings.Logger)
+	}
+
 	se := &sumologicexporter{
 		config:  cfg,
 		logger:  createSettings.Logger,
 		sources: sfs,
 		// NOTE: client is now set in start()
-		filter:              f,
-		prometheusFormatter: pf,
-		graphiteFormatter:   gf,
+		filter:          f,
+		metricFormatter: mf,
+		backpressure:    bp,
+		senderDeps: senderDeps{
+			payloadSampler:  ps,
+			usageCounters:   uc,
+			circuitBreakers: cbs,
+			routes:          rs,
+			bodySizeLimits:  newRequestBodySizeLimits(cfg.MaxRequestBodySize, createSettings.Logger),
+			dropAudit:       da,
+			carbonTCP:       ct,
+			metricFilter:    mfl,
+			throttling:      newThrottling(cfg.Throttling, createSettings.Logger),
+			batchSizeTuner:  bst,
+			sizeEstimator:   pse,
+			sharedBudget:    rb,
+			textLogTemplate: newTextLogTemplate(cfg.TextLogTemplate, cfg.TextFormatBodyHandling, cfg.JSONLogs.LogKey),
+			userAgent:       newUserAgent(cfg, createSettings.BuildInfo),
+		},
+		endpointDiscovery: ed,
+		abortCh:           make(chan struct{}),
+	}
+
+	if ps != nil {
+		se.logger.Warn(
+			"Payload sampling is enabled, outgoing requests are stored locally",
+			zap.String("directory", cfg.PayloadSampling.Directory),
+			zap.Int("sample_rate", cfg.PayloadSampling.SampleRate),
+		)
+	}
+
+	if cfg.DryRun {
+		se.logger.Warn("Dry run mode is enabled, data is validated and not sent to Sumo Logic")
+	}
+
+	if cfg.Debug.Enabled {
+		se.logger.Warn("Debug mode is enabled, data is not sent to Sumo Logic and its responses are simulated")
+	}
+
+	for pipeline, enabled := range map[PipelineType]bool{
+		LogsPipeline:    cfg.Logs.Enabled,
+		MetricsPipeline: cfg.Metrics.Enabled,
+		TracesPipeline:  cfg.Traces.Enabled,
+	} {
+		if !enabled {
+			se.logger.Warn("Sending is disabled, data is discarded", zap.String("pipeline", string(pipeline)))
+		}
 	}
 
 	se.logger.Info(
@@ -101,6 +239,7 @@ func initExporter(cfg *Config, createSettings component.ExporterCreateSettings)
 		zap.String("log_format", string(cfg.LogFormat)),
 		zap.String("metric_format", string(cfg.MetricFormat)),
 		zap.String("trace_format", string(cfg.TraceFormat)),
+		zap.String("user_agent", se.userAgent),
 	)
 
 	return se, nil
@@ -149,10 +288,10 @@ func newLogsExporter(
 		return nil, fmt.Errorf("failed to initialize the logs exporter: %w", err)
 	}
 
-	return exporterhelper.NewLogsExporter(
+	exp, err := exporterhelper.NewLogsExporter(
 		cfg,
 		params,
-		se.pushLogsData,
+		se.trackedPushLogsData,
 		// Disable exporterhelper Timeout, since we are using a custom mechanism
 		// within exporter itself
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
@@ -161,6 +300,14 @@ func newLogsExporter(
 		exporterhelper.WithStart(se.start),
 		exporterhelper.WithShutdown(se.shutdown),
 	)
+	if err != nil {
+		return nil, err
+	}
+	exp = &shutdownTimeoutLogsExporter{LogsExporter: exp, se: se}
+	if se.backpressure == nil {
+		return exp, nil
+	}
+	return &backpressureLogsExporter{LogsExporter: exp, backpressure: se.backpressure}, nil
 }
 
 func newMetricsExporter(
@@ -172,10 +319,10 @@ func newMetricsExporter(
 		return nil, err
 	}
 
-	return exporterhelper.NewMetricsExporter(
+	exp, err := exporterhelper.NewMetricsExporter(
 		cfg,
 		params,
-		se.pushMetricsData,
+		se.trackedPushMetricsData,
 		// Disable exporterhelper Timeout, since we are using a custom mechanism
 		// within exporter itself
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
@@ -184,6 +331,14 @@ func newMetricsExporter(
 		exporterhelper.WithStart(se.start),
 		exporterhelper.WithShutdown(se.shutdown),
 	)
+	if err != nil {
+		return nil, err
+	}
+	exp = &shutdownTimeoutMetricsExporter{MetricsExporter: exp, se: se}
+	if se.backpressure == nil {
+		return exp, nil
+	}
+	return &backpressureMetricsExporter{MetricsExporter: exp, backpressure: se.backpressure}, nil
 }
 
 func newTracesExporter(
@@ -195,10 +350,10 @@ func newTracesExporter(
 		return nil, err
 	}
 
-	return exporterhelper.NewTracesExporter(
+	exp, err := exporterhelper.NewTracesExporter(
 		cfg,
 		params,
-		se.pushTracesData,
+		se.trackedPushTracesData,
 		// Disable exporterhelper Timeout, since we are using a custom mechanism
 		// within exporter itself
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
@@ -207,6 +362,14 @@ func newTracesExporter(
 		exporterhelper.WithStart(se.start),
 		exporterhelper.WithShutdown(se.shutdown),
 	)
+	if err != nil {
+		return nil, err
+	}
+	exp = &shutdownTimeoutTracesExporter{TracesExporter: exp, se: se}
+	if se.backpressure == nil {
+		return exp, nil
+	}
+	return &backpressureTracesExporter{TracesExporter: exp, backpressure: se.backpressure}, nil
 }
 
 // pushLogsData groups data with common metadata and sends them as separate batched requests.
@@ -214,11 +377,13 @@ func newTracesExporter(
 // so they can be handled by OTC retry mechanism
 func (se *sumologicexporter) pushLogsData(ctx context.Context, ld pdata.Logs) error {
 	var (
-		currentMetadata  fields = newFields(pdata.NewAttributeMap())
-		previousMetadata fields = newFields(pdata.NewAttributeMap())
-		errs             []error
-		droppedRecords   []logPair
-		err              error
+		currentMetadata        fields = newFields(pdata.NewAttributeMap())
+		previousMetadata       fields = newFields(pdata.NewAttributeMap())
+		errs                   []error
+		droppedRecords         []logPair
+		truncatedRecords       int
+		unsupportedBodyRecords int
+		err                    error
 	)
 
 	c, err := newCompressor(se.config.CompressEncoding)
@@ -234,13 +399,18 @@ func (se *sumologicexporter) pushLogsData(ctx context.Context, ld pdata.Logs) er
 		se.filter,
 		se.sources,
 		c,
-		se.prometheusFormatter,
-		se.graphiteFormatter,
+		se.metricFormatter,
 		metricsUrl,
 		logsUrl,
 		tracesUrl,
+		se.senderDeps,
 	)
 
+	var groups *logGroups
+	if se.config.LogFormat == OTLPLogFormat {
+		groups = newLogGroups()
+	}
+
 	// Iterate over ResourceLogs
 	rls := ld.ResourceLogs()
 	for i := 0; i < rls.Len(); i++ {
@@ -255,6 +425,16 @@ func (se *sumologicexporter) pushLogsData(ctx context.Context, ld pdata.Logs) er
 			logs := ill.LogRecords()
 			for k := 0; k < logs.Len(); k++ {
 				log := logs.At(k)
+				if truncated, ok := truncateLogRecord(log, se.config.MaxLogRecordSize, se.config.LogTruncationMarker); ok {
+					log = truncated
+					truncatedRecords++
+				}
+
+				unsupportedBody := se.config.LogFormat == TextFormat && isUnsupportedTextLogBody(log.Body())
+				if unsupportedBody && se.config.TextFormatBodyHandling == TextFormatBodyDrop {
+					unsupportedBodyRecords++
+					continue
+				}
 				logAttrs := log.Attributes()
 
 				// copy resource attributes into logs attributes
@@ -268,6 +448,9 @@ func (se *sumologicexporter) pushLogsData(ctx context.Context, ld pdata.Logs) er
 					attributes.Insert(k, v)
 					return true
 				})
+				if unsupportedBody && se.config.TextFormatBodyHandling == TextFormatBodyAttributes {
+					moveTextLogBodyToAttributes(attributes, log.Body(), se.config.JSONLogs.LogKey)
+				}
 
 				// Put merged attributes into logPair
 				lp := logPair{
@@ -281,6 +464,13 @@ func (se *sumologicexporter) pushLogsData(ctx context.Context, ld pdata.Logs) er
 					currentMetadata.translateAttributes()
 				}
 
+				// In OTLP format the records are grouped by their metadata and sent
+				// in one request per group, after all of them are processed
+				if groups != nil {
+					groups.add(currentMetadata, lp)
+					continue
+				}
+
 				// If metadata differs from currently buffered, flush the buffer
 				if !currentMetadata.equals(previousMetadata) && !previousMetadata.isEmpty() {
 					var dropped []logPair
@@ -306,11 +496,41 @@ func (se *sumologicexporter) pushLogsData(ctx context.Context, ld pdata.Logs) er
 		}
 	}
 
-	// Flush pending logs
-	dropped, err := sdr.sendLogs(ctx, previousMetadata)
-	if err != nil {
-		droppedRecords = append(droppedRecords, dropped...)
-		errs = append(errs, err)
+	if truncatedRecords > 0 {
+		recordTruncatedLogRecords(truncatedRecords)
+	}
+	if unsupportedBodyRecords > 0 {
+		recordUnsupportedBodyLogRecordsDropped(unsupportedBodyRecords)
+		se.dropAudit.add(LogsPipeline, dropReasonUnsupportedBody, unsupportedBodyRecords)
+	}
+
+	if groups != nil {
+		for _, g := range groups.groups {
+			for _, lp := range g.records {
+				dropped, err := sdr.batchLog(ctx, lp, g.fields)
+				if err != nil {
+					droppedRecords = append(droppedRecords, dropped...)
+					errs = append(errs, err)
+				}
+			}
+
+			if sdr.countLogs() == 0 {
+				continue
+			}
+			dropped, err := sdr.sendLogs(ctx, g.fields)
+			if err != nil {
+				droppedRecords = append(droppedRecords, dropped...)
+				errs = append(errs, err)
+			}
+			sdr.cleanLogsBuffer()
+		}
+	} else {
+		// Flush pending logs
+		dropped, err := sdr.sendLogs(ctx, previousMetadata)
+		if err != nil {
+			droppedRecords = append(droppedRecords, dropped...)
+			errs = append(errs, err)
+		}
 	}
 
 	if len(droppedRecords) > 0 {
@@ -358,11 +578,11 @@ func (se *sumologicexporter) pushMetricsData(ctx context.Context, md pdata.Metri
 		se.filter,
 		se.sources,
 		c,
-		se.prometheusFormatter,
-		se.graphiteFormatter,
+		se.metricFormatter,
 		metricsUrl,
 		logsUrl,
 		tracesUrl,
+		se.senderDeps,
 	)
 
 	// Iterate over ResourceMetrics
@@ -454,6 +674,11 @@ func (se *sumologicexporter) pushMetricsData(ctx context.Context, md pdata.Metri
 // from sumologicextension which at this point should already detect the problem with
 // authorization (via heartbeats) and prepare new collector credentials to be available.
 func (se *sumologicexporter) handleUnauthorizedErrors(ctx context.Context, errs ...error) {
+	if se.config.Debug.Enabled {
+		// The unauthorized errors are simulated in the debug mode, reconfiguration
+		// would replace the debug client with one sending the data for real.
+		return
+	}
 	for _, err := range errs {
 		if errors.Is(err, errUnauthorized) {
 			se.logger.Warn("Received unauthorized status code, triggering reconfiguration")
@@ -482,11 +707,11 @@ func (se *sumologicexporter) pushTracesData(ctx context.Context, td pdata.Traces
 		se.filter,
 		se.sources,
 		c,
-		se.prometheusFormatter,
-		se.graphiteFormatter,
+		se.metricFormatter,
 		metricsUrl,
 		logsUrl,
 		tracesUrl,
+		se.senderDeps,
 	)
 	err = sdr.sendTraces(ctx, td, currentMetadata)
 	se.handleUnauthorizedErrors(ctx, err)
@@ -499,9 +724,49 @@ func (se *sumologicexporter) pushTracesData(ctx context.Context, td pdata.Traces
 
 func (se *sumologicexporter) start(ctx context.Context, host component.Host) error {
 	se.host = host
+	for _, e := range host.GetExtensions() {
+		if r, ok := e.(exportReporter); ok {
+			se.exportReporters = append(se.exportReporters, r)
+		}
+	}
+	if se.usageCounters != nil {
+		if err := se.usageCounters.start(ctx, host, se.config.ID()); err != nil {
+			return err
+		}
+	}
+	if se.dropAudit != nil {
+		if err := se.dropAudit.start(se.config.ID()); err != nil {
+			return err
+		}
+	}
+	if se.config.DryRun {
+		// nothing is sent, so there is no need for the HTTP client and the data URLs
+		return nil
+	}
+	if se.config.Debug.Enabled {
+		se.configureDebug()
+		return nil
+	}
+	if se.endpointDiscovery != nil {
+		se.endpointDiscovery.start()
+	}
 	return se.configure(ctx)
 }
 
+// configureDebug sets up the HTTP client returning the simulated responses in the debug mode.
+// The auth extension is not used then, so neither the endpoint nor the extension is required.
+func (se *sumologicexporter) configureDebug() {
+	endpoint := se.config.HTTPClientSettings.Endpoint
+	if endpoint == "" {
+		endpoint = debugEndpoint
+	}
+	se.setDataURLs(endpoint, endpoint, endpoint)
+	se.setHTTPClient(&http.Client{
+		Transport: newDebugTransport(se.config.Debug),
+		Timeout:   se.config.HTTPClientSettings.Timeout,
+	})
+}
+
 func (se *sumologicexporter) configure(ctx context.Context) error {
 	var (
 		ext          *sumologicextension.SumologicExtension
@@ -536,18 +801,18 @@ func (se *sumologicexporter) configure(ctx context.Context) error {
 		// endpoint was not set then send data on a collector generic ingest URL
 		// with authentication set by sumologicextension.
 
-		u, err := url.Parse(ext.BaseUrl())
+		logsUrl, metricsUrl, tracesUrl, err := getDataURLsFromBaseUrl(ext.BaseUrl())
 		if err != nil {
-			return fmt.Errorf("failed to parse API base URL from sumologicextension: %w", err)
+			return err
 		}
+		se.setDataURLs(logsUrl, metricsUrl, tracesUrl)
 
-		logsUrl := *u
-		logsUrl.Path = logsDataUrl
-		metricsUrl := *u
-		metricsUrl.Path = metricsDataUrl
-		tracesUrl := *u
-		tracesUrl.Path = tracesDataUrl
-		se.setDataURLs(logsUrl.String(), metricsUrl.String(), tracesUrl.String())
+		// The base URL can change when sumologicextension re-registers the collector
+		// (e.g. after being redirected to a different deployment) so make sure
+		// the new data URLs are used without restarting the collector.
+		se.baseUrlCallbackOnce.Do(func() {
+			ext.OnBaseUrlChange(se.updateDataURLs)
+		})
 
 	} else if httpSettings.Endpoint != "" {
 		se.setDataURLs(httpSettings.Endpoint, httpSettings.Endpoint, httpSettings.Endpoint)
@@ -561,15 +826,65 @@ func (se *sumologicexporter) configure(ctx context.Context) error {
 		return fmt.Errorf("no auth extension and no endpoint specified")
 	}
 
-	client, err := httpSettings.ToClient(se.host.GetExtensions(), component.TelemetrySettings{})
+	client, err := se.newHTTPClient(httpSettings)
 	if err != nil {
 		return fmt.Errorf("failed to create HTTP Client: %w", err)
 	}
 
+	if se.endpointDiscovery != nil {
+		client.Transport = se.endpointDiscovery.roundTripper(client.Transport)
+	}
+
 	se.setHTTPClient(client)
 	return nil
 }
 
+// newHTTPClient creates the HTTP client from the settings,
+// with the public keys of the endpoints verified when pinning is configured.
+func (se *sumologicexporter) newHTTPClient(httpSettings confighttp.HTTPClientSettings) (*http.Client, error) {
+	if len(se.config.TLSPinning.SPKISHA256) == 0 {
+		return httpSettings.ToClient(se.host.GetExtensions(), component.TelemetrySettings{})
+	}
+
+	pins, err := newSPKIPins(se.config.TLSPinning.SPKISHA256)
+	if err != nil {
+		return nil, err
+	}
+	return newPinnedHTTPClient(httpSettings, pins, se.host)
+}
+
+// updateDataURLs sets data URLs based on the provided API base URL.
+func (se *sumologicexporter) updateDataURLs(baseUrl string) {
+	logsUrl, metricsUrl, tracesUrl, err := getDataURLsFromBaseUrl(baseUrl)
+	if err != nil {
+		se.logger.Error("Failed to update data URLs", zap.Error(err))
+		return
+	}
+
+	se.setDataURLs(logsUrl, metricsUrl, tracesUrl)
+	se.logger.Info("API base URL changed, updated data URLs",
+		zap.String("logs_url", logsUrl),
+		zap.String("metrics_url", metricsUrl),
+		zap.String("traces_url", tracesUrl),
+	)
+}
+
+// getDataURLsFromBaseUrl returns collector generic ingest URLs for the provided API base URL.
+func getDataURLsFromBaseUrl(baseUrl string) (logs, metrics, traces string, err error) {
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to parse API base URL from sumologicextension: %w", err)
+	}
+
+	logsUrl := *u
+	logsUrl.Path = logsDataUrl
+	metricsUrl := *u
+	metricsUrl.Path = metricsDataUrl
+	tracesUrl := *u
+	tracesUrl.Path = tracesDataUrl
+	return logsUrl.String(), metricsUrl.String(), tracesUrl.String(), nil
+}
+
 func (se *sumologicexporter) setHTTPClient(client *http.Client) {
 	se.clientLock.Lock()
 	se.client = client
@@ -594,6 +909,192 @@ func (se *sumologicexporter) getDataURLs() (logs, metrics, traces string) {
 	return se.dataUrlLogs, se.dataUrlMetrics, se.dataUrlTraces
 }
 
-func (se *sumologicexporter) shutdown(context.Context) error {
-	return nil
+// shutdownTimeoutLogsExporter limits the whole shutdown of the logs exporter,
+// including draining the sending queue, to the shutdown timeout
+type shutdownTimeoutLogsExporter struct {
+	component.LogsExporter
+	se *sumologicexporter
+}
+
+func (e *shutdownTimeoutLogsExporter) Shutdown(ctx context.Context) error {
+	return e.se.shutdownWithTimeout(ctx, e.LogsExporter.Shutdown)
+}
+
+// shutdownTimeoutMetricsExporter limits the whole shutdown of the metrics exporter,
+// including draining the sending queue, to the shutdown timeout
+type shutdownTimeoutMetricsExporter struct {
+	component.MetricsExporter
+	se *sumologicexporter
+}
+
+func (e *shutdownTimeoutMetricsExporter) Shutdown(ctx context.Context) error {
+	return e.se.shutdownWithTimeout(ctx, e.MetricsExporter.Shutdown)
+}
+
+// shutdownTimeoutTracesExporter limits the whole shutdown of the traces exporter,
+// including draining the sending queue, to the shutdown timeout
+type shutdownTimeoutTracesExporter struct {
+	component.TracesExporter
+	se *sumologicexporter
+}
+
+func (e *shutdownTimeoutTracesExporter) Shutdown(ctx context.Context) error {
+	return e.se.shutdownWithTimeout(ctx, e.TracesExporter.Shutdown)
+}
+
+// shutdownWithTimeout calls the given shutdown, i.e. the one of exporterhelper which drains
+// the sending queue before calling se.shutdown, and aborts the pushes in progress
+// when it takes longer than the shutdown timeout.
+func (se *sumologicexporter) shutdownWithTimeout(ctx context.Context, shutdown component.ShutdownFunc) error {
+	timer := time.AfterFunc(se.config.ShutdownTimeout, se.abort)
+	defer timer.Stop()
+	return shutdown(ctx)
+}
+
+// abort cancels the contexts of the pushes in progress and of the ones started afterwards.
+func (se *sumologicexporter) abort() {
+	se.abortOnce.Do(func() { close(se.abortCh) })
+}
+
+func (se *sumologicexporter) shutdown(ctx context.Context) error {
+	se.flushInFlight(ctx)
+
+	if se.endpointDiscovery != nil {
+		se.endpointDiscovery.shutdown()
+	}
+
+	var errs error
+	if se.dropAudit != nil {
+		errs = multierr.Append(errs, se.dropAudit.shutdown())
+	}
+	if se.carbonTCP != nil {
+		errs = multierr.Append(errs, se.carbonTCP.shutdown())
+	}
+	if se.usageCounters != nil {
+		errs = multierr.Append(errs, se.usageCounters.shutdown(ctx))
+	}
+	return errs
+}
+
+// trackPush marks the push as in progress and returns its context, which is cancelled
+// when the shutdown deadline is exceeded, along with the function which has to be called
+// with the number of the records dropped by the push when it's done.
+func (se *sumologicexporter) trackPush(ctx context.Context) (context.Context, func(dropped int)) {
+	se.inFlight.Add(1)
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-se.abortCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func(dropped int) {
+		select {
+		case <-se.abortCh:
+			atomic.AddInt64(&se.unflushed, int64(dropped))
+		default:
+		}
+		cancel()
+		se.inFlight.Done()
+	}
+}
+
+// flushInFlight waits until the pushes in progress flush the buffered data,
+// at most until they are aborted on the shutdown timeout or the context is done.
+// Then the number of the records which could not be flushed is reported.
+func (se *sumologicexporter) flushInFlight(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		se.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		se.abort()
+		<-done
+	}
+
+	if unflushed := atomic.LoadInt64(&se.unflushed); unflushed > 0 {
+		se.logger.Warn("Shutdown deadline exceeded, not all records were flushed",
+			zap.Duration("shutdown_timeout", se.config.ShutdownTimeout),
+			zap.Int64("unflushed_records", unflushed),
+		)
+	}
+}
+
+func (se *sumologicexporter) trackedPushLogsData(ctx context.Context, ld pdata.Logs) error {
+	if !se.config.Logs.Enabled {
+		recordDisabledSignalRecords(LogsPipeline, ld.LogRecordCount())
+		return nil
+	}
+
+	ctx, done := se.trackPush(ctx)
+	err := se.pushLogsData(ctx, ld)
+	se.backpressure.onPushed(ctx, LogsPipeline, err)
+
+	dropped := 0
+	var logsErr consumererror.Logs
+	if errors.As(err, &logsErr) {
+		dropped = logsErr.GetLogs().LogRecordCount()
+	} else if err != nil {
+		dropped = ld.LogRecordCount()
+	}
+	done(dropped)
+	se.reportExport(LogsPipeline, err)
+	return err
+}
+
+func (se *sumologicexporter) trackedPushMetricsData(ctx context.Context, md pdata.Metrics) error {
+	if !se.config.Metrics.Enabled {
+		recordDisabledSignalRecords(MetricsPipeline, md.MetricCount())
+		return nil
+	}
+
+	ctx, done := se.trackPush(ctx)
+	err := se.pushMetricsData(ctx, md)
+	se.backpressure.onPushed(ctx, MetricsPipeline, err)
+
+	dropped := 0
+	var metricsErr consumererror.Metrics
+	if errors.As(err, &metricsErr) {
+		dropped = metricsErr.GetMetrics().MetricCount()
+	} else if err != nil {
+		dropped = md.MetricCount()
+	}
+	done(dropped)
+	se.reportExport(MetricsPipeline, err)
+	return err
+}
+
+func (se *sumologicexporter) trackedPushTracesData(ctx context.Context, td pdata.Traces) error {
+	if !se.config.Traces.Enabled {
+		recordDisabledSignalRecords(TracesPipeline, td.SpanCount())
+		return nil
+	}
+
+	ctx, done := se.trackPush(ctx)
+	err := se.pushTracesData(ctx, td)
+	se.backpressure.onPushed(ctx, TracesPipeline, err)
+
+	dropped := 0
+	var tracesErr consumererror.Traces
+	if errors.As(err, &tracesErr) {
+		dropped = tracesErr.GetTraces().SpanCount()
+	} else if err != nil {
+		dropped = td.SpanCount()
+	}
+	done(dropped)
+	se.reportExport(TracesPipeline, err)
+	return err
+}
+
+// reportExport reports the result of the export of the pipeline to the export reporters
+func (se *sumologicexporter) reportExport(pipeline PipelineType, err error) {
+	for _, r := range se.exportReporters {
+		r.ReportExport(se.config.ID(), string(pipeline), err)
+	}
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/model/otlp"
@@ -131,6 +132,22 @@ func TestInitExporter(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestUpdateDataURLs(t *testing.T) {
+	exp, err := initExporter(createTestConfig(), createExporterCreateSettings())
+	require.NoError(t, err)
+
+	exp.updateDataURLs("https://collectors.sumologic.com")
+	logs, metrics, traces := exp.getDataURLs()
+	assert.Equal(t, "https://collectors.sumologic.com/api/v1/collector/logs", logs)
+	assert.Equal(t, "https://collectors.sumologic.com/api/v1/collector/metrics", metrics)
+	assert.Equal(t, "https://collectors.sumologic.com/api/v1/collector/traces", traces)
+
+	// Invalid base URL should not overwrite the previous data URLs.
+	exp.updateDataURLs("https://collectors.sumologic.com/%zz")
+	logs, _, _ = exp.getDataURLs()
+	assert.Equal(t, "https://collectors.sumologic.com/api/v1/collector/logs", logs)
+}
+
 func TestAllSuccess(t *testing.T) {
 	test := prepareExporterTest(t, createTestConfig(), []func(w http.ResponseWriter, req *http.Request){
 		func(w http.ResponseWriter, req *http.Request) {
@@ -146,6 +163,81 @@ func TestAllSuccess(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestPushLogsTruncatesLongRecords(t *testing.T) {
+	test := prepareExporterTest(t, createTestConfig(), []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			assert.Equal(t, "Example log\nAnother ...", body)
+		},
+	}, func(cfg *Config) {
+		cfg.MaxLogRecordSize = 11
+		cfg.LogTruncationMarker = "..."
+	})
+
+	logs := LogRecordsToLogs(exampleTwoLogs())
+
+	err := test.exp.pushLogsData(context.Background(), logs)
+	assert.NoError(t, err)
+	// the exported data is not modified
+	assert.Equal(t, "Another example log",
+		logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).LogRecords().At(1).Body().StringVal(),
+	)
+}
+
+func TestPushTextLogsUnsupportedBody(t *testing.T) {
+	testcases := []struct {
+		name           string
+		handling       TextFormatBodyHandlingType
+		expectedBody   string
+		expectedFields string
+	}{
+		{
+			name:         "json",
+			handling:     TextFormatBodyJSON,
+			expectedBody: "{\"level\":\"info\",\"log\":\"Example log\"}\nAnother example log",
+		},
+		{
+			name:         "drop",
+			handling:     TextFormatBodyDrop,
+			expectedBody: "Another example log",
+		},
+		{
+			name:           "attributes",
+			handling:       TextFormatBodyAttributes,
+			expectedBody:   "Example log",
+			expectedFields: "level=info",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			test := prepareExporterTest(t, createTestConfig(), []func(w http.ResponseWriter, req *http.Request){
+				func(w http.ResponseWriter, req *http.Request) {
+					body := extractBody(t, req)
+					assert.Equal(t, tc.expectedBody, body)
+					assert.Equal(t, tc.expectedFields, req.Header.Get("X-Sumo-Fields"))
+				},
+				func(w http.ResponseWriter, req *http.Request) {
+					body := extractBody(t, req)
+					assert.Equal(t, "Another example log", body)
+				},
+			}, func(cfg *Config) {
+				cfg.MetadataAttributes = []string{"level"}
+				cfg.TextFormatBodyHandling = tc.handling
+			})
+
+			logs := LogRecordsToLogs(exampleTwoLogs())
+			records := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).LogRecords()
+			records.At(0).Attributes().Clear()
+			exampleMapBody().CopyTo(records.At(0).Body())
+			records.At(1).Attributes().Clear()
+
+			err := test.exp.pushLogsData(context.Background(), logs)
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestResourceMerge(t *testing.T) {
 	test := prepareExporterTest(t, createTestConfig(), []func(w http.ResponseWriter, req *http.Request){
 		func(w http.ResponseWriter, req *http.Request) {
@@ -456,6 +548,41 @@ func TestPushOTLPLogs_AttributeTranslation(t *testing.T) {
 	}
 }
 
+func TestPushOTLPLogsGroupsByFields(t *testing.T) {
+	checkRequest := func(expectedFields string, expectedBodies ...string) func(w http.ResponseWriter, req *http.Request) {
+		return func(w http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, expectedFields, req.Header.Get("X-Sumo-Fields"))
+
+			ld, err := otlp.NewProtobufLogsUnmarshaler().UnmarshalLogs([]byte(extractBody(t, req)))
+			require.NoError(t, err)
+			require.Equal(t, len(expectedBodies), ld.LogRecordCount())
+			logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).LogRecords()
+			for i, body := range expectedBodies {
+				assert.Equal(t, body, logs.At(i).Body().StringVal())
+			}
+		}
+	}
+
+	test := prepareExporterTest(t, createTestConfig(), []func(w http.ResponseWriter, req *http.Request){
+		checkRequest("team=a", "first", "third"),
+		checkRequest("team=b", "second"),
+	}, func(cfg *Config) {
+		cfg.LogFormat = OTLPLogFormat
+		cfg.MetadataAttributes = []string{"team"}
+	})
+
+	logs := pdata.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+	for _, r := range []struct{ body, team string }{{"first", "a"}, {"second", "b"}, {"third", "a"}} {
+		log := records.AppendEmpty()
+		log.Body().SetStringVal(r.body)
+		log.Attributes().InsertString("team", r.team)
+	}
+
+	err := test.exp.pushLogsData(context.Background(), logs)
+	assert.NoError(t, err)
+}
+
 func TestPushTextLogs_AttributeTranslation(t *testing.T) {
 	createLogs := func() pdata.Logs {
 		logs := LogRecordsToLogs(exampleLog())
@@ -731,6 +858,7 @@ gauge_metric_name{foo="bar",remote_name="156955",url="http://another_url"} 245 1
 		},
 	})
 	test.exp.config.MetricFormat = PrometheusFormat
+	test.exp.metricFormatter = getTestMetricFormatter(t, test.exp.config)
 
 	metrics := metricPairToMetrics([]metricPair{
 		exampleIntMetric(),
@@ -781,6 +909,7 @@ gauge_metric_name{foo="bar",remote_name="156955",url="http://another_url"} 245 1
 		},
 	})
 	test.exp.config.MetricFormat = PrometheusFormat
+	test.exp.metricFormatter = getTestMetricFormatter(t, test.exp.config)
 
 	metrics := metricPairToMetrics([]metricPair{
 		exampleIntMetric(),
@@ -812,9 +941,12 @@ gauge_metric_name{foo="bar",remote_name="156955",url="http://another_url"} 245 1
 			assert.Equal(t, expected, body)
 			assert.Equal(t, "application/vnd.sumologic.prometheus", req.Header.Get("Content-Type"))
 		},
+	}, func(cfg *Config) {
+		// the request body size limit is set up when the exporter is created
+		cfg.MaxRequestBodySize = 1
 	})
 	test.exp.config.MetricFormat = PrometheusFormat
-	test.exp.config.MaxRequestBodySize = 1
+	test.exp.metricFormatter = getTestMetricFormatter(t, test.exp.config)
 
 	records := []metricPair{
 		exampleIntMetric(),
@@ -870,6 +1002,7 @@ gauge_metric_name{foo="bar",key2="value2",remote_name="156955",url="http://anoth
 		},
 	})
 	test.exp.config.MetricFormat = PrometheusFormat
+	test.exp.metricFormatter = getTestMetricFormatter(t, test.exp.config)
 	test.exp.config.MaxRequestBodySize = 1
 
 	f, err := newFilter([]string{`key\d`})
@@ -918,6 +1051,7 @@ func TestPushMetricsFailedBatch(t *testing.T) {
 		},
 	})
 	test.exp.config.MetricFormat = PrometheusFormat
+	test.exp.metricFormatter = getTestMetricFormatter(t, test.exp.config)
 	test.exp.config.MaxRequestBodySize = 1024 * 1024 * 1024 * 1024
 
 	metrics := metricPairToMetrics([]metricPair{exampleIntMetric()})
@@ -1146,6 +1280,7 @@ func TestMetricsCarbon2FormatMetadataFilter(t *testing.T) {
 		},
 	})
 	test.exp.config.MetricFormat = Carbon2Format
+	test.exp.metricFormatter = getTestMetricFormatter(t, test.exp.config)
 
 	f, err := newFilter([]string{`key1`})
 	require.NoError(t, err)
@@ -1174,9 +1309,8 @@ func TestMetricsGraphiteFormatMetadataFilter(t *testing.T) {
 		},
 	})
 	test.exp.config.MetricFormat = GraphiteFormat
-	graphiteFormatter, err := newGraphiteFormatter("%{_metric_}.%{test}.%{test2}.%{key1}.%{key2}")
-	assert.NoError(t, err)
-	test.exp.graphiteFormatter = graphiteFormatter
+	test.exp.config.GraphiteTemplate = "%{_metric_}.%{test}.%{test2}.%{key1}.%{key2}"
+	test.exp.metricFormatter = getTestMetricFormatter(t, test.exp.config)
 
 	f, err := newFilter([]string{`key1`})
 	require.NoError(t, err)
@@ -1205,6 +1339,7 @@ func TestMetricsPrometheusFormatMetadataFilter(t *testing.T) {
 		},
 	})
 	test.exp.config.MetricFormat = PrometheusFormat
+	test.exp.metricFormatter = getTestMetricFormatter(t, test.exp.config)
 
 	f, err := newFilter([]string{`key1`})
 	require.NoError(t, err)
@@ -1627,3 +1762,204 @@ dummy_metric{test="test_value"} 10 1605534165002`,
 		})
 	}
 }
+
+func TestShutdownWaitsForInFlightPush(t *testing.T) {
+	started := make(chan struct{})
+	test := prepareExporterTest(t, createTestConfig(), []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			close(started)
+			time.Sleep(100 * time.Millisecond)
+		},
+	})
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- test.exp.trackedPushLogsData(context.Background(), LogRecordsToLogs(exampleTwoLogs()))
+	}()
+	<-started
+
+	require.NoError(t, test.exp.shutdown(context.Background()))
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	default:
+		assert.Fail(t, "shutdown returned before the push was done")
+	}
+	assert.EqualValues(t, 0, atomic.LoadInt64(&test.exp.unflushed))
+}
+
+func TestShutdownAbortsInFlightPushAfterTimeout(t *testing.T) {
+	started := make(chan struct{})
+	test := prepareExporterTest(t, createTestConfig(), []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			close(started)
+			select {
+			case <-req.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+		},
+	}, func(cfg *Config) {
+		cfg.ShutdownTimeout = 100 * time.Millisecond
+	})
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- test.exp.trackedPushLogsData(context.Background(), LogRecordsToLogs(exampleTwoLogs()))
+	}()
+	<-started
+
+	require.NoError(t, test.exp.shutdownWithTimeout(context.Background(), test.exp.shutdown))
+	assert.Error(t, <-errCh)
+	assert.EqualValues(t, 2, atomic.LoadInt64(&test.exp.unflushed))
+}
+
+func TestShutdownTimeoutWithSendingQueue(t *testing.T) {
+	started := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		close(started)
+		select {
+		case <-req.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := createTestConfig()
+	cfg.HTTPClientSettings.Endpoint = srv.URL
+	cfg.HTTPClientSettings.Auth = nil
+	cfg.QueueSettings.Enabled = true
+	cfg.QueueSettings.NumConsumers = 1
+	cfg.ShutdownTimeout = 100 * time.Millisecond
+
+	exp, err := NewFactory().CreateLogsExporter(context.Background(), componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, exp.ConsumeLogs(context.Background(), LogRecordsToLogs(exampleTwoLogs())))
+	<-started
+
+	start := time.Now()
+	require.NoError(t, exp.Shutdown(context.Background()))
+	assert.Less(t, time.Since(start), 5*time.Second, "shutdown didn't abort the push in progress")
+}
+
+type exportReport struct {
+	exporter config.ComponentID
+	pipeline string
+	err      error
+}
+
+type testExportReporter struct {
+	reports []exportReport
+}
+
+func (r *testExportReporter) Start(context.Context, component.Host) error { return nil }
+
+func (r *testExportReporter) Shutdown(context.Context) error { return nil }
+
+func (r *testExportReporter) ReportExport(exporter config.ComponentID, pipeline string, err error) {
+	r.reports = append(r.reports, exportReport{exporter: exporter, pipeline: pipeline, err: err})
+}
+
+func TestPushReportsExports(t *testing.T) {
+	test := prepareExporterTest(t, createTestConfig(), []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {},
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(500)
+		},
+	})
+
+	reporter := &testExportReporter{}
+	host := storageTestHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[config.ComponentID]component.Extension{config.NewComponentID("sumologic_health_check"): reporter},
+	}
+	require.NoError(t, test.exp.start(context.Background(), host))
+
+	assert.NoError(t, test.exp.trackedPushLogsData(context.Background(), LogRecordsToLogs(exampleLog())))
+	assert.Error(t, test.exp.trackedPushLogsData(context.Background(), LogRecordsToLogs(exampleLog())))
+
+	require.Len(t, reporter.reports, 2)
+	assert.Equal(t, test.exp.config.ID(), reporter.reports[0].exporter)
+	assert.Equal(t, "logs", reporter.reports[0].pipeline)
+	assert.NoError(t, reporter.reports[0].err)
+	assert.Equal(t, "logs", reporter.reports[1].pipeline)
+	assert.Error(t, reporter.reports[1].err)
+}
+
+func TestPushDisabledSignal(t *testing.T) {
+	var requests int32
+	test := prepareExporterTest(t, createTestConfig(), []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			assert.Equal(t, "application/vnd.sumologic.carbon2", req.Header.Get("Content-Type"))
+		},
+	}, func(c *Config) {
+		c.Logs.Enabled = false
+	})
+
+	assert.NoError(t, test.exp.trackedPushLogsData(context.Background(), LogRecordsToLogs(exampleLog())))
+	assert.NoError(t, test.exp.trackedPushMetricsData(context.Background(), metricPairToMetrics([]metricPair{exampleIntMetric()})))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&requests))
+}
+
+// canceledAfterRequestsContext reports the context as canceled once the server received
+// the given number of requests, its Done channel is never closed, so the request
+// in progress is not aborted and the test is deterministic
+type canceledAfterRequestsContext struct {
+	context.Context
+	requests *int32
+	after    int32
+}
+
+func (c canceledAfterRequestsContext) Err() error {
+	if atomic.LoadInt32(c.requests) >= c.after {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestPushLogsCanceledReturnsRemainingRecords(t *testing.T) {
+	var requests int32
+	test := prepareExporterTest(t, createTestConfig(), []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, "first", extractBody(t, req))
+			atomic.AddInt32(&requests, 1)
+		},
+	}, func(cfg *Config) {
+		// every record is sent in a separate request
+		cfg.MaxRequestBodySize = 1
+	})
+
+	logs := pdata.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+	for _, body := range []string{"first", "second", "third"} {
+		records.AppendEmpty().Body().SetStringVal(body)
+	}
+
+	ctx := canceledAfterRequestsContext{Context: context.Background(), requests: &requests, after: 1}
+	err := test.exp.pushLogsData(ctx, logs)
+	require.ErrorIs(t, err, context.Canceled)
+
+	var logsErr consumererror.Logs
+	require.ErrorAs(t, err, &logsErr)
+	dropped := logsErr.GetLogs().ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).LogRecords()
+	require.Equal(t, 2, dropped.Len())
+	assert.Equal(t, "second", dropped.At(0).Body().StringVal())
+	assert.Equal(t, "third", dropped.At(1).Body().StringVal())
+}
+
+func TestPushMetricsCanceledReturnsAllRecords(t *testing.T) {
+	test := prepareExporterTest(t, createTestConfig(), []func(w http.ResponseWriter, req *http.Request){})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	metrics := metricPairToMetrics([]metricPair{exampleIntMetric(), exampleIntGaugeMetric()})
+	err := test.exp.pushMetricsData(ctx, metrics)
+	require.ErrorIs(t, err, context.Canceled)
+
+	var metricsErr consumererror.Metrics
+	require.ErrorAs(t, err, &metricsErr)
+	assert.Equal(t, 2, metricsErr.GetMetrics().MetricCount())
+}
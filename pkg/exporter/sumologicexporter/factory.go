@@ -46,25 +46,92 @@ func createDefaultConfig() config.Exporter {
 	return &Config{
 		ExporterSettings: config.NewExporterSettings(config.NewComponentID(typeStr)),
 
-		TranslateAttributes:      DefaultTranslateAttributes,
-		TranslateTelegrafMetrics: DefaultTranslateTelegrafMetrics,
-		CompressEncoding:         DefaultCompressEncoding,
-		MaxRequestBodySize:       DefaultMaxRequestBodySize,
-		LogFormat:                DefaultLogFormat,
-		MetricFormat:             DefaultMetricFormat,
-		SourceCategory:           DefaultSourceCategory,
-		SourceName:               DefaultSourceName,
-		SourceHost:               DefaultSourceHost,
-		Client:                   DefaultClient,
-		ClearLogsTimestamp:       DefaultClearLogsTimestamp,
+		TranslateAttributes:         DefaultTranslateAttributes,
+		TranslateTelegrafMetrics:    DefaultTranslateTelegrafMetrics,
+		CompressEncoding:            DefaultCompressEncoding,
+		MaxRequestBodySize:          DefaultMaxRequestBodySize,
+		LogFormat:                   DefaultLogFormat,
+		TextLogTemplate:             DefaultTextLogTemplate,
+		TextFormatBodyHandling:      DefaultTextFormatBodyHandling,
+		MaxLogRecordSize:            DefaultMaxLogRecordSize,
+		LogTruncationMarker:         DefaultLogTruncationMarker,
+		MetricFormat:                DefaultMetricFormat,
+		MetricFormatWorkers:         DefaultMetricFormatWorkers,
+		SourceCategory:              DefaultSourceCategory,
+		SourceCategoryPrefix:        DefaultSourceCategoryPrefix,
+		SourceCategoryReplaceDash:   DefaultSourceCategoryReplaceDash,
+		SourceName:                  DefaultSourceName,
+		SourceHost:                  DefaultSourceHost,
+		AddSourceResourceAttributes: DefaultAddSourceResourceAttributes,
+		Client:                      DefaultClient,
+		ClearLogsTimestamp:          DefaultClearLogsTimestamp,
+		SourceHostFallback: SourceHostFallbackConfig{
+			RefreshInterval: DefaultSourceHostFallbackRefreshInterval,
+		},
 		JSONLogs: JSONLogs{
-			LogKey:       DefaultLogKey,
-			AddTimestamp: DefaultAddTimestamp,
-			TimestampKey: DefaultTimestampKey,
-			FlattenBody:  DefaultFlattenBody,
+			LogKey:        DefaultLogKey,
+			AddTimestamp:  DefaultAddTimestamp,
+			TimestampKey:  DefaultTimestampKey,
+			FlattenBody:   DefaultFlattenBody,
+			MaxAttributes: DefaultMaxAttributes,
+			PreserveTypes: DefaultPreserveTypes,
+		},
+		PayloadSampling: PayloadSamplingConfig{
+			SampleRate:  DefaultPayloadSamplingRate,
+			MaxFiles:    DefaultPayloadSamplingMaxFiles,
+			MaxFileSize: DefaultPayloadSamplingMaxFileSize,
+		},
+		UsageCounters: UsageCountersConfig{
+			RetentionDays: DefaultUsageCountersRetentionDays,
+			FlushInterval: DefaultUsageCountersFlushInterval,
+		},
+		CircuitBreaker: CircuitBreakerConfig{
+			FailureThreshold: DefaultCircuitBreakerFailureThreshold,
+			ProbeInterval:    DefaultCircuitBreakerProbeInterval,
+		},
+		RetryBudget: RetryBudgetConfig{
+			MaxConcurrentRetries: DefaultRetryBudgetMaxConcurrentRetries,
+			MaxRetryBytes:        DefaultRetryBudgetMaxRetryBytes,
+		},
+		DropAudit: DropAuditConfig{
+			Interval: DefaultDropAuditInterval,
+		},
+		GraphiteTCP: GraphiteTCPConfig{
+			Timeout:                  DefaultGraphiteTCPTimeout,
+			InitialReconnectInterval: DefaultGraphiteTCPInitialReconnectInterval,
+			MaxReconnectInterval:     DefaultGraphiteTCPMaxReconnectInterval,
+		},
+		Backpressure: BackpressureConfig{
+			FailureDuration: DefaultBackpressureFailureDuration,
+		},
+		Throttling: ThrottlingConfig{
+			DefaultPeriod: DefaultThrottlingDefaultPeriod,
+			MaxPeriod:     DefaultThrottlingMaxPeriod,
+		},
+		AdaptiveBatching: AdaptiveBatchingConfig{
+			TargetPayloadSize: DefaultAdaptiveBatchingTargetPayloadSize,
+			WindowSize:        DefaultAdaptiveBatchingWindowSize,
+		},
+		PayloadSizeEstimation: PayloadSizeEstimationConfig{
+			InitialCompressionRatio: DefaultPayloadSizeEstimationInitialCompressionRatio,
+		},
+		TracesRetry: TracesRetryConfig{
+			InitialInterval:     DefaultTracesRetryInitialInterval,
+			MaxInterval:         DefaultTracesRetryMaxInterval,
+			MaxElapsedTime:      DefaultTracesRetryMaxElapsedTime,
+			RandomizationFactor: DefaultTracesRetryRandomizationFactor,
+		},
+		EndpointDiscovery: EndpointDiscoveryConfig{
+			RefreshInterval: DefaultEndpointDiscoveryRefreshInterval,
 		},
-		GraphiteTemplate: DefaultGraphiteTemplate,
-		TraceFormat:      OTLPTraceFormat,
+		GraphiteTemplate:      DefaultGraphiteTemplate,
+		TraceFormat:           OTLPTraceFormat,
+		PropagateTraceContext: DefaultPropagateTraceContext,
+		ShutdownTimeout:       DefaultShutdownTimeout,
+		DryRun:                DefaultDryRun,
+		Logs:                  SignalConfig{Enabled: DefaultSignalEnabled},
+		Metrics:               SignalConfig{Enabled: DefaultSignalEnabled},
+		Traces:                SignalConfig{Enabled: DefaultSignalEnabled},
 
 		HTTPClientSettings: CreateDefaultHTTPClientSettings(),
 		RetrySettings:      exporterhelper.NewDefaultRetrySettings(),
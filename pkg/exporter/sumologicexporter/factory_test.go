@@ -38,25 +38,86 @@ func TestCreateDefaultConfig(t *testing.T) {
 	qs.Enabled = false
 
 	assert.Equal(t, cfg, &Config{
-		ExporterSettings:   config.NewExporterSettings(config.NewComponentID(typeStr)),
-		CompressEncoding:   "gzip",
-		MaxRequestBodySize: 1_048_576,
-		LogFormat:          "otlp",
-		MetricFormat:       "otlp",
-		SourceCategory:     "",
-		SourceName:         "",
-		SourceHost:         "",
-		Client:             "otelcol",
-		ClearLogsTimestamp: true,
+		ExporterSettings:            config.NewExporterSettings(config.NewComponentID(typeStr)),
+		CompressEncoding:            "gzip",
+		MaxRequestBodySize:          1_048_576,
+		LogFormat:                   "otlp",
+		LogTruncationMarker:         "...[truncated]",
+		TextFormatBodyHandling:      "string",
+		MetricFormat:                "otlp",
+		MetricFormatWorkers:         1,
+		SourceCategory:              "",
+		SourceCategoryPrefix:        "",
+		SourceCategoryReplaceDash:   "",
+		SourceName:                  "",
+		SourceHost:                  "",
+		AddSourceResourceAttributes: true,
+		Client:                      "otelcol",
+		ClearLogsTimestamp:          true,
+		SourceHostFallback: SourceHostFallbackConfig{
+			RefreshInterval: time.Hour,
+		},
 		JSONLogs: JSONLogs{
 			LogKey:       "log",
 			AddTimestamp: true,
 			TimestampKey: "timestamp",
 		},
+		PayloadSampling: PayloadSamplingConfig{
+			SampleRate:  100,
+			MaxFiles:    10,
+			MaxFileSize: 1_048_576,
+		},
+		UsageCounters: UsageCountersConfig{
+			RetentionDays: 31,
+			FlushInterval: time.Minute,
+		},
+		CircuitBreaker: CircuitBreakerConfig{
+			FailureThreshold: 5,
+			ProbeInterval:    30 * time.Second,
+		},
+		RetryBudget: RetryBudgetConfig{
+			MaxConcurrentRetries: 4,
+			MaxRetryBytes:        8 * 1024 * 1024,
+		},
+		DropAudit: DropAuditConfig{
+			Interval: time.Minute,
+		},
+		GraphiteTCP: GraphiteTCPConfig{
+			Timeout:                  5 * time.Second,
+			InitialReconnectInterval: time.Second,
+			MaxReconnectInterval:     time.Minute,
+		},
+		Backpressure: BackpressureConfig{
+			FailureDuration: time.Minute,
+		},
+		Throttling: ThrottlingConfig{
+			DefaultPeriod: DefaultThrottlingDefaultPeriod,
+			MaxPeriod:     DefaultThrottlingMaxPeriod,
+		},
+		AdaptiveBatching: AdaptiveBatchingConfig{
+			TargetPayloadSize: 512 * 1024,
+			WindowSize:        100,
+		},
+		PayloadSizeEstimation: PayloadSizeEstimationConfig{
+			InitialCompressionRatio: 1,
+		},
+		TracesRetry: TracesRetryConfig{
+			InitialInterval:     500 * time.Millisecond,
+			MaxInterval:         5 * time.Second,
+			MaxElapsedTime:      30 * time.Second,
+			RandomizationFactor: 0.5,
+		},
+		EndpointDiscovery: EndpointDiscoveryConfig{
+			RefreshInterval: 30 * time.Second,
+		},
 		GraphiteTemplate:         "%{_metric_}",
 		TranslateAttributes:      true,
 		TranslateTelegrafMetrics: true,
 		TraceFormat:              "otlp",
+		ShutdownTimeout:          10 * time.Second,
+		Logs:                     SignalConfig{Enabled: true},
+		Metrics:                  SignalConfig{Enabled: true},
+		Traces:                   SignalConfig{Enabled: true},
 
 		HTTPClientSettings: confighttp.HTTPClientSettings{
 			Timeout: 5 * time.Second,
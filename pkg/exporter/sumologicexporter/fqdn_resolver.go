@@ -0,0 +1,116 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fqdnLookupTimeout limits the time of resolving the FQDN
+const fqdnLookupTimeout = 5 * time.Second
+
+// fqdnResolver resolves the machine's FQDN, which is used as the source host
+// when no source host template is configured. The result is cached
+// for the refresh interval.
+type fqdnResolver struct {
+	logger          *zap.Logger
+	refreshInterval time.Duration
+	hostname        func() (string, error)
+	lookupFQDN      func(ctx context.Context, hostname string) (string, error)
+	now             func() time.Time
+
+	mu          sync.Mutex
+	fqdn        string
+	refreshedAt time.Time
+}
+
+func newFQDNResolver(cfg SourceHostFallbackConfig, logger *zap.Logger) *fqdnResolver {
+	return &fqdnResolver{
+		logger:          logger.Named("source_host_fallback"),
+		refreshInterval: cfg.RefreshInterval,
+		hostname:        os.Hostname,
+		lookupFQDN:      lookupFQDN,
+		now:             time.Now,
+	}
+}
+
+// get returns the cached FQDN, resolving it again when the refresh interval has passed.
+// When the resolution fails, the previously resolved value is kept and if there's none,
+// the OS hostname is returned.
+func (r *fqdnResolver) get() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if r.fqdn != "" && now.Sub(r.refreshedAt) < r.refreshInterval {
+		return r.fqdn
+	}
+	r.refreshedAt = now
+
+	hostname, err := r.hostname()
+	if err != nil {
+		r.logger.Warn("Failed to get the hostname", zap.Error(err))
+		return r.fqdn
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fqdnLookupTimeout)
+	defer cancel()
+
+	fqdn, err := r.lookupFQDN(ctx, hostname)
+	if err != nil {
+		r.logger.Debug("Failed to resolve the FQDN", zap.String("hostname", hostname), zap.Error(err))
+		if r.fqdn != "" {
+			return r.fqdn
+		}
+		fqdn = hostname
+	}
+
+	r.fqdn = fqdn
+	return r.fqdn
+}
+
+// lookupFQDN returns the first fully qualified name the addresses of the hostname resolve to
+func lookupFQDN(ctx context.Context, hostname string) (string, error) {
+	if strings.Contains(hostname, ".") {
+		return hostname, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupHost(ctx, hostname)
+	if err != nil {
+		return "", err
+	}
+
+	for _, addr := range addrs {
+		names, err := net.DefaultResolver.LookupAddr(ctx, addr)
+		if err != nil {
+			continue
+		}
+		for _, name := range names {
+			if name = strings.TrimSuffix(name, "."); strings.Contains(name, ".") {
+				return name, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no fully qualified name found for %s", hostname)
+}
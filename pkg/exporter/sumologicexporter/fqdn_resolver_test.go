@@ -0,0 +1,96 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type testFQDNLookup struct {
+	fqdn  string
+	err   error
+	calls int
+}
+
+func (l *testFQDNLookup) lookup(context.Context, string) (string, error) {
+	l.calls++
+	return l.fqdn, l.err
+}
+
+func newTestFQDNResolver(lookup *testFQDNLookup, now *time.Time) *fqdnResolver {
+	return &fqdnResolver{
+		logger:          zap.NewNop(),
+		refreshInterval: time.Minute,
+		hostname:        func() (string, error) { return "test-host", nil },
+		lookupFQDN:      lookup.lookup,
+		now:             func() time.Time { return *now },
+	}
+}
+
+func TestFQDNResolverCaches(t *testing.T) {
+	now := time.Now()
+	lookup := &testFQDNLookup{fqdn: "test-host.example.com"}
+	r := newTestFQDNResolver(lookup, &now)
+
+	assert.Equal(t, "test-host.example.com", r.get())
+	assert.Equal(t, "test-host.example.com", r.get())
+	assert.Equal(t, 1, lookup.calls)
+
+	lookup.fqdn = "test-host.example.org"
+	now = now.Add(time.Minute)
+	assert.Equal(t, "test-host.example.org", r.get())
+	assert.Equal(t, 2, lookup.calls)
+}
+
+func TestFQDNResolverFailure(t *testing.T) {
+	now := time.Now()
+	lookup := &testFQDNLookup{err: errors.New("no such host")}
+	r := newTestFQDNResolver(lookup, &now)
+
+	// the hostname is used when the FQDN was never resolved
+	assert.Equal(t, "test-host", r.get())
+
+	lookup.fqdn, lookup.err = "test-host.example.com", nil
+	now = now.Add(time.Minute)
+	assert.Equal(t, "test-host.example.com", r.get())
+
+	// the previously resolved FQDN is kept
+	lookup.fqdn, lookup.err = "", errors.New("no such host")
+	now = now.Add(time.Minute)
+	assert.Equal(t, "test-host.example.com", r.get())
+	assert.Equal(t, 3, lookup.calls)
+}
+
+func TestFQDNResolverHostnameFailure(t *testing.T) {
+	now := time.Now()
+	lookup := &testFQDNLookup{fqdn: "test-host.example.com"}
+	r := newTestFQDNResolver(lookup, &now)
+	r.hostname = func() (string, error) { return "", errors.New("no hostname") }
+
+	assert.Equal(t, "", r.get())
+	assert.Equal(t, 0, lookup.calls)
+}
+
+func TestLookupFQDNQualifiedHostname(t *testing.T) {
+	fqdn, err := lookupFQDN(context.Background(), "test-host.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-host.example.com", fqdn)
+}
@@ -126,3 +126,13 @@ func (gf *graphiteFormatter) metric2String(record metricPair) string {
 
 	return strings.Join(nextLines, "\n")
 }
+
+// Format returns the metric as graphite formatted lines
+func (gf *graphiteFormatter) Format(metric pdata.Metric, attributes pdata.AttributeMap) (string, error) {
+	return gf.metric2String(metricPair{metric: metric, attributes: attributes}), nil
+}
+
+// ContentType returns content type of the graphite metrics
+func (gf *graphiteFormatter) ContentType() string {
+	return contentTypeGraphite
+}
@@ -0,0 +1,67 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// idempotencyKey returns the deterministic key of the request, which is the same
+// for every retry of the same batch of data. It's the SHA-256 hash of the pipeline,
+// the Sumo Logic headers (source category, name, host and fields) and the body
+// before compression, so the same data sent with different metadata has different keys.
+func idempotencyKey(pipeline PipelineType, header http.Header, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(pipeline))
+	h.Write([]byte{0})
+
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		if strings.HasPrefix(k, "X-Sumo-") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(strings.Join(header.Values(k), ",")))
+		h.Write([]byte{0})
+	}
+
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// isValidHeaderName returns whether the name is a valid HTTP header name,
+// i.e. a non empty token as defined in RFC 7230
+func isValidHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	header := http.Header{}
+	header.Add(headerCategory, "category")
+	header.Add(headerFields, "key=value")
+	header.Add(headerContentType, contentTypeLogs)
+	key := idempotencyKey(LogsPipeline, header, []byte("Example log"))
+	assert.Len(t, key, 64)
+
+	// headers other than X-Sumo-* don't change the key
+	other := header.Clone()
+	other.Set(headerContentEncoding, contentEncodingGzip)
+	assert.Equal(t, key, idempotencyKey(LogsPipeline, other, []byte("Example log")))
+
+	assert.NotEqual(t, key, idempotencyKey(MetricsPipeline, header, []byte("Example log")))
+	assert.NotEqual(t, key, idempotencyKey(LogsPipeline, header, []byte("Another log")))
+
+	other = header.Clone()
+	other.Set(headerCategory, "another")
+	assert.NotEqual(t, key, idempotencyKey(LogsPipeline, other, []byte("Example log")))
+}
+
+func TestIsValidHeaderName(t *testing.T) {
+	assert.True(t, isValidHeaderName("Idempotency-Key"))
+	assert.True(t, isValidHeaderName("X-Request_ID"))
+	assert.False(t, isValidHeaderName(""))
+	assert.False(t, isValidHeaderName("Idempotency Key"))
+	assert.False(t, isValidHeaderName("Idempotency-Key:"))
+}
+
+func TestSendWithIdempotencyKey(t *testing.T) {
+	var keys []string
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		keys = append(keys, req.Header.Get("Idempotency-Key"))
+	}
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		handler,
+		handler,
+		handler,
+	}, func(cfg *Config) {
+		cfg.IdempotencyKeyHeader = "Idempotency-Key"
+	})
+
+	flds := fieldsFromMap(map[string]string{"key": "value"})
+	for _, body := range []string{"Example log", "Example log", "Another log"} {
+		require.NoError(t, test.s.send(context.Background(), LogsPipeline, strings.NewReader(body), flds))
+	}
+
+	require.Len(t, keys, 3)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+	assert.NotEqual(t, keys[0], keys[2])
+}
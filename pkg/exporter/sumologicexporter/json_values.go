@@ -0,0 +1,72 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"math"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// attributeMapToJSON converts the attribute map to a map which is marshaled
+// to JSON preserving the native types of the values, as opposed to AsRaw
+// which doesn't support nested maps and arrays in arrays.
+func attributeMapToJSON(attrs pdata.AttributeMap) map[string]interface{} {
+	ret := make(map[string]interface{}, attrs.Len())
+	attrs.Range(func(k string, v pdata.AttributeValue) bool {
+		ret[k] = attributeValueToJSON(v)
+		return true
+	})
+	return ret
+}
+
+// attributeValueToJSON converts the attribute value to a value which is marshaled
+// to JSON preserving its native type:
+//   - strings, booleans and integers are kept as they are,
+//   - doubles are kept as they are, except for NaN and infinities which
+//     aren't valid JSON numbers, so they are sent as strings,
+//   - bytes are sent as base64 encoded strings,
+//   - maps and arrays are converted recursively,
+//   - empty values are sent as null.
+func attributeValueToJSON(v pdata.AttributeValue) interface{} {
+	switch v.Type() {
+	case pdata.AttributeValueTypeString:
+		return v.StringVal()
+	case pdata.AttributeValueTypeBool:
+		return v.BoolVal()
+	case pdata.AttributeValueTypeInt:
+		return v.IntVal()
+	case pdata.AttributeValueTypeDouble:
+		d := v.DoubleVal()
+		if math.IsNaN(d) || math.IsInf(d, 0) {
+			return v.AsString()
+		}
+		return d
+	case pdata.AttributeValueTypeBytes:
+		// []byte is marshaled to a base64 encoded string
+		return v.BytesVal()
+	case pdata.AttributeValueTypeMap:
+		return attributeMapToJSON(v.MapVal())
+	case pdata.AttributeValueTypeArray:
+		slice := v.SliceVal()
+		ret := make([]interface{}, 0, slice.Len())
+		for i := 0; i < slice.Len(); i++ {
+			ret = append(ret, attributeValueToJSON(slice.At(i)))
+		}
+		return ret
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestAttributeValueToJSON(t *testing.T) {
+	testcases := []struct {
+		name     string
+		value    func() pdata.AttributeValue
+		expected string
+	}{
+		{
+			name:     "string",
+			value:    func() pdata.AttributeValue { return pdata.NewAttributeValueString("foo") },
+			expected: `"foo"`,
+		},
+		{
+			name:     "int",
+			value:    func() pdata.AttributeValue { return pdata.NewAttributeValueInt(-9007199254740993) },
+			expected: `-9007199254740993`,
+		},
+		{
+			name:     "double",
+			value:    func() pdata.AttributeValue { return pdata.NewAttributeValueDouble(13.5) },
+			expected: `13.5`,
+		},
+		{
+			name:     "double NaN",
+			value:    func() pdata.AttributeValue { return pdata.NewAttributeValueDouble(math.NaN()) },
+			expected: `"NaN"`,
+		},
+		{
+			name:     "double infinity",
+			value:    func() pdata.AttributeValue { return pdata.NewAttributeValueDouble(math.Inf(-1)) },
+			expected: `"-Inf"`,
+		},
+		{
+			name:     "bool",
+			value:    func() pdata.AttributeValue { return pdata.NewAttributeValueBool(false) },
+			expected: `false`,
+		},
+		{
+			name:     "bytes",
+			value:    func() pdata.AttributeValue { return pdata.NewAttributeValueBytes([]byte("foo")) },
+			expected: `"Zm9v"`,
+		},
+		{
+			name:     "empty",
+			value:    pdata.NewAttributeValueEmpty,
+			expected: `null`,
+		},
+		{
+			name: "map",
+			value: func() pdata.AttributeValue {
+				v := pdata.NewAttributeValueMap()
+				v.MapVal().InsertString("a", "b")
+				v.MapVal().InsertInt("c", 1)
+				nested := pdata.NewAttributeValueMap()
+				nested.MapVal().InsertBool("e", true)
+				v.MapVal().Insert("d", nested)
+				return v
+			},
+			expected: `{"a":"b","c":1,"d":{"e":true}}`,
+		},
+		{
+			name: "array",
+			value: func() pdata.AttributeValue {
+				v := pdata.NewAttributeValueArray()
+				v.SliceVal().AppendEmpty().SetDoubleVal(1.5)
+				nestedArr := pdata.NewAttributeValueArray()
+				nestedArr.SliceVal().AppendEmpty().SetStringVal("a")
+				nestedArr.CopyTo(v.SliceVal().AppendEmpty())
+				nestedMap := pdata.NewAttributeValueMap()
+				nestedMap.MapVal().InsertInt("b", 2)
+				nestedMap.CopyTo(v.SliceVal().AppendEmpty())
+				v.SliceVal().AppendEmpty()
+				return v
+			},
+			expected: `[1.5,["a"],{"b":2},null]`,
+		},
+		{
+			name:     "empty array",
+			value:    pdata.NewAttributeValueArray,
+			expected: `[]`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(attributeValueToJSON(tc.value()))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(b))
+		})
+	}
+}
+
+func TestAttributeMapToJSON(t *testing.T) {
+	attrs := pdata.NewAttributeMap()
+	attrs.InsertString("string", "foo")
+	attrs.InsertInt("int", 1)
+	attrs.InsertDouble("double", 1.5)
+	attrs.InsertBool("bool", true)
+
+	b, err := json.Marshal(attributeMapToJSON(attrs))
+	require.NoError(t, err)
+	assert.Equal(t, `{"bool":true,"double":1.5,"int":1,"string":"foo"}`, string(b))
+}
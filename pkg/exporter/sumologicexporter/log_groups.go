@@ -0,0 +1,65 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// logGroup is a set of log records with the same fields
+type logGroup struct {
+	fields  fields
+	records []logPair
+}
+
+// logGroups groups the log records by their fields, in the order the fields were first seen,
+// so the records with the same fields are sent together even if they are not adjacent.
+type logGroups struct {
+	index  map[string]int
+	groups []logGroup
+}
+
+func newLogGroups() *logGroups {
+	return &logGroups{
+		index: make(map[string]int),
+	}
+}
+
+// add adds the log record to the group of its fields
+func (lg *logGroups) add(flds fields, lp logPair) {
+	key := fieldsKey(flds)
+	i, ok := lg.index[key]
+	if !ok {
+		i = len(lg.groups)
+		lg.index[key] = i
+		lg.groups = append(lg.groups, logGroup{fields: flds})
+	}
+	lg.groups[i].records = append(lg.groups[i].records, lp)
+}
+
+// fieldsKey returns the key identifying the fields, unlike fields.string()
+// it includes the source attributes and the types of the values.
+func fieldsKey(flds fields) string {
+	keys := make([]string, 0, flds.orig.Len())
+	flds.orig.Range(func(k string, v pdata.AttributeValue) bool {
+		keys = append(keys, k+"\x00"+v.Type().String()+"\x00"+v.AsString())
+		return true
+	})
+	sort.Strings(keys)
+	return strings.Join(keys, "\x01")
+}
@@ -0,0 +1,53 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestLogGroups(t *testing.T) {
+	newTestFields := func(attrs map[string]pdata.AttributeValue) fields {
+		return newFields(pdata.NewAttributeMapFromMap(attrs))
+	}
+
+	lg := newLogGroups()
+	lg.add(newTestFields(map[string]pdata.AttributeValue{
+		"a": pdata.NewAttributeValueString("1"),
+		"b": pdata.NewAttributeValueString("2"),
+	}), logPair{log: pdata.NewLogRecord()})
+	lg.add(newTestFields(map[string]pdata.AttributeValue{
+		"a": pdata.NewAttributeValueInt(1),
+		"b": pdata.NewAttributeValueString("2"),
+	}), logPair{log: pdata.NewLogRecord()})
+	lg.add(newTestFields(map[string]pdata.AttributeValue{
+		"b": pdata.NewAttributeValueString("2"),
+		"a": pdata.NewAttributeValueString("1"),
+	}), logPair{log: pdata.NewLogRecord()})
+	lg.add(newTestFields(map[string]pdata.AttributeValue{
+		"_sourceCategory": pdata.NewAttributeValueString("category"),
+		"a":               pdata.NewAttributeValueString("1"),
+		"b":               pdata.NewAttributeValueString("2"),
+	}), logPair{log: pdata.NewLogRecord()})
+
+	if assert.Len(t, lg.groups, 3) {
+		assert.Len(t, lg.groups[0].records, 2)
+		assert.Len(t, lg.groups[1].records, 1)
+		assert.Len(t, lg.groups[2].records, 1)
+	}
+}
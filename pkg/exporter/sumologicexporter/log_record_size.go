@@ -0,0 +1,53 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"unicode/utf8"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// truncateLogRecord returns the log record with the string body truncated to maxSize bytes,
+// including the marker, and true if it was truncated.
+// The truncated record is a copy, as the exported data must not be modified.
+func truncateLogRecord(log pdata.LogRecord, maxSize int, marker string) (pdata.LogRecord, bool) {
+	body := log.Body()
+	if maxSize <= 0 || body.Type() != pdata.AttributeValueTypeString || len(body.StringVal()) <= maxSize {
+		return log, false
+	}
+
+	truncated := pdata.NewLogRecord()
+	log.CopyTo(truncated)
+	truncated.Body().SetStringVal(truncateString(body.StringVal(), maxSize, marker))
+	return truncated, true
+}
+
+// truncateString cuts s so that together with the marker it's at most maxSize bytes,
+// without splitting multi-byte characters
+func truncateString(s string, maxSize int, marker string) string {
+	if len(s) <= maxSize {
+		return s
+	}
+
+	cut := maxSize - len(marker)
+	if cut < 0 {
+		cut = 0
+	}
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + marker
+}
@@ -0,0 +1,104 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestTruncateString(t *testing.T) {
+	testcases := []struct {
+		name     string
+		value    string
+		maxSize  int
+		marker   string
+		expected string
+	}{
+		{
+			name:     "shorter",
+			value:    "short",
+			maxSize:  10,
+			marker:   "...",
+			expected: "short",
+		},
+		{
+			name:     "exact",
+			value:    "0123456789",
+			maxSize:  10,
+			marker:   "...",
+			expected: "0123456789",
+		},
+		{
+			name:     "longer",
+			value:    "0123456789abc",
+			maxSize:  10,
+			marker:   "...",
+			expected: "0123456...",
+		},
+		{
+			name:     "without marker",
+			value:    "0123456789abc",
+			maxSize:  10,
+			expected: "0123456789",
+		},
+		{
+			name:     "multi-byte character",
+			value:    "abcdżółw",
+			maxSize:  8,
+			marker:   "...",
+			expected: "abcd...",
+		},
+		{
+			name:     "marker longer than limit",
+			value:    "0123456789",
+			maxSize:  2,
+			marker:   "...",
+			expected: "...",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, truncateString(tc.value, tc.maxSize, tc.marker))
+		})
+	}
+}
+
+func TestTruncateLogRecord(t *testing.T) {
+	log := pdata.NewLogRecord()
+	log.Body().SetStringVal("0123456789abc")
+	log.Attributes().InsertString("key", "value")
+
+	truncated, ok := truncateLogRecord(log, 10, "...")
+	assert.True(t, ok)
+	assert.Equal(t, "0123456...", truncated.Body().StringVal())
+	assert.Equal(t, map[string]interface{}{"key": "value"}, truncated.Attributes().AsRaw())
+	// the original record is not modified
+	assert.Equal(t, "0123456789abc", log.Body().StringVal())
+
+	_, ok = truncateLogRecord(log, 0, "...")
+	assert.False(t, ok, "truncation is disabled")
+
+	_, ok = truncateLogRecord(log, 20, "...")
+	assert.False(t, ok, "body is not too long")
+
+	mapBody := pdata.NewLogRecord()
+	pdata.NewAttributeValueMap().CopyTo(mapBody.Body())
+	_, ok = truncateLogRecord(mapBody, 1, "...")
+	assert.False(t, ok, "only string bodies are truncated")
+}
@@ -0,0 +1,218 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// metricFilter drops the metrics and data points matching the metric_filters configuration
+type metricFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+	labels  []labelMatcher
+}
+
+// labelMatcher matches the data points with the label value matching the regex
+type labelMatcher struct {
+	name  string
+	value *regexp.Regexp
+}
+
+// newMetricFilter returns nil when no filters are configured
+func newMetricFilter(cfg MetricFiltersConfig) (*metricFilter, error) {
+	if len(cfg.Include) == 0 && len(cfg.Exclude) == 0 && len(cfg.ExcludeLabels) == 0 {
+		return nil, nil
+	}
+
+	include, err := compileRegexes(cfg.Include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+
+	exclude, err := compileRegexes(cfg.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+
+	labels := make([]labelMatcher, 0, len(cfg.ExcludeLabels))
+	for _, l := range cfg.ExcludeLabels {
+		value, err := regexp.Compile(l.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value pattern of label %s: %w", l.Name, err)
+		}
+		labels = append(labels, labelMatcher{name: l.Name, value: value})
+	}
+
+	return &metricFilter{
+		include: include,
+		exclude: exclude,
+		labels:  labels,
+	}, nil
+}
+
+func compileRegexes(patterns []string) ([]*regexp.Regexp, error) {
+	regexes := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		regex, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		regexes = append(regexes, regex)
+	}
+	return regexes, nil
+}
+
+func matchesAny(regexes []*regexp.Regexp, s string) bool {
+	for _, regex := range regexes {
+		if regex.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter returns the records which should be sent, reusing the records' slice.
+// Metrics with some of the data points filtered out are copied,
+// so the incoming data is not modified.
+func (f *metricFilter) filter(records []metricPair) []metricPair {
+	if f == nil {
+		return records
+	}
+
+	filtered := records[:0]
+	for _, record := range records {
+		if !f.keepName(record.metric.Name()) {
+			continue
+		}
+
+		if len(f.labels) > 0 {
+			metric, ok := f.filterDataPoints(record)
+			if !ok {
+				continue
+			}
+			record.metric = metric
+		}
+
+		filtered = append(filtered, record)
+	}
+	return filtered
+}
+
+func (f *metricFilter) keepName(name string) bool {
+	if len(f.include) > 0 && !matchesAny(f.include, name) {
+		return false
+	}
+	return !matchesAny(f.exclude, name)
+}
+
+// filterDataPoints returns the metric without the data points matching the label matchers
+// and false if no data points are left
+func (f *metricFilter) filterDataPoints(record metricPair) (pdata.Metric, bool) {
+	matches := func(dpAttributes pdata.AttributeMap) bool {
+		for _, l := range f.labels {
+			// Data point attributes take precedence over the resource attributes
+			v, ok := dpAttributes.Get(l.name)
+			if !ok {
+				v, ok = record.attributes.Get(l.name)
+			}
+			if ok && l.value.MatchString(v.AsString()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !anyDataPoint(record.metric, matches) {
+		return record.metric, true
+	}
+
+	metric := pdata.NewMetric()
+	record.metric.CopyTo(metric)
+	return metric, removeDataPointsIf(metric, matches) > 0
+}
+
+// anyDataPoint returns true if the attributes of any of the metric's data points match
+func anyDataPoint(m pdata.Metric, matches func(pdata.AttributeMap) bool) bool {
+	switch m.DataType() {
+	case pdata.MetricDataTypeGauge:
+		dps := m.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if matches(dps.At(i).Attributes()) {
+				return true
+			}
+		}
+	case pdata.MetricDataTypeSum:
+		dps := m.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if matches(dps.At(i).Attributes()) {
+				return true
+			}
+		}
+	case pdata.MetricDataTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if matches(dps.At(i).Attributes()) {
+				return true
+			}
+		}
+	case pdata.MetricDataTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if matches(dps.At(i).Attributes()) {
+				return true
+			}
+		}
+	case pdata.MetricDataTypeSummary:
+		dps := m.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			if matches(dps.At(i).Attributes()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// removeDataPointsIf removes the data points with matching attributes
+// and returns the number of the remaining data points
+func removeDataPointsIf(m pdata.Metric, matches func(pdata.AttributeMap) bool) int {
+	switch m.DataType() {
+	case pdata.MetricDataTypeGauge:
+		dps := m.Gauge().DataPoints()
+		dps.RemoveIf(func(dp pdata.NumberDataPoint) bool { return matches(dp.Attributes()) })
+		return dps.Len()
+	case pdata.MetricDataTypeSum:
+		dps := m.Sum().DataPoints()
+		dps.RemoveIf(func(dp pdata.NumberDataPoint) bool { return matches(dp.Attributes()) })
+		return dps.Len()
+	case pdata.MetricDataTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		dps.RemoveIf(func(dp pdata.HistogramDataPoint) bool { return matches(dp.Attributes()) })
+		return dps.Len()
+	case pdata.MetricDataTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		dps.RemoveIf(func(dp pdata.ExponentialHistogramDataPoint) bool { return matches(dp.Attributes()) })
+		return dps.Len()
+	case pdata.MetricDataTypeSummary:
+		dps := m.Summary().DataPoints()
+		dps.RemoveIf(func(dp pdata.SummaryDataPoint) bool { return matches(dp.Attributes()) })
+		return dps.Len()
+	}
+	return 0
+}
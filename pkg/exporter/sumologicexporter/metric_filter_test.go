@@ -0,0 +1,135 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestNewMetricFilterEmpty(t *testing.T) {
+	mfl, err := newMetricFilter(MetricFiltersConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, mfl)
+
+	records := []metricPair{exampleIntMetric()}
+	assert.Equal(t, records, mfl.filter(records))
+}
+
+func TestMetricFilterNames(t *testing.T) {
+	testcases := []struct {
+		name     string
+		cfg      MetricFiltersConfig
+		expected []string
+	}{
+		{
+			name:     "include",
+			cfg:      MetricFiltersConfig{Include: []string{"^gauge_"}},
+			expected: []string{"gauge_metric_name"},
+		},
+		{
+			name:     "exclude",
+			cfg:      MetricFiltersConfig{Exclude: []string{"metric"}},
+			expected: []string{},
+		},
+		{
+			name: "include and exclude",
+			cfg: MetricFiltersConfig{
+				Include: []string{"metric"},
+				Exclude: []string{"^gauge_"},
+			},
+			expected: []string{"test.metric.data"},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mfl, err := newMetricFilter(tc.cfg)
+			require.NoError(t, err)
+
+			filtered := mfl.filter([]metricPair{exampleIntMetric(), exampleIntGaugeMetric()})
+			names := make([]string, 0, len(filtered))
+			for _, record := range filtered {
+				names = append(names, record.metric.Name())
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func TestMetricFilterLabels(t *testing.T) {
+	testcases := []struct {
+		name           string
+		labels         []LabelMatcherConfig
+		record         metricPair
+		expectedPoints int
+	}{
+		{
+			name:           "no data points matching",
+			labels:         []LabelMatcherConfig{{Name: "remote_name", Value: "^1$"}},
+			record:         exampleIntGaugeMetric(),
+			expectedPoints: 2,
+		},
+		{
+			name:           "gauge data point matching",
+			labels:         []LabelMatcherConfig{{Name: "url", Value: "another"}},
+			record:         exampleIntGaugeMetric(),
+			expectedPoints: 1,
+		},
+		{
+			name:           "resource attribute matching",
+			labels:         []LabelMatcherConfig{{Name: "foo", Value: "bar"}},
+			record:         exampleIntGaugeMetric(),
+			expectedPoints: 0,
+		},
+		{
+			name:           "histogram data point matching",
+			labels:         []LabelMatcherConfig{{Name: "container", Value: "dolor"}},
+			record:         exampleHistogramMetric(),
+			expectedPoints: 1,
+		},
+		{
+			name:           "summary data point matching",
+			labels:         []LabelMatcherConfig{{Name: "pod_name", Value: "sit"}},
+			record:         exampleSummaryMetric(),
+			expectedPoints: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mfl, err := newMetricFilter(MetricFiltersConfig{ExcludeLabels: tc.labels})
+			require.NoError(t, err)
+
+			original := pdata.NewMetric()
+			tc.record.metric.CopyTo(original)
+
+			filtered := mfl.filter([]metricPair{tc.record})
+			if tc.expectedPoints == 0 {
+				assert.Empty(t, filtered)
+			} else {
+				require.Len(t, filtered, 1)
+				assert.Equal(t, tc.expectedPoints, removeDataPointsIf(filtered[0].metric, func(pdata.AttributeMap) bool { return false }))
+			}
+			// the incoming metric is not modified
+			assert.Equal(t, original, tc.record.metric)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"sync"
+)
+
+// minMetricsPerFormatWorker is the minimum number of metrics formatted by a single worker,
+// so small batches aren't split across goroutines which costs more than it saves
+const minMetricsPerFormatWorker = 64
+
+// formattedMetric is the result of formatting a single metric record
+type formattedMetric struct {
+	line string
+	err  error
+}
+
+// formatMetrics formats the records with up to workers goroutines, each formatting
+// a contiguous range of the records. The results are stored at the indexes of the records,
+// so they are assembled in the original order regardless of which worker finishes first.
+func formatMetrics(formatter MetricFormatter, records []metricPair, workers int) []formattedMetric {
+	results := make([]formattedMetric, len(records))
+	formatRange := func(from, to int) {
+		for i := from; i < to; i++ {
+			results[i].line, results[i].err = formatter.Format(records[i].metric, records[i].attributes)
+		}
+	}
+
+	if maxWorkers := len(records) / minMetricsPerFormatWorker; workers > maxWorkers {
+		workers = maxWorkers
+	}
+	if workers <= 1 {
+		formatRange(0, len(records))
+		return results
+	}
+
+	chunk := (len(records) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for from := 0; from < len(records); from += chunk {
+		to := from + chunk
+		if to > len(records) {
+			to = len(records)
+		}
+
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			formatRange(from, to)
+		}(from, to)
+	}
+	wg.Wait()
+
+	return results
+}
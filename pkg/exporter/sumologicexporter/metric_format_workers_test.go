@@ -0,0 +1,94 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func exampleMetricRecords(count int) []metricPair {
+	records := make([]metricPair, 0, count)
+	for i := 0; i < count; i++ {
+		record := exampleIntGaugeMetric()
+		record.metric.SetName(fmt.Sprintf("gauge_metric_%d", i))
+		records = append(records, record)
+	}
+	return records
+}
+
+// failingFormatter fails to format the metrics with the given name
+type failingFormatter struct {
+	MetricFormatter
+	name string
+}
+
+func (f failingFormatter) Format(metric pdata.Metric, attributes pdata.AttributeMap) (string, error) {
+	if metric.Name() == f.name {
+		return "", errors.New("failed formatting")
+	}
+	return f.MetricFormatter.Format(metric, attributes)
+}
+
+func TestFormatMetricsKeepsOrder(t *testing.T) {
+	records := exampleMetricRecords(1000)
+	formatter := failingFormatter{MetricFormatter: carbon2Formatter{}, name: "gauge_metric_500"}
+
+	sequential := formatMetrics(formatter, records, 1)
+	require.Len(t, sequential, len(records))
+
+	for _, workers := range []int{2, 3, 8, 100} {
+		assert.Equal(t, sequential, formatMetrics(formatter, records, workers), "workers: %d", workers)
+	}
+
+	assert.Error(t, sequential[500].err)
+	assert.Contains(t, sequential[501].line, "metric=gauge_metric_501")
+}
+
+func TestFormatMetricsSmallBatch(t *testing.T) {
+	records := exampleMetricRecords(3)
+	formatted := formatMetrics(carbon2Formatter{}, records, 8)
+	require.Len(t, formatted, 3)
+	for i, f := range formatted {
+		assert.NoError(t, f.err)
+		assert.Contains(t, f.line, fmt.Sprintf("metric=gauge_metric_%d", i))
+	}
+}
+
+func BenchmarkFormatMetrics(b *testing.B) {
+	records := exampleMetricRecords(10_000)
+
+	pf, err := newPrometheusFormatter()
+	require.NoError(b, err)
+	formatters := map[MetricFormatType]MetricFormatter{
+		PrometheusFormat: &pf,
+		Carbon2Format:    carbon2Formatter{},
+	}
+
+	for format, formatter := range formatters {
+		for _, workers := range []int{1, 2, 4, 8} {
+			b.Run(fmt.Sprintf("%s/workers_%d", format, workers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					formatMetrics(formatter, records, workers)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// MetricFormatter converts metrics to the text format sent to the Sumo Logic.
+// Format is called concurrently, e.g. with metric_format_workers.
+type MetricFormatter interface {
+	// Format returns the metric with the given attributes as newline separated lines.
+	// Data points which cannot be represented in the format should be skipped.
+	Format(metric pdata.Metric, attributes pdata.AttributeMap) (string, error)
+	// ContentType returns value of the Content-Type header for the formatted metrics
+	ContentType() string
+}
+
+// MetricFormatterFactory creates the formatter basing on the exporter configuration
+type MetricFormatterFactory func(cfg *Config) (MetricFormatter, error)
+
+var (
+	metricFormattersLock sync.RWMutex
+	metricFormatters     = map[MetricFormatType]MetricFormatterFactory{}
+)
+
+func init() {
+	RegisterMetricFormatter(PrometheusFormat, func(cfg *Config) (MetricFormatter, error) {
+		pf, err := newPrometheusFormatter()
+		if err != nil {
+			return nil, err
+		}
+		pf.createdSeries = cfg.PrometheusCreatedSeries
+		return &pf, nil
+	})
+	RegisterMetricFormatter(Carbon2Format, func(*Config) (MetricFormatter, error) {
+		return carbon2Formatter{}, nil
+	})
+	RegisterMetricFormatter(GraphiteFormat, func(cfg *Config) (MetricFormatter, error) {
+		gf, err := newGraphiteFormatter(cfg.GraphiteTemplate)
+		if err != nil {
+			return nil, err
+		}
+		return &gf, nil
+	})
+}
+
+// RegisterMetricFormatter makes the formatter available as `metric_format: <format>`.
+// It's meant to be called from init() of the package providing the format
+// and panics if the format is already registered.
+func RegisterMetricFormatter(format MetricFormatType, factory MetricFormatterFactory) {
+	metricFormattersLock.Lock()
+	defer metricFormattersLock.Unlock()
+
+	if format == OTLPMetricFormat {
+		panic("sumologicexporter: otlp metric format cannot be overridden")
+	}
+	if factory == nil {
+		panic(fmt.Sprintf("sumologicexporter: nil factory for metric format %s", format))
+	}
+	if _, ok := metricFormatters[format]; ok {
+		panic(fmt.Sprintf("sumologicexporter: metric format %s is already registered", format))
+	}
+	metricFormatters[format] = factory
+}
+
+func isMetricFormatRegistered(format MetricFormatType) bool {
+	metricFormattersLock.RLock()
+	defer metricFormattersLock.RUnlock()
+
+	_, ok := metricFormatters[format]
+	return ok
+}
+
+// newMetricFormatter creates the formatter for configured metric format.
+// It returns nil for the otlp format, as it's not text based.
+func newMetricFormatter(cfg *Config) (MetricFormatter, error) {
+	if cfg.MetricFormat == OTLPMetricFormat {
+		return nil, nil
+	}
+
+	metricFormattersLock.RLock()
+	factory, ok := metricFormatters[cfg.MetricFormat]
+	metricFormattersLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unexpected metric format: %s", cfg.MetricFormat)
+	}
+
+	return factory(cfg)
+}
@@ -0,0 +1,115 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const testLineProtocolFormat MetricFormatType = "test_line_protocol"
+
+// testLineProtocolFormatter is a minimal formatter used to verify
+// that the formats registered outside of the exporter are supported
+type testLineProtocolFormatter struct{}
+
+func (testLineProtocolFormatter) Format(metric pdata.Metric, attributes pdata.AttributeMap) (string, error) {
+	if metric.DataType() != pdata.MetricDataTypeGauge {
+		return "", fmt.Errorf("unsupported metric type: %s", metric.DataType())
+	}
+	dp := metric.Gauge().DataPoints().At(0)
+	return fmt.Sprintf("%s,attributes=%d value=%d %d", metric.Name(), attributes.Len(), dp.IntVal(), dp.Timestamp()), nil
+}
+
+func (testLineProtocolFormatter) ContentType() string {
+	return "text/plain"
+}
+
+func init() {
+	RegisterMetricFormatter(testLineProtocolFormat, func(*Config) (MetricFormatter, error) {
+		return testLineProtocolFormatter{}, nil
+	})
+}
+
+func getTestMetricFormatter(t *testing.T, cfg *Config) MetricFormatter {
+	mf, err := newMetricFormatter(cfg)
+	require.NoError(t, err)
+	return mf
+}
+
+func TestNewMetricFormatter(t *testing.T) {
+	testcases := []struct {
+		format   MetricFormatType
+		expected MetricFormatter
+	}{
+		{format: OTLPMetricFormat, expected: nil},
+		{format: Carbon2Format, expected: carbon2Formatter{}},
+		{format: testLineProtocolFormat, expected: testLineProtocolFormatter{}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(string(tc.format), func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.MetricFormat = tc.format
+
+			mf, err := newMetricFormatter(cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, mf)
+		})
+	}
+}
+
+func TestNewMetricFormatterUnknownFormat(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetricFormat = "unknown"
+
+	_, err := newMetricFormatter(cfg)
+	assert.EqualError(t, err, "unexpected metric format: unknown")
+	assert.EqualError(t, cfg.Validate(), "unexpected metric format: unknown")
+}
+
+func TestRegisterMetricFormatterPanics(t *testing.T) {
+	factory := func(*Config) (MetricFormatter, error) {
+		return testLineProtocolFormatter{}, nil
+	}
+
+	assert.Panics(t, func() { RegisterMetricFormatter(PrometheusFormat, factory) })
+	assert.Panics(t, func() { RegisterMetricFormatter(OTLPMetricFormat, factory) })
+	assert.Panics(t, func() { RegisterMetricFormatter("new_format", nil) })
+}
+
+func TestSendMetricsCustomFormat(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			assert.Equal(t, "gauge_metric_name,attributes=1 value=124 1608124661166000000", body)
+			assert.Equal(t, "text/plain", req.Header.Get("Content-Type"))
+		},
+	}, func(cfg *Config) {
+		cfg.MetricFormat = testLineProtocolFormat
+	})
+	require.NoError(t, test.s.config.Validate())
+
+	test.s.metricBuffer = []metricPair{exampleIntGaugeMetric()}
+
+	_, err := test.s.sendMetrics(context.Background(), newFields(pdata.NewAttributeMap()))
+	assert.NoError(t, err)
+}
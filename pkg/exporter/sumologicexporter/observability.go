@@ -0,0 +1,452 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func init() {
+	err := view.Register(
+		viewRequestUncompressedSize,
+		viewRequestCompressedSize,
+		viewRequestHeadersSize,
+		viewRequestCompressionRatio,
+		viewDryRunViolations,
+		viewCircuitBreakerState,
+		viewCircuitBreakerRejectedRequests,
+		viewRetryBudgetRejectedRequests,
+		viewRequestBodySizeLimit,
+		viewBackpressureState,
+		viewBackpressureRejected,
+		viewThrottledState,
+		viewThrottledRequests,
+		viewTruncatedLogRecords,
+		viewUnsupportedBodyLogRecordsDropped,
+		viewRequestRecords,
+		viewDisabledSignalRecords,
+	)
+	if err != nil {
+		fmt.Printf("Error registering sumologic exporter's views: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+var (
+	tagPipelineKey, _  = tag.NewKey("pipeline")
+	tagViolationKey, _ = tag.NewKey("violation")
+
+	mRequestUncompressedSize = stats.Int64(
+		"sumologic_exporter_request_uncompressed_size",
+		"Size of the request body before compression",
+		stats.UnitBytes,
+	)
+	mRequestCompressedSize = stats.Int64(
+		"sumologic_exporter_request_compressed_size",
+		"Size of the request body sent, after compression",
+		stats.UnitBytes,
+	)
+	mRequestHeadersSize = stats.Int64(
+		"sumologic_exporter_request_headers_size",
+		"Size of the request headers",
+		stats.UnitBytes,
+	)
+	mRequestCompressionRatio = stats.Float64(
+		"sumologic_exporter_request_compression_ratio",
+		"Ratio of the request body size before and after compression",
+		stats.UnitDimensionless,
+	)
+	mDryRunViolations = stats.Int64(
+		"sumologic_exporter_dry_run_violations",
+		"Number of violations of Sumo Logic constraints found in the dry run mode",
+		stats.UnitDimensionless,
+	)
+	mCircuitBreakerState = stats.Int64(
+		"sumologic_exporter_circuit_breaker_state",
+		"State of the circuit breaker: 0 - closed, 1 - open, 2 - half-open",
+		stats.UnitDimensionless,
+	)
+	mRequestBodySizeLimit = stats.Int64(
+		"sumologic_exporter_request_body_size_limit",
+		"Effective limit of the request body size, lowered after the requests rejected as too large",
+		stats.UnitBytes,
+	)
+	mCircuitBreakerRejected = stats.Int64(
+		"sumologic_exporter_circuit_breaker_rejected_requests",
+		"Number of requests not sent because the circuit breaker was open",
+		stats.UnitDimensionless,
+	)
+	mRetryBudgetRejected = stats.Int64(
+		"sumologic_exporter_retry_budget_rejected_requests",
+		"Number of requests of the failing pipelines not sent because the retry budget was exhausted",
+		stats.UnitDimensionless,
+	)
+	mBackpressureState = stats.Int64(
+		"sumologic_exporter_backpressure",
+		"Whether the new data is rejected because sending data keeps failing: 0 - accepted, 1 - rejected",
+		stats.UnitDimensionless,
+	)
+	mBackpressureRejected = stats.Int64(
+		"sumologic_exporter_backpressure_rejected_batches",
+		"Number of batches rejected because sending data keeps failing",
+		stats.UnitDimensionless,
+	)
+	mThrottledState = stats.Int64(
+		"sumologic_exporter_throttled",
+		"Whether the backend is throttling the requests of the pipeline with 429 Too Many Requests: 0 - not throttled, 1 - throttled",
+		stats.UnitDimensionless,
+	)
+	mThrottledRequests = stats.Int64(
+		"sumologic_exporter_throttled_requests",
+		"Number of requests rejected by the backend with 429 Too Many Requests",
+		stats.UnitDimensionless,
+	)
+	mTruncatedLogRecords = stats.Int64(
+		"sumologic_exporter_truncated_log_records",
+		"Number of log records with the body truncated to max_log_record_size",
+		stats.UnitDimensionless,
+	)
+	mUnsupportedBodyDropped = stats.Int64(
+		"sumologic_exporter_unsupported_body_log_records_dropped",
+		"Number of log records dropped because their body can't be sent with the text log format",
+		stats.UnitDimensionless,
+	)
+	mRequestRecords = stats.Int64(
+		"sumologic_exporter_request_records",
+		"Number of records (log records, metrics or spans) in the request",
+		stats.UnitDimensionless,
+	)
+	mDisabledSignalRecords = stats.Int64(
+		"sumologic_exporter_disabled_signal_records",
+		"Number of records (log records, metrics or spans) discarded because sending the signal is disabled",
+		stats.UnitDimensionless,
+	)
+	requestSizeDistribution    = view.Distribution(0, 1024, 4096, 16384, 65536, 262144, 524288, 1048576, 2097152, 4194304, 8388608, 16777216)
+	requestHeadersDistribution = view.Distribution(0, 128, 256, 512, 1024, 2048, 4096, 8192)
+)
+
+var viewRequestUncompressedSize = &view.View{
+	Name:        mRequestUncompressedSize.Name(),
+	Description: mRequestUncompressedSize.Description(),
+	Measure:     mRequestUncompressedSize,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: requestSizeDistribution,
+}
+
+var viewRequestCompressedSize = &view.View{
+	Name:        mRequestCompressedSize.Name(),
+	Description: mRequestCompressedSize.Description(),
+	Measure:     mRequestCompressedSize,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: requestSizeDistribution,
+}
+
+var viewRequestHeadersSize = &view.View{
+	Name:        mRequestHeadersSize.Name(),
+	Description: mRequestHeadersSize.Description(),
+	Measure:     mRequestHeadersSize,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: requestHeadersDistribution,
+}
+
+var viewRequestCompressionRatio = &view.View{
+	Name:        mRequestCompressionRatio.Name(),
+	Description: mRequestCompressionRatio.Description(),
+	Measure:     mRequestCompressionRatio,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: view.Distribution(1, 1.5, 2, 3, 4, 5, 7.5, 10, 15, 20, 30, 50),
+}
+
+var viewDryRunViolations = &view.View{
+	Name:        mDryRunViolations.Name(),
+	Description: mDryRunViolations.Description(),
+	Measure:     mDryRunViolations,
+	TagKeys:     []tag.Key{tagPipelineKey, tagViolationKey},
+	Aggregation: view.Sum(),
+}
+
+var viewCircuitBreakerState = &view.View{
+	Name:        mCircuitBreakerState.Name(),
+	Description: mCircuitBreakerState.Description(),
+	Measure:     mCircuitBreakerState,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: view.LastValue(),
+}
+
+var viewCircuitBreakerRejectedRequests = &view.View{
+	Name:        mCircuitBreakerRejected.Name(),
+	Description: mCircuitBreakerRejected.Description(),
+	Measure:     mCircuitBreakerRejected,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: view.Sum(),
+}
+
+var viewRetryBudgetRejectedRequests = &view.View{
+	Name:        mRetryBudgetRejected.Name(),
+	Description: mRetryBudgetRejected.Description(),
+	Measure:     mRetryBudgetRejected,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: view.Sum(),
+}
+
+var viewRequestBodySizeLimit = &view.View{
+	Name:        mRequestBodySizeLimit.Name(),
+	Description: mRequestBodySizeLimit.Description(),
+	Measure:     mRequestBodySizeLimit,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: view.LastValue(),
+}
+
+var viewBackpressureState = &view.View{
+	Name:        mBackpressureState.Name(),
+	Description: mBackpressureState.Description(),
+	Measure:     mBackpressureState,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: view.LastValue(),
+}
+
+var viewBackpressureRejected = &view.View{
+	Name:        mBackpressureRejected.Name(),
+	Description: mBackpressureRejected.Description(),
+	Measure:     mBackpressureRejected,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: view.Sum(),
+}
+
+var viewThrottledState = &view.View{
+	Name:        mThrottledState.Name(),
+	Description: mThrottledState.Description(),
+	Measure:     mThrottledState,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: view.LastValue(),
+}
+
+var viewThrottledRequests = &view.View{
+	Name:        mThrottledRequests.Name(),
+	Description: mThrottledRequests.Description(),
+	Measure:     mThrottledRequests,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: view.Sum(),
+}
+
+var viewTruncatedLogRecords = &view.View{
+	Name:        mTruncatedLogRecords.Name(),
+	Description: mTruncatedLogRecords.Description(),
+	Measure:     mTruncatedLogRecords,
+	Aggregation: view.Sum(),
+}
+
+var viewUnsupportedBodyLogRecordsDropped = &view.View{
+	Name:        mUnsupportedBodyDropped.Name(),
+	Description: mUnsupportedBodyDropped.Description(),
+	Measure:     mUnsupportedBodyDropped,
+	Aggregation: view.Sum(),
+}
+
+var viewRequestRecords = &view.View{
+	Name:        mRequestRecords.Name(),
+	Description: mRequestRecords.Description(),
+	Measure:     mRequestRecords,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: view.Distribution(0, 1, 10, 100, 1000, 10000, 100000),
+}
+
+var viewDisabledSignalRecords = &view.View{
+	Name:        mDisabledSignalRecords.Name(),
+	Description: mDisabledSignalRecords.Description(),
+	Measure:     mDisabledSignalRecords,
+	TagKeys:     []tag.Key{tagPipelineKey},
+	Aggregation: view.Sum(),
+}
+
+// requestSizes holds the sizes of the request sent to the Sumo Logic
+type requestSizes struct {
+	// uncompressed is the size of the body before compression, -1 if it's unknown
+	uncompressed int64
+	// compressed is the size of the body sent, -1 if it's unknown
+	compressed int64
+	headers    int64
+}
+
+// recordRequestSizes records the sizes of the request sent in the given pipeline
+func recordRequestSizes(pipeline PipelineType, sizes requestSizes) {
+	ctx, err := tag.New(context.Background(), tag.Upsert(tagPipelineKey, string(pipeline)))
+	if err != nil {
+		return
+	}
+
+	measurements := []stats.Measurement{mRequestHeadersSize.M(sizes.headers)}
+	if sizes.uncompressed >= 0 {
+		measurements = append(measurements, mRequestUncompressedSize.M(sizes.uncompressed))
+	}
+	if sizes.compressed >= 0 {
+		measurements = append(measurements, mRequestCompressedSize.M(sizes.compressed))
+	}
+	if sizes.uncompressed >= 0 && sizes.compressed > 0 {
+		measurements = append(measurements, mRequestCompressionRatio.M(float64(sizes.uncompressed)/float64(sizes.compressed)))
+	}
+	stats.Record(ctx, measurements...)
+}
+
+// recordViolation records the violation of Sumo Logic constraints found in the given pipeline
+func recordViolation(pipeline PipelineType, kind violationType) {
+	ctx, err := tag.New(context.Background(),
+		tag.Upsert(tagPipelineKey, string(pipeline)),
+		tag.Upsert(tagViolationKey, string(kind)),
+	)
+	if err != nil {
+		return
+	}
+	stats.Record(ctx, mDryRunViolations.M(1))
+}
+
+// recordCircuitBreakerState records the current state of the circuit breaker of the given pipeline
+func recordCircuitBreakerState(pipeline PipelineType, state circuitBreakerState) {
+	ctx, err := tag.New(context.Background(), tag.Upsert(tagPipelineKey, string(pipeline)))
+	if err != nil {
+		return
+	}
+	stats.Record(ctx, mCircuitBreakerState.M(int64(state)))
+}
+
+// recordCircuitBreakerRejection records the request of the given pipeline rejected by the circuit breaker
+func recordCircuitBreakerRejection(pipeline PipelineType) {
+	ctx, err := tag.New(context.Background(), tag.Upsert(tagPipelineKey, string(pipeline)))
+	if err != nil {
+		return
+	}
+	stats.Record(ctx, mCircuitBreakerRejected.M(1))
+}
+
+// recordRetryBudgetRejection records the request of the given pipeline rejected by the retry budget
+func recordRetryBudgetRejection(pipeline PipelineType) {
+	ctx, err := tag.New(context.Background(), tag.Upsert(tagPipelineKey, string(pipeline)))
+	if err != nil {
+		return
+	}
+	stats.Record(ctx, mRetryBudgetRejected.M(1))
+}
+
+// recordRequestBodySizeLimit records the lowered limit of the request body size of the given pipeline
+func recordRequestBodySizeLimit(pipeline PipelineType, limit int) {
+	ctx, err := tag.New(context.Background(), tag.Upsert(tagPipelineKey, string(pipeline)))
+	if err != nil {
+		return
+	}
+	stats.Record(ctx, mRequestBodySizeLimit.M(int64(limit)))
+}
+
+// recordBackpressureState records whether the new data of the given pipeline is rejected
+func recordBackpressureState(pipeline PipelineType, active bool) {
+	ctx, err := tag.New(context.Background(), tag.Upsert(tagPipelineKey, string(pipeline)))
+	if err != nil {
+		return
+	}
+	var state int64
+	if active {
+		state = 1
+	}
+	stats.Record(ctx, mBackpressureState.M(state))
+}
+
+// recordBackpressureRejection records the batch of the given pipeline rejected with ErrBackpressure
+func recordBackpressureRejection(pipeline PipelineType) {
+	ctx, err := tag.New(context.Background(), tag.Upsert(tagPipelineKey, string(pipeline)))
+	if err != nil {
+		return
+	}
+	stats.Record(ctx, mBackpressureRejected.M(1))
+}
+
+// recordThrottledState records whether the backend is throttling the requests of the given pipeline
+func recordThrottledState(pipeline PipelineType, throttled bool) {
+	ctx, err := tag.New(context.Background(), tag.Upsert(tagPipelineKey, string(pipeline)))
+	if err != nil {
+		return
+	}
+	var state int64
+	if throttled {
+		state = 1
+	}
+	stats.Record(ctx, mThrottledState.M(state))
+}
+
+// recordThrottledRequest records the request of the given pipeline rejected with 429 Too Many Requests
+func recordThrottledRequest(pipeline PipelineType) {
+	ctx, err := tag.New(context.Background(), tag.Upsert(tagPipelineKey, string(pipeline)))
+	if err != nil {
+		return
+	}
+	stats.Record(ctx, mThrottledRequests.M(1))
+}
+
+// recordTruncatedLogRecords records the number of log records with the body truncated
+func recordTruncatedLogRecords(count int) {
+	stats.Record(context.Background(), mTruncatedLogRecords.M(int64(count)))
+}
+
+// recordUnsupportedBodyLogRecordsDropped records the number of log records dropped
+// because of their map or array body
+func recordUnsupportedBodyLogRecordsDropped(count int) {
+	stats.Record(context.Background(), mUnsupportedBodyDropped.M(int64(count)))
+}
+
+// recordRequestRecords records the number of records in the request of the given pipeline
+func recordRequestRecords(pipeline PipelineType, records int) {
+	ctx, err := tag.New(context.Background(), tag.Upsert(tagPipelineKey, string(pipeline)))
+	if err != nil {
+		return
+	}
+	stats.Record(ctx, mRequestRecords.M(int64(records)))
+}
+
+// recordDisabledSignalRecords records the number of records discarded because sending the signal is disabled
+func recordDisabledSignalRecords(pipeline PipelineType, records int) {
+	ctx, err := tag.New(context.Background(), tag.Upsert(tagPipelineKey, string(pipeline)))
+	if err != nil {
+		return
+	}
+	stats.Record(ctx, mDisabledSignalRecords.M(int64(records)))
+}
+
+// readerLen returns the number of unread bytes of the reader, or -1 when it's unknown
+func readerLen(r io.Reader) int64 {
+	if cr, ok := r.(*countingReader); ok {
+		return readerLen(cr.r)
+	}
+	if l, ok := r.(interface{ Len() int }); ok {
+		return int64(l.Len())
+	}
+	return -1
+}
+
+// headersSize returns the size of the headers as sent in HTTP/1.1 request, i.e. `Key: Value\r\n` lines
+func headersSize(header http.Header) int64 {
+	var size int64
+	for key, values := range header {
+		for _, value := range values {
+			size += int64(len(key) + len(": ") + len(value) + len("\r\n"))
+		}
+	}
+	return size
+}
@@ -0,0 +1,92 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestReaderLen(t *testing.T) {
+	assert.EqualValues(t, 5, readerLen(strings.NewReader("hello")))
+	assert.EqualValues(t, 3, readerLen(bytes.NewReader([]byte("abc"))))
+	assert.EqualValues(t, 5, readerLen(&countingReader{r: strings.NewReader("hello")}))
+	assert.EqualValues(t, -1, readerLen(&countingReader{r: &countingReader{r: nil}}))
+}
+
+func TestHeadersSize(t *testing.T) {
+	header := http.Header{}
+	header.Add("X-Sumo-Name", "name")
+	header.Add("X-Sumo-Fields", "a=b")
+	header.Add("X-Sumo-Fields", "c=d")
+
+	// "X-Sumo-Name: name\r\n" + 2 * "X-Sumo-Fields: a=b\r\n"
+	assert.EqualValues(t, 19+2*20, headersSize(header))
+}
+
+// distributionData returns the count and the sum of the view's values recorded for the pipeline
+func distributionData(t *testing.T, v *view.View, pipeline PipelineType) (int64, float64) {
+	rows, err := view.RetrieveData(v.Name)
+	require.NoError(t, err)
+
+	for _, row := range rows {
+		for _, tag := range row.Tags {
+			if tag.Key == tagPipelineKey && tag.Value == string(pipeline) {
+				data := row.Data.(*view.DistributionData)
+				return data.Count, data.Mean * float64(data.Count)
+			}
+		}
+	}
+	return 0, 0
+}
+
+func TestSendRecordsRequestSizes(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {},
+	})
+
+	uncompressedCount, uncompressedSum := distributionData(t, viewRequestUncompressedSize, LogsPipeline)
+	compressedCount, compressedSum := distributionData(t, viewRequestCompressedSize, LogsPipeline)
+	headersCount, _ := distributionData(t, viewRequestHeadersSize, LogsPipeline)
+	ratioCount, ratioSum := distributionData(t, viewRequestCompressionRatio, LogsPipeline)
+
+	test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+	_, err := test.s.sendLogs(context.Background(), newFields(pdata.NewAttributeMap()))
+	require.NoError(t, err)
+
+	// "Example log\nAnother example log" is sent without compression
+	count, sum := distributionData(t, viewRequestUncompressedSize, LogsPipeline)
+	assert.Equal(t, uncompressedCount+1, count)
+	assert.InDelta(t, uncompressedSum+31, sum, 0.001)
+
+	count, sum = distributionData(t, viewRequestCompressedSize, LogsPipeline)
+	assert.Equal(t, compressedCount+1, count)
+	assert.InDelta(t, compressedSum+31, sum, 0.001)
+
+	count, _ = distributionData(t, viewRequestHeadersSize, LogsPipeline)
+	assert.Equal(t, headersCount+1, count)
+
+	count, sum = distributionData(t, viewRequestCompressionRatio, LogsPipeline)
+	assert.Equal(t, ratioCount+1, count)
+	assert.InDelta(t, ratioSum+1, sum, 0.001)
+}
@@ -0,0 +1,143 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	payloadSampleFilePattern = "payload-%03d.txt"
+	redactedMarker           = "[REDACTED]"
+)
+
+// secretsRegex matches values of the commonly used secret keys, e.g. `"password": "abc"` or `token=abc`
+var secretsRegex = regexp.MustCompile(
+	`(?i)((?:password|passwd|secret|token|api[_-]?key|access[_-]?key|access[_-]?id|authorization)["']?\s*[:=]\s*["']?)[^\s"'&,}]+`,
+)
+
+// sampledHeaders is a list of the request headers which are stored with the sampled payload
+var sampledHeaders = []string{
+	headerContentType,
+	headerContentEncoding,
+	headerClient,
+	headerHost,
+	headerName,
+	headerCategory,
+	headerFields,
+}
+
+// payloadSampler stores every N-th payload in a bounded ring of files
+type payloadSampler struct {
+	directory      string
+	sampleRate     uint64
+	maxFiles       uint64
+	maxFileSize    int
+	redactPatterns []*regexp.Regexp
+
+	counter uint64
+
+	// writeLock guards nextFile and writing to the files
+	writeLock sync.Mutex
+	nextFile  uint64
+}
+
+func newPayloadSampler(cfg PayloadSamplingConfig) (*payloadSampler, error) {
+	if err := os.MkdirAll(cfg.Directory, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create payload sampling directory: %w", err)
+	}
+
+	redactPatterns := make([]*regexp.Regexp, 0, len(cfg.RedactPatterns))
+	for _, p := range cfg.RedactPatterns {
+		r, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", p, err)
+		}
+		redactPatterns = append(redactPatterns, r)
+	}
+
+	return &payloadSampler{
+		directory:      cfg.Directory,
+		sampleRate:     uint64(cfg.SampleRate),
+		maxFiles:       uint64(cfg.MaxFiles),
+		maxFileSize:    cfg.MaxFileSize,
+		redactPatterns: redactPatterns,
+	}, nil
+}
+
+// shouldSample returns true for every N-th call, starting with the first one
+func (ps *payloadSampler) shouldSample() bool {
+	n := atomic.AddUint64(&ps.counter, 1)
+	return (n-1)%ps.sampleRate == 0
+}
+
+// sample stores the redacted payload along with the request metadata
+// in the next file of the ring, overwriting the oldest sample
+func (ps *payloadSampler) sample(pipeline PipelineType, u *url.URL, header http.Header, body []byte) error {
+	// Redact before truncating, so a partially cut secret is never stored
+	body = ps.redact(body)
+	truncated := false
+	if ps.maxFileSize > 0 && len(body) > ps.maxFileSize {
+		body = body[:ps.maxFileSize]
+		truncated = true
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# timestamp: %s\n", time.Now().UTC().Format(time.RFC3339Nano))
+	fmt.Fprintf(&buf, "# pipeline: %s\n", pipeline)
+	// Only scheme and host are stored as the path may contain the HTTP source token
+	fmt.Fprintf(&buf, "# host: %s://%s\n", u.Scheme, u.Host)
+	for _, h := range sampledHeaders {
+		if v := header.Get(h); v != "" {
+			fmt.Fprintf(&buf, "# header %s: %s\n", h, ps.redact([]byte(v)))
+		}
+	}
+	fmt.Fprintf(&buf, "# truncated: %t\n\n", truncated)
+	buf.Write(body)
+
+	ps.writeLock.Lock()
+	defer ps.writeLock.Unlock()
+
+	path := filepath.Join(ps.directory, fmt.Sprintf(payloadSampleFilePattern, ps.nextFile))
+	ps.nextFile = (ps.nextFile + 1) % ps.maxFiles
+
+	// Write to the temporary file first, so partially written sample is never retrieved
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write payload sample: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write payload sample: %w", err)
+	}
+
+	return nil
+}
+
+func (ps *payloadSampler) redact(data []byte) []byte {
+	data = secretsRegex.ReplaceAll(data, []byte("${1}"+redactedMarker))
+	for _, r := range ps.redactPatterns {
+		data = r.ReplaceAll(data, []byte(redactedMarker))
+	}
+	return data
+}
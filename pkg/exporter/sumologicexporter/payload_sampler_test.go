@@ -0,0 +1,179 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPayloadSamplerShouldSample(t *testing.T) {
+	ps, err := newPayloadSampler(PayloadSamplingConfig{
+		Directory:  t.TempDir(),
+		SampleRate: 3,
+		MaxFiles:   1,
+	})
+	require.NoError(t, err)
+
+	var sampled []bool
+	for i := 0; i < 7; i++ {
+		sampled = append(sampled, ps.shouldSample())
+	}
+	assert.Equal(t, []bool{true, false, false, true, false, false, true}, sampled)
+}
+
+func TestPayloadSamplerRing(t *testing.T) {
+	dir := t.TempDir()
+	ps, err := newPayloadSampler(PayloadSamplingConfig{
+		Directory:  dir,
+		SampleRate: 1,
+		MaxFiles:   2,
+	})
+	require.NoError(t, err)
+
+	u, err := url.Parse("https://collectors.sumologic.com/receiver/v1/http/secret-token")
+	require.NoError(t, err)
+
+	for _, body := range []string{"first", "second", "third"} {
+		require.NoError(t, ps.sample(LogsPipeline, u, http.Header{}, []byte(body)))
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	first, err := os.ReadFile(filepath.Join(dir, "payload-000.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(first), "\n\nthird")
+	assert.Contains(t, string(first), "# host: https://collectors.sumologic.com\n")
+	assert.NotContains(t, string(first), "secret-token")
+
+	second, err := os.ReadFile(filepath.Join(dir, "payload-001.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(second), "\n\nsecond")
+}
+
+func TestPayloadSamplerRedactAndTruncate(t *testing.T) {
+	dir := t.TempDir()
+	ps, err := newPayloadSampler(PayloadSamplingConfig{
+		Directory:      dir,
+		SampleRate:     1,
+		MaxFiles:       1,
+		MaxFileSize:    80,
+		RedactPatterns: []string{`\d{3}-\d{2}-\d{4}`},
+	})
+	require.NoError(t, err)
+
+	u, err := url.Parse("http://localhost:3000")
+	require.NoError(t, err)
+
+	header := http.Header{}
+	header.Set(headerFields, "token=abc, key=value")
+	header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	body := `{"password": "hunter2", "ssn": "123-45-6789", "log": "api_key=qwerty"} and some more text`
+
+	require.NoError(t, ps.sample(LogsPipeline, u, header, []byte(body)))
+
+	data, err := os.ReadFile(filepath.Join(dir, "payload-000.txt"))
+	require.NoError(t, err)
+	content := string(data)
+
+	assert.Contains(t, content, "# pipeline: logs\n")
+	assert.Contains(t, content, "# header X-Sumo-Fields: token=[REDACTED], key=value\n")
+	assert.Contains(t, content, "# truncated: true\n")
+	assert.Contains(t, content, `{"password": "[REDACTED]", "ssn": "[REDACTED]", "log": "api_key=[REDACTED]"}`)
+	assert.NotContains(t, content, "Authorization")
+	assert.NotContains(t, content, "hunter2")
+	assert.NotContains(t, content, "some more text")
+}
+
+func TestSendLogsPayloadSampling(t *testing.T) {
+	dir := t.TempDir()
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {},
+		func(w http.ResponseWriter, req *http.Request) {},
+	}, func(cfg *Config) {
+		cfg.CompressEncoding = GZIPCompression
+	})
+
+	ps, err := newPayloadSampler(PayloadSamplingConfig{
+		Directory:  dir,
+		SampleRate: 2,
+		MaxFiles:   10,
+	})
+	require.NoError(t, err)
+	test.s.payloadSampler = ps
+
+	for i := 0; i < 2; i++ {
+		test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+		_, err := test.s.sendLogs(context.Background(), fieldsFromMap(map[string]string{}))
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 2, *test.reqCounter)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# header Content-Encoding: gzip\n")
+	assert.Contains(t, string(data), "\n\nExample log\nAnother example log")
+}
+
+func TestSendLogsPayloadSamplingNotSent(t *testing.T) {
+	dir := t.TempDir()
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+	})
+	test.s.throttling = newThrottling(ThrottlingConfig{
+		PauseSends:    true,
+		DefaultPeriod: DefaultThrottlingDefaultPeriod,
+		MaxPeriod:     DefaultThrottlingMaxPeriod,
+	}, zap.NewNop())
+
+	ps, err := newPayloadSampler(PayloadSamplingConfig{
+		Directory:  dir,
+		SampleRate: 1,
+		MaxFiles:   10,
+	})
+	require.NoError(t, err)
+	test.s.payloadSampler = ps
+
+	test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+	_, err = test.s.sendLogs(context.Background(), fieldsFromMap(map[string]string{}))
+	require.Error(t, err)
+
+	// the request of the throttled pipeline is not issued, so it's not sampled
+	test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+	_, err = test.s.sendLogs(context.Background(), fieldsFromMap(map[string]string{}))
+	assert.ErrorIs(t, err, errThrottled)
+	assert.EqualValues(t, 1, *test.reqCounter)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
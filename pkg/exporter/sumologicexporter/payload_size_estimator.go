@@ -0,0 +1,101 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"sync"
+)
+
+const (
+	// minEstimatedCompressionRatio limits the estimated compression ratio, so the body
+	// before compression doesn't grow over 10 times the limit when the data compresses well
+	minEstimatedCompressionRatio = 0.1
+	// compressionRatioWeight is the weight of the last request
+	// in the moving average of the compression ratio
+	compressionRatioWeight = 0.2
+)
+
+// payloadSizeEstimator estimates the size of the request, as seen by the backend,
+// from the size of the body before compression. The compression ratio is
+// the exponential moving average of the ratios of the previous requests,
+// so it adapts to the data sent in the pipeline.
+type payloadSizeEstimator struct {
+	initialRatio float64
+
+	lock      sync.Mutex
+	pipelines map[PipelineType]*payloadSizeEstimate
+}
+
+type payloadSizeEstimate struct {
+	ratio   float64
+	headers int64
+}
+
+func newPayloadSizeEstimator(cfg PayloadSizeEstimationConfig) *payloadSizeEstimator {
+	return &payloadSizeEstimator{
+		initialRatio: cfg.InitialCompressionRatio,
+		pipelines:    make(map[PipelineType]*payloadSizeEstimate),
+	}
+}
+
+// estimate returns the estimated size of the request with the body of the given size
+// before compression, i.e. the size of the compressed body and the headers
+func (e *payloadSizeEstimator) estimate(pipeline PipelineType, bodySize int) int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	ratio, headers := e.initialRatio, int64(0)
+	if state, ok := e.pipelines[pipeline]; ok {
+		ratio, headers = state.ratio, state.headers
+	}
+	return int(float64(bodySize)*ratio) + int(headers)
+}
+
+// observe updates the compression ratio and the headers size of the pipeline
+// with the sizes of the request sent
+func (e *payloadSizeEstimator) observe(pipeline PipelineType, sizes requestSizes) {
+	if sizes.uncompressed <= 0 || sizes.compressed < 0 {
+		return
+	}
+
+	ratio := float64(sizes.compressed) / float64(sizes.uncompressed)
+	if ratio < minEstimatedCompressionRatio {
+		ratio = minEstimatedCompressionRatio
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	state, ok := e.pipelines[pipeline]
+	if !ok {
+		e.pipelines[pipeline] = &payloadSizeEstimate{
+			ratio:   ratio,
+			headers: sizes.headers,
+		}
+		return
+	}
+
+	state.ratio = compressionRatioWeight*ratio + (1-compressionRatioWeight)*state.ratio
+	state.headers = sizes.headers
+}
+
+// requestSize returns the size of the request sent, comparable with the estimates,
+// -1 if it's unknown
+func (e *payloadSizeEstimator) requestSize(sizes requestSizes) int64 {
+	if sizes.compressed < 0 {
+		return -1
+	}
+	return sizes.compressed + sizes.headers
+}
@@ -0,0 +1,55 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayloadSizeEstimator(t *testing.T) {
+	e := newPayloadSizeEstimator(PayloadSizeEstimationConfig{InitialCompressionRatio: 0.5})
+
+	// the initial ratio is used until the first request is sent
+	assert.Equal(t, 500, e.estimate(LogsPipeline, 1000))
+
+	e.observe(LogsPipeline, requestSizes{uncompressed: 1000, compressed: 250, headers: 100})
+	assert.Equal(t, 350, e.estimate(LogsPipeline, 1000))
+	assert.Equal(t, 500, e.estimate(MetricsPipeline, 1000), "the pipelines are estimated separately")
+
+	// the ratio is the moving average: 0.2 * 0.75 + 0.8 * 0.25 = 0.35
+	e.observe(LogsPipeline, requestSizes{uncompressed: 1000, compressed: 750, headers: 120})
+	assert.Equal(t, 470, e.estimate(LogsPipeline, 1000))
+
+	// the requests with unknown sizes are ignored
+	e.observe(LogsPipeline, requestSizes{uncompressed: -1, compressed: 10, headers: 0})
+	e.observe(LogsPipeline, requestSizes{uncompressed: 1000, compressed: -1, headers: 0})
+	assert.Equal(t, 470, e.estimate(LogsPipeline, 1000))
+}
+
+func TestPayloadSizeEstimatorMinRatio(t *testing.T) {
+	e := newPayloadSizeEstimator(PayloadSizeEstimationConfig{InitialCompressionRatio: 1})
+
+	e.observe(TracesPipeline, requestSizes{uncompressed: 100_000, compressed: 1000, headers: 0})
+	assert.Equal(t, 10_000, e.estimate(TracesPipeline, 100_000))
+}
+
+func TestPayloadSizeEstimatorRequestSize(t *testing.T) {
+	e := newPayloadSizeEstimator(PayloadSizeEstimationConfig{InitialCompressionRatio: 1})
+
+	assert.EqualValues(t, 300, e.requestSize(requestSizes{uncompressed: 1000, compressed: 250, headers: 50}))
+	assert.EqualValues(t, -1, e.requestSize(requestSizes{uncompressed: 1000, compressed: -1, headers: 50}))
+}
@@ -17,6 +17,7 @@ package sumologicexporter
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,9 +29,16 @@ type dataPoint interface {
 	Attributes() pdata.AttributeMap
 }
 
+type cumulativeDataPoint interface {
+	dataPoint
+	StartTimestamp() pdata.Timestamp
+}
+
 type prometheusFormatter struct {
 	sanitNameRegex *regexp.Regexp
 	replacer       *strings.Replacer
+	// createdSeries enables the _created lines with the start timestamps of the cumulative data points
+	createdSeries bool
 }
 
 type prometheusTags string
@@ -39,6 +47,7 @@ const (
 	prometheusLeTag       string = "le"
 	prometheusQuantileTag string = "quantile"
 	prometheusInfValue    string = "+Inf"
+	prometheusCreated     string = "_created"
 )
 
 func newPrometheusFormatter() (prometheusFormatter, error) {
@@ -173,6 +182,25 @@ func (f *prometheusFormatter) numberDataPointValueLine(name string, dp pdata.Num
 	return ""
 }
 
+// createdLines appends the _created line with the start timestamp of the cumulative data point
+// to the lines, following the OpenMetrics convention. The value is the start timestamp
+// in seconds, so the counter resets can be detected even when the value doesn't decrease.
+// Nothing is appended when the created series are disabled or the start timestamp is not set.
+func (f *prometheusFormatter) createdLines(lines []string, name string, dp cumulativeDataPoint, attributes pdata.AttributeMap) []string {
+	if !f.createdSeries || dp.StartTimestamp() == 0 {
+		return lines
+	}
+
+	startMillis := int64(dp.StartTimestamp() / pdata.Timestamp(time.Millisecond))
+	return append(lines, fmt.Sprintf(
+		"%s%s %s %d",
+		f.sanitizeKey(name+prometheusCreated),
+		f.tags2String(attributes, dp.Attributes()),
+		strconv.FormatFloat(float64(startMillis)/1000, 'f', -1, 64),
+		dp.Timestamp()/pdata.Timestamp(time.Millisecond),
+	))
+}
+
 // sumMetric returns _sum suffixed metric name
 func (f *prometheusFormatter) sumMetric(name string) string {
 	return fmt.Sprintf("%s_sum", name)
@@ -214,8 +242,10 @@ func (f *prometheusFormatter) gauge2Strings(record metricPair) []string {
 
 // doubleSum2Strings converts Sum record to a list of strings (one per dataPoint)
 func (f *prometheusFormatter) sum2Strings(record metricPair) []string {
-	dps := record.metric.Sum().DataPoints()
+	sum := record.metric.Sum()
+	dps := sum.DataPoints()
 	lines := make([]string, 0, dps.Len())
+	cumulative := sum.IsMonotonic() && sum.AggregationTemporality() == pdata.MetricAggregationTemporalityCumulative
 
 	for i := 0; i < dps.Len(); i++ {
 		dp := dps.At(i)
@@ -225,6 +255,10 @@ func (f *prometheusFormatter) sum2Strings(record metricPair) []string {
 			record.attributes,
 		)
 		lines = append(lines, line)
+
+		if cumulative {
+			lines = f.createdLines(lines, record.metric.Name(), dp, record.attributes)
+		}
 	}
 
 	return lines
@@ -268,6 +302,8 @@ func (f *prometheusFormatter) summary2Strings(record metricPair) []string {
 			record.attributes,
 		)
 		lines = append(lines, line)
+
+		lines = f.createdLines(lines, record.metric.Name(), dp, record.attributes)
 	}
 	return lines
 }
@@ -323,6 +359,10 @@ func (f *prometheusFormatter) histogram2Strings(record metricPair) []string {
 			record.attributes,
 		)
 		lines = append(lines, line)
+
+		if record.metric.Histogram().AggregationTemporality() == pdata.MetricAggregationTemporalityCumulative {
+			lines = f.createdLines(lines, record.metric.Name(), dp, record.attributes)
+		}
 	}
 
 	return lines
@@ -344,3 +384,13 @@ func (f *prometheusFormatter) metric2String(record metricPair) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+// Format returns the metric as prometheus formatted lines
+func (f *prometheusFormatter) Format(metric pdata.Metric, attributes pdata.AttributeMap) (string, error) {
+	return f.metric2String(metricPair{metric: metric, attributes: attributes}), nil
+}
+
+// ContentType returns content type of the prometheus metrics
+func (f *prometheusFormatter) ContentType() string {
+	return contentTypePrometheus
+}
@@ -155,3 +155,69 @@ histogram_metric_double_test_sum{bar="foo",container="sit",branch="main"} 54.1 1
 histogram_metric_double_test_count{bar="foo",container="sit",branch="main"} 98 1608424699186`
 	assert.Equal(t, expected, result)
 }
+
+func TestPrometheusCreatedSeries(t *testing.T) {
+	f, err := newPrometheusFormatter()
+	require.NoError(t, err)
+	f.createdSeries = true
+
+	sum := exampleIntSumMetric()
+	sum.metric.Sum().SetIsMonotonic(true)
+	sum.metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	sum.metric.Sum().DataPoints().At(0).SetStartTimestamp(1608124000.5 * 1e9)
+
+	expected := `sum_metric_int_test{foo="bar",name="156720",address="http://example_url"} 45 1608124444169
+sum_metric_int_test_created{foo="bar",name="156720",address="http://example_url"} 1608124000.5 1608124444169
+sum_metric_int_test{foo="bar",name="156155",address="http://another_url"} 1238 1608124699186`
+	assert.Equal(t, expected, f.metric2String(sum))
+
+	summary := exampleSummaryMetric()
+	summary.metric.Summary().DataPoints().At(1).SetStartTimestamp(1608424000 * 1e9)
+
+	expected = `summary_metric_double_test{foo="bar",quantile="0.6",pod_name="dolor",namespace="sumologic"} 0.7 1618124444169
+summary_metric_double_test{foo="bar",quantile="2.6",pod_name="dolor",namespace="sumologic"} 4 1618124444169
+summary_metric_double_test_sum{foo="bar",pod_name="dolor",namespace="sumologic"} 45.6 1618124444169
+summary_metric_double_test_count{foo="bar",pod_name="dolor",namespace="sumologic"} 3 1618124444169
+summary_metric_double_test_sum{foo="bar",pod_name="sit",namespace="main"} 1238.1 1608424699186
+summary_metric_double_test_count{foo="bar",pod_name="sit",namespace="main"} 7 1608424699186
+summary_metric_double_test_created{foo="bar",pod_name="sit",namespace="main"} 1608424000 1608424699186`
+	assert.Equal(t, expected, f.metric2String(summary))
+
+	histogram := exampleHistogramMetric()
+	histogram.metric.Histogram().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	histogram.metric.Histogram().DataPoints().RemoveIf(func(dp pdata.HistogramDataPoint) bool {
+		return dp.Count() == 98
+	})
+	histogram.metric.Histogram().DataPoints().At(0).SetStartTimestamp(1618124000.123 * 1e9)
+
+	expected = `histogram_metric_double_test{bar="foo",le="0.1",container="dolor",branch="sumologic"} 0 1618124444169
+histogram_metric_double_test{bar="foo",le="0.2",container="dolor",branch="sumologic"} 12 1618124444169
+histogram_metric_double_test{bar="foo",le="0.5",container="dolor",branch="sumologic"} 19 1618124444169
+histogram_metric_double_test{bar="foo",le="0.8",container="dolor",branch="sumologic"} 24 1618124444169
+histogram_metric_double_test{bar="foo",le="1",container="dolor",branch="sumologic"} 32 1618124444169
+histogram_metric_double_test{bar="foo",le="+Inf",container="dolor",branch="sumologic"} 45 1618124444169
+histogram_metric_double_test_sum{bar="foo",container="dolor",branch="sumologic"} 45.6 1618124444169
+histogram_metric_double_test_count{bar="foo",container="dolor",branch="sumologic"} 7 1618124444169
+histogram_metric_double_test_created{bar="foo",container="dolor",branch="sumologic"} 1618124000.123 1618124444169`
+	assert.Equal(t, expected, f.metric2String(histogram))
+}
+
+func TestPrometheusCreatedSeriesSkipsNonCumulative(t *testing.T) {
+	f, err := newPrometheusFormatter()
+	require.NoError(t, err)
+	f.createdSeries = true
+
+	sum := exampleIntSumMetric()
+	sum.metric.Sum().SetIsMonotonic(true)
+	sum.metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityDelta)
+	sum.metric.Sum().DataPoints().At(0).SetStartTimestamp(1608124000 * 1e9)
+	assert.NotContains(t, f.metric2String(sum), "_created")
+
+	sum.metric.Sum().SetIsMonotonic(false)
+	sum.metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	assert.NotContains(t, f.metric2String(sum), "_created")
+
+	f.createdSeries = false
+	sum.metric.Sum().SetIsMonotonic(true)
+	assert.NotContains(t, f.metric2String(sum), "_created")
+}
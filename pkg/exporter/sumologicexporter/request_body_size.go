@@ -0,0 +1,91 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// minRequestBodySize is the lowest limit the request body size is lowered to
+const minRequestBodySize int = 64 * 1024
+
+// requestBodySizeLimits keeps the effective limits of the request body size per pipeline.
+// They start at max_request_body_size and are lowered when the backend rejects
+// the request as too large, so the subsequent requests are split into smaller ones.
+type requestBodySizeLimits struct {
+	logger *zap.Logger
+	max    int
+
+	lock   sync.RWMutex
+	limits map[PipelineType]int
+}
+
+func newRequestBodySizeLimits(max int, logger *zap.Logger) *requestBodySizeLimits {
+	return &requestBodySizeLimits{
+		logger: logger,
+		max:    max,
+		limits: make(map[PipelineType]int),
+	}
+}
+
+// get returns the effective limit of the request body size of the pipeline
+func (l *requestBodySizeLimits) get(pipeline PipelineType) int {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	if limit, ok := l.limits[pipeline]; ok {
+		return limit
+	}
+	return l.max
+}
+
+// lower halves the limit of the pipeline after the request body of the given size
+// (-1 if it's unknown) was rejected as too large, but not below minRequestBodySize
+func (l *requestBodySizeLimits) lower(pipeline PipelineType, rejectedSize int64) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	current, ok := l.limits[pipeline]
+	if !ok {
+		current = l.max
+	}
+
+	limit := current
+	if rejectedSize >= 0 && rejectedSize < int64(limit) {
+		limit = int(rejectedSize)
+	}
+	limit /= 2
+
+	floor := minRequestBodySize
+	if l.max < floor {
+		floor = l.max
+	}
+	if limit < floor {
+		limit = floor
+	}
+	if limit >= current {
+		return
+	}
+
+	l.limits[pipeline] = limit
+	recordRequestBodySizeLimit(pipeline, limit)
+	l.logger.Warn("Request rejected as too large, lowered the request body size limit",
+		zap.String("pipeline", string(pipeline)),
+		zap.Int("previous_limit", current),
+		zap.Int("limit", limit),
+	)
+}
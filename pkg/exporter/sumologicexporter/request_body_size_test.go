@@ -0,0 +1,70 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRequestBodySizeLimits(t *testing.T) {
+	limits := newRequestBodySizeLimits(1024*1024, zap.NewNop())
+	assert.Equal(t, 1024*1024, limits.get(LogsPipeline))
+
+	limits.lower(LogsPipeline, 1024*1024)
+	assert.Equal(t, 512*1024, limits.get(LogsPipeline))
+	assert.Equal(t, 1024*1024, limits.get(MetricsPipeline))
+
+	// the limit is based on the rejected body when it's smaller than the limit
+	limits.lower(LogsPipeline, 300*1024)
+	assert.Equal(t, 150*1024, limits.get(LogsPipeline))
+
+	// the unknown size halves the current limit
+	limits.lower(LogsPipeline, -1)
+	assert.Equal(t, 75*1024, limits.get(LogsPipeline))
+
+	limits.lower(LogsPipeline, -1)
+	assert.Equal(t, minRequestBodySize, limits.get(LogsPipeline))
+	limits.lower(LogsPipeline, 1024)
+	assert.Equal(t, minRequestBodySize, limits.get(LogsPipeline))
+
+	// the limit is never raised above max_request_body_size
+	small := newRequestBodySizeLimits(1024, zap.NewNop())
+	small.lower(LogsPipeline, 1024)
+	assert.Equal(t, 1024, small.get(LogsPipeline))
+}
+
+func TestSendLowersRequestBodySizeLimit(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		},
+	}, func(cfg *Config) {
+		cfg.MaxRequestBodySize = 1024 * 1024
+	})
+	test.s.bodySizeLimits = newRequestBodySizeLimits(test.s.config.MaxRequestBodySize, zap.NewNop())
+
+	test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+	_, err := test.s.sendLogs(context.Background(), fieldsFromMap(map[string]string{}))
+	require.Error(t, err)
+
+	assert.Equal(t, minRequestBodySize, test.s.maxRequestBodySize(LogsPipeline))
+	assert.Equal(t, 1024*1024, test.s.maxRequestBodySize(MetricsPipeline))
+}
@@ -0,0 +1,55 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// route sends the data with the matching source category to the endpoint
+type route struct {
+	sourceCategory *regexp.Regexp
+	endpoint       string
+}
+
+// routes is the routing table, in which the first matching route wins
+type routes []route
+
+func newRoutes(cfgs []RouteConfig) (routes, error) {
+	rs := make(routes, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		// the pattern has to match the whole source category
+		re, err := regexp.Compile("^(?:" + cfg.SourceCategory + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid source category pattern %q: %w", cfg.SourceCategory, err)
+		}
+		rs = append(rs, route{
+			sourceCategory: re,
+			endpoint:       cfg.Endpoint,
+		})
+	}
+	return rs, nil
+}
+
+// endpoint returns the endpoint of the first route matching the source category
+func (rs routes) endpoint(sourceCategory string) (string, bool) {
+	for _, r := range rs {
+		if r.sourceCategory.MatchString(sourceCategory) {
+			return r.endpoint, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,92 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutes(t *testing.T) {
+	rs, err := newRoutes([]RouteConfig{
+		{SourceCategory: "prod/.*", Endpoint: "https://prod.example.com"},
+		{SourceCategory: "dev|test", Endpoint: "https://dev.example.com"},
+		{SourceCategory: ".*", Endpoint: "https://default.example.com"},
+	})
+	require.NoError(t, err)
+
+	testcases := []struct {
+		sourceCategory string
+		expected       string
+	}{
+		{sourceCategory: "prod/app", expected: "https://prod.example.com"},
+		{sourceCategory: "dev", expected: "https://dev.example.com"},
+		{sourceCategory: "test", expected: "https://dev.example.com"},
+		// the pattern has to match the whole source category
+		{sourceCategory: "my/prod/app", expected: "https://default.example.com"},
+		{sourceCategory: "development", expected: "https://default.example.com"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.sourceCategory, func(t *testing.T) {
+			endpoint, ok := rs.endpoint(tc.sourceCategory)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, endpoint)
+		})
+	}
+
+	_, ok := rs[:2].endpoint("other")
+	assert.False(t, ok)
+
+	_, err = newRoutes([]RouteConfig{{SourceCategory: "[", Endpoint: "https://example.com"}})
+	assert.Error(t, err)
+}
+
+func TestSendWithRoutes(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, "dev/app", req.Header.Get("X-Sumo-Category"))
+		},
+	})
+
+	var routedCounter int32
+	routed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "prod/app", req.Header.Get("X-Sumo-Category"))
+		atomic.AddInt32(&routedCounter, 1)
+	}))
+	t.Cleanup(routed.Close)
+
+	rs, err := newRoutes([]RouteConfig{{SourceCategory: "prod/.*", Endpoint: routed.URL}})
+	require.NoError(t, err)
+	test.s.routes = rs
+	test.s.sources.category = getTestSourceFormat(t, "%{env}/app")
+
+	test.s.logBuffer = logRecordsToLogPair(exampleLog())
+	_, err = test.s.sendLogs(context.Background(), fieldsFromMap(map[string]string{"env": "prod"}))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&routedCounter))
+	assert.EqualValues(t, 0, atomic.LoadInt32(test.reqCounter))
+
+	_, err = test.s.sendLogs(context.Background(), fieldsFromMap(map[string]string{"env": "dev"}))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&routedCounter))
+	assert.EqualValues(t, 1, atomic.LoadInt32(test.reqCounter))
+}
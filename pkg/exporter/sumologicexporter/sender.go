@@ -25,8 +25,10 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/model/otlp"
 	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/otel/propagation"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 )
@@ -57,21 +59,42 @@ type logPair struct {
 	log        pdata.LogRecord
 }
 
+// senderDeps are the components of the exporter shared by all of its senders
+type senderDeps struct {
+	payloadSampler  *payloadSampler
+	usageCounters   *usageCounters
+	circuitBreakers *circuitBreakers
+	routes          routes
+	bodySizeLimits  *requestBodySizeLimits
+	dropAudit       *dropAudit
+	carbonTCP       *carbonTCPClient
+	metricFilter    *metricFilter
+	throttling      *throttling
+	batchSizeTuner  *batchSizeTuner
+	sizeEstimator   *payloadSizeEstimator
+	// sharedBudget is shared with the exporters of the other signals with the same ID
+	sharedBudget *sharedRetryBudget
+	// textLogTemplate is nil unless text_log_template is set
+	textLogTemplate *textLogTemplate
+	// userAgent is sent in the User-Agent header of every request
+	userAgent string
+}
+
 type sender struct {
-	logger              *zap.Logger
-	logBuffer           []logPair
-	metricBuffer        []metricPair
-	config              *Config
-	client              *http.Client
-	filter              filter
-	sources             sourceFormats
-	compressor          compressor
-	prometheusFormatter prometheusFormatter
-	graphiteFormatter   graphiteFormatter
-	jsonLogsConfig      JSONLogs
-	dataUrlMetrics      string
-	dataUrlLogs         string
-	dataUrlTraces       string
+	logger          *zap.Logger
+	logBuffer       []logPair
+	metricBuffer    []metricPair
+	config          *Config
+	client          *http.Client
+	filter          filter
+	sources         sourceFormats
+	compressor      compressor
+	metricFormatter MetricFormatter
+	jsonLogsConfig  JSONLogs
+	dataUrlMetrics  string
+	dataUrlLogs     string
+	dataUrlTraces   string
+	senderDeps
 }
 
 const (
@@ -90,7 +113,11 @@ const (
 	attributeKeySourceName     = "_sourceName"
 	attributeKeySourceCategory = "_sourceCategory"
 
+	attributeKeyDroppedAttributesCount = "dropped_attributes_count"
+	attributeKeyTruncated              = "sumo.truncated"
+
 	contentTypeLogs       string = "application/x-www-form-urlencoded"
+	contentTypeJSON       string = "application/json"
 	contentTypePrometheus string = "application/vnd.sumologic.prometheus"
 	contentTypeCarbon2    string = "application/vnd.sumologic.carbon2"
 	contentTypeGraphite   string = "application/vnd.sumologic.graphite"
@@ -113,25 +140,25 @@ func newSender(
 	f filter,
 	s sourceFormats,
 	c compressor,
-	pf prometheusFormatter,
-	gf graphiteFormatter,
+	mf MetricFormatter,
 	metricsUrl string,
 	logsUrl string,
 	tracesUrl string,
+	deps senderDeps,
 ) *sender {
 	return &sender{
-		logger:              logger,
-		config:              cfg,
-		client:              cl,
-		filter:              f,
-		sources:             s,
-		compressor:          c,
-		prometheusFormatter: pf,
-		graphiteFormatter:   gf,
-		jsonLogsConfig:      cfg.JSONLogs,
-		dataUrlMetrics:      metricsUrl,
-		dataUrlLogs:         logsUrl,
-		dataUrlTraces:       tracesUrl,
+		logger:          logger,
+		config:          cfg,
+		client:          cl,
+		filter:          f,
+		sources:         s,
+		compressor:      c,
+		metricFormatter: mf,
+		jsonLogsConfig:  cfg.JSONLogs,
+		dataUrlMetrics:  metricsUrl,
+		dataUrlLogs:     logsUrl,
+		dataUrlTraces:   tracesUrl,
+		senderDeps:      deps,
 	}
 }
 
@@ -139,12 +166,42 @@ var errUnauthorized = errors.New("unauthorized")
 
 // send sends data to sumologic
 func (s *sender) send(ctx context.Context, pipeline PipelineType, body io.Reader, flds fields) error {
+	if s.config.DryRun {
+		return s.validate(pipeline, body, flds)
+	}
+
+	// Graphite metrics are written directly to the Carbon endpoint when configured
+	if pipeline == MetricsPipeline && s.carbonTCP != nil {
+		return s.carbonTCP.send(ctx, body)
+	}
+
+	// The raw body is needed for sampling and for computing the idempotency key
+	var rawBody []byte
+	sample := s.payloadSampler != nil && s.payloadSampler.shouldSample()
+	if sample || s.config.IdempotencyKeyHeader != "" {
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		rawBody = b
+		body = bytes.NewReader(b)
+	}
+
+	sizes := requestSizes{uncompressed: readerLen(body)}
+
+	var counter *countingReader
+	if s.usageCounters != nil {
+		counter = &countingReader{r: body}
+		body = counter
+	}
+
 	data, err := s.compressor.compress(body)
 	if err != nil {
 		return err
 	}
+	sizes.compressed = readerLen(data)
 
-	req, err := s.createRequest(ctx, pipeline, data)
+	req, err := s.createRequest(ctx, pipeline, data, flds)
 	if err != nil {
 		return err
 	}
@@ -153,18 +210,97 @@ func (s *sender) send(ctx context.Context, pipeline PipelineType, body io.Reader
 		return err
 	}
 
+	if s.config.PropagateTraceContext {
+		propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	}
+
+	if s.config.IdempotencyKeyHeader != "" {
+		req.Header.Set(s.config.IdempotencyKeyHeader, idempotencyKey(pipeline, req.Header, rawBody))
+	}
+
 	s.logger.Debug("Sending data",
 		zap.String("pipeline", string(pipeline)),
 		zap.Any("headers", req.Header),
 	)
 
+	sizes.headers = headersSize(req.Header)
+
+	if err := s.throttling.check(pipeline); err != nil {
+		return err
+	}
+
+	if s.sharedBudget != nil {
+		release, err := s.sharedBudget.acquire(pipeline, sizes.compressed)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	var cb *circuitBreaker
+	if s.circuitBreakers != nil {
+		cb = s.circuitBreakers.get(pipeline, req.URL.String())
+		if !cb.allow() {
+			return errCircuitBreakerOpen
+		}
+	}
+
 	resp, err := s.client.Do(req)
+
+	// Only the requests which were actually issued are sampled
+	if sample {
+		if err := s.payloadSampler.sample(pipeline, req.URL, req.Header, rawBody); err != nil {
+			s.logger.Warn("Failed to store payload sample", zap.Error(err))
+		}
+	}
+
 	if err != nil {
+		if cb != nil {
+			cb.onFailure()
+		}
+		if s.sharedBudget != nil {
+			s.sharedBudget.onResult(pipeline, true)
+		}
 		return err
 	}
 	defer resp.Body.Close()
 
-	return s.handleReceiverResponse(resp)
+	if s.sharedBudget != nil {
+		s.sharedBudget.onResult(pipeline,
+			isFailureStatusCode(resp.StatusCode) || resp.StatusCode == http.StatusTooManyRequests)
+	}
+
+	if cb != nil {
+		if isFailureStatusCode(resp.StatusCode) {
+			cb.onFailure()
+		} else {
+			cb.onSuccess()
+		}
+	}
+
+	recordRequestSizes(pipeline, sizes)
+	if s.batchSizeTuner != nil && sizes.compressed >= 0 {
+		s.batchSizeTuner.observe(pipeline, sizes.compressed)
+	}
+
+	rejectedSize := sizes.uncompressed
+	if s.sizeEstimator != nil {
+		s.sizeEstimator.observe(pipeline, sizes)
+		rejectedSize = s.sizeEstimator.requestSize(sizes)
+	}
+
+	if resp.StatusCode == http.StatusRequestEntityTooLarge && s.bodySizeLimits != nil {
+		s.bodySizeLimits.lower(pipeline, rejectedSize)
+	}
+
+	if err := s.throttling.onResponse(pipeline, resp, s.handleReceiverResponse(resp)); err != nil {
+		return err
+	}
+
+	if counter != nil {
+		s.usageCounters.add(req.Header.Get(headerCategory), counter.n)
+	}
+	return nil
 }
 
 func (s *sender) handleReceiverResponse(resp *http.Response) error {
@@ -229,9 +365,12 @@ func (s *sender) handleReceiverResponse(resp *http.Response) error {
 			)
 
 			if err := json.NewDecoder(tr).Decode(&rResponse); err != nil {
-				return fmt.Errorf("failed to decode API response (status: %s): %s",
-					resp.Status, b.String(),
-				)
+				return &statusError{
+					statusCode: resp.StatusCode,
+					err: fmt.Errorf("failed to decode API response (status: %s): %s",
+						resp.Status, b.String(),
+					),
+				}
 			}
 		}
 
@@ -252,13 +391,18 @@ func (s *sender) handleReceiverResponse(resp *http.Response) error {
 			errMsgs = append(errMsgs, fmt.Sprintf("errors: %+v", rResponse.Errors))
 		}
 
-		return fmt.Errorf("failed sending data: %s", strings.Join(errMsgs, ", "))
+		return &statusError{
+			statusCode: resp.StatusCode,
+			err:        fmt.Errorf("failed sending data: %s", strings.Join(errMsgs, ", ")),
+		}
 	}
 }
 
-func (s *sender) createRequest(ctx context.Context, pipeline PipelineType, data io.Reader) (*http.Request, error) {
+func (s *sender) createRequest(ctx context.Context, pipeline PipelineType, data io.Reader, flds fields) (*http.Request, error) {
 	var url string
-	if s.config.HTTPClientSettings.Endpoint == "" {
+	if endpoint, ok := s.routeEndpoint(flds); ok {
+		url = endpoint
+	} else if s.config.HTTPClientSettings.Endpoint == "" {
 		switch pipeline {
 		case MetricsPipeline:
 			url = s.dataUrlMetrics
@@ -266,6 +410,10 @@ func (s *sender) createRequest(ctx context.Context, pipeline PipelineType, data
 			url = s.dataUrlLogs
 		case TracesPipeline:
 			url = s.dataUrlTraces
+			if s.config.TraceFormat == TextTraceFormat {
+				// the spans rendered as text are ingested as logs
+				url = s.dataUrlLogs
+			}
 		default:
 			return nil, fmt.Errorf("unknown pipeline type: %s", pipeline)
 		}
@@ -281,14 +429,66 @@ func (s *sender) createRequest(ctx context.Context, pipeline PipelineType, data
 	return req, err
 }
 
-// logToText converts LogRecord to a plain text line, returns it and error eventually
-func (s *sender) logToText(record pdata.LogRecord) string {
-	return record.Body().AsString()
+// maxRequestBodySize returns the effective limit of the request body size of the pipeline,
+// the lower of the limits lowered after 413 responses and tuned by the adaptive batching
+func (s *sender) maxRequestBodySize(pipeline PipelineType) int {
+	limit := s.config.MaxRequestBodySize
+	if s.bodySizeLimits != nil {
+		limit = s.bodySizeLimits.get(pipeline)
+	}
+	if s.batchSizeTuner != nil {
+		if tuned := s.batchSizeTuner.get(pipeline); tuned < limit {
+			limit = tuned
+		}
+	}
+	return limit
+}
+
+// requestSize returns the size of the request with the body of the given size,
+// which is compared against the request body size limit. It's the size of the body
+// itself, unless the payload size estimation is enabled.
+func (s *sender) requestSize(pipeline PipelineType, bodySize int) int {
+	if s.sizeEstimator == nil {
+		return bodySize
+	}
+	return s.sizeEstimator.estimate(pipeline, bodySize)
+}
+
+// routeEndpoint returns the endpoint of the first route matching the source category
+// the data is sent with
+func (s *sender) routeEndpoint(flds fields) (string, bool) {
+	if len(s.routes) == 0 || !s.sources.category.isSet() {
+		return "", false
+	}
+	return s.routes.endpoint(s.sources.formatCategory(flds))
+}
+
+// logToText converts LogRecord to a plain text line, rendered according to text_log_template if it's set
+func (s *sender) logToText(record logPair) string {
+	if s.textLogTemplate != nil {
+		return s.textLogTemplate.format(record)
+	}
+	return formatTextLogBody(record.log.Body(), s.config.TextFormatBodyHandling, s.config.JSONLogs.LogKey)
 }
 
 // logToJSON converts LogRecord to a json line, returns it and error eventually
 func (s *sender) logToJSON(record logPair) (string, error) {
 	data := s.filter.filterOut(record.attributes)
+
+	truncated := false
+	if limit := s.jsonLogsConfig.MaxAttributes; limit > 0 && data.orig.Len() > limit {
+		data.orig = limitAttributes(data.orig, limit)
+		truncated = true
+	}
+
+	if dropped := record.log.DroppedAttributesCount(); dropped > 0 {
+		// the attribute of the record with the same name, if any, is kept
+		data.orig.InsertInt(attributeKeyDroppedAttributesCount, int64(dropped))
+	}
+	if truncated {
+		data.orig.UpsertBool(attributeKeyTruncated, true)
+	}
+
 	if s.jsonLogsConfig.AddTimestamp {
 		addJSONTimestamp(data.orig, s.jsonLogsConfig.TimestampKey, record.log.Timestamp())
 	}
@@ -310,7 +510,14 @@ func (s *sender) logToJSON(record logPair) (string, error) {
 		}
 	}
 
-	nextLine, err := json.Marshal(data.orig.AsRaw())
+	var raw map[string]interface{}
+	if s.jsonLogsConfig.PreserveTypes {
+		raw = attributeMapToJSON(data.orig)
+	} else {
+		raw = data.orig.AsRaw()
+	}
+
+	nextLine, err := json.Marshal(raw)
 	if err != nil {
 		return "", err
 	}
@@ -318,6 +525,24 @@ func (s *sender) logToJSON(record logPair) (string, error) {
 	return bytes.NewBuffer(nextLine).String(), nil
 }
 
+// limitAttributes returns a copy of attrs containing at most limit attributes.
+// Attributes are taken in the iteration order of attrs, which for filtered
+// attributes is the sorted key order.
+func limitAttributes(attrs pdata.AttributeMap, limit int) pdata.AttributeMap {
+	ret := pdata.NewAttributeMap()
+	ret.EnsureCapacity(limit)
+
+	attrs.Range(func(k string, v pdata.AttributeValue) bool {
+		if ret.Len() >= limit {
+			return false
+		}
+		ret.Insert(k, v)
+		return true
+	})
+
+	return ret
+}
+
 var timeZeroUTC = time.Unix(0, 0).UTC()
 
 // addJSONTimestamp adds a timestamp field to record attribtues before sending
@@ -357,14 +582,26 @@ func (s *sender) sendLogs(ctx context.Context, flds fields) ([]logPair, error) {
 		currentRecords []logPair
 	)
 
-	for _, record := range s.logBuffer {
+	for i, record := range s.logBuffer {
 		var formattedLine string
 		var err error
 
+		// When the context is done (e.g. on shutdown), the remaining records are returned
+		// as dropped at once, so they can be requeued, instead of failing to send them
+		// one request after another.
+		if err := ctx.Err(); err != nil {
+			remaining := append(currentRecords, s.logBuffer[i:]...)
+			droppedRecords = append(droppedRecords, remaining...)
+			errs = append(errs, err)
+			s.dropAudit.addError(LogsPipeline, err, len(remaining))
+			body.Reset()
+			break
+		}
+
 		switch s.config.LogFormat {
 		case TextFormat:
-			formattedLine = s.logToText(record.log)
-		case JSONFormat:
+			formattedLine = s.logToText(record)
+		case JSONFormat, JSONArrayFormat:
 			formattedLine, err = s.logToJSON(record)
 		default:
 			err = errors.New("unexpected log format")
@@ -373,23 +610,29 @@ func (s *sender) sendLogs(ctx context.Context, flds fields) ([]logPair, error) {
 		if err != nil {
 			droppedRecords = append(droppedRecords, record)
 			errs = append(errs, err)
+			s.dropAudit.add(LogsPipeline, dropReasonFormatError, 1)
 			continue
 		}
 
 		ar, err := s.appendAndSend(ctx, formattedLine, LogsPipeline, &body, flds)
 		if err != nil {
 			errs = append(errs, err)
+			dropped := 0
 			if ar.sent {
 				droppedRecords = append(droppedRecords, currentRecords...)
+				dropped += len(currentRecords)
 			}
 
 			if !ar.appended {
 				droppedRecords = append(droppedRecords, record)
+				dropped++
 			}
+			s.dropAudit.addError(LogsPipeline, err, dropped)
 		}
 
 		// If data was sent, cleanup the currentTimeSeries counter
 		if ar.sent {
+			recordRequestRecords(LogsPipeline, len(currentRecords))
 			currentRecords = currentRecords[:0]
 		}
 
@@ -400,9 +643,11 @@ func (s *sender) sendLogs(ctx context.Context, flds fields) ([]logPair, error) {
 	}
 
 	if body.Len() > 0 {
-		if err := s.send(ctx, LogsPipeline, strings.NewReader(body.String()), flds); err != nil {
+		recordRequestRecords(LogsPipeline, len(currentRecords))
+		if err := s.send(ctx, LogsPipeline, strings.NewReader(s.wrapBody(LogsPipeline, body.String())), flds); err != nil {
 			errs = append(errs, err)
 			droppedRecords = append(droppedRecords, currentRecords...)
+			s.dropAudit.addError(LogsPipeline, err, len(currentRecords))
 		}
 	}
 
@@ -416,6 +661,11 @@ func (s *sender) sendLogs(ctx context.Context, flds fields) ([]logPair, error) {
 // it returns an array of records which has not been sent correctly and an error.
 // TODO: add support for HTTP limits
 func (s *sender) sendOTLPLogs(ctx context.Context, flds fields) ([]logPair, error) {
+	if err := ctx.Err(); err != nil {
+		s.dropAudit.addError(LogsPipeline, err, len(s.logBuffer))
+		return s.logBuffer, err
+	}
+
 	ld := pdata.NewLogs()
 	rl := ld.ResourceLogs().AppendEmpty()
 	ill := rl.InstrumentationLibraryLogs().AppendEmpty()
@@ -443,10 +693,13 @@ func (s *sender) sendOTLPLogs(ctx context.Context, flds fields) ([]logPair, erro
 
 	body, err := logsMarshaler.MarshalLogs(ld)
 	if err != nil {
+		s.dropAudit.add(LogsPipeline, dropReasonFormatError, len(s.logBuffer))
 		return s.logBuffer, err
 	}
 
+	recordRequestRecords(LogsPipeline, len(s.logBuffer))
 	if err := s.send(ctx, LogsPipeline, bytes.NewReader(body), flds); err != nil {
+		s.dropAudit.addError(LogsPipeline, err, len(s.logBuffer))
 		return s.logBuffer, err
 	}
 	return nil, nil
@@ -454,6 +707,12 @@ func (s *sender) sendOTLPLogs(ctx context.Context, flds fields) ([]logPair, erro
 
 // sendMetrics sends metrics in right format basing on the s.config.MetricFormat
 func (s *sender) sendMetrics(ctx context.Context, flds fields) ([]metricPair, error) {
+	// Drop the filtered out metrics before they are formatted
+	s.metricBuffer = s.metricFilter.filter(s.metricBuffer)
+	if len(s.metricBuffer) == 0 {
+		return nil, nil
+	}
+
 	// Follow different execution path for OTLP format
 	if s.config.MetricFormat == OTLPMetricFormat {
 		return s.sendOTLPMetrics(ctx, flds)
@@ -466,41 +725,59 @@ func (s *sender) sendMetrics(ctx context.Context, flds fields) ([]metricPair, er
 		currentRecords []metricPair
 	)
 
-	for _, record := range s.metricBuffer {
+	var formatted []formattedMetric
+	if s.metricFormatter != nil {
+		formatted = formatMetrics(s.metricFormatter, s.metricBuffer, s.config.MetricFormatWorkers)
+	}
+
+	for i, record := range s.metricBuffer {
 		var formattedLine string
 		var err error
 
-		switch s.config.MetricFormat {
-		case PrometheusFormat:
-			formattedLine = s.prometheusFormatter.metric2String(record)
-		case Carbon2Format:
-			formattedLine = carbon2Metric2String(record)
-		case GraphiteFormat:
-			formattedLine = s.graphiteFormatter.metric2String(record)
-		default:
+		// When the context is done (e.g. on shutdown), the remaining records are returned
+		// as dropped at once, so they can be requeued, instead of failing to send them
+		// one request after another.
+		if err := ctx.Err(); err != nil {
+			remaining := append(currentRecords, s.metricBuffer[i:]...)
+			droppedRecords = append(droppedRecords, remaining...)
+			errs = append(errs, err)
+			s.dropAudit.addError(MetricsPipeline, err, len(remaining))
+			body.Reset()
+			break
+		}
+
+		if formatted != nil {
+			formattedLine, err = formatted[i].line, formatted[i].err
+		} else {
 			err = fmt.Errorf("unexpected metric format: %s", s.config.MetricFormat)
 		}
 
 		if err != nil {
 			droppedRecords = append(droppedRecords, record)
 			errs = append(errs, err)
+			s.dropAudit.add(MetricsPipeline, dropReasonFormatError, 1)
 			continue
 		}
 
 		ar, err := s.appendAndSend(ctx, formattedLine, MetricsPipeline, &body, flds)
 		if err != nil {
 			errs = append(errs, err)
+			dropped := 0
 			if ar.sent {
 				droppedRecords = append(droppedRecords, currentRecords...)
+				dropped += len(currentRecords)
 			}
 
 			if !ar.appended {
 				droppedRecords = append(droppedRecords, record)
+				dropped++
 			}
+			s.dropAudit.addError(MetricsPipeline, err, dropped)
 		}
 
 		// If data was sent, cleanup the currentTimeSeries counter
 		if ar.sent {
+			recordRequestRecords(MetricsPipeline, len(currentRecords))
 			currentRecords = currentRecords[:0]
 		}
 
@@ -511,9 +788,11 @@ func (s *sender) sendMetrics(ctx context.Context, flds fields) ([]metricPair, er
 	}
 
 	if body.Len() > 0 {
+		recordRequestRecords(MetricsPipeline, len(currentRecords))
 		if err := s.send(ctx, MetricsPipeline, strings.NewReader(body.String()), flds); err != nil {
 			errs = append(errs, err)
 			droppedRecords = append(droppedRecords, currentRecords...)
+			s.dropAudit.addError(MetricsPipeline, err, len(currentRecords))
 		}
 	}
 
@@ -527,6 +806,11 @@ func (s *sender) sendMetrics(ctx context.Context, flds fields) ([]metricPair, er
 // it returns an array of records which has not been sent correctly and an error.
 // TODO: add support for HTTP limits
 func (s *sender) sendOTLPMetrics(ctx context.Context, flds fields) ([]metricPair, error) {
+	if err := ctx.Err(); err != nil {
+		s.dropAudit.addError(MetricsPipeline, err, len(s.metricBuffer))
+		return s.metricBuffer, err
+	}
+
 	md := pdata.NewMetrics()
 	rms := md.ResourceMetrics()
 	rms.EnsureCapacity(len(s.metricBuffer))
@@ -541,10 +825,13 @@ func (s *sender) sendOTLPMetrics(ctx context.Context, flds fields) ([]metricPair
 
 	body, err := metricsMarshaler.MarshalMetrics(md)
 	if err != nil {
+		s.dropAudit.add(MetricsPipeline, dropReasonFormatError, len(s.metricBuffer))
 		return s.metricBuffer, err
 	}
 
+	recordRequestRecords(MetricsPipeline, len(s.metricBuffer))
 	if err := s.send(ctx, MetricsPipeline, bytes.NewReader(body), flds); err != nil {
+		s.dropAudit.addError(MetricsPipeline, err, len(s.metricBuffer))
 		return s.metricBuffer, err
 	}
 	return nil, nil
@@ -563,17 +850,18 @@ func (s *sender) appendAndSend(
 	var errors []error
 	ar := newAppendResponse()
 
-	if body.Len() > 0 && body.Len()+len(line) >= s.config.MaxRequestBodySize {
+	separator := s.lineSeparator(pipeline)
+	if body.Len() > 0 && s.requestSize(pipeline, body.Len()+len(line)+s.wrapOverhead(pipeline)) >= s.maxRequestBodySize(pipeline) {
 		ar.sent = true
-		if err := s.send(ctx, pipeline, strings.NewReader(body.String()), flds); err != nil {
+		if err := s.send(ctx, pipeline, strings.NewReader(s.wrapBody(pipeline, body.String())), flds); err != nil {
 			errors = append(errors, err)
 		}
 		body.Reset()
 	}
 
 	if body.Len() > 0 {
-		// Do not add newline if the body is empty
-		if _, err := body.WriteString("\n"); err != nil {
+		// Do not add separator if the body is empty
+		if _, err := body.WriteString(separator); err != nil {
 			errors = append(errors, err)
 			ar.appended = false
 		}
@@ -593,28 +881,165 @@ func (s *sender) appendAndSend(
 	return ar, nil
 }
 
+// isJSONArray returns true if the data of the pipeline is sent as a single JSON array
+func (s *sender) isJSONArray(pipeline PipelineType) bool {
+	return pipeline == LogsPipeline && s.config.LogFormat == JSONArrayFormat
+}
+
+// lineSeparator returns the separator of the formatted lines in the request body
+func (s *sender) lineSeparator(pipeline PipelineType) string {
+	if s.isJSONArray(pipeline) {
+		return ","
+	}
+	return "\n"
+}
+
+// wrapOverhead returns the number of bytes wrapBody adds to the request body
+func (s *sender) wrapOverhead(pipeline PipelineType) int {
+	if s.isJSONArray(pipeline) {
+		return len("[]")
+	}
+	return 0
+}
+
+// wrapBody returns the request body for the joined formatted lines,
+// for JSON array it wraps the lines in brackets
+func (s *sender) wrapBody(pipeline PipelineType, body string) string {
+	if s.isJSONArray(pipeline) {
+		return "[" + body + "]"
+	}
+	return body
+}
+
 // sendTraces sends traces in right format basing on the s.config.TraceFormat
 func (s *sender) sendTraces(ctx context.Context, td pdata.Traces, flds fields) error {
-	if s.config.TraceFormat == OTLPTraceFormat {
+	switch s.config.TraceFormat {
+	case OTLPTraceFormat:
 		return s.sendOTLPTraces(ctx, td, flds)
+	case TextTraceFormat:
+		return s.sendTextTraces(ctx, td, flds)
+	}
+	return nil
+}
+
+// sendTextTraces sends the spans rendered as text lines, one per span, to the logs URL.
+// The spans which were not sent are returned in the error.
+func (s *sender) sendTextTraces(ctx context.Context, td pdata.Traces, flds fields) error {
+	var (
+		body         strings.Builder
+		errs         []error
+		failedSpans  []spanRef
+		currentSpans []spanRef
+	)
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				ref := spanRef{rs: rs, ils: ils, span: spans.At(k)}
+
+				ar, err := s.appendAndSend(ctx, spanToText(rs.Resource(), ref.span), TracesPipeline, &body, flds)
+				if err != nil {
+					errs = append(errs, err)
+					dropped := 0
+					if ar.sent {
+						failedSpans = append(failedSpans, currentSpans...)
+						dropped += len(currentSpans)
+					}
+					if !ar.appended {
+						failedSpans = append(failedSpans, ref)
+						dropped++
+					}
+					s.dropAudit.addError(TracesPipeline, err, dropped)
+				}
+
+				if ar.sent {
+					recordRequestRecords(TracesPipeline, len(currentSpans))
+					currentSpans = currentSpans[:0]
+				}
+				if ar.appended {
+					currentSpans = append(currentSpans, ref)
+				}
+			}
+		}
+	}
+
+	if body.Len() > 0 {
+		recordRequestRecords(TracesPipeline, len(currentSpans))
+		if err := s.send(ctx, TracesPipeline, strings.NewReader(body.String()), flds); err != nil {
+			errs = append(errs, err)
+			failedSpans = append(failedSpans, currentSpans...)
+			s.dropAudit.addError(TracesPipeline, err, len(currentSpans))
+		}
+	}
+
+	if len(errs) > 0 {
+		return consumererror.NewTraces(multierr.Combine(errs...), spanRefsToTraces(failedSpans))
 	}
 	return nil
 }
 
-// sendOTLPTraces sends trace records in OTLP format
+// sendOTLPTraces sends trace records in OTLP format. The traces exceeding the max request
+// body size are split by resource spans and, when enabled, the requests are retried
+// within the budget shared by all of them. The traces which were not sent
+// are returned in the error.
 func (s *sender) sendOTLPTraces(ctx context.Context, td pdata.Traces, flds fields) error {
 	for i := 0; i < td.ResourceSpans().Len(); i++ {
 		s.addResourceAttributes(td.ResourceSpans().At(i).Resource().Attributes(), flds)
 	}
 
-	body, err := tracesMarshaler.MarshalTraces(td)
-	if err != nil {
-		return err
+	var budget *retryBudget
+	if s.config.TracesRetry.Enabled {
+		budget = newRetryBudget(s.config.TracesRetry, s.logger)
 	}
-	if err := s.send(ctx, TracesPipeline, bytes.NewReader(body), flds); err != nil {
-		return err
+
+	var (
+		errs   []error
+		failed []pdata.Traces
+	)
+	chunks := splitTraces(td, s.maxRequestBodySize(TracesPipeline))
+	for _, chunk := range chunks {
+		body, err := tracesMarshaler.MarshalTraces(chunk)
+		if err != nil {
+			s.dropAudit.add(TracesPipeline, dropReasonFormatError, chunk.SpanCount())
+			errs = append(errs, err)
+			failed = append(failed, chunk)
+			continue
+		}
+
+		recordRequestRecords(TracesPipeline, chunk.SpanCount())
+		send := func() error {
+			return s.send(ctx, TracesPipeline, bytes.NewReader(body), flds)
+		}
+		if budget != nil {
+			err = budget.do(ctx, send)
+		} else {
+			err = send()
+		}
+		if err != nil {
+			s.dropAudit.addError(TracesPipeline, err, chunk.SpanCount())
+			errs = append(errs, err)
+			failed = append(failed, chunk)
+		}
 	}
-	return nil
+
+	if len(errs) == 0 {
+		return nil
+	}
+	if len(chunks) == 1 {
+		return errs[0]
+	}
+
+	// Only the chunks which were not sent are retried
+	failedTraces := pdata.NewTraces()
+	for _, chunk := range failed {
+		chunk.ResourceSpans().MoveAndAppendTo(failedTraces.ResourceSpans())
+	}
+	return consumererror.NewTraces(multierr.Combine(errs...), failedTraces)
 }
 
 // cleanLogsBuffer zeroes logBuffer
@@ -649,6 +1074,11 @@ func (s *sender) cleanMetricBuffer() {
 // batchMetric adds metric to the metricBuffer and flushes them if metricBuffer is full to avoid overflow
 // returns list of metric records which were not sent successfully
 func (s *sender) batchMetric(ctx context.Context, metric metricPair, metadata fields) ([]metricPair, error) {
+	// Prometheus formatter sanitizes the metric names, so only the other formats are validated
+	if s.config.DryRun && s.config.MetricFormat != PrometheusFormat {
+		s.reportViolations(MetricsPipeline, validateMetricName(metric.metric.Name()))
+	}
+
 	s.metricBuffer = append(s.metricBuffer, metric)
 
 	if s.countMetrics() >= maxBufferSize {
@@ -680,8 +1110,8 @@ func addCompressHeader(req *http.Request, enc CompressEncodingType) error {
 }
 
 func addSourcesHeaders(req *http.Request, sources sourceFormats, flds fields) {
-	if sources.host.isSet() {
-		req.Header.Add(headerHost, sources.host.format(flds))
+	if host, ok := sources.formatHost(flds); ok {
+		req.Header.Add(headerHost, host)
 	}
 
 	if sources.name.isSet() {
@@ -689,7 +1119,7 @@ func addSourcesHeaders(req *http.Request, sources sourceFormats, flds fields) {
 	}
 
 	if sources.category.isSet() {
-		req.Header.Add(headerCategory, sources.category.format(flds))
+		req.Header.Add(headerCategory, sources.formatCategory(flds))
 	}
 }
 
@@ -697,6 +1127,8 @@ func addLogsHeaders(req *http.Request, lf LogFormatType, flds fields) {
 	switch lf {
 	case OTLPLogFormat:
 		req.Header.Add(headerContentType, contentTypeOTLP)
+	case JSONArrayFormat:
+		req.Header.Add(headerContentType, contentTypeJSON)
 	default:
 		req.Header.Add(headerContentType, contentTypeLogs)
 	}
@@ -706,16 +1138,12 @@ func addLogsHeaders(req *http.Request, lf LogFormatType, flds fields) {
 	}
 }
 
-func addMetricsHeaders(req *http.Request, mf MetricFormatType) error {
-	switch mf {
-	case PrometheusFormat:
-		req.Header.Add(headerContentType, contentTypePrometheus)
-	case Carbon2Format:
-		req.Header.Add(headerContentType, contentTypeCarbon2)
-	case GraphiteFormat:
-		req.Header.Add(headerContentType, contentTypeGraphite)
-	case OTLPMetricFormat:
+func addMetricsHeaders(req *http.Request, mf MetricFormatType, formatter MetricFormatter) error {
+	switch {
+	case mf == OTLPMetricFormat:
 		req.Header.Add(headerContentType, contentTypeOTLP)
+	case formatter != nil:
+		req.Header.Add(headerContentType, formatter.ContentType())
 	default:
 		return fmt.Errorf("unsupported metrics format: %s", mf)
 	}
@@ -726,6 +1154,8 @@ func addTracesHeaders(req *http.Request, tf TraceFormatType) error {
 	switch tf {
 	case OTLPTraceFormat:
 		req.Header.Add(headerContentType, contentTypeOTLP)
+	case TextTraceFormat:
+		req.Header.Add(headerContentType, contentTypeLogs)
 	default:
 		return fmt.Errorf("unsupported traces format: %s", tf)
 	}
@@ -734,6 +1164,9 @@ func addTracesHeaders(req *http.Request, tf TraceFormatType) error {
 
 func (s *sender) addRequestHeaders(req *http.Request, pipeline PipelineType, flds fields) error {
 	req.Header.Add(headerClient, s.config.Client)
+	if s.userAgent != "" {
+		req.Header.Set(headerUserAgent, s.userAgent)
+	}
 
 	if err := addCompressHeader(req, s.config.CompressEncoding); err != nil {
 		return err
@@ -744,7 +1177,7 @@ func (s *sender) addRequestHeaders(req *http.Request, pipeline PipelineType, fld
 	case LogsPipeline:
 		addLogsHeaders(req, s.config.LogFormat, flds)
 	case MetricsPipeline:
-		if err := addMetricsHeaders(req, s.config.MetricFormat); err != nil {
+		if err := addMetricsHeaders(req, s.config.MetricFormat, s.metricFormatter); err != nil {
 			return err
 		}
 	case TracesPipeline:
@@ -758,13 +1191,17 @@ func (s *sender) addRequestHeaders(req *http.Request, pipeline PipelineType, fld
 }
 
 func (s *sender) addResourceAttributes(attrs pdata.AttributeMap, flds fields) {
-	if s.sources.host.isSet() {
-		attrs.InsertString(attributeKeySourceHost, s.sources.host.format(flds))
+	if !s.config.AddSourceResourceAttributes {
+		return
+	}
+
+	if host, ok := s.sources.formatHost(flds); ok {
+		attrs.InsertString(attributeKeySourceHost, host)
 	}
 	if s.sources.name.isSet() {
 		attrs.InsertString(attributeKeySourceName, s.sources.name.format(flds))
 	}
 	if s.sources.category.isSet() {
-		attrs.InsertString(attributeKeySourceCategory, s.sources.category.format(flds))
+		attrs.InsertString(attributeKeySourceCategory, s.sources.formatCategory(flds))
 	}
 }
@@ -18,19 +18,24 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/model/otlp"
 	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -76,10 +81,10 @@ func prepareSenderTest(t *testing.T, cb []func(w http.ResponseWriter, req *http.
 	c, err := newCompressor(cfg.CompressEncoding)
 	require.NoError(t, err)
 
-	pf, err := newPrometheusFormatter()
+	mf, err := newMetricFormatter(cfg)
 	require.NoError(t, err)
 
-	gf, err := newGraphiteFormatter(cfg.GraphiteTemplate)
+	mfl, err := newMetricFilter(cfg.MetricFilters)
 	require.NoError(t, err)
 
 	logger, err := zap.NewDevelopment()
@@ -101,11 +106,14 @@ func prepareSenderTest(t *testing.T, cb []func(w http.ResponseWriter, req *http.
 				name:     getTestSourceFormat(t, "source_name"),
 			},
 			c,
-			pf,
-			gf,
+			mf,
 			"",
 			"",
 			"",
+			senderDeps{
+				metricFilter:    mfl,
+				textLogTemplate: newTextLogTemplate(cfg.TextLogTemplate, cfg.TextFormatBodyHandling, cfg.JSONLogs.LogKey),
+			},
 		),
 	}
 }
@@ -137,10 +145,10 @@ func prepareOTLPSenderTest(t *testing.T, cb []func(w http.ResponseWriter, req *h
 	c, err := newCompressor(cfg.CompressEncoding)
 	require.NoError(t, err)
 
-	pf, err := newPrometheusFormatter()
+	mf, err := newMetricFormatter(cfg)
 	require.NoError(t, err)
 
-	gf, err := newGraphiteFormatter(cfg.GraphiteTemplate)
+	mfl, err := newMetricFilter(cfg.MetricFilters)
 	require.NoError(t, err)
 
 	logger, err := zap.NewDevelopment()
@@ -162,11 +170,11 @@ func prepareOTLPSenderTest(t *testing.T, cb []func(w http.ResponseWriter, req *h
 				name:     getTestSourceFormat(t, "source_name"),
 			},
 			c,
-			pf,
-			gf,
+			mf,
 			testServer.URL,
 			testServer.URL,
 			testServer.URL,
+			senderDeps{metricFilter: mfl},
 		),
 	}
 }
@@ -233,6 +241,18 @@ func exampleLogWithComplexBody() []pdata.LogRecord {
 	return buffer
 }
 
+func exampleLogWithDroppedAttributes() []pdata.LogRecord {
+	buffer := make([]pdata.LogRecord, 1)
+	buffer[0] = pdata.NewLogRecord()
+	buffer[0].Body().SetStringVal("Example log")
+	buffer[0].Attributes().InsertString("key1", "value1")
+	buffer[0].Attributes().InsertString("key2", "value2")
+	buffer[0].Attributes().InsertString("key3", "value3")
+	buffer[0].SetDroppedAttributesCount(4)
+
+	return buffer
+}
+
 func exampleTwoDifferentLogs() []pdata.LogRecord {
 	buffer := make([]pdata.LogRecord, 2)
 	buffer[0] = pdata.NewLogRecord()
@@ -247,6 +267,28 @@ func exampleTwoDifferentLogs() []pdata.LogRecord {
 	return buffer
 }
 
+func exampleLogWithNestedAttributes() []pdata.LogRecord {
+	buffer := make([]pdata.LogRecord, 1)
+	buffer[0] = pdata.NewLogRecord()
+	buffer[0].Body().SetStringVal("Example log")
+
+	attrs := buffer[0].Attributes()
+	arr := pdata.NewAttributeValueArray()
+	nestedArr := arr.SliceVal().AppendEmpty()
+	pdata.NewAttributeValueArray().CopyTo(nestedArr)
+	nestedArr.SliceVal().AppendEmpty().SetStringVal("b")
+	nestedArr.SliceVal().AppendEmpty().SetIntVal(1)
+	nestedMap := arr.SliceVal().AppendEmpty()
+	pdata.NewAttributeValueMap().CopyTo(nestedMap)
+	nestedMap.MapVal().InsertBool("c", true)
+	attrs.Insert("a", arr)
+	attrs.InsertDouble("d", math.NaN())
+	attrs.Insert("e", pdata.NewAttributeValueBytes([]byte("foo")))
+	attrs.Insert("f", pdata.NewAttributeValueEmpty())
+
+	return buffer
+}
+
 func exampleMultitypeLogs() []pdata.LogRecord {
 	buffer := make([]pdata.LogRecord, 2)
 
@@ -313,6 +355,154 @@ func TestSendTrace(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSendTracePreservesEventsAndLinks(t *testing.T) {
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	resource, span := exampleSpanWithEventsAndLinks()
+	resource.CopyTo(rs.Resource())
+	span.CopyTo(rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty())
+
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			received, err := otlp.NewProtobufTracesUnmarshaler().UnmarshalTraces([]byte(extractBody(t, req)))
+			require.NoError(t, err)
+			require.Equal(t, 1, received.SpanCount())
+
+			receivedSpan := received.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+			assert.Equal(t, span.Events(), receivedSpan.Events())
+			assert.Equal(t, span.Links(), receivedSpan.Links())
+			assert.EqualValues(t, 2, receivedSpan.DroppedEventsCount())
+		},
+	})
+
+	err := test.s.sendTraces(context.Background(), td, fieldsFromMap(map[string]string{}))
+	assert.NoError(t, err)
+}
+
+func TestSendTextTraces(t *testing.T) {
+	td := exampleTrace()
+	rs := td.ResourceSpans().At(0)
+	expected := spanToText(rs.Resource(), rs.InstrumentationLibrarySpans().At(0).Spans().At(0))
+
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, expected, extractBody(t, req))
+			assert.Equal(t, "application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+		},
+	}, func(cfg *Config) {
+		cfg.TraceFormat = TextTraceFormat
+	})
+
+	err := test.s.sendTraces(context.Background(), td, fieldsFromMap(map[string]string{}))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(test.reqCounter))
+}
+
+func TestSendTextTracesFailed(t *testing.T) {
+	td := exampleTrace()
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.At(0).CopyTo(spans.AppendEmpty())
+	spans.At(1).SetName("failedSpan")
+
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			assert.Contains(t, extractBody(t, req), "name=testSpan")
+		},
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+	}, func(cfg *Config) {
+		cfg.TraceFormat = TextTraceFormat
+		// every span is sent in a separate request
+		cfg.MaxRequestBodySize = 1
+	})
+
+	err := test.s.sendTraces(context.Background(), td, fieldsFromMap(map[string]string{}))
+	var tracesErr consumererror.Traces
+	require.ErrorAs(t, err, &tracesErr)
+	failed := tracesErr.GetTraces()
+	require.Equal(t, 1, failed.SpanCount())
+	assert.Equal(t, "failedSpan", failed.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+	assert.EqualValues(t, 2, atomic.LoadInt32(test.reqCounter))
+}
+
+func TestSendTextTracesToLogsURL(t *testing.T) {
+	test := prepareSenderTest(t, nil, func(cfg *Config) {
+		cfg.HTTPClientSettings.Endpoint = ""
+		cfg.TraceFormat = TextTraceFormat
+	})
+	test.s.dataUrlLogs = "https://logs.example.com"
+	test.s.dataUrlTraces = "https://traces.example.com"
+
+	req, err := test.s.createRequest(context.Background(), TracesPipeline, strings.NewReader(""), fieldsFromMap(map[string]string{}))
+	require.NoError(t, err)
+	assert.Equal(t, "https://logs.example.com", req.URL.String())
+}
+
+func TestSendTraceRetried(t *testing.T) {
+	td := exampleTrace()
+	traceBody, err := tracesMarshaler.MarshalTraces(td)
+	require.NoError(t, err)
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+		func(w http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, string(traceBody), extractBody(t, req))
+		},
+	}, func(cfg *Config) {
+		cfg.TracesRetry.Enabled = true
+		cfg.TracesRetry.InitialInterval = time.Millisecond
+		cfg.TracesRetry.MaxInterval = time.Millisecond
+	})
+
+	err = test.s.sendTraces(context.Background(), td, fieldsFromMap(map[string]string{}))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(test.reqCounter))
+}
+
+func TestSendTraceNotRetriedOnClientError(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		},
+	}, func(cfg *Config) {
+		cfg.TracesRetry.Enabled = true
+		cfg.TracesRetry.InitialInterval = time.Millisecond
+	})
+
+	err := test.s.sendTraces(context.Background(), exampleTrace(), fieldsFromMap(map[string]string{}))
+	assert.EqualError(t, err, "failed sending data: status: 400 Bad Request")
+	assert.EqualValues(t, 1, atomic.LoadInt32(test.reqCounter))
+}
+
+func TestSendTraceSplit(t *testing.T) {
+	td := exampleTrace()
+	exampleTrace().ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	exampleTrace().ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	singleSize := tracesSizer.TracesSize(exampleTrace())
+
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			assert.Len(t, extractBody(t, req), 2*singleSize)
+		},
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+	}, func(cfg *Config) {
+		cfg.MaxRequestBodySize = 2 * singleSize
+	})
+
+	err := test.s.sendTraces(context.Background(), td, fieldsFromMap(map[string]string{}))
+	var tracesErr consumererror.Traces
+	require.ErrorAs(t, err, &tracesErr)
+	assert.Equal(t, 1, tracesErr.GetTraces().SpanCount())
+	assert.EqualValues(t, 2, atomic.LoadInt32(test.reqCounter))
+}
+
 func TestSendLogs(t *testing.T) {
 	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
 		func(w http.ResponseWriter, req *http.Request) {
@@ -331,6 +521,121 @@ func TestSendLogs(t *testing.T) {
 	assert.EqualValues(t, 1, *test.reqCounter)
 }
 
+func TestSendLogsTextTemplate(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			assert.Equal(t, "WARN value1: Example log\nundefined value1: Another example log", body)
+		},
+	}, func(cfg *Config) {
+		cfg.TextLogTemplate = "%{severity} %{key1}: %{body}"
+	})
+
+	logs := exampleTwoLogs()
+	logs[0].SetSeverityNumber(pdata.SeverityNumberWARN)
+	test.s.logBuffer = logRecordsToLogPair(logs)
+
+	_, err := test.s.sendLogs(context.Background(), fieldsFromMap(map[string]string{}))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, *test.reqCounter)
+}
+
+func TestSendLogsAdaptiveBatching(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, "Example log\nAnother example log", extractBody(t, req))
+		},
+	}, func(cfg *Config) {
+		cfg.CompressEncoding = NoCompression
+		cfg.MaxRequestBodySize = 1_000_000
+	})
+	test.s.batchSizeTuner = newBatchSizeTuner(AdaptiveBatchingConfig{
+		Enabled:           true,
+		TargetPayloadSize: 100_000,
+		WindowSize:        minAdaptiveBatchingWindowSize,
+	}, 1_000_000, zap.NewNop())
+
+	// the payload sizes of the sent requests are observed
+	test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+	_, err := test.s.sendLogs(context.Background(), fieldsFromMap(map[string]string{}))
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{int64(len("Example log\nAnother example log"))}, test.s.batchSizeTuner.pipelines[LogsPipeline].sizes)
+
+	// the tuned limit is used when it's lower than max_request_body_size
+	for i := 1; i < minAdaptiveBatchingWindowSize; i++ {
+		test.s.batchSizeTuner.observe(LogsPipeline, 400_000)
+	}
+	assert.Equal(t, 500_000, test.s.maxRequestBodySize(LogsPipeline))
+	assert.Equal(t, 1_000_000, test.s.maxRequestBodySize(MetricsPipeline))
+}
+
+func TestSendLogsThrottled(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+	})
+	test.s.throttling = newThrottling(ThrottlingConfig{
+		PauseSends:    true,
+		DefaultPeriod: DefaultThrottlingDefaultPeriod,
+		MaxPeriod:     DefaultThrottlingMaxPeriod,
+	}, zap.NewNop())
+	flds := fieldsFromMap(map[string]string{})
+
+	test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+	_, err := test.s.sendLogs(context.Background(), flds)
+	var se *statusError
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, http.StatusTooManyRequests, se.statusCode)
+
+	// the throttled pipeline is paused, while the other pipelines keep sending
+	test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+	_, err = test.s.sendLogs(context.Background(), flds)
+	assert.ErrorIs(t, err, errThrottled)
+	assert.EqualValues(t, 1, atomic.LoadInt32(test.reqCounter))
+	assert.NoError(t, test.s.throttling.check(MetricsPipeline))
+}
+
+func TestSendLogsPropagateTraceContext(t *testing.T) {
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	testcases := []struct {
+		name     string
+		enabled  bool
+		expected string
+	}{
+		{name: "enabled", enabled: true, expected: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "disabled", enabled: false, expected: ""},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+				func(w http.ResponseWriter, req *http.Request) {
+					assert.Equal(t, tc.expected, req.Header.Get("traceparent"))
+				},
+			}, func(cfg *Config) {
+				cfg.PropagateTraceContext = tc.enabled
+			})
+
+			test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+			_, err := test.s.sendLogs(ctx, fieldsFromMap(map[string]string{}))
+			assert.NoError(t, err)
+			assert.EqualValues(t, 1, *test.reqCounter)
+		})
+	}
+}
+
 func TestSendLogsWithEmptyField(t *testing.T) {
 	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
 		func(w http.ResponseWriter, req *http.Request) {
@@ -390,6 +695,50 @@ func TestSendLogsSplit(t *testing.T) {
 	assert.EqualValues(t, 2, *test.reqCounter)
 }
 
+func TestSendLogsSplitWithPayloadSizeEstimation(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			assert.Equal(t, "Example log\nAnother example log", body)
+		},
+	})
+	// the body of 31 bytes is estimated to 15 bytes after compression,
+	// so both logs are sent in a single request
+	test.s.config.MaxRequestBodySize = 20
+	test.s.sizeEstimator = newPayloadSizeEstimator(PayloadSizeEstimationConfig{InitialCompressionRatio: 1})
+	test.s.sizeEstimator.pipelines[LogsPipeline] = &payloadSizeEstimate{ratio: 0.5}
+	test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+
+	_, err := test.s.sendLogs(context.Background(), newFields(pdata.NewAttributeMap()))
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, 1, *test.reqCounter)
+}
+
+func TestSendLogsRetryBudgetExhausted(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+	})
+	test.s.sharedBudget = newSharedRetryBudget(RetryBudgetConfig{MaxConcurrentRetries: 1, MaxRetryBytes: 1000}, zap.NewNop())
+	test.s.logBuffer = logRecordsToLogPair(exampleLog())
+
+	_, err := test.s.sendLogs(context.Background(), newFields(pdata.NewAttributeMap()))
+	require.Error(t, err)
+	assert.EqualValues(t, 1, *test.reqCounter)
+
+	// the failing metrics pipeline takes the whole budget
+	test.s.sharedBudget.onResult(MetricsPipeline, true)
+	_, err = test.s.sharedBudget.acquire(MetricsPipeline, 10)
+	require.NoError(t, err)
+
+	dropped, err := test.s.sendLogs(context.Background(), newFields(pdata.NewAttributeMap()))
+	assert.ErrorIs(t, err, errRetryBudgetExhausted)
+	assert.Len(t, dropped, 1)
+	assert.EqualValues(t, 1, *test.reqCounter, "the retry should not be sent")
+}
+
 func TestSendLogsSplitFailedOne(t *testing.T) {
 	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
 		func(w http.ResponseWriter, req *http.Request) {
@@ -554,6 +903,73 @@ func TestSendLogsJsonConfig(t *testing.T) {
 				`"g":{"h":"i","j":false,"k":12,"l":11.1}},"m":"n","timestamp":\d{13}}`,
 			logBuffer: logRecordsToLogPair(exampleLogWithComplexBody()),
 		},
+		{
+			name: "dropped attributes count",
+			configOpts: []func(*Config){
+				func(c *Config) {
+					c.JSONLogs = JSONLogs{
+						LogKey:       DefaultLogKey,
+						AddTimestamp: DefaultAddTimestamp,
+						TimestampKey: DefaultTimestampKey,
+						FlattenBody:  DefaultFlattenBody,
+					}
+				},
+			},
+			bodyRegex: `{"dropped_attributes_count":4,"key1":"value1","key2":"value2","key3":"value3",` +
+				`"log":"Example log","timestamp":\d{13}}`,
+			logBuffer: logRecordsToLogPair(exampleLogWithDroppedAttributes()),
+		},
+		{
+			name: "dropped attributes count with the attribute of the same name",
+			configOpts: []func(*Config){
+				func(c *Config) {
+					c.JSONLogs = JSONLogs{
+						LogKey:       DefaultLogKey,
+						AddTimestamp: DefaultAddTimestamp,
+						TimestampKey: DefaultTimestampKey,
+						FlattenBody:  DefaultFlattenBody,
+					}
+				},
+			},
+			bodyRegex: `{"dropped_attributes_count":"user value","key1":"value1","key2":"value2","key3":"value3",` +
+				`"log":"Example log","timestamp":\d{13}}`,
+			logBuffer: func() []logPair {
+				logs := exampleLogWithDroppedAttributes()
+				logs[0].Attributes().InsertString("dropped_attributes_count", "user value")
+				return logRecordsToLogPair(logs)
+			}(),
+		},
+		{
+			name: "max attributes",
+			configOpts: []func(*Config){
+				func(c *Config) {
+					c.JSONLogs = JSONLogs{
+						LogKey:        DefaultLogKey,
+						AddTimestamp:  DefaultAddTimestamp,
+						TimestampKey:  DefaultTimestampKey,
+						FlattenBody:   DefaultFlattenBody,
+						MaxAttributes: 2,
+					}
+				},
+			},
+			bodyRegex: `{"dropped_attributes_count":4,"key1":"value1","key2":"value2",` +
+				`"log":"Example log","sumo.truncated":true,"timestamp":\d{13}}`,
+			logBuffer: logRecordsToLogPair(exampleLogWithDroppedAttributes()),
+		},
+		{
+			name: "preserve types",
+			configOpts: []func(*Config){
+				func(c *Config) {
+					c.JSONLogs = JSONLogs{
+						LogKey:        DefaultLogKey,
+						AddTimestamp:  false,
+						PreserveTypes: true,
+					}
+				},
+			},
+			bodyRegex: `^{"a":\[\["b",1\],{"c":true}\],"d":"NaN","e":"Zm9v","f":null,"log":"Example log"}$`,
+			logBuffer: logRecordsToLogPair(exampleLogWithNestedAttributes()),
+		},
 	}
 
 	for _, tc := range testcases {
@@ -715,6 +1131,60 @@ func TestSendLogsJsonSplitFailedAll(t *testing.T) {
 	assert.EqualValues(t, 2, *test.reqCounter)
 }
 
+func TestSendLogsJsonArray(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			var regex string
+			regex += `^\[`
+			regex += `{"key1":"value1","key2":"value2","log":"Example log","timestamp":\d{13}}`
+			regex += `,`
+			regex += `{"key1":"value1","key2":"value2","log":"Another example log","timestamp":\d{13}}`
+			regex += `\]$`
+			assert.Regexp(t, regex, body)
+
+			var records []map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(body), &records))
+			assert.Len(t, records, 2)
+
+			assert.Equal(t, "key=value", req.Header.Get("X-Sumo-Fields"))
+			assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		},
+	})
+	test.s.config.LogFormat = JSONArrayFormat
+	test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+
+	_, err := test.s.sendLogs(context.Background(), fieldsFromMap(map[string]string{"key": "value"}))
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, 1, *test.reqCounter)
+}
+
+func TestSendLogsJsonArraySplit(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			var regex string
+			regex += `^\[{"key1":"value1","key2":"value2","log":"Example log","timestamp":\d{13}}\]$`
+			assert.Regexp(t, regex, body)
+		},
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			var regex string
+			regex += `^\[{"key1":"value1","key2":"value2","log":"Another example log","timestamp":\d{13}}\]$`
+			assert.Regexp(t, regex, body)
+		},
+	})
+	test.s.config.LogFormat = JSONArrayFormat
+	test.s.config.MaxRequestBodySize = 10
+	test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+
+	_, err := test.s.sendLogs(context.Background(), newFields(pdata.NewAttributeMap()))
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, 2, *test.reqCounter)
+}
+
 func TestSendLogsUnexpectedFormat(t *testing.T) {
 	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
 		func(w http.ResponseWriter, req *http.Request) {
@@ -750,6 +1220,37 @@ func TestSendLogsOTLP(t *testing.T) {
 	assert.EqualValues(t, 1, *test.reqCounter)
 }
 
+func TestSendLogsOTLPWithoutSourceResourceAttributes(t *testing.T) {
+	test := prepareOTLPSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			unmarshaller := otlp.NewProtobufLogsUnmarshaler()
+			b, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			l, err := unmarshaller.UnmarshalLogs(b)
+			require.NoError(t, err)
+
+			require.Equal(t, l.ResourceLogs().Len(), 1)
+			attrs := l.ResourceLogs().At(0).Resource().Attributes()
+			for _, key := range []string{"_sourceHost", "_sourceName", "_sourceCategory"} {
+				_, ok := attrs.Get(key)
+				assert.False(t, ok, "unexpected resource attribute %s", key)
+			}
+
+			assert.Equal(t, "source_host", req.Header.Get("X-Sumo-Host"))
+			assert.Equal(t, "source_name", req.Header.Get("X-Sumo-Name"))
+			assert.Equal(t, "source_category", req.Header.Get("X-Sumo-Category"))
+		},
+	})
+
+	test.s.config.AddSourceResourceAttributes = false
+	test.s.logBuffer = logRecordsToLogPair(exampleLog())
+
+	_, err := test.s.sendLogs(context.Background(), newFields(pdata.NewAttributeMap()))
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, 1, *test.reqCounter)
+}
+
 func TestOverrideSourceName(t *testing.T) {
 	t.Run("text format", func(t *testing.T) {
 		test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
@@ -827,6 +1328,22 @@ func TestOverrideSourceCategory(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("prefix", func(t *testing.T) {
+		test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+			func(w http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, "prod/emea/Test source category/test/name", req.Header.Get("X-Sumo-Category"))
+			},
+		})
+
+		test.s.sources.category = getTestSourceFormat(t, "Test source category/%{key1}")
+		test.s.sources.categoryPrefix = "prod-emea-"
+		test.s.sources.categoryDashReplacer = strings.NewReplacer("-", "/")
+		test.s.logBuffer = logRecordsToLogPair(exampleLog())
+
+		_, err := test.s.sendLogs(context.Background(), fieldsFromMap(map[string]string{"key1": "test-name"}))
+		assert.NoError(t, err)
+	})
+
 	t.Run("json format", func(t *testing.T) {
 		test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
 			func(w http.ResponseWriter, req *http.Request) {
@@ -1151,6 +1668,7 @@ func TestInvalidMetricFormat(t *testing.T) {
 	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){})
 
 	test.s.config.MetricFormat = "invalid"
+	test.s.metricFormatter = nil
 
 	err := test.s.send(context.Background(), MetricsPipeline, strings.NewReader(""), newFields(pdata.NewAttributeMap()))
 	assert.EqualError(t, err, `unsupported metrics format: invalid`)
@@ -1256,6 +1774,7 @@ gauge_metric_name{foo="bar",remote_name="156955",url="http://another_url"} 245 1
 	})
 
 	test.s.config.MetricFormat = PrometheusFormat
+	test.s.metricFormatter = getTestMetricFormatter(t, test.s.config)
 	test.s.metricBuffer = []metricPair{
 		exampleIntMetric(),
 		exampleIntGaugeMetric(),
@@ -1280,6 +1799,7 @@ gauge_metric_name{foo="bar",remote_name="156955",url="http://another_url"} 245 1
 	})
 	test.s.config.MaxRequestBodySize = 10
 	test.s.config.MetricFormat = PrometheusFormat
+	test.s.metricFormatter = getTestMetricFormatter(t, test.s.config)
 	test.s.metricBuffer = []metricPair{
 		exampleIntMetric(),
 		exampleIntGaugeMetric(),
@@ -1307,6 +1827,7 @@ gauge_metric_name{foo="bar",remote_name="156955",url="http://another_url"} 245 1
 	})
 	test.s.config.MaxRequestBodySize = 10
 	test.s.config.MetricFormat = PrometheusFormat
+	test.s.metricFormatter = getTestMetricFormatter(t, test.s.config)
 	test.s.metricBuffer = []metricPair{
 		exampleIntMetric(),
 		exampleIntGaugeMetric(),
@@ -1337,6 +1858,7 @@ gauge_metric_name{foo="bar",remote_name="156955",url="http://another_url"} 245 1
 	})
 	test.s.config.MaxRequestBodySize = 10
 	test.s.config.MetricFormat = PrometheusFormat
+	test.s.metricFormatter = getTestMetricFormatter(t, test.s.config)
 	test.s.metricBuffer = []metricPair{
 		exampleIntMetric(),
 		exampleIntGaugeMetric(),
@@ -1357,6 +1879,7 @@ func TestSendMetricsUnexpectedFormat(t *testing.T) {
 		},
 	})
 	test.s.config.MetricFormat = "invalid"
+	test.s.metricFormatter = nil
 	metrics := []metricPair{
 		exampleIntMetric(),
 	}
@@ -1399,6 +1922,7 @@ func TestMetricsBufferOverflow(t *testing.T) {
 
 	test.s.config.HTTPClientSettings.Endpoint = ":"
 	test.s.config.MetricFormat = PrometheusFormat
+	test.s.metricFormatter = getTestMetricFormatter(t, test.s.config)
 	test.s.config.MaxRequestBodySize = 1024 * 1024 * 1024 * 1024
 	metric := exampleIntMetric()
 	flds := newFields(pdata.NewAttributeMap())
@@ -1428,6 +1952,7 @@ foo=bar metric=gauge_metric_name  245 1608124662`
 	})
 
 	test.s.config.MetricFormat = Carbon2Format
+	test.s.metricFormatter = getTestMetricFormatter(t, test.s.config)
 	test.s.metricBuffer = []metricPair{
 		exampleIntMetric(),
 		exampleIntGaugeMetric(),
@@ -1446,6 +1971,52 @@ foo=bar metric=gauge_metric_name  245 1608124662`
 	assert.NoError(t, err)
 }
 
+func TestSendMetricsFiltered(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			body := extractBody(t, req)
+			expected := `foo=bar metric=gauge_metric_name  124 1608124661`
+			assert.Equal(t, expected, body)
+		},
+	}, func(cfg *Config) {
+		cfg.MetricFilters = MetricFiltersConfig{
+			Exclude: []string{"^test\\."},
+			ExcludeLabels: []LabelMatcherConfig{
+				{Name: "remote_name", Value: "^156955$"},
+			},
+		}
+	})
+
+	gauge := exampleIntGaugeMetric()
+	test.s.metricBuffer = []metricPair{
+		exampleIntMetric(),
+		gauge,
+	}
+
+	_, err := test.s.sendMetrics(context.Background(), newFields(pdata.NewAttributeMap()))
+	assert.NoError(t, err)
+	// the incoming metric is not modified
+	assert.Equal(t, 2, gauge.metric.Gauge().DataPoints().Len())
+}
+
+func TestSendMetricsAllFiltered(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){}, func(cfg *Config) {
+		cfg.MetricFilters = MetricFiltersConfig{
+			Include: []string{"^other_metric$"},
+		}
+	})
+
+	test.s.metricBuffer = []metricPair{
+		exampleIntMetric(),
+		exampleIntGaugeMetric(),
+	}
+
+	dropped, err := test.s.sendMetrics(context.Background(), newFields(pdata.NewAttributeMap()))
+	assert.NoError(t, err)
+	assert.Empty(t, dropped)
+	assert.EqualValues(t, 0, atomic.LoadInt32(test.reqCounter))
+}
+
 func TestSendGraphiteMetrics(t *testing.T) {
 	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
 		func(w http.ResponseWriter, req *http.Request) {
@@ -1459,11 +2030,9 @@ gauge_metric_name.. 245 1608124662`
 		},
 	})
 
-	gf, err := newGraphiteFormatter("%{_metric_}.%{metric}.%{unit}")
-	require.NoError(t, err)
-	test.s.graphiteFormatter = gf
-
 	test.s.config.MetricFormat = GraphiteFormat
+	test.s.config.GraphiteTemplate = "%{_metric_}.%{metric}.%{unit}"
+	test.s.metricFormatter = getTestMetricFormatter(t, test.s.config)
 	test.s.metricBuffer = []metricPair{
 		exampleIntMetric(),
 		exampleIntGaugeMetric(),
@@ -1477,6 +2046,6 @@ gauge_metric_name.. 245 1608124662`
 	test.s.metricBuffer[0].attributes.InsertString("unit", "m/s")
 	test.s.metricBuffer[0].attributes.InsertBool("metric", true)
 
-	_, err = test.s.sendMetrics(context.Background(), flds)
+	_, err := test.s.sendMetrics(context.Background(), flds)
 	assert.NoError(t, err)
 }
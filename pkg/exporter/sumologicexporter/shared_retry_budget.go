@@ -0,0 +1,131 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"errors"
+	"sync"
+
+	"go.opentelemetry.io/collector/config"
+	"go.uber.org/zap"
+)
+
+var errRetryBudgetExhausted = errors.New("retry budget is exhausted, request not sent")
+
+// sharedRetryBudgets keeps the retry budgets by the IDs of the exporters. The logs, metrics
+// and traces exporters are created separately from the same configuration, so this is
+// how their pipelines share the budget.
+var sharedRetryBudgets = struct {
+	lock    sync.Mutex
+	budgets map[config.ComponentID]*sharedRetryBudget
+}{
+	budgets: make(map[config.ComponentID]*sharedRetryBudget),
+}
+
+// getSharedRetryBudget returns the retry budget of the exporter, it's created
+// when it doesn't exist yet or when the configuration has changed
+func getSharedRetryBudget(id config.ComponentID, cfg RetryBudgetConfig, logger *zap.Logger) *sharedRetryBudget {
+	sharedRetryBudgets.lock.Lock()
+	defer sharedRetryBudgets.lock.Unlock()
+
+	if b, ok := sharedRetryBudgets.budgets[id]; ok && b.cfg == cfg {
+		return b
+	}
+
+	b := newSharedRetryBudget(cfg, logger)
+	sharedRetryBudgets.budgets[id] = b
+	return b
+}
+
+// sharedRetryBudget limits the retries in progress of all the pipelines of the exporter,
+// by their number and by the size of their bodies, so the pipeline which keeps failing
+// can't take all the connections from the others. The request is considered a retry
+// when the previous request of its pipeline failed, as the exporter can't tell the data
+// retried by retry_on_failure from the new data.
+type sharedRetryBudget struct {
+	cfg    RetryBudgetConfig
+	logger *zap.Logger
+
+	// lock guards the fields below
+	lock    sync.Mutex
+	failing map[PipelineType]bool
+	retries int
+	bytes   int64
+}
+
+func newSharedRetryBudget(cfg RetryBudgetConfig, logger *zap.Logger) *sharedRetryBudget {
+	return &sharedRetryBudget{
+		cfg:     cfg,
+		logger:  logger,
+		failing: make(map[PipelineType]bool),
+	}
+}
+
+// acquire takes the budget for the request of the pipeline with the body of the given size,
+// when the request is a retry. The returned function gives the budget back and has to be called
+// when the request is done. errRetryBudgetExhausted is returned when the request is a retry
+// and the budget can't be taken. A single request larger than max_retry_bytes is allowed
+// when no other retries are in progress.
+func (b *sharedRetryBudget) acquire(pipeline PipelineType, size int64) (func(), error) {
+	if size < 0 {
+		size = 0
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if !b.failing[pipeline] {
+		return func() {}, nil
+	}
+
+	if b.retries >= b.cfg.MaxConcurrentRetries ||
+		(b.retries > 0 && b.bytes+size > int64(b.cfg.MaxRetryBytes)) {
+		recordRetryBudgetRejection(pipeline)
+		return nil, errRetryBudgetExhausted
+	}
+
+	b.retries++
+	b.bytes += size
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.lock.Lock()
+			defer b.lock.Unlock()
+			b.retries--
+			b.bytes -= size
+		})
+	}, nil
+}
+
+// onResult marks the pipeline as failing after the failed request
+// and as healthy after the successful one
+func (b *sharedRetryBudget) onResult(pipeline PipelineType, failed bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.failing[pipeline] == failed {
+		return
+	}
+	b.failing[pipeline] = failed
+
+	if failed {
+		b.logger.Info("Pipeline is failing, its requests are limited by the retry budget",
+			zap.String("pipeline", string(pipeline)))
+	} else {
+		b.logger.Info("Pipeline has recovered, its requests are no longer limited by the retry budget",
+			zap.String("pipeline", string(pipeline)))
+	}
+}
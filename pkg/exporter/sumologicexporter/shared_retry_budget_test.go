@@ -0,0 +1,115 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config"
+	"go.uber.org/zap"
+)
+
+func TestSharedRetryBudgetHealthyPipelines(t *testing.T) {
+	b := newSharedRetryBudget(RetryBudgetConfig{MaxConcurrentRetries: 1, MaxRetryBytes: 10}, zap.NewNop())
+
+	// the requests of the healthy pipelines are not retries, so they are not limited
+	for i := 0; i < 3; i++ {
+		_, err := b.acquire(LogsPipeline, 100)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 0, b.retries)
+	assert.EqualValues(t, 0, b.bytes)
+}
+
+func TestSharedRetryBudgetConcurrentRetries(t *testing.T) {
+	b := newSharedRetryBudget(RetryBudgetConfig{MaxConcurrentRetries: 2, MaxRetryBytes: 1000}, zap.NewNop())
+	b.onResult(MetricsPipeline, true)
+
+	release1, err := b.acquire(MetricsPipeline, 10)
+	require.NoError(t, err)
+	release2, err := b.acquire(MetricsPipeline, 10)
+	require.NoError(t, err)
+
+	_, err = b.acquire(MetricsPipeline, 10)
+	assert.ErrorIs(t, err, errRetryBudgetExhausted)
+
+	// the logs pipeline is healthy, so its requests are still sent
+	_, err = b.acquire(LogsPipeline, 10)
+	assert.NoError(t, err)
+
+	// the logs pipeline fails too and has to share the budget with the metrics pipeline
+	b.onResult(LogsPipeline, true)
+	_, err = b.acquire(LogsPipeline, 10)
+	assert.ErrorIs(t, err, errRetryBudgetExhausted)
+
+	release1()
+	release1()
+	_, err = b.acquire(LogsPipeline, 10)
+	assert.NoError(t, err)
+
+	release2()
+	assert.Equal(t, 1, b.retries)
+	assert.EqualValues(t, 10, b.bytes)
+}
+
+func TestSharedRetryBudgetBytes(t *testing.T) {
+	b := newSharedRetryBudget(RetryBudgetConfig{MaxConcurrentRetries: 10, MaxRetryBytes: 100}, zap.NewNop())
+	b.onResult(TracesPipeline, true)
+
+	// a single request larger than the limit is allowed when no other retries are in progress
+	release, err := b.acquire(TracesPipeline, 150)
+	require.NoError(t, err)
+
+	_, err = b.acquire(TracesPipeline, 1)
+	assert.ErrorIs(t, err, errRetryBudgetExhausted)
+	release()
+
+	_, err = b.acquire(TracesPipeline, 60)
+	require.NoError(t, err)
+	_, err = b.acquire(TracesPipeline, 50)
+	assert.ErrorIs(t, err, errRetryBudgetExhausted)
+	_, err = b.acquire(TracesPipeline, 40)
+	assert.NoError(t, err)
+}
+
+func TestSharedRetryBudgetRecovery(t *testing.T) {
+	b := newSharedRetryBudget(RetryBudgetConfig{MaxConcurrentRetries: 1, MaxRetryBytes: 100}, zap.NewNop())
+	b.onResult(LogsPipeline, true)
+
+	_, err := b.acquire(LogsPipeline, 10)
+	require.NoError(t, err)
+	_, err = b.acquire(LogsPipeline, 10)
+	assert.ErrorIs(t, err, errRetryBudgetExhausted)
+
+	b.onResult(LogsPipeline, false)
+	_, err = b.acquire(LogsPipeline, 10)
+	assert.NoError(t, err)
+}
+
+func TestGetSharedRetryBudget(t *testing.T) {
+	id := config.NewComponentIDWithName(typeStr, "shared_retry_budget_test")
+	cfg := RetryBudgetConfig{Enabled: true, MaxConcurrentRetries: 1, MaxRetryBytes: 100}
+
+	b := getSharedRetryBudget(id, cfg, zap.NewNop())
+	assert.Same(t, b, getSharedRetryBudget(id, cfg, zap.NewNop()), "the exporters with the same ID share the budget")
+	assert.NotSame(t, b, getSharedRetryBudget(config.NewComponentIDWithName(typeStr, "other"), cfg, zap.NewNop()))
+
+	cfg.MaxConcurrentRetries = 2
+	changed := getSharedRetryBudget(id, cfg, zap.NewNop())
+	assert.NotSame(t, b, changed, "the budget is recreated when the configuration changes")
+	assert.Same(t, changed, getSharedRetryBudget(id, cfg, zap.NewNop()))
+}
@@ -17,12 +17,20 @@ package sumologicexporter
 import (
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 type sourceFormats struct {
 	name     sourceFormat
 	host     sourceFormat
 	category sourceFormat
+	// categoryPrefix is prepended to the formatted category
+	categoryPrefix string
+	// categoryDashReplacer replaces the dashes in the prefixed category,
+	// it's nil when the dashes are kept
+	categoryDashReplacer *strings.Replacer
+	// hostFallback provides the source host when the host template is not set
+	hostFallback *fqdnResolver
 }
 
 type sourceFormat struct {
@@ -63,11 +71,16 @@ func newSourceFormats(cfg *Config) (sourceFormats, error) {
 		return sourceFormats{}, err
 	}
 
-	return sourceFormats{
-		category: newSourceFormat(r, cfg.SourceCategory),
-		host:     newSourceFormat(r, cfg.SourceHost),
-		name:     newSourceFormat(r, cfg.SourceName),
-	}, nil
+	sfs := sourceFormats{
+		category:       newSourceFormat(r, cfg.SourceCategory),
+		categoryPrefix: cfg.SourceCategoryPrefix,
+		host:           newSourceFormat(r, cfg.SourceHost),
+		name:           newSourceFormat(r, cfg.SourceName),
+	}
+	if cfg.SourceCategoryReplaceDash != "" && cfg.SourceCategoryReplaceDash != "-" {
+		sfs.categoryDashReplacer = strings.NewReplacer("-", cfg.SourceCategoryReplaceDash)
+	}
+	return sfs, nil
 }
 
 // format converts sourceFormat to string.
@@ -91,3 +104,26 @@ func (s *sourceFormat) format(f fields) string {
 func (s *sourceFormat) isSet() bool {
 	return len(s.template) > 0
 }
+
+// formatCategory returns the source category with the prefix prepended
+// and the dashes replaced. All the source categories the data is sent with
+// have to be resolved by it.
+func (s *sourceFormats) formatCategory(f fields) string {
+	category := s.categoryPrefix + s.category.format(f)
+	if s.categoryDashReplacer != nil {
+		category = s.categoryDashReplacer.Replace(category)
+	}
+	return category
+}
+
+// formatHost returns the source host and true if it's set,
+// falling back to the machine's FQDN when the host template is not set
+func (s *sourceFormats) formatHost(f fields) (string, bool) {
+	if s.host.isSet() {
+		return s.host.format(f), true
+	}
+	if s.hostFallback != nil {
+		return s.hostFallback.get(), true
+	}
+	return "", false
+}
@@ -17,9 +17,11 @@ package sumologicexporter
 import (
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func getTestSourceFormat(t *testing.T, template string) sourceFormat {
@@ -110,3 +112,72 @@ func TestIsNotSet(t *testing.T) {
 	s := getTestSourceFormat(t, "")
 	assert.False(t, s.isSet())
 }
+
+func TestFormatCategory(t *testing.T) {
+	testcases := []struct {
+		name        string
+		prefix      string
+		replaceDash string
+		expected    string
+	}{
+		{
+			name:     "no prefix",
+			expected: "my-app/prod",
+		},
+		{
+			name:     "prefix",
+			prefix:   "emea-1/",
+			expected: "emea-1/my-app/prod",
+		},
+		{
+			name:        "prefix with dash replacement",
+			prefix:      "emea-1/",
+			replaceDash: "_",
+			expected:    "emea_1/my_app/prod",
+		},
+		{
+			name:        "dash kept",
+			replaceDash: "-",
+			expected:    "my-app/prod",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := newSourceFormats(&Config{
+				SourceCategory:            "%{app}/%{env}",
+				SourceCategoryPrefix:      tc.prefix,
+				SourceCategoryReplaceDash: tc.replaceDash,
+			})
+			require.NoError(t, err)
+
+			f := fieldsFromMap(map[string]string{"app": "my-app", "env": "prod"})
+			assert.Equal(t, tc.expected, s.formatCategory(f))
+		})
+	}
+}
+
+func TestFormatHost(t *testing.T) {
+	f := fieldsFromMap(map[string]string{"host": "value"})
+	fallback := &fqdnResolver{
+		logger:          zap.NewNop(),
+		refreshInterval: time.Hour,
+		hostname:        func() (string, error) { return "test-host.example.com", nil },
+		lookupFQDN:      lookupFQDN,
+		now:             time.Now,
+	}
+
+	s := sourceFormats{host: getTestSourceFormat(t, "%{host}"), hostFallback: fallback}
+	host, ok := s.formatHost(f)
+	assert.True(t, ok)
+	assert.Equal(t, "value", host)
+
+	s = sourceFormats{host: getTestSourceFormat(t, ""), hostFallback: fallback}
+	host, ok = s.formatHost(f)
+	assert.True(t, ok)
+	assert.Equal(t, "test-host.example.com", host)
+
+	s = sourceFormats{host: getTestSourceFormat(t, "")}
+	_, ok = s.formatHost(f)
+	assert.False(t, ok)
+}
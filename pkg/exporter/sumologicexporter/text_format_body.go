@@ -0,0 +1,67 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"encoding/json"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// isUnsupportedTextLogBody returns true for the bodies which have no natural
+// text representation, i.e. maps and arrays
+func isUnsupportedTextLogBody(body pdata.AttributeValue) bool {
+	t := body.Type()
+	return t == pdata.AttributeValueTypeMap || t == pdata.AttributeValueTypeArray
+}
+
+// formatTextLogBody renders the body of the record sent with the text log format,
+// maps and arrays are rendered according to text_format_body_handling
+func formatTextLogBody(body pdata.AttributeValue, handling TextFormatBodyHandlingType, logKey string) string {
+	if !isUnsupportedTextLogBody(body) {
+		return body.AsString()
+	}
+
+	if handling == TextFormatBodyAttributes && body.Type() == pdata.AttributeValueTypeMap {
+		// the other entries were moved to the attributes
+		if v, ok := body.MapVal().Get(logKey); ok {
+			return v.AsString()
+		}
+		return ""
+	}
+
+	if handling == TextFormatBodyJSON || handling == TextFormatBodyAttributes {
+		data, err := json.Marshal(attributeValueToJSON(body))
+		if err == nil {
+			return string(data)
+		}
+	}
+	return body.AsString()
+}
+
+// moveTextLogBodyToAttributes inserts the entries of the map body, apart from the one
+// under logKey which is sent as the line, into the record attributes.
+// The existing attributes take precedence.
+func moveTextLogBodyToAttributes(attrs pdata.AttributeMap, body pdata.AttributeValue, logKey string) {
+	if body.Type() != pdata.AttributeValueTypeMap {
+		return
+	}
+	body.MapVal().Range(func(k string, v pdata.AttributeValue) bool {
+		if k != logKey {
+			attrs.Insert(k, v)
+		}
+		return true
+	})
+}
@@ -0,0 +1,95 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func exampleMapBody() pdata.AttributeValue {
+	body := pdata.NewAttributeValueMap()
+	body.MapVal().InsertString("log", "Example log")
+	body.MapVal().InsertString("level", "info")
+	return body
+}
+
+func TestFormatTextLogBody(t *testing.T) {
+	arrayBody := pdata.NewAttributeValueArray()
+	arrayBody.SliceVal().AppendEmpty().SetIntVal(1)
+	arrayBody.SliceVal().AppendEmpty().SetStringVal("a")
+
+	testcases := []struct {
+		name     string
+		body     pdata.AttributeValue
+		handling TextFormatBodyHandlingType
+		expected string
+	}{
+		{
+			name:     "string body",
+			body:     pdata.NewAttributeValueString("Example log"),
+			handling: TextFormatBodyJSON,
+			expected: "Example log",
+		},
+		{
+			name:     "map body as json",
+			body:     exampleMapBody(),
+			handling: TextFormatBodyJSON,
+			expected: `{"level":"info","log":"Example log"}`,
+		},
+		{
+			name:     "array body as json",
+			body:     arrayBody,
+			handling: TextFormatBodyJSON,
+			expected: `[1,"a"]`,
+		},
+		{
+			name:     "map body moved to attributes",
+			body:     exampleMapBody(),
+			handling: TextFormatBodyAttributes,
+			expected: "Example log",
+		},
+		{
+			name:     "array body with attributes handling",
+			body:     arrayBody,
+			handling: TextFormatBodyAttributes,
+			expected: `[1,"a"]`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, formatTextLogBody(tc.body, tc.handling, DefaultLogKey))
+		})
+	}
+}
+
+func TestMoveTextLogBodyToAttributes(t *testing.T) {
+	attrs := pdata.NewAttributeMap()
+	attrs.InsertString("level", "warn")
+	attrs.InsertString("host", "a")
+
+	body := exampleMapBody()
+	body.MapVal().InsertString("stream", "stdout")
+	moveTextLogBodyToAttributes(attrs, body, DefaultLogKey)
+
+	assert.Equal(t, map[string]interface{}{
+		"level":  "warn",
+		"host":   "a",
+		"stream": "stdout",
+	}, attrs.AsRaw())
+}
@@ -0,0 +1,122 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	// textLogTemplateTimestamp is the template key of the record's timestamp
+	textLogTemplateTimestamp = "timestamp"
+	// textLogTemplateSeverity is the template key of the record's severity
+	textLogTemplateSeverity = "severity"
+	// textLogTemplateBody is the template key of the record's body
+	textLogTemplateBody = "body"
+)
+
+var textLogTemplateRegex = regexp.MustCompile(sourceRegex)
+
+// textLogTemplate renders log records into text lines according to text_log_template
+type textLogTemplate struct {
+	parts        []textLogTemplatePart
+	bodyHandling TextFormatBodyHandlingType
+	logKey       string
+}
+
+// textLogTemplatePart is either a literal text or a key replaced with its value
+type textLogTemplatePart struct {
+	literal string
+	key     string
+}
+
+// newTextLogTemplate parses the template, e.g. `%{timestamp} %{severity} %{body}`,
+// returns nil if the template is empty
+func newTextLogTemplate(template string, bodyHandling TextFormatBodyHandlingType, logKey string) *textLogTemplate {
+	if template == "" {
+		return nil
+	}
+
+	var parts []textLogTemplatePart
+	last := 0
+	for _, match := range textLogTemplateRegex.FindAllStringSubmatchIndex(template, -1) {
+		if match[0] > last {
+			parts = append(parts, textLogTemplatePart{literal: template[last:match[0]]})
+		}
+		parts = append(parts, textLogTemplatePart{key: template[match[2]:match[3]]})
+		last = match[1]
+	}
+	if last < len(template) {
+		parts = append(parts, textLogTemplatePart{literal: template[last:]})
+	}
+
+	return &textLogTemplate{parts: parts, bodyHandling: bodyHandling, logKey: logKey}
+}
+
+// format renders the record. The timestamp, severity and body keys are taken from
+// the record, other keys from its attributes (or the resource attributes)
+func (t *textLogTemplate) format(record logPair) string {
+	var sb strings.Builder
+
+	for _, part := range t.parts {
+		if part.key == "" {
+			sb.WriteString(part.literal)
+			continue
+		}
+
+		switch part.key {
+		case textLogTemplateTimestamp:
+			sb.WriteString(formatTextLogTimestamp(record.log.Timestamp()))
+		case textLogTemplateSeverity:
+			sb.WriteString(formatTextLogSeverity(record.log))
+		case textLogTemplateBody:
+			sb.WriteString(formatTextLogBody(record.log.Body(), t.bodyHandling, t.logKey))
+		default:
+			if v, ok := record.attributes.Get(part.key); ok {
+				sb.WriteString(v.AsString())
+			} else {
+				sb.WriteString(unrecognizedAttributeValue)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// formatTextLogTimestamp formats the timestamp as RFC3339 with nanoseconds.
+// It returns an empty string if the timestamp is not set, so the line depends
+// on the record only, e.g. for the idempotency keys of the requests.
+func formatTextLogTimestamp(ts pdata.Timestamp) string {
+	if ts == 0 {
+		return ""
+	}
+	return ts.AsTime().UTC().Format(time.RFC3339Nano)
+}
+
+// formatTextLogSeverity returns the severity text of the record,
+// falling back to the name of its severity number
+func formatTextLogSeverity(log pdata.LogRecord) string {
+	if text := log.SeverityText(); text != "" {
+		return text
+	}
+	if log.SeverityNumber() == pdata.SeverityNumberUNDEFINED {
+		return unrecognizedAttributeValue
+	}
+	return strings.TrimPrefix(log.SeverityNumber().String(), "SEVERITY_NUMBER_")
+}
@@ -0,0 +1,108 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func exampleTemplateLogPair() logPair {
+	log := pdata.NewLogRecord()
+	log.SetTimestamp(pdata.NewTimestampFromTime(time.Date(2022, 5, 1, 12, 0, 0, 500, time.UTC)))
+	log.SetSeverityText("warning")
+	log.Body().SetStringVal("disk is almost full")
+
+	attributes := pdata.NewAttributeMap()
+	attributes.InsertString("k8s.pod.name", "pod-1")
+	attributes.InsertInt("usage", 95)
+
+	return logPair{log: log, attributes: attributes}
+}
+
+func TestNewTextLogTemplate(t *testing.T) {
+	assert.Nil(t, newTextLogTemplate("", TextFormatBodyString, DefaultLogKey))
+
+	assert.Equal(t,
+		&textLogTemplate{
+			parts: []textLogTemplatePart{
+				{literal: "["},
+				{key: "timestamp"},
+				{literal: "] "},
+				{key: "k8s.pod.name"},
+				{key: "body"},
+				{literal: " 100%"},
+			},
+			bodyHandling: TextFormatBodyString,
+			logKey:       DefaultLogKey,
+		},
+		newTextLogTemplate("[%{timestamp}] %{k8s.pod.name}%{body} 100%", TextFormatBodyString, DefaultLogKey),
+	)
+}
+
+func TestTextLogTemplateFormat(t *testing.T) {
+	testcases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "record fields and attributes",
+			template: "%{timestamp} %{severity} %{k8s.pod.name} %{body}",
+			expected: "2022-05-01T12:00:00.0000005Z warning pod-1 disk is almost full",
+		},
+		{
+			name:     "non string attribute",
+			template: "%{body} (%{usage}%)",
+			expected: "disk is almost full (95%)",
+		},
+		{
+			name:     "missing attribute",
+			template: "%{k8s.namespace.name}: %{body}",
+			expected: "undefined: disk is almost full",
+		},
+		{
+			name:     "literal only",
+			template: "static",
+			expected: "static",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, newTextLogTemplate(tc.template, TextFormatBodyString, DefaultLogKey).format(exampleTemplateLogPair()))
+		})
+	}
+}
+
+func TestFormatTextLogSeverity(t *testing.T) {
+	log := pdata.NewLogRecord()
+	assert.Equal(t, "undefined", formatTextLogSeverity(log))
+
+	log.SetSeverityNumber(pdata.SeverityNumberERROR2)
+	assert.Equal(t, "ERROR2", formatTextLogSeverity(log))
+
+	log.SetSeverityText("err")
+	assert.Equal(t, "err", formatTextLogSeverity(log))
+}
+
+func TestFormatTextLogTimestamp(t *testing.T) {
+	assert.Equal(t, "", formatTextLogTimestamp(0))
+	assert.Equal(t, "2022-05-01T12:00:00.5Z",
+		formatTextLogTimestamp(pdata.NewTimestampFromTime(time.Date(2022, 5, 1, 12, 0, 0, 500_000_000, time.UTC))))
+}
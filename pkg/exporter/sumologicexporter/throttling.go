@@ -0,0 +1,144 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/zap"
+)
+
+const headerRetryAfter string = "Retry-After"
+
+var errThrottled = errors.New("pipeline is throttled by the backend, request not sent")
+
+// throttling keeps the state of the pipelines throttled by the backend, i.e. which
+// requests were rejected with 429 Too Many Requests. It's shared by all the senders
+// of the exporter. A pipeline stays throttled until the backend accepts its request,
+// the throttle period only defines when sending is resumed when pausing is enabled.
+type throttling struct {
+	logger        *zap.Logger
+	pauseSends    bool
+	defaultPeriod time.Duration
+	maxPeriod     time.Duration
+	now           func() time.Time
+
+	// lock guards the fields below
+	lock  sync.Mutex
+	until map[PipelineType]time.Time
+}
+
+func newThrottling(cfg ThrottlingConfig, logger *zap.Logger) *throttling {
+	return &throttling{
+		logger:        logger,
+		pauseSends:    cfg.PauseSends,
+		defaultPeriod: cfg.DefaultPeriod,
+		maxPeriod:     cfg.MaxPeriod,
+		now:           time.Now,
+		until:         make(map[PipelineType]time.Time),
+	}
+}
+
+// check returns errThrottled wrapped in a throttle retry error when sending
+// of the pipeline is paused, so the request is retried after the throttle period.
+// The other pipelines are not affected.
+func (t *throttling) check(pipeline PipelineType) error {
+	if t == nil || !t.pauseSends {
+		return nil
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	until, ok := t.until[pipeline]
+	if !ok {
+		return nil
+	}
+	if remaining := until.Sub(t.now()); remaining > 0 {
+		return exporterhelper.NewThrottleRetry(errThrottled, remaining)
+	}
+	return nil
+}
+
+// onResponse updates the state of the pipeline after the backend responded
+// and returns the error of the response. When sending is paused, the error
+// of the 429 response is wrapped in a throttle retry error.
+func (t *throttling) onResponse(pipeline PipelineType, resp *http.Response, err error) error {
+	if t == nil {
+		return err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		if _, ok := t.until[pipeline]; ok {
+			t.logger.Info("Backend accepted the request, pipeline is no longer throttled",
+				zap.String("pipeline", string(pipeline)),
+			)
+			delete(t.until, pipeline)
+			recordThrottledState(pipeline, false)
+		}
+		return err
+	}
+
+	recordThrottledRequest(pipeline)
+
+	period := t.period(resp.Header.Get(headerRetryAfter))
+	until := t.now().Add(period)
+	previous, ok := t.until[pipeline]
+	if !ok {
+		t.logger.Warn("Backend is throttling the requests",
+			zap.String("pipeline", string(pipeline)),
+			zap.Duration("period", period),
+			zap.Bool("pause_sends", t.pauseSends),
+		)
+		recordThrottledState(pipeline, true)
+	}
+	if until.After(previous) {
+		t.until[pipeline] = until
+	}
+
+	if t.pauseSends {
+		return exporterhelper.NewThrottleRetry(err, period)
+	}
+	return err
+}
+
+// period returns the throttle period from the Retry-After header value, which is either
+// the number of seconds or the HTTP date. The default period is used when the value
+// is missing or invalid and the period is capped at the max period.
+func (t *throttling) period(retryAfter string) time.Duration {
+	period := t.defaultPeriod
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		period = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(retryAfter); err == nil {
+		period = date.Sub(t.now())
+	}
+
+	switch {
+	case period <= 0:
+		return t.defaultPeriod
+	case period > t.maxPeriod:
+		return t.maxPeriod
+	default:
+		return period
+	}
+}
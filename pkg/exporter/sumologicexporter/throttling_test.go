@@ -0,0 +1,122 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/zap"
+)
+
+func newThrottledResponse(retryAfter string) *http.Response {
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{},
+	}
+	if retryAfter != "" {
+		resp.Header.Set(headerRetryAfter, retryAfter)
+	}
+	return resp
+}
+
+func TestThrottlingPausesOnlyThrottledPipeline(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	th := newThrottling(ThrottlingConfig{
+		PauseSends:    true,
+		DefaultPeriod: 30 * time.Second,
+		MaxPeriod:     5 * time.Minute,
+	}, zap.NewNop())
+	th.now = func() time.Time { return now }
+
+	respErr := &statusError{statusCode: http.StatusTooManyRequests, err: errors.New("too many requests")}
+	err := th.onResponse(LogsPipeline, newThrottledResponse("10"), respErr)
+	assert.ErrorIs(t, err, respErr)
+	assert.Equal(t, exporterhelper.NewThrottleRetry(respErr, 10*time.Second), err)
+
+	err = th.check(LogsPipeline)
+	assert.ErrorIs(t, err, errThrottled)
+	assert.Equal(t, exporterhelper.NewThrottleRetry(errThrottled, 10*time.Second), err)
+	assert.NoError(t, th.check(MetricsPipeline))
+
+	// the pipeline is resumed after the throttle period
+	now = now.Add(10 * time.Second)
+	assert.NoError(t, th.check(LogsPipeline))
+
+	// and is no longer throttled once the backend accepts its request
+	assert.NoError(t, th.onResponse(LogsPipeline, &http.Response{StatusCode: http.StatusOK}, nil))
+	assert.NotContains(t, th.until, LogsPipeline)
+}
+
+func TestThrottlingWithoutPause(t *testing.T) {
+	th := newThrottling(ThrottlingConfig{
+		DefaultPeriod: 30 * time.Second,
+		MaxPeriod:     5 * time.Minute,
+	}, zap.NewNop())
+
+	respErr := &statusError{statusCode: http.StatusTooManyRequests, err: errors.New("too many requests")}
+	assert.Equal(t, respErr, th.onResponse(TracesPipeline, newThrottledResponse("10"), respErr))
+	assert.Contains(t, th.until, TracesPipeline)
+	assert.NoError(t, th.check(TracesPipeline))
+
+	// any other response ends the throttling
+	serverErr := &statusError{statusCode: http.StatusServiceUnavailable, err: errors.New("unavailable")}
+	resp := &http.Response{StatusCode: http.StatusServiceUnavailable}
+	assert.Equal(t, serverErr, th.onResponse(TracesPipeline, resp, serverErr))
+	assert.NotContains(t, th.until, TracesPipeline)
+}
+
+func TestThrottlingPeriod(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	th := newThrottling(ThrottlingConfig{
+		DefaultPeriod: 30 * time.Second,
+		MaxPeriod:     5 * time.Minute,
+	}, zap.NewNop())
+	th.now = func() time.Time { return now }
+
+	testcases := []struct {
+		retryAfter string
+		expected   time.Duration
+	}{
+		{retryAfter: "", expected: 30 * time.Second},
+		{retryAfter: "invalid", expected: 30 * time.Second},
+		{retryAfter: "0", expected: 30 * time.Second},
+		{retryAfter: "-5", expected: 30 * time.Second},
+		{retryAfter: "120", expected: 2 * time.Minute},
+		{retryAfter: "3600", expected: 5 * time.Minute},
+		{retryAfter: now.Add(time.Minute).Format(http.TimeFormat), expected: time.Minute},
+		{retryAfter: now.Add(-time.Minute).Format(http.TimeFormat), expected: 30 * time.Second},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.retryAfter, func(t *testing.T) {
+			assert.Equal(t, tc.expected, th.period(tc.retryAfter))
+		})
+	}
+}
+
+func TestThrottlingNil(t *testing.T) {
+	var th *throttling
+	require.NoError(t, th.check(LogsPipeline))
+
+	respErr := errors.New("too many requests")
+	assert.Equal(t, respErr, th.onResponse(LogsPipeline, newThrottledResponse(""), respErr))
+}
@@ -0,0 +1,124 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+// spkiPins is the set of the SHA-256 hashes of the pinned SubjectPublicKeyInfo
+type spkiPins map[[sha256.Size]byte]struct{}
+
+func newSPKIPins(hashes []string) (spkiPins, error) {
+	pins := make(spkiPins, len(hashes))
+	for _, h := range hashes {
+		b, err := base64.StdEncoding.DecodeString(h)
+		if err != nil {
+			return nil, fmt.Errorf("invalid spki_sha256 pin %q: %w", h, err)
+		}
+		if len(b) != sha256.Size {
+			return nil, fmt.Errorf("invalid spki_sha256 pin %q: expected %d bytes, got %d", h, sha256.Size, len(b))
+		}
+
+		var pin [sha256.Size]byte
+		copy(pin[:], b)
+		pins[pin] = struct{}{}
+	}
+	return pins, nil
+}
+
+// spkiHash returns the base64 encoded SHA-256 hash of the certificate's public key
+func spkiHash(cert *x509.Certificate) string {
+	h := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// verifyConnection checks if any certificate of the verified chains matches any of the pins,
+// so the pin of the intermediate or the root CA can be used as well. The certificates presented
+// by the server which are not part of a verified chain are never matched, as anyone can present them.
+// When the verification is skipped (insecure_skip_verify), only the leaf certificate is matched.
+func (p spkiPins) verifyConnection(cs tls.ConnectionState) error {
+	var certs []*x509.Certificate
+	switch {
+	case len(cs.VerifiedChains) > 0:
+		for _, chain := range cs.VerifiedChains {
+			certs = append(certs, chain...)
+		}
+	case len(cs.PeerCertificates) > 0:
+		certs = cs.PeerCertificates[:1]
+	}
+
+	for _, cert := range certs {
+		if _, ok := p[sha256.Sum256(cert.RawSubjectPublicKeyInfo)]; ok {
+			return nil
+		}
+	}
+
+	if len(cs.PeerCertificates) == 0 {
+		return fmt.Errorf("tls pinning: no certificates presented by %s", cs.ServerName)
+	}
+	return fmt.Errorf(
+		"tls pinning: none of the certificates presented by %s matches the pinned keys, leaf certificate spki_sha256: %s",
+		cs.ServerName, spkiHash(cs.PeerCertificates[0]),
+	)
+}
+
+// newPinnedHTTPClient creates the HTTP client like confighttp does, but with the pins
+// verified in the TLS handshake of every connection, before any data is sent.
+// The auth round tripper is applied after the transport is configured,
+// as it hides the transport created by confighttp.
+func newPinnedHTTPClient(
+	settings confighttp.HTTPClientSettings,
+	pins spkiPins,
+	host component.Host,
+) (*http.Client, error) {
+	auth := settings.Auth
+	settings.Auth = nil
+
+	client, err := settings.ToClient(host.GetExtensions(), component.TelemetrySettings{})
+	if err != nil {
+		return nil, err
+	}
+
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("tls pinning requires the HTTP client without custom round trippers")
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.VerifyConnection = pins.verifyConnection
+
+	if auth != nil {
+		authenticator, err := auth.GetClientAuthenticator(host.GetExtensions())
+		if err != nil {
+			return nil, err
+		}
+		if client.Transport, err = authenticator.RoundTripper(client.Transport); err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
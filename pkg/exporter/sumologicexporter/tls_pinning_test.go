@@ -0,0 +1,229 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+func TestNewSPKIPins(t *testing.T) {
+	valid := base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+
+	testcases := []struct {
+		name   string
+		hashes []string
+		errMsg string
+	}{
+		{
+			name:   "valid",
+			hashes: []string{valid},
+		},
+		{
+			name:   "invalid base64",
+			hashes: []string{valid, "not base64!"},
+			errMsg: `invalid spki_sha256 pin "not base64!": illegal base64 data at input byte 3`,
+		},
+		{
+			name:   "invalid length",
+			hashes: []string{"AAAA"},
+			errMsg: `invalid spki_sha256 pin "AAAA": expected 32 bytes, got 3`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			pins, err := newSPKIPins(tc.hashes)
+			if tc.errMsg != "" {
+				assert.EqualError(t, err, tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, pins, len(tc.hashes))
+		})
+	}
+}
+
+func TestPinnedHTTPClient(t *testing.T) {
+	var requests int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	t.Cleanup(srv.Close)
+
+	serverPin := spkiHash(srv.Certificate())
+	otherPin := base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+
+	testcases := []struct {
+		name     string
+		pins     []string
+		expected bool
+	}{
+		{
+			name:     "matching pin",
+			pins:     []string{serverPin},
+			expected: true,
+		},
+		{
+			name:     "matching one of the pins",
+			pins:     []string{otherPin, serverPin},
+			expected: true,
+		},
+		{
+			name:     "no matching pin",
+			pins:     []string{otherPin},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			atomic.StoreInt32(&requests, 0)
+
+			pins, err := newSPKIPins(tc.pins)
+			require.NoError(t, err)
+
+			settings := confighttp.HTTPClientSettings{
+				Endpoint: srv.URL,
+				TLSSetting: configtls.TLSClientSetting{
+					// the test server's certificate is self-signed,
+					// so the pins are checked against the presented certificates
+					InsecureSkipVerify: true,
+				},
+			}
+			client, err := newPinnedHTTPClient(settings, pins, componenttest.NewNopHost())
+			require.NoError(t, err)
+
+			resp, err := client.Get(srv.URL)
+			if tc.expected {
+				require.NoError(t, err)
+				resp.Body.Close()
+				assert.EqualValues(t, 1, atomic.LoadInt32(&requests))
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "tls pinning: none of the certificates presented")
+			assert.Contains(t, err.Error(), serverPin)
+			assert.EqualValues(t, 0, atomic.LoadInt32(&requests), "no request should reach the server")
+		})
+	}
+}
+
+// newTestCertificate creates the certificate signed by the parent, or self-signed when the parent is nil
+func newTestCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func TestSPKIPinsVerifyConnection(t *testing.T) {
+	root, rootKey := newTestCertificate(t, "root", nil, nil)
+	leaf, _ := newTestCertificate(t, "leaf", root, rootKey)
+	// pinned is a certificate of a trusted key which doesn't sign the endpoint's certificate
+	pinned, _ := newTestCertificate(t, "pinned", nil, nil)
+
+	testcases := []struct {
+		name     string
+		pin      *x509.Certificate
+		state    tls.ConnectionState
+		expected bool
+	}{
+		{
+			name: "pinned root of the verified chain",
+			pin:  root,
+			state: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf},
+				VerifiedChains:   [][]*x509.Certificate{{leaf, root}},
+			},
+			expected: true,
+		},
+		{
+			name: "pinned certificate only in the unverified intermediates",
+			pin:  pinned,
+			state: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf, pinned},
+				VerifiedChains:   [][]*x509.Certificate{{leaf, root}},
+			},
+			expected: false,
+		},
+		{
+			name: "pinned leaf without verification",
+			pin:  leaf,
+			state: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf, pinned},
+			},
+			expected: true,
+		},
+		{
+			name: "pinned intermediate without verification",
+			pin:  pinned,
+			state: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf, pinned},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			pins, err := newSPKIPins([]string{spkiHash(tc.pin)})
+			require.NoError(t, err)
+
+			err = pins.verifyConnection(tc.state)
+			if tc.expected {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), spkiHash(leaf))
+		})
+	}
+}
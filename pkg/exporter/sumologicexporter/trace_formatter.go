@@ -0,0 +1,166 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// spanToText renders the span as a single human-readable line, e.g.
+//
+//	2022-05-01T12:00:00Z name="GET /users" trace_id=... span_id=... kind=SERVER duration=1.5ms status=OK
+//	  service.name=api http.method=GET events=[retry@2022-05-01T12:00:00.001Z{attempt=1}]
+//
+// The resource attributes precede the span attributes. The events and the links
+// are rendered with their attributes, and the numbers of the attributes, events and links
+// dropped before the span was exported are rendered when they are not zero, so the lost
+// data can be noticed in the logs.
+func spanToText(resource pdata.Resource, span pdata.Span) string {
+	var sb strings.Builder
+
+	sb.WriteString(span.StartTimestamp().AsTime().UTC().Format(time.RFC3339Nano))
+	writeTextField(&sb, "name", span.Name())
+	writeTextField(&sb, "trace_id", span.TraceID().HexString())
+	writeTextField(&sb, "span_id", span.SpanID().HexString())
+	if !span.ParentSpanID().IsEmpty() {
+		writeTextField(&sb, "parent_span_id", span.ParentSpanID().HexString())
+	}
+	writeTextField(&sb, "kind", strings.TrimPrefix(span.Kind().String(), "SPAN_KIND_"))
+	writeTextField(&sb, "duration", span.EndTimestamp().AsTime().Sub(span.StartTimestamp().AsTime()).String())
+	writeTextField(&sb, "status", strings.TrimPrefix(span.Status().Code().String(), "STATUS_CODE_"))
+	if msg := span.Status().Message(); msg != "" {
+		writeTextField(&sb, "status_message", msg)
+	}
+
+	writeTextAttributes(&sb, resource.Attributes())
+	writeTextAttributes(&sb, span.Attributes())
+
+	if events := span.Events(); events.Len() > 0 {
+		sb.WriteString(" events=[")
+		for i := 0; i < events.Len(); i++ {
+			event := events.At(i)
+			if i > 0 {
+				sb.WriteString("; ")
+			}
+			sb.WriteString(quoteTextValue(event.Name()))
+			sb.WriteByte('@')
+			sb.WriteString(event.Timestamp().AsTime().UTC().Format(time.RFC3339Nano))
+			writeTextAttributesBlock(&sb, event.Attributes(), event.DroppedAttributesCount())
+		}
+		sb.WriteByte(']')
+	}
+
+	if links := span.Links(); links.Len() > 0 {
+		sb.WriteString(" links=[")
+		for i := 0; i < links.Len(); i++ {
+			link := links.At(i)
+			if i > 0 {
+				sb.WriteString("; ")
+			}
+			sb.WriteString(link.TraceID().HexString())
+			sb.WriteByte(':')
+			sb.WriteString(link.SpanID().HexString())
+			writeTextAttributesBlock(&sb, link.Attributes(), link.DroppedAttributesCount())
+		}
+		sb.WriteByte(']')
+	}
+
+	writeDroppedCount(&sb, "dropped_attributes_count", span.DroppedAttributesCount())
+	writeDroppedCount(&sb, "dropped_events_count", span.DroppedEventsCount())
+	writeDroppedCount(&sb, "dropped_links_count", span.DroppedLinksCount())
+
+	return sb.String()
+}
+
+// writeTextField writes the ` key=value` pair, quoting the value when needed
+func writeTextField(sb *strings.Builder, key string, value string) {
+	sb.WriteByte(' ')
+	sb.WriteString(key)
+	sb.WriteByte('=')
+	sb.WriteString(quoteTextValue(value))
+}
+
+func writeTextAttributes(sb *strings.Builder, attrs pdata.AttributeMap) {
+	attrs.Range(func(k string, v pdata.AttributeValue) bool {
+		writeTextField(sb, k, v.AsString())
+		return true
+	})
+}
+
+// writeTextAttributesBlock writes the attributes of the event or the link in braces
+func writeTextAttributesBlock(sb *strings.Builder, attrs pdata.AttributeMap, dropped uint32) {
+	if attrs.Len() == 0 && dropped == 0 {
+		return
+	}
+
+	var block strings.Builder
+	writeTextAttributes(&block, attrs)
+	writeDroppedCount(&block, "dropped_attributes_count", dropped)
+
+	sb.WriteByte('{')
+	sb.WriteString(strings.TrimPrefix(block.String(), " "))
+	sb.WriteByte('}')
+}
+
+func writeDroppedCount(sb *strings.Builder, key string, count uint32) {
+	if count > 0 {
+		writeTextField(sb, key, strconv.FormatUint(uint64(count), 10))
+	}
+}
+
+// quoteTextValue quotes the value when it's empty or contains characters
+// which would make the line ambiguous, e.g. spaces or brackets
+func quoteTextValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"=[]{};@") || !strconv.CanBackquote(value) {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// spanRef refers to the span of the traces together with its resource and instrumentation library
+type spanRef struct {
+	rs   pdata.ResourceSpans
+	ils  pdata.InstrumentationLibrarySpans
+	span pdata.Span
+}
+
+// spanRefsToTraces copies the referred spans to the new traces, the consecutive spans
+// of the same resource and instrumentation library are kept together
+func spanRefsToTraces(refs []spanRef) pdata.Traces {
+	td := pdata.NewTraces()
+
+	var (
+		prev  spanRef
+		spans pdata.SpanSlice
+	)
+	for i, ref := range refs {
+		if i == 0 || ref.rs != prev.rs || ref.ils != prev.ils {
+			rs := td.ResourceSpans().AppendEmpty()
+			ref.rs.Resource().CopyTo(rs.Resource())
+			rs.SetSchemaUrl(ref.rs.SchemaUrl())
+			ils := rs.InstrumentationLibrarySpans().AppendEmpty()
+			ref.ils.InstrumentationLibrary().CopyTo(ils.InstrumentationLibrary())
+			ils.SetSchemaUrl(ref.ils.SchemaUrl())
+			spans = ils.Spans()
+		}
+		ref.span.CopyTo(spans.AppendEmpty())
+		prev = ref
+	}
+	return td
+}
@@ -0,0 +1,129 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func exampleSpanWithEventsAndLinks() (pdata.Resource, pdata.Span) {
+	start := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	resource := pdata.NewResource()
+	resource.Attributes().InsertString("service.name", "checkout")
+
+	span := pdata.NewSpan()
+	span.SetTraceID(pdata.NewTraceID([16]byte{0x5B, 0x8E, 0xFF, 0xF7, 0x98, 0x3, 0x81, 0x3, 0xD2, 0x69, 0xB6, 0x33, 0x81, 0x3F, 0xC6, 0xC}))
+	span.SetSpanID(pdata.NewSpanID([8]byte{0xEE, 0xE1, 0x9B, 0x7E, 0xC3, 0xC1, 0xB1, 0x73}))
+	span.SetParentSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	span.SetName("POST /orders")
+	span.SetKind(pdata.SpanKindServer)
+	span.SetStartTimestamp(pdata.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pdata.NewTimestampFromTime(start.Add(1500 * time.Microsecond)))
+	span.Status().SetCode(pdata.StatusCodeError)
+	span.Status().SetMessage("payment failed")
+	span.Attributes().InsertString("http.method", "POST")
+	span.Attributes().InsertInt("http.status_code", 502)
+	span.SetDroppedEventsCount(2)
+
+	event := span.Events().AppendEmpty()
+	event.SetName("exception")
+	event.SetTimestamp(pdata.NewTimestampFromTime(start.Add(time.Millisecond)))
+	event.Attributes().InsertString("exception.message", "connection reset")
+	event.SetDroppedAttributesCount(1)
+	span.Events().AppendEmpty().SetName("retry")
+
+	link := span.Links().AppendEmpty()
+	link.SetTraceID(pdata.NewTraceID([16]byte{0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6, 0xA7, 0xA8, 0xA9, 0xAA, 0xAB, 0xAC, 0xAD, 0xAE, 0xAF, 0xB0}))
+	link.SetSpanID(pdata.NewSpanID([8]byte{0xB1, 0xB2, 0xB3, 0xB4, 0xB5, 0xB6, 0xB7, 0xB8}))
+	link.Attributes().InsertString("link.type", "follows_from")
+
+	return resource, span
+}
+
+func TestSpanToText(t *testing.T) {
+	resource, span := exampleSpanWithEventsAndLinks()
+
+	assert.Equal(t,
+		`2022-05-01T12:00:00Z name="POST /orders" trace_id=5b8efff798038103d269b633813fc60c span_id=eee19b7ec3c1b173`+
+			` parent_span_id=0102030405060708 kind=SERVER duration=1.5ms status=ERROR status_message="payment failed"`+
+			` service.name=checkout http.method=POST http.status_code=502`+
+			` events=[exception@2022-05-01T12:00:00.001Z{exception.message="connection reset" dropped_attributes_count=1}; retry@1970-01-01T00:00:00Z]`+
+			` links=[a1a2a3a4a5a6a7a8a9aaabacadaeafb0:b1b2b3b4b5b6b7b8{link.type=follows_from}]`+
+			` dropped_events_count=2`,
+		spanToText(resource, span),
+	)
+}
+
+func TestSpanToTextMinimal(t *testing.T) {
+	assert.Equal(t,
+		`1970-01-01T00:00:00Z name="" trace_id="" span_id="" kind=UNSPECIFIED duration=0s status=UNSET`,
+		spanToText(pdata.NewResource(), pdata.NewSpan()),
+	)
+}
+
+func TestQuoteTextValue(t *testing.T) {
+	testcases := []struct {
+		value    string
+		expected string
+	}{
+		{value: "simple", expected: "simple"},
+		{value: "", expected: `""`},
+		{value: "with space", expected: `"with space"`},
+		{value: `with "quotes"`, expected: `"with \"quotes\""`},
+		{value: "key=value", expected: `"key=value"`},
+		{value: "[1,2]", expected: `"[1,2]"`},
+		{value: "multi\nline", expected: `"multi\nline"`},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, tc.expected, quoteTextValue(tc.value))
+	}
+}
+
+func TestSpanRefsToTraces(t *testing.T) {
+	td := pdata.NewTraces()
+	for _, service := range []string{"first", "second"} {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().InsertString("service.name", service)
+		spans := rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+		spans.AppendEmpty().SetName(service + "-1")
+		spans.AppendEmpty().SetName(service + "-2")
+	}
+
+	first := td.ResourceSpans().At(0)
+	second := td.ResourceSpans().At(1)
+	refs := []spanRef{
+		{rs: first, ils: first.InstrumentationLibrarySpans().At(0), span: first.InstrumentationLibrarySpans().At(0).Spans().At(1)},
+		{rs: second, ils: second.InstrumentationLibrarySpans().At(0), span: second.InstrumentationLibrarySpans().At(0).Spans().At(0)},
+		{rs: second, ils: second.InstrumentationLibrarySpans().At(0), span: second.InstrumentationLibrarySpans().At(0).Spans().At(1)},
+	}
+
+	result := spanRefsToTraces(refs)
+	require.Equal(t, 2, result.ResourceSpans().Len())
+	assert.Equal(t, 3, result.SpanCount())
+
+	service, ok := result.ResourceSpans().At(1).Resource().Attributes().Get("service.name")
+	require.True(t, ok)
+	assert.Equal(t, "second", service.StringVal())
+	spans := result.ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans()
+	assert.Equal(t, "second-1", spans.At(0).Name())
+	assert.Equal(t, "second-2", spans.At(1).Name())
+}
@@ -0,0 +1,156 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+var tracesSizer = tracesMarshaler.(pdata.TracesSizer)
+
+// retryBudget retries the trace requests with jittered exponential backoff.
+// It's created for a single batch of traces and its max elapsed time is shared
+// by all the requests the batch is split into, so retrying the first chunks
+// doesn't delay the batch beyond the max elapsed time.
+type retryBudget struct {
+	cfg      TracesRetryConfig
+	logger   *zap.Logger
+	deadline time.Time
+	now      func() time.Time
+	random   func() float64
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryBudget(cfg TracesRetryConfig, logger *zap.Logger) *retryBudget {
+	return &retryBudget{
+		cfg:      cfg,
+		logger:   logger,
+		deadline: time.Now().Add(cfg.MaxElapsedTime),
+		now:      time.Now,
+		random:   rand.Float64,
+		sleep:    sleepContext,
+	}
+}
+
+// do calls send until it succeeds, fails with an error which is not retryable
+// or the next attempt would be made after the budget's deadline.
+// The backoff interval starts from the initial interval for every call.
+func (b *retryBudget) do(ctx context.Context, send func() error) error {
+	interval := b.cfg.InitialInterval
+	for attempt := 1; ; attempt++ {
+		err := send()
+		if err == nil || !isRetryableError(ctx, err) {
+			return err
+		}
+
+		wait := b.jitter(interval)
+		if b.now().Add(wait).After(b.deadline) {
+			return err
+		}
+
+		b.logger.Debug("Retrying traces request",
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", wait),
+			zap.Error(err),
+		)
+		if sleepErr := b.sleep(ctx, wait); sleepErr != nil {
+			return err
+		}
+
+		interval *= 2
+		if interval > b.cfg.MaxInterval {
+			interval = b.cfg.MaxInterval
+		}
+	}
+}
+
+// jitter returns the interval randomized by up to the randomization factor in both directions
+func (b *retryBudget) jitter(interval time.Duration) time.Duration {
+	delta := b.cfg.RandomizationFactor * float64(interval)
+	return time.Duration(float64(interval) - delta + 2*delta*b.random())
+}
+
+// isRetryableError returns whether the request failed in a way which may succeed
+// when it's sent again: a connection error, 429 Too Many Requests or a 5xx response
+func isRetryableError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var (
+		se *statusError
+		ue *url.Error
+		oe *net.OpError
+	)
+	switch {
+	case errors.As(err, &se):
+		return se.statusCode == http.StatusTooManyRequests || isFailureStatusCode(se.statusCode)
+	case errors.As(err, &ue), errors.As(err, &oe):
+		return true
+	default:
+		return false
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// splitTraces splits the traces by resource spans into chunks which serialized size
+// is at most maxSize. Resource spans larger than maxSize are put in their own chunks.
+func splitTraces(td pdata.Traces, maxSize int) []pdata.Traces {
+	rss := td.ResourceSpans()
+	if maxSize <= 0 || rss.Len() < 2 || tracesSizer.TracesSize(td) <= maxSize {
+		return []pdata.Traces{td}
+	}
+
+	var (
+		chunks      []pdata.Traces
+		current     pdata.Traces
+		currentSize int
+	)
+	for i := 0; i < rss.Len(); i++ {
+		single := pdata.NewTraces()
+		rss.At(i).CopyTo(single.ResourceSpans().AppendEmpty())
+		size := tracesSizer.TracesSize(single)
+
+		if currentSize > 0 && currentSize+size > maxSize {
+			chunks = append(chunks, current)
+			currentSize = 0
+		}
+		if currentSize == 0 {
+			current = pdata.NewTraces()
+		}
+		single.ResourceSpans().MoveAndAppendTo(current.ResourceSpans())
+		currentSize += size
+	}
+	return append(chunks, current)
+}
@@ -0,0 +1,198 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+// newTestRetryBudget returns the retry budget which records the waits instead of sleeping
+func newTestRetryBudget(cfg TracesRetryConfig, random float64) (*retryBudget, *[]time.Duration) {
+	now := time.Unix(0, 0)
+	waits := []time.Duration{}
+
+	b := newRetryBudget(cfg, zap.NewNop())
+	b.deadline = now.Add(cfg.MaxElapsedTime)
+	b.now = func() time.Time { return now }
+	b.random = func() float64 { return random }
+	b.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		now = now.Add(d)
+		return nil
+	}
+	return b, &waits
+}
+
+func TestRetryBudgetBackoff(t *testing.T) {
+	b, waits := newTestRetryBudget(TracesRetryConfig{
+		InitialInterval: time.Second,
+		MaxInterval:     3 * time.Second,
+		MaxElapsedTime:  time.Minute,
+	}, 0)
+
+	attempts := 0
+	err := b.do(context.Background(), func() error {
+		attempts++
+		if attempts < 5 {
+			return &statusError{statusCode: http.StatusServiceUnavailable, err: errors.New("unavailable")}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, attempts)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}, *waits)
+}
+
+func TestRetryBudgetJitter(t *testing.T) {
+	cfg := TracesRetryConfig{
+		InitialInterval:     time.Second,
+		MaxInterval:         time.Second,
+		MaxElapsedTime:      time.Minute,
+		RandomizationFactor: 0.5,
+	}
+
+	b, _ := newTestRetryBudget(cfg, 0)
+	assert.Equal(t, 500*time.Millisecond, b.jitter(time.Second))
+
+	b, _ = newTestRetryBudget(cfg, 0.5)
+	assert.Equal(t, time.Second, b.jitter(time.Second))
+
+	b, _ = newTestRetryBudget(cfg, 0.99)
+	assert.Equal(t, 1490*time.Millisecond, b.jitter(time.Second))
+}
+
+func TestRetryBudgetSharedMaxElapsedTime(t *testing.T) {
+	b, waits := newTestRetryBudget(TracesRetryConfig{
+		InitialInterval: time.Second,
+		MaxInterval:     time.Second,
+		MaxElapsedTime:  5 * time.Second,
+	}, 0)
+
+	failing := func() error {
+		return &url.Error{Op: "Post", URL: "http://localhost", Err: errors.New("connection refused")}
+	}
+
+	// the first chunk uses 3s of the budget
+	attempts := 0
+	err := b.do(context.Background(), func() error {
+		attempts++
+		if attempts < 4 {
+			return failing()
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// the second chunk is retried only within the remaining 2s
+	err = b.do(context.Background(), failing)
+	assert.Error(t, err)
+	assert.Len(t, *waits, 5)
+}
+
+func TestRetryBudgetNotRetryableError(t *testing.T) {
+	b, waits := newTestRetryBudget(TracesRetryConfig{
+		InitialInterval: time.Second,
+		MaxInterval:     time.Second,
+		MaxElapsedTime:  time.Minute,
+	}, 0)
+
+	err := b.do(context.Background(), func() error { return errUnauthorized })
+	assert.ErrorIs(t, err, errUnauthorized)
+	assert.Empty(t, *waits)
+}
+
+func TestIsRetryableError(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		err      error
+		expected bool
+	}{
+		{
+			name:     "server error",
+			ctx:      context.Background(),
+			err:      &statusError{statusCode: http.StatusBadGateway, err: errors.New("bad gateway")},
+			expected: true,
+		},
+		{
+			name:     "too many requests",
+			ctx:      context.Background(),
+			err:      &statusError{statusCode: http.StatusTooManyRequests, err: errors.New("too many requests")},
+			expected: true,
+		},
+		{
+			name: "client error",
+			ctx:  context.Background(),
+			err:  &statusError{statusCode: http.StatusBadRequest, err: errors.New("bad request")},
+		},
+		{
+			name:     "connection error",
+			ctx:      context.Background(),
+			err:      &url.Error{Op: "Post", URL: "http://localhost", Err: errors.New("connection refused")},
+			expected: true,
+		},
+		{
+			name: "canceled context",
+			ctx:  canceled,
+			err:  &url.Error{Op: "Post", URL: "http://localhost", Err: context.Canceled},
+		},
+		{
+			name: "circuit breaker open",
+			ctx:  context.Background(),
+			err:  errCircuitBreakerOpen,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isRetryableError(tc.ctx, tc.err))
+		})
+	}
+}
+
+func TestSplitTraces(t *testing.T) {
+	td := pdata.NewTraces()
+	for _, name := range []string{"service-1", "service-2", "service-3"} {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().InsertString("service.name", name)
+		rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty().SetName(name)
+	}
+	single := pdata.NewTraces()
+	td.ResourceSpans().At(0).CopyTo(single.ResourceSpans().AppendEmpty())
+	singleSize := tracesSizer.TracesSize(single)
+
+	assert.Len(t, splitTraces(td, 0), 1)
+	assert.Len(t, splitTraces(td, 3*singleSize), 1)
+
+	chunks := splitTraces(td, 2*singleSize)
+	if assert.Len(t, chunks, 2) {
+		assert.Equal(t, 2, chunks[0].SpanCount())
+		assert.Equal(t, 1, chunks[1].SpanCount())
+	}
+
+	assert.Len(t, splitTraces(td, 1), 3)
+}
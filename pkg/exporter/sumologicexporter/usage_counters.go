@@ -0,0 +1,281 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	usageCountersStorageName = "usage_counters"
+	usageCountersStorageKey  = "days"
+	usageCountersDayLayout   = "2006-01-02"
+
+	// usageDefaultCategory is used for the data sent without the source category header,
+	// for which the source category of the HTTP source is applied
+	usageDefaultCategory = "_default"
+)
+
+// usageCounters counts the bytes sent per source category and day (in UTC).
+// The counters are persisted with a storage extension, so they survive restarts.
+type usageCounters struct {
+	logger        *zap.Logger
+	storageID     *config.ComponentID
+	endpoint      string
+	retentionDays int
+	flushInterval time.Duration
+	now           func() time.Time
+
+	// lock guards days and dirty
+	lock sync.Mutex
+	// days maps the day to the number of bytes sent per source category
+	days  map[string]map[string]uint64
+	dirty bool
+
+	client storage.Client
+	server *http.Server
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newUsageCounters(cfg UsageCountersConfig, logger *zap.Logger) *usageCounters {
+	return &usageCounters{
+		logger:        logger,
+		storageID:     cfg.StorageID,
+		endpoint:      cfg.Endpoint,
+		retentionDays: cfg.RetentionDays,
+		flushInterval: cfg.FlushInterval,
+		now:           time.Now,
+		days:          make(map[string]map[string]uint64),
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// start loads the persisted counters and starts the HTTP endpoint
+func (uc *usageCounters) start(ctx context.Context, host component.Host, id config.ComponentID) error {
+	if uc.storageID != nil {
+		ext, ok := host.GetExtensions()[*uc.storageID]
+		if !ok {
+			return fmt.Errorf("storage extension %s not found", uc.storageID)
+		}
+		se, ok := ext.(storage.Extension)
+		if !ok {
+			return fmt.Errorf("extension %s is not a storage extension", uc.storageID)
+		}
+		client, err := se.GetClient(ctx, component.KindExporter, id, usageCountersStorageName)
+		if err != nil {
+			return fmt.Errorf("failed to get storage client: %w", err)
+		}
+		uc.client = client
+
+		if err := uc.load(ctx); err != nil {
+			return err
+		}
+
+		uc.wg.Add(1)
+		go uc.flushLoop()
+	}
+
+	if uc.endpoint != "" {
+		ln, err := net.Listen("tcp", uc.endpoint)
+		if err != nil {
+			return fmt.Errorf("failed to listen on usage counters endpoint: %w", err)
+		}
+		uc.server = &http.Server{Handler: uc}
+
+		uc.wg.Add(1)
+		go func() {
+			defer uc.wg.Done()
+			if err := uc.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				uc.logger.Error("Usage counters endpoint failed", zap.Error(err))
+			}
+		}()
+	}
+
+	return nil
+}
+
+// shutdown stops the HTTP endpoint and persists the counters
+func (uc *usageCounters) shutdown(ctx context.Context) error {
+	close(uc.stopCh)
+
+	var err error
+	if uc.server != nil {
+		err = uc.server.Shutdown(ctx)
+	}
+	uc.wg.Wait()
+
+	if uc.client != nil {
+		if fErr := uc.flush(ctx); fErr != nil && err == nil {
+			err = fErr
+		}
+		if cErr := uc.client.Close(ctx); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+// add increases the counter of the given source category for the current day
+func (uc *usageCounters) add(category string, bytes int64) {
+	if category == "" {
+		category = usageDefaultCategory
+	}
+	day := uc.now().UTC().Format(usageCountersDayLayout)
+
+	uc.lock.Lock()
+	defer uc.lock.Unlock()
+
+	counters, ok := uc.days[day]
+	if !ok {
+		counters = make(map[string]uint64)
+		uc.days[day] = counters
+		uc.prune()
+	}
+	counters[category] += uint64(bytes)
+	uc.dirty = true
+}
+
+// snapshot returns a copy of the counters which are within the retention period
+func (uc *usageCounters) snapshot() map[string]map[string]uint64 {
+	uc.lock.Lock()
+	defer uc.lock.Unlock()
+
+	uc.prune()
+	ret := make(map[string]map[string]uint64, len(uc.days))
+	for day, counters := range uc.days {
+		c := make(map[string]uint64, len(counters))
+		for category, bytes := range counters {
+			c[category] = bytes
+		}
+		ret[day] = c
+	}
+	return ret
+}
+
+// prune removes the days which are out of the retention period, it has to be called with lock held
+func (uc *usageCounters) prune() {
+	// The days are formatted as YYYY-MM-DD, so they can be compared as strings
+	oldest := uc.now().UTC().AddDate(0, 0, -(uc.retentionDays - 1)).Format(usageCountersDayLayout)
+	for day := range uc.days {
+		if day < oldest {
+			delete(uc.days, day)
+			uc.dirty = true
+		}
+	}
+}
+
+func (uc *usageCounters) load(ctx context.Context) error {
+	data, err := uc.client.Get(ctx, usageCountersStorageKey)
+	if err != nil {
+		return fmt.Errorf("failed to load usage counters: %w", err)
+	}
+	if data == nil {
+		return nil
+	}
+
+	days := make(map[string]map[string]uint64)
+	if err := json.Unmarshal(data, &days); err != nil {
+		// Counters are not critical, so don't prevent the exporter from starting
+		uc.logger.Warn("Failed to decode persisted usage counters, starting from zero", zap.Error(err))
+		return nil
+	}
+
+	uc.lock.Lock()
+	defer uc.lock.Unlock()
+	uc.days = days
+	uc.prune()
+	return nil
+}
+
+// flush persists the counters if they have changed since the last flush
+func (uc *usageCounters) flush(ctx context.Context) error {
+	uc.lock.Lock()
+	if !uc.dirty {
+		uc.lock.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(uc.days)
+	uc.dirty = false
+	uc.lock.Unlock()
+
+	if err == nil {
+		err = uc.client.Set(ctx, usageCountersStorageKey, data)
+	}
+	if err != nil {
+		uc.lock.Lock()
+		uc.dirty = true
+		uc.lock.Unlock()
+		return fmt.Errorf("failed to persist usage counters: %w", err)
+	}
+	return nil
+}
+
+func (uc *usageCounters) flushLoop() {
+	defer uc.wg.Done()
+
+	ticker := time.NewTicker(uc.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := uc.flush(context.Background()); err != nil {
+				uc.logger.Warn("Failed to flush usage counters", zap.Error(err))
+			}
+		case <-uc.stopCh:
+			return
+		}
+	}
+}
+
+// ServeHTTP responds with the counters as JSON object,
+// e.g. {"2022-05-01": {"prod/app": 1024}}
+func (uc *usageCounters) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set(headerContentType, "application/json")
+	if err := json.NewEncoder(w).Encode(uc.snapshot()); err != nil {
+		uc.logger.Debug("Failed to write usage counters response", zap.Error(err))
+	}
+}
+
+// countingReader counts the bytes read from the underlying reader
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,185 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+// memoryStorage is a storage extension keeping the data in memory, shared by all its clients
+type memoryStorage struct {
+	component.Extension
+	lock sync.Mutex
+	data map[string][]byte
+}
+
+func (ms *memoryStorage) GetClient(context.Context, component.Kind, config.ComponentID, string) (storage.Client, error) {
+	return &memoryStorageClient{ms: ms}, nil
+}
+
+type memoryStorageClient struct {
+	storage.Client
+	ms *memoryStorage
+}
+
+func (c *memoryStorageClient) Get(_ context.Context, key string) ([]byte, error) {
+	c.ms.lock.Lock()
+	defer c.ms.lock.Unlock()
+	return c.ms.data[key], nil
+}
+
+func (c *memoryStorageClient) Set(_ context.Context, key string, value []byte) error {
+	c.ms.lock.Lock()
+	defer c.ms.lock.Unlock()
+	c.ms.data[key] = value
+	return nil
+}
+
+func (c *memoryStorageClient) Close(context.Context) error {
+	return nil
+}
+
+type storageTestHost struct {
+	component.Host
+	extensions map[config.ComponentID]component.Extension
+}
+
+func (h storageTestHost) GetExtensions() map[config.ComponentID]component.Extension {
+	return h.extensions
+}
+
+func newStorageTestHost(id config.ComponentID, ms *memoryStorage) component.Host {
+	return storageTestHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[config.ComponentID]component.Extension{id: ms},
+	}
+}
+
+func TestUsageCountersRetention(t *testing.T) {
+	now := time.Date(2022, 5, 1, 23, 0, 0, 0, time.UTC)
+	uc := newUsageCounters(UsageCountersConfig{RetentionDays: 2}, zap.NewNop())
+	uc.now = func() time.Time { return now }
+
+	uc.add("prod/app", 100)
+	uc.add("prod/app", 20)
+	uc.add("", 5)
+
+	now = now.Add(2 * time.Hour)
+	uc.add("prod/app", 1)
+
+	assert.Equal(t, map[string]map[string]uint64{
+		"2022-05-01": {"prod/app": 120, usageDefaultCategory: 5},
+		"2022-05-02": {"prod/app": 1},
+	}, uc.snapshot())
+
+	now = now.Add(24 * time.Hour)
+	assert.Equal(t, map[string]map[string]uint64{
+		"2022-05-02": {"prod/app": 1},
+	}, uc.snapshot())
+}
+
+func TestUsageCountersPersistence(t *testing.T) {
+	storageID := config.NewComponentID("memory_storage")
+	ms := &memoryStorage{data: map[string][]byte{}}
+	host := newStorageTestHost(storageID, ms)
+	cfg := UsageCountersConfig{
+		StorageID:     &storageID,
+		RetentionDays: 7,
+		FlushInterval: time.Hour,
+	}
+
+	uc := newUsageCounters(cfg, zap.NewNop())
+	require.NoError(t, uc.start(context.Background(), host, config.NewComponentID(typeStr)))
+	uc.add("prod/app", 1024)
+	require.NoError(t, uc.shutdown(context.Background()))
+
+	restarted := newUsageCounters(cfg, zap.NewNop())
+	require.NoError(t, restarted.start(context.Background(), host, config.NewComponentID(typeStr)))
+	t.Cleanup(func() { assert.NoError(t, restarted.shutdown(context.Background())) })
+	restarted.add("prod/app", 1)
+
+	day := time.Now().UTC().Format(usageCountersDayLayout)
+	assert.Equal(t, map[string]map[string]uint64{
+		day: {"prod/app": 1025},
+	}, restarted.snapshot())
+}
+
+func TestUsageCountersMissingStorage(t *testing.T) {
+	storageID := config.NewComponentID("memory_storage")
+	uc := newUsageCounters(UsageCountersConfig{
+		StorageID:     &storageID,
+		RetentionDays: 7,
+		FlushInterval: time.Hour,
+	}, zap.NewNop())
+
+	err := uc.start(context.Background(), componenttest.NewNopHost(), config.NewComponentID(typeStr))
+	assert.EqualError(t, err, "storage extension memory_storage not found")
+}
+
+func TestUsageCountersServeHTTP(t *testing.T) {
+	uc := newUsageCounters(UsageCountersConfig{RetentionDays: 1}, zap.NewNop())
+	uc.add("prod/app", 10)
+
+	rec := httptest.NewRecorder()
+	uc.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var resp map[string]map[string]uint64
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, uc.snapshot(), resp)
+
+	rec = httptest.NewRecorder()
+	uc.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestSendLogsUsageCounters(t *testing.T) {
+	test := prepareSenderTest(t, []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {},
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+	}, func(cfg *Config) {
+		cfg.CompressEncoding = GZIPCompression
+	})
+	test.s.usageCounters = newUsageCounters(UsageCountersConfig{RetentionDays: 1}, zap.NewNop())
+
+	// Failed requests are not counted
+	for i := 0; i < 2; i++ {
+		test.s.logBuffer = logRecordsToLogPair(exampleTwoLogs())
+		_, _ = test.s.sendLogs(context.Background(), fieldsFromMap(map[string]string{}))
+	}
+	assert.EqualValues(t, 2, *test.reqCounter)
+
+	day := time.Now().UTC().Format(usageCountersDayLayout)
+	assert.Equal(t, map[string]map[string]uint64{
+		day: {"source_category": uint64(len("Example log\nAnother example log"))},
+	}, test.s.usageCounters.snapshot())
+}
@@ -0,0 +1,98 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const (
+	headerUserAgent string = "User-Agent"
+
+	unknownVersion string = "unknown"
+	noCompression  string = "none"
+)
+
+// newUserAgent returns the value of the User-Agent header describing the collector,
+// i.e. its version, the OS and architecture and the enabled features.
+// The configured user agent is returned as is.
+func newUserAgent(cfg *Config, buildInfo component.BuildInfo) string {
+	if cfg.UserAgent != "" {
+		return cfg.UserAgent
+	}
+
+	command := buildInfo.Command
+	if command == "" {
+		command = cfg.Client
+	}
+	version := buildInfo.Version
+	if version == "" {
+		version = unknownVersion
+	}
+
+	compression := string(cfg.CompressEncoding)
+	if compression == "" {
+		compression = noCompression
+	}
+
+	details := []string{
+		fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+		fmt.Sprintf("compress_encoding=%s", compression),
+		fmt.Sprintf("log_format=%s", cfg.LogFormat),
+		fmt.Sprintf("metric_format=%s", cfg.MetricFormat),
+		fmt.Sprintf("trace_format=%s", cfg.TraceFormat),
+	}
+	if features := enabledFeatures(cfg); len(features) > 0 {
+		details = append(details, fmt.Sprintf("features=%s", strings.Join(features, ",")))
+	}
+
+	return fmt.Sprintf("%s/%s (%s)", command, version, strings.Join(details, "; "))
+}
+
+// enabledFeatures returns the names of the optional features enabled in the configuration,
+// in alphabetical order.
+func enabledFeatures(cfg *Config) []string {
+	features := []struct {
+		name    string
+		enabled bool
+	}{
+		{"adaptive_batching", cfg.AdaptiveBatching.Enabled},
+		{"backpressure", cfg.Backpressure.Enabled},
+		{"circuit_breaker", cfg.CircuitBreaker.Enabled},
+		{"debug", cfg.Debug.Enabled},
+		{"drop_audit", cfg.DropAudit.Enabled},
+		{"dry_run", cfg.DryRun},
+		{"endpoint_discovery", cfg.EndpointDiscovery.Enabled},
+		{"graphite_tcp", cfg.GraphiteTCP.Endpoint != ""},
+		{"payload_sampling", cfg.PayloadSampling.Enabled},
+		{"routes", len(cfg.Routes) > 0},
+		{"sending_queue", cfg.QueueSettings.Enabled},
+		{"source_host_fallback", cfg.SourceHostFallback.Enabled},
+		{"traces_retry", cfg.TracesRetry.Enabled},
+		{"usage_counters", cfg.UsageCounters.Enabled},
+	}
+
+	var ret []string
+	for _, f := range features {
+		if f.enabled {
+			ret = append(ret, f.name)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,97 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicexporter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+)
+
+func TestNewUserAgent(t *testing.T) {
+	buildInfo := component.BuildInfo{Command: "otelcol-sumo", Version: "v0.0.48"}
+	platform := fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)
+
+	testcases := []struct {
+		name      string
+		buildInfo component.BuildInfo
+		cfgFn     func(*Config)
+		expected  string
+	}{
+		{
+			name:      "default",
+			buildInfo: buildInfo,
+			cfgFn:     func(*Config) {},
+			expected: "otelcol-sumo/v0.0.48 (" + platform +
+				"; compress_encoding=gzip; log_format=otlp; metric_format=otlp; trace_format=otlp)",
+		},
+		{
+			name:      "enabled features",
+			buildInfo: buildInfo,
+			cfgFn: func(cfg *Config) {
+				cfg.QueueSettings.Enabled = true
+				cfg.CompressEncoding = NoCompression
+				cfg.LogFormat = JSONFormat
+				cfg.CircuitBreaker.Enabled = true
+				cfg.AdaptiveBatching.Enabled = true
+				cfg.Routes = []RouteConfig{{SourceCategory: "prod/.*", Endpoint: "http://localhost"}}
+			},
+			expected: "otelcol-sumo/v0.0.48 (" + platform +
+				"; compress_encoding=none; log_format=json; metric_format=otlp; trace_format=otlp; features=adaptive_batching,circuit_breaker,routes,sending_queue)",
+		},
+		{
+			name:      "no build info",
+			buildInfo: component.BuildInfo{},
+			cfgFn:     func(*Config) {},
+			expected: "otelcol/unknown (" + platform +
+				"; compress_encoding=gzip; log_format=otlp; metric_format=otlp; trace_format=otlp)",
+		},
+		{
+			name:      "overridden",
+			buildInfo: buildInfo,
+			cfgFn: func(cfg *Config) {
+				cfg.UserAgent = "my-collector/1.0"
+			},
+			expected: "my-collector/1.0",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tc.cfgFn(cfg)
+			assert.Equal(t, tc.expected, newUserAgent(cfg, tc.buildInfo))
+		})
+	}
+}
+
+func TestPushLogsSendsUserAgent(t *testing.T) {
+	test := prepareExporterTest(t, createTestConfig(), []func(w http.ResponseWriter, req *http.Request){
+		func(w http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, "my-collector/1.0", req.Header.Get("User-Agent"))
+			assert.Equal(t, "otelcol", req.Header.Get("X-Sumo-Client"))
+		},
+	}, func(cfg *Config) {
+		cfg.UserAgent = "my-collector/1.0"
+	})
+
+	require.NoError(t, test.exp.pushLogsData(context.Background(), LogRecordsToLogs(exampleLog())))
+}
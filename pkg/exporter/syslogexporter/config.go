@@ -0,0 +1,111 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogexporter
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// Config defines configuration for the syslog exporter.
+type Config struct {
+	config.ExporterSettings        `mapstructure:",squash"`
+	exporterhelper.TimeoutSettings `mapstructure:",squash"`
+	exporterhelper.QueueSettings   `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings   `mapstructure:"retry_on_failure"`
+
+	// Endpoint is the address of the syslog server in the host:port form.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// TLSSetting configures TLS of the connection to the syslog server.
+	// The messages are sent over plain TCP when insecure is set to true.
+	TLSSetting configtls.TLSClientSetting `mapstructure:"tls"`
+
+	// Framing defines how the messages are delimited in the TCP stream,
+	// either octet_counting (RFC 6587 section 3.4.1) or non_transparent (terminated by a newline).
+	// By default this is octet_counting.
+	Framing string `mapstructure:"framing"`
+
+	// FacilityAttribute is the log record or resource attribute holding the facility of the message,
+	// either its keyword (e.g. local0) or its code (e.g. 16).
+	// By default this is syslog.facility.
+	FacilityAttribute string `mapstructure:"facility_attribute"`
+
+	// DefaultFacility is the facility of the messages of the logs without the facility attribute.
+	// By default this is user.
+	DefaultFacility string `mapstructure:"default_facility"`
+
+	// SeverityAttribute is the log record or resource attribute holding the severity of the message,
+	// either its keyword (e.g. warning) or its code (e.g. 4). The logs without it have their severity
+	// mapped from the severity number of the log record.
+	// By default this is syslog.severity.
+	SeverityAttribute string `mapstructure:"severity_attribute"`
+
+	// DefaultSeverity is the severity of the messages of the logs without the severity attribute
+	// and without the severity number.
+	// By default this is info.
+	DefaultSeverity string `mapstructure:"default_severity"`
+
+	// HostnameAttribute is the log record or resource attribute used as the HOSTNAME of the message.
+	// By default this is host.name.
+	HostnameAttribute string `mapstructure:"hostname_attribute"`
+
+	// AppNameAttribute is the log record or resource attribute used as the APP-NAME of the message.
+	// By default this is service.name.
+	AppNameAttribute string `mapstructure:"app_name_attribute"`
+}
+
+const (
+	// octetCountingFraming prefixes every message with its length
+	octetCountingFraming = "octet_counting"
+	// nonTransparentFraming terminates every message with a newline
+	nonTransparentFraming = "non_transparent"
+)
+
+var _ config.Exporter = (*Config)(nil)
+
+// Validate checks if the exporter configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.Endpoint == "" {
+		return errors.New("endpoint must be set")
+	}
+	if _, _, err := net.SplitHostPort(cfg.Endpoint); err != nil {
+		return fmt.Errorf("invalid endpoint %q: %w", cfg.Endpoint, err)
+	}
+
+	switch cfg.Framing {
+	case octetCountingFraming, nonTransparentFraming:
+	default:
+		return fmt.Errorf("unexpected framing: %q", cfg.Framing)
+	}
+
+	if _, err := parseFacility(cfg.DefaultFacility); err != nil {
+		return fmt.Errorf("invalid default_facility: %w", err)
+	}
+	if _, err := parseSeverity(cfg.DefaultSeverity); err != nil {
+		return fmt.Errorf("invalid default_severity: %w", err)
+	}
+
+	if cfg.Timeout <= 0 {
+		return fmt.Errorf("timeout has to be positive: %s", cfg.Timeout)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogexporter
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Exporters[factory.Type()] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	defaultCfg := factory.CreateDefaultConfig().(*Config)
+	defaultCfg.Endpoint = "siem.example.com:6514"
+	assert.Equal(t, defaultCfg, cfg.Exporters[config.NewComponentID(typeStr)])
+
+	localCfg := factory.CreateDefaultConfig().(*Config)
+	localCfg.ExporterSettings = config.NewExporterSettings(config.NewComponentIDWithName(typeStr, "local"))
+	localCfg.Endpoint = "localhost:514"
+	localCfg.TLSSetting.Insecure = true
+	localCfg.Framing = nonTransparentFraming
+	localCfg.FacilityAttribute = "facility"
+	localCfg.DefaultFacility = "local0"
+	localCfg.SeverityAttribute = ""
+	localCfg.DefaultSeverity = "notice"
+	localCfg.HostnameAttribute = "k8s.node.name"
+	localCfg.AppNameAttribute = "k8s.container.name"
+	localCfg.Timeout = 10 * time.Second
+	localCfg.QueueSettings.Enabled = false
+	localCfg.RetrySettings.Enabled = false
+	assert.Equal(t, localCfg, cfg.Exporters[config.NewComponentIDWithName(typeStr, "local")])
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name   string
+		modify func(cfg *Config)
+		errMsg string
+	}{
+		{
+			name:   "valid",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name: "codes",
+			modify: func(cfg *Config) {
+				cfg.DefaultFacility = "23"
+				cfg.DefaultSeverity = "0"
+			},
+		},
+		{
+			name: "no endpoint",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = ""
+			},
+			errMsg: "endpoint must be set",
+		},
+		{
+			name: "endpoint without port",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "siem.example.com"
+			},
+			errMsg: `invalid endpoint "siem.example.com": address siem.example.com: missing port in address`,
+		},
+		{
+			name: "unknown framing",
+			modify: func(cfg *Config) {
+				cfg.Framing = "udp"
+			},
+			errMsg: `unexpected framing: "udp"`,
+		},
+		{
+			name: "unknown facility",
+			modify: func(cfg *Config) {
+				cfg.DefaultFacility = "local8"
+			},
+			errMsg: `invalid default_facility: unknown keyword or code: "local8"`,
+		},
+		{
+			name: "facility code out of range",
+			modify: func(cfg *Config) {
+				cfg.DefaultFacility = "24"
+			},
+			errMsg: `invalid default_facility: unknown keyword or code: "24"`,
+		},
+		{
+			name: "unknown severity",
+			modify: func(cfg *Config) {
+				cfg.DefaultSeverity = "fatal"
+			},
+			errMsg: `invalid default_severity: unknown keyword or code: "fatal"`,
+		},
+		{
+			name: "zero timeout",
+			modify: func(cfg *Config) {
+				cfg.Timeout = 0
+			},
+			errMsg: "timeout has to be positive: 0s",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = "siem.example.com:6514"
+			tc.modify(cfg)
+
+			if tc.errMsg == "" {
+				assert.NoError(t, cfg.Validate())
+			} else {
+				assert.EqualError(t, cfg.Validate(), tc.errMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogexporter
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+// syslogExporter forwards the logs as RFC 5424 syslog messages over TCP or TLS.
+// The connection is kept open between the pushes and reestablished by the next push
+// after a failed write.
+type syslogExporter struct {
+	config    *Config
+	logger    *zap.Logger
+	formatter *formatter
+	tlsConfig *tls.Config
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func newSyslogExporter(cfg *Config, logger *zap.Logger) (*syslogExporter, error) {
+	f, err := newFormatter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	se := &syslogExporter{
+		config:    cfg,
+		logger:    logger,
+		formatter: f,
+	}
+
+	if !cfg.TLSSetting.Insecure {
+		tlsConfig, err := cfg.TLSSetting.LoadTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS config: %w", err)
+		}
+		if tlsConfig.ServerName == "" {
+			host, _, err := net.SplitHostPort(cfg.Endpoint)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.ServerName = host
+		}
+		se.tlsConfig = tlsConfig
+	}
+
+	return se, nil
+}
+
+func (se *syslogExporter) pushLogs(ctx context.Context, ld pdata.Logs) error {
+	var buf bytes.Buffer
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resource := rl.Resource().Attributes()
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			records := ills.At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				se.frame(&buf, se.formatter.format(records.At(k), resource))
+			}
+		}
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	return se.write(ctx, buf.Bytes())
+}
+
+// frame appends the message to the buffer with the configured framing
+func (se *syslogExporter) frame(buf *bytes.Buffer, message string) {
+	switch se.config.Framing {
+	case nonTransparentFraming:
+		buf.WriteString(message)
+		buf.WriteByte('\n')
+	default:
+		buf.WriteString(strconv.Itoa(len(message)))
+		buf.WriteByte(' ')
+		buf.WriteString(message)
+	}
+}
+
+func (se *syslogExporter) write(ctx context.Context, data []byte) error {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	conn, err := se.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(se.config.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetWriteDeadline(deadline); err != nil {
+		se.closeConn()
+		return err
+	}
+
+	if _, err := conn.Write(data); err != nil {
+		se.logger.Warn("Failed writing to syslog endpoint, the connection is going to be reestablished",
+			zap.String("endpoint", se.config.Endpoint),
+			zap.Error(err),
+		)
+		se.closeConn()
+		return err
+	}
+	return nil
+}
+
+// connect returns the open connection or establishes a new one.
+// It has to be called with the lock held.
+func (se *syslogExporter) connect(ctx context.Context) (net.Conn, error) {
+	if se.conn != nil {
+		return se.conn, nil
+	}
+
+	dialer := &net.Dialer{Timeout: se.config.Timeout}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if se.tlsConfig != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: se.tlsConfig}).DialContext(ctx, "tcp", se.config.Endpoint)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", se.config.Endpoint)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed connecting to syslog endpoint %s: %w", se.config.Endpoint, err)
+	}
+
+	se.conn = conn
+	return conn, nil
+}
+
+// closeConn closes the connection, so the next write reconnects.
+// It has to be called with the lock held.
+func (se *syslogExporter) closeConn() {
+	if se.conn == nil {
+		return
+	}
+	if err := se.conn.Close(); err != nil {
+		se.logger.Debug("Failed closing connection to syslog endpoint", zap.Error(err))
+	}
+	se.conn = nil
+}
+
+func (se *syslogExporter) shutdown(context.Context) error {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	if se.conn == nil {
+		return nil
+	}
+	err := se.conn.Close()
+	se.conn = nil
+	return err
+}
@@ -0,0 +1,253 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogexporter
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+func TestPushLogsOctetCounting(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = ln.Addr().String()
+	cfg.TLSSetting.Insecure = true
+
+	received := acceptMessages(t, ln, 2, readOctetCounted)
+
+	se, err := newSyslogExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	defer se.shutdown(context.Background())
+
+	require.NoError(t, se.pushLogs(context.Background(), exampleLogs("first", "second")))
+
+	assert.Equal(t, []string{
+		"<14>1 - web-1 checkout - - - first",
+		"<14>1 - web-1 checkout - - - second",
+	}, <-received)
+}
+
+func TestPushLogsNonTransparent(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = ln.Addr().String()
+	cfg.TLSSetting.Insecure = true
+	cfg.Framing = nonTransparentFraming
+
+	received := acceptMessages(t, ln, 2, readLine)
+
+	se, err := newSyslogExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	defer se.shutdown(context.Background())
+
+	require.NoError(t, se.pushLogs(context.Background(), exampleLogs("first", "second")))
+
+	assert.Equal(t, []string{
+		"<14>1 - web-1 checkout - - - first",
+		"<14>1 - web-1 checkout - - - second",
+	}, <-received)
+}
+
+func TestPushLogsTLS(t *testing.T) {
+	cert := selfSignedCertificate(t)
+	ln, err := tls.Listen("tcp", "localhost:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+	defer ln.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = ln.Addr().String()
+	cfg.TLSSetting.InsecureSkipVerify = true
+
+	received := acceptMessages(t, ln, 1, readOctetCounted)
+
+	se, err := newSyslogExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	defer se.shutdown(context.Background())
+
+	require.NoError(t, se.pushLogs(context.Background(), exampleLogs("over tls")))
+
+	assert.Equal(t, []string{"<14>1 - web-1 checkout - - - over tls"}, <-received)
+}
+
+func TestPushLogsTLSUntrustedCertificate(t *testing.T) {
+	cert := selfSignedCertificate(t)
+	ln, err := tls.Listen("tcp", "localhost:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = ln.Addr().String()
+
+	se, err := newSyslogExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	defer se.shutdown(context.Background())
+
+	err = se.pushLogs(context.Background(), exampleLogs("untrusted"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed connecting to syslog endpoint")
+}
+
+func TestPushLogsReconnects(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = ln.Addr().String()
+	cfg.TLSSetting.Insecure = true
+
+	se, err := newSyslogExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	defer se.shutdown(context.Background())
+
+	// the first connection is closed by the server right after the first message
+	received := acceptMessages(t, ln, 1, readOctetCounted)
+	require.NoError(t, se.pushLogs(context.Background(), exampleLogs("first")))
+	assert.Equal(t, []string{"<14>1 - web-1 checkout - - - first"}, <-received)
+
+	// writes to the closed connection eventually fail and the connection is reestablished by the next push
+	received = acceptMessages(t, ln, 1, readOctetCounted)
+	assert.Eventually(t, func() bool {
+		return se.pushLogs(context.Background(), exampleLogs("lost")) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, se.pushLogs(context.Background(), exampleLogs("second")))
+	assert.Equal(t, []string{"<14>1 - web-1 checkout - - - second"}, <-received)
+}
+
+func TestPushLogsEndpointUnavailable(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = ln.Addr().String()
+	cfg.TLSSetting.Insecure = true
+	require.NoError(t, ln.Close())
+
+	se, err := newSyslogExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	err = se.pushLogs(context.Background(), exampleLogs("first"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed connecting to syslog endpoint "+cfg.Endpoint)
+}
+
+func exampleLogs(bodies ...string) pdata.Logs {
+	logs := pdata.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString("host.name", "web-1")
+	rl.Resource().Attributes().InsertString("service.name", "checkout")
+	records := rl.InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+	for _, body := range bodies {
+		records.AppendEmpty().Body().SetStringVal(body)
+	}
+	return logs
+}
+
+// acceptMessages accepts a single connection, reads count messages from it and closes it
+func acceptMessages(
+	t *testing.T,
+	ln net.Listener,
+	count int,
+	read func(r *bufio.Reader) (string, error),
+) <-chan []string {
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		var messages []string
+		for i := 0; i < count; i++ {
+			message, err := read(r)
+			if !assert.NoError(t, err) {
+				return
+			}
+			messages = append(messages, message)
+		}
+		received <- messages
+	}()
+	return received
+}
+
+func readOctetCounted(r *bufio.Reader) (string, error) {
+	length, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+	if err != nil {
+		return "", err
+	}
+	message := make([]byte, n)
+	if _, err := io.ReadFull(r, message); err != nil {
+		return "", err
+	}
+	return string(message), nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	return strings.TrimSuffix(line, "\n"), err
+}
+
+func selfSignedCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
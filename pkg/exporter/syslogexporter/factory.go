@@ -0,0 +1,89 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogexporter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "syslog"
+
+	defaultTimeout           = 5 * time.Second
+	defaultFraming           = octetCountingFraming
+	defaultFacilityAttribute = "syslog.facility"
+	defaultFacility          = "user"
+	defaultSeverityAttribute = "syslog.severity"
+	defaultSeverity          = "info"
+	defaultHostnameAttribute = "host.name"
+	defaultAppNameAttribute  = "service.name"
+)
+
+// NewFactory returns a new factory for the syslog exporter.
+func NewFactory() component.ExporterFactory {
+	return component.NewExporterFactory(
+		typeStr,
+		createDefaultConfig,
+		component.WithLogsExporter(createLogsExporter),
+	)
+}
+
+func createDefaultConfig() config.Exporter {
+	return &Config{
+		ExporterSettings: config.NewExporterSettings(config.NewComponentID(typeStr)),
+		TimeoutSettings: exporterhelper.TimeoutSettings{
+			Timeout: defaultTimeout,
+		},
+		QueueSettings: exporterhelper.NewDefaultQueueSettings(),
+		RetrySettings: exporterhelper.NewDefaultRetrySettings(),
+
+		Framing:           defaultFraming,
+		FacilityAttribute: defaultFacilityAttribute,
+		DefaultFacility:   defaultFacility,
+		SeverityAttribute: defaultSeverityAttribute,
+		DefaultSeverity:   defaultSeverity,
+		HostnameAttribute: defaultHostnameAttribute,
+		AppNameAttribute:  defaultAppNameAttribute,
+	}
+}
+
+func createLogsExporter(
+	_ context.Context,
+	params component.ExporterCreateSettings,
+	cfg config.Exporter,
+) (component.LogsExporter, error) {
+	eCfg := cfg.(*Config)
+	se, err := newSyslogExporter(eCfg, params.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the syslog exporter: %w", err)
+	}
+
+	return exporterhelper.NewLogsExporter(
+		cfg,
+		params,
+		se.pushLogs,
+		exporterhelper.WithTimeout(eCfg.TimeoutSettings),
+		exporterhelper.WithQueue(eCfg.QueueSettings),
+		exporterhelper.WithRetry(eCfg.RetrySettings),
+		exporterhelper.WithShutdown(se.shutdown),
+	)
+}
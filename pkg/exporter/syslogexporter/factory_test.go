@@ -0,0 +1,49 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Equal(t, "", cfg.Endpoint)
+	assert.False(t, cfg.TLSSetting.Insecure)
+	assert.Equal(t, "octet_counting", cfg.Framing)
+	assert.Equal(t, "syslog.facility", cfg.FacilityAttribute)
+	assert.Equal(t, "user", cfg.DefaultFacility)
+	assert.Equal(t, "syslog.severity", cfg.SeverityAttribute)
+	assert.Equal(t, "info", cfg.DefaultSeverity)
+	assert.Equal(t, "host.name", cfg.HostnameAttribute)
+	assert.Equal(t, "service.name", cfg.AppNameAttribute)
+	assert.Equal(t, defaultTimeout, cfg.Timeout)
+}
+
+func TestCreateLogsExporter(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:6514"
+
+	exp, err := factory.CreateLogsExporter(context.Background(), componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, exp)
+	assert.NoError(t, exp.Shutdown(context.Background()))
+}
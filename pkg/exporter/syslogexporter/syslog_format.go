@@ -0,0 +1,223 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogexporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	// nilValue is used in place of the empty header fields
+	nilValue = "-"
+	// version is the version of the syslog protocol specification (RFC 5424)
+	version = 1
+
+	maxHostnameLength = 255
+	maxAppNameLength  = 48
+
+	timestampFormat = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// facilities maps the facility keywords to their codes, see RFC 5424 section 6.2.1
+var facilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"ntp":      12,
+	"audit":    13,
+	"alert":    14,
+	"clock":    15,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// severities maps the severity keywords to their codes, see RFC 5424 section 6.2.1
+var severities = map[string]int{
+	"emerg":   0,
+	"alert":   1,
+	"crit":    2,
+	"err":     3,
+	"warning": 4,
+	"notice":  5,
+	"info":    6,
+	"debug":   7,
+}
+
+const (
+	severityCritical      = 2
+	severityError         = 3
+	severityWarning       = 4
+	severityInformational = 6
+	severityDebug         = 7
+)
+
+func parseFacility(value string) (int, error) {
+	return parseCode(value, facilities, 23)
+}
+
+func parseSeverity(value string) (int, error) {
+	return parseCode(value, severities, 7)
+}
+
+// parseCode returns the code of the keyword, or the value itself if it's a number between 0 and maxCode
+func parseCode(value string, keywords map[string]int, maxCode int) (int, error) {
+	if code, ok := keywords[strings.ToLower(value)]; ok {
+		return code, nil
+	}
+	code, err := strconv.Atoi(value)
+	if err != nil || code < 0 || code > maxCode {
+		return 0, fmt.Errorf("unknown keyword or code: %q", value)
+	}
+	return code, nil
+}
+
+// severityFromNumber maps the OpenTelemetry severity number to the syslog severity
+func severityFromNumber(number pdata.SeverityNumber) (int, bool) {
+	switch {
+	case number >= pdata.SeverityNumberFATAL:
+		return severityCritical, true
+	case number >= pdata.SeverityNumberERROR:
+		return severityError, true
+	case number >= pdata.SeverityNumberWARN:
+		return severityWarning, true
+	case number >= pdata.SeverityNumberINFO:
+		return severityInformational, true
+	case number >= pdata.SeverityNumberTRACE:
+		return severityDebug, true
+	default:
+		return 0, false
+	}
+}
+
+// formatter renders the log records as RFC 5424 syslog messages
+type formatter struct {
+	config          *Config
+	defaultFacility int
+	defaultSeverity int
+}
+
+func newFormatter(cfg *Config) (*formatter, error) {
+	facility, err := parseFacility(cfg.DefaultFacility)
+	if err != nil {
+		return nil, err
+	}
+	severity, err := parseSeverity(cfg.DefaultSeverity)
+	if err != nil {
+		return nil, err
+	}
+	return &formatter{
+		config:          cfg,
+		defaultFacility: facility,
+		defaultSeverity: severity,
+	}, nil
+}
+
+// format returns the syslog message of the log record, without framing
+func (f *formatter) format(record pdata.LogRecord, resource pdata.AttributeMap) string {
+	attributes := record.Attributes()
+
+	facility := f.defaultFacility
+	if value, ok := lookupAttribute(f.config.FacilityAttribute, attributes, resource); ok {
+		if code, err := parseFacility(value); err == nil {
+			facility = code
+		}
+	}
+
+	severity := f.defaultSeverity
+	if value, ok := lookupAttribute(f.config.SeverityAttribute, attributes, resource); ok {
+		if code, err := parseSeverity(value); err == nil {
+			severity = code
+		}
+	} else if code, ok := severityFromNumber(record.SeverityNumber()); ok {
+		severity = code
+	}
+
+	timestamp := nilValue
+	if record.Timestamp() != 0 {
+		timestamp = record.Timestamp().AsTime().UTC().Format(timestampFormat)
+	}
+
+	hostname, _ := lookupAttribute(f.config.HostnameAttribute, attributes, resource)
+	appName, _ := lookupAttribute(f.config.AppNameAttribute, attributes, resource)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<%d>%d %s %s %s %s %s %s",
+		facility*8+severity,
+		version,
+		timestamp,
+		headerField(hostname, maxHostnameLength),
+		headerField(appName, maxAppNameLength),
+		nilValue, // PROCID
+		nilValue, // MSGID
+		nilValue, // STRUCTURED-DATA
+	)
+	if body := record.Body().AsString(); body != "" {
+		sb.WriteByte(' ')
+		sb.WriteString(body)
+	}
+	return sb.String()
+}
+
+// lookupAttribute returns the value of the log record attribute or, if it's missing, of the resource attribute
+func lookupAttribute(name string, attributes pdata.AttributeMap, resource pdata.AttributeMap) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+	if value, ok := attributes.Get(name); ok {
+		return value.AsString(), true
+	}
+	if value, ok := resource.Get(name); ok {
+		return value.AsString(), true
+	}
+	return "", false
+}
+
+// headerField returns the value with the characters not allowed in the header fields
+// (anything but printable US-ASCII without space) replaced with underscores,
+// truncated to the maximum length or the nil value if it's empty
+func headerField(value string, maxLength int) string {
+	if value == "" {
+		return nilValue
+	}
+	out := []byte(value)
+	for i, c := range out {
+		if c < 33 || c > 126 {
+			out[i] = '_'
+		}
+	}
+	if len(out) > maxLength {
+		out = out[:maxLength]
+	}
+	return string(out)
+}
@@ -0,0 +1,141 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogexporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestFormat(t *testing.T) {
+	timestamp := pdata.NewTimestampFromTime(time.Date(2022, 3, 4, 5, 6, 7, 890123000, time.UTC))
+
+	testcases := []struct {
+		name     string
+		modify   func(record pdata.LogRecord, resource pdata.AttributeMap)
+		expected string
+	}{
+		{
+			name:     "defaults",
+			modify:   func(record pdata.LogRecord, resource pdata.AttributeMap) {},
+			expected: "<14>1 2022-03-04T05:06:07.890123Z - - - - - hello world",
+		},
+		{
+			name: "resource attributes",
+			modify: func(record pdata.LogRecord, resource pdata.AttributeMap) {
+				resource.InsertString("host.name", "web-1")
+				resource.InsertString("service.name", "checkout")
+				resource.InsertString("syslog.facility", "local3")
+			},
+			expected: "<158>1 2022-03-04T05:06:07.890123Z web-1 checkout - - - hello world",
+		},
+		{
+			name: "record attributes take precedence",
+			modify: func(record pdata.LogRecord, resource pdata.AttributeMap) {
+				resource.InsertString("syslog.facility", "local3")
+				record.Attributes().InsertInt("syslog.facility", 4)
+				record.Attributes().InsertString("syslog.severity", "warning")
+			},
+			expected: "<36>1 2022-03-04T05:06:07.890123Z - - - - - hello world",
+		},
+		{
+			name: "severity number",
+			modify: func(record pdata.LogRecord, resource pdata.AttributeMap) {
+				record.SetSeverityNumber(pdata.SeverityNumberERROR2)
+			},
+			expected: "<11>1 2022-03-04T05:06:07.890123Z - - - - - hello world",
+		},
+		{
+			name: "severity attribute overrides severity number",
+			modify: func(record pdata.LogRecord, resource pdata.AttributeMap) {
+				record.SetSeverityNumber(pdata.SeverityNumberERROR)
+				record.Attributes().InsertString("syslog.severity", "NOTICE")
+			},
+			expected: "<13>1 2022-03-04T05:06:07.890123Z - - - - - hello world",
+		},
+		{
+			name: "invalid attributes fall back to defaults",
+			modify: func(record pdata.LogRecord, resource pdata.AttributeMap) {
+				record.Attributes().InsertString("syslog.facility", "local9")
+				record.Attributes().InsertString("syslog.severity", "fatal")
+			},
+			expected: "<14>1 2022-03-04T05:06:07.890123Z - - - - - hello world",
+		},
+		{
+			name: "header fields are sanitized",
+			modify: func(record pdata.LogRecord, resource pdata.AttributeMap) {
+				resource.InsertString("service.name", "my serviceé")
+			},
+			expected: "<14>1 2022-03-04T05:06:07.890123Z - my_service__ - - - hello world",
+		},
+		{
+			name: "no timestamp and body",
+			modify: func(record pdata.LogRecord, resource pdata.AttributeMap) {
+				record.SetTimestamp(0)
+				record.Body().SetStringVal("")
+			},
+			expected: "<14>1 - - - - - -",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			f, err := newFormatter(cfg)
+			require.NoError(t, err)
+
+			record := pdata.NewLogRecord()
+			record.SetTimestamp(timestamp)
+			record.Body().SetStringVal("hello world")
+			resource := pdata.NewAttributeMap()
+			tc.modify(record, resource)
+
+			assert.Equal(t, tc.expected, f.format(record, resource))
+		})
+	}
+}
+
+func TestSeverityFromNumber(t *testing.T) {
+	testcases := []struct {
+		number   pdata.SeverityNumber
+		expected int
+		ok       bool
+	}{
+		{number: pdata.SeverityNumberUNDEFINED},
+		{number: pdata.SeverityNumberTRACE, expected: severityDebug, ok: true},
+		{number: pdata.SeverityNumberDEBUG4, expected: severityDebug, ok: true},
+		{number: pdata.SeverityNumberINFO, expected: severityInformational, ok: true},
+		{number: pdata.SeverityNumberWARN3, expected: severityWarning, ok: true},
+		{number: pdata.SeverityNumberERROR, expected: severityError, ok: true},
+		{number: pdata.SeverityNumberFATAL4, expected: severityCritical, ok: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.number.String(), func(t *testing.T) {
+			severity, ok := severityFromNumber(tc.number)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, severity)
+		})
+	}
+}
+
+func TestHeaderFieldTruncation(t *testing.T) {
+	assert.Equal(t, strings.Repeat("a", maxAppNameLength), headerField(strings.Repeat("a", 100), maxAppNameLength))
+}
@@ -15,6 +15,9 @@
 package sumologicextension
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"time"
 
 	"go.opentelemetry.io/collector/config"
@@ -86,6 +89,10 @@ type Config struct {
 	// Exponential algorithm is being used.
 	// Please see following link for details: https://github.com/cenkalti/backoff
 	BackOff backOffConfig `mapstructure:"backoff"`
+
+	// ProxyAuth configures NTLM or Negotiate authentication with the HTTP
+	// proxy used for registration and heartbeat requests.
+	ProxyAuth proxyAuthConfig `mapstructure:"proxy_auth"`
 }
 
 type accessCredentials struct {
@@ -100,3 +107,85 @@ type backOffConfig struct {
 	MaxInterval     time.Duration `mapstructure:"max_interval"`
 	MaxElapsedTime  time.Duration `mapstructure:"max_elapsed_time"`
 }
+
+const (
+	proxyAuthTypeNTLM      = "ntlm"
+	proxyAuthTypeNegotiate = "negotiate"
+)
+
+// proxyAuthConfig defines the authentication with the HTTP proxy, which is
+// disabled when Type is empty.
+type proxyAuthConfig struct {
+	// Type is the authentication scheme, either ntlm or negotiate.
+	Type string `mapstructure:"type"`
+	// ProxyURL is the URL of the HTTP proxy, e.g. http://proxy.corp.local:8080.
+	ProxyURL string `mapstructure:"proxy_url"`
+	// Domain, Username and Password are the domain credentials
+	// used to authenticate with the proxy.
+	Domain   string `mapstructure:"domain"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// UseSSPI makes the extension authenticate as the user the collector
+	// runs as, using Windows SSPI, instead of the configured credentials.
+	UseSSPI bool `mapstructure:"use_sspi"`
+}
+
+// Validate checks if the extension configuration is valid
+func (cfg *Config) Validate() error {
+	if FIPSMode {
+		if err := ValidateFIPSTLSSettings(cfg.TLSSetting); err != nil {
+			return fmt.Errorf("invalid tls settings: %w", err)
+		}
+	}
+
+	if cfg.ProxyAuth.Type != "" {
+		if FIPSMode {
+			return errors.New("proxy_auth is not allowed in FIPS mode")
+		}
+		// The authenticated proxy connection is set up on the HTTP transport,
+		// which is not accessible when the client settings wrap it.
+		if len(cfg.Headers) > 0 || cfg.Compression != "" || cfg.Auth != nil {
+			return errors.New("proxy_auth can't be used together with headers, compression or auth")
+		}
+		if err := cfg.ProxyAuth.Validate(); err != nil {
+			return fmt.Errorf("proxy_auth has invalid configuration: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks if the proxy authentication configuration is valid
+func (cfg *proxyAuthConfig) Validate() error {
+	switch cfg.Type {
+	case proxyAuthTypeNTLM, proxyAuthTypeNegotiate:
+	default:
+		return fmt.Errorf("unexpected type: %q", cfg.Type)
+	}
+
+	if cfg.ProxyURL == "" {
+		return errors.New("proxy_url has to be specified")
+	}
+	u, err := url.Parse(cfg.ProxyURL)
+	if err != nil {
+		return fmt.Errorf("invalid proxy_url: %w", err)
+	}
+	if u.Scheme != "http" || u.Host == "" {
+		return fmt.Errorf("proxy_url %q has to be an http:// URL", cfg.ProxyURL)
+	}
+
+	if cfg.UseSSPI {
+		if !sspiSupported {
+			return errors.New("use_sspi is only supported on Windows")
+		}
+		if cfg.Username != "" || cfg.Password != "" {
+			return errors.New("username and password can't be used together with use_sspi")
+		}
+		return nil
+	}
+
+	if cfg.Username == "" || cfg.Password == "" {
+		return errors.New("username and password have to be specified")
+	}
+	return nil
+}
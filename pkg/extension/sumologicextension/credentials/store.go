@@ -15,6 +15,8 @@
 package credentials
 
 import (
+	"time"
+
 	"github.com/SumoLogic/sumologic-otel-collector/pkg/extension/sumologicextension/api"
 )
 
@@ -32,6 +34,9 @@ type CollectorCredentials struct {
 	// API base URL so that when the collector starts up again it can use this
 	// API base URL for communication with the backend.
 	ApiBaseUrl string `json:"apiBaseUrl"`
+	// RegisteredAt is the time the collector was registered at. It's zero for
+	// the credentials stored by the versions which didn't record it.
+	RegisteredAt time.Time `json:"registeredAt"`
 }
 
 // Store is an interface to get collector authentication data
@@ -49,6 +49,9 @@ type SumologicExtension struct {
 	// it as base URL for API requests and this access has to be coordinated.
 	baseUrlLock sync.RWMutex
 	baseUrl     string
+	// baseUrlChangeCallbacks are called whenever the base URL changes, e.g. when
+	// re-registration redirects the collector to a different deployment.
+	baseUrlChangeCallbacks []func(baseUrl string)
 
 	host             component.Host
 	conf             *Config
@@ -59,6 +62,17 @@ type SumologicExtension struct {
 	httpClient       *http.Client
 	registrationInfo api.OpenRegisterResponsePayload
 
+	// identityLock guards identity, which can change when the collector re-registers.
+	identityLock sync.RWMutex
+	identity     collectorIdentity
+
+	// proxyAuthDialer connects through the proxy with authentication,
+	// it's nil when proxy authentication is not configured.
+	proxyAuthDialer *proxyAuthDialer
+	// registrationTransport is the transport used for registration requests,
+	// nil means http.DefaultTransport.
+	registrationTransport http.RoundTripper
+
 	closeChan chan struct{}
 	closeOnce sync.Once
 	backOff   *backoff.ExponentialBackOff
@@ -84,11 +98,35 @@ const (
 	DefaultHeartbeatInterval = 15 * time.Second
 )
 
+// CollectorIdentity is implemented by the extension, so that other components
+// (e.g. processors setting the `_collector` field) can include the identity
+// of the registered collector in their data without duplicating its configuration.
+// Components which don't depend on this module can declare an interface with the same methods
+// and type assert the extensions returned by component.Host.GetExtensions to it.
+type CollectorIdentity interface {
+	// CollectorID returns the ID of the registered collector.
+	CollectorID() string
+	// CollectorName returns the name the collector is registered with.
+	CollectorName() string
+	// RegisteredAt returns the time the collector was registered at,
+	// it's zero when it's not known for the stored credentials.
+	RegisteredAt() time.Time
+}
+
+type collectorIdentity struct {
+	id           string
+	name         string
+	registeredAt time.Time
+}
+
 var errGRPCNotSupported = fmt.Errorf("gRPC is not supported by sumologicextension")
 
 // SumologicExtension implements ClientAuthenticator
 var _ configauth.ClientAuthenticator = (*SumologicExtension)(nil)
 
+// SumologicExtension implements CollectorIdentity
+var _ CollectorIdentity = (*SumologicExtension)(nil)
+
 func newSumologicExtension(conf *Config, logger *zap.Logger) (*SumologicExtension, error) {
 	if conf.Credentials.AccessID == "" || conf.Credentials.AccessKey == "" {
 		return nil, errors.New("access_key and/or access_id not provided")
@@ -133,6 +171,20 @@ func newSumologicExtension(conf *Config, logger *zap.Logger) (*SumologicExtensio
 	backOff.MaxElapsedTime = conf.BackOff.MaxElapsedTime
 	backOff.MaxInterval = conf.BackOff.MaxInterval
 
+	var (
+		proxyAuthDialer       *proxyAuthDialer
+		registrationTransport http.RoundTripper
+	)
+	if conf.ProxyAuth.Type != "" {
+		proxyAuthDialer, err = newProxyAuthDialer(conf.ProxyAuth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize proxy authentication: %w", err)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		proxyAuthDialer.configureTransport(transport)
+		registrationTransport = transport
+	}
+
 	return &SumologicExtension{
 		collectorName:    collectorName,
 		baseUrl:          strings.TrimSuffix(conf.ApiBaseUrl, "/"),
@@ -143,6 +195,9 @@ func newSumologicExtension(conf *Config, logger *zap.Logger) (*SumologicExtensio
 		credentialsStore: credentialsStore,
 		closeChan:        make(chan struct{}),
 		backOff:          backOff,
+
+		proxyAuthDialer:       proxyAuthDialer,
+		registrationTransport: registrationTransport,
 	}, nil
 }
 
@@ -214,6 +269,18 @@ func (se *SumologicExtension) injectCredentials(colCreds credentials.CollectorCr
 	// Set the registration info so that it can be used in RoundTripper.
 	se.registrationInfo = colCreds.Credentials
 
+	name := colCreds.Credentials.CollectorName
+	if name == "" {
+		name = colCreds.CollectorName
+	}
+	se.identityLock.Lock()
+	se.identity = collectorIdentity{
+		id:           colCreds.Credentials.CollectorId,
+		name:         name,
+		registeredAt: colCreds.RegisteredAt,
+	}
+	se.identityLock.Unlock()
+
 	httpClient, err := se.getHTTPClient(se.conf.HTTPClientSettings, colCreds.Credentials)
 	if err != nil {
 		return err
@@ -237,6 +304,14 @@ func (se *SumologicExtension) getHTTPClient(
 		return nil, fmt.Errorf("couldn't create HTTP client: %w", err)
 	}
 
+	if se.proxyAuthDialer != nil {
+		transport, ok := httpClient.Transport.(*http.Transport)
+		if !ok {
+			return nil, errors.New("proxy authentication requires an unwrapped HTTP transport")
+		}
+		se.proxyAuthDialer.configureTransport(transport)
+	}
+
 	// Set the transport so that all requests from httpClient will contain
 	// the collector credentials.
 	httpClient.Transport, err = se.RoundTripper(httpClient.Transport)
@@ -380,6 +455,7 @@ func (se *SumologicExtension) registerCollector(ctx context.Context, collectorNa
 	se.logger.Info("Calling register API", zap.String("URL", u.String()))
 
 	client := *http.DefaultClient
+	client.Transport = se.registrationTransport
 	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		return http.ErrUseLastResponse
 	}
@@ -412,6 +488,7 @@ func (se *SumologicExtension) registerCollector(ctx context.Context, collectorNa
 		CollectorName: collectorName,
 		Credentials:   resp,
 		ApiBaseUrl:    se.BaseUrl(),
+		RegisteredAt:  time.Now(),
 	}, nil
 }
 
@@ -609,7 +686,21 @@ func (se *SumologicExtension) ComponentID() config.ComponentID {
 }
 
 func (se *SumologicExtension) CollectorID() string {
-	return se.registrationInfo.CollectorId
+	se.identityLock.RLock()
+	defer se.identityLock.RUnlock()
+	return se.identity.id
+}
+
+func (se *SumologicExtension) CollectorName() string {
+	se.identityLock.RLock()
+	defer se.identityLock.RUnlock()
+	return se.identity.name
+}
+
+func (se *SumologicExtension) RegisteredAt() time.Time {
+	se.identityLock.RLock()
+	defer se.identityLock.RUnlock()
+	return se.identity.registeredAt
 }
 
 func (se *SumologicExtension) BaseUrl() string {
@@ -620,7 +711,28 @@ func (se *SumologicExtension) BaseUrl() string {
 
 func (se *SumologicExtension) SetBaseUrl(baseUrl string) {
 	se.baseUrlLock.Lock()
+	changed := se.baseUrl != baseUrl
 	se.baseUrl = baseUrl
+	callbacks := make([]func(string), len(se.baseUrlChangeCallbacks))
+	copy(callbacks, se.baseUrlChangeCallbacks)
+	se.baseUrlLock.Unlock()
+
+	if !changed {
+		return
+	}
+
+	// Call the callbacks outside of the lock so that they can safely call BaseUrl().
+	for _, cb := range callbacks {
+		cb(baseUrl)
+	}
+}
+
+// OnBaseUrlChange registers a callback which will be called with the new
+// base URL whenever it changes. This allows components using the extension
+// (e.g. sumologicexporter) to pick up new data URLs without a restart.
+func (se *SumologicExtension) OnBaseUrlChange(cb func(baseUrl string)) {
+	se.baseUrlLock.Lock()
+	se.baseUrlChangeCallbacks = append(se.baseUrlChangeCallbacks, cb)
 	se.baseUrlLock.Unlock()
 }
 
@@ -142,10 +142,17 @@ func TestBasicStart(t *testing.T) {
 
 	se, err := newSumologicExtension(cfg, zap.NewNop())
 	require.NoError(t, err)
+	startTime := time.Now()
 	require.NoError(t, se.Start(context.Background(), componenttest.NewNopHost()))
 	assert.NotEmpty(t, se.registrationInfo.CollectorCredentialId)
 	assert.NotEmpty(t, se.registrationInfo.CollectorCredentialKey)
 	assert.NotEmpty(t, se.registrationInfo.CollectorId)
+
+	var identity CollectorIdentity = se
+	assert.Equal(t, "id", identity.CollectorID())
+	assert.Equal(t, "collector_name", identity.CollectorName())
+	assert.False(t, identity.RegisteredAt().Before(startTime))
+	assert.False(t, identity.RegisteredAt().After(time.Now()))
 	require.NoError(t, se.Shutdown(context.Background()))
 }
 
@@ -367,6 +374,7 @@ func TestStoreCredentials_PreexistingCredentialsAreUsed(t *testing.T) {
 
 	hashKey := createHashKey(cfg)
 
+	registeredAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
 	require.NoError(t,
 		store.Store(hashKey, credentials.CollectorCredentials{
 			CollectorName: "collector_name",
@@ -374,7 +382,9 @@ func TestStoreCredentials_PreexistingCredentialsAreUsed(t *testing.T) {
 				CollectorCredentialId:  "collectorId",
 				CollectorCredentialKey: "collectorKey",
 				CollectorId:            "id",
+				CollectorName:          "registered_collector_name",
 			},
+			RegisteredAt: registeredAt,
 		}),
 	)
 
@@ -389,6 +399,9 @@ func TestStoreCredentials_PreexistingCredentialsAreUsed(t *testing.T) {
 	require.FileExists(t, credsPath)
 
 	require.NoError(t, se.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, "id", se.CollectorID())
+	assert.Equal(t, "registered_collector_name", se.CollectorName())
+	assert.True(t, registeredAt.Equal(se.RegisteredAt()))
 	require.NoError(t, se.Shutdown(context.Background()))
 	require.FileExists(t, credsPath)
 
@@ -1148,6 +1161,35 @@ func TestRegistrationRedirect(t *testing.T) {
 	})
 }
 
+func TestBaseUrlChangeCallbacks(t *testing.T) {
+	t.Parallel()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.CollectorName = "collector_name"
+	cfg.ExtensionSettings = config.ExtensionSettings{}
+	cfg.ApiBaseUrl = "https://example.com/"
+	cfg.Credentials.AccessID = "dummy_access_id"
+	cfg.Credentials.AccessKey = "dummy_access_key"
+	cfg.CollectorCredentialsDirectory = t.TempDir()
+
+	se, err := newSumologicExtension(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	var urls []string
+	se.OnBaseUrlChange(func(baseUrl string) {
+		// Callbacks are called outside of the lock so BaseUrl() can be used.
+		assert.Equal(t, baseUrl, se.BaseUrl())
+		urls = append(urls, baseUrl)
+	})
+
+	se.SetBaseUrl("https://example.com")
+	assert.Empty(t, urls, "callback shouldn't be called when the base URL doesn't change")
+
+	se.SetBaseUrl("https://redirected.example.com")
+	se.SetBaseUrl("https://redirected.example.com")
+	assert.Equal(t, []string{"https://redirected.example.com"}, urls)
+}
+
 func TestCollectorReregistersAfterHTTPUnathorizedFromHeartbeat(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,47 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicextension
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// ValidateFIPSTLSSettings checks that the TLS client settings can be used
+// in a FIPS 140-2 compliant binary, which only allows TLS 1.2 and above
+// and requires verifying the server's certificate.
+func ValidateFIPSTLSSettings(cfg configtls.TLSClientSetting) error {
+	if cfg.Insecure || cfg.InsecureSkipVerify {
+		return errors.New("insecure TLS settings are not allowed in FIPS mode")
+	}
+
+	for _, v := range []struct {
+		name    string
+		version string
+	}{
+		{name: "min_version", version: cfg.MinVersion},
+		{name: "max_version", version: cfg.MaxVersion},
+	} {
+		switch v.version {
+		case "", "1.2", "1.3":
+		default:
+			return fmt.Errorf("TLS %s %q is not allowed in FIPS mode, use 1.2 or 1.3", v.name, v.version)
+		}
+	}
+
+	return nil
+}
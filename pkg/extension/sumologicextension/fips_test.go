@@ -0,0 +1,88 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicextension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+func TestValidateFIPSTLSSettings(t *testing.T) {
+	testcases := []struct {
+		name        string
+		cfg         configtls.TLSClientSetting
+		expectedErr string
+	}{
+		{
+			name: "default",
+			cfg:  configtls.TLSClientSetting{},
+		},
+		{
+			name: "tls 1.2 and 1.3",
+			cfg: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{
+					MinVersion: "1.2",
+					MaxVersion: "1.3",
+				},
+			},
+		},
+		{
+			name: "insecure skip verify",
+			cfg: configtls.TLSClientSetting{
+				InsecureSkipVerify: true,
+			},
+			expectedErr: "insecure TLS settings are not allowed in FIPS mode",
+		},
+		{
+			name: "insecure",
+			cfg: configtls.TLSClientSetting{
+				Insecure: true,
+			},
+			expectedErr: "insecure TLS settings are not allowed in FIPS mode",
+		},
+		{
+			name: "tls 1.1",
+			cfg: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{
+					MinVersion: "1.1",
+				},
+			},
+			expectedErr: `TLS min_version "1.1" is not allowed in FIPS mode, use 1.2 or 1.3`,
+		},
+		{
+			name: "tls 1.0 max version",
+			cfg: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{
+					MaxVersion: "1.0",
+				},
+			},
+			expectedErr: `TLS max_version "1.0" is not allowed in FIPS mode, use 1.2 or 1.3`,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateFIPSTLSSettings(tc.cfg)
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
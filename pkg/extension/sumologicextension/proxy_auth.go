@@ -0,0 +1,220 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicextension
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-ntlmssp"
+)
+
+// maxProxyAuthRounds limits the number of CONNECT requests sent to the proxy
+// while authenticating a single connection.
+const maxProxyAuthRounds = 3
+
+// proxyAuthHandshake produces the authentication tokens exchanged with
+// the proxy while establishing a single connection.
+type proxyAuthHandshake interface {
+	// next returns the token answering the proxy's challenge,
+	// which is nil for the initial request.
+	next(challenge []byte) ([]byte, error)
+	release()
+}
+
+// proxyAuthDialer establishes connections tunneled through an HTTP proxy
+// with CONNECT requests, authenticating each of them with NTLM or Negotiate.
+//
+// Both schemes authenticate the connection rather than the request, so the
+// whole handshake has to happen on the same connection, which isn't possible
+// with the proxy support of http.Transport.
+type proxyAuthDialer struct {
+	proxyAddr    string
+	scheme       string
+	newHandshake func() (proxyAuthHandshake, error)
+	dialer       net.Dialer
+}
+
+func newProxyAuthDialer(cfg proxyAuthConfig) (*proxyAuthDialer, error) {
+	u, err := url.Parse(cfg.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy_url: %w", err)
+	}
+
+	d := &proxyAuthDialer{
+		proxyAddr: u.Host,
+		scheme:    "NTLM",
+	}
+	if u.Port() == "" {
+		d.proxyAddr = net.JoinHostPort(u.Hostname(), "80")
+	}
+	if cfg.Type == proxyAuthTypeNegotiate {
+		d.scheme = "Negotiate"
+	}
+
+	if cfg.UseSSPI {
+		// Kerberos requires the service principal name of the proxy.
+		spn := "HTTP/" + u.Hostname()
+		d.newHandshake = func() (proxyAuthHandshake, error) {
+			return newSSPIHandshake(cfg.Type, spn)
+		}
+	} else {
+		d.newHandshake = func() (proxyAuthHandshake, error) {
+			return &ntlmHandshake{
+				domain:   cfg.Domain,
+				username: cfg.Username,
+				password: cfg.Password,
+			}, nil
+		}
+	}
+
+	return d, nil
+}
+
+// configureTransport makes the transport connect through the proxy.
+func (d *proxyAuthDialer) configureTransport(transport *http.Transport) {
+	transport.Proxy = nil
+	transport.DialContext = d.DialContext
+}
+
+// DialContext connects to the address through the proxy.
+func (d *proxyAuthDialer) DialContext(ctx context.Context, network string, addr string) (net.Conn, error) {
+	conn, err := d.dialer.DialContext(ctx, network, d.proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy %s: %w", d.proxyAddr, err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if err := d.connect(conn, addr); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to %s through proxy %s: %w", addr, d.proxyAddr, err)
+	}
+
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// connect sets up the tunnel to the address, authenticating with the proxy.
+func (d *proxyAuthDialer) connect(conn net.Conn, addr string) error {
+	hs, err := d.newHandshake()
+	if err != nil {
+		return err
+	}
+	defer hs.release()
+
+	// The proxy doesn't send anything after accepting the tunnel, so the reader
+	// never buffers any of the data the tunneled connection should return.
+	br := bufio.NewReader(conn)
+
+	var challenge []byte
+	for i := 0; i < maxProxyAuthRounds; i++ {
+		token, err := hs.next(challenge)
+		if err != nil {
+			return fmt.Errorf("failed to create %s token: %w", d.scheme, err)
+		}
+
+		req := &http.Request{
+			Method: http.MethodConnect,
+			URL:    &url.URL{Opaque: addr},
+			Host:   addr,
+			Header: http.Header{},
+		}
+		req.Header.Set("Proxy-Authorization", d.scheme+" "+base64.StdEncoding.EncodeToString(token))
+		if err := req.Write(conn); err != nil {
+			return err
+		}
+
+		res, err := http.ReadResponse(br, req)
+		if err != nil {
+			return err
+		}
+		if res.StatusCode == http.StatusOK {
+			// The body of a successful CONNECT response is the tunneled connection.
+			return nil
+		}
+
+		// Drain the body so the next request can be sent on the same connection.
+		_, err = io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case res.StatusCode != http.StatusProxyAuthRequired:
+			return fmt.Errorf("unexpected proxy response status: %s", res.Status)
+		case res.Close:
+			return errors.New("proxy closed the connection during authentication")
+		}
+
+		challenge, err = d.challenge(res)
+		if err != nil {
+			return err
+		}
+	}
+
+	return errors.New("proxy authentication failed")
+}
+
+// challenge returns the decoded challenge of the proxy's 407 response.
+func (d *proxyAuthDialer) challenge(res *http.Response) ([]byte, error) {
+	for _, h := range res.Header.Values("Proxy-Authenticate") {
+		scheme, value, _ := strings.Cut(h, " ")
+		if !strings.EqualFold(scheme, d.scheme) {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			// The proxy restarts the handshake, which means the credentials were rejected.
+			return nil, errors.New("proxy rejected the credentials")
+		}
+		return base64.StdEncoding.DecodeString(value)
+	}
+	return nil, fmt.Errorf("proxy doesn't support %s authentication", d.scheme)
+}
+
+// ntlmHandshake authenticates with the configured domain credentials.
+type ntlmHandshake struct {
+	domain   string
+	username string
+	password string
+}
+
+func (h *ntlmHandshake) next(challenge []byte) ([]byte, error) {
+	if challenge == nil {
+		return ntlmssp.NewNegotiateMessage(h.domain, "")
+	}
+	return ntlmssp.ProcessChallenge(challenge, h.username, h.password)
+}
+
+func (h *ntlmHandshake) release() {}
@@ -0,0 +1,98 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+// +build windows
+
+package sumologicextension
+
+import (
+	"errors"
+
+	"github.com/alexbrainman/sspi"
+	"github.com/alexbrainman/sspi/negotiate"
+	"github.com/alexbrainman/sspi/ntlm"
+)
+
+// sspiSupported is true when the proxy authentication can use the
+// credentials of the current user via Windows SSPI.
+const sspiSupported = true
+
+func newSSPIHandshake(authType string, spn string) (proxyAuthHandshake, error) {
+	if authType == proxyAuthTypeNegotiate {
+		cred, err := negotiate.AcquireCurrentUserCredentials()
+		if err != nil {
+			return nil, err
+		}
+		return &negotiateSSPIHandshake{cred: cred, spn: spn}, nil
+	}
+
+	cred, err := ntlm.AcquireCurrentUserCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return &ntlmSSPIHandshake{cred: cred}, nil
+}
+
+type ntlmSSPIHandshake struct {
+	cred *sspi.Credentials
+	ctx  *ntlm.ClientContext
+}
+
+func (h *ntlmSSPIHandshake) next(challenge []byte) ([]byte, error) {
+	if challenge == nil {
+		ctx, token, err := ntlm.NewClientContext(h.cred)
+		if err != nil {
+			return nil, err
+		}
+		h.ctx = ctx
+		return token, nil
+	}
+	if h.ctx == nil {
+		return nil, errors.New("NTLM handshake was not started")
+	}
+	return h.ctx.Update(challenge)
+}
+
+func (h *ntlmSSPIHandshake) release() {
+	h.ctx.Release()
+	h.cred.Release()
+}
+
+type negotiateSSPIHandshake struct {
+	cred *sspi.Credentials
+	spn  string
+	ctx  *negotiate.ClientContext
+}
+
+func (h *negotiateSSPIHandshake) next(challenge []byte) ([]byte, error) {
+	if challenge == nil {
+		ctx, token, err := negotiate.NewClientContext(h.cred, h.spn)
+		if err != nil {
+			return nil, err
+		}
+		h.ctx = ctx
+		return token, nil
+	}
+	if h.ctx == nil {
+		return nil, errors.New("Negotiate handshake was not started")
+	}
+	_, token, err := h.ctx.Update(challenge)
+	return token, err
+}
+
+func (h *negotiateSSPIHandshake) release() {
+	h.ctx.Release()
+	h.cred.Release()
+}
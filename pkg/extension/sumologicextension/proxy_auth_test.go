@@ -0,0 +1,281 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicextension
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.uber.org/zap"
+)
+
+// ntlmChallenge returns a minimal NTLM CHALLENGE message.
+func ntlmChallenge() []byte {
+	msg := make([]byte, 48)
+	copy(msg, "NTLMSSP\x00")
+	binary.LittleEndian.PutUint32(msg[8:], 2)
+	// NTLMSSP_NEGOTIATE_UNICODE | NTLMSSP_NEGOTIATE_NTLM
+	binary.LittleEndian.PutUint32(msg[20:], 0x00000201)
+	copy(msg[24:32], "12345678")
+	return msg
+}
+
+// ntlmMessageType returns the type of the NTLM message sent by the client.
+func ntlmMessageType(t *testing.T, req *http.Request) uint32 {
+	auth := req.Header.Get("Proxy-Authorization")
+	require.True(t, strings.HasPrefix(auth, "NTLM "), "unexpected Proxy-Authorization: %q", auth)
+	msg, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(auth, "NTLM "))
+	require.NoError(t, err)
+	require.True(t, len(msg) >= 12)
+	require.Equal(t, "NTLMSSP\x00", string(msg[:8]))
+	return binary.LittleEndian.Uint32(msg[8:])
+}
+
+// startNTLMProxy starts a proxy which requires NTLM authentication of CONNECT
+// requests and returns its URL and the number of successfully authenticated tunnels.
+func startNTLMProxy(t *testing.T) (string, *int32) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	var tunnels int32
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				br := bufio.NewReader(conn)
+
+				req, err := http.ReadRequest(br)
+				if !assert.NoError(t, err) || !assert.Equal(t, http.MethodConnect, req.Method) {
+					return
+				}
+				if !assert.EqualValues(t, 1, ntlmMessageType(t, req)) {
+					return
+				}
+				fmt.Fprintf(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n"+
+					"Proxy-Authenticate: NTLM %s\r\nContent-Length: 0\r\n\r\n",
+					base64.StdEncoding.EncodeToString(ntlmChallenge()),
+				)
+
+				req, err = http.ReadRequest(br)
+				if !assert.NoError(t, err) || !assert.EqualValues(t, 3, ntlmMessageType(t, req)) {
+					return
+				}
+
+				target, err := net.Dial("tcp", req.Host)
+				if !assert.NoError(t, err) {
+					return
+				}
+				defer target.Close()
+
+				atomic.AddInt32(&tunnels, 1)
+				fmt.Fprint(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+				go io.Copy(target, br)
+				io.Copy(conn, target)
+			}()
+		}
+	}()
+
+	return "http://" + l.Addr().String(), &tunnels
+}
+
+func TestRegistrationAndHeartbeatThroughNTLMProxy(t *testing.T) {
+	t.Parallel()
+
+	var reqCount int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch atomic.AddInt32(&reqCount, 1) {
+		// register
+		case 1:
+			require.Equal(t, registerUrl, req.URL.Path)
+			_, err := w.Write([]byte(`{
+				"collectorCredentialId": "collectorId",
+				"collectorCredentialKey": "collectorKey",
+				"collectorId": "id"
+			}`))
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+
+		// heartbeat
+		case 2:
+			assert.Equal(t, heartbeatUrl, req.URL.Path)
+			w.WriteHeader(204)
+
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(func() { srv.Close() })
+
+	proxyURL, tunnels := startNTLMProxy(t)
+
+	dir, err := os.MkdirTemp("", "otelcol-sumo-proxy-auth-test-*")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.CollectorName = "collector_name"
+	cfg.ExtensionSettings = config.ExtensionSettings{}
+	cfg.ApiBaseUrl = srv.URL
+	cfg.Credentials.AccessID = "dummy_access_id"
+	cfg.Credentials.AccessKey = "dummy_access_key"
+	cfg.CollectorCredentialsDirectory = dir
+	cfg.ProxyAuth = proxyAuthConfig{
+		Type:     proxyAuthTypeNTLM,
+		ProxyURL: proxyURL,
+		Domain:   "CORP",
+		Username: "user",
+		Password: "password",
+	}
+	require.NoError(t, cfg.Validate())
+
+	se, err := newSumologicExtension(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, se.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, "collectorId", se.registrationInfo.CollectorCredentialId)
+	// The heartbeat is sent by the heartbeat loop right after the start.
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&reqCount) == 2 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, se.Shutdown(context.Background()))
+
+	// Registration and heartbeat use separate clients, each with its own tunnel.
+	assert.EqualValues(t, 2, atomic.LoadInt32(tunnels))
+}
+
+func TestProxyAuthDialerRejectedCredentials(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		br := bufio.NewReader(conn)
+		for {
+			if _, err := http.ReadRequest(br); err != nil {
+				return
+			}
+			fmt.Fprintf(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n"+
+				"Proxy-Authenticate: NTLM\r\nContent-Length: 0\r\n\r\n",
+			)
+		}
+	}()
+
+	d, err := newProxyAuthDialer(proxyAuthConfig{
+		Type:     proxyAuthTypeNTLM,
+		ProxyURL: "http://" + l.Addr().String(),
+		Username: "user",
+		Password: "password",
+	})
+	require.NoError(t, err)
+
+	_, err = d.DialContext(context.Background(), "tcp", "example.com:443")
+	assert.EqualError(t, err, fmt.Sprintf(
+		"failed to connect to example.com:443 through proxy %s: proxy rejected the credentials", l.Addr().String(),
+	))
+}
+
+func TestProxyAuthConfigValidate(t *testing.T) {
+	testcases := []struct {
+		name        string
+		cfg         proxyAuthConfig
+		expectedErr string
+	}{
+		{
+			name: "ntlm",
+			cfg: proxyAuthConfig{
+				Type:     proxyAuthTypeNTLM,
+				ProxyURL: "http://proxy.corp.local:8080",
+				Domain:   "CORP",
+				Username: "user",
+				Password: "password",
+			},
+		},
+		{
+			name: "unexpected type",
+			cfg: proxyAuthConfig{
+				Type:     "basic",
+				ProxyURL: "http://proxy.corp.local:8080",
+				Username: "user",
+				Password: "password",
+			},
+			expectedErr: `unexpected type: "basic"`,
+		},
+		{
+			name: "no proxy url",
+			cfg: proxyAuthConfig{
+				Type:     proxyAuthTypeNegotiate,
+				Username: "user",
+				Password: "password",
+			},
+			expectedErr: "proxy_url has to be specified",
+		},
+		{
+			name: "https proxy url",
+			cfg: proxyAuthConfig{
+				Type:     proxyAuthTypeNTLM,
+				ProxyURL: "https://proxy.corp.local:8080",
+				Username: "user",
+				Password: "password",
+			},
+			expectedErr: `proxy_url "https://proxy.corp.local:8080" has to be an http:// URL`,
+		},
+		{
+			name: "no password",
+			cfg: proxyAuthConfig{
+				Type:     proxyAuthTypeNTLM,
+				ProxyURL: "http://proxy.corp.local:8080",
+				Username: "user",
+			},
+			expectedErr: "username and password have to be specified",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,540 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicfilestorageextension
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	// fileHeader is written at the beginning of every file, it holds the version of the format
+	fileHeader = "SUMOFS01"
+	// recordHeaderSize is the size of the payload length and the payload checksum preceding every payload
+	recordHeaderSize = 8
+
+	opSet    byte = 1
+	opDelete byte = 2
+
+	corruptedFileSuffix  = ".corrupted"
+	compactingFileSuffix = ".compacting"
+)
+
+var (
+	errClientClosed  = errors.New("the client is closed")
+	errStorageFull   = errors.New("the storage is full")
+	errInvalidRecord = errors.New("invalid record")
+)
+
+type clientSettings struct {
+	maxSize                int64
+	fsync                  bool
+	compactOnStart         bool
+	compactionGarbageRatio float64
+	compactionMinSize      int64
+}
+
+// fileStorageClient keeps the data in the append-only file, in which every change is a record:
+//
+//	| payload length (4 bytes) | payload CRC-32 (4 bytes) | operation (1 byte) | key length (uvarint) | key | value |
+//
+// Only the index of the records holding the current values is kept in memory,
+// the values are read from the file.
+type fileStorageClient struct {
+	path     string
+	settings clientSettings
+	logger   *zap.Logger
+	onClose  func()
+
+	// lock guards the fields below
+	lock   sync.Mutex
+	closed bool
+	// file is nil when the client is closed, or when the file couldn't be reopened after the compaction
+	file  *os.File
+	index map[string]valueLocation
+	// size is the size of the file
+	size int64
+	// liveSize is the size of the records holding the current values
+	liveSize int64
+}
+
+// valueLocation is the location of the record holding the current value of the key
+type valueLocation struct {
+	// offset is the offset of the value in the file
+	offset int64
+	// length is the length of the value
+	length int
+	// recordSize is the size of the whole record
+	recordSize int64
+}
+
+var _ storage.Client = (*fileStorageClient)(nil)
+
+func newFileStorageClient(path string, settings clientSettings, logger *zap.Logger, onClose func()) (*fileStorageClient, error) {
+	// remove the leftover of the compaction interrupted by the crash
+	if err := os.Remove(path + compactingFileSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	c := &fileStorageClient{
+		path:     path,
+		settings: settings,
+		logger:   logger,
+		onClose:  onClose,
+		index:    make(map[string]valueLocation),
+	}
+	if err := c.load(); err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+
+	if settings.compactOnStart && c.garbageSize() > 0 {
+		if err := c.compact(); err != nil {
+			if c.file != nil {
+				_ = c.file.Close()
+			}
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Get returns the value of the key, or nil if it doesn't exist
+func (c *fileStorageClient) Get(_ context.Context, key string) ([]byte, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.file == nil {
+		return nil, errClientClosed
+	}
+	return c.get(key)
+}
+
+// Set sets the value of the key
+func (c *fileStorageClient) Set(_ context.Context, key string, value []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.file == nil {
+		return errClientClosed
+	}
+	return c.set(key, value)
+}
+
+// Delete deletes the key
+func (c *fileStorageClient) Delete(_ context.Context, key string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.file == nil {
+		return errClientClosed
+	}
+	return c.delete(key)
+}
+
+// Batch executes the operations in order, the values of the get operations are set in place
+func (c *fileStorageClient) Batch(_ context.Context, ops ...storage.Operation) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.file == nil {
+		return errClientClosed
+	}
+
+	for _, op := range ops {
+		var err error
+		switch op.Type {
+		case storage.Get:
+			op.Value, err = c.get(op.Key)
+		case storage.Set:
+			err = c.set(op.Key, op.Value)
+		case storage.Delete:
+			err = c.delete(op.Key)
+		default:
+			err = fmt.Errorf("unexpected operation type: %v", op.Type)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the file
+func (c *fileStorageClient) Close(context.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	c.onClose()
+
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
+
+func (c *fileStorageClient) get(key string) ([]byte, error) {
+	location, ok := c.index[key]
+	if !ok {
+		return nil, nil
+	}
+
+	value := make([]byte, location.length)
+	if _, err := c.file.ReadAt(value, location.offset); err != nil {
+		return nil, fmt.Errorf("failed to read the value of %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func (c *fileStorageClient) set(key string, value []byte) error {
+	record, valueOffset := encodeRecord(opSet, key, value)
+
+	if c.settings.maxSize > 0 && c.size+int64(len(record)) > c.settings.maxSize {
+		if c.garbageSize() > 0 {
+			if err := c.compact(); err != nil {
+				return err
+			}
+		}
+		if c.size+int64(len(record)) > c.settings.maxSize {
+			return fmt.Errorf("cannot set %s: %w", key, errStorageFull)
+		}
+	}
+
+	offset, err := c.write(record)
+	if err != nil {
+		return err
+	}
+	c.setLocation(key, valueLocation{
+		offset:     offset + int64(valueOffset),
+		length:     len(value),
+		recordSize: int64(len(record)),
+	})
+	c.maybeCompact()
+	return nil
+}
+
+// delete deletes the key, it's allowed when the storage is full,
+// so the space can be reclaimed
+func (c *fileStorageClient) delete(key string) error {
+	if _, ok := c.index[key]; !ok {
+		return nil
+	}
+
+	record, _ := encodeRecord(opDelete, key, nil)
+	if _, err := c.write(record); err != nil {
+		return err
+	}
+	c.removeLocation(key)
+	c.maybeCompact()
+	return nil
+}
+
+// write appends the record to the file and returns its offset
+func (c *fileStorageClient) write(record []byte) (int64, error) {
+	offset := c.size
+	_, err := c.file.WriteAt(record, offset)
+	if err == nil && c.settings.fsync {
+		err = c.file.Sync()
+	}
+	if err != nil {
+		// don't leave the partially written record behind
+		_ = c.file.Truncate(offset)
+		return 0, fmt.Errorf("failed to write to %s: %w", c.path, err)
+	}
+
+	c.size += int64(len(record))
+	return offset, nil
+}
+
+func (c *fileStorageClient) setLocation(key string, location valueLocation) {
+	c.removeLocation(key)
+	c.index[key] = location
+	c.liveSize += location.recordSize
+}
+
+func (c *fileStorageClient) removeLocation(key string) {
+	if location, ok := c.index[key]; ok {
+		c.liveSize -= location.recordSize
+		delete(c.index, key)
+	}
+}
+
+// garbageSize returns the size of the records which don't hold the current values
+func (c *fileStorageClient) garbageSize() int64 {
+	return c.size - int64(len(fileHeader)) - c.liveSize
+}
+
+// load opens the file and builds the index from its records
+func (c *fileStorageClient) load() error {
+	file, err := os.OpenFile(c.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if info.Size() == 0 {
+		if _, err := file.WriteAt([]byte(fileHeader), 0); err != nil {
+			_ = file.Close()
+			return err
+		}
+		c.file = file
+		c.size = int64(len(fileHeader))
+		return nil
+	}
+
+	if !hasFileHeader(file) {
+		// the file isn't ours or it's damaged beyond recovery, keep it for investigation
+		_ = file.Close()
+		corruptedPath := c.path + corruptedFileSuffix
+		if err := os.Rename(c.path, corruptedPath); err != nil {
+			return err
+		}
+		c.logger.Warn("The file has invalid header, it was moved aside and the storage starts empty",
+			zap.String("path", corruptedPath),
+		)
+		return c.load()
+	}
+
+	c.file = file
+	if err := c.readRecords(info.Size()); err != nil {
+		_ = file.Close()
+		c.file = nil
+		return err
+	}
+	return nil
+}
+
+// readRecords builds the index from the records of the file, the records
+// following the first corrupted one (e.g. partially written on crash) are discarded
+func (c *fileStorageClient) readRecords(fileSize int64) error {
+	offset := int64(len(fileHeader))
+	reader := bufio.NewReader(io.NewSectionReader(c.file, offset, fileSize-offset))
+	header := make([]byte, recordHeaderSize)
+
+	for {
+		if _, err := io.ReadFull(reader, header); err == io.EOF {
+			break
+		} else if err == io.ErrUnexpectedEOF {
+			c.logger.Warn("Found partially written record header")
+			break
+		} else if err != nil {
+			return err
+		}
+
+		length := int64(binary.BigEndian.Uint32(header[:4]))
+		if length > fileSize-offset-recordHeaderSize {
+			c.logger.Warn("Found partially written record", zap.Int64("offset", offset))
+			break
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return err
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:]) {
+			c.logger.Warn("Found record with invalid checksum", zap.Int64("offset", offset))
+			break
+		}
+		op, key, valueOffset, err := decodePayload(payload)
+		if err != nil {
+			c.logger.Warn("Found invalid record", zap.Int64("offset", offset), zap.Error(err))
+			break
+		}
+
+		recordSize := recordHeaderSize + length
+		switch op {
+		case opSet:
+			c.setLocation(key, valueLocation{
+				offset:     offset + recordHeaderSize + int64(valueOffset),
+				length:     len(payload) - valueOffset,
+				recordSize: recordSize,
+			})
+		case opDelete:
+			c.removeLocation(key)
+		}
+		offset += recordSize
+	}
+
+	if offset < fileSize {
+		c.logger.Warn("Discarding the corrupted end of the file",
+			zap.Int64("offset", offset),
+			zap.Int64("discarded_bytes", fileSize-offset),
+		)
+		if err := c.file.Truncate(offset); err != nil {
+			return err
+		}
+	}
+	c.size = offset
+	return nil
+}
+
+// maybeCompact compacts the file when the garbage takes enough of it
+func (c *fileStorageClient) maybeCompact() {
+	if c.size < c.settings.compactionMinSize {
+		return
+	}
+	if float64(c.garbageSize()) < c.settings.compactionGarbageRatio*float64(c.size) {
+		return
+	}
+	if err := c.compact(); err != nil {
+		c.logger.Warn("Failed to compact the file", zap.Error(err))
+	}
+}
+
+// compact replaces the file with the one holding only the current values
+func (c *fileStorageClient) compact() error {
+	compactingPath := c.path + compactingFileSuffix
+	compacting, err := os.OpenFile(compactingPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to compact %s: %w", c.path, err)
+	}
+
+	index, size, err := c.copyValues(compacting)
+	if err == nil {
+		err = compacting.Sync()
+	}
+	if closeErr := compacting.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(compactingPath)
+		return fmt.Errorf("failed to compact %s: %w", c.path, err)
+	}
+
+	// the file is closed before it's replaced, as it's required on Windows
+	_ = c.file.Close()
+	renameErr := os.Rename(compactingPath, c.path)
+	file, err := os.OpenFile(c.path, os.O_RDWR, 0600)
+	if err != nil {
+		c.file = nil
+		return fmt.Errorf("failed to reopen %s after compaction: %w", c.path, err)
+	}
+	c.file = file
+	if renameErr != nil {
+		_ = os.Remove(compactingPath)
+		return fmt.Errorf("failed to replace %s with the compacted file: %w", c.path, renameErr)
+	}
+
+	c.logger.Debug("Compacted the file",
+		zap.Int64("size_before", c.size),
+		zap.Int64("size_after", size),
+	)
+	c.index = index
+	c.size = size
+	c.liveSize = size - int64(len(fileHeader))
+	return nil
+}
+
+// copyValues writes the current values to the file and returns their index and the size of the file
+func (c *fileStorageClient) copyValues(dst *os.File) (map[string]valueLocation, int64, error) {
+	writer := bufio.NewWriter(dst)
+	if _, err := writer.WriteString(fileHeader); err != nil {
+		return nil, 0, err
+	}
+	size := int64(len(fileHeader))
+
+	keys := make([]string, 0, len(c.index))
+	for key := range c.index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	index := make(map[string]valueLocation, len(keys))
+	for _, key := range keys {
+		value, err := c.get(key)
+		if err != nil {
+			return nil, 0, err
+		}
+		record, valueOffset := encodeRecord(opSet, key, value)
+		if _, err := writer.Write(record); err != nil {
+			return nil, 0, err
+		}
+		index[key] = valueLocation{
+			offset:     size + int64(valueOffset),
+			length:     len(value),
+			recordSize: int64(len(record)),
+		}
+		size += int64(len(record))
+	}
+	return index, size, writer.Flush()
+}
+
+func hasFileHeader(file *os.File) bool {
+	header := make([]byte, len(fileHeader))
+	if _, err := file.ReadAt(header, 0); err != nil {
+		return false
+	}
+	return string(header) == fileHeader
+}
+
+// encodeRecord returns the record and the offset of the value in it
+func encodeRecord(op byte, key string, value []byte) ([]byte, int) {
+	var keyLength [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(keyLength[:], uint64(len(key)))
+
+	payloadLength := 1 + n + len(key) + len(value)
+	record := make([]byte, recordHeaderSize, recordHeaderSize+payloadLength)
+	record = append(record, op)
+	record = append(record, keyLength[:n]...)
+	record = append(record, key...)
+	valueOffset := len(record)
+	record = append(record, value...)
+
+	payload := record[recordHeaderSize:]
+	binary.BigEndian.PutUint32(record[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:recordHeaderSize], crc32.ChecksumIEEE(payload))
+	return record, valueOffset
+}
+
+// decodePayload returns the operation, the key and the offset of the value in the payload
+func decodePayload(payload []byte) (byte, string, int, error) {
+	if len(payload) == 0 {
+		return 0, "", 0, errInvalidRecord
+	}
+	op := payload[0]
+	if op != opSet && op != opDelete {
+		return 0, "", 0, fmt.Errorf("%w: unexpected operation %d", errInvalidRecord, op)
+	}
+
+	keyLength, n := binary.Uvarint(payload[1:])
+	if n <= 0 || keyLength > uint64(len(payload)-1-n) {
+		return 0, "", 0, fmt.Errorf("%w: invalid key length", errInvalidRecord)
+	}
+	keyStart := 1 + n
+	keyEnd := keyStart + int(keyLength)
+	if op == opDelete && keyEnd != len(payload) {
+		return 0, "", 0, fmt.Errorf("%w: delete operation with value", errInvalidRecord)
+	}
+	return op, string(payload[keyStart:keyEnd]), keyEnd, nil
+}
@@ -0,0 +1,286 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicfilestorageextension
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+func defaultClientSettings() clientSettings {
+	return clientSettings{
+		maxSize:                defaultMaxSizeMiB * bytesInMiB,
+		compactOnStart:         true,
+		compactionGarbageRatio: defaultCompactionGarbageRatio,
+		compactionMinSize:      defaultCompactionMinSizeMiB * bytesInMiB,
+	}
+}
+
+func newTestClient(t *testing.T, path string, settings clientSettings) *fileStorageClient {
+	client, err := newFileStorageClient(path, settings, zap.NewNop(), func() {})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, client.Close(context.Background()))
+	})
+	return client
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	return info.Size()
+}
+
+func TestClientOperations(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, filepath.Join(t.TempDir(), "client"), defaultClientSettings())
+
+	value, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	require.NoError(t, client.Set(ctx, "key", []byte("value")))
+	value, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	require.NoError(t, client.Set(ctx, "key", []byte("new value")))
+	value, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new value"), value)
+
+	require.NoError(t, client.Delete(ctx, "key"))
+	value, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	// deleting the key which doesn't exist is no-op
+	require.NoError(t, client.Delete(ctx, "key"))
+}
+
+func TestClientBatch(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, filepath.Join(t.TempDir(), "client"), defaultClientSettings())
+
+	require.NoError(t, client.Set(ctx, "deleted", []byte("value")))
+
+	getOp := storage.GetOperation("key")
+	getDeletedOp := storage.GetOperation("deleted")
+	err := client.Batch(ctx,
+		storage.SetOperation("key", []byte("value")),
+		storage.DeleteOperation("deleted"),
+		getOp,
+		getDeletedOp,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), getOp.Value)
+	assert.Nil(t, getDeletedOp.Value)
+}
+
+func TestClientPersistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "client")
+
+	client, err := newFileStorageClient(path, defaultClientSettings(), zap.NewNop(), func() {})
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "first", []byte("1")))
+	require.NoError(t, client.Set(ctx, "second", []byte("2")))
+	require.NoError(t, client.Set(ctx, "first", []byte("3")))
+	require.NoError(t, client.Delete(ctx, "second"))
+	require.NoError(t, client.Set(ctx, "empty", []byte{}))
+	require.NoError(t, client.Close(ctx))
+
+	client = newTestClient(t, path, defaultClientSettings())
+	value, err := client.Get(ctx, "first")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("3"), value)
+
+	value, err = client.Get(ctx, "second")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	value, err = client.Get(ctx, "empty")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{}, value)
+}
+
+func TestClientClosed(t *testing.T) {
+	ctx := context.Background()
+	closed := false
+	client, err := newFileStorageClient(filepath.Join(t.TempDir(), "client"), defaultClientSettings(), zap.NewNop(), func() { closed = true })
+	require.NoError(t, err)
+
+	require.NoError(t, client.Close(ctx))
+	assert.True(t, closed)
+	require.NoError(t, client.Close(ctx))
+
+	_, err = client.Get(ctx, "key")
+	assert.ErrorIs(t, err, errClientClosed)
+	assert.ErrorIs(t, client.Set(ctx, "key", []byte("value")), errClientClosed)
+	assert.ErrorIs(t, client.Delete(ctx, "key"), errClientClosed)
+	assert.ErrorIs(t, client.Batch(ctx, storage.GetOperation("key")), errClientClosed)
+}
+
+func TestClientRecoversFromPartiallyWrittenRecord(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "client")
+
+	client, err := newFileStorageClient(path, defaultClientSettings(), zap.NewNop(), func() {})
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "first", []byte("1")))
+	require.NoError(t, client.Set(ctx, "second", []byte("2")))
+	require.NoError(t, client.Close(ctx))
+
+	// cut the last record in the middle, like on crash during the write
+	size := fileSize(t, path)
+	require.NoError(t, os.Truncate(path, size-2))
+
+	client = newTestClient(t, path, defaultClientSettings())
+	value, err := client.Get(ctx, "first")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+
+	value, err = client.Get(ctx, "second")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	// the new records are appended after the last valid one
+	require.NoError(t, client.Set(ctx, "second", []byte("4")))
+	value, err = client.Get(ctx, "second")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("4"), value)
+}
+
+func TestClientRecoversFromInvalidChecksum(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "client")
+
+	client, err := newFileStorageClient(path, defaultClientSettings(), zap.NewNop(), func() {})
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "first", []byte("1")))
+	require.NoError(t, client.Set(ctx, "second", []byte("2")))
+	require.NoError(t, client.Close(ctx))
+
+	// damage the value of the last record
+	file, err := os.OpenFile(path, os.O_RDWR, 0600)
+	require.NoError(t, err)
+	_, err = file.WriteAt([]byte("x"), fileSize(t, path)-1)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	client = newTestClient(t, path, defaultClientSettings())
+	value, err := client.Get(ctx, "first")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+
+	value, err = client.Get(ctx, "second")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestClientMovesAsideFileWithInvalidHeader(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "client")
+	require.NoError(t, os.WriteFile(path, []byte("not a storage file"), 0600))
+
+	client := newTestClient(t, path, defaultClientSettings())
+	value, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	corrupted, err := os.ReadFile(path + corruptedFileSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("not a storage file"), corrupted)
+}
+
+func TestClientCompaction(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "client")
+	settings := defaultClientSettings()
+	settings.compactionMinSize = 0
+
+	client := newTestClient(t, path, settings)
+	require.NoError(t, client.Set(ctx, "kept", []byte("value")))
+	sizeWithKeptValue := fileSize(t, path)
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, client.Set(ctx, "overwritten", []byte("value")))
+	}
+	require.NoError(t, client.Delete(ctx, "overwritten"))
+
+	// the garbage never takes more than half of the file
+	assert.LessOrEqual(t, client.garbageSize()*2, client.size)
+	assert.Equal(t, client.size, fileSize(t, path))
+	assert.Less(t, fileSize(t, path), 2*sizeWithKeptValue)
+
+	value, err := client.Get(ctx, "kept")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+}
+
+func TestClientCompactionOnStart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "client")
+
+	client, err := newFileStorageClient(path, defaultClientSettings(), zap.NewNop(), func() {})
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "kept", []byte("value")))
+	sizeWithKeptValue := fileSize(t, path)
+	require.NoError(t, client.Set(ctx, "deleted", []byte("value")))
+	require.NoError(t, client.Delete(ctx, "deleted"))
+	require.NoError(t, client.Close(ctx))
+	assert.Greater(t, fileSize(t, path), sizeWithKeptValue)
+
+	client = newTestClient(t, path, defaultClientSettings())
+	assert.Equal(t, sizeWithKeptValue, fileSize(t, path))
+
+	value, err := client.Get(ctx, "kept")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+}
+
+func TestClientMaxSize(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "client")
+	settings := defaultClientSettings()
+	settings.maxSize = 100
+	settings.compactionGarbageRatio = 1
+
+	client := newTestClient(t, path, settings)
+	value := []byte("0123456789")
+	require.NoError(t, client.Set(ctx, "first", value))
+	require.NoError(t, client.Set(ctx, "second", value))
+	require.NoError(t, client.Set(ctx, "third", value))
+
+	err := client.Set(ctx, "fourth", value)
+	assert.ErrorIs(t, err, errStorageFull)
+	assert.LessOrEqual(t, fileSize(t, path), settings.maxSize)
+
+	// the space of the deleted value is reclaimed
+	require.NoError(t, client.Delete(ctx, "first"))
+	require.NoError(t, client.Set(ctx, "fourth", value))
+	assert.LessOrEqual(t, fileSize(t, path), settings.maxSize)
+
+	result, err := client.Get(ctx, "fourth")
+	require.NoError(t, err)
+	assert.Equal(t, value, result)
+}
@@ -0,0 +1,77 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicfilestorageextension
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the Sumo Logic file storage extension.
+type Config struct {
+	config.ExtensionSettings `mapstructure:"-"`
+
+	// Directory is the directory in which the files of the clients are kept.
+	Directory string `mapstructure:"directory"`
+
+	// MaxSizeMiB is the maximum size of the file of a single client in MiB.
+	// When it's reached, the new values cannot be set until the space is
+	// reclaimed by deleting the existing ones. 0 disables the limit.
+	MaxSizeMiB int64 `mapstructure:"max_size_mib"`
+
+	// Fsync specifies whether the file is synced to the disk after every change.
+	Fsync bool `mapstructure:"fsync"`
+
+	// Compaction defines when the file is compacted, i.e. rewritten with
+	// the current values only.
+	Compaction CompactionConfig `mapstructure:"compaction"`
+}
+
+// CompactionConfig defines when the files of the clients are compacted.
+type CompactionConfig struct {
+	// OnStart specifies whether the file is compacted when the client is created.
+	OnStart bool `mapstructure:"on_start"`
+
+	// GarbageRatio is the fraction of the file taken by the removed and overwritten
+	// values, above which the file is compacted.
+	GarbageRatio float64 `mapstructure:"garbage_ratio"`
+
+	// MinSizeMiB is the size of the file in MiB below which the file is not compacted.
+	MinSizeMiB int64 `mapstructure:"min_size_mib"`
+}
+
+var _ config.Extension = (*Config)(nil)
+
+// Validate checks if the extension configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.Directory == "" {
+		return errors.New("directory cannot be empty")
+	}
+	if cfg.MaxSizeMiB < 0 {
+		return fmt.Errorf("max_size_mib cannot be negative: %d", cfg.MaxSizeMiB)
+	}
+	if cfg.Compaction.GarbageRatio <= 0 || cfg.Compaction.GarbageRatio > 1 {
+		return fmt.Errorf("compaction: garbage_ratio has to be greater than 0 and not greater than 1: %v", cfg.Compaction.GarbageRatio)
+	}
+	if cfg.Compaction.MinSizeMiB < 0 {
+		return fmt.Errorf("compaction: min_size_mib cannot be negative: %d", cfg.Compaction.MinSizeMiB)
+	}
+	if cfg.MaxSizeMiB > 0 && cfg.Compaction.MinSizeMiB > cfg.MaxSizeMiB {
+		return fmt.Errorf("compaction: min_size_mib cannot be greater than max_size_mib: %d > %d", cfg.Compaction.MinSizeMiB, cfg.MaxSizeMiB)
+	}
+	return nil
+}
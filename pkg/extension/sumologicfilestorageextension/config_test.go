@@ -0,0 +1,115 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicfilestorageextension
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Extensions[typeStr] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Extensions[config.NewComponentID(typeStr)])
+
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: config.NewExtensionSettings(config.NewComponentIDWithName(typeStr, "custom")),
+			Directory:         "/var/lib/otelcol-sumo/queue",
+			MaxSizeMiB:        512,
+			Fsync:             true,
+			Compaction: CompactionConfig{
+				OnStart:      false,
+				GarbageRatio: 0.25,
+				MinSizeMiB:   16,
+			},
+		},
+		cfg.Extensions[config.NewComponentIDWithName(typeStr, "custom")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		modify        func(cfg *Config)
+		expectedError string
+	}{
+		{
+			name:   "default",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:          "empty directory",
+			modify:        func(cfg *Config) { cfg.Directory = "" },
+			expectedError: "directory cannot be empty",
+		},
+		{
+			name:          "negative max size",
+			modify:        func(cfg *Config) { cfg.MaxSizeMiB = -1 },
+			expectedError: "max_size_mib cannot be negative: -1",
+		},
+		{
+			name:          "zero garbage ratio",
+			modify:        func(cfg *Config) { cfg.Compaction.GarbageRatio = 0 },
+			expectedError: "compaction: garbage_ratio has to be greater than 0 and not greater than 1: 0",
+		},
+		{
+			name:          "garbage ratio greater than 1",
+			modify:        func(cfg *Config) { cfg.Compaction.GarbageRatio = 1.5 },
+			expectedError: "compaction: garbage_ratio has to be greater than 0 and not greater than 1: 1.5",
+		},
+		{
+			name:          "negative compaction min size",
+			modify:        func(cfg *Config) { cfg.Compaction.MinSizeMiB = -1 },
+			expectedError: "compaction: min_size_mib cannot be negative: -1",
+		},
+		{
+			name: "compaction min size greater than max size",
+			modify: func(cfg *Config) {
+				cfg.MaxSizeMiB = 10
+				cfg.Compaction.MinSizeMiB = 20
+			},
+			expectedError: "compaction: min_size_mib cannot be greater than max_size_mib: 20 > 10",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicfilestorageextension
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+const bytesInMiB = 1024 * 1024
+
+// unsafeCharacters matches the characters which are replaced in the names of the files
+var unsafeCharacters = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+type fileStorage struct {
+	cfg    *Config
+	logger *zap.Logger
+
+	// lock guards clients
+	lock sync.Mutex
+	// clients holds the open clients per file name, so the same file is never opened twice
+	clients map[string]*fileStorageClient
+}
+
+var _ storage.Extension = (*fileStorage)(nil)
+
+func newFileStorage(cfg *Config, logger *zap.Logger) *fileStorage {
+	return &fileStorage{
+		cfg:     cfg,
+		logger:  logger,
+		clients: make(map[string]*fileStorageClient),
+	}
+}
+
+// Start checks that the directory exists
+func (fs *fileStorage) Start(context.Context, component.Host) error {
+	info, err := os.Stat(fs.cfg.Directory)
+	if err != nil {
+		return fmt.Errorf("cannot access the directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", fs.cfg.Directory)
+	}
+	return nil
+}
+
+// Shutdown closes the clients which weren't closed by the components
+func (fs *fileStorage) Shutdown(ctx context.Context) error {
+	fs.lock.Lock()
+	clients := make([]*fileStorageClient, 0, len(fs.clients))
+	for _, client := range fs.clients {
+		clients = append(clients, client)
+	}
+	fs.lock.Unlock()
+
+	var errs []string
+	for _, client := range clients {
+		if err := client.Close(ctx); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close the clients: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// GetClient returns the client storing the data of the component in a separate file
+func (fs *fileStorage) GetClient(_ context.Context, kind component.Kind, id config.ComponentID, storageName string) (storage.Client, error) {
+	name := fmt.Sprintf("%s_%s_%s", kindString(kind), id.Type(), id.Name())
+	if storageName != "" {
+		name = fmt.Sprintf("%s_%s", name, storageName)
+	}
+	name = unsafeCharacters.ReplaceAllString(name, "_")
+
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	if _, ok := fs.clients[name]; ok {
+		return nil, fmt.Errorf("the client of %s is already open", name)
+	}
+
+	client, err := newFileStorageClient(
+		filepath.Join(fs.cfg.Directory, name),
+		clientSettings{
+			maxSize:                fs.cfg.MaxSizeMiB * bytesInMiB,
+			fsync:                  fs.cfg.Fsync,
+			compactOnStart:         fs.cfg.Compaction.OnStart,
+			compactionGarbageRatio: fs.cfg.Compaction.GarbageRatio,
+			compactionMinSize:      fs.cfg.Compaction.MinSizeMiB * bytesInMiB,
+		},
+		fs.logger.With(zap.String("file", name)),
+		func() { fs.removeClient(name) },
+	)
+	if err != nil {
+		return nil, err
+	}
+	fs.clients[name] = client
+	return client, nil
+}
+
+func (fs *fileStorage) removeClient(name string) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	delete(fs.clients, name)
+}
+
+func kindString(kind component.Kind) string {
+	switch kind {
+	case component.KindReceiver:
+		return "receiver"
+	case component.KindProcessor:
+		return "processor"
+	case component.KindExporter:
+		return "exporter"
+	case component.KindExtension:
+		return "extension"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicfilestorageextension
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.uber.org/zap"
+)
+
+func newTestFileStorage(t *testing.T) *fileStorage {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Directory = t.TempDir()
+
+	fs := newFileStorage(cfg, zap.NewNop())
+	require.NoError(t, fs.Start(context.Background(), componenttest.NewNopHost()))
+	return fs
+}
+
+func TestStartWithoutDirectory(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Directory = filepath.Join(t.TempDir(), "missing")
+
+	fs := newFileStorage(cfg, zap.NewNop())
+	assert.Error(t, fs.Start(context.Background(), componenttest.NewNopHost()))
+}
+
+func TestGetClientFileName(t *testing.T) {
+	ctx := context.Background()
+	fs := newTestFileStorage(t)
+
+	client, err := fs.GetClient(ctx, component.KindExporter, config.NewComponentIDWithName("sumologic", "logs/1"), "logs")
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "key", []byte("value")))
+	require.NoError(t, client.Close(ctx))
+
+	_, err = os.Stat(filepath.Join(fs.cfg.Directory, "exporter_sumologic_logs_1_logs"))
+	assert.NoError(t, err)
+}
+
+func TestGetClientTwice(t *testing.T) {
+	ctx := context.Background()
+	fs := newTestFileStorage(t)
+	id := config.NewComponentID("sumologic")
+
+	client, err := fs.GetClient(ctx, component.KindExporter, id, "logs")
+	require.NoError(t, err)
+
+	_, err = fs.GetClient(ctx, component.KindExporter, id, "logs")
+	assert.EqualError(t, err, "the client of exporter_sumologic__logs is already open")
+
+	// the client of the other signal uses a separate file
+	other, err := fs.GetClient(ctx, component.KindExporter, id, "metrics")
+	require.NoError(t, err)
+	require.NoError(t, other.Close(ctx))
+
+	// the file can be opened again after the client is closed
+	require.NoError(t, client.Close(ctx))
+	client, err = fs.GetClient(ctx, component.KindExporter, id, "logs")
+	require.NoError(t, err)
+	require.NoError(t, client.Close(ctx))
+}
+
+func TestShutdownClosesClients(t *testing.T) {
+	ctx := context.Background()
+	fs := newTestFileStorage(t)
+
+	client, err := fs.GetClient(ctx, component.KindReceiver, config.NewComponentID("journald"), "")
+	require.NoError(t, err)
+
+	require.NoError(t, fs.Shutdown(ctx))
+	assert.Empty(t, fs.clients)
+	assert.ErrorIs(t, client.Set(ctx, "key", []byte("value")), errClientClosed)
+}
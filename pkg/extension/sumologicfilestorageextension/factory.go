@@ -0,0 +1,59 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicfilestorageextension
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+)
+
+const (
+	// The value of extension "type" in configuration.
+	typeStr = "sumologic_file_storage"
+
+	defaultDirectory              = "/var/lib/otelcol-sumo/file_storage"
+	defaultMaxSizeMiB             = 1024
+	defaultCompactionGarbageRatio = 0.5
+	defaultCompactionMinSizeMiB   = 1
+)
+
+// NewFactory creates a factory for the Sumo Logic file storage extension.
+func NewFactory() component.ExtensionFactory {
+	return component.NewExtensionFactory(
+		typeStr,
+		createDefaultConfig,
+		createExtension,
+	)
+}
+
+func createDefaultConfig() config.Extension {
+	return &Config{
+		ExtensionSettings: config.NewExtensionSettings(config.NewComponentID(typeStr)),
+		Directory:         defaultDirectory,
+		MaxSizeMiB:        defaultMaxSizeMiB,
+		Fsync:             false,
+		Compaction: CompactionConfig{
+			OnStart:      true,
+			GarbageRatio: defaultCompactionGarbageRatio,
+			MinSizeMiB:   defaultCompactionMinSizeMiB,
+		},
+	}
+}
+
+func createExtension(_ context.Context, params component.ExtensionCreateSettings, cfg config.Extension) (component.Extension, error) {
+	return newFileStorage(cfg.(*Config), params.Logger), nil
+}
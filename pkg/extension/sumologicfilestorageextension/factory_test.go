@@ -0,0 +1,56 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicfilestorageextension
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+)
+
+func TestFactory_CreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.Equal(t, &Config{
+		ExtensionSettings: config.NewExtensionSettings(config.NewComponentID(typeStr)),
+		Directory:         defaultDirectory,
+		MaxSizeMiB:        defaultMaxSizeMiB,
+		Compaction: CompactionConfig{
+			OnStart:      true,
+			GarbageRatio: defaultCompactionGarbageRatio,
+			MinSizeMiB:   defaultCompactionMinSizeMiB,
+		},
+	}, cfg)
+
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestFactory_CreateExtension(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Directory = t.TempDir()
+
+	ext, err := createExtension(context.Background(),
+		component.ExtensionCreateSettings{
+			TelemetrySettings: componenttest.NewNopTelemetrySettings(),
+		},
+		cfg,
+	)
+	require.NoError(t, err)
+	require.NotNil(t, ext)
+}
@@ -0,0 +1,60 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologichealthcheckextension
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+// Config defines configuration for the Sumo Logic health check extension.
+type Config struct {
+	config.ExtensionSettings      `mapstructure:"-"`
+	confighttp.HTTPServerSettings `mapstructure:",squash"`
+
+	// Path is the path of the endpoint reporting the export status.
+	Path string `mapstructure:"path"`
+
+	// Window is the period over which the numbers of successful
+	// and failed exports are reported.
+	Window time.Duration `mapstructure:"window"`
+
+	// UnhealthyAfter is for how long an exporter has to fail, without any
+	// successful export, to be reported as unhealthy.
+	UnhealthyAfter time.Duration `mapstructure:"unhealthy_after"`
+}
+
+var _ config.Extension = (*Config)(nil)
+
+// Validate checks if the extension configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.Endpoint == "" {
+		return fmt.Errorf("endpoint cannot be empty")
+	}
+	if !strings.HasPrefix(cfg.Path, "/") {
+		return fmt.Errorf("path has to start with /: %q", cfg.Path)
+	}
+	if cfg.Window <= 0 {
+		return fmt.Errorf("window has to be positive: %s", cfg.Window)
+	}
+	if cfg.UnhealthyAfter <= 0 {
+		return fmt.Errorf("unhealthy_after has to be positive: %s", cfg.UnhealthyAfter)
+	}
+	return nil
+}
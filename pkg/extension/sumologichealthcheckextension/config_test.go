@@ -0,0 +1,102 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologichealthcheckextension
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Extensions[typeStr] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Extensions[config.NewComponentID(typeStr)])
+
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: config.NewExtensionSettings(config.NewComponentIDWithName(typeStr, "custom")),
+			HTTPServerSettings: confighttp.HTTPServerSettings{
+				Endpoint: "localhost:8080",
+			},
+			Path:           "/health",
+			Window:         time.Minute,
+			UnhealthyAfter: 10 * time.Minute,
+		},
+		cfg.Extensions[config.NewComponentIDWithName(typeStr, "custom")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		modify        func(cfg *Config)
+		expectedError string
+	}{
+		{
+			name:   "default",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:          "empty endpoint",
+			modify:        func(cfg *Config) { cfg.Endpoint = "" },
+			expectedError: "endpoint cannot be empty",
+		},
+		{
+			name:          "relative path",
+			modify:        func(cfg *Config) { cfg.Path = "health" },
+			expectedError: `path has to start with /: "health"`,
+		},
+		{
+			name:          "zero window",
+			modify:        func(cfg *Config) { cfg.Window = 0 },
+			expectedError: "window has to be positive: 0s",
+		},
+		{
+			name:          "negative unhealthy after",
+			modify:        func(cfg *Config) { cfg.UnhealthyAfter = -time.Second },
+			expectedError: "unhealthy_after has to be positive: -1s",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
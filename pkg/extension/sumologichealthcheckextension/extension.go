@@ -0,0 +1,247 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologichealthcheckextension
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opencensus.io/metric/metricproducer"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.uber.org/zap"
+)
+
+// queueSizeMetric is the name of the metric with the sizes of the sending queues
+// of the exporters, labeled with the exporter name
+const queueSizeMetric = "exporter/queue_size"
+
+// ExportReporter is implemented by the extension, so the exporters can report
+// the results of their exports. The exporters don't have to import this package,
+// it's enough that they look for the extensions with the ReportExport method.
+type ExportReporter interface {
+	// ReportExport records the result of a single export of the pipeline
+	// (e.g. logs) of the exporter, err is nil when the export succeeded.
+	ReportExport(exporter config.ComponentID, pipeline string, err error)
+}
+
+// HealthCheck serves the export status of the exporters over HTTP, so it can be
+// used by the liveness and readiness probes. It reports the collector as unhealthy
+// only when an exporter keeps failing without any success for unhealthy_after.
+type HealthCheck struct {
+	cfg      *Config
+	logger   *zap.Logger
+	settings component.TelemetrySettings
+	server   *http.Server
+	wg       sync.WaitGroup
+
+	// now returns the current time, replaced in tests
+	now func() time.Time
+	// queueSizes returns the sizes of the sending queues per exporter, replaced in tests
+	queueSizes func() map[string]int64
+
+	// lock guards statuses
+	lock sync.Mutex
+	// statuses maps the exporter to the statuses of its pipelines
+	statuses map[config.ComponentID]map[string]*pipelineStatus
+}
+
+var _ ExportReporter = (*HealthCheck)(nil)
+
+// statusResponse is the response of the status endpoint
+type statusResponse struct {
+	Healthy   bool                               `json:"healthy"`
+	Exporters map[string]*exporterStatusResponse `json:"exporters"`
+}
+
+// exporterStatusResponse is the status of a single exporter
+type exporterStatusResponse struct {
+	Healthy   bool                               `json:"healthy"`
+	QueueSize *int64                             `json:"queue_size,omitempty"`
+	Pipelines map[string]*pipelineStatusResponse `json:"pipelines,omitempty"`
+}
+
+// pipelineStatusResponse is the status of a single pipeline of an exporter
+type pipelineStatusResponse struct {
+	Healthy       bool       `json:"healthy"`
+	Successes     int64      `json:"successes"`
+	Failures      int64      `json:"failures"`
+	ErrorRate     float64    `json:"error_rate"`
+	LastSuccess   *time.Time `json:"last_success,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorTime *time.Time `json:"last_error_time,omitempty"`
+	FailingSince  *time.Time `json:"failing_since,omitempty"`
+}
+
+func newHealthCheck(cfg *Config, settings component.TelemetrySettings) *HealthCheck {
+	return &HealthCheck{
+		cfg:        cfg,
+		logger:     settings.Logger,
+		settings:   settings,
+		now:        time.Now,
+		queueSizes: readQueueSizes,
+		statuses:   make(map[config.ComponentID]map[string]*pipelineStatus),
+	}
+}
+
+// Start starts the HTTP server serving the export status
+func (hc *HealthCheck) Start(_ context.Context, host component.Host) error {
+	ln, err := hc.cfg.ToListener()
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(hc.cfg.Path, hc.handleStatus)
+	hc.server, err = hc.cfg.ToServer(host, hc.settings, mux)
+	if err != nil {
+		ln.Close()
+		return err
+	}
+
+	hc.logger.Info("Starting health check server", zap.String("endpoint", hc.cfg.Endpoint))
+	hc.wg.Add(1)
+	go func() {
+		defer hc.wg.Done()
+		if err := hc.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			host.ReportFatalError(err)
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the HTTP server
+func (hc *HealthCheck) Shutdown(ctx context.Context) error {
+	if hc.server == nil {
+		return nil
+	}
+	err := hc.server.Shutdown(ctx)
+	hc.wg.Wait()
+	return err
+}
+
+// ReportExport records the result of a single export of the pipeline of the exporter
+func (hc *HealthCheck) ReportExport(exporter config.ComponentID, pipeline string, err error) {
+	hc.lock.Lock()
+	defer hc.lock.Unlock()
+
+	pipelines, ok := hc.statuses[exporter]
+	if !ok {
+		pipelines = make(map[string]*pipelineStatus)
+		hc.statuses[exporter] = pipelines
+	}
+	status, ok := pipelines[pipeline]
+	if !ok {
+		status = newPipelineStatus(hc.cfg.Window)
+		pipelines[pipeline] = status
+	}
+	status.report(hc.now(), err)
+}
+
+// status returns the current export status of all the exporters
+func (hc *HealthCheck) status() statusResponse {
+	now := hc.now()
+	resp := statusResponse{
+		Healthy:   true,
+		Exporters: make(map[string]*exporterStatusResponse),
+	}
+	exporterStatus := func(name string) *exporterStatusResponse {
+		es, ok := resp.Exporters[name]
+		if !ok {
+			es = &exporterStatusResponse{Healthy: true}
+			resp.Exporters[name] = es
+		}
+		return es
+	}
+
+	hc.lock.Lock()
+	for exporter, pipelines := range hc.statuses {
+		es := exporterStatus(exporter.String())
+		es.Pipelines = make(map[string]*pipelineStatusResponse, len(pipelines))
+		for pipeline, s := range pipelines {
+			ps := &pipelineStatusResponse{
+				Healthy:       s.healthy(now, hc.cfg.UnhealthyAfter),
+				LastError:     s.lastError,
+				LastSuccess:   timeOrNil(s.lastSuccess),
+				LastErrorTime: timeOrNil(s.lastErrorTime),
+				FailingSince:  timeOrNil(s.failingSince),
+			}
+			ps.Successes, ps.Failures = s.counts(now)
+			if total := ps.Successes + ps.Failures; total > 0 {
+				ps.ErrorRate = float64(ps.Failures) / float64(total)
+			}
+			es.Pipelines[pipeline] = ps
+
+			if !ps.Healthy {
+				es.Healthy = false
+				resp.Healthy = false
+			}
+		}
+	}
+	hc.lock.Unlock()
+
+	for exporter, size := range hc.queueSizes() {
+		size := size
+		exporterStatus(exporter).QueueSize = &size
+	}
+	return resp
+}
+
+func (hc *HealthCheck) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	resp := hc.status()
+
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Healthy {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		hc.logger.Debug("Failed to write the health check response", zap.Error(err))
+	}
+}
+
+// readQueueSizes reads the sizes of the sending queues of the exporters
+// from the metrics registered by the exporter helper
+func readQueueSizes() map[string]int64 {
+	sizes := make(map[string]int64)
+	for _, producer := range metricproducer.GlobalManager().GetAll() {
+		for _, m := range producer.Read() {
+			if m.Descriptor.Name != queueSizeMetric || len(m.Descriptor.LabelKeys) != 1 {
+				continue
+			}
+			for _, ts := range m.TimeSeries {
+				if len(ts.LabelValues) != 1 || !ts.LabelValues[0].Present || len(ts.Points) == 0 {
+					continue
+				}
+				if v, ok := ts.Points[len(ts.Points)-1].Value.(int64); ok {
+					sizes[ts.LabelValues[0].Value] = v
+				}
+			}
+		}
+	}
+	return sizes
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
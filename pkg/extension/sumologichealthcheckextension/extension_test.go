@@ -0,0 +1,126 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologichealthcheckextension
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+)
+
+// availableLocalAddress returns a local address with a free port
+func availableLocalAddress(t *testing.T) string {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+func newTestHealthCheck(t *testing.T) (*HealthCheck, *time.Time) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = availableLocalAddress(t)
+	cfg.Window = time.Minute
+	cfg.UnhealthyAfter = 5 * time.Minute
+
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	hc := newHealthCheck(cfg, componenttest.NewNopTelemetrySettings())
+	hc.now = func() time.Time { return now }
+	hc.queueSizes = func() map[string]int64 { return map[string]int64{"sumologic": 3} }
+	return hc, &now
+}
+
+func TestStatus(t *testing.T) {
+	hc, now := newTestHealthCheck(t)
+	exporter := config.NewComponentID("sumologic")
+
+	hc.ReportExport(exporter, "logs", nil)
+	hc.ReportExport(exporter, "logs", errors.New("connection refused"))
+	hc.ReportExport(exporter, "metrics", nil)
+
+	status := hc.status()
+	assert.True(t, status.Healthy)
+	require.Contains(t, status.Exporters, "sumologic")
+
+	es := status.Exporters["sumologic"]
+	require.NotNil(t, es.QueueSize)
+	assert.EqualValues(t, 3, *es.QueueSize)
+
+	logs := es.Pipelines["logs"]
+	assert.True(t, logs.Healthy)
+	assert.EqualValues(t, 1, logs.Successes)
+	assert.EqualValues(t, 1, logs.Failures)
+	assert.Equal(t, 0.5, logs.ErrorRate)
+	assert.Equal(t, "connection refused", logs.LastError)
+	assert.Equal(t, *now, *logs.FailingSince)
+
+	// The exports keep failing for unhealthy_after
+	*now = now.Add(5 * time.Minute)
+	hc.ReportExport(exporter, "logs", errors.New("connection refused"))
+
+	status = hc.status()
+	assert.False(t, status.Healthy)
+	assert.False(t, status.Exporters["sumologic"].Healthy)
+	assert.False(t, status.Exporters["sumologic"].Pipelines["logs"].Healthy)
+	assert.True(t, status.Exporters["sumologic"].Pipelines["metrics"].Healthy)
+	// Only the exports within the window are counted
+	assert.EqualValues(t, 0, status.Exporters["sumologic"].Pipelines["logs"].Successes)
+	assert.EqualValues(t, 1, status.Exporters["sumologic"].Pipelines["logs"].Failures)
+
+	// A single success makes the exporter healthy again
+	hc.ReportExport(exporter, "logs", nil)
+	status = hc.status()
+	assert.True(t, status.Healthy)
+	assert.Nil(t, status.Exporters["sumologic"].Pipelines["logs"].FailingSince)
+}
+
+func TestStatusEndpoint(t *testing.T) {
+	hc, now := newTestHealthCheck(t)
+	hc.cfg.Path = "/health"
+	require.NoError(t, hc.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, hc.Shutdown(context.Background())) })
+
+	url := fmt.Sprintf("http://%s/health", hc.cfg.Endpoint)
+	get := func() (int, statusResponse) {
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var status statusResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+		return resp.StatusCode, status
+	}
+
+	code, status := get()
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, status.Healthy)
+
+	hc.ReportExport(config.NewComponentID("sumologic"), "traces", errors.New("429 Too Many Requests"))
+	*now = now.Add(10 * time.Minute)
+
+	code, status = get()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, status.Healthy)
+	assert.Equal(t, "429 Too Many Requests", status.Exporters["sumologic"].Pipelines["traces"].LastError)
+}
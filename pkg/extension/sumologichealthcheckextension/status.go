@@ -0,0 +1,90 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologichealthcheckextension
+
+import (
+	"time"
+)
+
+// statusBuckets is the number of buckets the window is split into
+const statusBuckets = 10
+
+// statusBucket counts the exports within a part of the window
+type statusBucket struct {
+	// id is the number of the bucket duration since the epoch
+	id        int64
+	successes int64
+	failures  int64
+}
+
+// pipelineStatus is the export status of a single pipeline (e.g. logs) of an exporter
+type pipelineStatus struct {
+	buckets        [statusBuckets]statusBucket
+	bucketDuration time.Duration
+
+	lastSuccess   time.Time
+	lastError     string
+	lastErrorTime time.Time
+	// failingSince is the time of the first failure after the last success
+	failingSince time.Time
+}
+
+func newPipelineStatus(window time.Duration) *pipelineStatus {
+	d := window / statusBuckets
+	if d <= 0 {
+		d = 1
+	}
+	return &pipelineStatus{bucketDuration: d}
+}
+
+// report records the result of a single export
+func (s *pipelineStatus) report(now time.Time, err error) {
+	id := now.UnixNano() / int64(s.bucketDuration)
+	b := &s.buckets[id%statusBuckets]
+	if b.id != id {
+		*b = statusBucket{id: id}
+	}
+
+	if err == nil {
+		b.successes++
+		s.lastSuccess = now
+		s.failingSince = time.Time{}
+		return
+	}
+
+	b.failures++
+	s.lastError = err.Error()
+	s.lastErrorTime = now
+	if s.failingSince.IsZero() {
+		s.failingSince = now
+	}
+}
+
+// counts returns the numbers of successful and failed exports within the window
+func (s *pipelineStatus) counts(now time.Time) (successes int64, failures int64) {
+	current := now.UnixNano() / int64(s.bucketDuration)
+	for _, b := range s.buckets {
+		if b.id > current-statusBuckets && b.id <= current {
+			successes += b.successes
+			failures += b.failures
+		}
+	}
+	return successes, failures
+}
+
+// healthy returns false when the exports keep failing, without any success, for unhealthyAfter
+func (s *pipelineStatus) healthy(now time.Time, unhealthyAfter time.Duration) bool {
+	return s.failingSince.IsZero() || now.Sub(s.failingSince) < unhealthyAfter
+}
@@ -91,6 +91,24 @@ var cfgAutoRate = cfconfig.Config{
 	},
 }
 
+var cfgProbabilisticFallback = cfconfig.Config{
+	ProcessorSettings: &config.ProcessorSettings{},
+	DecisionWait:      2 * time.Second,
+	PolicyCfgs: []cfconfig.TraceAcceptCfg{
+		{
+			Name:           "duration",
+			SpansPerSecond: 20,
+			PropertiesCfg: cfconfig.PropertiesCfg{
+				MinDuration: &testValue,
+			},
+		},
+	},
+	ProbabilisticFallbackCfg: &cfconfig.ProbabilisticFallbackCfg{
+		Percentage:     50,
+		SpansPerSecond: 30,
+	},
+}
+
 func fillSpan(span *pdata.Span, durationMicros int64) {
 	nowTs := time.Now().UnixNano()
 	startTime := nowTs - durationMicros*1000
@@ -182,6 +200,54 @@ func TestProbabilisticFilter(t *testing.T) {
 	cfg.ProbabilisticFilteringRatio = &ratio
 }
 
+func TestProbabilisticFallback(t *testing.T) {
+	cascading := createCascadingEvaluatorWithConfig(t, cfgProbabilisticFallback)
+	require.Len(t, cascading.traceAcceptRules, 2)
+	require.True(t, cascading.traceAcceptRules[1].probabilisticFallback)
+	require.Equal(t, int32(50), cascading.maxSpansPerSecond)
+
+	// Trace IDs with low bits set to zero are always selected by the fallback policy
+	// while the ones with all bits set are never selected with 50%
+	selectedID := pdata.NewTraceID([16]byte{1})
+	notSelectedID := pdata.NewTraceID([16]byte{1, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+
+	// Matched by the duration policy
+	trace1 := createTrace(cascading, 10, 1000000)
+	decision, policy := cascading.makeProvisionalDecision(selectedID, trace1)
+	require.Equal(t, sampling.Sampled, decision)
+	require.Equal(t, "duration", policy.Name)
+	require.False(t, trace1.SelectedByProbabilisticFallback)
+
+	// Not matched by any policy, but selected by the fallback
+	trace2 := createTrace(cascading, 10, 1000)
+	decision, policy = cascading.makeProvisionalDecision(selectedID, trace2)
+	require.Equal(t, sampling.Sampled, decision)
+	require.Equal(t, probabilisticFallbackPolicyName, policy.Name)
+	require.True(t, trace2.SelectedByProbabilisticFallback)
+
+	// Not matched by any policy and not selected by the fallback
+	trace3 := createTrace(cascading, 10, 1000)
+	decision, _ = cascading.makeProvisionalDecision(notSelectedID, trace3)
+	require.Equal(t, sampling.NotSampled, decision)
+	require.False(t, trace3.SelectedByProbabilisticFallback)
+
+	// Selected by the fallback, but exceeding its spans per second budget
+	trace4 := createTrace(cascading, 35, 1000)
+	decision, _ = cascading.makeProvisionalDecision(selectedID, trace4)
+	require.Equal(t, sampling.NotSampled, decision)
+	require.False(t, trace4.SelectedByProbabilisticFallback)
+}
+
+func TestProbabilisticFallbackInvalidConfig(t *testing.T) {
+	conf := cfgProbabilisticFallback
+	conf.ProbabilisticFallbackCfg = &cfconfig.ProbabilisticFallbackCfg{
+		Percentage:     150,
+		SpansPerSecond: 30,
+	}
+	_, err := newCascadingFilterSpanProcessor(zap.NewNop(), nil, conf)
+	require.Error(t, err)
+}
+
 func TestDropTraces(t *testing.T) {
 	cascading := createCascadingEvaluator(t)
 
@@ -32,10 +32,15 @@ type TraceAcceptCfg struct {
 	AttributeCfg []AttributeCfg `mapstructure:"attributes"`
 	// Configs for properties sampling policy evaluator.
 	PropertiesCfg PropertiesCfg `mapstructure:"properties"`
+	// TraceAggregatesCfg (optional) configs conditions evaluated over the whole trace rather than individual spans.
+	TraceAggregatesCfg *TraceAggregatesCfg `mapstructure:"trace_aggregates"`
 	// SpansPerSecond specifies the rule budget that should never be exceeded for it
 	SpansPerSecond int32 `mapstructure:"spans_per_second"`
 	// InvertMatch specifies if the match should be inverted. Default: false
 	InvertMatch bool `mapstructure:"invert_match"`
+	// Priority is stamped on the traces selected by this policy, so the exporters can prefer
+	// keeping the traces with higher priority when shedding load. Optional.
+	Priority *int64 `mapstructure:"priority"`
 }
 
 // PropertiesCfg holds the configurable settings to create a duration filter
@@ -44,12 +49,34 @@ type PropertiesCfg struct {
 	NamePattern *string `mapstructure:"name_pattern"`
 	// MinDuration (optional) is the minimum duration of trace to be considered a match.
 	MinDuration *time.Duration `mapstructure:"min_duration"`
+	// MinSpanDuration (optional) is the minimum duration of any single span for the trace to be considered a match.
+	MinSpanDuration *time.Duration `mapstructure:"min_span_duration"`
 	// MinNumberOfSpans (optional) is the minimum number spans that must be present in a matching trace.
 	MinNumberOfSpans *int `mapstructure:"min_number_of_spans"`
 	// MinNumberOfErrors (optional) is the minimum number of spans with the status set to error that must be present in a matching trace.
 	MinNumberOfErrors *int `mapstructure:"min_number_of_errors"`
 }
 
+// TraceAggregatesCfg holds the configurable conditions evaluated over the aggregates of the whole trace.
+// All the specified conditions must be met for the trace to be considered a match.
+type TraceAggregatesCfg struct {
+	// MinSpanCount (optional) is the minimum number of spans in the trace.
+	MinSpanCount *int `mapstructure:"min_span_count"`
+	// MaxSpanCount (optional) is the maximum number of spans in the trace.
+	MaxSpanCount *int `mapstructure:"max_span_count"`
+	// MinDuration (optional) is the minimum end-to-end duration of the trace, i.e. from the start
+	// of its earliest span until the end of its latest span.
+	MinDuration *time.Duration `mapstructure:"min_duration"`
+	// MaxDuration (optional) is the maximum end-to-end duration of the trace.
+	MaxDuration *time.Duration `mapstructure:"max_duration"`
+	// HasError (optional) selects the traces with (when true) or without (when false) any span with the error status.
+	HasError *bool `mapstructure:"has_error"`
+	// MinServiceCount (optional) is the minimum number of distinct services (service.name resource attribute values) in the trace.
+	MinServiceCount *int `mapstructure:"min_service_count"`
+	// MaxServiceCount (optional) is the maximum number of distinct services in the trace.
+	MaxServiceCount *int `mapstructure:"max_service_count"`
+}
+
 // NumericAttributeCfg holds the configurable settings to create a numeric attribute filter
 // sampling policy evaluator.
 type NumericAttributeCfg struct {
@@ -78,6 +105,14 @@ type AttributeRange struct {
 	MaxValue int64 `mapstructure:"max"`
 }
 
+// AttributeComparison defines numeric comparison of the attribute value with the provided value
+type AttributeComparison struct {
+	// Operator is one of: ">", ">=", "<", "<=", "==", "!="
+	Operator string `mapstructure:"operator"`
+	// Value is compared with the attribute value
+	Value float64 `mapstructure:"value"`
+}
+
 // AttributeCfg holds a universal config specification for a given key
 type AttributeCfg struct {
 	// Tag that the filter is going to be matching against.
@@ -88,6 +123,8 @@ type AttributeCfg struct {
 	UseRegex bool `mapstructure:"use_regex"`
 	// Ranges keep numeric attribute ranges
 	Ranges []AttributeRange `mapstructure:"ranges"`
+	// Comparisons keep numeric attribute comparisons, all of them must be met
+	Comparisons []AttributeComparison `mapstructure:"comparisons"`
 }
 
 // TraceRejectCfg holds the configurable settings which drop all traces matching the specified criteria (all of them)
@@ -105,6 +142,45 @@ type TraceRejectCfg struct {
 	NamePattern *string `mapstructure:"name_pattern"`
 }
 
+// ProbabilisticFallbackCfg holds the configurable settings of the policy which probabilistically
+// selects traces that were not matched by any of the trace accept policies.
+type ProbabilisticFallbackCfg struct {
+	// Percentage (0.0-100.0) of otherwise not matched traces which should be selected.
+	Percentage float32 `mapstructure:"percentage"`
+	// SpansPerSecond specifies the budget for the fallback policy that should never be exceeded.
+	SpansPerSecond int32 `mapstructure:"spans_per_second"`
+}
+
+// DecisionCacheCfg holds the configurable settings of the cache which keeps the final decisions
+// for traces that were already removed from memory.
+type DecisionCacheCfg struct {
+	// TTL is the time for which the decision is kept after the trace was removed from memory.
+	TTL time.Duration `mapstructure:"ttl"`
+	// MaxSize is the maximum number of decisions kept in the cache. When set to zero (default value)
+	// it is equal to NumTraces.
+	MaxSize uint64 `mapstructure:"max_size"`
+}
+
+// SpillCfg holds the configurable settings of spilling undecided traces to disk.
+type SpillCfg struct {
+	// Directory where the spilled traces are stored.
+	Directory string `mapstructure:"directory"`
+	// MaxDiskUsage is the maximum number of bytes used by the spilled traces.
+	MaxDiskUsage int64 `mapstructure:"max_disk_usage"`
+}
+
+// TenantBudgetCfg holds the configurable settings of partitioning the total spans per second budget
+// by the tenant of the trace.
+type TenantBudgetCfg struct {
+	// Attribute is the resource attribute which identifies the tenant of the trace, e.g. service.namespace.
+	Attribute string `mapstructure:"attribute"`
+	// SpansPerSecond is the budget of each tenant, unless it is overridden in Tenants. When set to zero
+	// (default value) - only the tenants listed in Tenants are limited.
+	SpansPerSecond int32 `mapstructure:"spans_per_second"`
+	// Tenants overrides the budget of the given tenants, keyed by the attribute value.
+	Tenants map[string]int32 `mapstructure:"tenants"`
+}
+
 // Config holds the configuration for cascading-filter-based sampling.
 type Config struct {
 	*config.ProcessorSettings `mapstructure:"-"`
@@ -136,4 +212,16 @@ type Config struct {
 	// TraceRejectCfgs sets the criteria for which traces are evaluated before applying sampling rules. If
 	// trace matches them, it is no further processed
 	TraceRejectCfgs []TraceRejectCfg `mapstructure:"trace_reject_filters"`
+	// ProbabilisticFallbackCfg (optional) sets the policy which is evaluated after all trace accept policies
+	// and probabilistically selects the traces which were not matched by any of them.
+	ProbabilisticFallbackCfg *ProbabilisticFallbackCfg `mapstructure:"probabilistic_fallback"`
+	// DecisionCacheCfg (optional) enables caching of the final decisions, so spans arriving after the trace
+	// was removed from memory are forwarded or dropped consistently with the original decision.
+	DecisionCacheCfg *DecisionCacheCfg `mapstructure:"decision_cache"`
+	// SpillCfg (optional) enables storing undecided traces on disk when they need to be removed from memory
+	// (or on shutdown), so they are still evaluated (or recovered on startup) instead of being lost.
+	SpillCfg *SpillCfg `mapstructure:"spill_to_disk"`
+	// TenantBudgetCfg (optional) caps the spans per second of each tenant (identified by a resource attribute),
+	// so a single tenant cannot use up the whole SpansPerSecond budget.
+	TenantBudgetCfg *TenantBudgetCfg `mapstructure:"tenant_budget"`
 }
@@ -40,12 +40,17 @@ func TestLoadConfig(t *testing.T) {
 	require.NotNil(t, cfg)
 
 	minDurationValue := 9 * time.Second
+	minSpanDurationValue := 2 * time.Second
 	minSpansValue := 10
 	minErrorsValue := 2
+	errorsPriority := int64(10)
 	probFilteringRatio := float32(0.1)
 	probFilteringRate := int32(100)
 	namePatternValue := "foo.*"
 	healthCheckNamePatternValue := "health.*"
+	slowTraceDurationValue := 5 * time.Second
+	minServicesValue := 2
+	hasErrorValue := false
 
 	id1 := config.NewComponentIDWithName("cascading_filter", "1")
 	ps1 := config.NewProcessorSettings(id1)
@@ -66,6 +71,7 @@ func TestLoadConfig(t *testing.T) {
 				{
 					Name:           "include-errors",
 					SpansPerSecond: 200,
+					Priority:       &errorsPriority,
 					PropertiesCfg: cfconfig.PropertiesCfg{
 						MinNumberOfErrors: &minErrorsValue,
 					},
@@ -96,6 +102,47 @@ func TestLoadConfig(t *testing.T) {
 						},
 					},
 				},
+				{
+					Name:           "include-slow-server-errors",
+					SpansPerSecond: 600,
+					PropertiesCfg: cfconfig.PropertiesCfg{
+						MinSpanDuration: &minSpanDurationValue,
+					},
+					AttributeCfg: []cfconfig.AttributeCfg{
+						{
+							Key: "http.status_code",
+							Comparisons: []cfconfig.AttributeComparison{
+								{Operator: ">=", Value: 500},
+							},
+						},
+					},
+				},
+				{
+					Name:           "include-slow-distributed-traces",
+					SpansPerSecond: 700,
+					TraceAggregatesCfg: &cfconfig.TraceAggregatesCfg{
+						MinDuration:     &slowTraceDurationValue,
+						MinServiceCount: &minServicesValue,
+						HasError:        &hasErrorValue,
+					},
+				},
+			},
+			ProbabilisticFallbackCfg: &cfconfig.ProbabilisticFallbackCfg{
+				Percentage:     2.5,
+				SpansPerSecond: 50,
+			},
+			DecisionCacheCfg: &cfconfig.DecisionCacheCfg{
+				TTL:     5 * time.Minute,
+				MaxSize: 1000,
+			},
+			SpillCfg: &cfconfig.SpillCfg{
+				Directory:    "/var/lib/otelcol/cascading_filter",
+				MaxDiskUsage: 104857600,
+			},
+			TenantBudgetCfg: &cfconfig.TenantBudgetCfg{
+				Attribute:      "service.namespace",
+				SpansPerSecond: 200,
+				Tenants:        map[string]int32{"checkout": 500},
 			},
 		})
 
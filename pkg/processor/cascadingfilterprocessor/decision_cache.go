@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascadingfilterprocessor
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/sampling"
+)
+
+// decisionCacheEntry keeps the final decision taken for a trace which was already removed from memory
+type decisionCacheEntry struct {
+	decision     sampling.Decision
+	decisionTime time.Time
+	expiresAt    time.Time
+}
+
+// decisionCache keeps the final decisions keyed by trace ID for the configured TTL,
+// so late spans can be handled consistently with the original decision.
+// Since the TTL is the same for all entries, the insertion order is also the expiration order.
+type decisionCache struct {
+	sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	entries map[traceKey]*list.Element
+	order   *list.List
+	now     func() time.Time
+}
+
+type decisionCacheItem struct {
+	key   traceKey
+	entry decisionCacheEntry
+}
+
+func newDecisionCache(ttl time.Duration, maxSize uint64) *decisionCache {
+	return &decisionCache{
+		ttl:     ttl,
+		maxSize: int(maxSize),
+		entries: make(map[traceKey]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// Put stores the decision for the given trace, evicting the expired and the oldest entries if needed
+func (dc *decisionCache) Put(key traceKey, decision sampling.Decision, decisionTime time.Time) {
+	dc.Lock()
+	defer dc.Unlock()
+
+	now := dc.now()
+	dc.removeExpired(now)
+
+	if elem, ok := dc.entries[key]; ok {
+		dc.order.Remove(elem)
+		delete(dc.entries, key)
+	}
+
+	for dc.maxSize > 0 && dc.order.Len() >= dc.maxSize {
+		dc.removeOldest()
+	}
+
+	dc.entries[key] = dc.order.PushBack(&decisionCacheItem{
+		key: key,
+		entry: decisionCacheEntry{
+			decision:     decision,
+			decisionTime: decisionTime,
+			expiresAt:    now.Add(dc.ttl),
+		},
+	})
+}
+
+// Get returns the cached decision for the given trace if it's present and not expired
+func (dc *decisionCache) Get(key traceKey) (decisionCacheEntry, bool) {
+	dc.Lock()
+	defer dc.Unlock()
+
+	dc.removeExpired(dc.now())
+
+	elem, ok := dc.entries[key]
+	if !ok {
+		return decisionCacheEntry{}, false
+	}
+	return elem.Value.(*decisionCacheItem).entry, true
+}
+
+// Len returns the number of entries in the cache
+func (dc *decisionCache) Len() int {
+	dc.Lock()
+	defer dc.Unlock()
+	return dc.order.Len()
+}
+
+func (dc *decisionCache) removeExpired(now time.Time) {
+	for elem := dc.order.Front(); elem != nil; elem = dc.order.Front() {
+		if now.Before(elem.Value.(*decisionCacheItem).entry.expiresAt) {
+			return
+		}
+		dc.removeOldest()
+	}
+}
+
+func (dc *decisionCache) removeOldest() {
+	elem := dc.order.Front()
+	if elem == nil {
+		return
+	}
+	dc.order.Remove(elem)
+	delete(dc.entries, elem.Value.(*decisionCacheItem).key)
+}
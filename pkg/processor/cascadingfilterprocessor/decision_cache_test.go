@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascadingfilterprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/sampling"
+)
+
+func TestDecisionCacheTTL(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cache := newDecisionCache(10*time.Second, 100)
+	cache.now = func() time.Time { return now }
+
+	first := traceKey{1}
+	second := traceKey{2}
+
+	cache.Put(first, sampling.Sampled, now)
+	now = now.Add(5 * time.Second)
+	cache.Put(second, sampling.NotSampled, now)
+
+	entry, ok := cache.Get(first)
+	require.True(t, ok)
+	assert.Equal(t, sampling.Sampled, entry.decision)
+
+	now = now.Add(5 * time.Second)
+	_, ok = cache.Get(first)
+	assert.False(t, ok, "first entry should have expired")
+
+	entry, ok = cache.Get(second)
+	require.True(t, ok)
+	assert.Equal(t, sampling.NotSampled, entry.decision)
+	assert.Equal(t, 1, cache.Len())
+
+	now = now.Add(5 * time.Second)
+	_, ok = cache.Get(second)
+	assert.False(t, ok, "second entry should have expired")
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestDecisionCacheMaxSize(t *testing.T) {
+	cache := newDecisionCache(time.Minute, 2)
+
+	cache.Put(traceKey{1}, sampling.Sampled, time.Now())
+	cache.Put(traceKey{2}, sampling.Dropped, time.Now())
+	cache.Put(traceKey{3}, sampling.NotSampled, time.Now())
+
+	assert.Equal(t, 2, cache.Len())
+	_, ok := cache.Get(traceKey{1})
+	assert.False(t, ok, "oldest entry should have been evicted")
+	_, ok = cache.Get(traceKey{2})
+	assert.True(t, ok)
+	_, ok = cache.Get(traceKey{3})
+	assert.True(t, ok)
+}
+
+func TestDecisionCacheOverwrite(t *testing.T) {
+	cache := newDecisionCache(time.Minute, 2)
+
+	cache.Put(traceKey{1}, sampling.NotSampled, time.Now())
+	cache.Put(traceKey{1}, sampling.Sampled, time.Now())
+
+	assert.Equal(t, 1, cache.Len())
+	entry, ok := cache.Get(traceKey{1})
+	require.True(t, ok)
+	assert.Equal(t, sampling.Sampled, entry.decision)
+}
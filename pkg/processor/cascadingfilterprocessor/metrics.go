@@ -49,6 +49,25 @@ var (
 	statDroppedTooEarlyCount    = stats.Int64("casdading_trace_dropped_too_early", "Count of traces that needed to be dropped the configured wait time", stats.UnitDimensionless)
 	statNewTraceIDReceivedCount = stats.Int64("cascading_new_trace_id_received", "Counts the arrival of new traces", stats.UnitDimensionless)
 	statTracesOnMemoryGauge     = stats.Int64("cascading_traces_on_memory", "Tracks the number of traces current on memory", stats.UnitDimensionless)
+
+	statDecisionCacheHitCount = stats.Int64("cascading_decision_cache_hit", "Count of late spans handled using the cached decision", stats.UnitDimensionless)
+
+	statRateLimitSaturation = stats.Float64(
+		"cascading_rate_limit_saturation",
+		"Ratio (0.0-1.0) of the total spans per second budget used in the current second",
+		stats.UnitDimensionless,
+	)
+	statPolicyRateLimitSaturation = stats.Float64(
+		"cascading_policy_rate_limit_saturation",
+		"Ratio (0.0-1.0) of the policy spans per second budget used in the current second",
+		stats.UnitDimensionless,
+	)
+
+	statTracesSpilledCount  = stats.Int64("cascading_traces_spilled", "Count of undecided traces spilled to disk", stats.UnitDimensionless)
+	statTracesRestoredCount = stats.Int64("cascading_traces_restored", "Count of spilled traces restored from disk", stats.UnitDimensionless)
+	statSpillRejectedCount  = stats.Int64("cascading_traces_spill_rejected", "Count of undecided traces which could not be spilled to disk", stats.UnitDimensionless)
+
+	statSpillRestoreErrorCount = stats.Int64("cascading_traces_restore_errors", "Count of spilled traces which could not be restored from disk", stats.UnitDimensionless)
 )
 
 // CascadingFilterMetricViews return the metrics views according to given telemetry level.
@@ -130,6 +149,50 @@ func CascadingFilterMetricViews(level configtelemetry.Level) []*view.View {
 		Description: statTracesOnMemoryGauge.Description(),
 		Aggregation: view.LastValue(),
 	}
+	countDecisionCacheHitView := &view.View{
+		Name:        statDecisionCacheHitCount.Name(),
+		Measure:     statDecisionCacheHitCount,
+		Description: statDecisionCacheHitCount.Description(),
+		TagKeys:     []tag.Key{tagCascadingFilterDecisionKey},
+		Aggregation: view.Sum(),
+	}
+	rateLimitSaturationView := &view.View{
+		Name:        statRateLimitSaturation.Name(),
+		Measure:     statRateLimitSaturation,
+		Description: statRateLimitSaturation.Description(),
+		Aggregation: view.LastValue(),
+	}
+	policyRateLimitSaturationView := &view.View{
+		Name:        statPolicyRateLimitSaturation.Name(),
+		Measure:     statPolicyRateLimitSaturation,
+		Description: statPolicyRateLimitSaturation.Description(),
+		TagKeys:     []tag.Key{tagPolicyKey},
+		Aggregation: view.LastValue(),
+	}
+	countTracesSpilledView := &view.View{
+		Name:        statTracesSpilledCount.Name(),
+		Measure:     statTracesSpilledCount,
+		Description: statTracesSpilledCount.Description(),
+		Aggregation: view.Sum(),
+	}
+	countTracesRestoredView := &view.View{
+		Name:        statTracesRestoredCount.Name(),
+		Measure:     statTracesRestoredCount,
+		Description: statTracesRestoredCount.Description(),
+		Aggregation: view.Sum(),
+	}
+	countSpillRejectedView := &view.View{
+		Name:        statSpillRejectedCount.Name(),
+		Measure:     statSpillRejectedCount,
+		Description: statSpillRejectedCount.Description(),
+		Aggregation: view.Sum(),
+	}
+	countSpillRestoreErrorView := &view.View{
+		Name:        statSpillRestoreErrorCount.Name(),
+		Measure:     statSpillRestoreErrorCount,
+		Description: statSpillRestoreErrorCount.Description(),
+		Aggregation: view.Sum(),
+	}
 
 	legacyViews := []*view.View{
 		overallDecisionLatencyView,
@@ -144,6 +207,13 @@ func CascadingFilterMetricViews(level configtelemetry.Level) []*view.View {
 		countTraceDroppedTooEarlyView,
 		countTraceIDArrivalView,
 		trackTracesOnMemorylView,
+		countDecisionCacheHitView,
+		rateLimitSaturationView,
+		policyRateLimitSaturationView,
+		countTracesSpilledView,
+		countTracesRestoredView,
+		countSpillRejectedView,
+		countSpillRestoreErrorView,
 	}
 
 	// return obsreport.ProcessorMetricViews(typeStr, legacyViews)
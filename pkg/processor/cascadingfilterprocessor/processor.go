@@ -16,6 +16,7 @@ package cascadingfilterprocessor
 
 import (
 	"context"
+	"errors"
 	"math"
 	"runtime"
 	"sync"
@@ -46,6 +47,11 @@ type TraceAcceptEvaluator struct {
 	ctx context.Context
 	// probabilisticFilter determines whether `sampling.probability` field must be calculated and added
 	probabilisticFilter bool
+	// probabilisticFallback determines whether this is the fallback policy which is evaluated
+	// only for traces not matched by any other policy
+	probabilisticFallback bool
+	// priority (optional) is stamped on the traces selected by this policy
+	priority *int64
 }
 
 // TraceRejectEvaluator holds checking if trace should be dropped completely before further processing
@@ -83,13 +89,29 @@ type cascadingFilterSpanProcessor struct {
 	currentSecond        int64
 	maxSpansPerSecond    int32
 	spansInCurrentSecond int32
+
+	// probabilisticFallbackRatio is the ratio (0.0-1.0) of traces selected by the probabilistic fallback policy
+	probabilisticFallbackRatio float64
+
+	// decisionCache (optional) keeps final decisions of traces which were removed from memory
+	decisionCache *decisionCache
+
+	// spill (optional) keeps undecided traces which had to be removed from memory
+	spill *traceSpill
+
+	// tenantBudget (optional) caps the spans per second of each tenant
+	tenantBudget *tenantBudget
 }
 
 const (
-	probabilisticFilterPolicyName = "probabilistic_filter"
-	probabilisticRuleVale         = "probabilistic"
-	filteredRuleValue             = "filtered"
-	AttributeSamplingRule         = "sampling.rule"
+	probabilisticFilterPolicyName   = "probabilistic_filter"
+	probabilisticFallbackPolicyName = "probabilistic_fallback"
+	probabilisticRuleVale           = "probabilistic"
+	probabilisticFallbackRuleValue  = "probabilistic_fallback"
+	filteredRuleValue               = "filtered"
+	AttributeSamplingRule           = "sampling.rule"
+	AttributeSamplingFilter         = "sampling.filter"
+	AttributeSamplingPriority       = "sampling.retention_priority"
 
 	AttributeSamplingProbability = "sampling.probability"
 )
@@ -164,6 +186,7 @@ func newCascadingFilterSpanProcessor(logger *zap.Logger, nextConsumer consumer.T
 			Evaluator:           eval,
 			ctx:                 policyCtx,
 			probabilisticFilter: false,
+			priority:            policyCfg.Priority,
 		}
 		if policyCfg.SpansPerSecond > 0 {
 			totalRate += policyCfg.SpansPerSecond
@@ -174,6 +197,35 @@ func newCascadingFilterSpanProcessor(logger *zap.Logger, nextConsumer consumer.T
 		policies = append(policies, policy)
 	}
 
+	// Setup probabilistic fallback - it must be always evaluated last as it selects only traces
+	// which were not matched by any other traceAcceptRules
+
+	probabilisticFallbackRatio := 0.0
+
+	if cfg.ProbabilisticFallbackCfg != nil {
+		fallbackCfg := cfg.ProbabilisticFallbackCfg
+		policyCtx, err := tag.New(ctx, tag.Upsert(tagPolicyKey, probabilisticFallbackPolicyName))
+		if err != nil {
+			return nil, err
+		}
+		eval, err := sampling.NewProbabilisticFallbackFilter(logger, fallbackCfg)
+		if err != nil {
+			return nil, err
+		}
+		policy := &TraceAcceptEvaluator{
+			Name:                  probabilisticFallbackPolicyName,
+			Evaluator:             eval,
+			ctx:                   policyCtx,
+			probabilisticFallback: true,
+		}
+		totalRate += fallbackCfg.SpansPerSecond
+		probabilisticFallbackRatio = float64(fallbackCfg.Percentage) / 100
+		logger.Info("Adding probabilistic fallback rule",
+			zap.Float32("percentage", fallbackCfg.Percentage),
+			zap.Int32("spans_per_second", fallbackCfg.SpansPerSecond))
+		policies = append(policies, policy)
+	}
+
 	// Recalculate the total spans per second rate if needed
 	spansPerSecond := cfg.SpansPerSecond
 	if spansPerSecond == 0 {
@@ -226,6 +278,50 @@ func newCascadingFilterSpanProcessor(logger *zap.Logger, nextConsumer consumer.T
 		logger.Info("No rules set for cascading_filter processor. Processor wil output all incoming spans without filtering.")
 	}
 
+	// Setup decision cache, so late spans are handled consistently with the original decision
+
+	var cache *decisionCache
+	if cfg.DecisionCacheCfg != nil {
+		if cfg.DecisionCacheCfg.TTL <= 0 {
+			return nil, errors.New("decision cache ttl must be a positive duration")
+		}
+		maxSize := cfg.DecisionCacheCfg.MaxSize
+		if maxSize == 0 {
+			maxSize = cfg.NumTraces
+		}
+		logger.Info("Setting decision cache",
+			zap.Duration("ttl", cfg.DecisionCacheCfg.TTL),
+			zap.Uint64("max_size", maxSize))
+		cache = newDecisionCache(cfg.DecisionCacheCfg.TTL, maxSize)
+	}
+
+	// Setup spilling undecided traces to disk
+
+	var spill *traceSpill
+	if cfg.SpillCfg != nil {
+		spill, err = newTraceSpill(cfg.SpillCfg)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Setting spilling undecided traces to disk",
+			zap.String("directory", cfg.SpillCfg.Directory),
+			zap.Int64("max_disk_usage", cfg.SpillCfg.MaxDiskUsage))
+	}
+
+	// Setup partitioning the spans per second budget by tenant
+
+	var budget *tenantBudget
+	if cfg.TenantBudgetCfg != nil {
+		budget, err = newTenantBudget(cfg.TenantBudgetCfg)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Setting spans per second limit per tenant",
+			zap.String("attribute", cfg.TenantBudgetCfg.Attribute),
+			zap.Int32("spans_per_second", cfg.TenantBudgetCfg.SpansPerSecond),
+			zap.Int("tenants", len(cfg.TenantBudgetCfg.Tenants)))
+	}
+
 	// Build the span procesor
 
 	cfsp := &cascadingFilterSpanProcessor{
@@ -238,6 +334,11 @@ func newCascadingFilterSpanProcessor(logger *zap.Logger, nextConsumer consumer.T
 		traceAcceptRules:  policies,
 		traceRejectRules:  dropTraceEvals,
 		filteringEnabled:  len(policies) > 0 || len(dropTraceEvals) > 0,
+
+		probabilisticFallbackRatio: probabilisticFallbackRatio,
+		decisionCache:              cache,
+		spill:                      spill,
+		tenantBudget:               budget,
 	}
 
 	cfsp.policyTicker = &policyTicker{onTick: cfsp.samplingPolicyOnTick}
@@ -258,23 +359,35 @@ type policyMetrics struct {
 	idNotFoundOnMapCount, evaluateErrorCount, decisionSampled, decisionNotSampled int64
 }
 
-func (cfsp *cascadingFilterSpanProcessor) updateRate(currSecond int64, numSpans int32) sampling.Decision {
-	if cfsp.maxSpansPerSecond <= 0 {
-		return sampling.Sampled
-	}
+func (cfsp *cascadingFilterSpanProcessor) updateRate(currSecond int64, trace *sampling.TraceData) sampling.Decision {
+	numSpans := trace.SpanCount
 
-	if cfsp.currentSecond != currSecond {
-		cfsp.currentSecond = currSecond
-		cfsp.spansInCurrentSecond = 0
+	// The trace must fit within the budget of its tenant first
+	tenant, hasTenant := "", false
+	if cfsp.tenantBudget != nil {
+		tenant, hasTenant = cfsp.tenantBudget.tenant(trace)
+		if hasTenant && !cfsp.tenantBudget.fits(currSecond, tenant, numSpans) {
+			return sampling.NotSampled
+		}
 	}
 
-	spansInSecondIfSampled := cfsp.spansInCurrentSecond + numSpans
-	if spansInSecondIfSampled <= cfsp.maxSpansPerSecond {
+	if cfsp.maxSpansPerSecond > 0 {
+		if cfsp.currentSecond != currSecond {
+			cfsp.currentSecond = currSecond
+			cfsp.spansInCurrentSecond = 0
+		}
+
+		spansInSecondIfSampled := cfsp.spansInCurrentSecond + numSpans
+		if spansInSecondIfSampled > cfsp.maxSpansPerSecond {
+			return sampling.NotSampled
+		}
 		cfsp.spansInCurrentSecond = spansInSecondIfSampled
-		return sampling.Sampled
 	}
 
-	return sampling.NotSampled
+	if hasTenant {
+		cfsp.tenantBudget.add(tenant, numSpans)
+	}
+	return sampling.Sampled
 }
 
 func (cfsp *cascadingFilterSpanProcessor) samplingPolicyOnTick() {
@@ -290,14 +403,16 @@ func (cfsp *cascadingFilterSpanProcessor) samplingPolicyOnTick() {
 	totalSpans := int64(0)
 	selectedByProbabilisticFilterSpans := int64(0)
 
+	// Traces which were spilled to disk and are no longer kept in memory
+	restored := make(map[traceKey]*sampling.TraceData)
+
 	// The first run applies decisions to batches, executing each policy separately
 	for _, id := range batch {
-		d, ok := cfsp.idToTrace.Load(traceKey(id.Bytes()))
+		trace, ok := cfsp.loadTrace(id, restored)
 		if !ok {
 			metrics.idNotFoundOnMapCount++
 			continue
 		}
-		trace := d.(*sampling.TraceData)
 		trace.DecisionTime = time.Now()
 
 		var provisionalDecision sampling.Decision
@@ -311,14 +426,14 @@ func (cfsp *cascadingFilterSpanProcessor) samplingPolicyOnTick() {
 		}
 
 		if provisionalDecision == sampling.Sampled {
-			trace.FinalDecision = cfsp.updateRate(currSecond, trace.SpanCount)
+			trace.FinalDecision = cfsp.updateRate(currSecond, trace)
 			if trace.FinalDecision == sampling.Sampled {
 				if trace.SelectedByProbabilisticFilter {
 					selectedByProbabilisticFilterSpans += int64(trace.SpanCount)
 				}
 				err := stats.RecordWithTags(
 					cfsp.ctx,
-					[]tag.Mutator{tag.Insert(tagCascadingFilterDecisionKey, statusSampled)},
+					finalDecisionTags(trace, statusSampled),
 					statCascadingFilterDecision.M(int64(1)),
 				)
 				if err != nil {
@@ -327,7 +442,7 @@ func (cfsp *cascadingFilterSpanProcessor) samplingPolicyOnTick() {
 			} else {
 				err := stats.RecordWithTags(
 					cfsp.ctx,
-					[]tag.Mutator{tag.Insert(tagCascadingFilterDecisionKey, statusExceededKey)},
+					finalDecisionTags(trace, statusExceededKey),
 					statCascadingFilterDecision.M(int64(1)),
 				)
 				if err != nil {
@@ -340,7 +455,7 @@ func (cfsp *cascadingFilterSpanProcessor) samplingPolicyOnTick() {
 			trace.FinalDecision = provisionalDecision
 			err := stats.RecordWithTags(
 				cfsp.ctx,
-				[]tag.Mutator{tag.Insert(tagCascadingFilterDecisionKey, statusNotSampled)},
+				finalDecisionTags(trace, statusNotSampled),
 				statCascadingFilterDecision.M(int64(1)),
 			)
 			if err != nil {
@@ -351,17 +466,22 @@ func (cfsp *cascadingFilterSpanProcessor) samplingPolicyOnTick() {
 
 	// The second run executes the decisions and makes "SecondChance" decisions in the meantime
 	for _, id := range batch {
-		d, ok := cfsp.idToTrace.Load(traceKey(id.Bytes()))
-		if !ok {
+		var trace *sampling.TraceData
+		restoredTrace, isRestored := restored[traceKey(id.Bytes())]
+		if d, ok := cfsp.idToTrace.Load(traceKey(id.Bytes())); ok {
+			trace = d.(*sampling.TraceData)
+			isRestored = false
+		} else if isRestored {
+			trace = restoredTrace
+		} else {
 			continue
 		}
-		trace := d.(*sampling.TraceData)
 		if trace.FinalDecision == sampling.SecondChance {
-			trace.FinalDecision = cfsp.updateRate(currSecond, trace.SpanCount)
+			trace.FinalDecision = cfsp.updateRate(currSecond, trace)
 			if trace.FinalDecision == sampling.Sampled {
 				err := stats.RecordWithTags(
 					cfsp.ctx,
-					[]tag.Mutator{tag.Insert(tagCascadingFilterDecisionKey, statusSecondChanceSampled)},
+					finalDecisionTags(trace, statusSecondChanceSampled),
 					statCascadingFilterDecision.M(int64(1)),
 				)
 				if err != nil {
@@ -370,7 +490,7 @@ func (cfsp *cascadingFilterSpanProcessor) samplingPolicyOnTick() {
 			} else {
 				err := stats.RecordWithTags(
 					cfsp.ctx,
-					[]tag.Mutator{tag.Insert(tagCascadingFilterDecisionKey, statusSecondChanceExceeded)},
+					finalDecisionTags(trace, statusSecondChanceExceeded),
 					statCascadingFilterDecision.M(int64(1)),
 				)
 				if err != nil {
@@ -398,9 +518,17 @@ func (cfsp *cascadingFilterSpanProcessor) samplingPolicyOnTick() {
 
 			if trace.SelectedByProbabilisticFilter {
 				updateProbabilisticRateTag(allSpans, selectedByProbabilisticFilterSpans, totalSpans)
+			} else if trace.SelectedByProbabilisticFallback {
+				updateProbabilisticFallbackTag(allSpans, cfsp.probabilisticFallbackRatio)
 			} else {
 				updateFilteringTag(allSpans)
 			}
+			if trace.SelectedByPolicy != "" {
+				updateSamplingFilterTag(allSpans, trace.SelectedByPolicy)
+			}
+			if trace.SelectedPriority != nil {
+				updateSamplingPriorityTag(allSpans, *trace.SelectedPriority)
+			}
 
 			err := cfsp.nextConsumer.ConsumeTraces(cfsp.ctx, allSpans)
 			if err != nil {
@@ -409,8 +537,15 @@ func (cfsp *cascadingFilterSpanProcessor) samplingPolicyOnTick() {
 		} else {
 			metrics.decisionNotSampled++
 		}
+
+		// Restored trace is not kept in memory, so its decision is cached right away
+		if isRestored && cfsp.decisionCache != nil {
+			cfsp.decisionCache.Put(traceKey(id.Bytes()), trace.FinalDecision, trace.DecisionTime)
+		}
 	}
 
+	cfsp.recordRateLimitSaturation(currSecond)
+
 	stats.Record(cfsp.ctx,
 		statOverallDecisionLatencyus.M(int64(time.Since(startTime)/time.Microsecond)),
 		statDroppedTooEarlyCount.M(metrics.idNotFoundOnMapCount),
@@ -426,6 +561,39 @@ func (cfsp *cascadingFilterSpanProcessor) samplingPolicyOnTick() {
 	)
 }
 
+// finalDecisionTags returns tags of the final decision metric, including the policy which selected the trace (if any)
+func finalDecisionTags(trace *sampling.TraceData, status string) []tag.Mutator {
+	mutators := []tag.Mutator{tag.Insert(tagCascadingFilterDecisionKey, status)}
+	if trace.SelectedByPolicy != "" {
+		mutators = append(mutators, tag.Insert(tagPolicyKey, trace.SelectedByPolicy))
+	}
+	return mutators
+}
+
+// recordRateLimitSaturation records which part of the total and per-policy spans per second budget
+// was used in the current second
+func (cfsp *cascadingFilterSpanProcessor) recordRateLimitSaturation(currSecond int64) {
+	if cfsp.maxSpansPerSecond > 0 {
+		spans := cfsp.spansInCurrentSecond
+		if cfsp.currentSecond != currSecond {
+			spans = 0
+		}
+		stats.Record(cfsp.ctx, statRateLimitSaturation.M(float64(spans)/float64(cfsp.maxSpansPerSecond)))
+	}
+
+	for _, policy := range cfsp.traceAcceptRules {
+		evaluator, ok := policy.Evaluator.(sampling.RateLimitedPolicyEvaluator)
+		if !ok {
+			continue
+		}
+		spans, limit := evaluator.SpansPerSecondUsage(currSecond)
+		if limit <= 0 {
+			continue
+		}
+		stats.Record(policy.ctx, statPolicyRateLimitSaturation.M(float64(spans)/float64(limit)))
+	}
+}
+
 func updateProbabilisticRateTag(traces pdata.Traces, probabilisticSpans int64, allSpans int64) {
 	ratio := float64(probabilisticSpans) / float64(allSpans)
 
@@ -449,6 +617,27 @@ func updateProbabilisticRateTag(traces pdata.Traces, probabilisticSpans int64, a
 	}
 }
 
+func updateProbabilisticFallbackTag(traces pdata.Traces, ratio float64) {
+	rs := traces.ResourceSpans()
+
+	for i := 0; i < rs.Len(); i++ {
+		ils := rs.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ils.Len(); j++ {
+			spans := ils.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				attrs := spans.At(k).Attributes()
+				av, found := attrs.Get(AttributeSamplingProbability)
+				if found && av.Type() == pdata.AttributeValueTypeDouble && !math.IsNaN(av.DoubleVal()) && av.DoubleVal() > 0.0 {
+					av.SetDoubleVal(av.DoubleVal() * ratio)
+				} else {
+					attrs.UpsertDouble(AttributeSamplingProbability, ratio)
+				}
+				attrs.UpsertString(AttributeSamplingRule, probabilisticFallbackRuleValue)
+			}
+		}
+	}
+}
+
 func updateFilteringTag(traces pdata.Traces) {
 	rs := traces.ResourceSpans()
 
@@ -464,6 +653,36 @@ func updateFilteringTag(traces pdata.Traces) {
 	}
 }
 
+func updateSamplingFilterTag(traces pdata.Traces, policyName string) {
+	rs := traces.ResourceSpans()
+
+	for i := 0; i < rs.Len(); i++ {
+		ils := rs.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ils.Len(); j++ {
+			spans := ils.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				attrs := spans.At(k).Attributes()
+				attrs.UpsertString(AttributeSamplingFilter, policyName)
+			}
+		}
+	}
+}
+
+func updateSamplingPriorityTag(traces pdata.Traces, priority int64) {
+	rs := traces.ResourceSpans()
+
+	for i := 0; i < rs.Len(); i++ {
+		ils := rs.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ils.Len(); j++ {
+			spans := ils.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				attrs := spans.At(k).Attributes()
+				attrs.UpsertInt(AttributeSamplingPriority, priority)
+			}
+		}
+	}
+}
+
 func (cfsp *cascadingFilterSpanProcessor) shouldBeDropped(id pdata.TraceID, trace *sampling.TraceData) bool {
 	for _, dropRule := range cfsp.traceRejectRules {
 		if dropRule.Evaluator.ShouldDrop(id, trace) {
@@ -483,6 +702,11 @@ func (cfsp *cascadingFilterSpanProcessor) makeProvisionalDecision(id pdata.Trace
 	provisionalDecision := sampling.Unspecified
 
 	for i, policy := range cfsp.traceAcceptRules {
+		// The fallback policy considers only traces which were not matched by any other policy
+		if policy.probabilisticFallback && provisionalDecision == sampling.SecondChance {
+			continue
+		}
+
 		policyEvaluateStartTime := time.Now()
 		decision := policy.Evaluator.Evaluate(id, trace)
 		stats.Record(
@@ -496,10 +720,15 @@ func (cfsp *cascadingFilterSpanProcessor) makeProvisionalDecision(id pdata.Trace
 			// any single policy that decides to sample will cause the decision to be sampled
 			// the nextConsumer will get the context from the first matching policy
 			provisionalDecision = sampling.Sampled
+			trace.SelectedByPolicy = policy.Name
+			trace.SelectedPriority = policy.priority
 
 			if policy.probabilisticFilter {
 				trace.SelectedByProbabilisticFilter = true
 			}
+			if policy.probabilisticFallback {
+				trace.SelectedByProbabilisticFallback = true
+			}
 
 			err := stats.RecordWithTags(
 				policy.ctx,
@@ -528,6 +757,11 @@ func (cfsp *cascadingFilterSpanProcessor) makeProvisionalDecision(id pdata.Trace
 			if provisionalDecision != sampling.Sampled {
 				provisionalDecision = sampling.SecondChance
 			}
+			// The first policy which emitted second chance is considered as the one selecting the trace
+			if trace.SelectedByPolicy == "" {
+				trace.SelectedByPolicy = policy.Name
+				trace.SelectedPriority = policy.priority
+			}
 
 			err := stats.RecordWithTags(
 				policy.ctx,
@@ -549,10 +783,7 @@ func (cfsp *cascadingFilterSpanProcessor) ConsumeTraces(ctx context.Context, td
 		return cfsp.nextConsumer.ConsumeTraces(ctx, td)
 	}
 
-	cfsp.start.Do(func() {
-		cfsp.logger.Info("First trace data arrived, starting cascading_filter timers")
-		cfsp.policyTicker.Start(1 * time.Second)
-	})
+	cfsp.startTimers("First trace data arrived, starting cascading_filter timers")
 	resourceSpans := td.ResourceSpans()
 	for i := 0; i < resourceSpans.Len(); i++ {
 		resourceSpan := resourceSpans.At(i)
@@ -591,6 +822,15 @@ func (cfsp *cascadingFilterSpanProcessor) processTraces(ctx context.Context, res
 		for i := 0; i < lenPolicies; i++ {
 			initialDecisions[i] = sampling.Pending
 		}
+
+		// The trace was already removed from memory, apply the decision taken earlier
+		if cfsp.decisionCache != nil {
+			if entry, ok := cfsp.decisionCache.Get(id); ok {
+				cfsp.applyCachedDecision(ctx, resourceSpans, spans, entry)
+				continue
+			}
+		}
+
 		initialTraceData := &sampling.TraceData{
 			Decisions:   initialDecisions,
 			ArrivalTime: time.Now(),
@@ -658,6 +898,30 @@ func (cfsp *cascadingFilterSpanProcessor) processTraces(ctx context.Context, res
 	stats.Record(cfsp.ctx, statNewTraceIDReceivedCount.M(newTraceIDs))
 }
 
+func (cfsp *cascadingFilterSpanProcessor) applyCachedDecision(ctx context.Context, resourceSpans pdata.ResourceSpans, spans []*pdata.Span, entry decisionCacheEntry) {
+	status := statusNotSampled
+	if entry.decision == sampling.Sampled {
+		status = statusSampled
+		traceTd := prepareTraceBatch(resourceSpans, spans)
+		if err := cfsp.nextConsumer.ConsumeTraces(ctx, traceTd); err != nil {
+			cfsp.logger.Warn("Error sending late arrived spans to destination",
+				zap.Error(err))
+		}
+	} else if entry.decision == sampling.Dropped {
+		status = statusDropped
+	}
+
+	stats.Record(cfsp.ctx, statLateSpanArrivalAfterDecision.M(int64(time.Since(entry.decisionTime)/time.Second)))
+	err := stats.RecordWithTags(
+		cfsp.ctx,
+		[]tag.Mutator{tag.Insert(tagCascadingFilterDecisionKey, status)},
+		statDecisionCacheHitCount.M(int64(len(spans))),
+	)
+	if err != nil {
+		cfsp.logger.Error("Error recording decision cache hit", zap.Error(err))
+	}
+}
+
 // func (cfsp *cascadingFilterSpanProcessor) GetCapabilities() component.ProcessorCapabilities {
 // 	return component.ProcessorCapabilities{MutatesConsumedData: false}
 // }
@@ -666,13 +930,48 @@ func (cfsp *cascadingFilterSpanProcessor) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
 
+func (cfsp *cascadingFilterSpanProcessor) startTimers(reason string) {
+	cfsp.start.Do(func() {
+		cfsp.logger.Info(reason)
+		cfsp.policyTicker.Start(1 * time.Second)
+	})
+}
+
 // Start is invoked during service startup.
 func (cfsp *cascadingFilterSpanProcessor) Start(context.Context, component.Host) error {
+	if cfsp.spill == nil {
+		return nil
+	}
+
+	// Traces spilled before the restart are scheduled for evaluation as if they just arrived
+	ids := cfsp.spill.ids()
+	if len(ids) == 0 {
+		return nil
+	}
+	for _, id := range ids {
+		cfsp.decisionBatcher.AddToCurrentBatch(pdata.NewTraceID(id))
+	}
+	cfsp.logger.Info("Recovered undecided traces spilled to disk", zap.Int("traces", len(ids)))
+	cfsp.startTimers("Spilled traces recovered, starting cascading_filter timers")
 	return nil
 }
 
 // Shutdown is invoked during service shutdown.
 func (cfsp *cascadingFilterSpanProcessor) Shutdown(context.Context) error {
+	if cfsp.spill == nil {
+		return nil
+	}
+
+	// Undecided traces are spilled to disk, so they are recovered on startup
+	spilled := 0
+	cfsp.idToTrace.Range(func(key, value interface{}) bool {
+		trace := value.(*sampling.TraceData)
+		if isUndecided(trace.FinalDecision) && cfsp.spillTrace(key.(traceKey), trace) {
+			spilled++
+		}
+		return true
+	})
+	cfsp.logger.Info("Spilled undecided traces to disk", zap.Int("traces", spilled))
 	return nil
 }
 
@@ -690,6 +989,94 @@ func (cfsp *cascadingFilterSpanProcessor) dropTrace(traceID traceKey, deletionTi
 	}
 
 	stats.Record(cfsp.ctx, statTraceRemovalAgeSec.M(int64(deletionTime.Sub(trace.ArrivalTime)/time.Second)))
+
+	// Trace is removed from memory before the decision was taken, keep it on disk until its evaluation
+	if cfsp.spill != nil && isUndecided(trace.FinalDecision) {
+		cfsp.spillTrace(traceID, trace)
+	}
+
+	if cfsp.decisionCache != nil {
+		switch trace.FinalDecision {
+		case sampling.Sampled, sampling.NotSampled, sampling.Dropped:
+			cfsp.decisionCache.Put(traceID, trace.FinalDecision, trace.DecisionTime)
+		}
+	}
+}
+
+// spillTrace moves spans of the trace to disk and returns true if it succeeded
+func (cfsp *cascadingFilterSpanProcessor) spillTrace(id traceKey, trace *sampling.TraceData) bool {
+	trace.Lock()
+	batches := trace.ReceivedBatches
+	trace.ReceivedBatches = nil
+	trace.Unlock()
+
+	if len(batches) == 0 {
+		return false
+	}
+
+	if err := cfsp.spill.store(id, batches); err != nil {
+		cfsp.logger.Warn("Failed to spill undecided trace to disk", zap.Error(err))
+		stats.Record(cfsp.ctx, statSpillRejectedCount.M(int64(1)))
+
+		// Keep the spans in memory, ahead of the ones received in the meantime
+		trace.Lock()
+		trace.ReceivedBatches = append(batches, trace.ReceivedBatches...)
+		trace.Unlock()
+		return false
+	}
+	stats.Record(cfsp.ctx, statTracesSpilledCount.M(int64(1)))
+	return true
+}
+
+// loadTrace returns the trace kept in memory merged with its spans spilled to disk (if any).
+// Traces which are no longer kept in memory are restored from disk and added to restored.
+func (cfsp *cascadingFilterSpanProcessor) loadTrace(id pdata.TraceID, restored map[traceKey]*sampling.TraceData) (*sampling.TraceData, bool) {
+	key := traceKey(id.Bytes())
+	var trace *sampling.TraceData
+	if d, ok := cfsp.idToTrace.Load(key); ok {
+		trace = d.(*sampling.TraceData)
+	}
+
+	if cfsp.spill == nil {
+		return trace, trace != nil
+	}
+
+	td, ok, err := cfsp.spill.take(key)
+	if err != nil {
+		// The spilled spans are lost, as they are removed from the spill index anyway
+		cfsp.logger.Warn("Failed to restore spilled trace", zap.Error(err))
+		stats.Record(cfsp.ctx, statSpillRestoreErrorCount.M(int64(1)))
+	}
+	if !ok {
+		return trace, trace != nil
+	}
+	stats.Record(cfsp.ctx, statTracesRestoredCount.M(int64(1)))
+
+	spanCount := int32(td.SpanCount())
+	if trace == nil {
+		decisions := make([]sampling.Decision, len(cfsp.traceAcceptRules))
+		for i := range decisions {
+			decisions[i] = sampling.Pending
+		}
+		trace = &sampling.TraceData{
+			Decisions:       decisions,
+			ArrivalTime:     time.Now(),
+			SpanCount:       spanCount,
+			ReceivedBatches: []pdata.Traces{td},
+		}
+		restored[key] = trace
+		return trace, true
+	}
+
+	trace.Lock()
+	trace.ReceivedBatches = append([]pdata.Traces{td}, trace.ReceivedBatches...)
+	trace.Unlock()
+	atomic.AddInt32(&trace.SpanCount, spanCount)
+	return trace, true
+}
+
+func isUndecided(decision sampling.Decision) bool {
+	return decision == sampling.Unspecified || decision == sampling.Pending
 }
 
 func prepareTraceBatch(rss pdata.ResourceSpans, spans []*pdata.Span) pdata.Traces {
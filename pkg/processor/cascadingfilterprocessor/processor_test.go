@@ -549,6 +549,206 @@ func TestMultipleBatchesAreCombinedIntoOne(t *testing.T) {
 }
 
 //nolint:unused
+func TestLateSpansUseCachedDecision(t *testing.T) {
+	tests := []struct {
+		name              string
+		decision          sampling.Decision
+		expectedLateSpans int
+	}{
+		{
+			name:              "sampled",
+			decision:          sampling.Sampled,
+			expectedLateSpans: 1,
+		},
+		{
+			name:              "not sampled",
+			decision:          sampling.NotSampled,
+			expectedLateSpans: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const maxSize = 2
+			msp := new(consumertest.TracesSink)
+			mpe := &mockPolicyEvaluator{NextDecision: tt.decision}
+			tsp := &cascadingFilterSpanProcessor{
+				ctx:               context.Background(),
+				nextConsumer:      msp,
+				maxNumTraces:      maxSize,
+				logger:            zap.NewNop(),
+				decisionBatcher:   newSyncIDBatcher(1),
+				traceAcceptRules:  []*TraceAcceptEvaluator{{Name: "mock-policy", Evaluator: mpe, ctx: context.TODO()}},
+				deleteChan:        make(chan traceKey, maxSize),
+				policyTicker:      &manualTTicker{},
+				maxSpansPerSecond: 10000,
+				filteringEnabled:  true,
+				decisionCache:     newDecisionCache(time.Minute, maxSize),
+			}
+
+			lateTraceID := bigendianconverter.UInt64ToTraceID(1, 1)
+			require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(lateTraceID)))
+			tsp.samplingPolicyOnTick()
+			tsp.samplingPolicyOnTick()
+			require.Equal(t, 1, mpe.EvaluationCount)
+			spansAfterDecision := msp.SpanCount()
+
+			// New traces push the decided one out of memory
+			for i := 2; i <= maxSize+1; i++ {
+				traceID := bigendianconverter.UInt64ToTraceID(1, uint64(i))
+				require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+			}
+			_, ok := tsp.idToTrace.Load(traceKey(lateTraceID.Bytes()))
+			require.False(t, ok, "trace should have been removed from memory")
+			require.Equal(t, 1, tsp.decisionCache.Len())
+
+			// Late span is handled according to the cached decision and is not evaluated again
+			require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(lateTraceID)))
+			assert.Equal(t, spansAfterDecision+tt.expectedLateSpans, msp.SpanCount())
+			_, ok = tsp.idToTrace.Load(traceKey(lateTraceID.Bytes()))
+			assert.False(t, ok, "late span should not be stored in memory")
+
+			tsp.samplingPolicyOnTick()
+			tsp.samplingPolicyOnTick()
+			assert.Equal(t, 1+maxSize, mpe.EvaluationCount)
+		})
+	}
+}
+
+func TestDecisionCacheInvalidConfig(t *testing.T) {
+	cfg := config.Config{
+		DecisionWait:            defaultTestDecisionWait,
+		NumTraces:               100,
+		ExpectedNewTracesPerSec: 100,
+		DecisionCacheCfg:        &config.DecisionCacheCfg{},
+	}
+	_, err := newTraceProcessor(zap.NewNop(), consumertest.NewNop(), cfg)
+	require.EqualError(t, err, "decision cache ttl must be a positive duration")
+}
+
+func newSpillTestProcessor(t *testing.T, dir string, maxSize uint64, msp *consumertest.TracesSink, mpe *mockPolicyEvaluator) *cascadingFilterSpanProcessor {
+	spill, err := newTraceSpill(&config.SpillCfg{Directory: dir, MaxDiskUsage: 1024 * 1024})
+	require.NoError(t, err)
+
+	return &cascadingFilterSpanProcessor{
+		ctx:               context.Background(),
+		nextConsumer:      msp,
+		maxNumTraces:      maxSize,
+		logger:            zap.NewNop(),
+		decisionBatcher:   newSyncIDBatcher(1),
+		traceAcceptRules:  []*TraceAcceptEvaluator{{Name: "mock-policy", Evaluator: mpe, ctx: context.TODO()}},
+		deleteChan:        make(chan traceKey, maxSize),
+		policyTicker:      &manualTTicker{},
+		maxSpansPerSecond: 10000,
+		filteringEnabled:  true,
+		spill:             spill,
+	}
+}
+
+func TestUndecidedTraceSpilledOnEviction(t *testing.T) {
+	msp := new(consumertest.TracesSink)
+	mpe := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	tsp := newSpillTestProcessor(t, t.TempDir(), 1, msp, mpe)
+
+	evictedTraceID := bigendianconverter.UInt64ToTraceID(1, 1)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(evictedTraceID)))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(bigendianconverter.UInt64ToTraceID(1, 2))))
+
+	_, ok := tsp.idToTrace.Load(traceKey(evictedTraceID.Bytes()))
+	require.False(t, ok, "trace should have been removed from memory")
+	require.Len(t, tsp.spill.ids(), 1)
+
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+
+	assert.Equal(t, 2, mpe.EvaluationCount)
+	assert.Equal(t, 2, msp.SpanCount(), "spilled trace should have been evaluated and sampled")
+	assert.Empty(t, tsp.spill.ids())
+}
+
+func TestSpillRejectedTraceKeptInMemory(t *testing.T) {
+	msp := new(consumertest.TracesSink)
+	mpe := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	tsp := newSpillTestProcessor(t, t.TempDir(), 10, msp, mpe)
+	tsp.spill.maxDiskUsage = 1
+
+	traceID := bigendianconverter.UInt64ToTraceID(1, 1)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+	require.NoError(t, tsp.Shutdown(context.Background()))
+	assert.Empty(t, tsp.spill.ids())
+
+	d, ok := tsp.idToTrace.Load(traceKey(traceID.Bytes()))
+	require.True(t, ok)
+	trace := d.(*sampling.TraceData)
+	require.Len(t, trace.ReceivedBatches, 1)
+	assert.Equal(t, 1, trace.ReceivedBatches[0].SpanCount(), "spans should have been kept when spilling failed")
+}
+
+func TestUndecidedTracesRecoveredAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	traceID := bigendianconverter.UInt64ToTraceID(1, 1)
+
+	msp := new(consumertest.TracesSink)
+	mpe := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	tsp := newSpillTestProcessor(t, dir, 10, msp, mpe)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+	require.NoError(t, tsp.Shutdown(context.Background()))
+	assert.Equal(t, 0, msp.SpanCount())
+
+	restartedMtt := &manualTTicker{}
+	restarted := newSpillTestProcessor(t, dir, 10, msp, mpe)
+	restarted.policyTicker = restartedMtt
+	require.NoError(t, restarted.Start(context.Background(), nil))
+	require.True(t, restartedMtt.Started, "Time ticker was expected to have started")
+
+	restarted.samplingPolicyOnTick()
+	restarted.samplingPolicyOnTick()
+
+	assert.Equal(t, 1, msp.SpanCount(), "recovered trace should have been evaluated and sampled")
+	assert.Empty(t, restarted.spill.ids())
+}
+
+func TestSampledTraceHasSamplingFilterAttribute(t *testing.T) {
+	priority := int64(5)
+	msp := new(consumertest.TracesSink)
+	tsp := &cascadingFilterSpanProcessor{
+		ctx:             context.Background(),
+		nextConsumer:    msp,
+		maxNumTraces:    10,
+		logger:          zap.NewNop(),
+		decisionBatcher: newSyncIDBatcher(1),
+		traceAcceptRules: []*TraceAcceptEvaluator{
+			{Name: "not-matching-policy", Evaluator: &mockPolicyEvaluator{NextDecision: sampling.NotSampled}, ctx: context.TODO()},
+			{Name: "matching-policy", Evaluator: &mockPolicyEvaluator{NextDecision: sampling.Sampled}, ctx: context.TODO(), priority: &priority},
+		},
+		deleteChan:        make(chan traceKey, 10),
+		policyTicker:      &manualTTicker{},
+		maxSpansPerSecond: 10000,
+		filteringEnabled:  true,
+	}
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(bigendianconverter.UInt64ToTraceID(1, 1))))
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+
+	require.Len(t, msp.AllTraces(), 1)
+	span := msp.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	filter, ok := span.Attributes().Get(AttributeSamplingFilter)
+	require.True(t, ok)
+	assert.Equal(t, "matching-policy", filter.StringVal())
+	retentionPriority, ok := span.Attributes().Get(AttributeSamplingPriority)
+	require.True(t, ok)
+	assert.Equal(t, priority, retentionPriority.IntVal())
+	rule, ok := span.Attributes().Get(AttributeSamplingRule)
+	require.True(t, ok)
+	assert.Equal(t, filteredRuleValue, rule.StringVal())
+}
+
+func TestFinalDecisionTags(t *testing.T) {
+	assert.Len(t, finalDecisionTags(&sampling.TraceData{}, statusNotSampled), 1)
+	assert.Len(t, finalDecisionTags(&sampling.TraceData{SelectedByPolicy: "policy"}, statusSampled), 2)
+}
+
 func collectSpanIds(trace *pdata.Traces) []pdata.SpanID {
 	spanIDs := make([]pdata.SpanID, 0)
 
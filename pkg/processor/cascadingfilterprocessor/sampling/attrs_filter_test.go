@@ -20,8 +20,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.uber.org/zap"
+
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/config"
 )
 
 func newAttrsFilter(filters []attributeFilter) policyEvaluator {
@@ -52,11 +56,17 @@ func TestAttributesFilter(t *testing.T) {
 	filterBarPattern := newAttrFilter("bar", []string{"baz.*"}, nil)
 	filterCooNothing := newAttrFilter("coo", nil, nil)
 	filterFooRange := newAttrFilter("foo", nil, []attributeRange{{minValue: 100, maxValue: 150}})
+	filterFooComparisons := newAttrFilter("foo", nil, nil)
+	filterFooComparisons.comparisons = []attributeComparison{
+		{operator: operatorGreaterOrEqual, value: 120},
+		{operator: operatorLess, value: 200},
+	}
 	filterFooRangesOrPatterns := newAttrFilter("foo", []string{"foo.*", "claz.*"}, []attributeRange{{minValue: 100, maxValue: 150}, {minValue: 200, maxValue: 250}})
 
 	composite := newAttrsFilter([]attributeFilter{filterFooRangesOrPatterns, filterBarPattern})
 	bar := newAttrsFilter([]attributeFilter{filterBarPattern})
 	fooRange := newAttrsFilter([]attributeFilter{filterFooRange})
+	fooComparisons := newAttrsFilter([]attributeFilter{filterFooComparisons})
 	fooPattern := newAttrsFilter([]attributeFilter{filterFooPattern})
 	coo := newAttrsFilter([]attributeFilter{filterCooNothing})
 
@@ -66,6 +76,12 @@ func TestAttributesFilter(t *testing.T) {
 	fooNumTraces, fooNumAttrs := newTrace()
 	fooNumAttrs.InsertInt("foo", 130)
 
+	fooDoubleTraces, fooDoubleAttrs := newTrace()
+	fooDoubleAttrs.InsertDouble("foo", 199.5)
+
+	fooHighNumTraces, fooHighNumAttrs := newTrace()
+	fooHighNumAttrs.InsertInt("foo", 500)
+
 	fooBarTraces, fooBarAttrs := newTrace()
 	fooBarAttrs.InsertString("foo", "foobar")
 	fooBarAttrs.InsertString("bar", "bazbar")
@@ -94,6 +110,12 @@ func TestAttributesFilter(t *testing.T) {
 			Match:     []*TraceData{fooNumTraces},
 			DontMatch: []*TraceData{fooTraces, fooBarTraces, booTraces, cooTraces},
 		},
+		{
+			Desc:      "numeric comparisons",
+			Evaluator: fooComparisons,
+			Match:     []*TraceData{fooNumTraces, fooDoubleTraces},
+			DontMatch: []*TraceData{fooHighNumTraces, fooTraces, fooBarTraces, booTraces, cooTraces},
+		},
 		{
 			Desc:      "simple pattern",
 			Evaluator: bar,
@@ -132,6 +154,25 @@ func TestAttributesFilter(t *testing.T) {
 	}
 }
 
+func TestCreateAttributeFilterComparisons(t *testing.T) {
+	filter, err := createAttributeFilter(config.AttributeCfg{
+		Key: "http.status_code",
+		Comparisons: []config.AttributeComparison{
+			{Operator: ">=", Value: 500},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []attributeComparison{{operator: operatorGreaterOrEqual, value: 500}}, filter.comparisons)
+
+	_, err = createAttributeFilter(config.AttributeCfg{
+		Key: "http.status_code",
+		Comparisons: []config.AttributeComparison{
+			{Operator: "=>", Value: 500},
+		},
+	})
+	assert.EqualError(t, err, `unsupported comparison operator "=>" for attribute "http.status_code"`)
+}
+
 func newTrace() (*TraceData, pdata.AttributeMap) {
 	endTs := time.Now().UnixNano()
 	startTs := endTs - 100000
@@ -30,6 +30,13 @@ type TraceData struct {
 	FinalDecision Decision
 	// SelectedByProbabilisticFilter determines if this trace was selected by probabilistic filter
 	SelectedByProbabilisticFilter bool
+	// SelectedByProbabilisticFallback determines if this trace was selected by probabilistic fallback policy
+	SelectedByProbabilisticFallback bool
+	// SelectedByPolicy is the name of the trace accept policy which selected this trace
+	SelectedByPolicy string
+	// SelectedPriority is the retention priority of the policy which selected this trace,
+	// nil if the policy has no priority configured
+	SelectedPriority *int64
 	// Arrival time the first span for the trace was received.
 	ArrivalTime time.Time
 	// Decisiontime time when sampling decision was taken.
@@ -69,6 +76,13 @@ type PolicyEvaluator interface {
 	Evaluate(traceID pdata.TraceID, trace *TraceData) Decision
 }
 
+// RateLimitedPolicyEvaluator is implemented by the policy evaluators which have their own spans per second budget
+type RateLimitedPolicyEvaluator interface {
+	// SpansPerSecondUsage returns the number of spans selected in the given second and the spans per second budget.
+	// Negative budget means that the evaluator is not limited.
+	SpansPerSecondUsage(currSecond int64) (int32, int32)
+}
+
 // DropTraceEvaluator implements a cascading policy evaluator,
 // which checks if trace should be dropped completely before making any other operations
 type DropTraceEvaluator interface {
@@ -16,6 +16,7 @@ package sampling
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"time"
 
@@ -40,11 +41,28 @@ type attributeRange struct {
 	maxValue int64
 }
 
+type comparisonOperator string
+
+const (
+	operatorGreater        comparisonOperator = ">"
+	operatorGreaterOrEqual comparisonOperator = ">="
+	operatorLess           comparisonOperator = "<"
+	operatorLessOrEqual    comparisonOperator = "<="
+	operatorEqual          comparisonOperator = "=="
+	operatorNotEqual       comparisonOperator = "!="
+)
+
+type attributeComparison struct {
+	operator comparisonOperator
+	value    float64
+}
+
 type attributeFilter struct {
-	key      string
-	values   map[string]struct{}
-	patterns []*regexp.Regexp
-	ranges   []attributeRange
+	key         string
+	values      map[string]struct{}
+	patterns    []*regexp.Regexp
+	ranges      []attributeRange
+	comparisons []attributeComparison
 }
 
 type policyEvaluator struct {
@@ -54,9 +72,12 @@ type policyEvaluator struct {
 
 	operationRe       *regexp.Regexp
 	minDuration       *time.Duration
+	minSpanDuration   *time.Duration
 	minNumberOfSpans  *int
 	minNumberOfErrors *int
 
+	traceAggregates *traceAggregatesFilter
+
 	currentSecond        int64
 	maxSpansPerSecond    int32
 	spansInCurrentSecond int32
@@ -67,6 +88,7 @@ type policyEvaluator struct {
 }
 
 var _ PolicyEvaluator = (*policyEvaluator)(nil)
+var _ RateLimitedPolicyEvaluator = (*policyEvaluator)(nil)
 
 func createNumericAttributeFilter(cfg *config.NumericAttributeCfg) *numericAttributeFilter {
 	if cfg == nil {
@@ -132,11 +154,25 @@ func createAttributeFilter(cfg config.AttributeCfg) (*attributeFilter, error) {
 		})
 	}
 
+	var comparisons []attributeComparison
+	for _, c := range cfg.Comparisons {
+		switch op := comparisonOperator(c.Operator); op {
+		case operatorGreater, operatorGreaterOrEqual, operatorLess, operatorLessOrEqual, operatorEqual, operatorNotEqual:
+			comparisons = append(comparisons, attributeComparison{
+				operator: op,
+				value:    c.Value,
+			})
+		default:
+			return nil, fmt.Errorf("unsupported comparison operator %q for attribute %q", c.Operator, cfg.Key)
+		}
+	}
+
 	return &attributeFilter{
-		key:      cfg.Key,
-		values:   valuesMap,
-		patterns: patterns,
-		ranges:   ranges,
+		key:         cfg.Key,
+		values:      valuesMap,
+		patterns:    patterns,
+		ranges:      ranges,
+		comparisons: comparisons,
 	}, nil
 }
 
@@ -192,18 +228,29 @@ func NewFilter(logger *zap.Logger, cfg *config.TraceAcceptCfg) (PolicyEvaluator,
 		return nil, errors.New("minimum span duration must be a non-negative number")
 	}
 
+	if cfg.PropertiesCfg.MinSpanDuration != nil && *cfg.PropertiesCfg.MinSpanDuration < 0*time.Second {
+		return nil, errors.New("minimum single span duration must be a non-negative number")
+	}
+
 	if cfg.PropertiesCfg.MinNumberOfSpans != nil && *cfg.PropertiesCfg.MinNumberOfSpans < 1 {
 		return nil, errors.New("minimum number of spans must be a positive number")
 	}
 
+	traceAggregatesFilter, err := createTraceAggregatesFilter(cfg.TraceAggregatesCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &policyEvaluator{
 		stringAttr:           stringAttrFilter,
 		numericAttr:          numericAttrFilter,
 		attrs:                attrsFilter,
 		operationRe:          operationRe,
 		minDuration:          cfg.PropertiesCfg.MinDuration,
+		minSpanDuration:      cfg.PropertiesCfg.MinSpanDuration,
 		minNumberOfSpans:     cfg.PropertiesCfg.MinNumberOfSpans,
 		minNumberOfErrors:    cfg.PropertiesCfg.MinNumberOfErrors,
+		traceAggregates:      traceAggregatesFilter,
 		logger:               logger,
 		currentSecond:        0,
 		spansInCurrentSecond: 0,
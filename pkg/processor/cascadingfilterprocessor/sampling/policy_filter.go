@@ -78,8 +78,14 @@ func checkAttributeFilterMatchedAndFound(attrs pdata.AttributeMap, filter attrib
 			}
 		}
 
+		if len(filter.comparisons) > 0 {
+			if value, ok := numericValue(v); ok && checkComparisons(value, filter.comparisons) {
+				return true, true
+			}
+		}
+
 		// This is special condition which just checks if any filters were defined or not; For latter, pass if key found
-		if len(filter.ranges) == 0 && len(filter.values) == 0 && len(filter.patterns) == 0 {
+		if len(filter.ranges) == 0 && len(filter.values) == 0 && len(filter.patterns) == 0 && len(filter.comparisons) == 0 {
 			return true, true
 		}
 
@@ -90,6 +96,42 @@ func checkAttributeFilterMatchedAndFound(attrs pdata.AttributeMap, filter attrib
 	return false, false
 }
 
+func numericValue(v pdata.AttributeValue) (float64, bool) {
+	switch v.Type() {
+	case pdata.AttributeValueTypeDouble:
+		return v.DoubleVal(), true
+	case pdata.AttributeValueTypeInt:
+		return float64(v.IntVal()), true
+	default:
+		return 0, false
+	}
+}
+
+// checkComparisons returns true if all the comparisons are met by the value
+func checkComparisons(value float64, comparisons []attributeComparison) bool {
+	for _, c := range comparisons {
+		var met bool
+		switch c.operator {
+		case operatorGreater:
+			met = value > c.value
+		case operatorGreaterOrEqual:
+			met = value >= c.value
+		case operatorLess:
+			met = value < c.value
+		case operatorLessOrEqual:
+			met = value <= c.value
+		case operatorEqual:
+			met = value == c.value
+		case operatorNotEqual:
+			met = value != c.value
+		}
+		if !met {
+			return false
+		}
+	}
+	return true
+}
+
 func checkIfNumericAttrFound(attrs pdata.AttributeMap, filter *numericAttributeFilter) bool {
 	if v, ok := attrs.Get(filter.key); ok {
 		value := v.IntVal()
@@ -132,18 +174,28 @@ func (pe *policyEvaluator) evaluateRules(_ pdata.TraceID, trace *TraceData) Deci
 	matchingStringAttrFound := false
 	matchingNumericAttrFound := false
 	matchingAttrsFound := false
+	matchingSpanDurationFound := false
 
 	spanCount := 0
 	errorCount := 0
 	minStartTime := int64(0)
 	maxEndTime := int64(0)
 
+	var aggregates *traceAggregates
+	if pe.traceAggregates != nil {
+		aggregates = newTraceAggregates()
+	}
+
 	for _, batch := range batches {
 		rs := batch.ResourceSpans()
 
 		for i := 0; i < rs.Len(); i++ {
 			res := rs.At(i).Resource()
 
+			if aggregates != nil {
+				aggregates.addResource(res)
+			}
+
 			if !matchingStringAttrFound && pe.stringAttr != nil {
 				matchingStringAttrFound = checkIfStringAttrFound(res.Attributes(), pe.stringAttr)
 			}
@@ -159,6 +211,10 @@ func (pe *policyEvaluator) evaluateRules(_ pdata.TraceID, trace *TraceData) Deci
 				for k := 0; k < spans.Len(); k++ {
 					span := spans.At(k)
 
+					if aggregates != nil {
+						aggregates.addSpan(span)
+					}
+
 					if !matchingAttrsFound && len(pe.attrs) > 0 {
 						matchingAttrsFound = checkIfAttrsMatched(res.Attributes(), span.Attributes(), pe.attrs)
 					}
@@ -194,6 +250,13 @@ func (pe *policyEvaluator) evaluateRules(_ pdata.TraceID, trace *TraceData) Deci
 						}
 					}
 
+					if pe.minSpanDuration != nil && !matchingSpanDurationFound {
+						if span.EndTimestamp() > span.StartTimestamp() &&
+							tsToMicros(span.EndTimestamp()-span.StartTimestamp()) >= pe.minSpanDuration.Microseconds() {
+							matchingSpanDurationFound = true
+						}
+					}
+
 					if span.Status().Code() == pdata.StatusCodeError {
 						errorCount++
 					}
@@ -203,15 +266,17 @@ func (pe *policyEvaluator) evaluateRules(_ pdata.TraceID, trace *TraceData) Deci
 	}
 
 	conditionMet := struct {
-		operationName, minDuration, minSpanCount, stringAttr, numericAttr, attrs, minErrorCount bool
+		operationName, minDuration, minSpanDuration, minSpanCount, stringAttr, numericAttr, attrs, minErrorCount, traceAggregates bool
 	}{
-		operationName: true,
-		minDuration:   true,
-		minSpanCount:  true,
-		stringAttr:    true,
-		numericAttr:   true,
-		attrs:         true,
-		minErrorCount: true,
+		operationName:   true,
+		minDuration:     true,
+		minSpanDuration: true,
+		minSpanCount:    true,
+		stringAttr:      true,
+		numericAttr:     true,
+		attrs:           true,
+		minErrorCount:   true,
+		traceAggregates: true,
 	}
 
 	if pe.operationRe != nil {
@@ -223,6 +288,9 @@ func (pe *policyEvaluator) evaluateRules(_ pdata.TraceID, trace *TraceData) Deci
 	if pe.minDuration != nil {
 		conditionMet.minDuration = maxEndTime > minStartTime && maxEndTime-minStartTime >= pe.minDuration.Microseconds()
 	}
+	if pe.minSpanDuration != nil {
+		conditionMet.minSpanDuration = matchingSpanDurationFound
+	}
 	if pe.numericAttr != nil {
 		conditionMet.numericAttr = matchingNumericAttrFound
 	}
@@ -235,14 +303,19 @@ func (pe *policyEvaluator) evaluateRules(_ pdata.TraceID, trace *TraceData) Deci
 	if pe.minNumberOfErrors != nil {
 		conditionMet.minErrorCount = errorCount >= *pe.minNumberOfErrors
 	}
+	if aggregates != nil {
+		conditionMet.traceAggregates = pe.traceAggregates.matches(aggregates)
+	}
 
 	if conditionMet.minSpanCount &&
 		conditionMet.minDuration &&
+		conditionMet.minSpanDuration &&
 		conditionMet.operationName &&
 		conditionMet.numericAttr &&
 		conditionMet.stringAttr &&
 		conditionMet.attrs &&
-		conditionMet.minErrorCount {
+		conditionMet.minErrorCount &&
+		conditionMet.traceAggregates {
 		if pe.invertMatch {
 			return NotSampled
 		}
@@ -290,6 +363,14 @@ func (pe *policyEvaluator) updateRate(currSecond int64, numSpans int32) Decision
 	return NotSampled
 }
 
+// SpansPerSecondUsage returns the number of spans selected in the given second and the spans per second budget
+func (pe *policyEvaluator) SpansPerSecondUsage(currSecond int64) (int32, int32) {
+	if pe.currentSecond != currSecond {
+		return 0, pe.maxSpansPerSecond
+	}
+	return pe.spansInCurrentSecond, pe.maxSpansPerSecond
+}
+
 // Evaluate looks at the trace data and returns a corresponding SamplingDecision. Also takes into account
 // the usage of sampling rate budget
 func (pe *policyEvaluator) Evaluate(traceID pdata.TraceID, trace *TraceData) Decision {
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/config"
+)
+
+// traceIDHashBits is the number of trace ID bits used for the probabilistic decision.
+// It fits in float64 mantissa so the threshold can be calculated without loss of precision.
+const traceIDHashBits = 53
+
+type probabilisticFallbackEvaluator struct {
+	policyEvaluator
+
+	// threshold below which the trace ID hash must be for the trace to be selected
+	threshold uint64
+}
+
+var _ PolicyEvaluator = (*probabilisticFallbackEvaluator)(nil)
+
+// NewProbabilisticFallbackFilter creates a policy evaluator which selects the given percentage of traces,
+// up to the given spans per second rate. Selection is based on the trace ID so that the same
+// trace is consistently selected (or not) across all collectors.
+func NewProbabilisticFallbackFilter(logger *zap.Logger, cfg *config.ProbabilisticFallbackCfg) (PolicyEvaluator, error) {
+	if cfg.Percentage <= 0 || cfg.Percentage > 100 {
+		return nil, errors.New("probabilistic fallback percentage must be greater than 0 and not greater than 100")
+	}
+
+	if cfg.SpansPerSecond <= 0 {
+		return nil, errors.New("probabilistic fallback spans per second must be a positive number")
+	}
+
+	return &probabilisticFallbackEvaluator{
+		policyEvaluator: policyEvaluator{
+			logger:            logger,
+			maxSpansPerSecond: cfg.SpansPerSecond,
+		},
+		threshold: uint64(float64(cfg.Percentage) / 100 * (1 << traceIDHashBits)),
+	}, nil
+}
+
+func (pe *probabilisticFallbackEvaluator) selected(traceID pdata.TraceID) bool {
+	bytes := traceID.Bytes()
+	hash := binary.BigEndian.Uint64(bytes[8:]) >> (64 - traceIDHashBits)
+	return hash < pe.threshold
+}
+
+// Evaluate looks at the trace ID and returns a corresponding SamplingDecision. Also takes into account
+// the usage of sampling rate budget
+func (pe *probabilisticFallbackEvaluator) Evaluate(traceID pdata.TraceID, trace *TraceData) Decision {
+	currSecond := time.Now().Unix()
+
+	if !pe.shouldConsider(currSecond, trace) {
+		return NotSampled
+	}
+
+	if !pe.selected(traceID) {
+		return NotSampled
+	}
+
+	return pe.updateRate(currSecond, trace.SpanCount)
+}
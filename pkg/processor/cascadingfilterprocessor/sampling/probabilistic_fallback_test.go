@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/config"
+)
+
+func traceIDWithLowBits(low uint64) pdata.TraceID {
+	id := [16]byte{}
+	binary.BigEndian.PutUint64(id[8:], low)
+	return pdata.NewTraceID(id)
+}
+
+func TestProbabilisticFallbackSelection(t *testing.T) {
+	filter, err := NewProbabilisticFallbackFilter(zap.NewNop(), &config.ProbabilisticFallbackCfg{
+		Percentage:     10,
+		SpansPerSecond: 1000,
+	})
+	require.NoError(t, err)
+
+	var empty = map[string]pdata.AttributeValue{}
+	trace := newTraceStringAttrs(empty, "example", "value")
+	trace.SpanCount = 1
+
+	sampled := 0
+	total := 1000
+	step := ^uint64(0) / uint64(total)
+	for i := 0; i < total; i++ {
+		// Spread the IDs evenly across the whole range
+		id := traceIDWithLowBits(uint64(i)*step + step/2)
+		if filter.Evaluate(id, trace) == Sampled {
+			sampled++
+		}
+	}
+	assert.Equal(t, 100, sampled)
+}
+
+func TestProbabilisticFallbackRateLimit(t *testing.T) {
+	filter, err := NewProbabilisticFallbackFilter(zap.NewNop(), &config.ProbabilisticFallbackCfg{
+		Percentage:     100,
+		SpansPerSecond: 3,
+	})
+	require.NoError(t, err)
+
+	var empty = map[string]pdata.AttributeValue{}
+	trace := newTraceStringAttrs(empty, "example", "value")
+	traceID := traceIDWithLowBits(^uint64(0))
+
+	trace.SpanCount = 4
+	assert.Equal(t, NotSampled, filter.Evaluate(traceID, trace))
+
+	trace.SpanCount = 3
+	assert.Equal(t, Sampled, filter.Evaluate(traceID, trace))
+}
+
+func TestProbabilisticFallbackInvalidConfig(t *testing.T) {
+	_, err := NewProbabilisticFallbackFilter(zap.NewNop(), &config.ProbabilisticFallbackCfg{
+		Percentage:     0,
+		SpansPerSecond: 10,
+	})
+	assert.Error(t, err)
+
+	_, err = NewProbabilisticFallbackFilter(zap.NewNop(), &config.ProbabilisticFallbackCfg{
+		Percentage:     10,
+		SpansPerSecond: 0,
+	})
+	assert.Error(t, err)
+}
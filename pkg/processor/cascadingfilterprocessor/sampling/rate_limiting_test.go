@@ -56,3 +56,17 @@ func TestRateLimiter(t *testing.T) {
 	decision = rateLimiter.Evaluate(traceID, trace)
 	assert.Equal(t, decision, Sampled)
 }
+
+func TestRateLimiterSpansPerSecondUsage(t *testing.T) {
+	rateLimiter := newRateLimiterFilter(10)
+
+	assert.Equal(t, Sampled, rateLimiter.updateRate(100, 4))
+	spans, limit := rateLimiter.SpansPerSecondUsage(100)
+	assert.Equal(t, int32(4), spans)
+	assert.Equal(t, int32(10), limit)
+
+	// Nothing was selected in the next second yet
+	spans, limit = rateLimiter.SpansPerSecondUsage(101)
+	assert.Equal(t, int32(0), spans)
+	assert.Equal(t, int32(10), limit)
+}
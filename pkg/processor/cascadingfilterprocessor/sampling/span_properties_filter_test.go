@@ -152,6 +152,27 @@ func TestSpanPropertiesFilter(t *testing.T) {
 	}
 }
 
+func TestMinSpanDurationFilter(t *testing.T) {
+	minSpanDuration := 2 * time.Second
+	filter := policyEvaluator{
+		logger:            zap.NewNop(),
+		minSpanDuration:   &minSpanDuration,
+		maxSpansPerSecond: math.MaxInt32,
+	}
+
+	// Trace which is long, but all of its spans are short
+	longTraceShortSpans := newTraceAttrs("foobar", time.Second, 2, 0)
+	spans := longTraceShortSpans.ReceivedBatches[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	secondSpan := spans.At(1)
+	secondSpan.SetStartTimestamp(secondSpan.StartTimestamp() + pdata.Timestamp(2*time.Second))
+	secondSpan.SetEndTimestamp(secondSpan.EndTimestamp() + pdata.Timestamp(2*time.Second))
+
+	evaluate(t, filter, newTraceAttrs("foobar", 3*time.Second, 1, 0), Sampled)
+	evaluate(t, filter, newTraceAttrs("foobar", 2*time.Second, 1, 0), Sampled)
+	evaluate(t, filter, newTraceAttrs("foobar", time.Second, 1, 0), NotSampled)
+	evaluate(t, filter, longTraceShortSpans, NotSampled)
+}
+
 func newTraceAttrs(operationName string, duration time.Duration, numberOfSpans int, numberOfErrors int) *TraceData {
 	endTs := time.Now().UnixNano()
 	startTs := endTs - duration.Nanoseconds()
@@ -0,0 +1,140 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	conventions "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/config"
+)
+
+// traceAggregatesFilter holds the conditions evaluated over the aggregates of the whole trace
+type traceAggregatesFilter struct {
+	minSpanCount, maxSpanCount       *int
+	minDuration, maxDuration         *time.Duration
+	hasError                         *bool
+	minServiceCount, maxServiceCount *int
+}
+
+// traceAggregates are collected from all the spans of the trace
+type traceAggregates struct {
+	spanCount  int
+	errorCount int
+	minStart   pdata.Timestamp
+	maxEnd     pdata.Timestamp
+	services   map[string]struct{}
+}
+
+func createTraceAggregatesFilter(cfg *config.TraceAggregatesCfg) (*traceAggregatesFilter, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	for _, count := range []*int{cfg.MinSpanCount, cfg.MaxSpanCount, cfg.MinServiceCount, cfg.MaxServiceCount} {
+		if count != nil && *count < 0 {
+			return nil, errors.New("trace aggregates span and service counts must be non-negative numbers")
+		}
+	}
+	for _, d := range []*time.Duration{cfg.MinDuration, cfg.MaxDuration} {
+		if d != nil && *d < 0 {
+			return nil, errors.New("trace aggregates durations must be non-negative")
+		}
+	}
+	if cfg.MinSpanCount != nil && cfg.MaxSpanCount != nil && *cfg.MinSpanCount > *cfg.MaxSpanCount {
+		return nil, errors.New("trace aggregates minimum span count cannot be greater than the maximum span count")
+	}
+	if cfg.MinDuration != nil && cfg.MaxDuration != nil && *cfg.MinDuration > *cfg.MaxDuration {
+		return nil, errors.New("trace aggregates minimum duration cannot be greater than the maximum duration")
+	}
+	if cfg.MinServiceCount != nil && cfg.MaxServiceCount != nil && *cfg.MinServiceCount > *cfg.MaxServiceCount {
+		return nil, errors.New("trace aggregates minimum service count cannot be greater than the maximum service count")
+	}
+
+	return &traceAggregatesFilter{
+		minSpanCount:    cfg.MinSpanCount,
+		maxSpanCount:    cfg.MaxSpanCount,
+		minDuration:     cfg.MinDuration,
+		maxDuration:     cfg.MaxDuration,
+		hasError:        cfg.HasError,
+		minServiceCount: cfg.MinServiceCount,
+		maxServiceCount: cfg.MaxServiceCount,
+	}, nil
+}
+
+func newTraceAggregates() *traceAggregates {
+	return &traceAggregates{services: map[string]struct{}{}}
+}
+
+func (ta *traceAggregates) addResource(res pdata.Resource) {
+	if v, ok := res.Attributes().Get(conventions.AttributeServiceName); ok && v.StringVal() != "" {
+		ta.services[v.StringVal()] = struct{}{}
+	}
+}
+
+func (ta *traceAggregates) addSpan(span pdata.Span) {
+	ta.spanCount++
+	if span.Status().Code() == pdata.StatusCodeError {
+		ta.errorCount++
+	}
+	if ta.minStart == 0 || span.StartTimestamp() < ta.minStart {
+		ta.minStart = span.StartTimestamp()
+	}
+	if span.EndTimestamp() > ta.maxEnd {
+		ta.maxEnd = span.EndTimestamp()
+	}
+}
+
+// duration returns the end-to-end duration of the trace
+func (ta *traceAggregates) duration() time.Duration {
+	if ta.maxEnd <= ta.minStart {
+		return 0
+	}
+	return time.Duration(ta.maxEnd - ta.minStart)
+}
+
+// matches checks if all the defined conditions are met by the aggregates
+func (f *traceAggregatesFilter) matches(ta *traceAggregates) bool {
+	if f.minSpanCount != nil && ta.spanCount < *f.minSpanCount {
+		return false
+	}
+	if f.maxSpanCount != nil && ta.spanCount > *f.maxSpanCount {
+		return false
+	}
+
+	duration := ta.duration()
+	if f.minDuration != nil && duration < *f.minDuration {
+		return false
+	}
+	if f.maxDuration != nil && duration > *f.maxDuration {
+		return false
+	}
+
+	if f.hasError != nil && *f.hasError != (ta.errorCount > 0) {
+		return false
+	}
+
+	if f.minServiceCount != nil && len(ta.services) < *f.minServiceCount {
+		return false
+	}
+	if f.maxServiceCount != nil && len(ta.services) > *f.maxServiceCount {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/config"
+)
+
+// newMultiServiceTrace returns a trace with one span per service, each of them received in a separate batch.
+// The spans last 1s and each starts 1s after the previous one ends.
+func newMultiServiceTrace(services []string, errorService string) *TraceData {
+	start := time.Now()
+
+	var batches []pdata.Traces
+	for i, service := range services {
+		traces := pdata.NewTraces()
+		rs := traces.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().InsertString("service.name", service)
+		span := rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+		spanStart := start.Add(time.Duration(2*i) * time.Second)
+		span.SetStartTimestamp(pdata.NewTimestampFromTime(spanStart))
+		span.SetEndTimestamp(pdata.NewTimestampFromTime(spanStart.Add(time.Second)))
+		if service == errorService {
+			span.Status().SetCode(pdata.StatusCodeError)
+		}
+		batches = append(batches, traces)
+	}
+
+	return &TraceData{
+		ReceivedBatches: batches,
+		SpanCount:       int32(len(services)),
+	}
+}
+
+func TestTraceAggregatesFilter(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	durationPtr := func(v time.Duration) *time.Duration { return &v }
+	boolPtr := func(v bool) *bool { return &v }
+
+	// the end-to-end duration is 5s, while every span lasts 1s
+	trace := newMultiServiceTrace([]string{"frontend", "backend", "frontend"}, "backend")
+
+	cases := []struct {
+		Desc     string
+		Cfg      config.TraceAggregatesCfg
+		Decision Decision
+	}{
+		{
+			Desc:     "end-to-end duration",
+			Cfg:      config.TraceAggregatesCfg{MinDuration: durationPtr(5 * time.Second)},
+			Decision: Sampled,
+		},
+		{
+			Desc:     "end-to-end duration too short",
+			Cfg:      config.TraceAggregatesCfg{MinDuration: durationPtr(6 * time.Second)},
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "end-to-end duration too long",
+			Cfg:      config.TraceAggregatesCfg{MaxDuration: durationPtr(4 * time.Second)},
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "span count range",
+			Cfg:      config.TraceAggregatesCfg{MinSpanCount: intPtr(3), MaxSpanCount: intPtr(3)},
+			Decision: Sampled,
+		},
+		{
+			Desc:     "too many spans",
+			Cfg:      config.TraceAggregatesCfg{MaxSpanCount: intPtr(2)},
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "has error",
+			Cfg:      config.TraceAggregatesCfg{HasError: boolPtr(true)},
+			Decision: Sampled,
+		},
+		{
+			Desc:     "has no error",
+			Cfg:      config.TraceAggregatesCfg{HasError: boolPtr(false)},
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "distinct service count",
+			Cfg:      config.TraceAggregatesCfg{MinServiceCount: intPtr(2), MaxServiceCount: intPtr(2)},
+			Decision: Sampled,
+		},
+		{
+			Desc:     "not enough services",
+			Cfg:      config.TraceAggregatesCfg{MinServiceCount: intPtr(3)},
+			Decision: NotSampled,
+		},
+		{
+			Desc: "all conditions met",
+			Cfg: config.TraceAggregatesCfg{
+				MinDuration:     durationPtr(5 * time.Second),
+				MinSpanCount:    intPtr(2),
+				HasError:        boolPtr(true),
+				MinServiceCount: intPtr(2),
+			},
+			Decision: Sampled,
+		},
+		{
+			Desc: "one condition not met",
+			Cfg: config.TraceAggregatesCfg{
+				MinDuration:     durationPtr(5 * time.Second),
+				MinSpanCount:    intPtr(2),
+				HasError:        boolPtr(false),
+				MinServiceCount: intPtr(2),
+			},
+			Decision: NotSampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			cfg := c.Cfg
+			evaluator, err := NewFilter(zap.NewNop(), &config.TraceAcceptCfg{
+				Name:               "test",
+				SpansPerSecond:     1000,
+				TraceAggregatesCfg: &cfg,
+			})
+			require.NoError(t, err)
+
+			decision := evaluator.Evaluate(pdata.NewTraceID([16]byte{1}), trace)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func TestTraceAggregatesFilterInvalidConfig(t *testing.T) {
+	minCount, maxCount := 3, 2
+	minDuration, maxDuration := 2*time.Second, time.Second
+	negative := -1
+
+	for _, cfg := range []config.TraceAggregatesCfg{
+		{MinSpanCount: &minCount, MaxSpanCount: &maxCount},
+		{MinServiceCount: &minCount, MaxServiceCount: &maxCount},
+		{MinDuration: &minDuration, MaxDuration: &maxDuration},
+		{MaxSpanCount: &negative},
+	} {
+		cfg := cfg
+		_, err := NewFilter(zap.NewNop(), &config.TraceAcceptCfg{Name: "test", TraceAggregatesCfg: &cfg})
+		assert.Error(t, err)
+	}
+}
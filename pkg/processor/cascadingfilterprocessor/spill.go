@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascadingfilterprocessor
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/config"
+)
+
+const spillFileExtension = ".pb"
+
+var (
+	errSpillDiskUsageExceeded = errors.New("max disk usage of spilled traces exceeded")
+
+	spillMarshaler   = otlp.NewProtobufTracesMarshaler()
+	spillUnmarshaler = otlp.NewProtobufTracesUnmarshaler()
+)
+
+// traceSpill stores undecided traces on disk, one file per trace ID. The index of stored
+// traces is kept in memory, so checking if a trace was spilled doesn't touch the disk.
+type traceSpill struct {
+	sync.Mutex
+	directory    string
+	maxDiskUsage int64
+	index        map[traceKey]int64
+	usage        int64
+}
+
+// newTraceSpill creates the spill directory (if needed) and recovers the index
+// of the traces which were spilled before the restart
+func newTraceSpill(cfg *config.SpillCfg) (*traceSpill, error) {
+	if cfg.Directory == "" {
+		return nil, errors.New("spill to disk directory must be specified")
+	}
+	if cfg.MaxDiskUsage <= 0 {
+		return nil, errors.New("spill to disk max disk usage must be a positive number")
+	}
+	if err := os.MkdirAll(cfg.Directory, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create spill to disk directory: %w", err)
+	}
+
+	ts := &traceSpill{
+		directory:    cfg.Directory,
+		maxDiskUsage: cfg.MaxDiskUsage,
+		index:        make(map[traceKey]int64),
+	}
+
+	entries, err := os.ReadDir(cfg.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spill to disk directory: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, spillFileExtension) {
+			continue
+		}
+		id, err := hex.DecodeString(strings.TrimSuffix(name, spillFileExtension))
+		if err != nil || len(id) != 16 {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spilled trace: %w", err)
+		}
+
+		var key traceKey
+		copy(key[:], id)
+		ts.index[key] = info.Size()
+		ts.usage += info.Size()
+	}
+
+	return ts, nil
+}
+
+// store copies the spans from batches to the file of the given trace, merging them with the
+// spans spilled earlier (if any). The batches are left intact, so they can be kept in memory
+// when storing fails.
+func (ts *traceSpill) store(id traceKey, batches []pdata.Traces) error {
+	ts.Lock()
+	defer ts.Unlock()
+
+	td := pdata.NewTraces()
+	prevSize, spilled := ts.index[id]
+	if spilled {
+		prev, err := ts.read(id)
+		if err != nil {
+			return err
+		}
+		prev.ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	}
+	for _, batch := range batches {
+		rss := batch.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			rss.At(i).CopyTo(td.ResourceSpans().AppendEmpty())
+		}
+	}
+
+	data, err := spillMarshaler.MarshalTraces(td)
+	if err != nil {
+		return err
+	}
+
+	if ts.usage-prevSize+int64(len(data)) > ts.maxDiskUsage {
+		return errSpillDiskUsageExceeded
+	}
+
+	path := ts.path(id)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to spill trace: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to spill trace: %w", err)
+	}
+
+	ts.index[id] = int64(len(data))
+	ts.usage += int64(len(data)) - prevSize
+	return nil
+}
+
+// take returns the spans spilled for the given trace and removes them from disk
+func (ts *traceSpill) take(id traceKey) (pdata.Traces, bool, error) {
+	ts.Lock()
+	defer ts.Unlock()
+
+	size, ok := ts.index[id]
+	if !ok {
+		return pdata.Traces{}, false, nil
+	}
+
+	td, err := ts.read(id)
+	delete(ts.index, id)
+	ts.usage -= size
+	if rmErr := os.Remove(ts.path(id)); rmErr != nil && err == nil {
+		err = fmt.Errorf("failed to remove spilled trace: %w", rmErr)
+	}
+	if err != nil {
+		return pdata.Traces{}, false, err
+	}
+
+	return td, true, nil
+}
+
+// ids returns IDs of all spilled traces
+func (ts *traceSpill) ids() []traceKey {
+	ts.Lock()
+	defer ts.Unlock()
+
+	ids := make([]traceKey, 0, len(ts.index))
+	for id := range ts.index {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (ts *traceSpill) read(id traceKey) (pdata.Traces, error) {
+	data, err := os.ReadFile(ts.path(id))
+	if err != nil {
+		return pdata.Traces{}, fmt.Errorf("failed to read spilled trace: %w", err)
+	}
+	td, err := spillUnmarshaler.UnmarshalTraces(data)
+	if err != nil {
+		return pdata.Traces{}, fmt.Errorf("failed to unmarshal spilled trace: %w", err)
+	}
+	return td, nil
+}
+
+func (ts *traceSpill) path(id traceKey) string {
+	return filepath.Join(ts.directory, hex.EncodeToString(id[:])+spillFileExtension)
+}
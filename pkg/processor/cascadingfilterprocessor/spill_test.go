@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascadingfilterprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/bigendianconverter"
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/config"
+)
+
+func TestTraceSpillStoreAndTake(t *testing.T) {
+	ts, err := newTraceSpill(&config.SpillCfg{Directory: t.TempDir(), MaxDiskUsage: 1024 * 1024})
+	require.NoError(t, err)
+
+	traceID := bigendianconverter.UInt64ToTraceID(1, 1)
+	key := traceKey(traceID.Bytes())
+
+	require.NoError(t, ts.store(key, []pdata.Traces{simpleTracesWithID(traceID)}))
+	require.NoError(t, ts.store(key, []pdata.Traces{simpleTracesWithID(traceID), simpleTracesWithID(traceID)}))
+	assert.Equal(t, []traceKey{key}, ts.ids())
+	assert.Greater(t, ts.usage, int64(0))
+
+	td, ok, err := ts.take(key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, 3, td.SpanCount())
+	assert.Equal(t, int64(0), ts.usage)
+	assert.Empty(t, ts.ids())
+
+	_, ok, err = ts.take(key)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestTraceSpillMaxDiskUsage(t *testing.T) {
+	ts, err := newTraceSpill(&config.SpillCfg{Directory: t.TempDir(), MaxDiskUsage: 1})
+	require.NoError(t, err)
+
+	traceID := bigendianconverter.UInt64ToTraceID(1, 1)
+	err = ts.store(traceKey(traceID.Bytes()), []pdata.Traces{simpleTracesWithID(traceID)})
+	assert.ErrorIs(t, err, errSpillDiskUsageExceeded)
+	assert.Empty(t, ts.ids())
+}
+
+func TestTraceSpillRecovery(t *testing.T) {
+	dir := t.TempDir()
+	ts, err := newTraceSpill(&config.SpillCfg{Directory: dir, MaxDiskUsage: 1024 * 1024})
+	require.NoError(t, err)
+
+	traceID := bigendianconverter.UInt64ToTraceID(1, 1)
+	key := traceKey(traceID.Bytes())
+	require.NoError(t, ts.store(key, []pdata.Traces{simpleTracesWithID(traceID)}))
+	// Files not created by the spill are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "unrelated.pb"), []byte("test"), 0o600))
+
+	recovered, err := newTraceSpill(&config.SpillCfg{Directory: dir, MaxDiskUsage: 1024 * 1024})
+	require.NoError(t, err)
+	assert.Equal(t, []traceKey{key}, recovered.ids())
+	assert.Equal(t, ts.usage, recovered.usage)
+
+	td, ok, err := recovered.take(key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, 1, td.SpanCount())
+}
+
+func TestTraceSpillInvalidConfig(t *testing.T) {
+	_, err := newTraceSpill(&config.SpillCfg{MaxDiskUsage: 1})
+	assert.EqualError(t, err, "spill to disk directory must be specified")
+
+	_, err = newTraceSpill(&config.SpillCfg{Directory: t.TempDir()})
+	assert.EqualError(t, err, "spill to disk max disk usage must be a positive number")
+}
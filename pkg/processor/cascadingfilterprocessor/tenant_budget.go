@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascadingfilterprocessor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/config"
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/sampling"
+)
+
+// tenantBudget partitions the total spans per second budget by the tenant of the trace,
+// identified by the value of the configured resource attribute
+type tenantBudget struct {
+	attribute    string
+	defaultLimit int32
+	limits       map[string]int32
+
+	currentSecond        int64
+	spansInCurrentSecond map[string]int32
+}
+
+func newTenantBudget(cfg *config.TenantBudgetCfg) (*tenantBudget, error) {
+	if cfg.Attribute == "" {
+		return nil, errors.New("tenant budget attribute must be set")
+	}
+	if cfg.SpansPerSecond < 0 {
+		return nil, fmt.Errorf("tenant budget spans_per_second cannot be negative: %d", cfg.SpansPerSecond)
+	}
+	for tenant, limit := range cfg.Tenants {
+		if limit < 0 {
+			return nil, fmt.Errorf("tenant budget spans_per_second of tenant %q cannot be negative: %d", tenant, limit)
+		}
+	}
+
+	return &tenantBudget{
+		attribute:            cfg.Attribute,
+		defaultLimit:         cfg.SpansPerSecond,
+		limits:               cfg.Tenants,
+		spansInCurrentSecond: make(map[string]int32),
+	}, nil
+}
+
+// tenant returns the tenant of the trace, taken from the resource of the first received batch.
+// The second value is false when the trace has no tenant attribute.
+func (tb *tenantBudget) tenant(trace *sampling.TraceData) (string, bool) {
+	trace.Lock()
+	defer trace.Unlock()
+
+	for _, batch := range trace.ReceivedBatches {
+		rss := batch.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			if av, ok := rss.At(i).Resource().Attributes().Get(tb.attribute); ok {
+				return av.AsString(), true
+			}
+		}
+	}
+	return "", false
+}
+
+// limit returns the spans per second budget of the tenant, zero means no limit
+func (tb *tenantBudget) limit(tenant string) int32 {
+	if limit, ok := tb.limits[tenant]; ok {
+		return limit
+	}
+	return tb.defaultLimit
+}
+
+// fits checks if the spans of the tenant fit within its budget in the given second
+func (tb *tenantBudget) fits(currSecond int64, tenant string, numSpans int32) bool {
+	if tb.currentSecond != currSecond {
+		tb.currentSecond = currSecond
+		tb.spansInCurrentSecond = make(map[string]int32)
+	}
+
+	limit := tb.limit(tenant)
+	return limit <= 0 || tb.spansInCurrentSecond[tenant]+numSpans <= limit
+}
+
+// add records the spans of the tenant selected in the current second
+func (tb *tenantBudget) add(tenant string, numSpans int32) {
+	tb.spansInCurrentSecond[tenant] += numSpans
+}
@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascadingfilterprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/bigendianconverter"
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/config"
+	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/cascadingfilterprocessor/sampling"
+)
+
+func tenantTraces(traceID pdata.TraceID, tenant string, numSpans int) pdata.Traces {
+	traces := pdata.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	if tenant != "" {
+		rs.Resource().Attributes().InsertString("service.namespace", tenant)
+	}
+
+	ils := rs.InstrumentationLibrarySpans().AppendEmpty()
+	for i := 0; i < numSpans; i++ {
+		ils.Spans().AppendEmpty().SetTraceID(traceID)
+	}
+
+	return traces
+}
+
+func TestTenantBudgetInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.TenantBudgetCfg
+		expectedErr string
+	}{
+		{
+			name:        "no attribute",
+			cfg:         config.TenantBudgetCfg{SpansPerSecond: 100},
+			expectedErr: "tenant budget attribute must be set",
+		},
+		{
+			name:        "negative spans per second",
+			cfg:         config.TenantBudgetCfg{Attribute: "tenant", SpansPerSecond: -1},
+			expectedErr: "tenant budget spans_per_second cannot be negative: -1",
+		},
+		{
+			name:        "negative tenant spans per second",
+			cfg:         config.TenantBudgetCfg{Attribute: "tenant", Tenants: map[string]int32{"a": -5}},
+			expectedErr: `tenant budget spans_per_second of tenant "a" cannot be negative: -5`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{
+				DecisionWait:            defaultTestDecisionWait,
+				NumTraces:               100,
+				ExpectedNewTracesPerSec: 100,
+				TenantBudgetCfg:         &tt.cfg,
+			}
+			_, err := newTraceProcessor(zap.NewNop(), consumertest.NewNop(), cfg)
+			require.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTenantBudgetFits(t *testing.T) {
+	tb, err := newTenantBudget(&config.TenantBudgetCfg{
+		Attribute:      "service.namespace",
+		SpansPerSecond: 10,
+		Tenants:        map[string]int32{"big": 20, "unlimited": 0},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, tb.fits(1, "small", 10))
+	tb.add("small", 10)
+	assert.False(t, tb.fits(1, "small", 1))
+
+	assert.True(t, tb.fits(1, "big", 20))
+	tb.add("big", 20)
+	assert.False(t, tb.fits(1, "big", 1))
+
+	assert.True(t, tb.fits(1, "unlimited", 1000))
+
+	// The budget is renewed every second
+	assert.True(t, tb.fits(2, "small", 10))
+	assert.True(t, tb.fits(2, "big", 20))
+}
+
+func TestTenantBudgetLimitsNoisyTenant(t *testing.T) {
+	msp := new(consumertest.TracesSink)
+	budget, err := newTenantBudget(&config.TenantBudgetCfg{
+		Attribute:      "service.namespace",
+		SpansPerSecond: 10,
+	})
+	require.NoError(t, err)
+
+	tsp := &cascadingFilterSpanProcessor{
+		ctx:             context.Background(),
+		nextConsumer:    msp,
+		maxNumTraces:    100,
+		logger:          zap.NewNop(),
+		decisionBatcher: newSyncIDBatcher(1),
+		traceAcceptRules: []*TraceAcceptEvaluator{
+			{Name: "mock-policy", Evaluator: &mockPolicyEvaluator{NextDecision: sampling.Sampled}, ctx: context.TODO()},
+		},
+		deleteChan:        make(chan traceKey, 100),
+		policyTicker:      &manualTTicker{},
+		maxSpansPerSecond: 30,
+		filteringEnabled:  true,
+		tenantBudget:      budget,
+	}
+
+	// The noisy tenant sends more spans than the whole budget, but only its own budget is used up
+	for i := uint64(1); i <= 5; i++ {
+		td := tenantTraces(bigendianconverter.UInt64ToTraceID(1, i), "noisy", 5)
+		require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+	}
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), tenantTraces(bigendianconverter.UInt64ToTraceID(2, 1), "quiet", 5)))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), tenantTraces(bigendianconverter.UInt64ToTraceID(3, 1), "", 10)))
+
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+
+	spansPerTenant := map[string]int{}
+	for _, td := range msp.AllTraces() {
+		rs := td.ResourceSpans()
+		for i := 0; i < rs.Len(); i++ {
+			tenant := ""
+			if av, ok := rs.At(i).Resource().Attributes().Get("service.namespace"); ok {
+				tenant = av.StringVal()
+			}
+			spansPerTenant[tenant] += rs.At(i).InstrumentationLibrarySpans().At(0).Spans().Len()
+		}
+	}
+	assert.Equal(t, map[string]int{"noisy": 10, "quiet": 5, "": 10}, spansPerTenant)
+}
@@ -0,0 +1,132 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascadinglogfilterprocessor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Action is the action taken on the log records matching the rule
+type Action string
+
+const (
+	// ActionInclude keeps the matching log records, up to the rule's rate limit
+	ActionInclude Action = "include"
+	// ActionDrop drops the matching log records
+	ActionDrop Action = "drop"
+)
+
+// Config defines configuration for the cascading log filter processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:"-"`
+
+	// Rules is the ordered list of the rules. The first rule matching
+	// the log record decides whether the record is kept or dropped.
+	// The records which don't match any rule are kept.
+	Rules []RuleConfig `mapstructure:"rules"`
+
+	// SourceCategoryAttribute is the attribute with the source category,
+	// the rules' rate limits are applied separately per its value.
+	// By default this is _sourceCategory.
+	SourceCategoryAttribute string `mapstructure:"source_category_attribute"`
+}
+
+// RuleConfig defines the rule matching the log records.
+// All the conditions of the rule have to be met for the record to match,
+// the rule without conditions matches all the records.
+type RuleConfig struct {
+	// Name is the name of the rule, used in the logs and metrics.
+	Name string `mapstructure:"name"`
+
+	// Action is either include or drop.
+	Action Action `mapstructure:"action"`
+
+	// BodyRegex is the regular expression which the log body has to match.
+	BodyRegex string `mapstructure:"body_regex"`
+
+	// Attributes are the conditions on the log record attributes,
+	// or the resource attributes if the record doesn't have the attribute.
+	Attributes []AttributeConfig `mapstructure:"attributes"`
+
+	// RecordsPerSecond limits the number of the log records kept by the include rule
+	// per second and source category. The records above the limit are dropped.
+	// 0 means no limit.
+	RecordsPerSecond int64 `mapstructure:"records_per_second"`
+}
+
+// AttributeConfig defines the condition on the attribute value
+type AttributeConfig struct {
+	// Key is the attribute name.
+	Key string `mapstructure:"key"`
+
+	// Regex is the regular expression which the attribute value has to match.
+	Regex string `mapstructure:"regex"`
+}
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.SourceCategoryAttribute == "" {
+		return errors.New("source_category_attribute cannot be empty")
+	}
+
+	names := make(map[string]struct{}, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("rule %q has invalid configuration: %w", rule.Name, err)
+		}
+		if _, ok := names[rule.Name]; ok {
+			return fmt.Errorf("rule %q is configured more than once", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+	}
+	return nil
+}
+
+// Validate checks if the rule configuration is valid
+func (rule *RuleConfig) Validate() error {
+	if rule.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+
+	switch rule.Action {
+	case ActionInclude:
+	case ActionDrop:
+		if rule.RecordsPerSecond != 0 {
+			return errors.New("records_per_second can be used with include action only")
+		}
+	default:
+		return fmt.Errorf("unexpected action: %s", rule.Action)
+	}
+
+	if rule.RecordsPerSecond < 0 {
+		return fmt.Errorf("records_per_second cannot be negative: %d", rule.RecordsPerSecond)
+	}
+	if _, err := regexp.Compile(rule.BodyRegex); err != nil {
+		return fmt.Errorf("invalid body_regex: %w", err)
+	}
+	for _, attr := range rule.Attributes {
+		if attr.Key == "" {
+			return errors.New("attribute key cannot be empty")
+		}
+		if _, err := regexp.Compile(attr.Regex); err != nil {
+			return fmt.Errorf("invalid regex of attribute %s: %w", attr.Key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascadinglogfilterprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[factory.Type()] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Processors[config.NewComponentID(typeStr)])
+
+	assert.Equal(t,
+		&Config{
+			ProcessorSettings:       config.NewProcessorSettings(config.NewComponentIDWithName(typeStr, "custom")),
+			SourceCategoryAttribute: "category",
+			Rules: []RuleConfig{
+				{
+					Name:      "drop-health-checks",
+					Action:    ActionDrop,
+					BodyRegex: "GET /healthz",
+				},
+				{
+					Name:   "limit-noisy-app",
+					Action: ActionInclude,
+					Attributes: []AttributeConfig{
+						{Key: "k8s.namespace.name", Regex: "^noisy-"},
+					},
+					RecordsPerSecond: 100,
+				},
+			},
+		},
+		cfg.Processors[config.NewComponentIDWithName(typeStr, "custom")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		rules         []RuleConfig
+		expectedError string
+	}{
+		{
+			name: "valid rules",
+			rules: []RuleConfig{
+				{Name: "drop", Action: ActionDrop, BodyRegex: "DEBUG"},
+				{Name: "limit", Action: ActionInclude, RecordsPerSecond: 10},
+			},
+		},
+		{
+			name:          "no name",
+			rules:         []RuleConfig{{Action: ActionDrop}},
+			expectedError: `rule "" has invalid configuration: name cannot be empty`,
+		},
+		{
+			name: "duplicated name",
+			rules: []RuleConfig{
+				{Name: "rule", Action: ActionDrop},
+				{Name: "rule", Action: ActionInclude},
+			},
+			expectedError: `rule "rule" is configured more than once`,
+		},
+		{
+			name:          "unexpected action",
+			rules:         []RuleConfig{{Name: "rule", Action: "sample"}},
+			expectedError: `rule "rule" has invalid configuration: unexpected action: sample`,
+		},
+		{
+			name:          "rate limit of drop rule",
+			rules:         []RuleConfig{{Name: "rule", Action: ActionDrop, RecordsPerSecond: 10}},
+			expectedError: `rule "rule" has invalid configuration: records_per_second can be used with include action only`,
+		},
+		{
+			name:          "negative rate limit",
+			rules:         []RuleConfig{{Name: "rule", Action: ActionInclude, RecordsPerSecond: -1}},
+			expectedError: `rule "rule" has invalid configuration: records_per_second cannot be negative: -1`,
+		},
+		{
+			name:          "invalid body regex",
+			rules:         []RuleConfig{{Name: "rule", Action: ActionDrop, BodyRegex: "("}},
+			expectedError: "rule \"rule\" has invalid configuration: invalid body_regex: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name: "empty attribute key",
+			rules: []RuleConfig{{Name: "rule", Action: ActionDrop, Attributes: []AttributeConfig{
+				{Regex: ".*"},
+			}}},
+			expectedError: `rule "rule" has invalid configuration: attribute key cannot be empty`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Rules = tc.rules
+
+			err := cfg.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascadinglogfilterprocessor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func init() {
+	err := view.Register(viewRecordsFiltered)
+	if err != nil {
+		fmt.Printf("Error registering cascading log filter processor's views: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+var (
+	tagRuleKey, _     = tag.NewKey("rule")
+	tagDecisionKey, _ = tag.NewKey("decision")
+
+	mRecordsFiltered = stats.Int64("cascading_log_filter_records", "Number of log records matching the rule, per decision", stats.UnitDimensionless)
+)
+
+var viewRecordsFiltered = &view.View{
+	Name:        mRecordsFiltered.Name(),
+	Description: mRecordsFiltered.Description(),
+	Measure:     mRecordsFiltered,
+	TagKeys:     []tag.Key{tagRuleKey, tagDecisionKey},
+	Aggregation: view.Sum(),
+}
+
+// recordDecision increments the metric that the log record matched the rule
+func recordDecision(rule string, d decision) {
+	_ = stats.RecordWithTags(
+		context.Background(),
+		[]tag.Mutator{tag.Upsert(tagRuleKey, rule), tag.Upsert(tagDecisionKey, string(d))},
+		mRecordsFiltered.M(int64(1)),
+	)
+}
@@ -0,0 +1,215 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascadinglogfilterprocessor
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type cascadingLogFilterProcessor struct {
+	rules                   []*rule
+	sourceCategoryAttribute string
+	// now returns the current time, it's overridden in tests
+	now func() time.Time
+}
+
+type rule struct {
+	name             string
+	action           Action
+	bodyRegex        *regexp.Regexp
+	attributes       []attributeCondition
+	recordsPerSecond int64
+
+	// lock guards windows
+	lock sync.Mutex
+	// windows holds the number of the records kept in the current second per source category
+	windows map[string]*rateWindow
+}
+
+type attributeCondition struct {
+	key   string
+	regex *regexp.Regexp
+}
+
+type rateWindow struct {
+	second int64
+	count  int64
+}
+
+// decision is the result of the filtering of a log record
+type decision string
+
+const (
+	decisionKept        decision = "kept"
+	decisionDropped     decision = "dropped"
+	decisionRateLimited decision = "rate_limited"
+)
+
+func newCascadingLogFilterProcessor(cfg *Config) (*cascadingLogFilterProcessor, error) {
+	rules := make([]*rule, 0, len(cfg.Rules))
+	for _, rc := range cfg.Rules {
+		r := &rule{
+			name:             rc.Name,
+			action:           rc.Action,
+			recordsPerSecond: rc.RecordsPerSecond,
+			windows:          make(map[string]*rateWindow),
+		}
+		if rc.BodyRegex != "" {
+			regex, err := regexp.Compile(rc.BodyRegex)
+			if err != nil {
+				return nil, err
+			}
+			r.bodyRegex = regex
+		}
+		for _, attr := range rc.Attributes {
+			regex, err := regexp.Compile(attr.Regex)
+			if err != nil {
+				return nil, err
+			}
+			r.attributes = append(r.attributes, attributeCondition{key: attr.Key, regex: regex})
+		}
+		rules = append(rules, r)
+	}
+
+	return &cascadingLogFilterProcessor{
+		rules:                   rules,
+		sourceCategoryAttribute: cfg.SourceCategoryAttribute,
+		now:                     time.Now,
+	}, nil
+}
+
+// ProcessLogs drops the log records according to the rules
+func (lfp *cascadingLogFilterProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resourceAttributes := rl.Resource().Attributes()
+
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ills.At(j).LogRecords().RemoveIf(func(lr pdata.LogRecord) bool {
+				return !lfp.keep(resourceAttributes, lr)
+			})
+		}
+		ills.RemoveIf(func(ill pdata.InstrumentationLibraryLogs) bool {
+			return ill.LogRecords().Len() == 0
+		})
+	}
+	rls.RemoveIf(func(rl pdata.ResourceLogs) bool {
+		return rl.InstrumentationLibraryLogs().Len() == 0
+	})
+
+	if rls.Len() == 0 {
+		return ld, processorhelper.ErrSkipProcessingData
+	}
+	return ld, nil
+}
+
+// keep returns whether the log record should be kept, basing on the first matching rule
+func (lfp *cascadingLogFilterProcessor) keep(resourceAttributes pdata.AttributeMap, lr pdata.LogRecord) bool {
+	for _, r := range lfp.rules {
+		if !r.matches(resourceAttributes, lr) {
+			continue
+		}
+
+		d := decisionDropped
+		if r.action == ActionInclude {
+			category := getAttribute(resourceAttributes, lr.Attributes(), lfp.sourceCategoryAttribute)
+			if r.allow(category, lfp.now()) {
+				d = decisionKept
+			} else {
+				d = decisionRateLimited
+			}
+		}
+		recordDecision(r.name, d)
+		return d == decisionKept
+	}
+	return true
+}
+
+func (r *rule) matches(resourceAttributes pdata.AttributeMap, lr pdata.LogRecord) bool {
+	if r.bodyRegex != nil {
+		if lr.Body().Type() != pdata.AttributeValueTypeString || !r.bodyRegex.MatchString(lr.Body().StringVal()) {
+			return false
+		}
+	}
+	for _, cond := range r.attributes {
+		value, ok := lookupAttribute(resourceAttributes, lr.Attributes(), cond.key)
+		if !ok || !cond.regex.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// allow returns whether the record of the source category is within the rule's rate limit
+func (r *rule) allow(category string, now time.Time) bool {
+	if r.recordsPerSecond == 0 {
+		return true
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	second := now.Unix()
+	window, ok := r.windows[category]
+	if !ok {
+		window = &rateWindow{}
+		r.windows[category] = window
+	}
+	if window.second != second {
+		window.second = second
+		window.count = 0
+		r.pruneWindows(second)
+	}
+	if window.count >= r.recordsPerSecond {
+		return false
+	}
+	window.count++
+	return true
+}
+
+// pruneWindows removes the windows of the source categories which didn't send records
+// in the previous second, it has to be called with lock held
+func (r *rule) pruneWindows(second int64) {
+	for category, window := range r.windows {
+		if window.second < second-1 {
+			delete(r.windows, category)
+		}
+	}
+}
+
+// lookupAttribute returns the value of the log record attribute,
+// or the resource attribute if the record doesn't have it
+func lookupAttribute(resourceAttributes pdata.AttributeMap, recordAttributes pdata.AttributeMap, key string) (string, bool) {
+	if value, ok := recordAttributes.Get(key); ok {
+		return value.AsString(), true
+	}
+	if value, ok := resourceAttributes.Get(key); ok {
+		return value.AsString(), true
+	}
+	return "", false
+}
+
+func getAttribute(resourceAttributes pdata.AttributeMap, recordAttributes pdata.AttributeMap, key string) string {
+	value, _ := lookupAttribute(resourceAttributes, recordAttributes, key)
+	return value
+}
@@ -0,0 +1,161 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascadinglogfilterprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+func newTestProcessor(t *testing.T, rules []RuleConfig) *cascadingLogFilterProcessor {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Rules = rules
+	require.NoError(t, cfg.Validate())
+
+	lfp, err := newCascadingLogFilterProcessor(cfg)
+	require.NoError(t, err)
+	return lfp
+}
+
+// newLogs creates the logs with a single resource of the source category and the record per body
+func newLogs(sourceCategory string, bodies ...string) pdata.Logs {
+	logs := pdata.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString("_sourceCategory", sourceCategory)
+	records := rl.InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+	for _, body := range bodies {
+		records.AppendEmpty().Body().SetStringVal(body)
+	}
+	return logs
+}
+
+func getBodies(logs pdata.Logs) []string {
+	bodies := []string{}
+	rls := logs.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		ills := rls.At(i).InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			records := ills.At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				bodies = append(bodies, records.At(k).Body().AsString())
+			}
+		}
+	}
+	return bodies
+}
+
+func TestProcessLogsFirstMatchingRuleDecides(t *testing.T) {
+	lfp := newTestProcessor(t, []RuleConfig{
+		{Name: "keep-errors", Action: ActionInclude, BodyRegex: "ERROR"},
+		{Name: "drop-debug", Action: ActionDrop, BodyRegex: "DEBUG|ERROR"},
+	})
+
+	logs, err := lfp.ProcessLogs(context.Background(), newLogs("app", "DEBUG first", "ERROR second", "INFO third"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ERROR second", "INFO third"}, getBodies(logs))
+}
+
+func TestProcessLogsAttributes(t *testing.T) {
+	lfp := newTestProcessor(t, []RuleConfig{
+		{
+			Name:   "drop-noisy-debug",
+			Action: ActionDrop,
+			Attributes: []AttributeConfig{
+				{Key: "_sourceCategory", Regex: "^noisy/"},
+				{Key: "level", Regex: "^debug$"},
+			},
+		},
+	})
+
+	logs := newLogs("noisy/app", "first", "second", "third")
+	records := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).LogRecords()
+	records.At(0).Attributes().InsertString("level", "debug")
+	records.At(1).Attributes().InsertString("level", "info")
+
+	logs, err := lfp.ProcessLogs(context.Background(), logs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"second", "third"}, getBodies(logs))
+}
+
+func TestProcessLogsNonStringBody(t *testing.T) {
+	lfp := newTestProcessor(t, []RuleConfig{
+		{Name: "drop-numbers", Action: ActionDrop, BodyRegex: "^[0-9]+$"},
+	})
+
+	logs := newLogs("app")
+	logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).LogRecords().AppendEmpty().Body().SetIntVal(42)
+
+	logs, err := lfp.ProcessLogs(context.Background(), logs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"42"}, getBodies(logs))
+}
+
+func TestProcessLogsAllDropped(t *testing.T) {
+	lfp := newTestProcessor(t, []RuleConfig{
+		{Name: "drop-all", Action: ActionDrop},
+	})
+
+	logs, err := lfp.ProcessLogs(context.Background(), newLogs("app", "first", "second"))
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+	assert.Equal(t, 0, logs.ResourceLogs().Len())
+}
+
+func TestProcessLogsRateLimitPerSourceCategory(t *testing.T) {
+	lfp := newTestProcessor(t, []RuleConfig{
+		{Name: "limit", Action: ActionInclude, RecordsPerSecond: 2},
+	})
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	lfp.now = func() time.Time { return now }
+
+	logs, err := lfp.ProcessLogs(context.Background(), newLogs("spammy", "1", "2", "3", "4"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, getBodies(logs))
+
+	// the other source category has its own limit
+	logs, err = lfp.ProcessLogs(context.Background(), newLogs("quiet", "5"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"5"}, getBodies(logs))
+
+	// the limit of the source category is reached in the current second
+	_, err = lfp.ProcessLogs(context.Background(), newLogs("spammy", "6"))
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+
+	now = now.Add(time.Second)
+	logs, err = lfp.ProcessLogs(context.Background(), newLogs("spammy", "7", "8", "9"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"7", "8"}, getBodies(logs))
+}
+
+func TestRulePruneWindows(t *testing.T) {
+	lfp := newTestProcessor(t, []RuleConfig{
+		{Name: "limit", Action: ActionInclude, RecordsPerSecond: 1},
+	})
+	r := lfp.rules[0]
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, r.allow("first", now))
+	assert.True(t, r.allow("second", now.Add(time.Second)))
+	assert.Len(t, r.windows, 2)
+
+	assert.True(t, r.allow("second", now.Add(3*time.Second)))
+	assert.Len(t, r.windows, 1)
+	assert.Contains(t, r.windows, "second")
+}
@@ -0,0 +1,110 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8slogsamplerprocessor
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the Kubernetes log sampler processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:"-"`
+
+	// Tiers is the ordered list of the tiers. The first tier matching the pod
+	// the log records come from decides their sampling percentage.
+	Tiers []TierConfig `mapstructure:"tiers"`
+
+	// DefaultSamplingPercentage is the sampling percentage of the log records
+	// of the pods which don't match any tier.
+	// By default this is 100.
+	DefaultSamplingPercentage float64 `mapstructure:"default_sampling_percentage"`
+
+	// PriorityClassAttribute is the resource attribute with the pod's priority class name.
+	// By default this is k8s.pod.priority_class_name.
+	PriorityClassAttribute string `mapstructure:"priority_class_attribute"`
+
+	// NamespaceAttribute is the resource attribute with the pod's namespace.
+	// By default this is k8s.namespace.name.
+	NamespaceAttribute string `mapstructure:"namespace_attribute"`
+}
+
+// TierConfig defines the tier of the pods with the same sampling percentage.
+// The pod matches the tier when either its priority class or its namespace
+// is on the tier's lists.
+type TierConfig struct {
+	// Name is the name of the tier, used in the metrics.
+	Name string `mapstructure:"name"`
+
+	// PriorityClasses are the priority class names of the pods in the tier.
+	PriorityClasses []string `mapstructure:"priority_classes"`
+
+	// Namespaces are the namespaces of the pods in the tier.
+	Namespaces []string `mapstructure:"namespaces"`
+
+	// SamplingPercentage is the percentage of the log records which are kept.
+	SamplingPercentage float64 `mapstructure:"sampling_percentage"`
+}
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	if err := validatePercentage(cfg.DefaultSamplingPercentage); err != nil {
+		return fmt.Errorf("default_sampling_percentage: %w", err)
+	}
+	if cfg.PriorityClassAttribute == "" {
+		return errors.New("priority_class_attribute cannot be empty")
+	}
+	if cfg.NamespaceAttribute == "" {
+		return errors.New("namespace_attribute cannot be empty")
+	}
+
+	names := make(map[string]struct{}, len(cfg.Tiers))
+	for _, tier := range cfg.Tiers {
+		if err := tier.Validate(); err != nil {
+			return fmt.Errorf("tier %q has invalid configuration: %w", tier.Name, err)
+		}
+		if _, ok := names[tier.Name]; ok {
+			return fmt.Errorf("tier %q is configured more than once", tier.Name)
+		}
+		names[tier.Name] = struct{}{}
+	}
+	return nil
+}
+
+// Validate checks if the tier configuration is valid
+func (tier *TierConfig) Validate() error {
+	if tier.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if tier.Name == defaultTierName {
+		return fmt.Errorf("name %q is reserved for the pods without a tier", defaultTierName)
+	}
+	if len(tier.PriorityClasses) == 0 && len(tier.Namespaces) == 0 {
+		return errors.New("either priority_classes or namespaces have to be specified")
+	}
+	if err := validatePercentage(tier.SamplingPercentage); err != nil {
+		return fmt.Errorf("sampling_percentage: %w", err)
+	}
+	return nil
+}
+
+func validatePercentage(p float64) error {
+	if p < 0 || p > 100 {
+		return fmt.Errorf("has to be between 0 and 100: %v", p)
+	}
+	return nil
+}
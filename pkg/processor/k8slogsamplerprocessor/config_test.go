@@ -0,0 +1,148 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8slogsamplerprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[factory.Type()] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Processors[config.NewComponentID(typeStr)])
+
+	assert.Equal(t,
+		&Config{
+			ProcessorSettings:         config.NewProcessorSettings(config.NewComponentIDWithName(typeStr, "custom")),
+			DefaultSamplingPercentage: 50,
+			PriorityClassAttribute:    "priority",
+			NamespaceAttribute:        "namespace",
+			Tiers: []TierConfig{
+				{
+					Name:               "critical",
+					PriorityClasses:    []string{"system-cluster-critical", "system-node-critical"},
+					Namespaces:         []string{"kube-system"},
+					SamplingPercentage: 100,
+				},
+				{
+					Name:               "best-effort",
+					PriorityClasses:    []string{"best-effort"},
+					SamplingPercentage: 10,
+				},
+			},
+		},
+		cfg.Processors[config.NewComponentIDWithName(typeStr, "custom")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		modify        func(cfg *Config)
+		expectedError string
+	}{
+		{
+			name: "valid tiers",
+			modify: func(cfg *Config) {
+				cfg.Tiers = []TierConfig{
+					{Name: "critical", PriorityClasses: []string{"critical"}, SamplingPercentage: 100},
+					{Name: "dev", Namespaces: []string{"dev"}, SamplingPercentage: 0},
+				}
+			},
+		},
+		{
+			name:          "default sampling percentage out of range",
+			modify:        func(cfg *Config) { cfg.DefaultSamplingPercentage = 101 },
+			expectedError: "default_sampling_percentage: has to be between 0 and 100: 101",
+		},
+		{
+			name:          "empty priority class attribute",
+			modify:        func(cfg *Config) { cfg.PriorityClassAttribute = "" },
+			expectedError: "priority_class_attribute cannot be empty",
+		},
+		{
+			name:          "empty namespace attribute",
+			modify:        func(cfg *Config) { cfg.NamespaceAttribute = "" },
+			expectedError: "namespace_attribute cannot be empty",
+		},
+		{
+			name: "no name",
+			modify: func(cfg *Config) {
+				cfg.Tiers = []TierConfig{{Namespaces: []string{"dev"}}}
+			},
+			expectedError: `tier "" has invalid configuration: name cannot be empty`,
+		},
+		{
+			name: "reserved name",
+			modify: func(cfg *Config) {
+				cfg.Tiers = []TierConfig{{Name: "default", Namespaces: []string{"dev"}}}
+			},
+			expectedError: `tier "default" has invalid configuration: name "default" is reserved for the pods without a tier`,
+		},
+		{
+			name: "duplicated name",
+			modify: func(cfg *Config) {
+				cfg.Tiers = []TierConfig{
+					{Name: "tier", Namespaces: []string{"dev"}},
+					{Name: "tier", Namespaces: []string{"test"}},
+				}
+			},
+			expectedError: `tier "tier" is configured more than once`,
+		},
+		{
+			name: "no conditions",
+			modify: func(cfg *Config) {
+				cfg.Tiers = []TierConfig{{Name: "tier", SamplingPercentage: 10}}
+			},
+			expectedError: `tier "tier" has invalid configuration: either priority_classes or namespaces have to be specified`,
+		},
+		{
+			name: "negative sampling percentage",
+			modify: func(cfg *Config) {
+				cfg.Tiers = []TierConfig{{Name: "tier", Namespaces: []string{"dev"}, SamplingPercentage: -1}}
+			},
+			expectedError: `tier "tier" has invalid configuration: sampling_percentage: has to be between 0 and 100: -1`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
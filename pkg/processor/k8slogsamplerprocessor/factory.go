@@ -0,0 +1,68 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8slogsamplerprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "k8s_log_sampler"
+
+	defaultSamplingPercentage     = 100
+	defaultPriorityClassAttribute = "k8s.pod.priority_class_name"
+	defaultNamespaceAttribute     = "k8s.namespace.name"
+)
+
+var processorCapabilities = consumer.Capabilities{MutatesData: true}
+
+// NewFactory returns a new factory for the Kubernetes log sampler processor.
+func NewFactory() component.ProcessorFactory {
+	return component.NewProcessorFactory(
+		typeStr,
+		createDefaultConfig,
+		component.WithLogsProcessor(createLogsProcessor),
+	)
+}
+
+func createDefaultConfig() config.Processor {
+	return &Config{
+		ProcessorSettings:         config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		DefaultSamplingPercentage: defaultSamplingPercentage,
+		PriorityClassAttribute:    defaultPriorityClassAttribute,
+		NamespaceAttribute:        defaultNamespaceAttribute,
+	}
+}
+
+func createLogsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateSettings,
+	cfg config.Processor,
+	nextConsumer consumer.Logs,
+) (component.LogsProcessor, error) {
+	lsp := newK8sLogSamplerProcessor(cfg.(*Config))
+
+	return processorhelper.NewLogsProcessor(
+		cfg,
+		nextConsumer,
+		lsp.ProcessLogs,
+		processorhelper.WithCapabilities(processorCapabilities))
+}
@@ -0,0 +1,60 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8slogsamplerprocessor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func init() {
+	err := view.Register(viewRecordsSampled)
+	if err != nil {
+		fmt.Printf("Error registering k8s log sampler processor's views: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+var (
+	tagTierKey, _     = tag.NewKey("tier")
+	tagDecisionKey, _ = tag.NewKey("decision")
+
+	mRecordsSampled = stats.Int64("k8s_log_sampler_records", "Number of log records, per tier and sampling decision", stats.UnitDimensionless)
+)
+
+var viewRecordsSampled = &view.View{
+	Name:        mRecordsSampled.Name(),
+	Description: mRecordsSampled.Description(),
+	Measure:     mRecordsSampled,
+	TagKeys:     []tag.Key{tagTierKey, tagDecisionKey},
+	Aggregation: view.Sum(),
+}
+
+// recordDecisions increments the metric by the number of the tier's log records with the decision
+func recordDecisions(tier string, d decision, count int) {
+	if count == 0 {
+		return
+	}
+	_ = stats.RecordWithTags(
+		context.Background(),
+		[]tag.Mutator{tag.Upsert(tagTierKey, tier), tag.Upsert(tagDecisionKey, string(d))},
+		mRecordsSampled.M(int64(count)),
+	)
+}
@@ -0,0 +1,155 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8slogsamplerprocessor
+
+import (
+	"context"
+	"math/rand"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+// defaultTierName is the tier of the pods which don't match any configured tier
+const defaultTierName = "default"
+
+type k8sLogSamplerProcessor struct {
+	tiers                  []tier
+	defaultTier            tier
+	priorityClassAttribute string
+	namespaceAttribute     string
+	// random returns a pseudo-random number in [0.0,1.0), it's overridden in tests
+	random func() float64
+}
+
+type tier struct {
+	name            string
+	priorityClasses map[string]struct{}
+	namespaces      map[string]struct{}
+	// ratio is the sampling percentage divided by 100
+	ratio float64
+}
+
+// decision is the result of the sampling of a log record
+type decision string
+
+const (
+	decisionSampledIn  decision = "sampled_in"
+	decisionSampledOut decision = "sampled_out"
+)
+
+func newK8sLogSamplerProcessor(cfg *Config) *k8sLogSamplerProcessor {
+	tiers := make([]tier, 0, len(cfg.Tiers))
+	for _, tc := range cfg.Tiers {
+		tiers = append(tiers, tier{
+			name:            tc.Name,
+			priorityClasses: toSet(tc.PriorityClasses),
+			namespaces:      toSet(tc.Namespaces),
+			ratio:           tc.SamplingPercentage / 100,
+		})
+	}
+
+	return &k8sLogSamplerProcessor{
+		tiers: tiers,
+		defaultTier: tier{
+			name:  defaultTierName,
+			ratio: cfg.DefaultSamplingPercentage / 100,
+		},
+		priorityClassAttribute: cfg.PriorityClassAttribute,
+		namespaceAttribute:     cfg.NamespaceAttribute,
+		random:                 rand.Float64,
+	}
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// ProcessLogs samples the log records with the sampling percentage of their pod's tier
+func (lsp *k8sLogSamplerProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		t := lsp.tierOf(rl.Resource().Attributes())
+
+		sampledIn, sampledOut := 0, 0
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ills.At(j).LogRecords().RemoveIf(func(pdata.LogRecord) bool {
+				if lsp.sample(t) {
+					sampledIn++
+					return false
+				}
+				sampledOut++
+				return true
+			})
+		}
+		ills.RemoveIf(func(ill pdata.InstrumentationLibraryLogs) bool {
+			return ill.LogRecords().Len() == 0
+		})
+
+		recordDecisions(t.name, decisionSampledIn, sampledIn)
+		recordDecisions(t.name, decisionSampledOut, sampledOut)
+	}
+	rls.RemoveIf(func(rl pdata.ResourceLogs) bool {
+		return rl.InstrumentationLibraryLogs().Len() == 0
+	})
+
+	if rls.Len() == 0 {
+		return ld, processorhelper.ErrSkipProcessingData
+	}
+	return ld, nil
+}
+
+// tierOf returns the first tier matching the pod's priority class or namespace
+func (lsp *k8sLogSamplerProcessor) tierOf(resourceAttributes pdata.AttributeMap) *tier {
+	priorityClass, hasPriorityClass := getStringAttribute(resourceAttributes, lsp.priorityClassAttribute)
+	namespace, hasNamespace := getStringAttribute(resourceAttributes, lsp.namespaceAttribute)
+
+	for i := range lsp.tiers {
+		t := &lsp.tiers[i]
+		if _, ok := t.priorityClasses[priorityClass]; ok && hasPriorityClass {
+			return t
+		}
+		if _, ok := t.namespaces[namespace]; ok && hasNamespace {
+			return t
+		}
+	}
+	return &lsp.defaultTier
+}
+
+// sample returns whether the log record is kept
+func (lsp *k8sLogSamplerProcessor) sample(t *tier) bool {
+	switch {
+	case t.ratio >= 1:
+		return true
+	case t.ratio <= 0:
+		return false
+	default:
+		return lsp.random() < t.ratio
+	}
+}
+
+func getStringAttribute(attributes pdata.AttributeMap, key string) (string, bool) {
+	value, ok := attributes.Get(key)
+	if !ok {
+		return "", false
+	}
+	return value.AsString(), true
+}
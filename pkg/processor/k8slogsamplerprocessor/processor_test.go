@@ -0,0 +1,130 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8slogsamplerprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+func newTestProcessor(t *testing.T, tiers []TierConfig) *k8sLogSamplerProcessor {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Tiers = tiers
+	require.NoError(t, cfg.Validate())
+
+	return newK8sLogSamplerProcessor(cfg)
+}
+
+// appendResourceLogs appends the resource of the pod with the record per body
+func appendResourceLogs(logs pdata.Logs, namespace string, priorityClass string, bodies ...string) {
+	rl := logs.ResourceLogs().AppendEmpty()
+	if namespace != "" {
+		rl.Resource().Attributes().InsertString(defaultNamespaceAttribute, namespace)
+	}
+	if priorityClass != "" {
+		rl.Resource().Attributes().InsertString(defaultPriorityClassAttribute, priorityClass)
+	}
+	records := rl.InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+	for _, body := range bodies {
+		records.AppendEmpty().Body().SetStringVal(body)
+	}
+}
+
+func getBodies(logs pdata.Logs) []string {
+	bodies := []string{}
+	rls := logs.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		ills := rls.At(i).InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			records := ills.At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				bodies = append(bodies, records.At(k).Body().AsString())
+			}
+		}
+	}
+	return bodies
+}
+
+func TestProcessLogsTiers(t *testing.T) {
+	lsp := newTestProcessor(t, []TierConfig{
+		{Name: "critical", PriorityClasses: []string{"critical"}, Namespaces: []string{"kube-system"}, SamplingPercentage: 100},
+		{Name: "best-effort", PriorityClasses: []string{"best-effort"}, SamplingPercentage: 0},
+	})
+
+	logs := pdata.NewLogs()
+	appendResourceLogs(logs, "app", "critical", "critical pod")
+	appendResourceLogs(logs, "kube-system", "best-effort", "kube-system pod")
+	appendResourceLogs(logs, "app", "best-effort", "best-effort pod")
+	appendResourceLogs(logs, "app", "", "pod without priority class")
+
+	logs, err := lsp.ProcessLogs(context.Background(), logs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"critical pod", "kube-system pod", "pod without priority class"}, getBodies(logs))
+	assert.Equal(t, 3, logs.ResourceLogs().Len())
+}
+
+func TestProcessLogsSamplingPercentage(t *testing.T) {
+	lsp := newTestProcessor(t, []TierConfig{
+		{Name: "best-effort", PriorityClasses: []string{"best-effort"}, SamplingPercentage: 25},
+	})
+	randoms := []float64{0.1, 0.3, 0.24, 0.25, 0.9}
+	lsp.random = func() float64 {
+		r := randoms[0]
+		randoms = randoms[1:]
+		return r
+	}
+
+	logs := pdata.NewLogs()
+	appendResourceLogs(logs, "app", "best-effort", "first", "second", "third", "fourth", "fifth")
+
+	logs, err := lsp.ProcessLogs(context.Background(), logs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "third"}, getBodies(logs))
+}
+
+func TestProcessLogsDefaultSamplingPercentage(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DefaultSamplingPercentage = 0
+	cfg.Tiers = []TierConfig{
+		{Name: "critical", PriorityClasses: []string{"critical"}, SamplingPercentage: 100},
+	}
+	require.NoError(t, cfg.Validate())
+	lsp := newK8sLogSamplerProcessor(cfg)
+
+	logs := pdata.NewLogs()
+	appendResourceLogs(logs, "app", "critical", "critical pod")
+	appendResourceLogs(logs, "app", "low", "low priority pod")
+
+	logs, err := lsp.ProcessLogs(context.Background(), logs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"critical pod"}, getBodies(logs))
+}
+
+func TestProcessLogsAllSampledOut(t *testing.T) {
+	lsp := newTestProcessor(t, []TierConfig{
+		{Name: "dev", Namespaces: []string{"dev"}, SamplingPercentage: 0},
+	})
+
+	logs := pdata.NewLogs()
+	appendResourceLogs(logs, "dev", "", "first", "second")
+
+	_, err := lsp.ProcessLogs(context.Background(), logs)
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+}
@@ -35,6 +35,8 @@ type fakeClient struct {
 	Associations []kube.Association
 	Informer     cache.SharedInformer
 	StopCh       chan struct{}
+	NotSynced    bool
+	Stale        bool
 }
 
 func selectors() (labels.Selector, fields.Selector) {
@@ -51,11 +53,14 @@ func newFakeClient(
 	associations []kube.Association,
 	exclude kube.Excludes,
 	_ kube.APIClientsetProvider,
+	_ kube.DynamicClientProvider,
 	_ kube.InformerProvider,
 	_ kube.OwnerProvider,
 	_ string,
 	_ time.Duration,
 	_ time.Duration,
+	_ time.Duration,
+	_ kube.StaleCacheSettings,
 ) (kube.Client, error) {
 	cs := fake.NewSimpleClientset()
 
@@ -65,7 +70,7 @@ func newFakeClient(
 		Rules:        rules,
 		Filters:      filters,
 		Associations: associations,
-		Informer:     kube.NewFakeInformer(cs, "", ls, fs),
+		Informer:     kube.NewFakeInformer(cs, "", ls, fs, kube.DefaultResyncPeriod),
 		StopCh:       make(chan struct{}),
 	}, nil
 }
@@ -77,6 +82,16 @@ func (f *fakeClient) GetPod(identifier kube.PodIdentifier) (*kube.Pod, bool) {
 	return p, ok
 }
 
+// HasSynced returns false only when FakeClient.NotSynced is set.
+func (f *fakeClient) HasSynced() bool {
+	return !f.NotSynced
+}
+
+// IsStale returns FakeClient.Stale.
+func (f *fakeClient) IsStale() bool {
+	return f.Stale
+}
+
 // Start is a noop for FakeClient.
 func (f *fakeClient) Start() {
 	if f.Informer != nil {
@@ -15,6 +15,11 @@
 package k8sprocessor
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
 	"go.opentelemetry.io/collector/config"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
@@ -51,12 +56,165 @@ type Config struct {
 	// Exclude section allows to define names of pod that should be
 	// ignored while tagging.
 	Exclude ExcludeConfig `mapstructure:"exclude"`
+
+	// ResyncPeriod is the period after which the pod and owner informers
+	// re-list all the watched objects.
+	ResyncPeriod time.Duration `mapstructure:"resync_period"`
+
+	// WaitForCacheSync defines how the processor waits until the pod and owner caches
+	// are synced or CacheSyncTimeout elapses, so that data received right after start
+	// is not missing metadata: either by holding the incoming data (hold_data)
+	// or by blocking its start (block_start). As the receivers are started after
+	// the processors, then no data enters the pipeline before the metadata is available.
+	// By default this is none.
+	WaitForCacheSync CacheSyncMode `mapstructure:"wait_for_cache_sync"`
+
+	// CacheSyncTimeout is the maximum time to wait for the initial
+	// synchronization of the pod and owner caches.
+	CacheSyncTimeout time.Duration `mapstructure:"cache_sync_timeout"`
+
+	// StaleCache section allows serving the pod metadata from the last known
+	// cache when the Kubernetes API server is unreachable.
+	StaleCache StaleCacheConfig `mapstructure:"stale_cache"`
+
+	// KubeConfig section allows selecting the kubeconfig file and context used
+	// with the kubeConfig auth_type, e.g. when the collector runs outside of the cluster,
+	// and limiting the rate of the Kubernetes API requests.
+	KubeConfig KubeConfigConfig `mapstructure:"kube_config"`
+
+	// Clusters allows enriching the data coming from multiple clusters, e.g. in a gateway
+	// collector. Each cluster is connected with its own kubeconfig context and the cluster
+	// of the data is selected by the ClusterAttribute resource attribute.
+	Clusters []ClusterConfig `mapstructure:"clusters"`
+
+	// ClusterAttribute is the resource attribute with the name of the cluster the data comes from.
+	// It is only used when Clusters are configured.
+	ClusterAttribute string `mapstructure:"cluster_attribute"`
+}
+
+// KubeConfigConfig defines how the Kubernetes API is connected with the kubeconfig file.
+type KubeConfigConfig struct {
+	// Path is the path of the kubeconfig file. When empty, the KUBECONFIG environment
+	// variable or ~/.kube/config is used.
+	Path string `mapstructure:"path"`
+
+	// Context is the kubeconfig context used. When empty, the current context is used.
+	Context string `mapstructure:"context"`
+
+	// QPS is the maximum number of the Kubernetes API requests per second.
+	// When zero, the client-go default is used.
+	QPS float32 `mapstructure:"qps"`
+
+	// Burst is the maximum burst of the Kubernetes API requests.
+	// When zero, the client-go default is used.
+	Burst int `mapstructure:"burst"`
+}
+
+// ClusterConfig defines one of the clusters the data is enriched from.
+type ClusterConfig struct {
+	// Name is the value of the cluster attribute of the data coming from the cluster.
+	Name string `mapstructure:"name"`
+
+	KubeConfigConfig `mapstructure:",squash"`
+}
+
+// StaleCacheConfig defines how the processor behaves when the Kubernetes API server is unreachable.
+type StaleCacheConfig struct {
+	// Enabled makes the processor keep enriching the data from the last known cache
+	// while the API server is unreachable and reconnect with exponential backoff.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Attribute is the resource attribute set to true on the resources enriched
+	// while the API server is unreachable. When empty, no attribute is set.
+	Attribute string `mapstructure:"attribute"`
+
+	// InitialReconnectInterval is the delay of the first reconnect attempt.
+	// It's doubled after every failed attempt up to MaxReconnectInterval.
+	InitialReconnectInterval time.Duration `mapstructure:"initial_reconnect_interval"`
+
+	// MaxReconnectInterval is the maximum delay between the reconnect attempts.
+	MaxReconnectInterval time.Duration `mapstructure:"max_reconnect_interval"`
 }
 
 func (cfg *Config) Validate() error {
+	if cfg.ResyncPeriod < 0 {
+		return fmt.Errorf("resync_period cannot be negative: %s", cfg.ResyncPeriod)
+	}
+	switch cfg.WaitForCacheSync {
+	case "", CacheSyncModeNone, CacheSyncModeHoldData, CacheSyncModeBlockStart:
+	default:
+		return fmt.Errorf("unsupported wait_for_cache_sync: %s, it has to be one of: %s, %s, %s",
+			cfg.WaitForCacheSync, CacheSyncModeNone, CacheSyncModeHoldData, CacheSyncModeBlockStart)
+	}
+	if cfg.CacheSyncTimeout < 0 {
+		return fmt.Errorf("cache_sync_timeout cannot be negative: %s", cfg.CacheSyncTimeout)
+	}
+	for i, pod := range cfg.Exclude.Pods {
+		if err := pod.Validate(); err != nil {
+			return fmt.Errorf("exclude.pods %d has invalid configuration: %w", i, err)
+		}
+	}
+	if cfg.StaleCache.Enabled {
+		if cfg.StaleCache.InitialReconnectInterval <= 0 {
+			return fmt.Errorf("stale_cache.initial_reconnect_interval has to be positive: %s", cfg.StaleCache.InitialReconnectInterval)
+		}
+		if cfg.StaleCache.MaxReconnectInterval < cfg.StaleCache.InitialReconnectInterval {
+			return fmt.Errorf("stale_cache.max_reconnect_interval cannot be lower than initial_reconnect_interval: %s", cfg.StaleCache.MaxReconnectInterval)
+		}
+	}
+	if err := cfg.KubeConfig.Validate(); err != nil {
+		return fmt.Errorf("kube_config has invalid configuration: %w", err)
+	}
+	if (cfg.KubeConfig != KubeConfigConfig{} || len(cfg.Clusters) > 0) && cfg.AuthType != k8sconfig.AuthTypeKubeConfig {
+		return fmt.Errorf("kube_config and clusters require auth_type %s", k8sconfig.AuthTypeKubeConfig)
+	}
+	if cfg.KubeConfig != (KubeConfigConfig{}) && len(cfg.Clusters) > 0 {
+		return errors.New("kube_config cannot be used together with clusters")
+	}
+	if len(cfg.Clusters) > 0 && cfg.ClusterAttribute == "" {
+		return errors.New("cluster_attribute cannot be empty when clusters are configured")
+	}
+	names := make(map[string]bool, len(cfg.Clusters))
+	for i, cluster := range cfg.Clusters {
+		if cluster.Name == "" {
+			return fmt.Errorf("clusters %d has invalid configuration: name has to be specified", i)
+		}
+		if names[cluster.Name] {
+			return fmt.Errorf("clusters %d has invalid configuration: duplicated name %q", i, cluster.Name)
+		}
+		names[cluster.Name] = true
+		if err := cluster.KubeConfigConfig.Validate(); err != nil {
+			return fmt.Errorf("clusters %d has invalid configuration: %w", i, err)
+		}
+	}
 	return cfg.APIConfig.Validate()
 }
 
+// Validate checks if the kubeconfig configuration is valid
+func (cfg *KubeConfigConfig) Validate() error {
+	if cfg.QPS < 0 {
+		return fmt.Errorf("qps cannot be negative: %v", cfg.QPS)
+	}
+	if cfg.Burst < 0 {
+		return fmt.Errorf("burst cannot be negative: %d", cfg.Burst)
+	}
+	return nil
+}
+
+// Validate checks if the pod exclusion configuration is valid
+func (cfg *ExcludePodConfig) Validate() error {
+	if cfg.Name == "" && cfg.Namespace == "" {
+		return errors.New("name or namespace has to be specified")
+	}
+	if _, err := regexp.Compile(cfg.Name); err != nil {
+		return fmt.Errorf("invalid name regex %q: %w", cfg.Name, err)
+	}
+	if _, err := regexp.Compile(cfg.Namespace); err != nil {
+		return fmt.Errorf("invalid namespace regex %q: %w", cfg.Namespace, err)
+	}
+	return nil
+}
+
 // ExtractConfig section allows specifying extraction rules to extract
 // data from k8s pod specs.
 type ExtractConfig struct {
@@ -93,12 +251,53 @@ type ExtractConfig struct {
 	// documentation for more details.
 	NamespaceLabels []FieldExtractConfig `mapstructure:"namespace_labels"`
 
+	// CustomResources allows extracting data from custom resources (e.g. Argo Rollouts
+	// or Knative Services) found in the owner references of the pod and record it
+	// as resource attributes. It requires owner_lookup_enabled to be set.
+	// It is a list of CustomResourceConfig type. See CustomResourceConfig
+	// documentation for more details.
+	CustomResources []CustomResourceConfig `mapstructure:"custom_resources"`
+
 	// Delimiter is going to be used to join multiple values for metadata.
 	// For example if given pod is associated with more than one service,
 	// delimiter is going to separate them in string.
 	Delimiter string `mapstructure:"delimiter"`
 }
 
+// CustomResourceConfig allows specifying a custom resource which is watched with a dynamic informer
+// and looked up in the owners of the pods, e.g.
+//
+//	processors:
+//	  k8s-tagger:
+//	    owner_lookup_enabled: true
+//	    extract:
+//	      metadata:
+//	        - replicaSetName
+//	      custom_resources:
+//	        - group: argoproj.io
+//	          version: v1alpha1
+//	          resource: rollouts
+//	          kind: Rollout
+//	          name_tag_name: k8s.rollout.name
+//	          labels:
+//	            - tag_name: k8s.rollout.label.%s
+//	              key: "*"
+type CustomResourceConfig struct {
+	// Group is the API group of the custom resource, e.g. argoproj.io.
+	Group string `mapstructure:"group"`
+	// Version is the API version of the custom resource, e.g. v1alpha1.
+	Version string `mapstructure:"version"`
+	// Resource is the plural name of the custom resource in the API, e.g. rollouts.
+	Resource string `mapstructure:"resource"`
+	// Kind is the kind of the custom resource as it appears in the owner references, e.g. Rollout.
+	Kind string `mapstructure:"kind"`
+	// NameTagName is the name of the tag the name of the custom resource is recorded as.
+	// The name is not recorded when empty.
+	NameTagName string `mapstructure:"name_tag_name"`
+	// Labels allows extracting the labels of the custom resource, the same way as the pod labels.
+	Labels []FieldExtractConfig `mapstructure:"labels"`
+}
+
 //FieldExtractConfig allows specifying an extraction rule to extract a value from exactly one field.
 //
 // The field accepts a list FilterExtractConfig map. The map accepts three keys
@@ -143,11 +342,25 @@ type ExtractConfig struct {
 //      annotations:
 //        - tag_name: k8s.annotation/%s
 //          key: *
+//
+//- key_regex can be used instead of key to extract all the fields with names matching
+//  the regular expression. The whole name has to match. The tag_name is a template
+//  in which `$1`, `${name}` etc. are replaced with the submatches of the name,
+//  and `$0` with the whole name. For example:
+//
+//  procesors:
+//    k8s-tagger:
+//      labels:
+//        - tag_name: k8s.app.$1
+//          key_regex: app\.kubernetes\.io/(.*)
+//
+//  will add the `app.kubernetes.io/name` label as the `k8s.app.name` tag.
 
 type FieldExtractConfig struct {
-	TagName string `mapstructure:"tag_name"`
-	Key     string `mapstructure:"key"`
-	Regex   string `mapstructure:"regex"`
+	TagName  string `mapstructure:"tag_name"`
+	Key      string `mapstructure:"key"`
+	KeyRegex string `mapstructure:"key_regex"`
+	Regex    string `mapstructure:"regex"`
 }
 
 // FilterConfig section allows specifying filters to filter
@@ -181,6 +394,11 @@ type FilterConfig struct {
 	// Namespace filters all pods by the provided namespace. All other pods are ignored.
 	Namespace string `mapstructure:"namespace"`
 
+	// ExcludeNamespaces lists the namespaces whose pods are not watched at all.
+	// Unlike the exclude section, the pods are filtered out by the API server,
+	// so they don't take any memory of the processor.
+	ExcludeNamespaces []string `mapstructure:"exclude_namespaces"`
+
 	// Fields allows to filter pods by generic k8s fields.
 	// Only the following operations are supported:
 	//    - equals
@@ -232,12 +450,58 @@ type PodAssociationConfig struct {
 // DefaultDelimiter is default value for Delimiter for ExtractConfig
 const DefaultDelimiter string = ", "
 
+// DefaultStaleCacheAttribute is default value for Attribute for StaleCacheConfig
+const DefaultStaleCacheAttribute string = "k8s.metadata.stale"
+
+// DefaultClusterAttribute is default value for ClusterAttribute
+const DefaultClusterAttribute string = "k8s.cluster.name"
+
+// DefaultCacheSyncTimeout is default value for CacheSyncTimeout
+const DefaultCacheSyncTimeout time.Duration = 10 * time.Second
+
+// CacheSyncMode defines how the processor waits for the initial sync of the pod and owner caches
+type CacheSyncMode string
+
+const (
+	// CacheSyncModeNone doesn't wait for the caches to sync
+	CacheSyncModeNone CacheSyncMode = "none"
+	// CacheSyncModeHoldData holds the incoming data until the caches are synced
+	CacheSyncModeHoldData CacheSyncMode = "hold_data"
+	// CacheSyncModeBlockStart blocks the start of the processor, and so of the pipeline,
+	// until the caches are synced
+	CacheSyncModeBlockStart CacheSyncMode = "block_start"
+)
+
 // ExcludeConfig represent a list of Pods to exclude
 type ExcludeConfig struct {
 	Pods []ExcludePodConfig `mapstructure:"pods"`
+
+	// CollectorPods allows excluding the pod the collector runs in
+	// and the other pods of its controller, e.g. of the collector's DaemonSet.
+	CollectorPods CollectorPodsConfig `mapstructure:"collector_pods"`
 }
 
 // ExcludePodConfig represent a Pod name to ignore
 type ExcludePodConfig struct {
+	// Name is the regex matched against the pod name.
 	Name string `mapstructure:"name"`
+	// Namespace is the regex matched against the pod namespace.
+	// When both Name and Namespace are set, both of them have to match.
+	Namespace string `mapstructure:"namespace"`
+}
+
+// CollectorPodsConfig defines how the pod the collector runs in is found.
+type CollectorPodsConfig struct {
+	// Enabled makes the processor ignore the collector's own pods.
+	Enabled bool `mapstructure:"enabled"`
+
+	// PodNameFromEnvVar is the name of the environment variable with the name
+	// of the collector's pod, e.g. set with the downward API (metadata.name).
+	// When empty, the hostname is used, which is the pod name unless the pod uses the host network.
+	PodNameFromEnvVar string `mapstructure:"pod_name_from_env_var"`
+
+	// NamespaceFromEnvVar is the name of the environment variable with the namespace
+	// of the collector's pod, e.g. set with the downward API (metadata.namespace).
+	// When empty, the namespace of the pod's service account is used.
+	NamespaceFromEnvVar string `mapstructure:"namespace_from_env_var"`
 }
@@ -17,6 +17,7 @@ package k8sprocessor
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -49,6 +50,15 @@ func TestLoadConfig(t *testing.T) {
 			ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
 			APIConfig:         k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
 			Extract:           ExtractConfig{Delimiter: ", "},
+			ResyncPeriod:      5 * time.Minute,
+			WaitForCacheSync:  CacheSyncModeNone,
+			CacheSyncTimeout:  10 * time.Second,
+			ClusterAttribute:  "k8s.cluster.name",
+			StaleCache: StaleCacheConfig{
+				Attribute:                "k8s.metadata.stale",
+				InitialReconnectInterval: time.Second,
+				MaxReconnectInterval:     time.Minute,
+			},
 		},
 		p0,
 	)
@@ -87,9 +97,10 @@ func TestLoadConfig(t *testing.T) {
 				Delimiter: ", ",
 			},
 			Filter: FilterConfig{
-				Namespace:      "ns2",
-				Node:           "ip-111.us-west-2.compute.internal",
-				NodeFromEnvVar: "K8S_NODE",
+				Namespace:         "ns2",
+				ExcludeNamespaces: []string{"kube-system"},
+				Node:              "ip-111.us-west-2.compute.internal",
+				NodeFromEnvVar:    "K8S_NODE",
 				Labels: []FieldFilterConfig{
 					{Key: "key1", Value: "value1"},
 					{Key: "key2", Value: "value2", Op: "not-equals"},
@@ -125,9 +136,132 @@ func TestLoadConfig(t *testing.T) {
 				Pods: []ExcludePodConfig{
 					{Name: "jaeger-agent"},
 					{Name: "jaeger-collector"},
+					{Namespace: "^kube-"},
+				},
+				CollectorPods: CollectorPodsConfig{
+					Enabled:           true,
+					PodNameFromEnvVar: "POD_NAME",
 				},
 			},
+			ResyncPeriod:     time.Minute,
+			WaitForCacheSync: CacheSyncModeBlockStart,
+			CacheSyncTimeout: 30 * time.Second,
+			StaleCache: StaleCacheConfig{
+				Enabled:                  true,
+				Attribute:                "k8s.cache.stale",
+				InitialReconnectInterval: 2 * time.Second,
+				MaxReconnectInterval:     5 * time.Minute,
+			},
+			KubeConfig: KubeConfigConfig{
+				Path:    "/etc/otelcol/kubeconfig",
+				Context: "prod-admin",
+				QPS:     20,
+				Burst:   40,
+			},
+			ClusterAttribute: "k8s.cluster.name",
 		},
 		p1,
 	)
+
+	p2 := cfg.Processors[config.NewComponentIDWithName(typeStr, "3")]
+	assert.EqualValues(t,
+		&Config{
+			ProcessorSettings: config.NewProcessorSettings(config.NewComponentIDWithName(typeStr, "3")),
+			APIConfig:         k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
+			Extract:           ExtractConfig{Delimiter: ", "},
+			ResyncPeriod:      5 * time.Minute,
+			WaitForCacheSync:  CacheSyncModeNone,
+			CacheSyncTimeout:  10 * time.Second,
+			StaleCache: StaleCacheConfig{
+				Attribute:                "k8s.metadata.stale",
+				InitialReconnectInterval: time.Second,
+				MaxReconnectInterval:     time.Minute,
+			},
+			ClusterAttribute: "cluster",
+			Clusters: []ClusterConfig{
+				{
+					Name: "prod",
+					KubeConfigConfig: KubeConfigConfig{
+						Path:    "/etc/otelcol/kubeconfig",
+						Context: "prod-admin",
+						QPS:     50,
+						Burst:   100,
+					},
+				},
+				{
+					Name: "staging",
+					KubeConfigConfig: KubeConfigConfig{
+						Path:    "/etc/otelcol/kubeconfig",
+						Context: "staging-admin",
+					},
+				},
+			},
+		},
+		p2,
+	)
+}
+
+func TestWaitForCacheSyncConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	for _, mode := range []CacheSyncMode{"", CacheSyncModeNone, CacheSyncModeHoldData, CacheSyncModeBlockStart} {
+		cfg.WaitForCacheSync = mode
+		assert.NoError(t, cfg.Validate())
+	}
+
+	cfg.WaitForCacheSync = "true"
+	assert.EqualError(t, cfg.Validate(), "unsupported wait_for_cache_sync: true, it has to be one of: none, hold_data, block_start")
+}
+
+func TestStaleCacheConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.StaleCache.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.StaleCache.MaxReconnectInterval = 500 * time.Millisecond
+	assert.EqualError(t, cfg.Validate(), "stale_cache.max_reconnect_interval cannot be lower than initial_reconnect_interval: 500ms")
+
+	cfg.StaleCache.InitialReconnectInterval = 0
+	assert.EqualError(t, cfg.Validate(), "stale_cache.initial_reconnect_interval has to be positive: 0s")
+}
+
+func TestExcludeConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Exclude.Pods = []ExcludePodConfig{{Namespace: "^kube-"}}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Exclude.Pods = []ExcludePodConfig{{Namespace: "^kube-"}, {}}
+	assert.EqualError(t, cfg.Validate(), "exclude.pods 1 has invalid configuration: name or namespace has to be specified")
+
+	cfg.Exclude.Pods = []ExcludePodConfig{{Name: "["}}
+	assert.EqualError(t, cfg.Validate(), "exclude.pods 0 has invalid configuration: invalid name regex \"[\": error parsing regexp: missing closing ]: `[`")
+}
+
+func TestClustersConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Clusters = []ClusterConfig{{Name: "prod"}}
+	assert.EqualError(t, cfg.Validate(), "kube_config and clusters require auth_type kubeConfig")
+
+	cfg.AuthType = k8sconfig.AuthTypeKubeConfig
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Clusters = []ClusterConfig{{Name: "prod"}, {}}
+	assert.EqualError(t, cfg.Validate(), "clusters 1 has invalid configuration: name has to be specified")
+
+	cfg.Clusters = []ClusterConfig{{Name: "prod"}, {Name: "prod"}}
+	assert.EqualError(t, cfg.Validate(), `clusters 1 has invalid configuration: duplicated name "prod"`)
+
+	cfg.Clusters = []ClusterConfig{{Name: "prod", KubeConfigConfig: KubeConfigConfig{QPS: -1}}}
+	assert.EqualError(t, cfg.Validate(), "clusters 0 has invalid configuration: qps cannot be negative: -1")
+
+	cfg.Clusters = []ClusterConfig{{Name: "prod"}}
+	cfg.ClusterAttribute = ""
+	assert.EqualError(t, cfg.Validate(), "cluster_attribute cannot be empty when clusters are configured")
+
+	cfg.ClusterAttribute = DefaultClusterAttribute
+	cfg.KubeConfig.Context = "prod-admin"
+	assert.EqualError(t, cfg.Validate(), "kube_config cannot be used together with clusters")
+
+	cfg.Clusters = nil
+	cfg.KubeConfig.Burst = -5
+	assert.EqualError(t, cfg.Validate(), "kube_config has invalid configuration: burst cannot be negative: -5")
 }
@@ -20,6 +20,8 @@
 // The rules for associating the data passing through the processor (spans, metrics and logs)
 // with specific Pod Metadata are configured via "pod_association" key.
 // It represents a list of rules that are executed in the specified order until the first one is able to do the match.
+// A rule matches when it produces a value identifying a Pod known to the processor, so e.g. telemetry sent by sidecars
+// carrying k8s.pod.uid and telemetry which only has pod name and namespace can both be enriched by a single processor.
 // Each rule is specified as a pair of from (representing the rule type) and name (representing the extracted key name).
 // Following rule types are available:
 //   from: "resource_attribute" - allows to specify the attribute name to lookup up in the list of attributes of the received Resource.
@@ -42,6 +44,8 @@
 //    name: ip
 //  - from: resource_attribute
 //    name: k8s.pod.uid
+//  - from: build_hostname
+//    name: k8s.pod.hostname
 //
 // If Pod association rules are not configured resources are associated with metadata only by connection's IP Address.
 //
@@ -52,6 +52,15 @@ func createDefaultConfig() config.Processor {
 		Extract: ExtractConfig{
 			Delimiter: DefaultDelimiter,
 		},
+		ResyncPeriod:     kube.DefaultResyncPeriod,
+		WaitForCacheSync: CacheSyncModeNone,
+		CacheSyncTimeout: DefaultCacheSyncTimeout,
+		ClusterAttribute: DefaultClusterAttribute,
+		StaleCache: StaleCacheConfig{
+			Attribute:                DefaultStaleCacheAttribute,
+			InitialReconnectInterval: kube.DefaultInitialReconnectInterval,
+			MaxReconnectInterval:     kube.DefaultMaxReconnectInterval,
+		},
 	}
 }
 
@@ -161,7 +170,7 @@ func createKubernetesProcessor(
 	}
 
 	// This might have been set by an option already
-	if kp.kc == nil {
+	if kp.kc == nil && len(kp.clusterClients) == 0 {
 		err := kp.initKubeClient(kp.logger, kubeClientProvider)
 		if err != nil {
 			return nil, err
@@ -183,6 +192,7 @@ func createProcessorOpts(cfg config.Processor) []Option {
 	opts = append(opts, WithExtractLabels(oCfg.Extract.Labels...))
 	opts = append(opts, WithExtractNamespaceLabels(oCfg.Extract.NamespaceLabels...))
 	opts = append(opts, WithExtractAnnotations(oCfg.Extract.Annotations...))
+	opts = append(opts, WithExtractCustomResources(oCfg.Extract.CustomResources...))
 	opts = append(opts, WithExtractTags(oCfg.Extract.Tags))
 
 	if oCfg.OwnerLookupEnabled {
@@ -192,6 +202,7 @@ func createProcessorOpts(cfg config.Processor) []Option {
 	// filters
 	opts = append(opts, WithFilterNode(oCfg.Filter.Node, oCfg.Filter.NodeFromEnvVar))
 	opts = append(opts, WithFilterNamespace(oCfg.Filter.Namespace))
+	opts = append(opts, WithFilterExcludeNamespaces(oCfg.Filter.ExcludeNamespaces...))
 	opts = append(opts, WithFilterLabels(oCfg.Filter.Labels...))
 	opts = append(opts, WithFilterFields(oCfg.Filter.Fields...))
 	opts = append(opts, WithAPIConfig(oCfg.APIConfig))
@@ -202,5 +213,17 @@ func createProcessorOpts(cfg config.Processor) []Option {
 
 	opts = append(opts, WithExcludes(oCfg.Exclude))
 
+	opts = append(opts, WithResyncPeriod(oCfg.ResyncPeriod))
+	opts = append(opts, WithWaitForCacheSync(oCfg.WaitForCacheSync, oCfg.CacheSyncTimeout))
+	if oCfg.StaleCache.Enabled {
+		opts = append(opts, WithStaleCache(oCfg.StaleCache))
+	}
+	if oCfg.KubeConfig != (KubeConfigConfig{}) {
+		opts = append(opts, WithKubeConfig(oCfg.KubeConfig))
+	}
+	if len(oCfg.Clusters) > 0 {
+		opts = append(opts, WithClusters(oCfg.ClusterAttribute, oCfg.Clusters...))
+	}
+
 	return opts
 }
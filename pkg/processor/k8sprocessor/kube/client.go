@@ -15,6 +15,7 @@
 package kube
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -22,9 +23,12 @@ import (
 
 	"go.uber.org/zap"
 	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
@@ -32,6 +36,9 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sprocessor/observability"
 )
 
+// collectorPodLookupTimeout limits the time of getting the collector's pod from the API server
+const collectorPodLookupTimeout = 10 * time.Second
+
 // WatchClient is the main interface provided by this package to a kubernetes cluster.
 type WatchClient struct {
 	m           sync.RWMutex
@@ -43,6 +50,10 @@ type WatchClient struct {
 	stopCh      chan struct{}
 	op          OwnerAPI
 	delimiter   string
+	staleCache  StaleCacheSettings
+	// stale is set to 1 while the API server is unreachable
+	stale       int32
+	reconnectCh chan struct{}
 
 	// A map containing Pod related data, used to associate them with resources.
 	// Key can be either an IP address or Pod UID
@@ -51,6 +62,10 @@ type WatchClient struct {
 	Filters      Filters
 	Associations []Association
 	Exclude      Excludes
+
+	// collectorControllerUID is the UID of the controller of the collector's pod,
+	// empty when the collector pods are not excluded or the pod has no controller
+	collectorControllerUID types.UID
 }
 
 // New initializes a new k8s Client.
@@ -62,11 +77,14 @@ func New(
 	associations []Association,
 	exclude Excludes,
 	newClientSet APIClientsetProvider,
+	newDynamicClient DynamicClientProvider,
 	newInformer InformerProvider,
 	newOwnerProviderFunc OwnerProvider,
 	delimiter string,
 	deleteInterval time.Duration,
 	gracePeriod time.Duration,
+	resyncPeriod time.Duration,
+	staleCache StaleCacheSettings,
 ) (Client, error) {
 	c := &WatchClient{
 		logger:       logger,
@@ -76,6 +94,8 @@ func New(
 		Exclude:      exclude,
 		stopCh:       make(chan struct{}),
 		delimiter:    delimiter,
+		staleCache:   staleCache,
+		reconnectCh:  make(chan struct{}, 1),
 		Pods:         map[PodIdentifier]*Pod{},
 	}
 	go c.deleteLoop(deleteInterval, gracePeriod)
@@ -90,6 +110,10 @@ func New(
 	}
 	c.kc = kc
 
+	if c.Exclude.CollectorPod != nil {
+		c.resolveCollectorController()
+	}
+
 	labelSelector, fieldSelector, err := selectorsFromFilters(c.Filters)
 	if err != nil {
 		return nil, err
@@ -100,7 +124,19 @@ func New(
 			newOwnerProviderFunc = newOwnerProvider
 		}
 
-		c.op, err = newOwnerProviderFunc(logger, c.kc, labelSelector, fieldSelector, rules, c.Filters.Namespace)
+		// the dynamic client is only needed to watch the custom resources
+		var dc dynamic.Interface
+		if len(c.Rules.CustomResources) > 0 {
+			if newDynamicClient == nil {
+				newDynamicClient = makeDynamicClient
+			}
+			dc, err = newDynamicClient(apiCfg)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		c.op, err = newOwnerProviderFunc(logger, c.kc, dc, labelSelector, fieldSelector, rules, c.Filters.Namespace, resyncPeriod)
 		if err != nil {
 			return nil, err
 		}
@@ -115,7 +151,7 @@ func New(
 		newInformer = newSharedInformer
 	}
 
-	c.informer = newInformer(c.kc, c.Filters.Namespace, labelSelector, fieldSelector)
+	c.informer = newInformer(c.kc, c.Filters.Namespace, labelSelector, fieldSelector, resyncPeriod)
 	return c, err
 }
 
@@ -130,6 +166,12 @@ func (c *WatchClient) Start() {
 		UpdateFunc: c.handlePodUpdate,
 		DeleteFunc: c.handlePodDelete,
 	})
+	if c.staleCache.Enabled {
+		if err := c.informer.SetWatchErrorHandler(c.handleWatchError); err != nil {
+			c.logger.Error("Failed to set the watch error handler of the pod informer", zap.Error(err))
+		}
+		go c.reconnectLoop()
+	}
 	c.informer.Run(c.stopCh)
 }
 
@@ -142,7 +184,21 @@ func (c *WatchClient) Stop() {
 	}
 }
 
+// HasSynced returns true when the pod informer and, if owner lookup is enabled,
+// all the owner informers have synced their caches.
+func (c *WatchClient) HasSynced() bool {
+	if !c.informer.HasSynced() {
+		return false
+	}
+	if c.op != nil {
+		return c.op.HasSynced()
+	}
+	return true
+}
+
 func (c *WatchClient) handlePodAdd(obj interface{}) {
+	// receiving the pod events means the API server is reachable
+	c.markFresh()
 	observability.RecordPodAdded()
 	if pod, ok := obj.(*api_v1.Pod); ok {
 		c.addOrUpdatePod(pod)
@@ -156,6 +212,8 @@ func (c *WatchClient) handlePodAdd(obj interface{}) {
 }
 
 func (c *WatchClient) handlePodUpdate(old, new interface{}) {
+	// receiving the pod events means the API server is reachable
+	c.markFresh()
 	observability.RecordPodUpdated()
 	if pod, ok := new.(*api_v1.Pod); ok {
 		// TODO: update or remove based on whether container is ready/unready?.
@@ -170,6 +228,8 @@ func (c *WatchClient) handlePodUpdate(old, new interface{}) {
 }
 
 func (c *WatchClient) handlePodDelete(obj interface{}) {
+	// receiving the pod events means the API server is reachable
+	c.markFresh()
 	observability.RecordPodDeleted()
 	if pod, ok := obj.(*api_v1.Pod); ok {
 		c.forgetPod(pod)
@@ -319,7 +379,7 @@ func (c *WatchClient) extractPodAttributes(pod *api_v1.Pod) map[string]string {
 				}
 
 			default:
-				// Do nothing
+				c.extractCustomResourceTags(owner, tags)
 			}
 		}
 
@@ -373,7 +433,17 @@ func (c *WatchClient) extractPodAttributes(pod *api_v1.Pod) map[string]string {
 }
 
 func (c *WatchClient) extractLabelsIntoTags(r FieldExtractionRule, labels map[string]string, tags map[string]string) {
-	if r.Key == "*" {
+	if r.KeyRegex != nil {
+		// Extract the matching labels, renamed with the submatches of their names
+		for label, value := range labels {
+			match := r.KeyRegex.FindStringSubmatchIndex(label)
+			if match == nil {
+				continue
+			}
+			name := r.KeyRegex.ExpandString(nil, r.Name, label, match)
+			tags[string(name)] = c.extractField(value, r)
+		}
+	} else if r.Key == "*" {
 		// Special case, extract everything
 		for label, value := range labels {
 			tags[fmt.Sprintf(r.Name, label)] = c.extractField(value, r)
@@ -494,12 +564,65 @@ func (c *WatchClient) shouldIgnorePod(pod *api_v1.Pod) bool {
 
 	// Check if user requested the pod to be ignored through configuration
 	for _, excludedPod := range c.Exclude.Pods {
-		if excludedPod.Name.MatchString(pod.Name) {
+		if excludedPod.matches(pod) {
 			return true
 		}
 	}
 
-	return false
+	return c.isCollectorPod(pod)
+}
+
+func (e ExcludePods) matches(pod *api_v1.Pod) bool {
+	if e.Name != nil && !e.Name.MatchString(pod.Name) {
+		return false
+	}
+	if e.Namespace != nil && !e.Namespace.MatchString(pod.Namespace) {
+		return false
+	}
+	return true
+}
+
+// resolveCollectorController looks up the controller of the collector's pod,
+// so the other pods of the controller are ignored as well.
+func (c *WatchClient) resolveCollectorController() {
+	collectorPod := c.Exclude.CollectorPod
+
+	ctx, cancel := context.WithTimeout(context.Background(), collectorPodLookupTimeout)
+	defer cancel()
+
+	pod, err := c.kc.CoreV1().Pods(collectorPod.Namespace).Get(ctx, collectorPod.Name, meta_v1.GetOptions{})
+	if err != nil {
+		c.logger.Warn("Failed to get the collector's pod, only the pod itself is ignored",
+			zap.String("pod", collectorPod.Name),
+			zap.String("namespace", collectorPod.Namespace),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if controller := meta_v1.GetControllerOf(pod); controller != nil {
+		c.collectorControllerUID = controller.UID
+		c.logger.Info("Ignoring the collector's pods",
+			zap.String("controller_kind", controller.Kind),
+			zap.String("controller_name", controller.Name),
+		)
+	}
+}
+
+// isCollectorPod checks if the pod is the collector's pod or another pod of its controller
+func (c *WatchClient) isCollectorPod(pod *api_v1.Pod) bool {
+	collectorPod := c.Exclude.CollectorPod
+	if collectorPod == nil || pod.Namespace != collectorPod.Namespace {
+		return false
+	}
+	if pod.Name == collectorPod.Name {
+		return true
+	}
+	if c.collectorControllerUID == "" {
+		return false
+	}
+	controller := meta_v1.GetControllerOf(pod)
+	return controller != nil && controller.UID == c.collectorControllerUID
 }
 
 func selectorsFromFilters(filters Filters) (labels.Selector, fields.Selector, error) {
@@ -527,5 +650,8 @@ func selectorsFromFilters(filters Filters) (labels.Selector, fields.Selector, er
 	if filters.Node != "" {
 		selectors = append(selectors, fields.OneTermEqualSelector(podNodeField, filters.Node))
 	}
+	for _, ns := range filters.ExcludeNamespaces {
+		selectors = append(selectors, fields.OneTermNotEqualSelector(podNamespaceField, ns))
+	}
 	return labelSelector, fields.AndSelectors(selectors...), nil
 }
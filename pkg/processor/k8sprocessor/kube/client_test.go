@@ -98,9 +98,12 @@ func TestDefaultClientset(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		"",
 		30*time.Second,
 		DefaultPodDeleteGracePeriod,
+		DefaultResyncPeriod,
+		StaleCacheSettings{},
 	)
 	assert.Error(t, err)
 	assert.Equal(t, "invalid authType for kubernetes: ", err.Error())
@@ -116,9 +119,12 @@ func TestDefaultClientset(t *testing.T) {
 		newFakeAPIClientset,
 		nil,
 		nil,
+		nil,
 		"",
 		30*time.Second,
 		DefaultPodDeleteGracePeriod,
+		DefaultResyncPeriod,
+		StaleCacheSettings{},
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, c)
@@ -133,11 +139,14 @@ func TestBadFilters(t *testing.T) {
 		[]Association{},
 		Excludes{},
 		newFakeAPIClientset,
+		nil,
 		NewFakeInformer,
 		newFakeOwnerProvider,
 		"",
 		30*time.Second,
 		DefaultPodDeleteGracePeriod,
+		DefaultResyncPeriod,
+		StaleCacheSettings{},
 	)
 	assert.Error(t, err)
 	assert.Nil(t, c)
@@ -181,11 +190,14 @@ func TestConstructorErrors(t *testing.T) {
 			[]Association{},
 			Excludes{},
 			clientProvider,
+			nil,
 			NewFakeInformer,
 			newFakeOwnerProvider,
 			"",
 			30*time.Second,
 			DefaultPodDeleteGracePeriod,
+			DefaultResyncPeriod,
+			StaleCacheSettings{},
 		)
 		assert.Nil(t, c)
 		assert.Error(t, err)
@@ -616,6 +628,31 @@ func TestExtractionRules(t *testing.T) {
 				"k8s.cronjob.name": "hello-cronjob",
 			},
 		},
+		{
+			name: "custom resource name and labels are added properly",
+			podOwner: &meta_v1.OwnerReference{
+				Kind: "Rollout",
+				Name: "canary-rollout",
+				UID:  "c5b35a1e-0a6c-4bd5-8a57-4d4a0e0a2f3c",
+			},
+			rules: ExtractionRules{
+				OwnerLookupEnabled: true,
+				Tags:               NewExtractionFieldTags(),
+				CustomResources: []CustomResourceRule{
+					{
+						Kind:    "Rollout",
+						NameTag: "k8s.rollout.name",
+						Labels: []FieldExtractionRule{
+							{Name: "k8s.rollout.label.%s", Key: "*"},
+						},
+					},
+				},
+			},
+			attributes: map[string]string{
+				"k8s.rollout.name":       "canary-rollout",
+				"k8s.rollout.label.team": "payments",
+			},
+		},
 		{
 			name: "metadata",
 			podOwner: &meta_v1.OwnerReference{
@@ -714,6 +751,27 @@ func TestExtractionRules(t *testing.T) {
 				"a1": "av1",
 			},
 		},
+		{
+			name: "labels-with-key-regex",
+			rules: ExtractionRules{
+				Labels: []FieldExtractionRule{
+					{
+						Name:     "k8s.app.$1",
+						KeyRegex: regexp.MustCompile(`^(?:label(\d))$`),
+					},
+					{
+						Name:     "k8s.pod.label.$0",
+						KeyRegex: regexp.MustCompile(`^(?:label2)$`),
+						Regex:    regexp.MustCompile(`k1=(?P<value>[^\s]+)`),
+					},
+				},
+			},
+			attributes: map[string]string{
+				"k8s.app.1":            "lv1",
+				"k8s.app.2":            "k1=v1 k5=v5 extra!",
+				"k8s.pod.label.label2": "v1",
+			},
+		},
 		{
 			name: "generic-labels",
 			rules: ExtractionRules{
@@ -821,6 +879,13 @@ func TestFilters(t *testing.T) {
 			},
 			labels: "k1=v1,k2!=v2",
 			fields: "k1=v1,k2!=v2",
+		}, {
+			name: "exclude-namespaces",
+			filters: Filters{
+				Node:              "ec2-test",
+				ExcludeNamespaces: []string{"kube-system", "monitoring"},
+			},
+			fields: "spec.nodeName=ec2-test,metadata.namespace!=kube-system,metadata.namespace!=monitoring",
 		},
 	}
 
@@ -925,6 +990,87 @@ func TestPodIgnorePatterns(t *testing.T) {
 	}
 }
 
+func TestPodIgnoreExcludes(t *testing.T) {
+	controller := true
+	collectorOwner := meta_v1.OwnerReference{Kind: "DaemonSet", Name: "otelcol", UID: "otelcol-ds-uid", Controller: &controller}
+	collectorPod := &api_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "otelcol-abcde",
+			Namespace:       "sumologic",
+			OwnerReferences: []meta_v1.OwnerReference{collectorOwner},
+		},
+	}
+
+	c, err := New(
+		zap.NewNop(),
+		k8sconfig.APIConfig{},
+		ExtractionRules{},
+		Filters{},
+		[]Association{},
+		Excludes{
+			Pods: []ExcludePods{
+				{Namespace: regexp.MustCompile(`^kube-.*`)},
+				{Name: regexp.MustCompile(`^debug-`), Namespace: regexp.MustCompile(`^default$`)},
+			},
+			CollectorPod: &CollectorPod{Name: "otelcol-abcde", Namespace: "sumologic"},
+		},
+		func(_ k8sconfig.APIConfig) (kubernetes.Interface, error) {
+			return fake.NewSimpleClientset(collectorPod), nil
+		},
+		nil,
+		NewFakeInformer,
+		newFakeOwnerProvider,
+		"_",
+		30*time.Second,
+		DefaultPodDeleteGracePeriod,
+		DefaultResyncPeriod,
+		StaleCacheSettings{},
+	)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		ignore bool
+		pod    *api_v1.Pod
+	}{
+		{
+			name:   "excluded namespace",
+			ignore: true,
+			pod:    &api_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "coredns", Namespace: "kube-system"}},
+		}, {
+			name:   "excluded name in namespace",
+			ignore: true,
+			pod:    &api_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "debug-shell", Namespace: "default"}},
+		}, {
+			name:   "excluded name in other namespace",
+			ignore: false,
+			pod:    &api_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "debug-shell", Namespace: "test"}},
+		}, {
+			name:   "collector pod",
+			ignore: true,
+			pod:    collectorPod,
+		}, {
+			name:   "other pod of collector's controller",
+			ignore: true,
+			pod: &api_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{
+				Name:            "otelcol-fghij",
+				Namespace:       "sumologic",
+				OwnerReferences: []meta_v1.OwnerReference{collectorOwner},
+			}},
+		}, {
+			name:   "other pod in collector's namespace",
+			ignore: false,
+			pod:    &api_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "fluentd-abcde", Namespace: "sumologic"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.ignore, c.(*WatchClient).shouldIgnorePod(tc.pod))
+		})
+	}
+}
+
 func Test_extractField(t *testing.T) {
 	c := WatchClient{}
 	type args struct {
@@ -1040,11 +1186,14 @@ func Test_PodsGetAddedAndDeletedFromCache(t *testing.T) {
 		[]Association{},
 		Excludes{},
 		newFakeAPIClientset,
+		nil,
 		newSharedInformer,
 		newOwnerProvider,
 		"_",
 		10*time.Millisecond,
 		10*time.Millisecond,
+		DefaultResyncPeriod,
+		StaleCacheSettings{},
 	)
 	require.NoError(t, err)
 
@@ -1127,11 +1276,14 @@ func newTestClientWithRulesAndFilters(t *testing.T, e ExtractionRules, f Filters
 		[]Association{},
 		exclude,
 		newFakeAPIClientset,
+		nil,
 		NewFakeInformer,
 		newFakeOwnerProvider,
 		"_",
 		30*time.Second,
 		DefaultPodDeleteGracePeriod,
+		DefaultResyncPeriod,
+		StaleCacheSettings{},
 	)
 	require.NoError(t, err)
 	return c.(*WatchClient), logs
@@ -0,0 +1,124 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+)
+
+// DynamicClientProvider defines a func type that initializes and returns a new kubernetes
+// dynamic client, used to watch the custom resources.
+type DynamicClientProvider func(config k8sconfig.APIConfig) (dynamic.Interface, error)
+
+func makeDynamicClient(apiConf k8sconfig.APIConfig) (dynamic.Interface, error) {
+	restConfig, err := createRestConfig(apiConf)
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(restConfig)
+}
+
+// CustomResourceRule defines a custom resource (e.g. Argo Rollout or Knative Service)
+// which is looked up in the owners of the pods, and the metadata extracted from it.
+type CustomResourceRule struct {
+	// Resource identifies the custom resource in the API, e.g. argoproj.io/v1alpha1, Resource=rollouts.
+	Resource schema.GroupVersionResource
+	// Kind is the kind of the custom resource as it appears in the owner references, e.g. Rollout.
+	Kind string
+	// NameTag is the tag the name of the custom resource is extracted into. Not extracted when empty.
+	NameTag string
+	// Labels are the rules extracting the labels of the custom resource.
+	Labels []FieldExtractionRule
+}
+
+// addCustomResourceInformers adds the informers of the custom resources which are available in the cluster.
+func (op *OwnerCache) addCustomResourceInformers(
+	client kubernetes.Interface,
+	dynamicClient dynamic.Interface,
+	rules []CustomResourceRule,
+	namespace string,
+	resyncPeriod time.Duration,
+) {
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, resyncPeriod, namespace, nil)
+
+	for _, rule := range rules {
+		if !isResourceAvailable(client, rule.Resource) {
+			// the informer would never sync, so the pods would be waiting for it forever
+			op.logger.Warn("custom resource is not available in the cluster, its metadata won't be extracted",
+				zap.String("resource", rule.Resource.String()),
+			)
+			continue
+		}
+
+		op.logger.Debug("adding informer for custom resource",
+			zap.String("kind", rule.Kind),
+			zap.String("api_version", rule.Resource.GroupVersion().String()),
+		)
+		op.addOwnerInformer(rule.Kind,
+			factory.ForResource(rule.Resource).Informer(),
+			op.cacheObjectWithLabels,
+			op.deleteObject)
+	}
+}
+
+// isResourceAvailable checks whether the API server serves the resource
+func isResourceAvailable(client kubernetes.Interface, resource schema.GroupVersionResource) bool {
+	resources, err := client.Discovery().ServerResourcesForGroupVersion(resource.GroupVersion().String())
+	if err != nil {
+		return false
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == resource.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheObjectWithLabels caches the object along with its labels, so they can be extracted
+func (op *OwnerCache) cacheObjectWithLabels(kind string, obj interface{}) {
+	meta := obj.(meta_v1.Object)
+	oo := newObjectOwner(kind, meta)
+	oo.labels = meta.GetLabels()
+
+	op.ownersMutex.Lock()
+	op.objectOwners[string(oo.UID)] = oo
+	op.ownersMutex.Unlock()
+}
+
+// extractCustomResourceTags extracts the metadata of the owner which is one of the custom resources
+func (c *WatchClient) extractCustomResourceTags(owner *ObjectOwner, tags map[string]string) {
+	for _, rule := range c.Rules.CustomResources {
+		if rule.Kind != owner.kind {
+			continue
+		}
+
+		if rule.NameTag != "" {
+			tags[rule.NameTag] = owner.name
+		}
+		for _, r := range rule.Labels {
+			c.extractLabelsIntoTags(r, owner.labels, tags)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIsResourceAvailable(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.Fake.Resources = []*meta_v1.APIResourceList{
+		{
+			GroupVersion: "argoproj.io/v1alpha1",
+			APIResources: []meta_v1.APIResource{
+				{Name: "rollouts", Kind: "Rollout"},
+			},
+		},
+	}
+
+	assert.True(t, isResourceAvailable(client, schema.GroupVersionResource{
+		Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts",
+	}))
+	assert.False(t, isResourceAvailable(client, schema.GroupVersionResource{
+		Group: "argoproj.io", Version: "v1alpha1", Resource: "analysisruns",
+	}))
+	assert.False(t, isResourceAvailable(client, schema.GroupVersionResource{
+		Group: "serving.knative.dev", Version: "v1", Resource: "services",
+	}))
+}
+
+func TestCacheObjectWithLabels(t *testing.T) {
+	op := newOwnerCache(zap.NewNop())
+
+	rollout := &unstructured.Unstructured{}
+	rollout.SetAPIVersion("argoproj.io/v1alpha1")
+	rollout.SetKind("Rollout")
+	rollout.SetName("canary-rollout")
+	rollout.SetNamespace("default")
+	rollout.SetUID("c5b35a1e-0a6c-4bd5-8a57-4d4a0e0a2f3c")
+	rollout.SetLabels(map[string]string{"team": "payments"})
+	op.cacheObjectWithLabels("Rollout", rollout)
+
+	replicaSet := &unstructured.Unstructured{}
+	replicaSet.SetName("canary-rollout-6d4f8b")
+	replicaSet.SetUID("9e0c4c1a-54c5-4d2b-9f6b-1f0a4f3e2b11")
+	replicaSet.SetOwnerReferences([]meta_v1.OwnerReference{
+		{Kind: "Rollout", Name: "canary-rollout", UID: "c5b35a1e-0a6c-4bd5-8a57-4d4a0e0a2f3c"},
+	})
+	op.cacheObject("ReplicaSet", replicaSet)
+
+	pod := &api_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			OwnerReferences: []meta_v1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "canary-rollout-6d4f8b", UID: "9e0c4c1a-54c5-4d2b-9f6b-1f0a4f3e2b11"},
+			},
+		},
+	}
+
+	owners := op.GetOwners(pod)
+	require.Len(t, owners, 2)
+	assert.Equal(t, "ReplicaSet", owners[0].kind)
+	assert.Nil(t, owners[0].labels)
+	assert.Equal(t, "Rollout", owners[1].kind)
+	assert.Equal(t, "canary-rollout", owners[1].name)
+	assert.Equal(t, map[string]string{"team": "payments"}, owners[1].labels)
+
+	op.deleteObject(rollout)
+	assert.Len(t, op.GetOwners(pod), 1)
+}
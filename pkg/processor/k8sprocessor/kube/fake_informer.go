@@ -37,6 +37,7 @@ func NewFakeInformer(
 	namespace string,
 	labelSelector labels.Selector,
 	fieldSelector fields.Selector,
+	_ time.Duration,
 ) cache.SharedInformer {
 	return &FakeInformer{
 		FakeController: &FakeController{},
@@ -15,12 +15,15 @@
 package kube
 
 import (
+	"time"
+
 	"go.uber.org/zap"
 	api_v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -33,10 +36,12 @@ type fakeOwnerCache struct {
 // NewOwnerProvider creates new instance of the owners api
 func newFakeOwnerProvider(logger *zap.Logger,
 	client kubernetes.Interface,
+	dynamicClient dynamic.Interface,
 	labelSelector labels.Selector,
 	fieldSelector fields.Selector,
 	extractionRules ExtractionRules,
-	namespace string) (OwnerAPI, error) {
+	namespace string,
+	resyncPeriod time.Duration) (OwnerAPI, error) {
 	ownerCache := fakeOwnerCache{}
 	ownerCache.objectOwners = map[string]*ObjectOwner{}
 	ownerCache.logger = logger
@@ -86,6 +91,16 @@ func newFakeOwnerProvider(logger *zap.Logger,
 	}
 	ownerCache.objectOwners[string(cronjob.UID)] = &cronjob
 
+	rollout := ObjectOwner{
+		UID:       "c5b35a1e-0a6c-4bd5-8a57-4d4a0e0a2f3c",
+		namespace: "default",
+		ownerUIDs: []types.UID{},
+		kind:      "Rollout",
+		name:      "canary-rollout",
+		labels:    map[string]string{"team": "payments"},
+	}
+	ownerCache.objectOwners[string(rollout.UID)] = &rollout
+
 	return &ownerCache, nil
 }
 
@@ -95,6 +110,11 @@ func (op *fakeOwnerCache) Start() {}
 // Stop
 func (op *fakeOwnerCache) Stop() {}
 
+// HasSynced
+func (op *fakeOwnerCache) HasSynced() bool {
+	return true
+}
+
 // GetServices fetches list of services for a given pod
 func (op *fakeOwnerCache) GetServices(pod *api_v1.Pod) []string {
 	return []string{"foo", "bar"}
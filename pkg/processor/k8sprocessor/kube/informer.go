@@ -16,6 +16,7 @@ package kube
 
 import (
 	"context"
+	"time"
 
 	api_v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +35,7 @@ type InformerProvider func(
 	namespace string,
 	labelSelector labels.Selector,
 	fieldSelector fields.Selector,
+	resyncPeriod time.Duration,
 ) cache.SharedInformer
 
 func newSharedInformer(
@@ -41,6 +43,7 @@ func newSharedInformer(
 	namespace string,
 	ls labels.Selector,
 	fs fields.Selector,
+	resyncPeriod time.Duration,
 ) cache.SharedInformer {
 	informer := cache.NewSharedInformer(
 		&cache.ListWatch{
@@ -48,7 +51,7 @@ func newSharedInformer(
 			WatchFunc: informerWatchFuncWithSelectors(client, namespace, ls, fs),
 		},
 		&api_v1.Pod{},
-		watchSyncPeriod,
+		resyncPeriod,
 	)
 	return informer
 }
@@ -33,7 +33,7 @@ func Test_newSharedInformer(t *testing.T) {
 	require.NoError(t, err)
 	client, err := newFakeAPIClientset(k8sconfig.APIConfig{})
 	require.NoError(t, err)
-	informer := newSharedInformer(client, "testns", labelSelector, fieldSelector)
+	informer := newSharedInformer(client, "testns", labelSelector, fieldSelector, DefaultResyncPeriod)
 	assert.NotNil(t, informer)
 }
 
@@ -95,7 +95,7 @@ func Test_fakeInformer(t *testing.T) {
 	// nothing real to test here. just to make coverage happy
 	c, err := newFakeAPIClientset(k8sconfig.APIConfig{})
 	assert.NoError(t, err)
-	i := NewFakeInformer(c, "ns", nil, nil)
+	i := NewFakeInformer(c, "ns", nil, nil, DefaultResyncPeriod)
 	i.AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{}, time.Second)
 	i.HasSynced()
 	i.LastSyncResourceVersion()
@@ -29,8 +29,9 @@ import (
 )
 
 const (
-	podNodeField            = "spec.nodeName"
-	ignoreAnnotation string = "opentelemetry.io/k8s-processor/ignore"
+	podNodeField             = "spec.nodeName"
+	podNamespaceField        = "metadata.namespace"
+	ignoreAnnotation  string = "opentelemetry.io/k8s-processor/ignore"
 
 	defaultTagContainerID     = "k8s.container.id"
 	defaultTagContainerImage  = "k8s.container.image"
@@ -52,12 +53,18 @@ type PodIdentifier string
 
 const (
 	DefaultPodDeleteGracePeriod = time.Second * 120
-	watchSyncPeriod             = time.Minute * 5
+	DefaultResyncPeriod         = time.Minute * 5
 )
 
 // Client defines the main interface that allows querying pods by metadata.
 type Client interface {
 	GetPod(PodIdentifier) (*Pod, bool)
+	// HasSynced returns true when the pod cache and, if owner lookup is enabled,
+	// the owner caches have been populated with the initial state of the cluster.
+	HasSynced() bool
+	// IsStale returns true when the Kubernetes API server is unreachable
+	// and the pods are served from the last known cache.
+	IsStale() bool
 	Start()
 	Stop()
 }
@@ -71,11 +78,14 @@ type ClientProvider func(
 	[]Association,
 	Excludes,
 	APIClientsetProvider,
+	DynamicClientProvider,
 	InformerProvider,
 	OwnerProvider,
 	string,
 	time.Duration,
 	time.Duration,
+	time.Duration,
+	StaleCacheSettings,
 ) (Client, error)
 
 // APIClientsetProvider defines a func type that initializes and return a new kubernetes
@@ -114,11 +124,13 @@ type deleteRequest struct {
 // for performance reasons. We can support adding additional custom filters
 // in future if there is a real need.
 type Filters struct {
-	Node            string
-	Namespace       string
-	Fields          []FieldFilter
-	Labels          []FieldFilter
-	NamespaceLabels []FieldFilter
+	Node      string
+	Namespace string
+	// ExcludeNamespaces lists the namespaces whose pods are not watched
+	ExcludeNamespaces []string
+	Fields            []FieldFilter
+	Labels            []FieldFilter
+	NamespaceLabels   []FieldFilter
 }
 
 // FieldFilter represents exactly one filter by field rule.
@@ -161,6 +173,7 @@ type ExtractionRules struct {
 	Annotations     []FieldExtractionRule
 	Labels          []FieldExtractionRule
 	NamespaceLabels []FieldExtractionRule
+	CustomResources []CustomResourceRule
 }
 
 // ExtractionFieldTags is used to describe selected exported key names for the extracted data
@@ -217,6 +230,9 @@ type FieldExtractionRule struct {
 	Name string
 	// Key is used to lookup k8s pod fields.
 	Key string
+	// KeyRegex is used instead of Key to lookup all the k8s pod fields with matching names.
+	// Name is then a template expanded with the submatches of the field name.
+	KeyRegex *regexp.Regexp
 }
 
 // Associations represent a list of rules for Pod metadata associations with resources
@@ -233,9 +249,21 @@ type Association struct {
 // Excludes represent a list of Pods to ignore
 type Excludes struct {
 	Pods []ExcludePods
+	// CollectorPod is the pod the collector runs in. When set, the pod and the other pods
+	// of its controller are ignored.
+	CollectorPod *CollectorPod
 }
 
 // ExcludePods represent a Pod name to ignore
 type ExcludePods struct {
+	// Name is nil when any pod name matches
 	Name *regexp.Regexp
+	// Namespace is nil when any namespace matches
+	Namespace *regexp.Regexp
+}
+
+// CollectorPod identifies the pod the collector runs in
+type CollectorPod struct {
+	Name      string
+	Namespace string
 }
@@ -17,6 +17,7 @@ package kube
 import (
 	"sort"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	api_v1 "k8s.io/api/core/v1"
@@ -24,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -35,10 +37,12 @@ import (
 type OwnerProvider func(
 	logger *zap.Logger,
 	client kubernetes.Interface,
+	dynamicClient dynamic.Interface,
 	labelSelector labels.Selector,
 	fieldSelector fields.Selector,
 	extractionRules ExtractionRules,
 	namespace string,
+	resyncPeriod time.Duration,
 ) (OwnerAPI, error)
 
 // ObjectOwner keeps single entry
@@ -48,6 +52,8 @@ type ObjectOwner struct {
 	kind      string
 	name      string
 	ownerUIDs []types.UID
+	// labels are kept only for the custom resources
+	labels map[string]string
 }
 
 // OwnerAPI describes functions that could allow retrieving owner info
@@ -55,6 +61,7 @@ type OwnerAPI interface {
 	GetOwners(pod *api_v1.Pod) []*ObjectOwner
 	GetNamespace(pod *api_v1.Pod) *api_v1.Namespace
 	GetServices(pod *api_v1.Pod) []string
+	HasSynced() bool
 	Start()
 	Stop()
 }
@@ -99,17 +106,29 @@ func (op *OwnerCache) Stop() {
 	close(op.stopCh)
 }
 
+// HasSynced returns true when all the informers have synced their caches
+func (op *OwnerCache) HasSynced() bool {
+	for _, informer := range op.informers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
 func newOwnerProvider(
 	logger *zap.Logger,
 	client kubernetes.Interface,
+	dynamicClient dynamic.Interface,
 	labelSelector labels.Selector,
 	fieldSelector fields.Selector,
 	extractionRules ExtractionRules,
-	namespace string) (OwnerAPI, error) {
+	namespace string,
+	resyncPeriod time.Duration) (OwnerAPI, error) {
 
 	ownerCache := newOwnerCache(logger)
 
-	factory := informers.NewSharedInformerFactoryWithOptions(client, watchSyncPeriod,
+	factory := informers.NewSharedInformerFactoryWithOptions(client, resyncPeriod,
 		informers.WithNamespace(namespace),
 		informers.WithTweakListOptions(func(opts *meta_v1.ListOptions) {
 			opts.LabelSelector = labelSelector.String()
@@ -233,6 +252,10 @@ func newOwnerProvider(
 		}
 	}
 
+	if len(extractionRules.CustomResources) > 0 && dynamicClient != nil {
+		ownerCache.addCustomResourceInformers(client, dynamicClient, extractionRules.CustomResources, namespace, resyncPeriod)
+	}
+
 	return &ownerCache, nil
 }
 
@@ -301,8 +324,14 @@ func (op *OwnerCache) deleteObject(obj interface{}) {
 }
 
 func (op *OwnerCache) cacheObject(kind string, obj interface{}) {
-	meta := obj.(meta_v1.Object)
+	oo := newObjectOwner(kind, obj.(meta_v1.Object))
 
+	op.ownersMutex.Lock()
+	op.objectOwners[string(oo.UID)] = oo
+	op.ownersMutex.Unlock()
+}
+
+func newObjectOwner(kind string, meta meta_v1.Object) *ObjectOwner {
 	oo := ObjectOwner{
 		UID:       meta.GetUID(),
 		namespace: meta.GetNamespace(),
@@ -313,10 +342,7 @@ func (op *OwnerCache) cacheObject(kind string, obj interface{}) {
 	for _, or := range meta.GetOwnerReferences() {
 		oo.ownerUIDs = append(oo.ownerUIDs, or.UID)
 	}
-
-	op.ownersMutex.Lock()
-	op.objectOwners[string(oo.UID)] = &oo
-	op.ownersMutex.Unlock()
+	return &oo
 }
 
 func (op *OwnerCache) addEndpointToPod(pod string, endpoint string) {
@@ -57,6 +57,7 @@ func Test_OwnerProvider_GetOwners_Statefulset(t *testing.T) {
 	op, err := newOwnerProvider(
 		logger,
 		c,
+		nil,
 		labels.Everything(),
 		fields.Everything(),
 		ExtractionRules{
@@ -68,6 +69,7 @@ func Test_OwnerProvider_GetOwners_Statefulset(t *testing.T) {
 			Tags:               NewExtractionFieldTags(),
 		},
 		"kube-system",
+		DefaultResyncPeriod,
 	)
 	require.NoError(t, err)
 
@@ -142,6 +144,7 @@ func Test_OwnerProvider_GetOwners_Daemonset(t *testing.T) {
 	op, err := newOwnerProvider(
 		logger,
 		c,
+		nil,
 		labels.Everything(),
 		fields.Everything(),
 		ExtractionRules{
@@ -153,6 +156,7 @@ func Test_OwnerProvider_GetOwners_Daemonset(t *testing.T) {
 			Tags:               NewExtractionFieldTags(),
 		},
 		"kube-system",
+		DefaultResyncPeriod,
 	)
 	require.NoError(t, err)
 
@@ -231,6 +235,7 @@ func Test_OwnerProvider_GetServices(t *testing.T) {
 	op, err := newOwnerProvider(
 		logger,
 		c,
+		nil,
 		labels.Everything(),
 		fields.Everything(),
 		ExtractionRules{
@@ -242,6 +247,7 @@ func Test_OwnerProvider_GetServices(t *testing.T) {
 			Tags:               NewExtractionFieldTags(),
 		},
 		namespace,
+		DefaultResyncPeriod,
 	)
 	require.NoError(t, err)
 
@@ -0,0 +1,131 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+)
+
+// createRestConfig creates the Kubernetes API config the same way k8sconfig.MakeClient does,
+// for the clients which k8sconfig doesn't provide (e.g. the dynamic client).
+func createRestConfig(apiConf k8sconfig.APIConfig) (*rest.Config, error) {
+	if err := apiConf.Validate(); err != nil {
+		return nil, err
+	}
+
+	var authConf *rest.Config
+	var err error
+
+	switch apiConf.AuthType {
+	case k8sconfig.AuthTypeKubeConfig:
+		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+		authConf, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to k8s with auth_type=%s: %w", k8sconfig.AuthTypeKubeConfig, err)
+		}
+	case k8sconfig.AuthTypeNone:
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if len(host) == 0 || len(port) == 0 {
+			return nil, fmt.Errorf("unable to load k8s config, KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT must be defined")
+		}
+		authConf = &rest.Config{
+			Host: "https://" + net.JoinHostPort(host, port),
+		}
+		authConf.Insecure = true
+	case k8sconfig.AuthTypeServiceAccount:
+		authConf, err = rest.InClusterConfig()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	authConf.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		// Don't use system proxy settings since the API is local to the cluster
+		if t, ok := rt.(*http.Transport); ok {
+			t.Proxy = nil
+		}
+		return rt
+	}
+
+	return authConf, nil
+}
+
+// KubeConfigSettings select the kubeconfig file and context the Kubernetes API is connected with,
+// e.g. when the collector runs outside of the cluster, and the rate limits of the API requests.
+type KubeConfigSettings struct {
+	// Path is the path of the kubeconfig file. When empty, the default loading rules
+	// are used, i.e. the KUBECONFIG environment variable or ~/.kube/config.
+	Path string
+	// Context is the kubeconfig context used. When empty, the current context is used.
+	Context string
+	// QPS is the maximum number of the API requests per second. When zero, the client-go default is used.
+	QPS float32
+	// Burst is the maximum burst of the API requests. When zero, the client-go default is used.
+	Burst int
+}
+
+// createKubeConfigRestConfig creates the Kubernetes API config from the kubeconfig file and context.
+// Unlike createRestConfig, it keeps the system proxy settings, as the API is usually not local then.
+func createKubeConfigRestConfig(settings KubeConfigSettings) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if settings.Path != "" {
+		loadingRules.ExplicitPath = settings.Path
+	}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: settings.Context}
+
+	authConf, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading kubeconfig %q with context %q: %w", settings.Path, settings.Context, err)
+	}
+	authConf.QPS = settings.QPS
+	authConf.Burst = settings.Burst
+
+	return authConf, nil
+}
+
+// NewKubeConfigClientsetProvider returns the APIClientsetProvider connecting to the Kubernetes API
+// with the given kubeconfig settings instead of the API config.
+func NewKubeConfigClientsetProvider(settings KubeConfigSettings) APIClientsetProvider {
+	return func(k8sconfig.APIConfig) (kubernetes.Interface, error) {
+		restConfig, err := createKubeConfigRestConfig(settings)
+		if err != nil {
+			return nil, err
+		}
+		return kubernetes.NewForConfig(restConfig)
+	}
+}
+
+// NewKubeConfigDynamicClientProvider returns the DynamicClientProvider connecting to the Kubernetes API
+// with the given kubeconfig settings instead of the API config.
+func NewKubeConfigDynamicClientProvider(settings KubeConfigSettings) DynamicClientProvider {
+	return func(k8sconfig.APIConfig) (dynamic.Interface, error) {
+		restConfig, err := createKubeConfigRestConfig(settings)
+		if err != nil {
+			return nil, err
+		}
+		return dynamic.NewForConfig(restConfig)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+)
+
+const testKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+- name: staging
+  cluster:
+    server: https://staging.example.com:6443
+users:
+- name: admin
+  user:
+    token: secret
+contexts:
+- name: prod-admin
+  context:
+    cluster: prod
+    user: admin
+- name: staging-admin
+  context:
+    cluster: staging
+    user: admin
+current-context: prod-admin
+`
+
+func TestCreateKubeConfigRestConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, []byte(testKubeConfig), 0600))
+
+	restConfig, err := createKubeConfigRestConfig(KubeConfigSettings{Path: path})
+	require.NoError(t, err)
+	assert.Equal(t, "https://prod.example.com:6443", restConfig.Host)
+
+	restConfig, err = createKubeConfigRestConfig(KubeConfigSettings{
+		Path:    path,
+		Context: "staging-admin",
+		QPS:     50,
+		Burst:   100,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.example.com:6443", restConfig.Host)
+	assert.Equal(t, "secret", restConfig.BearerToken)
+	assert.Equal(t, float32(50), restConfig.QPS)
+	assert.Equal(t, 100, restConfig.Burst)
+
+	_, err = createKubeConfigRestConfig(KubeConfigSettings{Path: path, Context: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestKubeConfigClientProviders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, []byte(testKubeConfig), 0600))
+	settings := KubeConfigSettings{Path: path, Context: "staging-admin"}
+
+	// the API config is ignored, so the clients are created outside of the cluster
+	apiConfig := k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount}
+
+	client, err := NewKubeConfigClientsetProvider(settings)(apiConfig)
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+
+	dynamicClient, err := NewKubeConfigDynamicClientProvider(settings)(apiConfig)
+	require.NoError(t, err)
+	assert.NotNil(t, dynamicClient)
+}
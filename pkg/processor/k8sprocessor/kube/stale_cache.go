@@ -0,0 +1,127 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sprocessor/observability"
+)
+
+const (
+	DefaultInitialReconnectInterval = time.Second
+	DefaultMaxReconnectInterval     = time.Minute
+)
+
+// StaleCacheSettings defines how the client behaves when the Kubernetes API server is unreachable.
+type StaleCacheSettings struct {
+	// Enabled makes the client track the availability of the API server. While it's unreachable,
+	// the pods are served from the last known cache and the client is reported as stale.
+	Enabled bool
+	// InitialReconnectInterval is the delay of the first reconnect attempt after the API server
+	// became unreachable. It's doubled after every failed attempt.
+	InitialReconnectInterval time.Duration
+	// MaxReconnectInterval is the maximum delay between the reconnect attempts.
+	MaxReconnectInterval time.Duration
+}
+
+// IsStale returns true when the API server is unreachable and the pods are served
+// from the last known cache.
+func (c *WatchClient) IsStale() bool {
+	return atomic.LoadInt32(&c.stale) == 1
+}
+
+// handleWatchError is called by the pod informer whenever listing or watching the pods fails.
+func (c *WatchClient) handleWatchError(r *cache.Reflector, err error) {
+	cache.DefaultWatchErrorHandler(r, err)
+
+	// the watch being closed by the server or the resource version being too old
+	// don't mean the API server is unreachable, the informer simply re-lists the pods
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+		return
+	}
+	c.markStale(err)
+}
+
+func (c *WatchClient) markStale(err error) {
+	if !atomic.CompareAndSwapInt32(&c.stale, 0, 1) {
+		return
+	}
+
+	c.logger.Warn("Kubernetes API server is unreachable, serving pod metadata from the last known cache",
+		zap.Error(err),
+	)
+	observability.RecordCacheStale(true)
+
+	// wake up the reconnect loop, unless it's already trying to reconnect
+	select {
+	case c.reconnectCh <- struct{}{}:
+	default:
+	}
+}
+
+func (c *WatchClient) markFresh() {
+	if !atomic.CompareAndSwapInt32(&c.stale, 1, 0) {
+		return
+	}
+
+	c.logger.Info("Kubernetes API server is reachable again, pod metadata is up to date")
+	observability.RecordCacheStale(false)
+}
+
+// reconnectLoop checks with exponential backoff whether the API server is reachable again
+// after it became unreachable. The informers keep retrying on their own, so once the
+// API server responds they resume watching the pods and the cache is updated.
+func (c *WatchClient) reconnectLoop() {
+	for {
+		select {
+		case <-c.reconnectCh:
+		case <-c.stopCh:
+			return
+		}
+
+		interval := c.staleCache.InitialReconnectInterval
+		for c.IsStale() {
+			timer := time.NewTimer(interval)
+			select {
+			case <-timer.C:
+			case <-c.stopCh:
+				timer.Stop()
+				return
+			}
+
+			if _, err := c.kc.Discovery().ServerVersion(); err != nil {
+				interval *= 2
+				if interval > c.staleCache.MaxReconnectInterval {
+					interval = c.staleCache.MaxReconnectInterval
+				}
+				c.logger.Debug("Kubernetes API server is still unreachable",
+					zap.Error(err),
+					zap.Duration("retry_in", interval),
+				)
+				continue
+			}
+			c.markFresh()
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newTestReflector() *cache.Reflector {
+	return cache.NewReflector(&cache.ListWatch{}, &api_v1.Pod{}, cache.NewStore(cache.MetaNamespaceKeyFunc), 0)
+}
+
+func TestStaleCache(t *testing.T) {
+	c, _ := newTestClient(t)
+	c.staleCache = StaleCacheSettings{
+		Enabled:                  true,
+		InitialReconnectInterval: 10 * time.Millisecond,
+		MaxReconnectInterval:     10 * time.Millisecond,
+	}
+	c.handlePodAdd(&api_v1.Pod{})
+	assert.False(t, c.IsStale())
+
+	// the watch closed by the server doesn't mean the API server is unreachable
+	c.handleWatchError(newTestReflector(), io.EOF)
+	assert.False(t, c.IsStale())
+
+	c.handleWatchError(newTestReflector(), errors.New("connection refused"))
+	assert.True(t, c.IsStale())
+
+	// the pods are still served from the cache
+	pod := &api_v1.Pod{}
+	pod.Name = "podA"
+	pod.Status.PodIP = "1.1.1.1"
+	c.addOrUpdatePod(pod)
+	got, ok := c.GetPod("1.1.1.1")
+	assert.True(t, ok)
+	assert.Equal(t, "podA", got.Name)
+	assert.True(t, c.IsStale())
+
+	// receiving a pod event means the API server is reachable again
+	c.handlePodUpdate(pod, pod)
+	assert.False(t, c.IsStale())
+}
+
+func TestStaleCacheReconnect(t *testing.T) {
+	c, _ := newTestClient(t)
+	c.staleCache = StaleCacheSettings{
+		Enabled:                  true,
+		InitialReconnectInterval: 10 * time.Millisecond,
+		MaxReconnectInterval:     10 * time.Millisecond,
+	}
+	go c.reconnectLoop()
+	defer c.Stop()
+
+	c.markStale(errors.New("connection refused"))
+	assert.True(t, c.IsStale())
+
+	// the fake API server responds to the reconnect attempt
+	assert.Eventually(t, func() bool {
+		return !c.IsStale()
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -35,6 +35,7 @@ func init() {
 		viewOtherDeleted,
 		viewIPLookupMiss,
 		viewPodTableSize,
+		viewCacheStale,
 	)
 	if err != nil {
 		fmt.Printf("Failed to register k8sprocessor's views: %v\n", err)
@@ -52,6 +53,8 @@ var (
 	mOtherDeleted = stats.Int64("otelsvc/k8s/other_deleted", "Number of other delete events received", "1")
 
 	mIPLookupMiss = stats.Int64("otelsvc/k8s/ip_lookup_miss", "Number of times pod by IP lookup failed.", "1")
+
+	mCacheStale = stats.Int64("otelsvc/k8s/cache_stale", "Whether the pod metadata is served from the last known cache because the API server is unreachable", "1")
 )
 
 var viewPodsUpdated = &view.View{
@@ -109,6 +112,13 @@ var viewPodTableSize = &view.View{
 	Aggregation: view.LastValue(),
 }
 
+var viewCacheStale = &view.View{
+	Name:        mCacheStale.Name(),
+	Description: mCacheStale.Description(),
+	Measure:     mCacheStale,
+	Aggregation: view.LastValue(),
+}
+
 // RecordPodUpdated increments the metric that records pod update events received.
 func RecordPodUpdated() {
 	stats.Record(context.Background(), mPodsUpdated.M(int64(1)))
@@ -148,3 +158,13 @@ func RecordIPLookupMiss() {
 func RecordPodTableSize(podTableSize int64) {
 	stats.Record(context.Background(), mPodTableSize.M(podTableSize))
 }
+
+// RecordCacheStale records whether the pod metadata is served from the last known cache
+// because the API server is unreachable.
+func RecordCacheStale(stale bool) {
+	var value int64
+	if stale {
+		value = 1
+	}
+	stats.Record(context.Background(), mCacheStale.M(value))
+}
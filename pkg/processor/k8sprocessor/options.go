@@ -15,11 +15,14 @@
 package k8sprocessor
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/selection"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
@@ -217,6 +220,36 @@ func WithExtractNamespaceLabels(labels ...FieldExtractConfig) Option {
 	}
 }
 
+// WithExtractCustomResources allows specifying the custom resources the metadata is extracted from.
+func WithExtractCustomResources(customResources ...CustomResourceConfig) Option {
+	return func(p *kubernetesprocessor) error {
+		rules := []kube.CustomResourceRule{}
+		for _, cr := range customResources {
+			if cr.Version == "" || cr.Resource == "" || cr.Kind == "" {
+				return fmt.Errorf("custom resource has to specify version, resource and kind: %+v", cr)
+			}
+
+			labels, err := extractFieldRules(strings.ToLower(cr.Kind)+".labels", cr.Labels...)
+			if err != nil {
+				return err
+			}
+
+			rules = append(rules, kube.CustomResourceRule{
+				Resource: schema.GroupVersionResource{
+					Group:    cr.Group,
+					Version:  cr.Version,
+					Resource: cr.Resource,
+				},
+				Kind:    cr.Kind,
+				NameTag: cr.NameTagName,
+				Labels:  labels,
+			})
+		}
+		p.rules.CustomResources = rules
+		return nil
+	}
+}
+
 // WithExtractAnnotations allows specifying options to control extraction of pod annotations tags.
 func WithExtractAnnotations(annotations ...FieldExtractConfig) Option {
 	return func(p *kubernetesprocessor) error {
@@ -232,15 +265,32 @@ func WithExtractAnnotations(annotations ...FieldExtractConfig) Option {
 func extractFieldRules(fieldType string, fields ...FieldExtractConfig) ([]kube.FieldExtractionRule, error) {
 	rules := []kube.FieldExtractionRule{}
 	for _, a := range fields {
+		if a.Key != "" && a.KeyRegex != "" {
+			return rules, fmt.Errorf("key and key_regex cannot be used together")
+		}
+
 		name := a.TagName
 		if name == "" {
-			if a.Key == "*" {
+			switch {
+			case a.KeyRegex != "":
+				name = fmt.Sprintf("k8s.%s.$0", fieldType)
+			case a.Key == "*":
 				name = fmt.Sprintf("k8s.%s.%%s", fieldType)
-			} else {
+			default:
 				name = fmt.Sprintf("k8s.%s.%s", fieldType, a.Key)
 			}
 		}
 
+		var keyRegex *regexp.Regexp
+		if a.KeyRegex != "" {
+			var err error
+			// the whole key has to match, so the submatches can be used to rename it
+			keyRegex, err = regexp.Compile("^(?:" + a.KeyRegex + ")$")
+			if err != nil {
+				return rules, err
+			}
+		}
+
 		var r *regexp.Regexp
 		if a.Regex != "" {
 			var err error
@@ -255,7 +305,7 @@ func extractFieldRules(fieldType string, fields ...FieldExtractConfig) ([]kube.F
 		}
 
 		rules = append(rules, kube.FieldExtractionRule{
-			Name: name, Key: a.Key, Regex: r,
+			Name: name, Key: a.Key, KeyRegex: keyRegex, Regex: r,
 		})
 	}
 	return rules, nil
@@ -281,6 +331,14 @@ func WithFilterNamespace(ns string) Option {
 	}
 }
 
+// WithFilterExcludeNamespaces allows specifying the namespaces whose pods are not watched.
+func WithFilterExcludeNamespaces(namespaces ...string) Option {
+	return func(p *kubernetesprocessor) error {
+		p.filters.ExcludeNamespaces = namespaces
+		return nil
+	}
+}
+
 // WithFilterLabels allows specifying options to control filtering pods by pod labels.
 func WithFilterLabels(filters ...FieldFilterConfig) Option {
 	return func(p *kubernetesprocessor) error {
@@ -370,15 +428,136 @@ func WithDelimiter(delimiter string) Option {
 func WithExcludes(excludeConfig ExcludeConfig) Option {
 	return func(p *kubernetesprocessor) error {
 		excludes := kube.Excludes{}
-		names := excludeConfig.Pods
 
-		for _, name := range names {
-			excludes.Pods = append(excludes.Pods, kube.ExcludePods{
-				Name: regexp.MustCompile(name.Name)},
-			)
+		for _, pod := range excludeConfig.Pods {
+			var exclude kube.ExcludePods
+			if pod.Name != "" {
+				exclude.Name = regexp.MustCompile(pod.Name)
+			}
+			if pod.Namespace != "" {
+				exclude.Namespace = regexp.MustCompile(pod.Namespace)
+			}
+			excludes.Pods = append(excludes.Pods, exclude)
+		}
+
+		if excludeConfig.CollectorPods.Enabled {
+			collectorPod, err := getCollectorPod(excludeConfig.CollectorPods)
+			if err != nil {
+				return err
+			}
+			excludes.CollectorPod = collectorPod
 		}
 
 		p.podIgnore = excludes
 		return nil
 	}
 }
+
+// serviceAccountNamespaceFile holds the namespace of the pod's service account
+var serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// getCollectorPod returns the name and namespace of the pod the collector runs in
+func getCollectorPod(cfg CollectorPodsConfig) (*kube.CollectorPod, error) {
+	var name string
+	if cfg.PodNameFromEnvVar != "" {
+		name = os.Getenv(cfg.PodNameFromEnvVar)
+	} else {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the collector's pod name: %w", err)
+		}
+		name = hostname
+	}
+	if name == "" {
+		return nil, fmt.Errorf("failed to get the collector's pod name: environment variable %s is empty", cfg.PodNameFromEnvVar)
+	}
+
+	var namespace string
+	if cfg.NamespaceFromEnvVar != "" {
+		namespace = os.Getenv(cfg.NamespaceFromEnvVar)
+		if namespace == "" {
+			return nil, fmt.Errorf("failed to get the collector's pod namespace: environment variable %s is empty", cfg.NamespaceFromEnvVar)
+		}
+	} else {
+		content, err := os.ReadFile(serviceAccountNamespaceFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the collector's pod namespace: %w", err)
+		}
+		namespace = strings.TrimSpace(string(content))
+	}
+
+	return &kube.CollectorPod{Name: name, Namespace: namespace}, nil
+}
+
+// WithResyncPeriod sets the period after which the informers re-list all the watched objects
+func WithResyncPeriod(period time.Duration) Option {
+	return func(p *kubernetesprocessor) error {
+		p.resyncPeriod = period
+		return nil
+	}
+}
+
+// WithWaitForCacheSync makes the processor wait until the pod and owner caches are synced
+// or the provided timeout elapses, either holding incoming data or blocking its start,
+// which delays the start of the pipeline
+func WithWaitForCacheSync(mode CacheSyncMode, timeout time.Duration) Option {
+	return func(p *kubernetesprocessor) error {
+		p.cacheSyncMode = mode
+		p.cacheSyncTimeout = timeout
+		return nil
+	}
+}
+
+// WithStaleCache makes the processor keep serving the pod metadata from the last known cache
+// when the Kubernetes API server is unreachable, marking the enriched resources with the attribute
+func WithStaleCache(cfg StaleCacheConfig) Option {
+	return func(p *kubernetesprocessor) error {
+		p.staleCache = kube.StaleCacheSettings{
+			Enabled:                  true,
+			InitialReconnectInterval: cfg.InitialReconnectInterval,
+			MaxReconnectInterval:     cfg.MaxReconnectInterval,
+		}
+		p.staleCacheAttribute = cfg.Attribute
+		return nil
+	}
+}
+
+// WithKubeConfig makes the processor connect to the Kubernetes API with the given kubeconfig file
+// and context, e.g. when it runs outside of the cluster
+func WithKubeConfig(cfg KubeConfigConfig) Option {
+	return func(p *kubernetesprocessor) error {
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+		p.kubeConfig = kubeConfigSettings(cfg)
+		return nil
+	}
+}
+
+// WithClusters makes the processor enrich the data of multiple clusters, each of them connected
+// with its own kubeconfig context. The cluster of the data is selected by the resource attribute.
+func WithClusters(attribute string, clusters ...ClusterConfig) Option {
+	return func(p *kubernetesprocessor) error {
+		if attribute == "" {
+			return errors.New("cluster attribute cannot be empty")
+		}
+		p.clusterAttribute = attribute
+		p.clusters = make(map[string]kube.KubeConfigSettings, len(clusters))
+		for _, cluster := range clusters {
+			if err := cluster.Validate(); err != nil {
+				return fmt.Errorf("cluster %q: %w", cluster.Name, err)
+			}
+			p.clusters[cluster.Name] = kubeConfigSettings(cluster.KubeConfigConfig)
+		}
+		return nil
+	}
+}
+
+func kubeConfigSettings(cfg KubeConfigConfig) kube.KubeConfigSettings {
+	return kube.KubeConfigSettings{
+		Path:    cfg.Path,
+		Context: cfg.Context,
+		QPS:     cfg.QPS,
+		Burst:   cfg.Burst,
+	}
+}
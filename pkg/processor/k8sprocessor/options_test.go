@@ -16,12 +16,14 @@ package k8sprocessor
 
 import (
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/selection"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
@@ -47,6 +49,12 @@ func TestWithFilterNamespace(t *testing.T) {
 	assert.Equal(t, p.filters.Namespace, "testns")
 }
 
+func TestWithFilterExcludeNamespaces(t *testing.T) {
+	p := &kubernetesprocessor{}
+	assert.NoError(t, WithFilterExcludeNamespaces("kube-system", "monitoring")(p))
+	assert.Equal(t, []string{"kube-system", "monitoring"}, p.filters.ExcludeNamespaces)
+}
+
 func TestWithFilterNode(t *testing.T) {
 	p := &kubernetesprocessor{}
 	assert.NoError(t, WithFilterNode("testnode", "")(p))
@@ -258,6 +266,36 @@ func TestWithExtractNamespaceLabels(t *testing.T) {
 	}
 }
 
+func TestWithExtractCustomResources(t *testing.T) {
+	p := &kubernetesprocessor{}
+	option := WithExtractCustomResources(CustomResourceConfig{
+		Group:       "argoproj.io",
+		Version:     "v1alpha1",
+		Resource:    "rollouts",
+		Kind:        "Rollout",
+		NameTagName: "k8s.rollout.name",
+		Labels: []FieldExtractConfig{
+			{Key: "*"},
+			{TagName: "team", Key: "team"},
+		},
+	})
+	require.NoError(t, option(p))
+	assert.Equal(t, []kube.CustomResourceRule{
+		{
+			Resource: schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"},
+			Kind:     "Rollout",
+			NameTag:  "k8s.rollout.name",
+			Labels: []kube.FieldExtractionRule{
+				{Name: "k8s.rollout.labels.%s", Key: "*"},
+				{Name: "team", Key: "team"},
+			},
+		},
+	}, p.rules.CustomResources)
+
+	option = WithExtractCustomResources(CustomResourceConfig{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"})
+	assert.Error(t, option(p))
+}
+
 func TestWithExtractMetadata(t *testing.T) {
 	p := &kubernetesprocessor{}
 	assert.NoError(t, WithExtractMetadata()(p))
@@ -601,6 +639,50 @@ func Test_extractFieldRules(t *testing.T) {
 			[]kube.FieldExtractionRule{},
 			true,
 		},
+		{
+			"key-regex",
+			args{"labels", []FieldExtractConfig{
+				{
+					TagName:  "k8s.app.$1",
+					KeyRegex: `app\.kubernetes\.io/(.*)`,
+				},
+				{
+					KeyRegex: `team-.*`,
+				},
+			}},
+			[]kube.FieldExtractionRule{
+				{
+					Name:     "k8s.app.$1",
+					KeyRegex: regexp.MustCompile(`^(?:app\.kubernetes\.io/(.*))$`),
+				},
+				{
+					Name:     "k8s.labels.$0",
+					KeyRegex: regexp.MustCompile(`^(?:team-.*)$`),
+				},
+			},
+			false,
+		},
+		{
+			"key-and-key-regex",
+			args{"labels", []FieldExtractConfig{
+				{
+					Key:      "key",
+					KeyRegex: "key.*",
+				},
+			}},
+			[]kube.FieldExtractionRule{},
+			true,
+		},
+		{
+			"bad-key-regex",
+			args{"labels", []FieldExtractConfig{
+				{
+					KeyRegex: "[",
+				},
+			}},
+			[]kube.FieldExtractionRule{},
+			true,
+		},
 		{
 			"badregex",
 			args{"field", []FieldExtractConfig{
@@ -691,6 +773,21 @@ func TestWithExcludes(t *testing.T) {
 				},
 			},
 		},
+		{
+			"namespace",
+			ExcludeConfig{
+				Pods: []ExcludePodConfig{
+					{Namespace: "^kube-"},
+					{Name: "^debug-", Namespace: "^default$"},
+				},
+			},
+			kube.Excludes{
+				Pods: []kube.ExcludePods{
+					{Namespace: regexp.MustCompile(`^kube-`)},
+					{Name: regexp.MustCompile(`^debug-`), Namespace: regexp.MustCompile(`^default$`)},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -701,3 +798,35 @@ func TestWithExcludes(t *testing.T) {
 		})
 	}
 }
+
+func TestWithExcludesCollectorPods(t *testing.T) {
+	os.Setenv("TEST_POD_NAME", "otelcol-abcde")
+	defer os.Unsetenv("TEST_POD_NAME")
+
+	namespaceFile := filepath.Join(t.TempDir(), "namespace")
+	require.NoError(t, os.WriteFile(namespaceFile, []byte("sumologic\n"), 0600))
+	originalNamespaceFile := serviceAccountNamespaceFile
+	serviceAccountNamespaceFile = namespaceFile
+	defer func() { serviceAccountNamespaceFile = originalNamespaceFile }()
+
+	p := &kubernetesprocessor{}
+	require.NoError(t, WithExcludes(ExcludeConfig{
+		CollectorPods: CollectorPodsConfig{Enabled: true, PodNameFromEnvVar: "TEST_POD_NAME"},
+	})(p))
+	assert.Equal(t, &kube.CollectorPod{Name: "otelcol-abcde", Namespace: "sumologic"}, p.podIgnore.CollectorPod)
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	os.Setenv("TEST_POD_NAMESPACE", "monitoring")
+	defer os.Unsetenv("TEST_POD_NAMESPACE")
+	p = &kubernetesprocessor{}
+	require.NoError(t, WithExcludes(ExcludeConfig{
+		CollectorPods: CollectorPodsConfig{Enabled: true, NamespaceFromEnvVar: "TEST_POD_NAMESPACE"},
+	})(p))
+	assert.Equal(t, &kube.CollectorPod{Name: hostname, Namespace: "monitoring"}, p.podIgnore.CollectorPod)
+
+	p = &kubernetesprocessor{}
+	assert.Error(t, WithExcludes(ExcludeConfig{
+		CollectorPods: CollectorPodsConfig{Enabled: true, NamespaceFromEnvVar: "TEST_MISSING_NAMESPACE"},
+	})(p))
+}
@@ -26,80 +26,81 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sprocessor/kube"
 )
 
-// extractPodIds extracts IP and pod UID from attributes or request context.
-// It returns a value pair containing configured label and IP Address and/or Pod UID.
-// If empty value in return it means that attributes does not contains configured label to match resources for Pod.
-func extractPodID(ctx context.Context, attrs pdata.AttributeMap, associations []kube.Association) (podIdentifierKey string, podIdentifierValue kube.PodIdentifier) {
+const (
+	associationFromConnection        = "connection"
+	associationFromResourceAttribute = "resource_attribute"
+	associationFromBuildHostname     = "build_hostname"
+)
+
+// podIdentifier is a pair of the attribute key under which the pod identifier
+// should be recorded and the identifier value itself.
+type podIdentifier struct {
+	key   string
+	value kube.PodIdentifier
+}
+
+// extractPodIDs extracts IP and pod UID from attributes or request context.
+// It returns a list of value pairs containing configured label and IP Address,
+// Pod UID or `pod_name.namespace_name`, one for every association rule which
+// was able to produce a value, in the order of the configured rules.
+// Empty list means that attributes do not contain configured labels to match resources for Pod.
+func extractPodIDs(ctx context.Context, attrs pdata.AttributeMap, associations []kube.Association) []podIdentifier {
 	connectionIP := getConnectionIP(ctx)
 	hostname := stringAttributeFromMap(attrs, conventions.AttributeHostName)
 
 	// If pod association is not set
 	if len(associations) == 0 {
-		var podIP, labelIP kube.PodIdentifier
-		podIP = kube.PodIdentifier(stringAttributeFromMap(attrs, k8sIPLabelName))
-		labelIP = kube.PodIdentifier(stringAttributeFromMap(attrs, clientIPLabelName))
-		podIdentifierKey = k8sIPLabelName
-		if podIP != "" {
-			podIdentifierValue = podIP
-			return
-		} else if labelIP != "" {
-			podIdentifierValue = labelIP
-			return
-		} else if connectionIP != "" {
-			podIdentifierValue = connectionIP
-			return
-		} else if net.ParseIP(hostname) != nil {
-			podIdentifierValue = kube.PodIdentifier(hostname)
-			return
+		podIP := kube.PodIdentifier(stringAttributeFromMap(attrs, k8sIPLabelName))
+		labelIP := kube.PodIdentifier(stringAttributeFromMap(attrs, clientIPLabelName))
+		switch {
+		case podIP != "":
+			return []podIdentifier{{key: k8sIPLabelName, value: podIP}}
+		case labelIP != "":
+			return []podIdentifier{{key: k8sIPLabelName, value: labelIP}}
+		case connectionIP != "":
+			return []podIdentifier{{key: k8sIPLabelName, value: connectionIP}}
+		case net.ParseIP(hostname) != nil:
+			return []podIdentifier{{key: k8sIPLabelName, value: kube.PodIdentifier(hostname)}}
 		}
-		podIdentifierKey = ""
-		return
+		return nil
 	}
 
+	var ids []podIdentifier
 	for _, asso := range associations {
-		switch {
+		switch asso.From {
 		// If association configured to take IP address from connection
-		case asso.From == "connection" && connectionIP != "":
-			podIdentifierKey = k8sIPLabelName
-			podIdentifierValue = connectionIP
-			return
-		case asso.From == "resource_attribute": // If association configured by resource_attribute
+		case associationFromConnection:
+			if connectionIP != "" {
+				ids = append(ids, podIdentifier{key: k8sIPLabelName, value: connectionIP})
+			}
+		case associationFromResourceAttribute: // If association configured by resource_attribute
 			// In k8s environment, host.name label set to a pod IP address.
 			// If the value doesn't represent an IP address, we skip it.
 			if asso.Name == conventions.AttributeHostName {
 				if net.ParseIP(hostname) != nil {
-					podIdentifierKey = k8sIPLabelName
-					podIdentifierValue = kube.PodIdentifier(hostname)
-					return
+					ids = append(ids, podIdentifier{key: k8sIPLabelName, value: kube.PodIdentifier(hostname)})
 				}
 			} else {
 				// Extract values based on configured resource_attribute.
 				// Value should be a pod ip, pod uid or `pod_name.namespace_name`
 				attributeValue := stringAttributeFromMap(attrs, asso.Name)
 				if attributeValue != "" {
-					podIdentifierKey = asso.Name
-					podIdentifierValue = kube.PodIdentifier(attributeValue)
-					return
+					ids = append(ids, podIdentifier{key: asso.Name, value: kube.PodIdentifier(attributeValue)})
 				}
 			}
-		case asso.From == "build_hostname":
+		case associationFromBuildHostname:
 			// Build hostname from pod k8s.pod.name and k8s.namespace.name attributes
-			pod, ok := attrs.Get(conventions.AttributeK8SPodName)
-			if !ok {
-				return "", ""
-			}
-
-			namespace, ok := attrs.Get(conventions.AttributeK8SNamespaceName)
-			if !ok {
-				return "", ""
-			}
-
-			if pod.StringVal() == "" || namespace.StringVal() != "" {
-				return asso.Name, kube.PodIdentifier(fmt.Sprintf("%s.%s", pod.StringVal(), namespace.StringVal()))
+			pod := stringAttributeFromMap(attrs, conventions.AttributeK8SPodName)
+			namespace := stringAttributeFromMap(attrs, conventions.AttributeK8SNamespaceName)
+			if pod != "" && namespace != "" {
+				ids = append(ids, podIdentifier{
+					key:   asso.Name,
+					value: kube.PodIdentifier(fmt.Sprintf("%s.%s", pod, namespace)),
+				})
 			}
 		}
 	}
-	return "", kube.PodIdentifier("")
+	return ids
 }
 
 func getConnectionIP(ctx context.Context) kube.PodIdentifier {
@@ -16,11 +16,13 @@ package k8sprocessor
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sprocessor/kube"
@@ -29,6 +31,8 @@ import (
 const (
 	k8sIPLabelName    string = "k8s.pod.ip"
 	clientIPLabelName string = "ip"
+
+	cacheSyncPollInterval = 100 * time.Millisecond
 )
 
 type kubernetesprocessor struct {
@@ -41,51 +45,184 @@ type kubernetesprocessor struct {
 	podAssociations []kube.Association
 	podIgnore       kube.Excludes
 	delimiter       string
+
+	resyncPeriod     time.Duration
+	cacheSyncMode    CacheSyncMode
+	cacheSyncTimeout time.Duration
+	// cacheSynced is closed once the client caches are synced or the sync timed out
+	cacheSynced chan struct{}
+
+	staleCache          kube.StaleCacheSettings
+	staleCacheAttribute string
+
+	// kubeConfig (optional) selects the kubeconfig file and context the API is connected with
+	kubeConfig kube.KubeConfigSettings
+	// clusters (optional) are the kubeconfig settings of the clusters keyed by their names,
+	// the cluster of the data is selected by clusterAttribute
+	clusters         map[string]kube.KubeConfigSettings
+	clusterAttribute string
+	clusterClients   map[string]kube.Client
 }
 
 func (kp *kubernetesprocessor) initKubeClient(logger *zap.Logger, kubeClient kube.ClientProvider) error {
 	if kubeClient == nil {
 		kubeClient = kube.New
 	}
-	if !kp.passthroughMode {
-		kc, err := kubeClient(
-			logger,
-			kp.apiConfig,
-			kp.rules,
-			kp.filters,
-			kp.podAssociations,
-			kp.podIgnore,
-			nil,
-			nil,
-			nil,
-			kp.delimiter,
-			30*time.Second,
-			kube.DefaultPodDeleteGracePeriod,
-		)
-		if err != nil {
-			return err
+	if kp.passthroughMode {
+		return nil
+	}
+
+	if len(kp.clusters) > 0 {
+		kp.clusterClients = make(map[string]kube.Client, len(kp.clusters))
+		for name, settings := range kp.clusters {
+			kc, err := kp.newKubeClient(logger.With(zap.String("cluster", name)), kubeClient, settings)
+			if err != nil {
+				return fmt.Errorf("failed to create the client of cluster %q: %w", name, err)
+			}
+			kp.clusterClients[name] = kc
 		}
-		kp.kc = kc
+		return nil
 	}
+
+	kc, err := kp.newKubeClient(logger, kubeClient, kp.kubeConfig)
+	if err != nil {
+		return err
+	}
+	kp.kc = kc
 	return nil
 }
 
-func (kp *kubernetesprocessor) Start(_ context.Context, _ component.Host) error {
+// newKubeClient creates the client connected with the kubeconfig settings, if any
+func (kp *kubernetesprocessor) newKubeClient(logger *zap.Logger, kubeClient kube.ClientProvider, settings kube.KubeConfigSettings) (kube.Client, error) {
+	var (
+		newClientSet     kube.APIClientsetProvider
+		newDynamicClient kube.DynamicClientProvider
+	)
+	if settings != (kube.KubeConfigSettings{}) {
+		newClientSet = kube.NewKubeConfigClientsetProvider(settings)
+		newDynamicClient = kube.NewKubeConfigDynamicClientProvider(settings)
+	}
+
+	return kubeClient(
+		logger,
+		kp.apiConfig,
+		kp.rules,
+		kp.filters,
+		kp.podAssociations,
+		kp.podIgnore,
+		newClientSet,
+		newDynamicClient,
+		nil,
+		nil,
+		kp.delimiter,
+		30*time.Second,
+		kube.DefaultPodDeleteGracePeriod,
+		kp.resyncPeriod,
+		kp.staleCache,
+	)
+}
+
+// clients returns the clients of all the clusters
+func (kp *kubernetesprocessor) clients() []kube.Client {
+	if len(kp.clusterClients) == 0 {
+		return []kube.Client{kp.kc}
+	}
+	clients := make([]kube.Client, 0, len(kp.clusterClients))
+	for _, kc := range kp.clusterClients {
+		clients = append(clients, kc)
+	}
+	return clients
+}
+
+// clientFor returns the client of the cluster the resource comes from,
+// false when the cluster is not known
+func (kp *kubernetesprocessor) clientFor(resource pdata.Resource) (kube.Client, bool) {
+	if len(kp.clusterClients) == 0 {
+		return kp.kc, true
+	}
+	cluster, ok := resource.Attributes().Get(kp.clusterAttribute)
+	if !ok {
+		return nil, false
+	}
+	kc, ok := kp.clusterClients[cluster.StringVal()]
+	return kc, ok
+}
+
+func (kp *kubernetesprocessor) Start(ctx context.Context, _ component.Host) error {
 	if !kp.passthroughMode {
-		go kp.kc.Start()
+		for _, kc := range kp.clients() {
+			go kc.Start()
+		}
+
+		if kp.cacheSyncMode == CacheSyncModeHoldData || kp.cacheSyncMode == CacheSyncModeBlockStart {
+			kp.cacheSynced = make(chan struct{})
+			go kp.awaitCacheSync()
+		}
+
+		// Blocking here delays the start of the receivers in the pipeline,
+		// so no data is accepted before the metadata is available.
+		if kp.cacheSyncMode == CacheSyncModeBlockStart {
+			if err := kp.waitForCache(ctx); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
+// awaitCacheSync waits until the client caches are synced or the configured
+// timeout elapses and then releases the data held by waitForCache.
+func (kp *kubernetesprocessor) awaitCacheSync() {
+	defer close(kp.cacheSynced)
+
+	start := time.Now()
+	err := wait.PollImmediate(cacheSyncPollInterval, kp.cacheSyncTimeout, func() (bool, error) {
+		for _, kc := range kp.clients() {
+			if !kc.HasSynced() {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		kp.logger.Warn("Timed out waiting for the k8s metadata cache to sync, releasing data without complete metadata",
+			zap.Duration("timeout", kp.cacheSyncTimeout),
+		)
+		return
+	}
+	kp.logger.Info("k8s metadata cache synced", zap.Duration("elapsed", time.Since(start)))
+}
+
+// waitForCache blocks until the client caches are synced when the processor is
+// configured to wait for the cache sync.
+func (kp *kubernetesprocessor) waitForCache(ctx context.Context) error {
+	if kp.cacheSynced == nil {
+		return nil
+	}
+
+	select {
+	case <-kp.cacheSynced:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (kp *kubernetesprocessor) Shutdown(context.Context) error {
 	if !kp.passthroughMode {
-		kp.kc.Stop()
+		for _, kc := range kp.clients() {
+			kc.Stop()
+		}
 	}
 	return nil
 }
 
 // ProcessTraces process traces and add k8s metadata using resource IP or incoming IP as pod origin.
 func (kp *kubernetesprocessor) ProcessTraces(ctx context.Context, td pdata.Traces) (pdata.Traces, error) {
+	if err := kp.waitForCache(ctx); err != nil {
+		return td, err
+	}
+
 	rss := td.ResourceSpans()
 	for i := 0; i < rss.Len(); i++ {
 		kp.processResource(ctx, rss.At(i).Resource())
@@ -96,6 +233,10 @@ func (kp *kubernetesprocessor) ProcessTraces(ctx context.Context, td pdata.Trace
 
 // ProcessMetrics process metrics and add k8s metadata using resource IP, hostname or incoming IP as pod origin.
 func (kp *kubernetesprocessor) ProcessMetrics(ctx context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	if err := kp.waitForCache(ctx); err != nil {
+		return md, err
+	}
+
 	rm := md.ResourceMetrics()
 	for i := 0; i < rm.Len(); i++ {
 		kp.processResource(ctx, rm.At(i).Resource())
@@ -106,6 +247,10 @@ func (kp *kubernetesprocessor) ProcessMetrics(ctx context.Context, md pdata.Metr
 
 // ProcessLogs process logs and add k8s metadata using resource IP, hostname or incoming IP as pod origin.
 func (kp *kubernetesprocessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	if err := kp.waitForCache(ctx); err != nil {
+		return ld, err
+	}
+
 	rl := ld.ResourceLogs()
 	for i := 0; i < rl.Len(); i++ {
 		kp.processResource(ctx, rl.At(i).Resource())
@@ -114,30 +259,47 @@ func (kp *kubernetesprocessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (
 	return ld, nil
 }
 
-// processResource adds Pod metadata tags to resource based on pod association configuration
+// processResource adds Pod metadata tags to resource based on pod association configuration.
+// Association rules are tried in the configured order and the first one which
+// identifies a known Pod is used. If none of them does, the identifier produced
+// by the first rule is recorded on the resource.
 func (kp *kubernetesprocessor) processResource(ctx context.Context, resource pdata.Resource) {
-	podIdentifierKey, podIdentifierValue := extractPodID(ctx, resource.Attributes(), kp.podAssociations)
-	if podIdentifierValue == "" {
+	podIdentifiers := extractPodIDs(ctx, resource.Attributes(), kp.podAssociations)
+	if len(podIdentifiers) == 0 {
 		return
 	}
 
-	if podIdentifierKey != "" {
-		resource.Attributes().InsertString(podIdentifierKey, string(podIdentifierValue))
+	if kp.passthroughMode {
+		resource.Attributes().InsertString(podIdentifiers[0].key, string(podIdentifiers[0].value))
+		return
 	}
 
-	if kp.passthroughMode {
+	kc, ok := kp.clientFor(resource)
+	if !ok {
+		resource.Attributes().InsertString(podIdentifiers[0].key, string(podIdentifiers[0].value))
 		return
 	}
-	attrsToAdd := kp.getAttributesForPod(podIdentifierValue)
-	for key, val := range attrsToAdd {
-		resource.Attributes().InsertString(key, val)
+
+	for _, id := range podIdentifiers {
+		if attrsToAdd, ok := getAttributesForPod(kc, id.value); ok {
+			resource.Attributes().InsertString(id.key, string(id.value))
+			for key, val := range attrsToAdd {
+				resource.Attributes().InsertString(key, val)
+			}
+			if kp.staleCacheAttribute != "" && kc.IsStale() {
+				resource.Attributes().UpsertBool(kp.staleCacheAttribute, true)
+			}
+			return
+		}
 	}
+
+	resource.Attributes().InsertString(podIdentifiers[0].key, string(podIdentifiers[0].value))
 }
 
-func (kp *kubernetesprocessor) getAttributesForPod(identifier kube.PodIdentifier) map[string]string {
-	pod, ok := kp.kc.GetPod(identifier)
+func getAttributesForPod(kc kube.Client, identifier kube.PodIdentifier) (map[string]string, bool) {
+	pod, ok := kc.GetPod(identifier)
 	if !ok {
-		return nil
+		return nil, false
 	}
-	return pod.Attributes
+	return pod.Attributes, true
 }
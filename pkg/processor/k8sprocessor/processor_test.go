@@ -232,11 +232,14 @@ func TestProcessorBadClientProvider(t *testing.T) {
 		_ []kube.Association,
 		_ kube.Excludes,
 		_ kube.APIClientsetProvider,
+		_ kube.DynamicClientProvider,
 		_ kube.InformerProvider,
 		_ kube.OwnerProvider,
 		_ string,
 		_ time.Duration,
 		_ time.Duration,
+		_ time.Duration,
+		_ kube.StaleCacheSettings,
 	) (kube.Client, error) {
 		return nil, fmt.Errorf("bad client error")
 	}
@@ -641,6 +644,112 @@ func TestPodUID(t *testing.T) {
 	})
 }
 
+func TestStaleCacheAttribute(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.StaleCache.Enabled = true
+	m := newMultiTest(t, cfg, nil)
+
+	m.kubernetesProcessorOperation(func(kp *kubernetesprocessor) {
+		kp.podAssociations = []kube.Association{
+			{
+				From: "resource_attribute",
+				Name: "k8s.pod.uid",
+			},
+		}
+		kp.kc.(*fakeClient).Pods["ef10d10b-2da5-4030-812e-5f45c1531227"] = &kube.Pod{
+			Name:       "PodA",
+			Attributes: map[string]string{"k": "v"},
+		}
+	})
+
+	m.testConsume(context.Background(),
+		generateTraces(withPodUID("ef10d10b-2da5-4030-812e-5f45c1531227")),
+		generateMetrics(withPodUID("ef10d10b-2da5-4030-812e-5f45c1531227")),
+		generateLogs(withPodUID("ef10d10b-2da5-4030-812e-5f45c1531227")),
+		nil)
+
+	m.kubernetesProcessorOperation(func(kp *kubernetesprocessor) {
+		kp.kc.(*fakeClient).Stale = true
+	})
+
+	m.testConsume(context.Background(),
+		generateTraces(withPodUID("ef10d10b-2da5-4030-812e-5f45c1531227")),
+		generateMetrics(withPodUID("ef10d10b-2da5-4030-812e-5f45c1531227")),
+		generateLogs(withPodUID("ef10d10b-2da5-4030-812e-5f45c1531227")),
+		nil)
+
+	m.assertBatchesLen(2)
+	m.assertResource(0, func(r pdata.Resource) {
+		assertResourceHasStringAttribute(t, r, "k", "v")
+		_, ok := r.Attributes().Get(DefaultStaleCacheAttribute)
+		assert.False(t, ok)
+	})
+	m.assertResource(1, func(r pdata.Resource) {
+		assertResourceHasStringAttribute(t, r, "k", "v")
+		stale, ok := r.Attributes().Get(DefaultStaleCacheAttribute)
+		require.True(t, ok)
+		assert.True(t, stale.BoolVal())
+	})
+}
+
+func withCluster(cluster string) generateResourceFunc {
+	return func(res pdata.Resource) {
+		res.Attributes().InsertString(DefaultClusterAttribute, cluster)
+	}
+}
+
+func TestMultipleClusters(t *testing.T) {
+	m := newMultiTest(
+		t,
+		NewFactory().CreateDefaultConfig(),
+		nil,
+		WithClusters(DefaultClusterAttribute, ClusterConfig{Name: "prod"}, ClusterConfig{Name: "staging"}),
+	)
+
+	m.kubernetesProcessorOperation(func(kp *kubernetesprocessor) {
+		require.Nil(t, kp.kc)
+		require.Len(t, kp.clusterClients, 2)
+		kp.podAssociations = []kube.Association{
+			{
+				From: "resource_attribute",
+				Name: "k8s.pod.uid",
+			},
+		}
+		// the same pod UID is looked up in the cluster the data comes from
+		kp.clusterClients["prod"].(*fakeClient).Pods["ef10d10b-2da5-4030-812e-5f45c1531227"] = &kube.Pod{
+			Name:       "PodA",
+			Attributes: map[string]string{"k8s.pod.name": "prod-pod"},
+		}
+		kp.clusterClients["staging"].(*fakeClient).Pods["ef10d10b-2da5-4030-812e-5f45c1531227"] = &kube.Pod{
+			Name:       "PodA",
+			Attributes: map[string]string{"k8s.pod.name": "staging-pod"},
+		}
+	})
+
+	for _, cluster := range []string{"prod", "staging", "unknown"} {
+		m.testConsume(context.Background(),
+			generateTraces(withCluster(cluster), withPodUID("ef10d10b-2da5-4030-812e-5f45c1531227")),
+			generateMetrics(withCluster(cluster), withPodUID("ef10d10b-2da5-4030-812e-5f45c1531227")),
+			generateLogs(withCluster(cluster), withPodUID("ef10d10b-2da5-4030-812e-5f45c1531227")),
+			func(err error) {
+				assert.NoError(t, err)
+			})
+	}
+
+	m.assertBatchesLen(3)
+	m.assertResource(0, func(r pdata.Resource) {
+		assertResourceHasStringAttribute(t, r, "k8s.pod.name", "prod-pod")
+	})
+	m.assertResource(1, func(r pdata.Resource) {
+		assertResourceHasStringAttribute(t, r, "k8s.pod.name", "staging-pod")
+	})
+	m.assertResource(2, func(r pdata.Resource) {
+		_, ok := r.Attributes().Get("k8s.pod.name")
+		assert.False(t, ok)
+		assertResourceHasStringAttribute(t, r, "k8s.pod.uid", "ef10d10b-2da5-4030-812e-5f45c1531227")
+	})
+}
+
 func TestProcessorAddLabels(t *testing.T) {
 	m := newMultiTest(
 		t,
@@ -791,6 +900,105 @@ func TestProcessorByPodNameAndNamespace(t *testing.T) {
 	})
 }
 
+func TestProcessorAssociationStrategiesFallback(t *testing.T) {
+	testCases := []struct {
+		name          string
+		resourceFuncs []generateResourceFunc
+		expectedAttrs map[string]string
+	}{
+		{
+			name: "pod uid attribute matches",
+			resourceFuncs: []generateResourceFunc{
+				withPodUID("ef10d10b-2da5-4030-812e-5f45c1531227"),
+				withPodAndNamespace("PodB", "test"),
+			},
+			expectedAttrs: map[string]string{
+				"k8s.pod.uid": "ef10d10b-2da5-4030-812e-5f45c1531227",
+				"pod":         "PodA",
+			},
+		},
+		{
+			name: "unknown pod uid falls back to pod name and namespace",
+			resourceFuncs: []generateResourceFunc{
+				withPodUID("00000000-0000-0000-0000-000000000000"),
+				withPodAndNamespace("PodB", "test"),
+			},
+			expectedAttrs: map[string]string{
+				"k8s.pod.uid": "00000000-0000-0000-0000-000000000000",
+				"_hostname":   "PodB.test",
+				"pod":         "PodB",
+			},
+		},
+		{
+			name: "only pod name and namespace",
+			resourceFuncs: []generateResourceFunc{
+				withPodAndNamespace("PodB", "test"),
+			},
+			expectedAttrs: map[string]string{
+				"_hostname": "PodB.test",
+				"pod":       "PodB",
+			},
+		},
+		{
+			name: "no pod matches",
+			resourceFuncs: []generateResourceFunc{
+				withPodUID("00000000-0000-0000-0000-000000000000"),
+				withPodAndNamespace("PodC", "test"),
+			},
+			expectedAttrs: map[string]string{
+				"k8s.pod.uid": "00000000-0000-0000-0000-000000000000",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMultiTest(
+				t,
+				NewFactory().CreateDefaultConfig(),
+				nil,
+			)
+
+			m.kubernetesProcessorOperation(func(kp *kubernetesprocessor) {
+				kp.podAssociations = []kube.Association{
+					{
+						From: "resource_attribute",
+						Name: "k8s.pod.uid",
+					},
+					{
+						From: "build_hostname",
+						Name: "_hostname",
+					},
+				}
+				kp.kc.(*fakeClient).Pods["ef10d10b-2da5-4030-812e-5f45c1531227"] = &kube.Pod{
+					Name:       "PodA",
+					Attributes: map[string]string{"pod": "PodA"},
+				}
+				kp.kc.(*fakeClient).Pods["PodB.test"] = &kube.Pod{
+					Name:       "PodB",
+					Attributes: map[string]string{"pod": "PodB"},
+				}
+			})
+
+			m.testConsume(
+				context.Background(),
+				generateTraces(tc.resourceFuncs...),
+				generateMetrics(tc.resourceFuncs...),
+				generateLogs(tc.resourceFuncs...),
+				func(err error) {
+					assert.NoError(t, err)
+				})
+
+			m.assertBatchesLen(1)
+			m.assertResource(0, func(res pdata.Resource) {
+				for k, v := range tc.expectedAttrs {
+					assertResourceHasStringAttribute(t, res, k, v)
+				}
+			})
+		})
+	}
+}
+
 func TestMetricsProcessorHostname(t *testing.T) {
 	next := new(consumertest.MetricsSink)
 	var kp *kubernetesprocessor
@@ -996,6 +1204,111 @@ func TestStartStop(t *testing.T) {
 	assert.True(t, controller.HasStopped())
 }
 
+func TestWaitForCacheSync(t *testing.T) {
+	var kp *kubernetesprocessor
+	p, err := newTracesProcessor(
+		NewFactory().CreateDefaultConfig(),
+		consumertest.NewNop(),
+		WithWaitForCacheSync(CacheSyncModeHoldData, time.Second),
+		withExtractKubernetesProcessorInto(&kp),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, p.Shutdown(context.Background())) })
+
+	assert.Eventually(t, func() bool {
+		select {
+		case <-kp.cacheSynced:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, p.ConsumeTraces(context.Background(), generateTraces()))
+}
+
+func TestWaitForCacheSyncTimeout(t *testing.T) {
+	var kp *kubernetesprocessor
+	p, err := newTracesProcessor(
+		NewFactory().CreateDefaultConfig(),
+		consumertest.NewNop(),
+		WithWaitForCacheSync(CacheSyncModeHoldData, 300*time.Millisecond),
+		withExtractKubernetesProcessorInto(&kp),
+	)
+	require.NoError(t, err)
+	kp.kc.(*fakeClient).NotSynced = true
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, p.Shutdown(context.Background())) })
+
+	// Data is held until the cache syncs so a cancelled context makes it fail.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, p.ConsumeTraces(ctx, generateTraces()), context.Canceled)
+
+	// Once the timeout elapses data is released even though the cache is not synced.
+	assert.NoError(t, p.ConsumeTraces(context.Background(), generateTraces()))
+}
+
+func TestWaitForCacheSyncOnStart(t *testing.T) {
+	var kp *kubernetesprocessor
+	p, err := newTracesProcessor(
+		NewFactory().CreateDefaultConfig(),
+		consumertest.NewNop(),
+		WithWaitForCacheSync(CacheSyncModeBlockStart, time.Second),
+		withExtractKubernetesProcessorInto(&kp),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, p.Shutdown(context.Background())) })
+
+	// Start returns only after the cache is synced.
+	select {
+	case <-kp.cacheSynced:
+	default:
+		t.Fatal("processor started before the cache was synced")
+	}
+	assert.NoError(t, p.ConsumeTraces(context.Background(), generateTraces()))
+}
+
+func TestWaitForCacheSyncOnStartTimeout(t *testing.T) {
+	var kp *kubernetesprocessor
+	p, err := newTracesProcessor(
+		NewFactory().CreateDefaultConfig(),
+		consumertest.NewNop(),
+		WithWaitForCacheSync(CacheSyncModeBlockStart, 300*time.Millisecond),
+		withExtractKubernetesProcessorInto(&kp),
+	)
+	require.NoError(t, err)
+	kp.kc.(*fakeClient).NotSynced = true
+
+	start := time.Now()
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, p.Shutdown(context.Background())) })
+	assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+
+	assert.NoError(t, p.ConsumeTraces(context.Background(), generateTraces()))
+}
+
+func TestWaitForCacheSyncOnStartCancelled(t *testing.T) {
+	var kp *kubernetesprocessor
+	p, err := newTracesProcessor(
+		NewFactory().CreateDefaultConfig(),
+		consumertest.NewNop(),
+		WithWaitForCacheSync(CacheSyncModeBlockStart, time.Minute),
+		withExtractKubernetesProcessorInto(&kp),
+	)
+	require.NoError(t, err)
+	kp.kc.(*fakeClient).NotSynced = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, p.Start(ctx, componenttest.NewNopHost()), context.Canceled)
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
 func assertResourceHasStringAttribute(t *testing.T, r pdata.Resource, k, v string) {
 	got, ok := r.Attributes().Get(k)
 	assert.True(t, ok, fmt.Sprintf("resource does not contain attribute %s", k))
@@ -0,0 +1,69 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logdedupprocessor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the log deduplication processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:"-"`
+
+	// Window is the period the duplicates are suppressed in. The first of the duplicated
+	// log records is held back until the end of the window and then sent with their count.
+	// By default this is 10s.
+	Window time.Duration `mapstructure:"window"`
+
+	// Attributes are the keys of the attributes included in the fingerprint of the log record,
+	// along with its body. They are looked up in the log record attributes first and then
+	// in the resource attributes.
+	// By default all the resource attributes are included instead, so only the log records
+	// of the same resource (e.g. container) are deduplicated.
+	Attributes []string `mapstructure:"attributes"`
+
+	// CountAttribute is the log record attribute set to the number of the duplicates
+	// of the sent log record, including itself.
+	// By default this is log.dedup.count.
+	CountAttribute string `mapstructure:"count_attribute"`
+
+	// MaxRecords is the maximum number of the distinct log records held back.
+	// When it's reached, the log records are sent before the end of the window.
+	// By default this is 10000.
+	MaxRecords int `mapstructure:"max_records"`
+}
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.Window <= 0 {
+		return fmt.Errorf("window has to be positive: %s", cfg.Window)
+	}
+	if cfg.CountAttribute == "" {
+		return errors.New("count_attribute cannot be empty")
+	}
+	if cfg.MaxRecords <= 0 {
+		return fmt.Errorf("max_records has to be positive: %d", cfg.MaxRecords)
+	}
+	for _, attribute := range cfg.Attributes {
+		if attribute == "" {
+			return errors.New("attributes cannot contain an empty key")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logdedupprocessor
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[factory.Type()] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Processors[config.NewComponentID(typeStr)])
+
+	assert.Equal(t,
+		&Config{
+			ProcessorSettings: config.NewProcessorSettings(config.NewComponentIDWithName(typeStr, "custom")),
+			Window:            time.Minute,
+			Attributes:        []string{"host.name", "log.file.path"},
+			CountAttribute:    "duplicates",
+			MaxRecords:        100,
+		},
+		cfg.Processors[config.NewComponentIDWithName(typeStr, "custom")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		modify        func(cfg *Config)
+		expectedError string
+	}{
+		{
+			name:   "attributes",
+			modify: func(cfg *Config) { cfg.Attributes = []string{"host.name"} },
+		},
+		{
+			name:          "zero window",
+			modify:        func(cfg *Config) { cfg.Window = 0 },
+			expectedError: "window has to be positive: 0s",
+		},
+		{
+			name:          "empty count attribute",
+			modify:        func(cfg *Config) { cfg.CountAttribute = "" },
+			expectedError: "count_attribute cannot be empty",
+		},
+		{
+			name:          "zero max records",
+			modify:        func(cfg *Config) { cfg.MaxRecords = 0 },
+			expectedError: "max_records has to be positive: 0",
+		},
+		{
+			name:          "empty attribute",
+			modify:        func(cfg *Config) { cfg.Attributes = []string{"host.name", ""} },
+			expectedError: "attributes cannot contain an empty key",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logdedupprocessor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+func init() {
+	err := view.Register(viewDuplicates)
+	if err != nil {
+		fmt.Printf("Error registering log dedup processor's views: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+var mDuplicates = stats.Int64("log_dedup_duplicates", "Number of suppressed duplicated log records", stats.UnitDimensionless)
+
+var viewDuplicates = &view.View{
+	Name:        mDuplicates.Name(),
+	Description: mDuplicates.Description(),
+	Measure:     mDuplicates,
+	Aggregation: view.Sum(),
+}
+
+// recordDuplicates increments the metric by the number of the suppressed log records
+func recordDuplicates(count int) {
+	if count == 0 {
+		return
+	}
+	stats.Record(context.Background(), mDuplicates.M(int64(count)))
+}
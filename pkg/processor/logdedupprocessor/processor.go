@@ -0,0 +1,263 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logdedupprocessor
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.uber.org/zap"
+)
+
+type logDedupProcessor struct {
+	logger         *zap.Logger
+	next           consumer.Logs
+	window         time.Duration
+	attributes     []string
+	countAttribute string
+	maxRecords     int
+
+	// lock guards the held back records
+	lock sync.Mutex
+	// records are the held back records by their fingerprints
+	records map[uint64]*dedupRecord
+	// pending are the held back records in the order they were received in
+	pending []*dedupRecord
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// dedupRecord is the first of the duplicated log records, along with its resource
+// and instrumentation library, which are shared by the records received together
+type dedupRecord struct {
+	resource pdata.Resource
+	library  pdata.InstrumentationLibrary
+	record   pdata.LogRecord
+	count    int64
+}
+
+func newLogDedupProcessor(cfg *Config, next consumer.Logs, logger *zap.Logger) *logDedupProcessor {
+	return &logDedupProcessor{
+		logger:         logger,
+		next:           next,
+		window:         cfg.Window,
+		attributes:     cfg.Attributes,
+		countAttribute: cfg.CountAttribute,
+		maxRecords:     cfg.MaxRecords,
+		records:        make(map[uint64]*dedupRecord),
+	}
+}
+
+// Start starts sending the held back log records at the end of every window
+func (ldp *logDedupProcessor) Start(_ context.Context, _ component.Host) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	ldp.cancel = cancel
+	ldp.wg.Add(1)
+	go ldp.run(ctx)
+	return nil
+}
+
+// Shutdown stops sending the log records periodically and sends the held back ones
+func (ldp *logDedupProcessor) Shutdown(ctx context.Context) error {
+	if ldp.cancel == nil {
+		return nil
+	}
+	ldp.cancel()
+	ldp.wg.Wait()
+
+	ldp.send(ctx, ldp.takeRecords())
+	return nil
+}
+
+func (ldp *logDedupProcessor) run(ctx context.Context) {
+	defer ldp.wg.Done()
+
+	ticker := time.NewTicker(ldp.window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ldp.send(ctx, ldp.takeRecords())
+		}
+	}
+}
+
+// ProcessLogs holds back the log records until the end of the window, counting their duplicates.
+// The log records are passed down the pipeline when the window ends, so nothing is returned.
+func (ldp *logDedupProcessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	var full []*dedupRecord
+	duplicates := 0
+
+	ldp.lock.Lock()
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resourceAttributes := rl.Resource().Attributes()
+		resourceKey := ldp.resourceKey(resourceAttributes)
+		// the resource is copied only if any of its records is held back
+		var resource *pdata.Resource
+
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			var library *pdata.InstrumentationLibrary
+
+			records := ill.LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				lr := records.At(k)
+				fingerprint := ldp.fingerprint(resourceKey, resourceAttributes, lr)
+				if r, ok := ldp.records[fingerprint]; ok {
+					r.count++
+					duplicates++
+					continue
+				}
+
+				if resource == nil {
+					r := pdata.NewResource()
+					rl.Resource().CopyTo(r)
+					resource = &r
+				}
+				if library == nil {
+					l := pdata.NewInstrumentationLibrary()
+					ill.InstrumentationLibrary().CopyTo(l)
+					library = &l
+				}
+				r := &dedupRecord{
+					resource: *resource,
+					library:  *library,
+					record:   pdata.NewLogRecord(),
+					count:    1,
+				}
+				lr.CopyTo(r.record)
+				ldp.records[fingerprint] = r
+				ldp.pending = append(ldp.pending, r)
+
+				if len(ldp.pending) >= ldp.maxRecords {
+					full = append(full, ldp.takeRecordsLocked()...)
+				}
+			}
+		}
+	}
+	ldp.lock.Unlock()
+
+	recordDuplicates(duplicates)
+	if len(full) > 0 {
+		ldp.send(ctx, full)
+	}
+	return ld, processorhelper.ErrSkipProcessingData
+}
+
+// fingerprint returns the hash of the body and the configured attributes of the log record.
+// If no attributes are configured, the key of the resource is used instead.
+func (ldp *logDedupProcessor) fingerprint(resourceKey []byte, resourceAttributes pdata.AttributeMap, lr pdata.LogRecord) uint64 {
+	h := fnv.New64a()
+	// hash.Hash never returns an error on write
+	_, _ = h.Write([]byte(lr.Body().AsString()))
+
+	if len(ldp.attributes) == 0 {
+		_, _ = h.Write(resourceKey)
+		return h.Sum64()
+	}
+
+	for _, key := range ldp.attributes {
+		value, ok := lr.Attributes().Get(key)
+		if !ok {
+			value, ok = resourceAttributes.Get(key)
+		}
+		if !ok {
+			_, _ = h.Write([]byte{0, 1})
+			continue
+		}
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(value.AsString()))
+	}
+	return h.Sum64()
+}
+
+// resourceKey returns the key identifying the resource by all its attributes,
+// it's used only when no attributes are configured
+func (ldp *logDedupProcessor) resourceKey(resourceAttributes pdata.AttributeMap) []byte {
+	if len(ldp.attributes) > 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, resourceAttributes.Len())
+	resourceAttributes.Range(func(k string, v pdata.AttributeValue) bool {
+		keys = append(keys, k+"="+v.AsString())
+		return true
+	})
+	sort.Strings(keys)
+	return []byte("\x00" + strings.Join(keys, "\x00"))
+}
+
+// takeRecords returns the held back records and starts a new window
+func (ldp *logDedupProcessor) takeRecords() []*dedupRecord {
+	ldp.lock.Lock()
+	defer ldp.lock.Unlock()
+	return ldp.takeRecordsLocked()
+}
+
+func (ldp *logDedupProcessor) takeRecordsLocked() []*dedupRecord {
+	pending := ldp.pending
+	ldp.pending = nil
+	ldp.records = make(map[uint64]*dedupRecord)
+	return pending
+}
+
+// send passes the log records down the pipeline, with their counts set
+func (ldp *logDedupProcessor) send(ctx context.Context, records []*dedupRecord) {
+	if len(records) == 0 {
+		return
+	}
+
+	ld := pdata.NewLogs()
+	var (
+		rl         pdata.ResourceLogs
+		ill        pdata.InstrumentationLibraryLogs
+		lastRecord *dedupRecord
+	)
+	for _, r := range records {
+		// the records received together share the resource and library, so they are sent together as well
+		if lastRecord == nil || r.resource != lastRecord.resource {
+			rl = ld.ResourceLogs().AppendEmpty()
+			r.resource.CopyTo(rl.Resource())
+			lastRecord = nil
+		}
+		if lastRecord == nil || r.library != lastRecord.library {
+			ill = rl.InstrumentationLibraryLogs().AppendEmpty()
+			r.library.CopyTo(ill.InstrumentationLibrary())
+		}
+		lastRecord = r
+
+		lr := ill.LogRecords().AppendEmpty()
+		r.record.CopyTo(lr)
+		lr.Attributes().UpsertInt(ldp.countAttribute, r.count)
+	}
+
+	if err := ldp.next.ConsumeLogs(ctx, ld); err != nil {
+		ldp.logger.Error("Failed to send the deduplicated log records", zap.Error(err))
+	}
+}
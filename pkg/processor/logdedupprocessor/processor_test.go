@@ -0,0 +1,169 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logdedupprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.uber.org/zap"
+)
+
+func newTestProcessor(t *testing.T, modify func(cfg *Config)) (*logDedupProcessor, *consumertest.LogsSink) {
+	cfg := createDefaultConfig().(*Config)
+	if modify != nil {
+		modify(cfg)
+	}
+	require.NoError(t, cfg.Validate())
+
+	sink := &consumertest.LogsSink{}
+	return newLogDedupProcessor(cfg, sink, zap.NewNop()), sink
+}
+
+// appendResourceLogs appends the resource of the pod with the record per body
+func appendResourceLogs(logs pdata.Logs, pod string, bodies ...string) {
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString("k8s.pod.name", pod)
+	records := rl.InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+	for _, body := range bodies {
+		records.AppendEmpty().Body().SetStringVal(body)
+	}
+}
+
+type sentRecord struct {
+	pod   string
+	body  string
+	count int64
+}
+
+func getSentRecords(t *testing.T, sink *consumertest.LogsSink) []sentRecord {
+	records := []sentRecord{}
+	for _, logs := range sink.AllLogs() {
+		rls := logs.ResourceLogs()
+		for i := 0; i < rls.Len(); i++ {
+			pod, ok := rls.At(i).Resource().Attributes().Get("k8s.pod.name")
+			require.True(t, ok)
+			ills := rls.At(i).InstrumentationLibraryLogs()
+			for j := 0; j < ills.Len(); j++ {
+				lrs := ills.At(j).LogRecords()
+				for k := 0; k < lrs.Len(); k++ {
+					count, ok := lrs.At(k).Attributes().Get(defaultCountAttribute)
+					require.True(t, ok)
+					records = append(records, sentRecord{
+						pod:   pod.StringVal(),
+						body:  lrs.At(k).Body().StringVal(),
+						count: count.IntVal(),
+					})
+				}
+			}
+		}
+	}
+	return records
+}
+
+func TestProcessLogsSuppressesDuplicates(t *testing.T) {
+	ldp, sink := newTestProcessor(t, nil)
+
+	logs := pdata.NewLogs()
+	appendResourceLogs(logs, "crashing", "panic: oops", "panic: oops", "restarting")
+	appendResourceLogs(logs, "other", "panic: oops")
+	_, err := ldp.ProcessLogs(context.Background(), logs)
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+
+	logs = pdata.NewLogs()
+	appendResourceLogs(logs, "crashing", "panic: oops")
+	_, err = ldp.ProcessLogs(context.Background(), logs)
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+
+	// nothing is sent until the end of the window
+	assert.Empty(t, sink.AllLogs())
+
+	ldp.send(context.Background(), ldp.takeRecords())
+	assert.Equal(t, []sentRecord{
+		{pod: "crashing", body: "panic: oops", count: 3},
+		{pod: "crashing", body: "restarting", count: 1},
+		{pod: "other", body: "panic: oops", count: 1},
+	}, getSentRecords(t, sink))
+	require.Len(t, sink.AllLogs(), 1)
+	assert.Equal(t, 2, sink.AllLogs()[0].ResourceLogs().Len())
+
+	// the duplicates are counted again in the next window
+	sink.Reset()
+	logs = pdata.NewLogs()
+	appendResourceLogs(logs, "crashing", "panic: oops")
+	_, err = ldp.ProcessLogs(context.Background(), logs)
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+	ldp.send(context.Background(), ldp.takeRecords())
+	assert.Equal(t, []sentRecord{{pod: "crashing", body: "panic: oops", count: 1}}, getSentRecords(t, sink))
+}
+
+func TestProcessLogsRecordAttributes(t *testing.T) {
+	ldp, sink := newTestProcessor(t, func(cfg *Config) {
+		cfg.Attributes = []string{"k8s.pod.name", "stream"}
+	})
+
+	logs := pdata.NewLogs()
+	appendResourceLogs(logs, "pod", "line", "line", "line")
+	records := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).LogRecords()
+	records.At(0).Attributes().InsertString("stream", "stdout")
+	records.At(1).Attributes().InsertString("stream", "stderr")
+	records.At(2).Attributes().InsertString("stream", "stdout")
+
+	_, err := ldp.ProcessLogs(context.Background(), logs)
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+	ldp.send(context.Background(), ldp.takeRecords())
+
+	assert.Equal(t, []sentRecord{
+		{pod: "pod", body: "line", count: 2},
+		{pod: "pod", body: "line", count: 1},
+	}, getSentRecords(t, sink))
+}
+
+func TestProcessLogsMaxRecords(t *testing.T) {
+	ldp, sink := newTestProcessor(t, func(cfg *Config) {
+		cfg.MaxRecords = 2
+	})
+
+	logs := pdata.NewLogs()
+	appendResourceLogs(logs, "pod", "first", "first", "second", "third")
+	_, err := ldp.ProcessLogs(context.Background(), logs)
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+
+	// the records are sent once there are 2 distinct records held back
+	assert.Equal(t, []sentRecord{
+		{pod: "pod", body: "first", count: 2},
+		{pod: "pod", body: "second", count: 1},
+	}, getSentRecords(t, sink))
+	assert.Len(t, ldp.pending, 1)
+}
+
+func TestShutdownSendsHeldBackRecords(t *testing.T) {
+	ldp, sink := newTestProcessor(t, nil)
+	require.NoError(t, ldp.Start(context.Background(), componenttest.NewNopHost()))
+
+	logs := pdata.NewLogs()
+	appendResourceLogs(logs, "pod", "line", "line")
+	_, err := ldp.ProcessLogs(context.Background(), logs)
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+
+	require.NoError(t, ldp.Shutdown(context.Background()))
+	assert.Equal(t, []sentRecord{{pod: "pod", body: "line", count: 2}}, getSentRecords(t, sink))
+}
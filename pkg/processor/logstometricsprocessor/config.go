@@ -0,0 +1,191 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstometricsprocessor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// MetricType is the type of the metric derived from the log records
+type MetricType string
+
+const (
+	// MetricTypeCount counts the matching log records
+	MetricTypeCount MetricType = "count"
+	// MetricTypeSum sums the values extracted from the matching log records
+	MetricTypeSum MetricType = "sum"
+	// MetricTypeHistogram records the distribution of the values extracted from the matching log records
+	MetricTypeHistogram MetricType = "histogram"
+)
+
+// Config defines configuration for the logs to metrics processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:"-"`
+
+	// Exporters are the IDs of the metrics exporters the derived metrics are sent to.
+	// The exporters have to be a part of a metrics pipeline.
+	Exporters []string `mapstructure:"exporters"`
+
+	// Interval defines how often the metrics are sent to the exporters.
+	// By default this is 60s.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// DropMatchedLogs makes the processor drop the log records matching at least one of the metrics,
+	// so only the metrics are sent.
+	DropMatchedLogs bool `mapstructure:"drop_matched_logs"`
+
+	// Metrics are the metrics derived from the log records.
+	Metrics []MetricConfig `mapstructure:"metrics"`
+}
+
+// MetricConfig defines the metric derived from the log records.
+// All the conditions of the metric have to be met for the record to match,
+// the metric without conditions matches all the records.
+type MetricConfig struct {
+	// Name is the name of the metric.
+	Name string `mapstructure:"name"`
+
+	// Description is the description of the metric.
+	Description string `mapstructure:"description"`
+
+	// Unit is the unit of the metric, e.g. ms.
+	Unit string `mapstructure:"unit"`
+
+	// Type is either count, sum or histogram.
+	Type MetricType `mapstructure:"type"`
+
+	// BodyRegex is the regular expression which the log body has to match.
+	BodyRegex string `mapstructure:"body_regex"`
+
+	// Attributes are the conditions on the log record attributes,
+	// or the resource attributes if the record doesn't have the attribute.
+	Attributes []AttributeConfig `mapstructure:"attributes"`
+
+	// ValueAttribute is the attribute with the value of the sum and histogram metrics.
+	ValueAttribute string `mapstructure:"value_attribute"`
+
+	// ValueRegex is the regular expression extracting the value of the sum and histogram metrics
+	// from the log body, the value is its first capturing group.
+	ValueRegex string `mapstructure:"value_regex"`
+
+	// Labels are the attributes of the log records copied to the metric data points,
+	// the records with different values are counted separately.
+	Labels []string `mapstructure:"labels"`
+
+	// Buckets are the upper bounds of the histogram buckets.
+	// By default these are the buckets suited for the durations in milliseconds.
+	Buckets []float64 `mapstructure:"buckets"`
+}
+
+// AttributeConfig defines the condition on the attribute value
+type AttributeConfig struct {
+	// Key is the attribute name.
+	Key string `mapstructure:"key"`
+
+	// Regex is the regular expression which the attribute value has to match.
+	Regex string `mapstructure:"regex"`
+}
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	if len(cfg.Exporters) == 0 {
+		return errors.New("at least one exporter has to be configured")
+	}
+	for _, exporter := range cfg.Exporters {
+		if _, err := config.NewComponentIDFromString(exporter); err != nil {
+			return fmt.Errorf("invalid exporter: %w", err)
+		}
+	}
+	if cfg.Interval <= 0 {
+		return fmt.Errorf("interval has to be positive: %s", cfg.Interval)
+	}
+	if len(cfg.Metrics) == 0 {
+		return errors.New("at least one metric has to be configured")
+	}
+
+	names := make(map[string]struct{}, len(cfg.Metrics))
+	for _, metric := range cfg.Metrics {
+		if err := metric.Validate(); err != nil {
+			return fmt.Errorf("metric %q has invalid configuration: %w", metric.Name, err)
+		}
+		if _, ok := names[metric.Name]; ok {
+			return fmt.Errorf("metric %q is configured more than once", metric.Name)
+		}
+		names[metric.Name] = struct{}{}
+	}
+	return nil
+}
+
+// Validate checks if the metric configuration is valid
+func (metric *MetricConfig) Validate() error {
+	if metric.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+
+	switch metric.Type {
+	case MetricTypeCount:
+		if metric.ValueAttribute != "" || metric.ValueRegex != "" {
+			return errors.New("value_attribute and value_regex cannot be used with count metric")
+		}
+	case MetricTypeSum, MetricTypeHistogram:
+		if (metric.ValueAttribute == "") == (metric.ValueRegex == "") {
+			return errors.New("exactly one of value_attribute and value_regex has to be specified")
+		}
+	default:
+		return fmt.Errorf("unexpected type: %s", metric.Type)
+	}
+
+	if len(metric.Buckets) > 0 {
+		if metric.Type != MetricTypeHistogram {
+			return errors.New("buckets can be used with histogram metric only")
+		}
+		if !sort.Float64sAreSorted(metric.Buckets) {
+			return errors.New("buckets have to be sorted")
+		}
+	}
+
+	if _, err := regexp.Compile(metric.BodyRegex); err != nil {
+		return fmt.Errorf("invalid body_regex: %w", err)
+	}
+	if metric.ValueRegex != "" {
+		regex, err := regexp.Compile(metric.ValueRegex)
+		if err != nil {
+			return fmt.Errorf("invalid value_regex: %w", err)
+		}
+		if regex.NumSubexp() == 0 {
+			return errors.New("value_regex has to have a capturing group")
+		}
+	}
+	for _, attr := range metric.Attributes {
+		if attr.Key == "" {
+			return errors.New("attribute key cannot be empty")
+		}
+		if _, err := regexp.Compile(attr.Regex); err != nil {
+			return fmt.Errorf("invalid regex of attribute %s: %w", attr.Key, err)
+		}
+	}
+	for _, label := range metric.Labels {
+		if label == "" {
+			return errors.New("label cannot be empty")
+		}
+	}
+	return nil
+}
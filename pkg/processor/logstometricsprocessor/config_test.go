@@ -0,0 +1,188 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstometricsprocessor
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[factory.Type()] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Processors[config.NewComponentID(typeStr)])
+
+	assert.Equal(t,
+		&Config{
+			ProcessorSettings: config.NewProcessorSettings(config.NewComponentIDWithName(typeStr, "custom")),
+			Exporters:         []string{"nop"},
+			Interval:          30 * time.Second,
+			DropMatchedLogs:   true,
+			Metrics: []MetricConfig{
+				{
+					Name:        "nginx.requests",
+					Description: "The number of the nginx requests",
+					Type:        MetricTypeCount,
+					BodyRegex:   "HTTP/1.1",
+					Attributes: []AttributeConfig{
+						{Key: "k8s.container.name", Regex: "^nginx$"},
+					},
+					Labels: []string{"k8s.namespace.name"},
+				},
+				{
+					Name:       "nginx.request.duration",
+					Unit:       "ms",
+					Type:       MetricTypeHistogram,
+					ValueRegex: `request_time=(\d+)`,
+					Buckets:    []float64{10, 100, 1000},
+				},
+			},
+		},
+		cfg.Processors[config.NewComponentIDWithName(typeStr, "custom")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		modify        func(cfg *Config)
+		expectedError string
+	}{
+		{
+			name:   "valid config",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:          "no exporters",
+			modify:        func(cfg *Config) { cfg.Exporters = nil },
+			expectedError: "at least one exporter has to be configured",
+		},
+		{
+			name:          "invalid exporter",
+			modify:        func(cfg *Config) { cfg.Exporters = []string{"sumologic/"} },
+			expectedError: `invalid exporter: in "sumologic/" id: the part after / should not be empty`,
+		},
+		{
+			name:          "non-positive interval",
+			modify:        func(cfg *Config) { cfg.Interval = 0 },
+			expectedError: "interval has to be positive: 0s",
+		},
+		{
+			name:          "no metrics",
+			modify:        func(cfg *Config) { cfg.Metrics = nil },
+			expectedError: "at least one metric has to be configured",
+		},
+		{
+			name:          "duplicated metric",
+			modify:        func(cfg *Config) { cfg.Metrics = append(cfg.Metrics, cfg.Metrics[0]) },
+			expectedError: `metric "requests" is configured more than once`,
+		},
+		{
+			name:          "no name",
+			modify:        func(cfg *Config) { cfg.Metrics[0].Name = "" },
+			expectedError: `metric "" has invalid configuration: name cannot be empty`,
+		},
+		{
+			name:          "unexpected type",
+			modify:        func(cfg *Config) { cfg.Metrics[0].Type = "gauge" },
+			expectedError: `metric "requests" has invalid configuration: unexpected type: gauge`,
+		},
+		{
+			name:          "count with value",
+			modify:        func(cfg *Config) { cfg.Metrics[0].ValueAttribute = "duration" },
+			expectedError: `metric "requests" has invalid configuration: value_attribute and value_regex cannot be used with count metric`,
+		},
+		{
+			name:          "sum without value",
+			modify:        func(cfg *Config) { cfg.Metrics[1].ValueAttribute = "" },
+			expectedError: `metric "duration" has invalid configuration: exactly one of value_attribute and value_regex has to be specified`,
+		},
+		{
+			name:          "sum with both values",
+			modify:        func(cfg *Config) { cfg.Metrics[1].ValueRegex = `duration=(\d+)` },
+			expectedError: `metric "duration" has invalid configuration: exactly one of value_attribute and value_regex has to be specified`,
+		},
+		{
+			name:          "buckets of sum",
+			modify:        func(cfg *Config) { cfg.Metrics[1].Buckets = []float64{1, 10} },
+			expectedError: `metric "duration" has invalid configuration: buckets can be used with histogram metric only`,
+		},
+		{
+			name: "unsorted buckets",
+			modify: func(cfg *Config) {
+				cfg.Metrics[1].Type = MetricTypeHistogram
+				cfg.Metrics[1].Buckets = []float64{10, 1}
+			},
+			expectedError: `metric "duration" has invalid configuration: buckets have to be sorted`,
+		},
+		{
+			name:          "invalid body_regex",
+			modify:        func(cfg *Config) { cfg.Metrics[0].BodyRegex = "(" },
+			expectedError: "metric \"requests\" has invalid configuration: invalid body_regex: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name: "value_regex without capturing group",
+			modify: func(cfg *Config) {
+				cfg.Metrics[1].ValueAttribute = ""
+				cfg.Metrics[1].ValueRegex = `duration=\d+`
+			},
+			expectedError: `metric "duration" has invalid configuration: value_regex has to have a capturing group`,
+		},
+		{
+			name:          "empty attribute key",
+			modify:        func(cfg *Config) { cfg.Metrics[0].Attributes = []AttributeConfig{{Regex: ".*"}} },
+			expectedError: `metric "requests" has invalid configuration: attribute key cannot be empty`,
+		},
+		{
+			name:          "empty label",
+			modify:        func(cfg *Config) { cfg.Metrics[0].Labels = []string{""} },
+			expectedError: `metric "requests" has invalid configuration: label cannot be empty`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Exporters = []string{"sumologic"}
+			cfg.Metrics = []MetricConfig{
+				{Name: "requests", Type: MetricTypeCount},
+				{Name: "duration", Type: MetricTypeSum, ValueAttribute: "duration"},
+			}
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
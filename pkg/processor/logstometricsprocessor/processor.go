@@ -0,0 +1,425 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstometricsprocessor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.uber.org/zap"
+)
+
+type logsToMetricsProcessor struct {
+	logger          *zap.Logger
+	exporterIDs     []config.ComponentID
+	interval        time.Duration
+	dropMatchedLogs bool
+	metrics         []*metric
+	exporters       []consumer.Metrics
+
+	// lock guards the series of the metrics
+	lock      sync.Mutex
+	startTime pdata.Timestamp
+	// now returns the current time, it's overridden in tests
+	now func() time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type metric struct {
+	name           string
+	description    string
+	unit           string
+	metricType     MetricType
+	bodyRegex      *regexp.Regexp
+	attributes     []attributeCondition
+	valueAttribute string
+	valueRegex     *regexp.Regexp
+	labels         []string
+	buckets        []float64
+
+	// series are the aggregated values per the label values
+	series map[string]*series
+}
+
+type attributeCondition struct {
+	key   string
+	regex *regexp.Regexp
+}
+
+// series is the aggregated value of the metric for the label values
+type series struct {
+	labels       map[string]string
+	count        uint64
+	sum          float64
+	bucketCounts []uint64
+}
+
+func newLogsToMetricsProcessor(cfg *Config, logger *zap.Logger) (*logsToMetricsProcessor, error) {
+	exporterIDs := make([]config.ComponentID, 0, len(cfg.Exporters))
+	for _, exporter := range cfg.Exporters {
+		id, err := config.NewComponentIDFromString(exporter)
+		if err != nil {
+			return nil, err
+		}
+		exporterIDs = append(exporterIDs, id)
+	}
+
+	metrics := make([]*metric, 0, len(cfg.Metrics))
+	for _, mc := range cfg.Metrics {
+		m, err := newMetric(mc)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+
+	return &logsToMetricsProcessor{
+		logger:          logger,
+		exporterIDs:     exporterIDs,
+		interval:        cfg.Interval,
+		dropMatchedLogs: cfg.DropMatchedLogs,
+		metrics:         metrics,
+		now:             time.Now,
+	}, nil
+}
+
+func newMetric(mc MetricConfig) (*metric, error) {
+	m := &metric{
+		name:           mc.Name,
+		description:    mc.Description,
+		unit:           mc.Unit,
+		metricType:     mc.Type,
+		valueAttribute: mc.ValueAttribute,
+		labels:         mc.Labels,
+		buckets:        mc.Buckets,
+		series:         make(map[string]*series),
+	}
+	if m.metricType == MetricTypeHistogram && len(m.buckets) == 0 {
+		m.buckets = defaultBuckets
+	}
+
+	if mc.BodyRegex != "" {
+		regex, err := regexp.Compile(mc.BodyRegex)
+		if err != nil {
+			return nil, err
+		}
+		m.bodyRegex = regex
+	}
+	if mc.ValueRegex != "" {
+		regex, err := regexp.Compile(mc.ValueRegex)
+		if err != nil {
+			return nil, err
+		}
+		m.valueRegex = regex
+	}
+	for _, attr := range mc.Attributes {
+		regex, err := regexp.Compile(attr.Regex)
+		if err != nil {
+			return nil, err
+		}
+		m.attributes = append(m.attributes, attributeCondition{key: attr.Key, regex: regex})
+	}
+	return m, nil
+}
+
+// Start looks up the metrics exporters and starts sending the metrics periodically
+func (ltmp *logsToMetricsProcessor) Start(_ context.Context, host component.Host) error {
+	exporters := host.GetExporters()[config.MetricsDataType]
+	for _, id := range ltmp.exporterIDs {
+		exporter, ok := exporters[id]
+		if !ok {
+			return fmt.Errorf("metrics exporter %s not found", id)
+		}
+		me, ok := exporter.(component.MetricsExporter)
+		if !ok {
+			return fmt.Errorf("exporter %s is not a metrics exporter", id)
+		}
+		ltmp.exporters = append(ltmp.exporters, me)
+	}
+	ltmp.startTime = pdata.NewTimestampFromTime(ltmp.now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ltmp.cancel = cancel
+	ltmp.wg.Add(1)
+	go ltmp.run(ctx)
+	return nil
+}
+
+// Shutdown stops sending the metrics periodically and sends them for the last time
+func (ltmp *logsToMetricsProcessor) Shutdown(ctx context.Context) error {
+	if ltmp.cancel == nil {
+		return nil
+	}
+	ltmp.cancel()
+	ltmp.wg.Wait()
+
+	ltmp.sendMetrics(ctx)
+	return nil
+}
+
+func (ltmp *logsToMetricsProcessor) run(ctx context.Context) {
+	defer ltmp.wg.Done()
+
+	ticker := time.NewTicker(ltmp.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ltmp.sendMetrics(ctx)
+		}
+	}
+}
+
+// ProcessLogs updates the metrics with the matching log records,
+// which are dropped if drop_matched_logs is set
+func (ltmp *logsToMetricsProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	ltmp.lock.Lock()
+	defer ltmp.lock.Unlock()
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resourceAttributes := rl.Resource().Attributes()
+
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ills.At(j).LogRecords().RemoveIf(func(lr pdata.LogRecord) bool {
+				matched := false
+				for _, m := range ltmp.metrics {
+					if m.record(resourceAttributes, lr) {
+						matched = true
+					}
+				}
+				return matched && ltmp.dropMatchedLogs
+			})
+		}
+		ills.RemoveIf(func(ill pdata.InstrumentationLibraryLogs) bool {
+			return ill.LogRecords().Len() == 0
+		})
+	}
+	rls.RemoveIf(func(rl pdata.ResourceLogs) bool {
+		return rl.InstrumentationLibraryLogs().Len() == 0
+	})
+
+	if rls.Len() == 0 {
+		return ld, processorhelper.ErrSkipProcessingData
+	}
+	return ld, nil
+}
+
+// record updates the metric with the log record and returns whether the record matches the metric.
+// The records without the value are not counted in the sum and histogram metrics.
+func (m *metric) record(resourceAttributes pdata.AttributeMap, lr pdata.LogRecord) bool {
+	if !m.matches(resourceAttributes, lr) {
+		return false
+	}
+
+	value := 0.0
+	if m.metricType != MetricTypeCount {
+		var ok bool
+		if value, ok = m.value(resourceAttributes, lr); !ok {
+			return true
+		}
+	}
+
+	labels := make(map[string]string, len(m.labels))
+	var key strings.Builder
+	for _, label := range m.labels {
+		if v, ok := lookupAttribute(resourceAttributes, lr.Attributes(), label); ok {
+			labels[label] = v.AsString()
+			key.WriteString(labels[label])
+		}
+		key.WriteByte(0)
+	}
+
+	s, ok := m.series[key.String()]
+	if !ok {
+		s = &series{labels: labels}
+		if m.metricType == MetricTypeHistogram {
+			s.bucketCounts = make([]uint64, len(m.buckets)+1)
+		}
+		m.series[key.String()] = s
+	}
+	s.count++
+	s.sum += value
+	if s.bucketCounts != nil {
+		s.bucketCounts[sort.SearchFloat64s(m.buckets, value)]++
+	}
+	return true
+}
+
+func (m *metric) matches(resourceAttributes pdata.AttributeMap, lr pdata.LogRecord) bool {
+	if m.bodyRegex != nil {
+		if lr.Body().Type() != pdata.AttributeValueTypeString || !m.bodyRegex.MatchString(lr.Body().StringVal()) {
+			return false
+		}
+	}
+	for _, cond := range m.attributes {
+		value, ok := lookupAttribute(resourceAttributes, lr.Attributes(), cond.key)
+		if !ok || !cond.regex.MatchString(value.AsString()) {
+			return false
+		}
+	}
+	return true
+}
+
+// value extracts the numeric value of the log record, either from the attribute or the body
+func (m *metric) value(resourceAttributes pdata.AttributeMap, lr pdata.LogRecord) (float64, bool) {
+	if m.valueRegex != nil {
+		if lr.Body().Type() != pdata.AttributeValueTypeString {
+			return 0, false
+		}
+		match := m.valueRegex.FindStringSubmatch(lr.Body().StringVal())
+		if match == nil {
+			return 0, false
+		}
+		return parseValue(match[1])
+	}
+
+	value, ok := lookupAttribute(resourceAttributes, lr.Attributes(), m.valueAttribute)
+	if !ok {
+		return 0, false
+	}
+	switch value.Type() {
+	case pdata.AttributeValueTypeInt:
+		return float64(value.IntVal()), true
+	case pdata.AttributeValueTypeDouble:
+		return value.DoubleVal(), true
+	case pdata.AttributeValueTypeString:
+		return parseValue(value.StringVal())
+	default:
+		return 0, false
+	}
+}
+
+func parseValue(value string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// lookupAttribute returns the value of the log record attribute,
+// or the resource attribute if the record doesn't have it
+func lookupAttribute(resourceAttributes pdata.AttributeMap, recordAttributes pdata.AttributeMap, key string) (pdata.AttributeValue, bool) {
+	if value, ok := recordAttributes.Get(key); ok {
+		return value, true
+	}
+	return resourceAttributes.Get(key)
+}
+
+// sendMetrics sends the current values of the metrics to the exporters
+func (ltmp *logsToMetricsProcessor) sendMetrics(ctx context.Context) {
+	md := ltmp.buildMetrics()
+	if md.MetricCount() == 0 {
+		return
+	}
+
+	for i, exporter := range ltmp.exporters {
+		data := md
+		if i < len(ltmp.exporters)-1 {
+			data = md.Clone()
+		}
+		if err := exporter.ConsumeMetrics(ctx, data); err != nil {
+			ltmp.logger.Error("Failed to send the metrics derived from logs", zap.Error(err))
+		}
+	}
+}
+
+// buildMetrics converts the metrics to the cumulative OTLP metrics
+func (ltmp *logsToMetricsProcessor) buildMetrics() pdata.Metrics {
+	ltmp.lock.Lock()
+	defer ltmp.lock.Unlock()
+
+	md := pdata.NewMetrics()
+	ilm := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(typeStr)
+	now := pdata.NewTimestampFromTime(ltmp.now())
+
+	for _, m := range ltmp.metrics {
+		if len(m.series) == 0 {
+			continue
+		}
+
+		dest := ilm.Metrics().AppendEmpty()
+		dest.SetName(m.name)
+		dest.SetDescription(m.description)
+		dest.SetUnit(m.unit)
+
+		keys := make([]string, 0, len(m.series))
+		for key := range m.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		switch m.metricType {
+		case MetricTypeCount, MetricTypeSum:
+			dest.SetDataType(pdata.MetricDataTypeSum)
+			sum := dest.Sum()
+			sum.SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+			sum.SetIsMonotonic(m.metricType == MetricTypeCount)
+			for _, key := range keys {
+				s := m.series[key]
+				dp := sum.DataPoints().AppendEmpty()
+				dp.SetStartTimestamp(ltmp.startTime)
+				dp.SetTimestamp(now)
+				insertLabels(dp.Attributes(), s.labels)
+				if m.metricType == MetricTypeCount {
+					dp.SetIntVal(int64(s.count))
+				} else {
+					dp.SetDoubleVal(s.sum)
+				}
+			}
+		case MetricTypeHistogram:
+			dest.SetDataType(pdata.MetricDataTypeHistogram)
+			histogram := dest.Histogram()
+			histogram.SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+			for _, key := range keys {
+				s := m.series[key]
+				dp := histogram.DataPoints().AppendEmpty()
+				dp.SetStartTimestamp(ltmp.startTime)
+				dp.SetTimestamp(now)
+				insertLabels(dp.Attributes(), s.labels)
+				dp.SetCount(s.count)
+				dp.SetSum(s.sum)
+				dp.SetBucketCounts(append([]uint64(nil), s.bucketCounts...))
+				dp.SetExplicitBounds(append([]float64(nil), m.buckets...))
+			}
+		}
+	}
+	return md
+}
+
+func insertLabels(attrs pdata.AttributeMap, labels map[string]string) {
+	for key, value := range labels {
+		attrs.InsertString(key, value)
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstometricsprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.uber.org/zap"
+)
+
+var (
+	startTime = time.Date(2022, time.March, 1, 10, 0, 0, 0, time.UTC)
+	flushTime = startTime.Add(time.Minute)
+)
+
+// sinkExporter is the metrics exporter keeping the metrics in memory
+type sinkExporter struct {
+	component.Component
+	*consumertest.MetricsSink
+}
+
+type exportersHost struct {
+	component.Host
+	exporters map[config.DataType]map[config.ComponentID]component.Exporter
+}
+
+func (h exportersHost) GetExporters() map[config.DataType]map[config.ComponentID]component.Exporter {
+	return h.exporters
+}
+
+func newTestProcessor(t *testing.T, dropMatchedLogs bool, metrics ...MetricConfig) *logsToMetricsProcessor {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Exporters = []string{"sumologic"}
+	cfg.DropMatchedLogs = dropMatchedLogs
+	cfg.Metrics = metrics
+	require.NoError(t, cfg.Validate())
+
+	ltmp, err := newLogsToMetricsProcessor(cfg, zap.NewNop())
+	require.NoError(t, err)
+	ltmp.now = func() time.Time { return flushTime }
+	ltmp.startTime = pdata.NewTimestampFromTime(startTime)
+	return ltmp
+}
+
+// newLogs creates the logs with a single resource of the namespace and the record per body,
+// the attributes of the records are set by the set function
+func newLogs(namespace string, set func(i int, attrs pdata.AttributeMap), bodies ...string) pdata.Logs {
+	logs := pdata.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString("k8s.namespace.name", namespace)
+	records := rl.InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+	for i, body := range bodies {
+		lr := records.AppendEmpty()
+		lr.Body().SetStringVal(body)
+		if set != nil {
+			set(i, lr.Attributes())
+		}
+	}
+	return logs
+}
+
+// getMetric returns the derived metric with the name
+func getMetric(t *testing.T, md pdata.Metrics, name string) pdata.Metric {
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		if metrics.At(i).Name() == name {
+			return metrics.At(i)
+		}
+	}
+	require.Failf(t, "metric not found", "metric %s not found", name)
+	return pdata.Metric{}
+}
+
+func TestCountMetric(t *testing.T) {
+	ltmp := newTestProcessor(t, false, MetricConfig{
+		Name:      "requests",
+		Type:      MetricTypeCount,
+		BodyRegex: "GET|POST",
+		Attributes: []AttributeConfig{
+			{Key: "k8s.namespace.name", Regex: "^prod-"},
+		},
+		Labels: []string{"k8s.namespace.name", "status"},
+	})
+
+	for _, logs := range []pdata.Logs{
+		newLogs("prod-a", func(i int, attrs pdata.AttributeMap) {
+			attrs.InsertString("status", []string{"200", "500", "200", "200"}[i])
+		}, "GET /", "POST /", "DELETE /", "GET /"),
+		newLogs("prod-b", nil, "GET /"),
+		newLogs("dev", nil, "GET /"),
+	} {
+		_, err := ltmp.ProcessLogs(context.Background(), logs)
+		require.NoError(t, err)
+	}
+
+	md := ltmp.buildMetrics()
+	require.Equal(t, 1, md.MetricCount())
+	metric := getMetric(t, md, "requests")
+	require.Equal(t, pdata.MetricDataTypeSum, metric.DataType())
+	assert.True(t, metric.Sum().IsMonotonic())
+	assert.Equal(t, pdata.MetricAggregationTemporalityCumulative, metric.Sum().AggregationTemporality())
+
+	dps := metric.Sum().DataPoints()
+	require.Equal(t, 3, dps.Len())
+	expected := []struct {
+		labels map[string]pdata.AttributeValue
+		count  int64
+	}{
+		{
+			labels: map[string]pdata.AttributeValue{
+				"k8s.namespace.name": pdata.NewAttributeValueString("prod-a"),
+				"status":             pdata.NewAttributeValueString("200"),
+			},
+			count: 2,
+		},
+		{
+			labels: map[string]pdata.AttributeValue{
+				"k8s.namespace.name": pdata.NewAttributeValueString("prod-a"),
+				"status":             pdata.NewAttributeValueString("500"),
+			},
+			count: 1,
+		},
+		{
+			labels: map[string]pdata.AttributeValue{
+				"k8s.namespace.name": pdata.NewAttributeValueString("prod-b"),
+			},
+			count: 1,
+		},
+	}
+	for i, e := range expected {
+		dp := dps.At(i)
+		assert.Equal(t, pdata.NewAttributeMapFromMap(e.labels).Sort(), dp.Attributes().Sort())
+		assert.Equal(t, e.count, dp.IntVal())
+		assert.Equal(t, pdata.NewTimestampFromTime(startTime), dp.StartTimestamp())
+		assert.Equal(t, pdata.NewTimestampFromTime(flushTime), dp.Timestamp())
+	}
+}
+
+func TestSumMetric(t *testing.T) {
+	ltmp := newTestProcessor(t, false, MetricConfig{
+		Name:           "bytes",
+		Type:           MetricTypeSum,
+		ValueAttribute: "bytes",
+	})
+
+	_, err := ltmp.ProcessLogs(context.Background(), newLogs("prod", func(i int, attrs pdata.AttributeMap) {
+		switch i {
+		case 0:
+			attrs.InsertInt("bytes", 100)
+		case 1:
+			attrs.InsertDouble("bytes", 0.5)
+		case 2:
+			attrs.InsertString("bytes", " 20 ")
+		case 3:
+			attrs.InsertString("bytes", "unknown")
+		}
+	}, "first", "second", "third", "fourth", "fifth"))
+	require.NoError(t, err)
+
+	metric := getMetric(t, ltmp.buildMetrics(), "bytes")
+	require.Equal(t, pdata.MetricDataTypeSum, metric.DataType())
+	assert.False(t, metric.Sum().IsMonotonic())
+	require.Equal(t, 1, metric.Sum().DataPoints().Len())
+	assert.Equal(t, 120.5, metric.Sum().DataPoints().At(0).DoubleVal())
+}
+
+func TestHistogramMetric(t *testing.T) {
+	ltmp := newTestProcessor(t, false, MetricConfig{
+		Name:       "duration",
+		Type:       MetricTypeHistogram,
+		ValueRegex: `request_time=(\d+(\.\d+)?)`,
+		Buckets:    []float64{10, 100},
+	})
+
+	_, err := ltmp.ProcessLogs(context.Background(), newLogs("prod", nil,
+		"GET / request_time=5",
+		"GET / request_time=10",
+		"GET / request_time=99.5",
+		"GET / request_time=1000",
+		"GET / without duration",
+	))
+	require.NoError(t, err)
+
+	metric := getMetric(t, ltmp.buildMetrics(), "duration")
+	require.Equal(t, pdata.MetricDataTypeHistogram, metric.DataType())
+	require.Equal(t, 1, metric.Histogram().DataPoints().Len())
+	dp := metric.Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(4), dp.Count())
+	assert.Equal(t, 1114.5, dp.Sum())
+	assert.Equal(t, []float64{10, 100}, dp.ExplicitBounds())
+	assert.Equal(t, []uint64{2, 1, 1}, dp.BucketCounts())
+}
+
+func TestHistogramDefaultBuckets(t *testing.T) {
+	ltmp := newTestProcessor(t, false, MetricConfig{
+		Name:           "duration",
+		Type:           MetricTypeHistogram,
+		ValueAttribute: "duration",
+	})
+
+	_, err := ltmp.ProcessLogs(context.Background(), newLogs("prod", func(i int, attrs pdata.AttributeMap) {
+		attrs.InsertInt("duration", 3)
+	}, "first"))
+	require.NoError(t, err)
+
+	dp := getMetric(t, ltmp.buildMetrics(), "duration").Histogram().DataPoints().At(0)
+	assert.Equal(t, defaultBuckets, dp.ExplicitBounds())
+	assert.Equal(t, uint64(1), dp.BucketCounts()[1])
+}
+
+func TestDropMatchedLogs(t *testing.T) {
+	ltmp := newTestProcessor(t, true,
+		MetricConfig{Name: "requests", Type: MetricTypeCount, BodyRegex: "GET"},
+		MetricConfig{Name: "errors", Type: MetricTypeCount, BodyRegex: "ERROR"},
+	)
+
+	logs, err := ltmp.ProcessLogs(context.Background(), newLogs("prod", nil, "GET /", "ERROR", "INFO"))
+	require.NoError(t, err)
+	require.Equal(t, 1, logs.LogRecordCount())
+	assert.Equal(t, "INFO", logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).LogRecords().At(0).Body().StringVal())
+
+	_, err = ltmp.ProcessLogs(context.Background(), newLogs("prod", nil, "GET /", "ERROR"))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+
+	assert.Equal(t, 2, ltmp.buildMetrics().MetricCount())
+}
+
+func TestStartSendsMetricsToExporters(t *testing.T) {
+	ltmp := newTestProcessor(t, false, MetricConfig{Name: "requests", Type: MetricTypeCount})
+	sink := new(consumertest.MetricsSink)
+	host := exportersHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.MetricsDataType: {
+				config.NewComponentID("sumologic"): sinkExporter{MetricsSink: sink},
+			},
+		},
+	}
+
+	require.NoError(t, ltmp.Start(context.Background(), host))
+	_, err := ltmp.ProcessLogs(context.Background(), newLogs("prod", nil, "first", "second"))
+	require.NoError(t, err)
+	require.NoError(t, ltmp.Shutdown(context.Background()))
+
+	require.Len(t, sink.AllMetrics(), 1)
+	metric := getMetric(t, sink.AllMetrics()[0], "requests")
+	assert.Equal(t, int64(2), metric.Sum().DataPoints().At(0).IntVal())
+}
+
+func TestStartMissingExporter(t *testing.T) {
+	ltmp := newTestProcessor(t, false, MetricConfig{Name: "requests", Type: MetricTypeCount})
+
+	host := exportersHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.LogsDataType: {
+				config.NewComponentID("sumologic"): sinkExporter{MetricsSink: new(consumertest.MetricsSink)},
+			},
+		},
+	}
+	assert.EqualError(t, ltmp.Start(context.Background(), host), "metrics exporter sumologic not found")
+}
@@ -1,6 +1,7 @@
 package metricfrequencyprocessor
 
 import (
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/config"
@@ -12,6 +13,7 @@ type Config struct {
 
 	sieveConfig `mapstructure:",squash"`
 	cacheConfig `mapstructure:",squash"`
+	haConfig    `mapstructure:",squash"`
 }
 
 type sieveConfig struct {
@@ -20,6 +22,11 @@ type sieveConfig struct {
 	// which do not have data points in processor's cache older than MinPointAccumulationTime.
 	MinPointAccumulationTime time.Duration `mapstructure:"min_point_accumulation_time"`
 
+	// MinPointsForClassification defines how many data points of a metric have to be cached
+	// before it's classified. Data points of metrics with fewer cached data points are not sifted,
+	// so new metrics, e.g. after a deploy, are not classified as constant based on their first points.
+	MinPointsForClassification int `mapstructure:"min_points_for_classification"`
+
 	// ConstantMetricsReportFrequency defines minimum time between reports of a constant metric.
 	ConstantMetricsReportFrequency time.Duration `mapstructure:"constant_metrics_report_frequency"`
 
@@ -33,6 +40,11 @@ type sieveConfig struct {
 	// I.e. value v such that v > Iqr * IqrAnomalyCoef is considered an anomaly.
 	IqrAnomalyCoef float64 `mapstructure:"iqr_anomaly_coefficient"`
 
+	// WindowTimeSource defines the time the data points are windowed and reported by,
+	// either their timestamps (data_point) or the time they are received at (received).
+	// The latter is useful when backfilled or out-of-order data points are received.
+	WindowTimeSource string `mapstructure:"window_time_source"`
+
 	// VariationIqrThresholdCoef variation to iqr quotient above which a metric is no longer considered low info.
 	// Variation means sum of absolute values of differences between consecutive data points.
 	// I.e. if current variation v of a metric satisfies v / Iqr > VariationIqrThresholdCoef
@@ -40,6 +52,13 @@ type sieveConfig struct {
 	VariationIqrThresholdCoef float64 `mapstructure:"variation_iqr_threshold_coefficient"`
 }
 
+const (
+	// windowTimeSourceDataPoint windows the data points by their timestamps
+	windowTimeSourceDataPoint = "data_point"
+	// windowTimeSourceReceived windows the data points by the time they are received at
+	windowTimeSourceReceived = "received"
+)
+
 type cacheConfig struct {
 	// DataPointExpirationTime defines how long a data point should be used for determining metric's category.
 	DataPointExpirationTime time.Duration `mapstructure:"data_point_expiration_time"`
@@ -49,4 +68,49 @@ type cacheConfig struct {
 
 	// MetricCacheCleanupInterval defines how often no longer seen metrics are removed from memory.
 	MetricCacheCleanupInterval time.Duration `mapstructure:"metric_cache_cleanup_interval"`
+
+	// CacheShards defines the number of shards the cached metrics are distributed across by the hash of their names.
+	// Each shard has its own lock, so more shards allow more data points to be registered concurrently.
+	CacheShards int `mapstructure:"cache_shards"`
+}
+
+type haConfig struct {
+	// HAOwnerAttribute defines the resource attribute holding ID of the collector which currently owns
+	// (is active for) the metrics, e.g. set by an election mechanism for HA collector pairs.
+	// When empty, HA coordination is disabled and all metrics are sifted.
+	HAOwnerAttribute string `mapstructure:"ha_owner_attribute"`
+
+	// HACollectorID identifies this collector. Metrics owned by a different collector are not sifted,
+	// but passed through with HAStandbyAttribute set.
+	HACollectorID string `mapstructure:"ha_collector_id"`
+
+	// HAStandbyAttribute defines the resource attribute set to true on metrics passed through by a standby collector.
+	HAStandbyAttribute string `mapstructure:"ha_standby_attribute"`
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.MinPointsForClassification < 0 {
+		return fmt.Errorf("min_points_for_classification cannot be negative: %d", cfg.MinPointsForClassification)
+	}
+
+	switch cfg.WindowTimeSource {
+	case windowTimeSourceDataPoint, windowTimeSourceReceived:
+	default:
+		return fmt.Errorf("unexpected window_time_source: %q", cfg.WindowTimeSource)
+	}
+
+	if cfg.CacheShards < 1 {
+		return fmt.Errorf("cache_shards has to be positive: %d", cfg.CacheShards)
+	}
+
+	if cfg.HAOwnerAttribute != "" {
+		if cfg.HACollectorID == "" {
+			return fmt.Errorf("ha_collector_id has to be set when ha_owner_attribute is set")
+		}
+		if cfg.HAStandbyAttribute == "" {
+			return fmt.Errorf("ha_standby_attribute cannot be empty when ha_owner_attribute is set")
+		}
+	}
+
+	return nil
 }
@@ -26,3 +26,50 @@ func TestLoadConfig(t *testing.T) {
 
 	assert.Equal(t, cfg.Processors[id], createDefaultConfig())
 }
+
+func TestLoadConfigHA(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[factory.Type()] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config_ha.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	expected := createDefaultConfig().(*Config)
+	expected.HAOwnerAttribute = "collector.owner"
+	expected.HACollectorID = "collector-a"
+
+	assert.Equal(t, expected, cfg.Processors[config.NewComponentID("metric_frequency")])
+}
+
+func TestValidateConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.HAOwnerAttribute = "collector.owner"
+	assert.EqualError(t, cfg.Validate(), "ha_collector_id has to be set when ha_owner_attribute is set")
+
+	cfg.HACollectorID = "collector-a"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.HAStandbyAttribute = ""
+	assert.EqualError(t, cfg.Validate(), "ha_standby_attribute cannot be empty when ha_owner_attribute is set")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.CacheShards = 0
+	assert.EqualError(t, cfg.Validate(), "cache_shards has to be positive: 0")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.MinPointsForClassification = -1
+	assert.EqualError(t, cfg.Validate(), "min_points_for_classification cannot be negative: -1")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.WindowTimeSource = windowTimeSourceReceived
+	assert.NoError(t, cfg.Validate())
+
+	cfg.WindowTimeSource = "ingest"
+	assert.EqualError(t, cfg.Validate(), `unexpected window_time_source: "ingest"`)
+}
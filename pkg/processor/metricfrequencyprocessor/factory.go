@@ -19,9 +19,12 @@ const (
 	defaultMaxReportFrequency             = 30 * time.Second
 	defaultIqrAnomalyCoef                 = 1.5
 	defaultVariationIqrThresholdCoef      = 4.0
+	defaultWindowTimeSource               = windowTimeSourceDataPoint
 	defaultDataPointExpirationTime        = 1 * time.Hour
 	defaultDataPointCacheCleanupInterval  = 10 * time.Minute
 	defaultMetricCacheCleanupInterval     = 3 * time.Hour
+	defaultCacheShards                    = 64
+	defaultHAStandbyAttribute             = "metric_frequency.standby"
 )
 
 func NewFactory() component.ProcessorFactory {
@@ -45,11 +48,16 @@ func createDefaultConfig() config.Processor {
 			MaxReportFrequency:             defaultMaxReportFrequency,
 			IqrAnomalyCoef:                 defaultIqrAnomalyCoef,
 			VariationIqrThresholdCoef:      defaultVariationIqrThresholdCoef,
+			WindowTimeSource:               defaultWindowTimeSource,
 		},
 		cacheConfig{
 			DataPointExpirationTime:       defaultDataPointExpirationTime,
 			DataPointCacheCleanupInterval: defaultDataPointCacheCleanupInterval,
 			MetricCacheCleanupInterval:    defaultMetricCacheCleanupInterval,
+			CacheShards:                   defaultCacheShards,
+		},
+		haConfig{
+			HAStandbyAttribute: defaultHAStandbyAttribute,
 		},
 	}
 }
@@ -62,6 +70,7 @@ func createMetricsProcessor(
 ) (component.MetricsProcessor, error) {
 	var internalProcessor = &metricsfrequencyprocessor{
 		sieve: newMetricSieve(cfg.(*Config)),
+		ha:    cfg.(*Config).haConfig,
 	}
 	return processorhelper.NewMetricsProcessor(cfg, nextConsumer, internalProcessor.ProcessMetrics)
 }
@@ -2,6 +2,8 @@ package metricfrequencyprocessor
 
 import (
 	"fmt"
+	"hash/fnv"
+	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
@@ -14,17 +16,38 @@ type DataPoint struct {
 }
 
 // metricCache caches data points into two level mapping structure.
-// To easily list all data points of a given metric it keeps a separate cache for each incoming metric.
+// To easily list all data points of a given series it keeps a separate cache for each incoming series,
+// identified by its key (the metric name with the sorted attributes, see seriesKey).
+// The series are distributed across shards by the hash of their keys, each shard with its own lock,
+// so that the data points of different series, including the ones of the same metric,
+// can be registered concurrently.
 type metricCache struct {
 	config cacheConfig
 
+	shards []*metricCacheShard
+}
+
+type metricCacheShard struct {
+	lock           sync.RWMutex
 	internalCaches map[string]*cache.Cache
+	lastReported   map[string]pdata.Timestamp
 }
 
 func newMetricCache(config cacheConfig) *metricCache {
+	shardCount := config.CacheShards
+	if shardCount < 1 {
+		shardCount = 1
+	}
+
 	c := &metricCache{
-		config:         config,
-		internalCaches: make(map[string]*cache.Cache),
+		config: config,
+		shards: make([]*metricCacheShard, shardCount),
+	}
+	for i := range c.shards {
+		c.shards[i] = &metricCacheShard{
+			internalCaches: make(map[string]*cache.Cache),
+			lastReported:   make(map[string]pdata.Timestamp),
+		}
 	}
 
 	go func(c *metricCache) {
@@ -38,23 +61,51 @@ func newMetricCache(config cacheConfig) *metricCache {
 	return c
 }
 
-func (mc *metricCache) Register(name string, dataPoint pdata.NumberDataPoint) {
+// shard returns the shard of the series, by the hash of the whole series key,
+// so the series of a metric with many attribute sets are spread across the shards
+func (mc *metricCache) shard(series string) *metricCacheShard {
+	h := fnv.New32a()
+	// hash.Hash never returns an error on write
+	_, _ = h.Write([]byte(series))
+	return mc.shards[h.Sum32()%uint32(len(mc.shards))]
+}
+
+func (mc *metricCache) Register(series string, dataPoint pdata.NumberDataPoint) {
+	mc.RegisterAt(series, dataPoint.Timestamp(), getVal(dataPoint))
+}
+
+// RegisterAt caches the value of the series at the given time, which doesn't have to be
+// the timestamp of the data point (e.g. when the data points are windowed by the time they are received at).
+func (mc *metricCache) RegisterAt(series string, timestamp pdata.Timestamp, val float64) {
+	shard := mc.shard(series)
+
+	shard.lock.RLock()
+	internalCache, exists := shard.internalCaches[series]
+	shard.lock.RUnlock()
 
-	internalCache, exists := mc.internalCaches[name]
 	if !exists {
-		newCache := mc.newCache()
-		mc.internalCaches[name] = newCache
-		internalCache = newCache
+		shard.lock.Lock()
+		internalCache, exists = shard.internalCaches[series]
+		if !exists {
+			internalCache = mc.newCache()
+			shard.internalCaches[series] = internalCache
+		}
+		shard.lock.Unlock()
 	}
 
-	key := dataPoint.Timestamp().String()
-	value := &DataPoint{Timestamp: dataPoint.Timestamp(), Value: getVal(dataPoint)}
+	key := timestamp.String()
+	value := &DataPoint{Timestamp: timestamp, Value: val}
 	internalCache.Set(key, value, cache.DefaultExpiration)
 }
 
-func (mc *metricCache) List(metricName string) map[pdata.Timestamp]float64 {
+func (mc *metricCache) List(series string) map[pdata.Timestamp]float64 {
 	out := make(map[pdata.Timestamp]float64)
-	internalCache, found := mc.internalCaches[metricName]
+
+	shard := mc.shard(series)
+	shard.lock.RLock()
+	internalCache, found := shard.internalCaches[series]
+	shard.lock.RUnlock()
+
 	if found {
 		for _, item := range internalCache.Items() {
 			dataPoint, ok := item.Object.(*DataPoint)
@@ -68,11 +119,45 @@ func (mc *metricCache) List(metricName string) map[pdata.Timestamp]float64 {
 	return out
 }
 
+// LastReported returns the timestamp of the last reported data point of the series.
+func (mc *metricCache) LastReported(series string) (pdata.Timestamp, bool) {
+	shard := mc.shard(series)
+	shard.lock.RLock()
+	defer shard.lock.RUnlock()
+
+	timestamp, exists := shard.lastReported[series]
+	return timestamp, exists
+}
+
+// SetLastReported sets the timestamp of the last reported data point of the series.
+func (mc *metricCache) SetLastReported(series string, timestamp pdata.Timestamp) {
+	shard := mc.shard(series)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	shard.lastReported[series] = timestamp
+}
+
+// Delete removes the cached data points and the last reported timestamp of the series,
+// e.g. when it's marked as stale and is not expected to receive new data points.
+func (mc *metricCache) Delete(series string) {
+	shard := mc.shard(series)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	delete(shard.internalCaches, series)
+	delete(shard.lastReported, series)
+}
+
 func (mc *metricCache) Cleanup() {
-	for key, internalCache := range mc.internalCaches {
-		if internalCache.ItemCount() == 0 {
-			delete(mc.internalCaches, key)
+	for _, shard := range mc.shards {
+		shard.lock.Lock()
+		for key, internalCache := range shard.internalCaches {
+			if internalCache.ItemCount() == 0 {
+				delete(shard.internalCaches, key)
+			}
 		}
+		shard.lock.Unlock()
 	}
 }
 
@@ -1,6 +1,9 @@
 package metricfrequencyprocessor
 
 import (
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -47,6 +50,105 @@ func TestTwoRegistersOnTwoMetrics(t *testing.T) {
 	assert.Equal(t, map[pdata.Timestamp]float64{timestamp2: 1.0}, result2)
 }
 
+func TestLastReported(t *testing.T) {
+	cache := newCache()
+	_, exists := cache.LastReported("a")
+	assert.False(t, exists)
+
+	cache.SetLastReported("a", timestamp1)
+	cache.SetLastReported("b", timestamp2)
+
+	lastReported, exists := cache.LastReported("a")
+	assert.True(t, exists)
+	assert.Equal(t, timestamp1, lastReported)
+}
+
+func TestDelete(t *testing.T) {
+	cache := newCache()
+	cache.Register("a", newDataPoint(timestamp1, 0.0))
+	cache.Register("b", newDataPoint(timestamp2, 1.0))
+	cache.SetLastReported("a", timestamp1)
+
+	cache.Delete("a")
+
+	assert.Equal(t, emptyResult, cache.List("a"))
+	_, exists := cache.LastReported("a")
+	assert.False(t, exists)
+	assert.Equal(t, map[pdata.Timestamp]float64{timestamp2: 1.0}, cache.List("b"))
+}
+
+func TestConcurrentRegisters(t *testing.T) {
+	cache := newCache()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				name := fmt.Sprintf("metric-%d", j)
+				cache.Register(name, newDataPoint(pdata.NewTimestampFromTime(time.Unix(int64(i), 0)), float64(i)))
+				cache.List(name)
+			}
+			cache.Cleanup()
+		}(i)
+	}
+	wg.Wait()
+
+	for j := 0; j < 100; j++ {
+		assert.Len(t, cache.List(fmt.Sprintf("metric-%d", j)), 8)
+	}
+}
+
+func TestSeriesOfMetricSpreadAcrossShards(t *testing.T) {
+	cache := newCache()
+
+	shards := map[*metricCacheShard]struct{}{}
+	for i := 0; i < 100; i++ {
+		attributes := pdata.NewAttributeMap()
+		attributes.InsertString("pod", fmt.Sprintf("pod-%d", i))
+		series := seriesKey("a", attributes)
+
+		cache.Register(series, newDataPoint(timestamp1, float64(i)))
+		assert.Equal(t, map[pdata.Timestamp]float64{timestamp1: float64(i)}, cache.List(series))
+		shards[cache.shard(series)] = struct{}{}
+	}
+
+	assert.Greater(t, len(shards), 1, "series of the same metric should be spread across shards")
+}
+
+// BenchmarkMetricCacheRegister registers the data points of many series concurrently,
+// run it with e.g. -cpu 1,2,4,8 to compare the scaling of a single shard with the default sharding.
+func BenchmarkMetricCacheRegister(b *testing.B) {
+	const seriesCount = 100_000
+	names := make([]string, seriesCount)
+	for i := range names {
+		names[i] = fmt.Sprintf("metric-%d", i)
+	}
+	dataPoint := newDataPoint(timestamp1, 1.0)
+
+	for _, shards := range []int{1, defaultCacheShards} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			config := createDefaultConfig().(*Config).cacheConfig
+			config.CacheShards = shards
+			cache := newMetricCache(config)
+
+			var goroutines int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				// every goroutine starts with different series
+				i := int(atomic.AddInt64(&goroutines, 1)) * 1000
+				for pb.Next() {
+					name := names[i%seriesCount]
+					cache.Register(name, dataPoint)
+					cache.List(name)
+					i += 7919
+				}
+			})
+		})
+	}
+}
+
 var emptyResult = make(map[pdata.Timestamp]float64)
 var timestamp1 = pdata.NewTimestampFromTime(time.Unix(0, 0))
 var timestamp2 = pdata.NewTimestampFromTime(time.Unix(1, 0))
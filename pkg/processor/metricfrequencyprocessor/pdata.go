@@ -3,6 +3,7 @@ package metricfrequencyprocessor
 import (
 	"math"
 	"sort"
+	"strings"
 
 	"go.opentelemetry.io/collector/model/pdata"
 )
@@ -35,3 +36,33 @@ func getVal(point pdata.NumberDataPoint) float64 {
 		return math.NaN()
 	}
 }
+
+// seriesKey returns the key identifying the series of the data point by the metric name and the attributes
+func seriesKey(name string, attributes pdata.AttributeMap) string {
+	if attributes.Len() == 0 {
+		return name
+	}
+
+	keys := make([]string, 0, attributes.Len())
+	attributes.Range(func(k string, _ pdata.AttributeValue) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	sb.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		v, _ := attributes.Get(k)
+		sb.WriteString(k)
+		sb.WriteString(`="`)
+		sb.WriteString(v.AsString())
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
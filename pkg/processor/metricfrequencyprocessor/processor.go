@@ -9,6 +9,7 @@ import (
 
 type metricsfrequencyprocessor struct {
 	sieve metricSieve
+	ha    haConfig
 }
 
 var _ processorhelper.ProcessMetricsFunc = (*metricsfrequencyprocessor)(nil).ProcessMetrics
@@ -18,6 +19,11 @@ func (mfp *metricsfrequencyprocessor) ProcessMetrics(_ context.Context, md pdata
 	rms := md.ResourceMetrics()
 	for i := 0; i < rms.Len(); i++ {
 		rm := rms.At(i)
+		if mfp.isStandby(rm) {
+			mfp.passThrough(rm)
+			continue
+		}
+
 		ilms := rm.InstrumentationLibraryMetrics()
 		for j := 0; j < ilms.Len(); j++ {
 			ilm := ilms.At(j)
@@ -31,6 +37,38 @@ func (mfp *metricsfrequencyprocessor) ProcessMetrics(_ context.Context, md pdata
 	return md, nil
 }
 
+// isStandby returns true if the resource metrics are owned by a different collector of the HA pair.
+// Metrics without the owner attribute, or with the owner attribute which is not a string, are always sifted.
+func (mfp *metricsfrequencyprocessor) isStandby(rm pdata.ResourceMetrics) bool {
+	if mfp.ha.HAOwnerAttribute == "" {
+		return false
+	}
+
+	owner, ok := rm.Resource().Attributes().Get(mfp.ha.HAOwnerAttribute)
+	if !ok || owner.Type() != pdata.AttributeValueTypeString {
+		return false
+	}
+
+	return owner.StringVal() != mfp.ha.HACollectorID
+}
+
+// passThrough tags the resource metrics as passed through by a standby collector without sifting them.
+// The sieve is still fed with a copy of each metric, so that its cache is warm and the sifting
+// continues without gaps after a failover.
+func (mfp *metricsfrequencyprocessor) passThrough(rm pdata.ResourceMetrics) {
+	ilms := rm.InstrumentationLibraryMetrics()
+	for j := 0; j < ilms.Len(); j++ {
+		metrics := ilms.At(j).Metrics()
+		for k := 0; k < metrics.Len(); k++ {
+			metric := pdata.NewMetric()
+			metrics.At(k).CopyTo(metric)
+			mfp.sieve.Sift(metric)
+		}
+	}
+
+	rm.Resource().Attributes().UpsertBool(mfp.ha.HAStandbyAttribute, true)
+}
+
 func metricSliceEmpty(metrics pdata.InstrumentationLibraryMetrics) bool {
 	return metrics.Metrics().Len() == 0
 }
@@ -133,6 +133,83 @@ func TestSelectionWithTwoLibraries(t *testing.T) {
 	assert.Equal(t, "m2", result.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).Name())
 }
 
+func TestHAStandbyPassesThrough(t *testing.T) {
+	sieve := &countingSiftAllSieve{}
+	processor := &metricsfrequencyprocessor{
+		sieve: sieve,
+		ha: haConfig{
+			HAOwnerAttribute:   "collector.owner",
+			HACollectorID:      "collector-a",
+			HAStandbyAttribute: defaultHAStandbyAttribute,
+		},
+	}
+
+	resourceMetrics := map[string][]string{
+		"lib-1": {"m1", "m2"},
+	}
+
+	input := createMetrics(resourceMetrics, resourceMetrics, resourceMetrics)
+	input.ResourceMetrics().At(0).Resource().Attributes().InsertString("collector.owner", "collector-a")
+	input.ResourceMetrics().At(1).Resource().Attributes().InsertString("collector.owner", "collector-b")
+
+	result, err := processor.ProcessMetrics(context.Background(), input)
+
+	require.NoError(t, err)
+	// Metrics owned by this collector and metrics without owner are sifted,
+	// metrics owned by the other collector are passed through
+	require.Equal(t, 1, result.ResourceMetrics().Len())
+	rm := result.ResourceMetrics().At(0)
+	owner, ok := rm.Resource().Attributes().Get("collector.owner")
+	require.True(t, ok)
+	assert.Equal(t, "collector-b", owner.StringVal())
+	standby, ok := rm.Resource().Attributes().Get(defaultHAStandbyAttribute)
+	require.True(t, ok)
+	assert.True(t, standby.BoolVal())
+	assert.Equal(t, 2, rm.InstrumentationLibraryMetrics().At(0).Metrics().Len())
+
+	// The sieve is fed with all metrics, including passed through ones
+	assert.Equal(t, 6, sieve.count)
+}
+
+func TestHANonStringOwnerSifted(t *testing.T) {
+	processor := &metricsfrequencyprocessor{
+		sieve: &siftAllSieve{},
+		ha: haConfig{
+			HAOwnerAttribute:   "collector.owner",
+			HACollectorID:      "collector-a",
+			HAStandbyAttribute: defaultHAStandbyAttribute,
+		},
+	}
+
+	resourceMetrics := map[string][]string{
+		"lib-1": {"m1"},
+	}
+
+	input := createMetrics(resourceMetrics)
+	input.ResourceMetrics().At(0).Resource().Attributes().InsertInt("collector.owner", 1)
+
+	result, err := processor.ProcessMetrics(context.Background(), input)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.ResourceMetrics().Len())
+}
+
+func TestHADisabled(t *testing.T) {
+	processor := &metricsfrequencyprocessor{sieve: &siftAllSieve{}}
+
+	resourceMetrics := map[string][]string{
+		"lib-1": {"m1"},
+	}
+
+	input := createMetrics(resourceMetrics)
+	input.ResourceMetrics().At(0).Resource().Attributes().InsertString("collector.owner", "collector-b")
+
+	result, err := processor.ProcessMetrics(context.Background(), input)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.ResourceMetrics().Len())
+}
+
 func createGauge() pdata.Gauge {
 	dpSlice := pdata.NewNumberDataPointSlice()
 	pdata.NewNumberDataPoint().CopyTo(dpSlice.AppendEmpty())
@@ -26,17 +26,18 @@ type metricSieve interface {
 type defaultMetricSieve struct {
 	config sieveConfig
 
-	metricCache  *metricCache
-	lastReported map[string]pdata.Timestamp
+	metricCache *metricCache
+	// now returns the time the data points are received at
+	now func() time.Time
 }
 
 var _ metricSieve = (*defaultMetricSieve)(nil)
 
 func newMetricSieve(config *Config) *defaultMetricSieve {
 	return &defaultMetricSieve{
-		metricCache:  newMetricCache(config.cacheConfig),
-		lastReported: make(map[string]pdata.Timestamp),
-		config:       config.sieveConfig,
+		metricCache: newMetricCache(config.cacheConfig),
+		config:      config.sieveConfig,
+		now:         time.Now,
 	}
 }
 
@@ -59,27 +60,37 @@ func (ms *defaultMetricSieve) siftDropGauge(metric pdata.Metric) bool {
 
 func (ms *defaultMetricSieve) siftDataPoint(name string) func(pdata.NumberDataPoint) bool {
 	return func(dataPoint pdata.NumberDataPoint) bool {
+		series := seriesKey(name, dataPoint.Attributes())
+
+		if dataPoint.Flags().HasFlag(pdata.MetricDataPointFlagNoRecordedValue) {
+			// the series is gone (e.g. its scrape target was removed), so its cache entries are removed
+			// right away instead of waiting for them to expire, and the staleness marker is passed through
+			ms.metricCache.Delete(series)
+			return false
+		}
+
 		if math.IsNaN(getVal(dataPoint)) {
 			return false
 		}
 
-		cachedPoints := ms.metricCache.List(name)
-		ms.metricCache.Register(name, dataPoint)
-		lastReported, exists := ms.lastReported[name]
+		timestamp := ms.windowTimestamp(dataPoint)
+		cachedPoints := ms.metricCache.List(series)
+		ms.metricCache.RegisterAt(series, timestamp, getVal(dataPoint))
+		lastReported, exists := ms.metricCache.LastReported(series)
 		if !exists {
-			ms.lastReported[name] = dataPoint.Timestamp()
+			ms.metricCache.SetLastReported(series, timestamp)
 			return false
 		}
 		earliest := earliestTimestamp(cachedPoints)
-		cachedPoints[dataPoint.Timestamp()] = getVal(dataPoint)
+		cachedPoints[timestamp] = getVal(dataPoint)
 
-		if ms.metricRequiresSamples(dataPoint, earliest) {
-			ms.lastReported[name] = dataPoint.Timestamp()
+		if ms.metricRequiresSamples(timestamp, earliest) || len(cachedPoints) < ms.config.MinPointsForClassification {
+			ms.metricCache.SetLastReported(series, timestamp)
 			return false
 		}
 
-		if pastCategoryFrequency(dataPoint, lastReported, ms.config.ConstantMetricsReportFrequency) {
-			ms.lastReported[name] = dataPoint.Timestamp()
+		if pastCategoryFrequency(timestamp, lastReported, ms.config.ConstantMetricsReportFrequency) {
+			ms.metricCache.SetLastReported(series, timestamp)
 			return false
 		}
 
@@ -87,8 +98,8 @@ func (ms *defaultMetricSieve) siftDataPoint(name string) func(pdata.NumberDataPo
 			return true
 		}
 
-		if pastCategoryFrequency(dataPoint, lastReported, ms.config.LowInfoMetricsReportFrequency) {
-			ms.lastReported[name] = dataPoint.Timestamp()
+		if pastCategoryFrequency(timestamp, lastReported, ms.config.LowInfoMetricsReportFrequency) {
+			ms.metricCache.SetLastReported(series, timestamp)
 			return false
 		}
 
@@ -96,8 +107,8 @@ func (ms *defaultMetricSieve) siftDataPoint(name string) func(pdata.NumberDataPo
 			return true
 		}
 
-		if pastCategoryFrequency(dataPoint, lastReported, ms.config.MaxReportFrequency) {
-			ms.lastReported[name] = dataPoint.Timestamp()
+		if pastCategoryFrequency(timestamp, lastReported, ms.config.MaxReportFrequency) {
+			ms.metricCache.SetLastReported(series, timestamp)
 			return false
 		}
 
@@ -105,12 +116,21 @@ func (ms *defaultMetricSieve) siftDataPoint(name string) func(pdata.NumberDataPo
 	}
 }
 
-func (ms *defaultMetricSieve) metricRequiresSamples(point pdata.NumberDataPoint, earliest pdata.Timestamp) bool {
-	return point.Timestamp().AsTime().Before(earliest.AsTime().Add(ms.config.MinPointAccumulationTime))
+// windowTimestamp returns the time the data point is windowed and reported by,
+// depending on window_time_source.
+func (ms *defaultMetricSieve) windowTimestamp(dataPoint pdata.NumberDataPoint) pdata.Timestamp {
+	if ms.config.WindowTimeSource == windowTimeSourceReceived {
+		return pdata.NewTimestampFromTime(ms.now())
+	}
+	return dataPoint.Timestamp()
+}
+
+func (ms *defaultMetricSieve) metricRequiresSamples(timestamp pdata.Timestamp, earliest pdata.Timestamp) bool {
+	return timestamp.AsTime().Before(earliest.AsTime().Add(ms.config.MinPointAccumulationTime))
 }
 
-func pastCategoryFrequency(point pdata.NumberDataPoint, lastReport pdata.Timestamp, categoryFrequency time.Duration) bool {
-	return point.Timestamp().AsTime().Add(safetyInterval).After(lastReport.AsTime().Add(categoryFrequency))
+func pastCategoryFrequency(timestamp pdata.Timestamp, lastReport pdata.Timestamp, categoryFrequency time.Duration) bool {
+	return timestamp.AsTime().Add(safetyInterval).After(lastReport.AsTime().Add(categoryFrequency))
 }
 
 func isConstant(point pdata.NumberDataPoint, points map[pdata.Timestamp]float64) bool {
@@ -23,3 +23,12 @@ type singleMetricSieve struct {
 func (s *singleMetricSieve) Sift(metric pdata.Metric) bool {
 	return metric.Name() == s.name
 }
+
+type countingSiftAllSieve struct {
+	count int
+}
+
+func (s *countingSiftAllSieve) Sift(metric pdata.Metric) bool {
+	s.count++
+	return true
+}
@@ -21,6 +21,125 @@ func TestAccumulate(t *testing.T) {
 	assert.False(t, result)
 }
 
+func TestMinPointsForClassification(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MinPointAccumulationTime = 0
+	cfg.MinPointsForClassification = 3
+	sieve := newMetricSieve(cfg)
+	var timestamp = time.Unix(0, 0)
+	setupHistory(sieve, map[time.Time]float64{timestamp: 0.0})
+
+	// metric is not filtered, because there are only 2 data points
+	result := sieve.Sift(dataPointsToMetric(map[time.Time]float64{
+		timestamp.Add(1 * time.Second): 0.0,
+	}))
+	assert.False(t, result)
+
+	// metric is filtered as constant once there are 3 data points
+	result = sieve.Sift(dataPointsToMetric(map[time.Time]float64{
+		timestamp.Add(2 * time.Second): 0.0,
+	}))
+	assert.True(t, result)
+}
+
+func TestWindowTimeSource(t *testing.T) {
+	start := time.Unix(100_000, 0)
+	testcases := []struct {
+		windowTimeSource string
+		// the late constant data point is received 10 minutes after the first one,
+		// but its timestamp is only 2 minutes later
+		expectedLateSifted bool
+	}{
+		{
+			windowTimeSource:   windowTimeSourceDataPoint,
+			expectedLateSifted: true,
+		},
+		{
+			windowTimeSource:   windowTimeSourceReceived,
+			expectedLateSifted: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.windowTimeSource, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.MinPointAccumulationTime = 0
+			cfg.WindowTimeSource = tc.windowTimeSource
+			sieve := newMetricSieve(cfg)
+			now := start
+			sieve.now = func() time.Time { return now }
+
+			// the first data point is always reported
+			assert.False(t, sieve.Sift(dataPointsToMetric(map[time.Time]float64{start: 0.0})))
+
+			now = start.Add(1 * time.Minute)
+			assert.True(t, sieve.Sift(dataPointsToMetric(map[time.Time]float64{start.Add(1 * time.Minute): 0.0})))
+
+			now = start.Add(10 * time.Minute)
+			result := sieve.Sift(dataPointsToMetric(map[time.Time]float64{start.Add(2 * time.Minute): 0.0}))
+			assert.Equal(t, tc.expectedLateSifted, result)
+
+			// an out-of-order data point is sifted regardless of the time source,
+			// as the metric is constant and it's within the report frequency
+			now = start.Add(11 * time.Minute)
+			assert.True(t, sieve.Sift(dataPointsToMetric(map[time.Time]float64{start.Add(30 * time.Second): 0.0})))
+		})
+	}
+}
+
+func TestStaleDataPoint(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MinPointAccumulationTime = 0
+	sieve := newMetricSieve(cfg)
+	var timestamp = time.Unix(0, 0)
+	setupHistory(sieve, map[time.Time]float64{timestamp: 0.0})
+	assert.True(t, sieve.Sift(dataPointsToMetric(map[time.Time]float64{timestamp.Add(1 * time.Minute): 0.0})))
+
+	stale := dataPointsToMetric(map[time.Time]float64{timestamp.Add(2 * time.Minute): 0.0})
+	stale.Gauge().DataPoints().At(0).SetFlags(pdata.NewMetricDataPointFlags(pdata.MetricDataPointFlagNoRecordedValue))
+
+	// the staleness marker is not sifted and the cached data points of the metric are removed
+	assert.False(t, sieve.Sift(stale))
+	assert.Equal(t, 1, stale.Gauge().DataPoints().Len())
+	assert.Empty(t, sieve.metricCache.List("test"))
+	_, exists := sieve.metricCache.LastReported("test")
+	assert.False(t, exists)
+
+	// the metric is reported again as a new one
+	assert.False(t, sieve.Sift(dataPointsToMetric(map[time.Time]float64{timestamp.Add(3 * time.Minute): 0.0})))
+}
+
+func TestStaleDataPointOtherSeries(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MinPointAccumulationTime = 0
+	sieve := newMetricSieve(cfg)
+	var timestamp = time.Unix(0, 0)
+
+	withPod := func(metric pdata.Metric, pod string) pdata.Metric {
+		dataPoints := metric.Gauge().DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			dataPoints.At(i).Attributes().InsertString("pod", pod)
+		}
+		return metric
+	}
+
+	for _, pod := range []string{"a", "b"} {
+		sieve.Sift(withPod(dataPointsToMetric(map[time.Time]float64{timestamp: 0.0}), pod))
+		assert.True(t, sieve.Sift(withPod(dataPointsToMetric(map[time.Time]float64{timestamp.Add(1 * time.Minute): 0.0}), pod)))
+	}
+
+	stale := withPod(dataPointsToMetric(map[time.Time]float64{timestamp.Add(2 * time.Minute): 0.0}), "a")
+	stale.Gauge().DataPoints().At(0).SetFlags(pdata.NewMetricDataPointFlags(pdata.MetricDataPointFlagNoRecordedValue))
+	assert.False(t, sieve.Sift(stale))
+
+	// only the cached data points of the stale series are removed
+	assert.Empty(t, sieve.metricCache.List(`test{pod="a"}`))
+	assert.Len(t, sieve.metricCache.List(`test{pod="b"}`), 2)
+
+	// the other series keeps its history and is still sifted
+	assert.True(t, sieve.Sift(withPod(dataPointsToMetric(map[time.Time]float64{timestamp.Add(3 * time.Minute): 0.0}), "b")))
+}
+
 func TestIsConstant(t *testing.T) {
 	type testCase struct {
 		dataPoint     pdata.NumberDataPoint
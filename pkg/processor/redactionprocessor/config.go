@@ -0,0 +1,132 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the redaction processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:"-"`
+
+	// BuiltinPatterns are the names of the built-in patterns to redact:
+	// credit_card, email, ipv4 and ipv6.
+	// By default, when it's not set, all of them are used.
+	BuiltinPatterns []string `mapstructure:"builtin_patterns"`
+
+	// Patterns are the custom patterns to redact, applied after the built-in ones.
+	Patterns []PatternConfig `mapstructure:"patterns"`
+
+	// Mode defines how the matched values are redacted, either mask or hash.
+	// By default this is mask.
+	Mode string `mapstructure:"mode"`
+
+	// Mask is the string replacing the matched values in the mask mode.
+	// By default this is ****.
+	Mask string `mapstructure:"mask"`
+
+	// HashKey is the key of the HMAC-SHA256 hash replacing the matched values
+	// in the hash mode. Without the key the hashes of the values with few possible
+	// values, like IP addresses, can be reversed by computing all of them.
+	HashKey string `mapstructure:"hash_key"`
+
+	// Body enables the redaction of the log bodies.
+	// By default this is true.
+	Body bool `mapstructure:"body"`
+
+	// Attributes are the keys of the resource, log record and span attributes
+	// which are redacted.
+	Attributes []string `mapstructure:"attributes"`
+}
+
+// PatternConfig defines a custom pattern of the values to redact.
+type PatternConfig struct {
+	// Name is the name of the pattern, used in the metrics.
+	Name string `mapstructure:"name"`
+
+	// Regex matches the values to redact.
+	Regex string `mapstructure:"regex"`
+}
+
+const (
+	modeMask = "mask"
+	modeHash = "hash"
+)
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	builtinNames := cfg.enabledBuiltinPatterns()
+	if len(builtinNames) == 0 && len(cfg.Patterns) == 0 {
+		return errors.New("either builtin_patterns or patterns have to be specified")
+	}
+
+	names := make(map[string]struct{}, len(builtinNames)+len(cfg.Patterns))
+	for _, name := range builtinNames {
+		if _, ok := builtinPatterns[name]; !ok {
+			return fmt.Errorf("unknown builtin pattern: %q", name)
+		}
+		if _, ok := names[name]; ok {
+			return fmt.Errorf("pattern %q is configured more than once", name)
+		}
+		names[name] = struct{}{}
+	}
+	for _, p := range cfg.Patterns {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("pattern %q has invalid configuration: %w", p.Name, err)
+		}
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("pattern %q is configured more than once", p.Name)
+		}
+		names[p.Name] = struct{}{}
+	}
+
+	switch cfg.Mode {
+	case modeMask, modeHash:
+	default:
+		return fmt.Errorf("unexpected mode: %q", cfg.Mode)
+	}
+
+	if !cfg.Body && len(cfg.Attributes) == 0 {
+		return errors.New("either body or attributes have to be redacted")
+	}
+	return nil
+}
+
+// enabledBuiltinPatterns returns the names of the built-in patterns to redact
+func (cfg *Config) enabledBuiltinPatterns() []string {
+	if cfg.BuiltinPatterns == nil {
+		return builtinPatternNames
+	}
+	return cfg.BuiltinPatterns
+}
+
+// Validate checks if the pattern configuration is valid
+func (p *PatternConfig) Validate() error {
+	if p.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if p.Regex == "" {
+		return errors.New("regex cannot be empty")
+	}
+	if _, err := regexp.Compile(p.Regex); err != nil {
+		return fmt.Errorf("invalid regex %q: %w", p.Regex, err)
+	}
+	return nil
+}
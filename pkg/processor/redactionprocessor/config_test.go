@@ -0,0 +1,131 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[factory.Type()] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Processors[config.NewComponentID(typeStr)])
+
+	assert.Equal(t,
+		&Config{
+			ProcessorSettings: config.NewProcessorSettings(config.NewComponentIDWithName(typeStr, "custom")),
+			BuiltinPatterns:   []string{"email"},
+			Patterns: []PatternConfig{
+				{Name: "api_key", Regex: "key-[0-9a-f]{32}"},
+			},
+			Mode:       modeHash,
+			Mask:       defaultMask,
+			HashKey:    "secret",
+			Body:       false,
+			Attributes: []string{"user.email", "http.url"},
+		},
+		cfg.Processors[config.NewComponentIDWithName(typeStr, "custom")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		modify        func(cfg *Config)
+		expectedError string
+	}{
+		{
+			name: "custom patterns only",
+			modify: func(cfg *Config) {
+				cfg.BuiltinPatterns = []string{}
+				cfg.Patterns = []PatternConfig{{Name: "ssn", Regex: `\d{3}-\d{2}-\d{4}`}}
+			},
+		},
+		{
+			name: "no patterns",
+			modify: func(cfg *Config) {
+				cfg.BuiltinPatterns = []string{}
+			},
+			expectedError: "either builtin_patterns or patterns have to be specified",
+		},
+		{
+			name: "unknown builtin pattern",
+			modify: func(cfg *Config) {
+				cfg.BuiltinPatterns = []string{"phone"}
+			},
+			expectedError: `unknown builtin pattern: "phone"`,
+		},
+		{
+			name: "no name",
+			modify: func(cfg *Config) {
+				cfg.Patterns = []PatternConfig{{Regex: "secret"}}
+			},
+			expectedError: `pattern "" has invalid configuration: name cannot be empty`,
+		},
+		{
+			name: "invalid regex",
+			modify: func(cfg *Config) {
+				cfg.Patterns = []PatternConfig{{Name: "secret", Regex: "("}}
+			},
+			expectedError: "pattern \"secret\" has invalid configuration: invalid regex \"(\": error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name: "duplicated name",
+			modify: func(cfg *Config) {
+				cfg.Patterns = []PatternConfig{{Name: "email", Regex: "@"}}
+			},
+			expectedError: `pattern "email" is configured more than once`,
+		},
+		{
+			name:          "unexpected mode",
+			modify:        func(cfg *Config) { cfg.Mode = "drop" },
+			expectedError: `unexpected mode: "drop"`,
+		},
+		{
+			name:          "nothing to redact",
+			modify:        func(cfg *Config) { cfg.Body = false },
+			expectedError: "either body or attributes have to be redacted",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
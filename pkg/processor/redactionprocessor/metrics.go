@@ -0,0 +1,59 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func init() {
+	err := view.Register(viewMatches)
+	if err != nil {
+		fmt.Printf("Error registering redaction processor's views: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+var (
+	tagPatternKey, _ = tag.NewKey("pattern")
+
+	mMatches = stats.Int64("redaction_matches", "Number of redacted values, per pattern", stats.UnitDimensionless)
+)
+
+var viewMatches = &view.View{
+	Name:        mMatches.Name(),
+	Description: mMatches.Description(),
+	Measure:     mMatches,
+	TagKeys:     []tag.Key{tagPatternKey},
+	Aggregation: view.Sum(),
+}
+
+// recordMatches increments the metric by the number of the pattern's redacted values
+func recordMatches(pattern string, count int) {
+	if count == 0 {
+		return
+	}
+	_ = stats.RecordWithTags(
+		context.Background(),
+		[]tag.Mutator{tag.Upsert(tagPatternKey, pattern)},
+		mMatches.M(int64(count)),
+	)
+}
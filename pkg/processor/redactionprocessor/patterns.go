@@ -0,0 +1,101 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// pattern matches the values to redact.
+type pattern struct {
+	name  string
+	regex *regexp.Regexp
+	// valid checks the match further, when the regex alone isn't precise enough.
+	// It's nil when every match is redacted.
+	valid func(match string) bool
+}
+
+type builtinPattern struct {
+	regex string
+	valid func(match string) bool
+}
+
+// builtinPatternNames lists the built-in patterns in the order they are applied.
+var builtinPatternNames = []string{"credit_card", "email", "ipv4", "ipv6"}
+
+var builtinPatterns = map[string]builtinPattern{
+	// 13 to 19 digits, optionally separated with spaces or dashes, with a valid Luhn checksum.
+	"credit_card": {
+		regex: `\b\d(?:[ -]?\d){12,18}\b`,
+		valid: luhnValid,
+	},
+	"email": {
+		regex: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+	},
+	"ipv4": {
+		regex: `\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`,
+	},
+	// The regex matches any colon separated hexadecimal groups, e.g. times or MAC addresses,
+	// so the matches are checked to be IPv6 addresses.
+	"ipv6": {
+		regex: `(?:[0-9A-Fa-f]{0,4}:){2,7}[0-9A-Fa-f]{0,4}`,
+		valid: func(match string) bool {
+			return strings.Count(match, ":") >= 2 && net.ParseIP(match) != nil
+		},
+	},
+}
+
+func newPatterns(cfg *Config) []pattern {
+	builtinNames := cfg.enabledBuiltinPatterns()
+	patterns := make([]pattern, 0, len(builtinNames)+len(cfg.Patterns))
+	for _, name := range builtinNames {
+		bp := builtinPatterns[name]
+		patterns = append(patterns, pattern{
+			name:  name,
+			regex: regexp.MustCompile(bp.regex),
+			valid: bp.valid,
+		})
+	}
+	for _, pc := range cfg.Patterns {
+		patterns = append(patterns, pattern{
+			name:  pc.Name,
+			regex: regexp.MustCompile(pc.Regex),
+		})
+	}
+	return patterns
+}
+
+// luhnValid checks the number with the Luhn algorithm, ignoring spaces and dashes.
+func luhnValid(number string) bool {
+	sum, digits := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+	return digits >= 13 && digits <= 19 && sum%10 == 0
+}
@@ -0,0 +1,154 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+type redactionProcessor struct {
+	patterns   []pattern
+	body       bool
+	attributes []string
+	// redact returns the replacement of the matched value
+	redact func(match string) string
+}
+
+func newRedactionProcessor(cfg *Config) *redactionProcessor {
+	rp := &redactionProcessor{
+		patterns:   newPatterns(cfg),
+		body:       cfg.Body,
+		attributes: cfg.Attributes,
+	}
+
+	switch cfg.Mode {
+	case modeHash:
+		key := []byte(cfg.HashKey)
+		rp.redact = func(match string) string {
+			h := hmac.New(sha256.New, key)
+			h.Write([]byte(match))
+			return hex.EncodeToString(h.Sum(nil))
+		}
+	default:
+		mask := cfg.Mask
+		rp.redact = func(string) string { return mask }
+	}
+
+	return rp
+}
+
+// ProcessLogs redacts the log bodies and the configured attributes
+func (rp *redactionProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	counts := make([]int, len(rp.patterns))
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		rp.redactAttributes(rl.Resource().Attributes(), counts)
+
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).LogRecords()
+			for k := 0; k < logs.Len(); k++ {
+				log := logs.At(k)
+				if rp.body {
+					rp.redactValue(log.Body(), counts)
+				}
+				rp.redactAttributes(log.Attributes(), counts)
+			}
+		}
+	}
+
+	rp.recordMatches(counts)
+	return ld, nil
+}
+
+// ProcessTraces redacts the configured attributes of the spans
+func (rp *redactionProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	counts := make([]int, len(rp.patterns))
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		rp.redactAttributes(rs.Resource().Attributes(), counts)
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				rp.redactAttributes(spans.At(k).Attributes(), counts)
+			}
+		}
+	}
+
+	rp.recordMatches(counts)
+	return td, nil
+}
+
+func (rp *redactionProcessor) redactAttributes(attributes pdata.AttributeMap, counts []int) {
+	for _, key := range rp.attributes {
+		if value, ok := attributes.Get(key); ok {
+			rp.redactValue(value, counts)
+		}
+	}
+}
+
+// redactValue redacts the string value, or the string values nested in the map or array value
+func (rp *redactionProcessor) redactValue(value pdata.AttributeValue, counts []int) {
+	switch value.Type() {
+	case pdata.AttributeValueTypeString:
+		if redacted, ok := rp.redactString(value.StringVal(), counts); ok {
+			value.SetStringVal(redacted)
+		}
+	case pdata.AttributeValueTypeMap:
+		value.MapVal().Range(func(_ string, v pdata.AttributeValue) bool {
+			rp.redactValue(v, counts)
+			return true
+		})
+	case pdata.AttributeValueTypeArray:
+		values := value.SliceVal()
+		for i := 0; i < values.Len(); i++ {
+			rp.redactValue(values.At(i), counts)
+		}
+	}
+}
+
+// redactString replaces the matches of all the patterns in s and returns
+// whether there were any matches
+func (rp *redactionProcessor) redactString(s string, counts []int) (string, bool) {
+	redacted := false
+	for i, p := range rp.patterns {
+		s = p.regex.ReplaceAllStringFunc(s, func(match string) string {
+			if p.valid != nil && !p.valid(match) {
+				return match
+			}
+			counts[i]++
+			redacted = true
+			return rp.redact(match)
+		})
+	}
+	return s, redacted
+}
+
+func (rp *redactionProcessor) recordMatches(counts []int) {
+	for i, count := range counts {
+		recordMatches(rp.patterns[i].name, count)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func newTestProcessor(t *testing.T, modify func(cfg *Config)) *redactionProcessor {
+	cfg := createDefaultConfig().(*Config)
+	if modify != nil {
+		modify(cfg)
+	}
+	require.NoError(t, cfg.Validate())
+
+	return newRedactionProcessor(cfg)
+}
+
+func TestBuiltinPatterns(t *testing.T) {
+	testcases := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "payment with 4111 1111 1111 1111 accepted",
+			expected: "payment with **** accepted",
+		},
+		{
+			input:    "payment with 4111-1111-1111-1111 accepted",
+			expected: "payment with **** accepted",
+		},
+		{
+			input:    "order 4111111111111112 is not a card number",
+			expected: "order 4111111111111112 is not a card number",
+		},
+		{
+			input:    "user john.doe+test@example.com logged in",
+			expected: "user **** logged in",
+		},
+		{
+			input:    "request from 192.168.0.1:8080",
+			expected: "request from ****:8080",
+		},
+		{
+			input:    "version 1.2.3.456 released",
+			expected: "version 1.2.3.456 released",
+		},
+		{
+			input:    "request from 2001:db8::8a2e:370:7334 and ::1",
+			expected: "request from **** and ****",
+		},
+		{
+			input:    "started at 12:30:45 on 00:1a:2b:3c:4d:5e",
+			expected: "started at 12:30:45 on 00:1a:2b:3c:4d:5e",
+		},
+	}
+
+	rp := newTestProcessor(t, nil)
+	for _, tc := range testcases {
+		t.Run(tc.input, func(t *testing.T) {
+			counts := make([]int, len(rp.patterns))
+			redacted, _ := rp.redactString(tc.input, counts)
+			assert.Equal(t, tc.expected, redacted)
+		})
+	}
+}
+
+func TestProcessLogs(t *testing.T) {
+	rp := newTestProcessor(t, func(cfg *Config) {
+		cfg.Attributes = []string{"user.email", "client.address"}
+		cfg.Patterns = []PatternConfig{{Name: "password", Regex: `password=\S+`}}
+	})
+
+	logs := pdata.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString("client.address", "10.0.0.1")
+	rl.Resource().Attributes().InsertString("host.ip", "10.0.0.2")
+	records := rl.InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+
+	log := records.AppendEmpty()
+	log.Body().SetStringVal("login of admin@example.com with password=hunter2")
+	log.Attributes().InsertString("user.email", "admin@example.com")
+	log.Attributes().InsertString("user.name", "admin@example.com")
+
+	log = records.AppendEmpty()
+	body := pdata.NewAttributeValueMap()
+	body.MapVal().InsertString("message", "connection from 10.0.0.3")
+	body.MapVal().InsertInt("port", 8080)
+	body.CopyTo(log.Body())
+
+	logs, err := rp.ProcessLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	attributes := logs.ResourceLogs().At(0).Resource().Attributes()
+	assertAttribute(t, attributes, "client.address", "****")
+	assertAttribute(t, attributes, "host.ip", "10.0.0.2")
+
+	records = logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).LogRecords()
+	assert.Equal(t, "login of **** with ****", records.At(0).Body().StringVal())
+	assertAttribute(t, records.At(0).Attributes(), "user.email", "****")
+	assertAttribute(t, records.At(0).Attributes(), "user.name", "admin@example.com")
+
+	assertAttribute(t, records.At(1).Body().MapVal(), "message", "connection from ****")
+	port, ok := records.At(1).Body().MapVal().Get("port")
+	require.True(t, ok)
+	assert.EqualValues(t, 8080, port.IntVal())
+}
+
+func TestProcessLogsHash(t *testing.T) {
+	rp := newTestProcessor(t, func(cfg *Config) {
+		cfg.Mode = modeHash
+		cfg.HashKey = "key"
+	})
+
+	logs := pdata.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+	records.AppendEmpty().Body().SetStringVal("sent to admin@example.com")
+	records.AppendEmpty().Body().SetStringVal("admin@example.com replied")
+
+	logs, err := rp.ProcessLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	records = logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).LogRecords()
+	// HMAC-SHA256 of admin@example.com with the key
+	hash := rp.redact("admin@example.com")
+	assert.Len(t, hash, 64)
+	assert.Equal(t, "sent to "+hash, records.At(0).Body().StringVal())
+	assert.Equal(t, hash+" replied", records.At(1).Body().StringVal())
+
+	other := newTestProcessor(t, func(cfg *Config) {
+		cfg.Mode = modeHash
+		cfg.HashKey = "other key"
+	})
+	assert.NotEqual(t, hash, other.redact("admin@example.com"))
+}
+
+func TestProcessTraces(t *testing.T) {
+	rp := newTestProcessor(t, func(cfg *Config) {
+		cfg.Attributes = []string{"http.url", "tags"}
+	})
+
+	traces := pdata.NewTraces()
+	span := traces.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("GET /users/admin@example.com")
+	span.Attributes().InsertString("http.url", "http://10.0.0.1/users/admin@example.com")
+	tags := pdata.NewAttributeValueArray()
+	tags.SliceVal().AppendEmpty().SetStringVal("user:admin@example.com")
+	tags.SliceVal().AppendEmpty().SetStringVal("tier:gold")
+	span.Attributes().Insert("tags", tags)
+
+	traces, err := rp.ProcessTraces(context.Background(), traces)
+	require.NoError(t, err)
+
+	span = traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	// span names are not redacted
+	assert.Equal(t, "GET /users/admin@example.com", span.Name())
+	assertAttribute(t, span.Attributes(), "http.url", "http://****/users/****")
+	tagsValue, ok := span.Attributes().Get("tags")
+	require.True(t, ok)
+	assert.Equal(t, "user:****", tagsValue.SliceVal().At(0).StringVal())
+	assert.Equal(t, "tier:gold", tagsValue.SliceVal().At(1).StringVal())
+}
+
+func TestLuhnValid(t *testing.T) {
+	assert.True(t, luhnValid("4111111111111111"))
+	assert.True(t, luhnValid("5500-0000-0000-0004"))
+	assert.True(t, luhnValid("3782 822463 10005"))
+	assert.False(t, luhnValid("4111111111111112"))
+	// too short
+	assert.False(t, luhnValid("0000000000"))
+}
+
+func assertAttribute(t *testing.T, attributes pdata.AttributeMap, key string, expected string) {
+	value, ok := attributes.Get(key)
+	require.True(t, ok, "attribute %q not found", key)
+	assert.Equal(t, expected, value.StringVal())
+}
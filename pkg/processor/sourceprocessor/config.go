@@ -15,6 +15,11 @@
 package sourceprocessor
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
 	"go.opentelemetry.io/collector/config"
 )
 
@@ -28,22 +33,134 @@ type Config struct {
 	SourceCategory            string `mapstructure:"source_category"`
 	SourceCategoryPrefix      string `mapstructure:"source_category_prefix"`
 	SourceCategoryReplaceDash string `mapstructure:"source_category_replace_dash"`
+	// SourceCategoryStrategy is the name of the custom strategy of computing
+	// the source category, registered with WithSourceCategoryStrategy,
+	// which is used instead of the SourceCategory template.
+	SourceCategoryStrategy string `mapstructure:"source_category_strategy"`
 
 	// Exclude is a mapping of field names to exclusion regexes for those
 	// particular fields.
 	// Whenever a value for a particular field matches a corresponding regex,
 	// the processed entry is dropped.
 	Exclude map[string]string `mapstructure:"exclude"`
+	// ExposeExclusions makes the exclusion decisions available to the receivers
+	// (see ExclusionDecider), so they can stop collecting data which is dropped anyway.
+	ExposeExclusions bool `mapstructure:"expose_exclusions"`
+	// ExcludeSeverityBelow drops the log records with the severity lower than
+	// the given one (TRACE, DEBUG, INFO, WARN, ERROR or FATAL).
+	ExcludeSeverityBelow string `mapstructure:"exclude_severity_below"`
+	// NamespaceSeverityThresholds overrides ExcludeSeverityBelow for the log records
+	// of the given namespaces (the k8s.namespace.name resource attribute).
+	NamespaceSeverityThresholds map[string]string `mapstructure:"namespace_severity_thresholds"`
 
 	AnnotationPrefix   string `mapstructure:"annotation_prefix"`
 	PodKey             string `mapstructure:"pod_key"`
 	PodNameKey         string `mapstructure:"pod_name_key"`
 	PodTemplateHashKey string `mapstructure:"pod_template_hash_key"`
+	// PodNameExtraction lists the regexes matched against the pod name (the value of PodKey),
+	// which add their named groups as resource attributes, e.g. for use in the source templates.
+	PodNameExtraction []PodNameExtractionConfig `mapstructure:"pod_name_extraction"`
 
 	ContainerAnnotations ContainerAnnotationsConfig `mapstructure:"container_annotations"`
+	// DeleteAnnotationAttributes removes the sumologic.com/ pod annotation attributes
+	// (including the container-level ones) after they are used, so they are not sent as fields.
+	DeleteAnnotationAttributes bool `mapstructure:"delete_annotation_attributes"`
+
+	// Script is the Lua script executed for every resource, for the rules of filling
+	// the source fields or dropping the data which are too complex for the templates.
+	Script ScriptConfig `mapstructure:"script"`
+}
+
+// ScriptConfig defines the Lua script executed for every resource. The script has to define
+// the process function, which gets the resource attributes and returns nil or a table with
+// the source_category, source_host and source_name fields and the drop decision.
+type ScriptConfig struct {
+	// Source is the Lua code of the script.
+	Source string `mapstructure:"source"`
+	// File is the path of the file with the Lua code of the script,
+	// it cannot be used together with Source.
+	File string `mapstructure:"file"`
+	// Timeout limits the execution time of the script for a single resource,
+	// the resource is left unchanged when it's exceeded.
+	// By default this is 10ms.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+func (cfg *ScriptConfig) enabled() bool {
+	return cfg.Source != "" || cfg.File != ""
 }
 
 type ContainerAnnotationsConfig struct {
 	Enabled  bool     `mapstructure:"enabled"`
 	Prefixes []string `mapstructure:"prefixes"`
 }
+
+// PodNameExtractionConfig defines the extraction of resource attributes from the pod name
+type PodNameExtractionConfig struct {
+	// Regex is matched against the pod name. When it matches, the value of each
+	// of its named groups is added as a resource attribute named after the group.
+	Regex string `mapstructure:"regex"`
+	// AttributePrefix is prepended to the group names to get the attribute names.
+	AttributePrefix string `mapstructure:"attribute_prefix"`
+}
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.ExcludeSeverityBelow != "" {
+		if _, ok := parseSeverity(cfg.ExcludeSeverityBelow); !ok {
+			return fmt.Errorf("exclude_severity_below has invalid severity: %q", cfg.ExcludeSeverityBelow)
+		}
+	}
+	for namespace, severity := range cfg.NamespaceSeverityThresholds {
+		if _, ok := parseSeverity(severity); !ok {
+			return fmt.Errorf("namespace_severity_thresholds has invalid severity for namespace %q: %q", namespace, severity)
+		}
+	}
+	for i, e := range cfg.PodNameExtraction {
+		if err := e.Validate(); err != nil {
+			return fmt.Errorf("pod_name_extraction %d has invalid configuration: %w", i, err)
+		}
+	}
+	if err := cfg.Script.Validate(); err != nil {
+		return fmt.Errorf("script has invalid configuration: %w", err)
+	}
+	return nil
+}
+
+// Validate checks if the script configuration is valid
+func (cfg *ScriptConfig) Validate() error {
+	if !cfg.enabled() {
+		return nil
+	}
+	if cfg.Source != "" && cfg.File != "" {
+		return errors.New("only one of source and file can be specified")
+	}
+	if cfg.Timeout <= 0 {
+		return fmt.Errorf("timeout has to be positive: %s", cfg.Timeout)
+	}
+	if cfg.Source != "" {
+		if _, err := compileScript("script", cfg.Source); err != nil {
+			return fmt.Errorf("failed to compile source: %w", err)
+		}
+	}
+	return nil
+}
+
+// Validate checks if the pod name extraction configuration is valid
+func (cfg *PodNameExtractionConfig) Validate() error {
+	if cfg.Regex == "" {
+		return errors.New("regex has to be specified")
+	}
+
+	re, err := regexp.Compile(cfg.Regex)
+	if err != nil {
+		return fmt.Errorf("invalid regex %q: %w", cfg.Regex, err)
+	}
+
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("regex %q has no named groups", cfg.Regex)
+}
@@ -16,9 +16,12 @@ package sourceprocessor
 
 import (
 	"path"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/service/servicetest"
@@ -60,11 +63,22 @@ func TestLoadConfig(t *testing.T) {
 			"k8s.pod.name":       "excluded_pod_regex",
 			"_SYSTEMD_UNIT":      "excluded_systemd_unit_regex",
 		},
+		ExposeExclusions:     true,
+		ExcludeSeverityBelow: "info",
+		NamespaceSeverityThresholds: map[string]string{
+			"debugged_namespace": "TRACE",
+		},
 
 		AnnotationPrefix:   "pod_annotation_",
 		PodKey:             "k8s.pod.name",
 		PodNameKey:         "k8s.pod.pod_name",
 		PodTemplateHashKey: "pod_labels_pod-template-hash",
+		PodNameExtraction: []PodNameExtractionConfig{
+			{
+				Regex:           "-(?P<track>canary|stable)-",
+				AttributePrefix: "k8s.pod.",
+			},
+		},
 
 		ContainerAnnotations: ContainerAnnotationsConfig{
 			Enabled: false,
@@ -72,5 +86,83 @@ func TestLoadConfig(t *testing.T) {
 				"sumologic.com/",
 			},
 		},
+		DeleteAnnotationAttributes: true,
+
+		Script: ScriptConfig{
+			File:    "/etc/otelcol/source.lua",
+			Timeout: 5 * time.Millisecond,
+		},
 	})
 }
+
+func TestValidateSeverities(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ExcludeSeverityBelow = "INFO"
+	cfg.NamespaceSeverityThresholds = map[string]string{"dev": "debug"}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.ExcludeSeverityBelow = "VERBOSE"
+	assert.EqualError(t, cfg.Validate(), `exclude_severity_below has invalid severity: "VERBOSE"`)
+
+	cfg.ExcludeSeverityBelow = ""
+	cfg.NamespaceSeverityThresholds = map[string]string{"dev": "VERBOSE"}
+	assert.EqualError(t, cfg.Validate(), `namespace_severity_thresholds has invalid severity for namespace "dev": "VERBOSE"`)
+}
+
+func TestValidateScript(t *testing.T) {
+	testcases := []struct {
+		name          string
+		script        ScriptConfig
+		expectedError string
+	}{
+		{
+			name:   "no script",
+			script: ScriptConfig{Timeout: time.Millisecond},
+		},
+		{
+			name: "source",
+			script: ScriptConfig{
+				Source:  "function process(resource) return nil end",
+				Timeout: time.Millisecond,
+			},
+		},
+		{
+			name: "source and file",
+			script: ScriptConfig{
+				Source:  "function process(resource) return nil end",
+				File:    "/etc/otelcol/source.lua",
+				Timeout: time.Millisecond,
+			},
+			expectedError: "script has invalid configuration: only one of source and file can be specified",
+		},
+		{
+			name:          "non-positive timeout",
+			script:        ScriptConfig{File: "/etc/otelcol/source.lua"},
+			expectedError: "script has invalid configuration: timeout has to be positive: 0s",
+		},
+		{
+			name: "syntax error",
+			script: ScriptConfig{
+				Source:  "function process(resource",
+				Timeout: time.Millisecond,
+			},
+			expectedError: "script has invalid configuration: failed to compile source: script at EOF:   syntax error",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Script = tc.script
+
+			err := cfg.Validate()
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				// the lua errors end with a new line
+				assert.Equal(t, tc.expectedError, strings.TrimSpace(err.Error()))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
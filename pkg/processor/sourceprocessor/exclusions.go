@@ -0,0 +1,139 @@
+// Copyright 2022 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceprocessor
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// Attribute keys which are provided for the log files of Kubernetes pods by IsK8sLogPathExcluded.
+const (
+	K8sNamespaceNameKey = "k8s.namespace.name"
+	K8sPodNameKey       = "k8s.pod.name"
+	K8sPodUIDKey        = "k8s.pod.uid"
+	K8sContainerNameKey = "k8s.container.name"
+)
+
+// ExclusionDecider lets the receivers learn whether the data would be dropped by the source processor,
+// so they can stop collecting it (e.g. stop tailing a file) instead of reading and parsing it.
+type ExclusionDecider interface {
+	// IsExcluded returns true if data with the given resource attributes is dropped by the processor.
+	// Attributes which are not known to the receiver should be omitted. Note that include annotation
+	// is taken into account only if it's provided, so the decision is reliable only when
+	// the annotations are known or not used.
+	IsExcluded(attributes map[string]string) bool
+	// ExclusionAttributes returns keys of the resource attributes which are matched
+	// against the exclusion regexes.
+	ExclusionAttributes() []string
+}
+
+var (
+	exclusionDecidersLock sync.RWMutex
+	exclusionDeciders     = map[ExclusionDecider]struct{}{}
+)
+
+func registerExclusionDecider(d ExclusionDecider) {
+	exclusionDecidersLock.Lock()
+	defer exclusionDecidersLock.Unlock()
+	exclusionDeciders[d] = struct{}{}
+}
+
+func unregisterExclusionDecider(d ExclusionDecider) {
+	exclusionDecidersLock.Lock()
+	defer exclusionDecidersLock.Unlock()
+	delete(exclusionDeciders, d)
+}
+
+// ExclusionDeciders returns the deciders of all running source processors
+// with `expose_exclusions` enabled.
+func ExclusionDeciders() []ExclusionDecider {
+	exclusionDecidersLock.RLock()
+	defer exclusionDecidersLock.RUnlock()
+
+	ret := make([]ExclusionDecider, 0, len(exclusionDeciders))
+	for d := range exclusionDeciders {
+		ret = append(ret, d)
+	}
+	return ret
+}
+
+// IsExcluded returns true if data with the given resource attributes is dropped by any of the running
+// source processors with `expose_exclusions` enabled.
+func IsExcluded(attributes map[string]string) bool {
+	for _, d := range ExclusionDeciders() {
+		if d.IsExcluded(attributes) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsK8sLogPathExcluded returns true if logs from the given file are dropped by any of the running
+// source processors with `expose_exclusions` enabled. The path has to follow the Kubernetes
+// pod log files convention: /var/log/pods/<namespace>_<pod_name>_<pod_uid>/<container_name>/<n>.log.
+// It returns false for all other paths.
+func IsK8sLogPathExcluded(path string) bool {
+	attributes, ok := attributesFromK8sLogPath(path)
+	if !ok {
+		return false
+	}
+	return IsExcluded(attributes)
+}
+
+// attributesFromK8sLogPath extracts the resource attributes from Kubernetes pod log file path
+func attributesFromK8sLogPath(path string) (map[string]string, bool) {
+	containerDir := filepath.Dir(path)
+	podDir := filepath.Dir(containerDir)
+
+	podParts := strings.Split(filepath.Base(podDir), "_")
+	if len(podParts) != 3 || filepath.Ext(path) != ".log" {
+		return nil, false
+	}
+	for _, p := range podParts {
+		if p == "" {
+			return nil, false
+		}
+	}
+
+	return map[string]string{
+		K8sNamespaceNameKey: podParts[0],
+		K8sPodNameKey:       podParts[1],
+		K8sPodUIDKey:        podParts[2],
+		K8sContainerNameKey: filepath.Base(containerDir),
+	}, true
+}
+
+// IsExcluded returns true if data with the given resource attributes is dropped by the processor.
+func (sp *sourceProcessor) IsExcluded(attributes map[string]string) bool {
+	atts := pdata.NewAttributeMap()
+	for k, v := range attributes {
+		atts.InsertString(k, v)
+	}
+	sp.enrichPodName(&atts)
+	return sp.isFilteredOut(atts)
+}
+
+// ExclusionAttributes returns keys of the resource attributes which are matched against the exclusion regexes.
+func (sp *sourceProcessor) ExclusionAttributes() []string {
+	keys := make([]string, 0, len(sp.exclude))
+	for k := range sp.exclude {
+		keys = append(keys, k)
+	}
+	return keys
+}
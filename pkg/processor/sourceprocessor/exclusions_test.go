@@ -0,0 +1,143 @@
+// Copyright 2022 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExclusionDeciderRegistration(t *testing.T) {
+	cfg := createConfig()
+	cfg.ExposeExclusions = true
+	cfg.Exclude = map[string]string{
+		"k8s.namespace.name": "excluded-.*",
+	}
+	sp := newSourceProcessor(cfg)
+
+	attributes := map[string]string{"k8s.namespace.name": "excluded-namespace"}
+	assert.False(t, IsExcluded(attributes), "processor is not started yet")
+
+	require.NoError(t, sp.Start(context.Background(), nil))
+	assert.True(t, IsExcluded(attributes))
+	assert.False(t, IsExcluded(map[string]string{"k8s.namespace.name": "namespace"}))
+	assert.Equal(t, []string{"k8s.namespace.name"}, sp.ExclusionAttributes())
+
+	require.NoError(t, sp.Shutdown(context.Background()))
+	assert.False(t, IsExcluded(attributes), "processor is shut down")
+}
+
+func TestExclusionDeciderNotExposed(t *testing.T) {
+	cfg := createConfig()
+	cfg.Exclude = map[string]string{
+		"k8s.namespace.name": "excluded-.*",
+	}
+	sp := newSourceProcessor(cfg)
+
+	require.NoError(t, sp.Start(context.Background(), nil))
+	defer func() { require.NoError(t, sp.Shutdown(context.Background())) }()
+
+	assert.Empty(t, ExclusionDeciders())
+	assert.False(t, IsExcluded(map[string]string{"k8s.namespace.name": "excluded-namespace"}))
+}
+
+func TestSourceProcessorIsExcluded(t *testing.T) {
+	cfg := createConfig()
+	cfg.Exclude = map[string]string{
+		"k8s.pod.pod_name":   "excluded-pod",
+		"k8s.container.name": "sidecar",
+	}
+	sp := newSourceProcessor(cfg)
+
+	testcases := []struct {
+		name       string
+		attributes map[string]string
+		excluded   bool
+	}{
+		{
+			name:       "deuniquified pod name",
+			attributes: map[string]string{"k8s.pod.name": "excluded-pod-5db86d8867-sdqlj"},
+			excluded:   true,
+		},
+		{
+			name:       "container",
+			attributes: map[string]string{"k8s.pod.name": "pod-1", "k8s.container.name": "sidecar"},
+			excluded:   true,
+		},
+		{
+			name: "include annotation",
+			attributes: map[string]string{
+				"k8s.container.name":                   "sidecar",
+				"pod_annotation_sumologic.com/include": "true",
+			},
+			excluded: false,
+		},
+		{
+			name:       "exclude annotation",
+			attributes: map[string]string{"pod_annotation_sumologic.com/exclude": "true"},
+			excluded:   true,
+		},
+		{
+			name:       "not matching",
+			attributes: map[string]string{"k8s.pod.name": "pod-1", "k8s.container.name": "app"},
+			excluded:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.excluded, sp.IsExcluded(tc.attributes))
+		})
+	}
+}
+
+func TestAttributesFromK8sLogPath(t *testing.T) {
+	attributes, ok := attributesFromK8sLogPath("/var/log/pods/namespace-1_pod-1_1234-abcd/container-1/0.log")
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{
+		"k8s.namespace.name": "namespace-1",
+		"k8s.pod.name":       "pod-1",
+		"k8s.pod.uid":        "1234-abcd",
+		"k8s.container.name": "container-1",
+	}, attributes)
+
+	for _, path := range []string{
+		"/var/log/syslog",
+		"/var/log/pods/namespace-1_pod-1/container-1/0.log",
+		"/var/log/pods/namespace-1_pod-1_1234-abcd/container-1/0.log.gz",
+		"/var/log/pods/_pod-1_1234-abcd/container-1/0.log",
+	} {
+		_, ok := attributesFromK8sLogPath(path)
+		assert.False(t, ok, path)
+	}
+}
+
+func TestIsK8sLogPathExcluded(t *testing.T) {
+	cfg := createConfig()
+	cfg.ExposeExclusions = true
+	cfg.Exclude = map[string]string{
+		"k8s.namespace.name": "kube-system",
+	}
+	sp := newSourceProcessor(cfg)
+	require.NoError(t, sp.Start(context.Background(), nil))
+	defer func() { require.NoError(t, sp.Shutdown(context.Background())) }()
+
+	assert.True(t, IsK8sLogPathExcluded("/var/log/pods/kube-system_coredns-1_1234/coredns/0.log"))
+	assert.False(t, IsK8sLogPathExcluded("/var/log/pods/default_app-1_1234/app/0.log"))
+	assert.False(t, IsK8sLogPathExcluded("/var/log/syslog"))
+}
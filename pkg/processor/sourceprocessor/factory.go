@@ -16,6 +16,7 @@ package sourceprocessor
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
@@ -39,18 +40,21 @@ const (
 	defaultPodKey             = "k8s.pod.name"
 	defaultPodNameKey         = "k8s.pod.pod_name"
 	defaultPodTemplateHashKey = "k8s.pod.label.pod-template-hash"
+
+	defaultScriptTimeout = 10 * time.Millisecond
 )
 
 var processorCapabilities = consumer.Capabilities{MutatesData: true}
 
 // NewFactory returns a new factory for the Span processor.
-func NewFactory() component.ProcessorFactory {
+func NewFactory(opts ...FactoryOption) component.ProcessorFactory {
+	o := newFactoryOptions(opts)
 	return component.NewProcessorFactory(
 		typeStr,
 		createDefaultConfig,
-		component.WithTracesProcessor(createTraceProcessor),
-		component.WithMetricsProcessor(createMetricsProcessor),
-		component.WithLogsProcessor(createLogsProcessor),
+		component.WithTracesProcessor(o.createTraceProcessor),
+		component.WithMetricsProcessor(o.createMetricsProcessor),
+		component.WithLogsProcessor(o.createLogsProcessor),
 	)
 }
 
@@ -77,11 +81,15 @@ func createDefaultConfig() config.Processor {
 				"sumologic.com/",
 			},
 		},
+
+		Script: ScriptConfig{
+			Timeout: defaultScriptTimeout,
+		},
 	}
 }
 
 // CreateTraceProcessor creates a trace processor based on this config.
-func createTraceProcessor(
+func (o *factoryOptions) createTraceProcessor(
 	_ context.Context,
 	params component.ProcessorCreateSettings,
 	cfg config.Processor,
@@ -89,18 +97,23 @@ func createTraceProcessor(
 
 	oCfg := cfg.(*Config)
 
-	sp := newSourceProcessor(oCfg)
+	sp, err := o.newSourceProcessor(oCfg, params.Logger)
+	if err != nil {
+		return nil, err
+	}
 
 	return processorhelper.NewTracesProcessor(
 		cfg,
 		next,
 		sp.ProcessTraces,
 		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(sp.Start),
+		processorhelper.WithShutdown(sp.Shutdown),
 	)
 }
 
 // createMetricsProcessor creates a metrics processor based on this config
-func createMetricsProcessor(
+func (o *factoryOptions) createMetricsProcessor(
 	_ context.Context,
 	params component.ProcessorCreateSettings,
 	cfg config.Processor,
@@ -108,17 +121,22 @@ func createMetricsProcessor(
 ) (component.MetricsProcessor, error) {
 	oCfg := cfg.(*Config)
 
-	sp := newSourceProcessor(oCfg)
+	sp, err := o.newSourceProcessor(oCfg, params.Logger)
+	if err != nil {
+		return nil, err
+	}
 	return processorhelper.NewMetricsProcessor(
 		cfg,
 		next,
 		sp.ProcessMetrics,
 		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(sp.Start),
+		processorhelper.WithShutdown(sp.Shutdown),
 	)
 }
 
 // createLogsProcessor creates a logs processor based on this config
-func createLogsProcessor(
+func (o *factoryOptions) createLogsProcessor(
 	_ context.Context,
 	params component.ProcessorCreateSettings,
 	cfg config.Processor,
@@ -126,11 +144,16 @@ func createLogsProcessor(
 ) (component.LogsProcessor, error) {
 	oCfg := cfg.(*Config)
 
-	sp := newSourceProcessor(oCfg)
+	sp, err := o.newSourceProcessor(oCfg, params.Logger)
+	if err != nil {
+		return nil, err
+	}
 	return processorhelper.NewLogsProcessor(
 		cfg,
 		next,
 		sp.ProcessLogs,
 		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(sp.Start),
+		processorhelper.WithShutdown(sp.Shutdown),
 	)
 }
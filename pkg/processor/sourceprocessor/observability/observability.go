@@ -28,6 +28,8 @@ func init() {
 		viewResourceSpansProcessed,
 		viewRecordsFilteredOut,
 		viewRecordsFilteredIn,
+		viewResourcesDropped,
+		viewScriptErrors,
 	)
 	if err != nil {
 		fmt.Printf("Error registering source processor's views: %v\n", err)
@@ -39,6 +41,8 @@ var (
 	mResouceSpansProcessed = stats.Int64("otelsvc/sumo/resource_spans_processed", "Number of record span packages processed", "1")
 	mRecordsFilteredOut    = stats.Int64("otelsvc/sumo/records_filtered_out", "Number of records filtered out", "1")
 	mRecordsFilteredIn     = stats.Int64("otelsvc/sumo/records_filtered_in", "Number of records filtered in", "1")
+	mResourcesDropped      = stats.Int64("otelsvc/sumo/resources_dropped", "Number of resources dropped as all their records were filtered out", "1")
+	mScriptErrors          = stats.Int64("otelsvc/sumo/script_errors", "Number of resources the script failed for, including the timeouts", "1")
 )
 
 var viewResourceSpansProcessed = &view.View{
@@ -62,6 +66,20 @@ var viewRecordsFilteredIn = &view.View{
 	Aggregation: view.Sum(),
 }
 
+var viewResourcesDropped = &view.View{
+	Name:        mResourcesDropped.Name(),
+	Description: mResourcesDropped.Description(),
+	Measure:     mResourcesDropped,
+	Aggregation: view.Sum(),
+}
+
+var viewScriptErrors = &view.View{
+	Name:        mScriptErrors.Name(),
+	Description: mScriptErrors.Description(),
+	Measure:     mScriptErrors,
+	Aggregation: view.Sum(),
+}
+
 // RecordResourceSpansProcessed increments the metric that resource spans package was processed
 func RecordResourceSpansProcessed() {
 	stats.Record(context.Background(), mResouceSpansProcessed.M(int64(1)))
@@ -86,3 +104,13 @@ func RecordFilteredIn() {
 func RecordFilteredInN(n int) {
 	stats.Record(context.Background(), mRecordsFilteredIn.M(int64(n)))
 }
+
+// RecordResourceDropped increments the metric that records resource dropped as all its records were filtered out
+func RecordResourceDropped() {
+	stats.Record(context.Background(), mResourcesDropped.M(int64(1)))
+}
+
+// RecordScriptError increments the metric that records resource the script failed for
+func RecordScriptError() {
+	stats.Record(context.Background(), mScriptErrors.M(int64(1)))
+}
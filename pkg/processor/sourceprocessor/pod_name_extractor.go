@@ -0,0 +1,72 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceprocessor
+
+import (
+	"regexp"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// podNameExtractor adds the named groups of the regexes matching the pod name
+// as resource attributes
+type podNameExtractor struct {
+	regex           *regexp.Regexp
+	attributePrefix string
+}
+
+func newPodNameExtractors(cfgs []PodNameExtractionConfig) []podNameExtractor {
+	extractors := make([]podNameExtractor, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if r := compileRegex(cfg.Regex); r != nil {
+			extractors = append(extractors, podNameExtractor{
+				regex:           r,
+				attributePrefix: cfg.AttributePrefix,
+			})
+		}
+	}
+	return extractors
+}
+
+// extract upserts the values of the named groups which took part in the match.
+// Nothing is added when the regex doesn't match.
+func (e *podNameExtractor) extract(podName string, atts pdata.AttributeMap) {
+	match := e.regex.FindStringSubmatch(podName)
+	if match == nil {
+		return
+	}
+
+	for i, name := range e.regex.SubexpNames() {
+		if name == "" || match[i] == "" {
+			continue
+		}
+		atts.UpsertString(e.attributePrefix+name, match[i])
+	}
+}
+
+func (sp *sourceProcessor) extractFromPodName(atts pdata.AttributeMap) {
+	if len(sp.podNameExtractors) == 0 {
+		return
+	}
+
+	pod, found := atts.Get(sp.keys.podKey)
+	if !found || pod.Type() != pdata.AttributeValueTypeString {
+		return
+	}
+
+	for i := range sp.podNameExtractors {
+		sp.podNameExtractors[i].extract(pod.StringVal(), atts)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPodNameExtraction(t *testing.T) {
+	testcases := []struct {
+		name     string
+		pod      string
+		expected map[string]string
+	}{
+		{
+			name: "canary",
+			pod:  "checkout-canary-5db86d8867-sdqlj",
+			expected: map[string]string{
+				"k8s.pod.app":   "checkout",
+				"k8s.pod.track": "canary",
+			},
+		},
+		{
+			name: "stable",
+			pod:  "checkout-stable-5db86d8867-sdqlj",
+			expected: map[string]string{
+				"k8s.pod.app":   "checkout",
+				"k8s.pod.track": "stable",
+			},
+		},
+		{
+			name: "no match",
+			pod:  "checkout-5db86d8867-sdqlj",
+			expected: map[string]string{
+				"k8s.pod.app":   "",
+				"k8s.pod.track": "",
+			},
+		},
+		{
+			name: "optional group not matching",
+			pod:  "checkout-5db86d8867-sdqlj-shard-2",
+			expected: map[string]string{
+				"shard":         "2",
+				"k8s.pod.track": "",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			inputAttributes := createK8sLabels()
+			inputAttributes["k8s.pod.name"] = tc.pod
+			traces := newTraceData(inputAttributes)
+
+			config := createDefaultConfig().(*Config)
+			config.PodNameExtraction = []PodNameExtractionConfig{
+				{
+					Regex:           `^(?P<app>[a-z]+)-(?P<track>canary|stable)-[a-z0-9]+-[a-z0-9]{5}$`,
+					AttributePrefix: "k8s.pod.",
+				},
+				{
+					Regex: `-shard-(?P<shard>[0-9]+)(?P<replica>-r[0-9]+)?$`,
+				},
+			}
+			require.NoError(t, config.Validate())
+
+			processedTraces, err := newSourceProcessor(config).ProcessTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			attributes := processedTraces.ResourceSpans().At(0).Resource().Attributes()
+			for k, v := range tc.expected {
+				assertAttribute(t, attributes, k, v)
+			}
+			assertAttribute(t, attributes, "replica", "")
+		})
+	}
+}
+
+func TestPodNameExtractionInSourceCategory(t *testing.T) {
+	inputAttributes := createK8sLabels()
+	inputAttributes["k8s.pod.name"] = "checkout-canary-5db86d8867-sdqlj"
+	inputAttributes["k8s.pod.label.pod-template-hash"] = "5db86d8867"
+	traces := newTraceData(inputAttributes)
+
+	config := createDefaultConfig().(*Config)
+	config.SourceCategory = "%{k8s.namespace.name}/%{k8s.pod.pod_name}/%{track}"
+	config.PodNameExtraction = []PodNameExtractionConfig{
+		{Regex: `-(?P<track>canary|stable)-`},
+	}
+
+	processedTraces, err := newSourceProcessor(config).ProcessTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	attributes := processedTraces.ResourceSpans().At(0).Resource().Attributes()
+	assertAttribute(t, attributes, "_sourceCategory", "kubernetes/namespace/1/checkout/canary/canary")
+}
+
+func TestPodNameExtractionValidate(t *testing.T) {
+	testcases := []struct {
+		name        string
+		cfg         PodNameExtractionConfig
+		expectedErr string
+	}{
+		{
+			name:        "no regex",
+			cfg:         PodNameExtractionConfig{},
+			expectedErr: "pod_name_extraction 0 has invalid configuration: regex has to be specified",
+		},
+		{
+			name:        "invalid regex",
+			cfg:         PodNameExtractionConfig{Regex: "(?P<track>"},
+			expectedErr: "pod_name_extraction 0 has invalid configuration: invalid regex \"(?P<track>\": error parsing regexp: missing closing ): `(?P<track>`",
+		},
+		{
+			name:        "no named groups",
+			cfg:         PodNameExtractionConfig{Regex: "-(canary|stable)-"},
+			expectedErr: "pod_name_extraction 0 has invalid configuration: regex \"-(canary|stable)-\" has no named groups",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.PodNameExtraction = []PodNameExtractionConfig{tc.cfg}
+			assert.EqualError(t, config.Validate(), tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,336 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceprocessor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	// scriptFunction is the name of the global function of the script called for every resource
+	scriptFunction = "process"
+
+	scriptResultDrop           = "drop"
+	scriptResultSourceCategory = "source_category"
+	scriptResultSourceHost     = "source_host"
+	scriptResultSourceName     = "source_name"
+
+	// scriptCallStackSize and scriptRegistrySize limit the memory used by the script
+	scriptCallStackSize   = 64
+	scriptRegistrySize    = 1024
+	scriptRegistryMaxSize = 64 * 1024
+	// scriptMaxStringLength limits the length of the strings built by string.rep and table.concat
+	scriptMaxStringLength = 1024 * 1024
+)
+
+// scriptUnsafeGlobals are removed from the script environment, so the script
+// cannot access the filesystem, load other code or write to the standard output
+var scriptUnsafeGlobals = []string{
+	"collectgarbage",
+	"dofile",
+	"getfenv",
+	"load",
+	"loadfile",
+	"loadstring",
+	"module",
+	"newproxy",
+	"print",
+	"require",
+	"setfenv",
+	"_printregs",
+}
+
+// scriptResultFields maps the fields returned by the script to the resource attributes
+var scriptResultFields = map[string]string{
+	scriptResultSourceCategory: sourceCategoryKey,
+	scriptResultSourceHost:     sourceHostKey,
+	scriptResultSourceName:     sourceNameKey,
+}
+
+// scriptResult is the decision of the script for a single resource
+type scriptResult struct {
+	drop bool
+	// fields are the source attributes to be set on the resource
+	fields map[string]string
+}
+
+// script runs the Lua script configured with the script option. The Lua states
+// are not safe for concurrent use, so they are kept in a pool. The script is loaded
+// into a new environment with fresh copies of the libraries for every call,
+// so neither the globals nor the changes of the libraries made by the script
+// are shared between the resources.
+type script struct {
+	cfg    ScriptConfig
+	proto  *lua.FunctionProto
+	states sync.Pool
+}
+
+// compileScript parses and compiles the Lua source
+func compileScript(name string, source string) (*lua.FunctionProto, error) {
+	chunk, err := parse.Parse(strings.NewReader(source), name)
+	if err != nil {
+		return nil, err
+	}
+	return lua.Compile(chunk, name)
+}
+
+// newScript loads and compiles the script, it returns nil if the script is not configured
+func newScript(cfg ScriptConfig) (*script, error) {
+	if !cfg.enabled() {
+		return nil, nil
+	}
+
+	name, source := "script", cfg.Source
+	if cfg.File != "" {
+		data, err := os.ReadFile(cfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read script file: %w", err)
+		}
+		name, source = cfg.File, string(data)
+	}
+
+	proto, err := compileScript(name, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile script: %w", err)
+	}
+
+	s := &script{
+		cfg:   cfg,
+		proto: proto,
+	}
+
+	// Load the script right away, so the errors of its top-level code are reported on start
+	state, err := s.newState()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Timeout)
+	defer cancel()
+	state.SetContext(ctx)
+	_, err = s.load(state)
+	state.RemoveContext()
+	if err != nil {
+		state.Close()
+		return nil, err
+	}
+	s.states.Put(state)
+	return s, nil
+}
+
+// newState creates the sandboxed Lua state
+func (s *script) newState() (*lua.LState, error) {
+	state := lua.NewState(lua.Options{
+		SkipOpenLibs:    true,
+		CallStackSize:   scriptCallStackSize,
+		RegistrySize:    scriptRegistrySize,
+		RegistryMaxSize: scriptRegistryMaxSize,
+	})
+
+	libs := []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	}
+	for _, lib := range libs {
+		if err := state.CallByParam(lua.P{Fn: state.NewFunction(lib.open), Protect: true}, lua.LString(lib.name)); err != nil {
+			state.Close()
+			return nil, fmt.Errorf("failed to open lua library %s: %w", lib.name, err)
+		}
+	}
+	for _, name := range scriptUnsafeGlobals {
+		state.SetGlobal(name, lua.LNil)
+	}
+
+	limitStringLength(state, lua.StringLibName, "rep", repLength)
+	limitStringLength(state, lua.TabLibName, "concat", concatLength)
+
+	// The string methods are looked up in the string library of the state through the metatable
+	// of the strings, so the metatable is hidden from getmetatable to keep the library read-only
+	if mt, ok := state.GetMetatable(lua.LString("")).(*lua.LTable); ok {
+		mt.RawSetString("__metatable", lua.LFalse)
+	}
+	return state, nil
+}
+
+// limitStringLength replaces the function of the library with the one which raises an error
+// instead of building a string longer than scriptMaxStringLength, so the script
+// cannot allocate an arbitrary amount of memory
+func limitStringLength(state *lua.LState, lib string, name string, length func(*lua.LState) int) {
+	tbl, ok := state.GetGlobal(lib).(*lua.LTable)
+	if !ok {
+		return
+	}
+	fn, ok := tbl.RawGetString(name).(*lua.LFunction)
+	if !ok || fn.GFunction == nil {
+		return
+	}
+	tbl.RawSetString(name, state.NewFunction(func(L *lua.LState) int {
+		if length(L) > scriptMaxStringLength {
+			L.RaiseError("%s.%s: result longer than %d bytes", lib, name, scriptMaxStringLength)
+		}
+		return fn.GFunction(L)
+	}))
+}
+
+// repLength returns the length of the result of string.rep(s, n)
+func repLength(L *lua.LState) int {
+	str, n := L.CheckString(1), L.CheckInt(2)
+	if n <= 0 || len(str) == 0 {
+		return 0
+	}
+	if n > scriptMaxStringLength/len(str) {
+		return scriptMaxStringLength + 1
+	}
+	return n * len(str)
+}
+
+// concatLength returns the length of the result of table.concat(t [, sep [, i [, j]]])
+func concatLength(L *lua.LState) int {
+	tbl, sep := L.CheckTable(1), L.OptString(2, "")
+	i, j := L.OptInt(3, 1), L.OptInt(4, tbl.Len())
+	if i < 1 {
+		i = 1
+	}
+	if j > tbl.Len() {
+		j = tbl.Len()
+	}
+
+	n := 0
+	for ; i <= j && n <= scriptMaxStringLength; i++ {
+		if v := tbl.RawGetInt(i); lua.LVCanConvToString(v) {
+			n += len(lua.LVAsString(v))
+		}
+		if i != j {
+			n += len(sep)
+		}
+	}
+	return n
+}
+
+// load runs the top-level code of the script in a new environment and returns its process function.
+// The environment holds copies of the globals of the state, and of the library tables,
+// so the globals set and the libraries modified by the script are kept in the environment only.
+func (s *script) load(state *lua.LState) (*lua.LFunction, error) {
+	env := state.NewTable()
+	state.G.Global.ForEach(func(k, v lua.LValue) {
+		if lib, ok := v.(*lua.LTable); ok && lib != state.G.Global {
+			libCopy := state.NewTable()
+			lib.ForEach(libCopy.RawSet)
+			v = libCopy
+		}
+		env.RawSet(k, v)
+	})
+	env.RawSetString("_G", env)
+
+	chunk := state.NewFunctionFromProto(s.proto)
+	chunk.Env = env
+	state.Push(chunk)
+	if err := state.PCall(0, 0, nil); err != nil {
+		return nil, fmt.Errorf("failed to run script: %w", err)
+	}
+
+	process, ok := env.RawGetString(scriptFunction).(*lua.LFunction)
+	if !ok {
+		return nil, fmt.Errorf("script has to define the %s function", scriptFunction)
+	}
+	return process, nil
+}
+
+// run loads the script and calls its process function with the resource attributes
+// and returns its decision
+func (s *script) run(attributes pdata.AttributeMap) (scriptResult, error) {
+	state, ok := s.states.Get().(*lua.LState)
+	if !ok {
+		var err error
+		state, err = s.newState()
+		if err != nil {
+			return scriptResult{}, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Timeout)
+	defer cancel()
+	state.SetContext(ctx)
+
+	process, err := s.load(state)
+	if err == nil {
+		resource := state.CreateTable(0, attributes.Len())
+		attributes.Range(func(k string, v pdata.AttributeValue) bool {
+			resource.RawSetString(k, lua.LString(v.AsString()))
+			return true
+		})
+
+		err = state.CallByParam(lua.P{
+			Fn:      process,
+			NRet:    1,
+			Protect: true,
+		}, resource)
+	}
+	state.RemoveContext()
+	if err != nil {
+		// The state might be left inconsistent, e.g. when the script was interrupted
+		state.Close()
+		return scriptResult{}, err
+	}
+
+	ret := state.Get(-1)
+	state.Pop(1)
+	s.states.Put(state)
+
+	return parseScriptResult(ret)
+}
+
+// parseScriptResult converts the value returned by the process function,
+// either nil or a table with the drop decision and the source fields
+func parseScriptResult(ret lua.LValue) (scriptResult, error) {
+	var result scriptResult
+
+	switch ret := ret.(type) {
+	case *lua.LNilType:
+		return result, nil
+	case *lua.LTable:
+		result.drop = lua.LVAsBool(ret.RawGetString(scriptResultDrop))
+		for field, key := range scriptResultFields {
+			value := ret.RawGetString(field)
+			switch value.Type() {
+			case lua.LTNil:
+				continue
+			case lua.LTString, lua.LTNumber:
+				if result.fields == nil {
+					result.fields = make(map[string]string, len(scriptResultFields))
+				}
+				result.fields[key] = value.String()
+			default:
+				return scriptResult{}, fmt.Errorf("%s returned by the script has to be a string, got %s", field, value.Type())
+			}
+		}
+		return result, nil
+	default:
+		return result, fmt.Errorf("%s function has to return a table or nil, got %s", scriptFunction, ret.Type())
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceprocessor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const testScript = `
+function process(resource)
+  local namespace = resource["k8s.namespace.name"]
+  if namespace == "kube-system" then
+    return { drop = true }
+  end
+  if namespace ~= nil and string.find(namespace, "^team%-") then
+    return {
+      source_category = string.gsub(namespace, "^team%-", "teams/"),
+      source_name = resource["k8s.container.name"],
+    }
+  end
+  return nil
+end
+`
+
+func newTestScript(t *testing.T, source string) *script {
+	s, err := newScript(ScriptConfig{Source: source, Timeout: time.Second})
+	require.NoError(t, err)
+	return s
+}
+
+func attributeMap(attributes map[string]string) pdata.AttributeMap {
+	atts := pdata.NewAttributeMap()
+	for k, v := range attributes {
+		atts.InsertString(k, v)
+	}
+	return atts
+}
+
+func TestScriptRun(t *testing.T) {
+	s := newTestScript(t, testScript)
+
+	testcases := []struct {
+		name       string
+		attributes map[string]string
+		expected   scriptResult
+	}{
+		{
+			name:       "drop",
+			attributes: map[string]string{"k8s.namespace.name": "kube-system"},
+			expected:   scriptResult{drop: true},
+		},
+		{
+			name:       "source fields",
+			attributes: map[string]string{"k8s.namespace.name": "team-payments", "k8s.container.name": "api"},
+			expected: scriptResult{
+				fields: map[string]string{
+					"_sourceCategory": "teams/payments",
+					"_sourceName":     "api",
+				},
+			},
+		},
+		{
+			name:       "unchanged",
+			attributes: map[string]string{"k8s.namespace.name": "default"},
+			expected:   scriptResult{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := s.run(attributeMap(tc.attributes))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestScriptFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "source.lua")
+	require.NoError(t, os.WriteFile(path, []byte(testScript), 0600))
+
+	s, err := newScript(ScriptConfig{File: path, Timeout: time.Second})
+	require.NoError(t, err)
+
+	result, err := s.run(attributeMap(map[string]string{"k8s.namespace.name": "kube-system"}))
+	require.NoError(t, err)
+	assert.True(t, result.drop)
+
+	_, err = newScript(ScriptConfig{File: filepath.Join(dir, "missing.lua"), Timeout: time.Second})
+	assert.Error(t, err)
+}
+
+func TestScriptNotConfigured(t *testing.T) {
+	s, err := newScript(ScriptConfig{Timeout: time.Second})
+	require.NoError(t, err)
+	assert.Nil(t, s)
+}
+
+func TestScriptErrors(t *testing.T) {
+	testcases := []struct {
+		name          string
+		source        string
+		expectedError string
+	}{
+		{
+			name:          "no process function",
+			source:        `local x = 1`,
+			expectedError: "script has to define the process function",
+		},
+		{
+			name:          "error on load",
+			source:        `error("broken")`,
+			expectedError: "failed to run script: script:1: broken",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newScript(ScriptConfig{Source: tc.source, Timeout: time.Second})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScriptRunErrors(t *testing.T) {
+	testcases := []struct {
+		name          string
+		source        string
+		expectedError string
+	}{
+		{
+			name:          "runtime error",
+			source:        `function process(resource) return resource.missing.field end`,
+			expectedError: "attempt to index a non-table object(nil)",
+		},
+		{
+			name:          "invalid result",
+			source:        `function process(resource) return "category" end`,
+			expectedError: "process function has to return a table or nil, got string",
+		},
+		{
+			name:          "invalid field",
+			source:        `function process(resource) return { source_category = {} } end`,
+			expectedError: "source_category returned by the script has to be a string, got table",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestScript(t, tc.source)
+			_, err := s.run(pdata.NewAttributeMap())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScriptTimeout(t *testing.T) {
+	s, err := newScript(ScriptConfig{
+		Source:  `function process(resource) while true do end end`,
+		Timeout: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	_, err = s.run(pdata.NewAttributeMap())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "context deadline exceeded")
+
+	_, err = newScript(ScriptConfig{
+		Source:  `while true do end`,
+		Timeout: 10 * time.Millisecond,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "context deadline exceeded")
+}
+
+func TestScriptSandbox(t *testing.T) {
+	for _, global := range []string{"io", "os", "require", "dofile", "loadfile", "loadstring", "print"} {
+		t.Run(global, func(t *testing.T) {
+			s := newTestScript(t, `function process(resource) return { source_name = type(`+global+`) } end`)
+			result, err := s.run(pdata.NewAttributeMap())
+			require.NoError(t, err)
+			assert.Equal(t, "nil", result.fields["_sourceName"])
+		})
+	}
+}
+
+func TestScriptGlobalsNotShared(t *testing.T) {
+	s := newTestScript(t, `
+calls = 0
+
+local function count()
+  calls = calls + 1
+  _G.seen = (_G.seen or 0) + 1
+end
+
+function process(resource)
+  count()
+  return { source_name = calls .. "/" .. seen }
+end
+`)
+
+	for i := 0; i < 3; i++ {
+		result, err := s.run(pdata.NewAttributeMap())
+		require.NoError(t, err)
+		assert.Equal(t, "1/1", result.fields["_sourceName"])
+	}
+}
+
+func TestScriptLibrariesNotShared(t *testing.T) {
+	s := newTestScript(t, `
+function process(resource)
+  local seen = (string.cache or "") .. (table.cache or "") .. tostring(getmetatable("x"))
+  string.cache = "string"
+  table.cache = "table"
+  math.floor = nil
+  return { source_name = seen }
+end
+`)
+
+	for i := 0; i < 3; i++ {
+		result, err := s.run(pdata.NewAttributeMap())
+		require.NoError(t, err)
+		assert.Equal(t, "false", result.fields["_sourceName"])
+	}
+}
+
+func TestScriptStringLengthLimit(t *testing.T) {
+	testcases := []struct {
+		name          string
+		source        string
+		expectedError string
+	}{
+		{
+			name:          "string.rep",
+			source:        `function process(resource) return { source_name = string.rep("x", 1e9) } end`,
+			expectedError: "string.rep: result longer than 1048576 bytes",
+		},
+		{
+			name:          "string method",
+			source:        `function process(resource) return { source_name = ("xy"):rep(1e9) } end`,
+			expectedError: "string.rep: result longer than 1048576 bytes",
+		},
+		{
+			name: "table.concat",
+			source: `
+function process(resource)
+  local parts = {}
+  for i = 1, 2048 do parts[i] = string.rep("x", 1024) end
+  return { source_name = table.concat(parts, ",") }
+end
+`,
+			expectedError: "table.concat: result longer than 1048576 bytes",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestScript(t, tc.source)
+			_, err := s.run(pdata.NewAttributeMap())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+
+	s := newTestScript(t, `function process(resource) return { source_name = table.concat({ string.rep("x", 3), "y" }, "-") } end`)
+	result, err := s.run(pdata.NewAttributeMap())
+	require.NoError(t, err)
+	assert.Equal(t, "xxx-y", result.fields["_sourceName"])
+}
+
+func TestScriptConcurrentRuns(t *testing.T) {
+	s := newTestScript(t, testScript)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				result, err := s.run(attributeMap(map[string]string{"k8s.namespace.name": "team-a"}))
+				assert.NoError(t, err)
+				assert.Equal(t, "teams/a", result.fields["_sourceCategory"])
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLogsSourceProcessorScript(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Script.Source = testScript
+	sink := new(consumertest.LogsSink)
+	proc, err := factory.CreateLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	logs := newLogsDataWithLogs(map[string]string{"k8s.namespace.name": "team-payments", "k8s.container.name": "api"}, nil)
+	newLogsDataWithLogs(map[string]string{"k8s.namespace.name": "kube-system"}, nil).ResourceLogs().MoveAndAppendTo(logs.ResourceLogs())
+	newLogsDataWithLogs(map[string]string{"k8s.namespace.name": "default", "k8s.pod.pod_name": "web"}, nil).ResourceLogs().MoveAndAppendTo(logs.ResourceLogs())
+	require.NoError(t, proc.ConsumeLogs(context.Background(), logs))
+
+	require.Len(t, sink.AllLogs(), 1)
+	rls := sink.AllLogs()[0].ResourceLogs()
+	require.Equal(t, 2, rls.Len())
+	assertAttribute(t, rls.At(0).Resource().Attributes(), "_sourceCategory", "teams/payments")
+	assertAttribute(t, rls.At(0).Resource().Attributes(), "_sourceName", "api")
+	assertAttribute(t, rls.At(1).Resource().Attributes(), "_sourceCategory", "kubernetes/default/web")
+}
+
+func TestSourceProcessorScriptFailureKeepsResource(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Script.Source = `function process(resource) error("failed") end`
+	sink := new(consumertest.TracesSink)
+	proc, err := factory.CreateTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, proc.ConsumeTraces(context.Background(), newTraceData(map[string]string{"k8s.namespace.name": "default"})))
+
+	require.Len(t, sink.AllTraces(), 1)
+	rss := sink.AllTraces()[0].ResourceSpans()
+	require.Equal(t, 1, rss.Len())
+	category, ok := rss.At(0).Resource().Attributes().Get("_sourceCategory")
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(category.StringVal(), "kubernetes/default"))
+}
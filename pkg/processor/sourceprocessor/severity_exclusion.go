@@ -0,0 +1,86 @@
+// Copyright 2019 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceprocessor
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// severities maps the severity names to the lowest severity numbers of their ranges
+var severities = map[string]pdata.SeverityNumber{
+	"TRACE":   pdata.SeverityNumberTRACE,
+	"DEBUG":   pdata.SeverityNumberDEBUG,
+	"INFO":    pdata.SeverityNumberINFO,
+	"WARN":    pdata.SeverityNumberWARN,
+	"WARNING": pdata.SeverityNumberWARN,
+	"ERROR":   pdata.SeverityNumberERROR,
+	"FATAL":   pdata.SeverityNumberFATAL,
+}
+
+// parseSeverity returns the severity number of the case-insensitive severity name
+func parseSeverity(s string) (pdata.SeverityNumber, bool) {
+	n, ok := severities[strings.ToUpper(s)]
+	return n, ok
+}
+
+// severityExclusion drops the log records with the severity lower than the threshold,
+// which can be set per namespace
+type severityExclusion struct {
+	below      pdata.SeverityNumber
+	namespaces map[string]pdata.SeverityNumber
+}
+
+// newSeverityExclusion returns nil when no severity threshold is configured
+func newSeverityExclusion(cfg *Config) *severityExclusion {
+	if cfg.ExcludeSeverityBelow == "" && len(cfg.NamespaceSeverityThresholds) == 0 {
+		return nil
+	}
+
+	// the config is validated, so the severities are known
+	below, _ := parseSeverity(cfg.ExcludeSeverityBelow)
+	namespaces := make(map[string]pdata.SeverityNumber, len(cfg.NamespaceSeverityThresholds))
+	for namespace, severity := range cfg.NamespaceSeverityThresholds {
+		namespaces[namespace], _ = parseSeverity(severity)
+	}
+
+	return &severityExclusion{
+		below:      below,
+		namespaces: namespaces,
+	}
+}
+
+// threshold returns the severity threshold for the resource with the given attributes,
+// SeverityNumberUNDEFINED means that no log records are dropped
+func (se *severityExclusion) threshold(atts pdata.AttributeMap) pdata.SeverityNumber {
+	if namespace, ok := atts.Get(K8sNamespaceNameKey); ok {
+		if below, ok := se.namespaces[namespace.StringVal()]; ok {
+			return below
+		}
+	}
+	return se.below
+}
+
+// isExcludedBySeverity returns true if the severity of the log record is lower than the threshold.
+// The severity text is used when the severity number is not set, the log records
+// with unknown severity are never dropped.
+func isExcludedBySeverity(log pdata.LogRecord, below pdata.SeverityNumber) bool {
+	severity := log.SeverityNumber()
+	if severity == pdata.SeverityNumberUNDEFINED {
+		severity, _ = parseSeverity(log.SeverityText())
+	}
+	return severity != pdata.SeverityNumberUNDEFINED && severity < below
+}
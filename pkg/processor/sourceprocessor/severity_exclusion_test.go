@@ -0,0 +1,103 @@
+// Copyright 2019 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestNewSeverityExclusionNotConfigured(t *testing.T) {
+	assert.Nil(t, newSeverityExclusion(createDefaultConfig().(*Config)))
+}
+
+func TestIsExcludedBySeverity(t *testing.T) {
+	testcases := []struct {
+		name     string
+		number   pdata.SeverityNumber
+		text     string
+		excluded bool
+	}{
+		{name: "lower number", number: pdata.SeverityNumberDEBUG4, excluded: true},
+		{name: "equal number", number: pdata.SeverityNumberINFO},
+		{name: "higher number", number: pdata.SeverityNumberERROR},
+		{name: "number takes precedence over text", number: pdata.SeverityNumberWARN, text: "DEBUG"},
+		{name: "lower text", text: "debug", excluded: true},
+		{name: "higher text", text: "Warning"},
+		{name: "unknown text", text: "verbose"},
+		{name: "unknown severity"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			log := pdata.NewLogRecord()
+			log.SetSeverityNumber(tc.number)
+			log.SetSeverityText(tc.text)
+			assert.Equal(t, tc.excluded, isExcludedBySeverity(log, pdata.SeverityNumberINFO))
+		})
+	}
+}
+
+func TestLogsSeverityExclusion(t *testing.T) {
+	config := createConfig()
+	config.ExcludeSeverityBelow = "INFO"
+	config.NamespaceSeverityThresholds = map[string]string{
+		"noisy":    "WARN",
+		"debugged": "TRACE",
+	}
+	rtp := newSourceProcessor(config)
+
+	ld := pdata.NewLogs()
+	for _, namespace := range []string{"default", "noisy", "debugged"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().UpsertString(K8sNamespaceNameKey, namespace)
+		logs := rl.InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+		for _, severity := range []pdata.SeverityNumber{pdata.SeverityNumberDEBUG, pdata.SeverityNumberINFO, pdata.SeverityNumberWARN} {
+			log := logs.AppendEmpty()
+			log.SetSeverityNumber(severity)
+			log.Body().SetStringVal(severity.String())
+		}
+	}
+	// all the log records of this resource are dropped, so it's dropped as well
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().UpsertString(K8sNamespaceNameKey, "noisy")
+	rl.InstrumentationLibraryLogs().AppendEmpty().LogRecords().AppendEmpty().SetSeverityNumber(pdata.SeverityNumberINFO)
+
+	ld, err := rtp.ProcessLogs(context.Background(), ld)
+	require.NoError(t, err)
+	require.Equal(t, 3, ld.ResourceLogs().Len())
+
+	expected := map[string][]pdata.SeverityNumber{
+		"default":  {pdata.SeverityNumberINFO, pdata.SeverityNumberWARN},
+		"noisy":    {pdata.SeverityNumberWARN},
+		"debugged": {pdata.SeverityNumberDEBUG, pdata.SeverityNumberINFO, pdata.SeverityNumberWARN},
+	}
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		namespace, ok := rl.Resource().Attributes().Get(K8sNamespaceNameKey)
+		require.True(t, ok)
+
+		var severities []pdata.SeverityNumber
+		logs := rl.InstrumentationLibraryLogs().At(0).LogRecords()
+		for j := 0; j < logs.Len(); j++ {
+			severities = append(severities, logs.At(j).SeverityNumber())
+		}
+		assert.Equal(t, expected[namespace.StringVal()], severities, namespace.StringVal())
+	}
+}
@@ -30,6 +30,8 @@ type sourceCategoryFiller struct {
 	annotationPrefix             string
 	containerAnnotationsEnabled  bool
 	containerAnnotationsPrefixes []string
+	// strategy is the custom strategy used instead of valueTemplate, if set
+	strategy SourceCategoryStrategy
 }
 
 // newSourceCategoryFiller creates a new sourceCategoryFiller.
@@ -63,6 +65,7 @@ func extractTemplateAttributes(template string) []string {
 // The source category is retrieved from one of three places (in the following precedence):
 // - the source category container-level annotation (e.g. "k8s.pod.annotation.sumologic.com/container-name.sourceCategory"),
 // - the source category pod-level annotation (e.g. "k8s.pod.annotation.sumologic.com/sourceCategory"),
+// - the source category computed by the custom strategy selected with "source_category_strategy" option,
 // - the source category configured in the processor's "source_category" configuration option.
 func (f *sourceCategoryFiller) fill(attributes *pdata.AttributeMap) {
 	containerSourceCategory := f.getSourceCategoryFromContainerAnnotation(attributes)
@@ -71,15 +74,15 @@ func (f *sourceCategoryFiller) fill(attributes *pdata.AttributeMap) {
 		return
 	}
 
+	var sourceCategoryValue string
 	valueTemplate := getAnnotationAttributeValue(f.annotationPrefix, sourceCategorySpecialAnnotation, attributes)
-	var templateAttributes []string
 	if valueTemplate != "" {
-		templateAttributes = extractTemplateAttributes(valueTemplate)
+		sourceCategoryValue = f.replaceTemplateAttributes(valueTemplate, extractTemplateAttributes(valueTemplate), attributes)
+	} else if value, ok := f.getSourceCategoryFromStrategy(attributes); ok {
+		sourceCategoryValue = value
 	} else {
-		valueTemplate = f.valueTemplate
-		templateAttributes = f.templateAttributes
+		sourceCategoryValue = f.replaceTemplateAttributes(f.valueTemplate, f.templateAttributes, attributes)
 	}
-	sourceCategoryValue := f.replaceTemplateAttributes(valueTemplate, templateAttributes, attributes)
 
 	prefix := getAnnotationAttributeValue(f.annotationPrefix, sourceCategoryPrefixAnnotation, attributes)
 	if prefix == "" {
@@ -96,6 +99,13 @@ func (f *sourceCategoryFiller) fill(attributes *pdata.AttributeMap) {
 	attributes.UpsertString(sourceCategoryKey, sourceCategoryValue)
 }
 
+func (f *sourceCategoryFiller) getSourceCategoryFromStrategy(attributes *pdata.AttributeMap) (string, bool) {
+	if f.strategy == nil {
+		return "", false
+	}
+	return f.strategy.SourceCategory(*attributes)
+}
+
 func (f *sourceCategoryFiller) getSourceCategoryFromContainerAnnotation(attributes *pdata.AttributeMap) string {
 	if !f.containerAnnotationsEnabled {
 		return ""
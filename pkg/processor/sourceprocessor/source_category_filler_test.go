@@ -131,3 +131,37 @@ func TestFillWithContainerAnnotations(t *testing.T) {
 		assertAttribute(t, attrs, "_sourceCategory", "THIRD_s-c!")
 	})
 }
+
+func TestFillWithStrategy(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+
+	filler := newSourceCategoryFiller(cfg)
+	filler.strategy = SourceCategoryStrategyFunc(func(attributes pdata.AttributeMap) (string, bool) {
+		team, ok := attributes.Get("team")
+		if !ok {
+			return "", false
+		}
+		return "team-" + team.StringVal(), true
+	})
+
+	attrs := pdata.NewAttributeMap()
+	attrs.InsertString("k8s.namespace.name", "ns-1")
+	attrs.InsertString("k8s.pod.pod_name", "pod-1")
+	attrs.InsertString("team", "a")
+	filler.fill(&attrs)
+	assertAttribute(t, attrs, "_sourceCategory", "kubernetes/team/a")
+
+	// the template is used when the strategy cannot determine the source category
+	attrs = pdata.NewAttributeMap()
+	attrs.InsertString("k8s.namespace.name", "ns-1")
+	attrs.InsertString("k8s.pod.pod_name", "pod-1")
+	filler.fill(&attrs)
+	assertAttribute(t, attrs, "_sourceCategory", "kubernetes/ns/1/pod/1")
+
+	// the pod annotation takes precedence over the strategy
+	attrs = pdata.NewAttributeMap()
+	attrs.InsertString("team", "a")
+	attrs.InsertString("k8s.pod.annotation.sumologic.com/sourceCategory", "from-annotation")
+	filler.fill(&attrs)
+	assertAttribute(t, attrs, "_sourceCategory", "kubernetes/from/annotation")
+}
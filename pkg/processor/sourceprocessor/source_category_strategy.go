@@ -0,0 +1,87 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceprocessor
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+// SourceCategoryStrategy computes the source category of the record from its resource
+// attributes with custom logic, e.g. a lookup in an external inventory.
+// It's used instead of the `source_category` template when selected with
+// the `source_category_strategy` option, and it has to be safe for concurrent use.
+type SourceCategoryStrategy interface {
+	// SourceCategory returns the source category of the record, or false when
+	// it cannot be determined, in which case the `source_category` template is used.
+	SourceCategory(attributes pdata.AttributeMap) (string, bool)
+}
+
+// SourceCategoryStrategyFunc is an adapter allowing to use a function as SourceCategoryStrategy.
+type SourceCategoryStrategyFunc func(attributes pdata.AttributeMap) (string, bool)
+
+// SourceCategory calls f(attributes).
+func (f SourceCategoryStrategyFunc) SourceCategory(attributes pdata.AttributeMap) (string, bool) {
+	return f(attributes)
+}
+
+// FactoryOption configures the factory returned by NewFactory.
+type FactoryOption func(*factoryOptions)
+
+type factoryOptions struct {
+	strategies map[string]SourceCategoryStrategy
+}
+
+// WithSourceCategoryStrategy registers the strategy under the name, so it can be selected
+// with `source_category_strategy: <name>`. It allows distributions of the collector
+// to plug in their own logic of computing the source category.
+func WithSourceCategoryStrategy(name string, strategy SourceCategoryStrategy) FactoryOption {
+	return func(o *factoryOptions) {
+		o.strategies[name] = strategy
+	}
+}
+
+func newFactoryOptions(opts []FactoryOption) *factoryOptions {
+	o := &factoryOptions{
+		strategies: make(map[string]SourceCategoryStrategy),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// newSourceProcessor creates the processor using the source category strategy selected in the config
+// and the script
+func (o *factoryOptions) newSourceProcessor(cfg *Config, logger *zap.Logger) (*sourceProcessor, error) {
+	sp := newSourceProcessor(cfg)
+	if cfg.SourceCategoryStrategy != "" {
+		strategy, ok := o.strategies[cfg.SourceCategoryStrategy]
+		if !ok {
+			return nil, fmt.Errorf("unknown source category strategy: %s", cfg.SourceCategoryStrategy)
+		}
+		sp.sourceCategoryFiller.strategy = strategy
+	}
+
+	s, err := newScript(cfg.Script)
+	if err != nil {
+		return nil, err
+	}
+	sp.script = s
+	sp.logger = logger
+	return sp, nil
+}
@@ -23,6 +23,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
 
 	"github.com/SumoLogic/sumologic-otel-collector/pkg/processor/sourceprocessor/observability"
 )
@@ -47,8 +48,18 @@ type sourceProcessor struct {
 	sourceNameFiller     attributeFiller
 	sourceHostFiller     attributeFiller
 
-	exclude map[string]*regexp.Regexp
-	keys    sourceKeys
+	exclude           map[string]*regexp.Regexp
+	exposeExclusions  bool
+	keys              sourceKeys
+	podNameExtractors []podNameExtractor
+	// severityExclusion is nil unless the severity thresholds are configured
+	severityExclusion *severityExclusion
+	// deletedAnnotationPrefixes are the prefixes of the annotation attributes
+	// removed after processing, empty when they are kept
+	deletedAnnotationPrefixes []string
+	// script is nil unless the script is configured
+	script *script
+	logger *zap.Logger
 }
 
 const (
@@ -60,6 +71,8 @@ const (
 	sourceCategoryPrefixAnnotation      = "sumologic.com/sourceCategoryPrefix"
 	sourceCategoryReplaceDashAnnotation = "sumologic.com/sourceCategoryReplaceDash"
 
+	sumologicAnnotationPrefix = "sumologic.com/"
+
 	includeAnnotation = "sumologic.com/include"
 	excludeAnnotation = "sumologic.com/exclude"
 
@@ -97,6 +110,16 @@ func newSourceProcessor(cfg *Config) *sourceProcessor {
 		}
 	}
 
+	var deletedAnnotationPrefixes []string
+	if cfg.DeleteAnnotationAttributes {
+		deletedAnnotationPrefixes = append(deletedAnnotationPrefixes, cfg.AnnotationPrefix+sumologicAnnotationPrefix)
+		if cfg.ContainerAnnotations.Enabled {
+			for _, prefix := range cfg.ContainerAnnotations.Prefixes {
+				deletedAnnotationPrefixes = append(deletedAnnotationPrefixes, cfg.AnnotationPrefix+prefix)
+			}
+		}
+	}
+
 	return &sourceProcessor{
 		collector:            cfg.Collector,
 		keys:                 keys,
@@ -104,6 +127,12 @@ func newSourceProcessor(cfg *Config) *sourceProcessor {
 		sourceCategoryFiller: newSourceCategoryFiller(cfg),
 		sourceNameFiller:     createSourceNameFiller(cfg),
 		exclude:              exclude,
+		exposeExclusions:     cfg.ExposeExclusions,
+		severityExclusion:    newSeverityExclusion(cfg),
+		podNameExtractors:    newPodNameExtractors(cfg.PodNameExtraction),
+
+		deletedAnnotationPrefixes: deletedAnnotationPrefixes,
+		logger:                    zap.NewNop(),
 	}
 }
 
@@ -144,18 +173,61 @@ func (sp *sourceProcessor) isFilteredOut(atts pdata.AttributeMap) bool {
 	return false
 }
 
+// runScript runs the script for the resource and sets the source fields it returned,
+// it returns true if the script decided to drop the resource.
+// The resource is left unchanged when the script fails.
+func (sp *sourceProcessor) runScript(atts pdata.AttributeMap) bool {
+	if sp.script == nil {
+		return false
+	}
+
+	result, err := sp.script.run(atts)
+	if err != nil {
+		observability.RecordScriptError()
+		sp.logger.Warn("Source processor script failed", zap.Error(err))
+		return false
+	}
+
+	for k, v := range result.fields {
+		atts.UpsertString(k, v)
+	}
+	return result.drop
+}
+
 func (sp *sourceProcessor) annotationAttribute(annotationKey string) string {
 	return sp.keys.annotationPrefix + annotationKey
 }
 
+// deleteAnnotationAttributes removes the annotation attributes which were used
+// for filtering and filling the sources, when delete_annotation_attributes is enabled
+func (sp *sourceProcessor) deleteAnnotationAttributes(atts pdata.AttributeMap) {
+	if len(sp.deletedAnnotationPrefixes) == 0 {
+		return
+	}
+
+	var keys []string
+	atts.Range(func(k string, _ pdata.AttributeValue) bool {
+		for _, prefix := range sp.deletedAnnotationPrefixes {
+			if strings.HasPrefix(k, prefix) {
+				keys = append(keys, k)
+				break
+			}
+		}
+		return true
+	})
+
+	for _, k := range keys {
+		atts.Delete(k)
+	}
+}
+
 // ProcessTraces processes traces
 func (sp *sourceProcessor) ProcessTraces(ctx context.Context, td pdata.Traces) (pdata.Traces, error) {
-	rss := td.ResourceSpans()
-
-	for i := 0; i < rss.Len(); i++ {
+	// Resources with all the spans filtered out are removed,
+	// so they are not passed down the pipeline empty
+	td.ResourceSpans().RemoveIf(func(rs pdata.ResourceSpans) bool {
 		observability.RecordResourceSpansProcessed()
 
-		rs := rss.At(i)
 		res := sp.processResource(rs.Resource())
 		atts := res.Attributes()
 
@@ -166,47 +238,55 @@ func (sp *sourceProcessor) ProcessTraces(ctx context.Context, td pdata.Traces) (
 			totalSpans += ils.Spans().Len()
 		}
 
-		if sp.isFilteredOut(atts) {
-			rs.InstrumentationLibrarySpans().RemoveIf(func(pdata.InstrumentationLibrarySpans) bool { return true })
+		if sp.isFilteredOut(atts) || sp.runScript(atts) {
 			observability.RecordFilteredOutN(totalSpans)
-		} else {
-			observability.RecordFilteredInN(totalSpans)
+			observability.RecordResourceDropped()
+			return true
 		}
-	}
+
+		sp.deleteAnnotationAttributes(atts)
+		observability.RecordFilteredInN(totalSpans)
+		return false
+	})
 
 	return td, nil
 }
 
 // ProcessMetrics processes metrics
 func (sp *sourceProcessor) ProcessMetrics(ctx context.Context, md pdata.Metrics) (pdata.Metrics, error) {
-	rss := md.ResourceMetrics()
-
-	for i := 0; i < rss.Len(); i++ {
-		rs := rss.At(i)
+	md.ResourceMetrics().RemoveIf(func(rs pdata.ResourceMetrics) bool {
 		res := sp.processResource(rs.Resource())
 		atts := res.Attributes()
 
-		if sp.isFilteredOut(atts) {
-			rs.InstrumentationLibraryMetrics().RemoveIf(func(pdata.InstrumentationLibraryMetrics) bool { return true })
+		if sp.isFilteredOut(atts) || sp.runScript(atts) {
+			observability.RecordResourceDropped()
+			return true
 		}
-	}
+
+		sp.deleteAnnotationAttributes(atts)
+		return false
+	})
 
 	return md, nil
 }
 
 // ProcessLogs processes logs
 func (sp *sourceProcessor) ProcessLogs(ctx context.Context, md pdata.Logs) (pdata.Logs, error) {
-	rss := md.ResourceLogs()
-
 	var dockerLog dockerLog
 
-	for i := 0; i < rss.Len(); i++ {
-		rs := rss.At(i)
+	md.ResourceLogs().RemoveIf(func(rs pdata.ResourceLogs) bool {
 		res := sp.processResource(rs.Resource())
 		atts := res.Attributes()
 
-		if sp.isFilteredOut(atts) {
-			rs.InstrumentationLibraryLogs().RemoveIf(func(pdata.InstrumentationLibraryLogs) bool { return true })
+		if sp.isFilteredOut(atts) || sp.runScript(atts) {
+			observability.RecordResourceDropped()
+			return true
+		}
+		sp.deleteAnnotationAttributes(atts)
+
+		if sp.severityExclusion != nil && sp.dropLogsBySeverity(rs) {
+			observability.RecordResourceDropped()
+			return true
 		}
 
 		// Due to fluent-bit configuration for sumologic kubernetes collection,
@@ -242,19 +322,55 @@ func (sp *sourceProcessor) ProcessLogs(ctx context.Context, md pdata.Logs) (pdat
 				}
 			}
 		}
-	}
+
+		return false
+	})
 
 	return md, nil
 }
 
+// dropLogsBySeverity removes the log records of the resource with the severity lower
+// than its threshold, it returns true if all the log records of the resource were removed.
+func (sp *sourceProcessor) dropLogsBySeverity(rs pdata.ResourceLogs) bool {
+	below := sp.severityExclusion.threshold(rs.Resource().Attributes())
+	if below == pdata.SeverityNumberUNDEFINED {
+		return false
+	}
+
+	dropped := 0
+	ills := rs.InstrumentationLibraryLogs()
+	ills.RemoveIf(func(ill pdata.InstrumentationLibraryLogs) bool {
+		logs := ill.LogRecords()
+		if logs.Len() == 0 {
+			return false
+		}
+		logs.RemoveIf(func(log pdata.LogRecord) bool {
+			if isExcludedBySeverity(log, below) {
+				dropped++
+				return true
+			}
+			return false
+		})
+		return logs.Len() == 0
+	})
+
+	if dropped == 0 {
+		return false
+	}
+	observability.RecordFilteredOutN(dropped)
+	return ills.Len() == 0
+}
+
 // processResource performs multiple actions on resource:
 //   - enrich pod name, so it can be used in templates
+//   - extract attributes from pod name, so they can be used in templates
 //   - fills source attributes based on config or annotations
 //   - set metadata (collector name)
 func (sp *sourceProcessor) processResource(res pdata.Resource) pdata.Resource {
 	atts := res.Attributes()
 
 	sp.enrichPodName(&atts)
+	sp.extractFromPodName(atts)
 	sp.fillOtherMeta(atts)
 
 	sp.sourceHostFiller.fillResourceOrUseAnnotation(&atts,
@@ -269,12 +385,16 @@ func (sp *sourceProcessor) processResource(res pdata.Resource) pdata.Resource {
 }
 
 // Start is invoked during service startup.
-func (*sourceProcessor) Start(_context context.Context, _host component.Host) error {
+func (sp *sourceProcessor) Start(_context context.Context, _host component.Host) error {
+	if sp.exposeExclusions {
+		registerExclusionDecider(sp)
+	}
 	return nil
 }
 
 // Shutdown is invoked during service shutdown.
-func (*sourceProcessor) Shutdown(_context context.Context) error {
+func (sp *sourceProcessor) Shutdown(_context context.Context) error {
+	unregisterExclusionDecider(sp)
 	return nil
 }
 
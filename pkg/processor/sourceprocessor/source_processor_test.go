@@ -20,6 +20,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/model/pdata"
 )
 
@@ -272,8 +274,7 @@ func TestTraceSourceFilteringOutByRegex(t *testing.T) {
 			}(),
 			want: func() pdata.Traces {
 				want := newTraceDataWithSpans(mergedK8sLabels, k8sLabels)
-				want.ResourceSpans().At(0).InstrumentationLibrarySpans().
-					RemoveIf(func(pdata.InstrumentationLibrarySpans) bool { return true })
+				want.ResourceSpans().RemoveIf(func(pdata.ResourceSpans) bool { return true })
 				return want
 			}(),
 		},
@@ -288,8 +289,7 @@ func TestTraceSourceFilteringOutByRegex(t *testing.T) {
 			}(),
 			want: func() pdata.Traces {
 				want := newTraceDataWithSpans(mergedK8sLabels, k8sLabels)
-				want.ResourceSpans().At(0).InstrumentationLibrarySpans().
-					RemoveIf(func(pdata.InstrumentationLibrarySpans) bool { return true })
+				want.ResourceSpans().RemoveIf(func(pdata.ResourceSpans) bool { return true })
 				return want
 			}(),
 		},
@@ -304,8 +304,7 @@ func TestTraceSourceFilteringOutByRegex(t *testing.T) {
 			}(),
 			want: func() pdata.Traces {
 				want := newTraceDataWithSpans(mergedK8sLabels, k8sLabels)
-				want.ResourceSpans().At(0).InstrumentationLibrarySpans().
-					RemoveIf(func(pdata.InstrumentationLibrarySpans) bool { return true })
+				want.ResourceSpans().RemoveIf(func(pdata.ResourceSpans) bool { return true })
 				return want
 			}(),
 		},
@@ -340,8 +339,7 @@ func TestTraceSourceFilteringOutByExclude(t *testing.T) {
 		UpsertString("pod_annotation_sumologic.com/exclude", "true")
 
 	want := newTraceDataWithSpans(limitedLabelsWithMeta, mergedK8sLabels)
-	want.ResourceSpans().At(0).InstrumentationLibrarySpans().
-		RemoveIf(func(pdata.InstrumentationLibrarySpans) bool { return true })
+	want.ResourceSpans().RemoveIf(func(pdata.ResourceSpans) bool { return true })
 
 	rtp := newSourceProcessor(cfg)
 
@@ -351,6 +349,61 @@ func TestTraceSourceFilteringOutByExclude(t *testing.T) {
 	assertSpansEqual(t, want, td)
 }
 
+func TestSourceFilteringOutDropsResources(t *testing.T) {
+	excluded := map[string]string{"pod_annotation_sumologic.com/exclude": "true"}
+	rtp := newSourceProcessor(cfg)
+
+	t.Run("traces", func(t *testing.T) {
+		td := pdata.NewTraces()
+		for _, attrs := range []map[string]string{excluded, k8sLabels, excluded} {
+			rs := td.ResourceSpans().AppendEmpty()
+			for k, v := range attrs {
+				rs.Resource().Attributes().UpsertString(k, v)
+			}
+			rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty().SetName("foo")
+		}
+
+		td, err := rtp.ProcessTraces(context.Background(), td)
+		require.NoError(t, err)
+		require.Equal(t, 1, td.ResourceSpans().Len())
+		assert.Equal(t, 1, td.SpanCount())
+		_, ok := td.ResourceSpans().At(0).Resource().Attributes().Get("pod_annotation_sumologic.com/exclude")
+		assert.False(t, ok)
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		md := pdata.NewMetrics()
+		for _, attrs := range []map[string]string{excluded, k8sLabels} {
+			rm := md.ResourceMetrics().AppendEmpty()
+			for k, v := range attrs {
+				rm.Resource().Attributes().UpsertString(k, v)
+			}
+			rm.InstrumentationLibraryMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("foo")
+		}
+
+		md, err := rtp.ProcessMetrics(context.Background(), md)
+		require.NoError(t, err)
+		require.Equal(t, 1, md.ResourceMetrics().Len())
+		assert.Equal(t, 1, md.MetricCount())
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		ld := pdata.NewLogs()
+		for _, attrs := range []map[string]string{k8sLabels, excluded} {
+			rl := ld.ResourceLogs().AppendEmpty()
+			for k, v := range attrs {
+				rl.Resource().Attributes().UpsertString(k, v)
+			}
+			rl.InstrumentationLibraryLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStringVal("foo")
+		}
+
+		ld, err := rtp.ProcessLogs(context.Background(), ld)
+		require.NoError(t, err)
+		require.Equal(t, 1, ld.ResourceLogs().Len())
+		assert.Equal(t, 1, ld.LogRecordCount())
+	})
+}
+
 func TestTraceSourceIncludePrecedence(t *testing.T) {
 	test := newTraceDataWithSpans(limitedLabels, k8sLabels)
 	test.ResourceSpans().At(0).Resource().Attributes().UpsertString("pod_annotation_sumologic.com/include", "true")
@@ -459,6 +512,66 @@ func TestSourceCategoryAnnotations(t *testing.T) {
 	})
 }
 
+func TestDeleteAnnotationAttributes(t *testing.T) {
+	inputAttributes := createK8sLabels()
+	inputAttributes["pod_annotation_sumologic.com/sourceHost"] = "sh:%{k8s.pod.uid}"
+	inputAttributes["pod_annotation_sumologic.com/include"] = "true"
+	inputAttributes["pod_annotation_custom.io/container-1.sourceCategory"] = "container-sc"
+	inputAttributes["pod_annotation_other.io/owner"] = "team"
+
+	config := createConfig()
+	config.DeleteAnnotationAttributes = true
+	config.ContainerAnnotations.Enabled = true
+	config.ContainerAnnotations.Prefixes = []string{"custom.io/"}
+	sp := newSourceProcessor(config)
+
+	t.Run("traces", func(t *testing.T) {
+		processedTraces, err := sp.ProcessTraces(context.Background(), newTraceData(inputAttributes))
+		require.NoError(t, err)
+
+		processedAttributes := processedTraces.ResourceSpans().At(0).Resource().Attributes()
+		assertAttribute(t, processedAttributes, "_sourceHost", "sh:pod-1234")
+		assertAttribute(t, processedAttributes, "_sourceCategory", "container-sc")
+		assertAttribute(t, processedAttributes, "pod_annotation_other.io/owner", "team")
+		assertAttribute(t, processedAttributes, "pod_annotation_sumologic.com/sourceHost", "")
+		assertAttribute(t, processedAttributes, "pod_annotation_sumologic.com/include", "")
+		assertAttribute(t, processedAttributes, "pod_annotation_custom.io/container-1.sourceCategory", "")
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		md := pdata.NewMetrics()
+		attrs := md.ResourceMetrics().AppendEmpty().Resource().Attributes()
+		for k, v := range inputAttributes {
+			attrs.UpsertString(k, v)
+		}
+
+		processedMetrics, err := sp.ProcessMetrics(context.Background(), md)
+		require.NoError(t, err)
+
+		processedAttributes := processedMetrics.ResourceMetrics().At(0).Resource().Attributes()
+		assertAttribute(t, processedAttributes, "_sourceHost", "sh:pod-1234")
+		assertAttribute(t, processedAttributes, "pod_annotation_sumologic.com/sourceHost", "")
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		processedLogs, err := sp.ProcessLogs(context.Background(), newLogsDataWithLogs(inputAttributes, nil))
+		require.NoError(t, err)
+
+		processedAttributes := processedLogs.ResourceLogs().At(0).Resource().Attributes()
+		assertAttribute(t, processedAttributes, "_sourceHost", "sh:pod-1234")
+		assertAttribute(t, processedAttributes, "pod_annotation_sumologic.com/sourceHost", "")
+	})
+
+	t.Run("excluded by annotation", func(t *testing.T) {
+		excludedAttributes := createK8sLabels()
+		excludedAttributes["pod_annotation_sumologic.com/exclude"] = "true"
+
+		processedTraces, err := sp.ProcessTraces(context.Background(), newTraceData(excludedAttributes))
+		require.NoError(t, err)
+		assert.Equal(t, 0, processedTraces.ResourceSpans().Len())
+	})
+}
+
 func TestSourceCategoryTemplateWithCustomAttribute(t *testing.T) {
 	t.Run("attribute name is a single word", func(t *testing.T) {
 		inputAttributes := createK8sLabels()
@@ -607,3 +720,27 @@ func TestLogProcessorJson(t *testing.T) {
 		})
 	}
 }
+
+func TestSourceCategoryStrategy(t *testing.T) {
+	strategy := SourceCategoryStrategyFunc(func(attributes pdata.AttributeMap) (string, bool) {
+		return "from_strategy", true
+	})
+	factory := NewFactory(WithSourceCategoryStrategy("custom", strategy))
+
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SourceCategoryStrategy = "custom"
+	sink := new(consumertest.LogsSink)
+	proc, err := factory.CreateLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	logs := pdata.NewLogs()
+	logs.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().LogRecords().AppendEmpty()
+	require.NoError(t, proc.ConsumeLogs(context.Background(), logs))
+
+	require.Len(t, sink.AllLogs(), 1)
+	assertAttribute(t, sink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes(), "_sourceCategory", "kubernetes/from_strategy")
+
+	cfg.SourceCategoryStrategy = "unknown"
+	_, err = factory.CreateLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	assert.EqualError(t, err, "unknown source category strategy: unknown")
+}
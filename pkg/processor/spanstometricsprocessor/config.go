@@ -0,0 +1,99 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstometricsprocessor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the spans to metrics processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:"-"`
+
+	// Exporters are the IDs of the metrics exporters the metrics are sent to.
+	// The exporters have to be a part of a metrics pipeline.
+	Exporters []string `mapstructure:"exporters"`
+
+	// Interval defines how often the metrics are sent to the exporters.
+	// By default this is 60s.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// SpanKinds are the kinds of the spans the metrics are derived from,
+	// either unspecified, internal, server, client, producer or consumer.
+	// By default these are server and consumer, so only the spans of the requests
+	// handled by the services are counted.
+	SpanKinds []string `mapstructure:"span_kinds"`
+
+	// Dimensions are the span attributes, or the resource attributes if the span
+	// doesn't have the attribute, added to the metrics besides the service, operation and span kind.
+	Dimensions []string `mapstructure:"dimensions"`
+
+	// LatencyBuckets are the upper bounds of the duration histogram buckets in milliseconds.
+	// By default these are 2, 4, 6, 8, 10, 50, 100, 200, 400, 800, 1000, 1400, 2000, 5000, 10000 and 15000.
+	LatencyBuckets []float64 `mapstructure:"latency_buckets"`
+
+	// MaxSeries limits the number of the distinct dimension values per metric,
+	// the spans with the new values above the limit are not counted.
+	// By default this is 10000.
+	MaxSeries int `mapstructure:"max_series"`
+}
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	if len(cfg.Exporters) == 0 {
+		return errors.New("at least one exporter has to be configured")
+	}
+	for _, exporter := range cfg.Exporters {
+		if _, err := config.NewComponentIDFromString(exporter); err != nil {
+			return fmt.Errorf("invalid exporter: %w", err)
+		}
+	}
+	if cfg.Interval <= 0 {
+		return fmt.Errorf("interval has to be positive: %s", cfg.Interval)
+	}
+	for _, kind := range cfg.SpanKinds {
+		if _, ok := parseSpanKind(kind); !ok {
+			return fmt.Errorf("unexpected span kind: %s", kind)
+		}
+	}
+
+	dimensions := map[string]struct{}{
+		dimensionService:   {},
+		dimensionOperation: {},
+		dimensionSpanKind:  {},
+	}
+	for _, dimension := range cfg.Dimensions {
+		if dimension == "" {
+			return errors.New("dimension cannot be empty")
+		}
+		if _, ok := dimensions[dimension]; ok {
+			return fmt.Errorf("dimension %s is configured more than once", dimension)
+		}
+		dimensions[dimension] = struct{}{}
+	}
+
+	if !sort.Float64sAreSorted(cfg.LatencyBuckets) {
+		return errors.New("latency_buckets have to be sorted")
+	}
+	if cfg.MaxSeries <= 0 {
+		return fmt.Errorf("max_series has to be positive: %d", cfg.MaxSeries)
+	}
+	return nil
+}
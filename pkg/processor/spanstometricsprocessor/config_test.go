@@ -0,0 +1,127 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstometricsprocessor
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[factory.Type()] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Processors[config.NewComponentID(typeStr)])
+
+	assert.Equal(t,
+		&Config{
+			ProcessorSettings: config.NewProcessorSettings(config.NewComponentIDWithName(typeStr, "custom")),
+			Exporters:         []string{"nop"},
+			Interval:          30 * time.Second,
+			SpanKinds:         []string{"server"},
+			Dimensions:        []string{"http.status_code", "deployment.environment"},
+			LatencyBuckets:    []float64{10, 100, 1000},
+			MaxSeries:         500,
+		},
+		cfg.Processors[config.NewComponentIDWithName(typeStr, "custom")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		modify        func(cfg *Config)
+		expectedError string
+	}{
+		{
+			name:   "valid config",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:          "no exporters",
+			modify:        func(cfg *Config) { cfg.Exporters = nil },
+			expectedError: "at least one exporter has to be configured",
+		},
+		{
+			name:          "invalid exporter",
+			modify:        func(cfg *Config) { cfg.Exporters = []string{"/metrics"} },
+			expectedError: `invalid exporter: in "/metrics" id: the part before / should not be empty`,
+		},
+		{
+			name:          "non-positive interval",
+			modify:        func(cfg *Config) { cfg.Interval = -time.Second },
+			expectedError: "interval has to be positive: -1s",
+		},
+		{
+			name:          "unexpected span kind",
+			modify:        func(cfg *Config) { cfg.SpanKinds = []string{"server", "SPAN_KIND_CLIENT"} },
+			expectedError: "unexpected span kind: SPAN_KIND_CLIENT",
+		},
+		{
+			name:          "empty dimension",
+			modify:        func(cfg *Config) { cfg.Dimensions = []string{""} },
+			expectedError: "dimension cannot be empty",
+		},
+		{
+			name:          "duplicated dimension",
+			modify:        func(cfg *Config) { cfg.Dimensions = []string{"http.method", "http.method"} },
+			expectedError: "dimension http.method is configured more than once",
+		},
+		{
+			name:          "builtin dimension",
+			modify:        func(cfg *Config) { cfg.Dimensions = []string{"operation"} },
+			expectedError: "dimension operation is configured more than once",
+		},
+		{
+			name:          "unsorted latency buckets",
+			modify:        func(cfg *Config) { cfg.LatencyBuckets = []float64{100, 10} },
+			expectedError: "latency_buckets have to be sorted",
+		},
+		{
+			name:          "non-positive max_series",
+			modify:        func(cfg *Config) { cfg.MaxSeries = 0 },
+			expectedError: "max_series has to be positive: 0",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Exporters = []string{"sumologic"}
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstometricsprocessor
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "spans_to_metrics"
+
+	defaultInterval  = 60 * time.Second
+	defaultMaxSeries = 10000
+)
+
+// The defaults of the slices are applied by the processor, as the slices set by createDefaultConfig
+// would be merged with the configured ones when the configuration is unmarshalled.
+var (
+	defaultSpanKinds = []string{"server", "consumer"}
+	// defaultLatencyBuckets are the duration histogram buckets in milliseconds
+	defaultLatencyBuckets = []float64{2, 4, 6, 8, 10, 50, 100, 200, 400, 800, 1000, 1400, 2000, 5000, 10000, 15000}
+)
+
+var processorCapabilities = consumer.Capabilities{MutatesData: false}
+
+// NewFactory returns a new factory for the spans to metrics processor.
+func NewFactory() component.ProcessorFactory {
+	return component.NewProcessorFactory(
+		typeStr,
+		createDefaultConfig,
+		component.WithTracesProcessor(createTracesProcessor),
+	)
+}
+
+func createDefaultConfig() config.Processor {
+	return &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		Interval:          defaultInterval,
+		MaxSeries:         defaultMaxSeries,
+	}
+}
+
+func createTracesProcessor(
+	_ context.Context,
+	params component.ProcessorCreateSettings,
+	cfg config.Processor,
+	nextConsumer consumer.Traces,
+) (component.TracesProcessor, error) {
+	stmp, err := newSpansToMetricsProcessor(cfg.(*Config), params.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return processorhelper.NewTracesProcessor(
+		cfg,
+		nextConsumer,
+		stmp.ProcessTraces,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(stmp.Start),
+		processorhelper.WithShutdown(stmp.Shutdown))
+}
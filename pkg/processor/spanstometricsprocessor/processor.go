@@ -0,0 +1,375 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstometricsprocessor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+const (
+	// The metric names and the dimensions follow the Sumo Logic conventions,
+	// e.g. service.name is translated to service
+	metricRequests = "traces.service.requests"
+	metricErrors   = "traces.service.errors"
+	metricDuration = "traces.service.duration"
+
+	dimensionService   = "service"
+	dimensionOperation = "operation"
+	dimensionSpanKind  = "span_kind"
+
+	attributeServiceName = "service.name"
+	unknownService       = "unknown_service"
+)
+
+// spanKinds maps the span kind to its name used in the configuration and the span_kind dimension
+var spanKinds = map[pdata.SpanKind]string{
+	pdata.SpanKindUnspecified: "unspecified",
+	pdata.SpanKindInternal:    "internal",
+	pdata.SpanKindServer:      "server",
+	pdata.SpanKindClient:      "client",
+	pdata.SpanKindProducer:    "producer",
+	pdata.SpanKindConsumer:    "consumer",
+}
+
+// parseSpanKind returns the span kind for its name
+func parseSpanKind(name string) (pdata.SpanKind, bool) {
+	for kind, kindName := range spanKinds {
+		if kindName == name {
+			return kind, true
+		}
+	}
+	return pdata.SpanKindUnspecified, false
+}
+
+type spansToMetricsProcessor struct {
+	logger      *zap.Logger
+	exporterIDs []config.ComponentID
+	interval    time.Duration
+	spanKinds   map[pdata.SpanKind]struct{}
+	dimensions  []string
+	buckets     []float64
+	maxSeries   int
+	exporters   []consumer.Metrics
+
+	// lock guards series and droppedSpans
+	lock sync.Mutex
+	// series are the aggregated values per the dimension values
+	series map[string]*series
+	// droppedSpans is the number of the spans not counted since the last metrics were sent,
+	// because of max_series
+	droppedSpans int
+	startTime    pdata.Timestamp
+	// now returns the current time, it's overridden in tests
+	now func() time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// series is the aggregated values of the spans with the same dimension values
+type series struct {
+	dimensions   map[string]string
+	requests     uint64
+	errors       uint64
+	durationSum  float64
+	bucketCounts []uint64
+}
+
+func newSpansToMetricsProcessor(cfg *Config, logger *zap.Logger) (*spansToMetricsProcessor, error) {
+	exporterIDs := make([]config.ComponentID, 0, len(cfg.Exporters))
+	for _, exporter := range cfg.Exporters {
+		id, err := config.NewComponentIDFromString(exporter)
+		if err != nil {
+			return nil, err
+		}
+		exporterIDs = append(exporterIDs, id)
+	}
+
+	kindNames := cfg.SpanKinds
+	if len(kindNames) == 0 {
+		kindNames = defaultSpanKinds
+	}
+	kinds := make(map[pdata.SpanKind]struct{}, len(kindNames))
+	for _, name := range kindNames {
+		kind, ok := parseSpanKind(name)
+		if !ok {
+			return nil, fmt.Errorf("unexpected span kind: %s", name)
+		}
+		kinds[kind] = struct{}{}
+	}
+
+	buckets := cfg.LatencyBuckets
+	if len(buckets) == 0 {
+		buckets = defaultLatencyBuckets
+	}
+
+	return &spansToMetricsProcessor{
+		logger:      logger,
+		exporterIDs: exporterIDs,
+		interval:    cfg.Interval,
+		spanKinds:   kinds,
+		dimensions:  cfg.Dimensions,
+		buckets:     buckets,
+		maxSeries:   cfg.MaxSeries,
+		series:      make(map[string]*series),
+		now:         time.Now,
+	}, nil
+}
+
+// Start looks up the metrics exporters and starts sending the metrics periodically
+func (stmp *spansToMetricsProcessor) Start(_ context.Context, host component.Host) error {
+	exporters := host.GetExporters()[config.MetricsDataType]
+	for _, id := range stmp.exporterIDs {
+		exporter, ok := exporters[id]
+		if !ok {
+			return fmt.Errorf("metrics exporter %s not found", id)
+		}
+		me, ok := exporter.(component.MetricsExporter)
+		if !ok {
+			return fmt.Errorf("exporter %s is not a metrics exporter", id)
+		}
+		stmp.exporters = append(stmp.exporters, me)
+	}
+	stmp.startTime = pdata.NewTimestampFromTime(stmp.now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stmp.cancel = cancel
+	stmp.wg.Add(1)
+	go stmp.run(ctx)
+	return nil
+}
+
+// Shutdown stops sending the metrics periodically and sends them for the last time
+func (stmp *spansToMetricsProcessor) Shutdown(ctx context.Context) error {
+	if stmp.cancel == nil {
+		return nil
+	}
+	stmp.cancel()
+	stmp.wg.Wait()
+
+	stmp.sendMetrics(ctx)
+	return nil
+}
+
+func (stmp *spansToMetricsProcessor) run(ctx context.Context) {
+	defer stmp.wg.Done()
+
+	ticker := time.NewTicker(stmp.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stmp.sendMetrics(ctx)
+		}
+	}
+}
+
+// ProcessTraces updates the metrics with the spans of the configured kinds, the traces are passed unchanged
+func (stmp *spansToMetricsProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	stmp.lock.Lock()
+	defer stmp.lock.Unlock()
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resourceAttributes := rs.Resource().Attributes()
+		service := unknownService
+		if v, ok := resourceAttributes.Get(attributeServiceName); ok && v.AsString() != "" {
+			service = v.AsString()
+		}
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if _, ok := stmp.spanKinds[span.Kind()]; ok {
+					stmp.record(service, resourceAttributes, span)
+				}
+			}
+		}
+	}
+	return td, nil
+}
+
+// record updates the series of the span's dimension values, it has to be called with lock held
+func (stmp *spansToMetricsProcessor) record(service string, resourceAttributes pdata.AttributeMap, span pdata.Span) {
+	dimensions := map[string]string{
+		dimensionService:   service,
+		dimensionOperation: span.Name(),
+		dimensionSpanKind:  spanKinds[span.Kind()],
+	}
+	var key strings.Builder
+	key.WriteString(service)
+	key.WriteByte(0)
+	key.WriteString(span.Name())
+	key.WriteByte(0)
+	key.WriteString(dimensions[dimensionSpanKind])
+	for _, dimension := range stmp.dimensions {
+		key.WriteByte(0)
+		if v, ok := lookupAttribute(resourceAttributes, span.Attributes(), dimension); ok {
+			dimensions[dimension] = v
+			key.WriteString(v)
+		}
+	}
+
+	s, ok := stmp.series[key.String()]
+	if !ok {
+		if len(stmp.series) >= stmp.maxSeries {
+			stmp.droppedSpans++
+			return
+		}
+		s = &series{
+			dimensions:   dimensions,
+			bucketCounts: make([]uint64, len(stmp.buckets)+1),
+		}
+		stmp.series[key.String()] = s
+	}
+
+	duration := float64(span.EndTimestamp()-span.StartTimestamp()) / float64(time.Millisecond)
+	if span.EndTimestamp() < span.StartTimestamp() {
+		duration = 0
+	}
+	s.requests++
+	if span.Status().Code() == pdata.StatusCodeError {
+		s.errors++
+	}
+	s.durationSum += duration
+	s.bucketCounts[sort.SearchFloat64s(stmp.buckets, duration)]++
+}
+
+// lookupAttribute returns the value of the span attribute,
+// or the resource attribute if the span doesn't have it
+func lookupAttribute(resourceAttributes pdata.AttributeMap, spanAttributes pdata.AttributeMap, key string) (string, bool) {
+	if value, ok := spanAttributes.Get(key); ok {
+		return value.AsString(), true
+	}
+	if value, ok := resourceAttributes.Get(key); ok {
+		return value.AsString(), true
+	}
+	return "", false
+}
+
+// sendMetrics sends the current values of the metrics to the exporters
+func (stmp *spansToMetricsProcessor) sendMetrics(ctx context.Context) {
+	md := stmp.buildMetrics()
+	if md.MetricCount() == 0 {
+		return
+	}
+
+	for i, exporter := range stmp.exporters {
+		data := md
+		if i < len(stmp.exporters)-1 {
+			data = md.Clone()
+		}
+		if err := exporter.ConsumeMetrics(ctx, data); err != nil {
+			stmp.logger.Error("Failed to send the metrics derived from spans", zap.Error(err))
+		}
+	}
+}
+
+// buildMetrics converts the series to the cumulative OTLP metrics
+func (stmp *spansToMetricsProcessor) buildMetrics() pdata.Metrics {
+	stmp.lock.Lock()
+	defer stmp.lock.Unlock()
+
+	md := pdata.NewMetrics()
+	if stmp.droppedSpans > 0 {
+		stmp.logger.Warn("The spans with new dimension values were not counted, max_series is reached",
+			zap.Int("max_series", stmp.maxSeries),
+			zap.Int("spans", stmp.droppedSpans),
+		)
+		stmp.droppedSpans = 0
+	}
+	if len(stmp.series) == 0 {
+		return md
+	}
+
+	ilm := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(typeStr)
+	now := pdata.NewTimestampFromTime(stmp.now())
+
+	keys := make([]string, 0, len(stmp.series))
+	for key := range stmp.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	requests := newSum(ilm.Metrics().AppendEmpty(), metricRequests, "The number of the requests")
+	errs := newSum(ilm.Metrics().AppendEmpty(), metricErrors, "The number of the requests which failed")
+	duration := ilm.Metrics().AppendEmpty()
+	duration.SetName(metricDuration)
+	duration.SetDescription("The duration of the requests")
+	duration.SetUnit("ms")
+	duration.SetDataType(pdata.MetricDataTypeHistogram)
+	duration.Histogram().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+
+	for _, key := range keys {
+		s := stmp.series[key]
+		for _, count := range []struct {
+			dps   pdata.NumberDataPointSlice
+			value uint64
+		}{
+			{dps: requests, value: s.requests},
+			{dps: errs, value: s.errors},
+		} {
+			dp := count.dps.AppendEmpty()
+			dp.SetStartTimestamp(stmp.startTime)
+			dp.SetTimestamp(now)
+			insertDimensions(dp.Attributes(), s.dimensions)
+			dp.SetIntVal(int64(count.value))
+		}
+
+		dp := duration.Histogram().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(stmp.startTime)
+		dp.SetTimestamp(now)
+		insertDimensions(dp.Attributes(), s.dimensions)
+		dp.SetCount(s.requests)
+		dp.SetSum(s.durationSum)
+		dp.SetBucketCounts(append([]uint64(nil), s.bucketCounts...))
+		dp.SetExplicitBounds(append([]float64(nil), stmp.buckets...))
+	}
+	return md
+}
+
+// newSum initializes the monotonic cumulative sum metric and returns its data points
+func newSum(metric pdata.Metric, name string, description string) pdata.NumberDataPointSlice {
+	metric.SetName(name)
+	metric.SetDescription(description)
+	metric.SetUnit("1")
+	metric.SetDataType(pdata.MetricDataTypeSum)
+	metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	metric.Sum().SetIsMonotonic(true)
+	return metric.Sum().DataPoints()
+}
+
+func insertDimensions(attrs pdata.AttributeMap, dimensions map[string]string) {
+	for key, value := range dimensions {
+		attrs.InsertString(key, value)
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstometricsprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+var (
+	startTime = time.Date(2022, time.March, 1, 10, 0, 0, 0, time.UTC)
+	flushTime = startTime.Add(time.Minute)
+)
+
+// sinkExporter is the metrics exporter keeping the metrics in memory
+type sinkExporter struct {
+	component.Component
+	*consumertest.MetricsSink
+}
+
+type exportersHost struct {
+	component.Host
+	exporters map[config.DataType]map[config.ComponentID]component.Exporter
+}
+
+func (h exportersHost) GetExporters() map[config.DataType]map[config.ComponentID]component.Exporter {
+	return h.exporters
+}
+
+func newTestProcessor(t *testing.T, modify func(cfg *Config)) *spansToMetricsProcessor {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Exporters = []string{"sumologic"}
+	cfg.LatencyBuckets = []float64{10, 100}
+	modify(cfg)
+	require.NoError(t, cfg.Validate())
+
+	stmp, err := newSpansToMetricsProcessor(cfg, zap.NewNop())
+	require.NoError(t, err)
+	stmp.now = func() time.Time { return flushTime }
+	stmp.startTime = pdata.NewTimestampFromTime(startTime)
+	return stmp
+}
+
+type testSpan struct {
+	name     string
+	kind     pdata.SpanKind
+	duration time.Duration
+	failed   bool
+	status   string
+}
+
+// newTraces creates the traces with a single resource of the service and the spans
+func newTraces(service string, spans ...testSpan) pdata.Traces {
+	traces := pdata.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	if service != "" {
+		rs.Resource().Attributes().InsertString("service.name", service)
+	}
+	rs.Resource().Attributes().InsertString("deployment.environment", "prod")
+	dest := rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+	for _, s := range spans {
+		span := dest.AppendEmpty()
+		span.SetName(s.name)
+		span.SetKind(s.kind)
+		span.SetStartTimestamp(pdata.NewTimestampFromTime(startTime))
+		span.SetEndTimestamp(pdata.NewTimestampFromTime(startTime.Add(s.duration)))
+		if s.failed {
+			span.Status().SetCode(pdata.StatusCodeError)
+		}
+		if s.status != "" {
+			span.Attributes().InsertString("http.status_code", s.status)
+		}
+	}
+	return traces
+}
+
+// getMetric returns the metric with the name
+func getMetric(t *testing.T, md pdata.Metrics, name string) pdata.Metric {
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		if metrics.At(i).Name() == name {
+			return metrics.At(i)
+		}
+	}
+	require.Failf(t, "metric not found", "metric %s not found", name)
+	return pdata.Metric{}
+}
+
+func dimensions(attrs pdata.AttributeMap) map[string]string {
+	result := make(map[string]string)
+	attrs.Range(func(k string, v pdata.AttributeValue) bool {
+		result[k] = v.StringVal()
+		return true
+	})
+	return result
+}
+
+func TestProcessTraces(t *testing.T) {
+	stmp := newTestProcessor(t, func(cfg *Config) {})
+
+	traces := newTraces("checkout",
+		testSpan{name: "GET /cart", kind: pdata.SpanKindServer, duration: 5 * time.Millisecond},
+		testSpan{name: "GET /cart", kind: pdata.SpanKindServer, duration: 50 * time.Millisecond, failed: true},
+		testSpan{name: "GET /cart", kind: pdata.SpanKindServer, duration: time.Second},
+		testSpan{name: "SELECT", kind: pdata.SpanKindClient, duration: time.Millisecond},
+		testSpan{name: "orders process", kind: pdata.SpanKindConsumer, duration: 20 * time.Millisecond},
+	)
+	result, err := stmp.ProcessTraces(context.Background(), traces)
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.SpanCount())
+	_, err = stmp.ProcessTraces(context.Background(), newTraces("", testSpan{name: "GET /", kind: pdata.SpanKindServer}))
+	require.NoError(t, err)
+
+	md := stmp.buildMetrics()
+	require.Equal(t, 3, md.MetricCount())
+
+	requests := getMetric(t, md, metricRequests)
+	assert.True(t, requests.Sum().IsMonotonic())
+	assert.Equal(t, pdata.MetricAggregationTemporalityCumulative, requests.Sum().AggregationTemporality())
+	require.Equal(t, 3, requests.Sum().DataPoints().Len())
+	errs := getMetric(t, md, metricErrors)
+	require.Equal(t, 3, errs.Sum().DataPoints().Len())
+	duration := getMetric(t, md, metricDuration)
+	assert.Equal(t, "ms", duration.Unit())
+	require.Equal(t, 3, duration.Histogram().DataPoints().Len())
+
+	expected := []struct {
+		dimensions   map[string]string
+		requests     int64
+		errors       int64
+		durationSum  float64
+		bucketCounts []uint64
+	}{
+		{
+			dimensions:   map[string]string{"service": "checkout", "operation": "GET /cart", "span_kind": "server"},
+			requests:     3,
+			errors:       1,
+			durationSum:  1055,
+			bucketCounts: []uint64{1, 1, 1},
+		},
+		{
+			dimensions:   map[string]string{"service": "checkout", "operation": "orders process", "span_kind": "consumer"},
+			requests:     1,
+			durationSum:  20,
+			bucketCounts: []uint64{0, 1, 0},
+		},
+		{
+			dimensions:   map[string]string{"service": "unknown_service", "operation": "GET /", "span_kind": "server"},
+			requests:     1,
+			bucketCounts: []uint64{1, 0, 0},
+		},
+	}
+	for i, e := range expected {
+		dp := requests.Sum().DataPoints().At(i)
+		assert.Equal(t, e.dimensions, dimensions(dp.Attributes()))
+		assert.Equal(t, e.requests, dp.IntVal())
+		assert.Equal(t, pdata.NewTimestampFromTime(startTime), dp.StartTimestamp())
+		assert.Equal(t, pdata.NewTimestampFromTime(flushTime), dp.Timestamp())
+
+		dp = errs.Sum().DataPoints().At(i)
+		assert.Equal(t, e.dimensions, dimensions(dp.Attributes()))
+		assert.Equal(t, e.errors, dp.IntVal())
+
+		hdp := duration.Histogram().DataPoints().At(i)
+		assert.Equal(t, e.dimensions, dimensions(hdp.Attributes()))
+		assert.Equal(t, uint64(e.requests), hdp.Count())
+		assert.Equal(t, e.durationSum, hdp.Sum())
+		assert.Equal(t, []float64{10, 100}, hdp.ExplicitBounds())
+		assert.Equal(t, e.bucketCounts, hdp.BucketCounts())
+	}
+}
+
+func TestProcessTracesDimensions(t *testing.T) {
+	stmp := newTestProcessor(t, func(cfg *Config) {
+		cfg.SpanKinds = []string{"client"}
+		cfg.Dimensions = []string{"http.status_code", "deployment.environment", "missing"}
+	})
+
+	_, err := stmp.ProcessTraces(context.Background(), newTraces("frontend",
+		testSpan{name: "GET", kind: pdata.SpanKindClient, status: "200"},
+		testSpan{name: "GET", kind: pdata.SpanKindClient, status: "500"},
+		testSpan{name: "GET", kind: pdata.SpanKindClient, status: "200"},
+		testSpan{name: "GET /", kind: pdata.SpanKindServer, status: "200"},
+	))
+	require.NoError(t, err)
+
+	dps := getMetric(t, stmp.buildMetrics(), metricRequests).Sum().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.Equal(t, map[string]string{
+		"service":                "frontend",
+		"operation":              "GET",
+		"span_kind":              "client",
+		"http.status_code":       "200",
+		"deployment.environment": "prod",
+	}, dimensions(dps.At(0).Attributes()))
+	assert.Equal(t, int64(2), dps.At(0).IntVal())
+	assert.Equal(t, int64(1), dps.At(1).IntVal())
+}
+
+func TestProcessTracesMaxSeries(t *testing.T) {
+	stmp := newTestProcessor(t, func(cfg *Config) { cfg.MaxSeries = 2 })
+
+	_, err := stmp.ProcessTraces(context.Background(), newTraces("checkout",
+		testSpan{name: "first", kind: pdata.SpanKindServer},
+		testSpan{name: "second", kind: pdata.SpanKindServer},
+		testSpan{name: "third", kind: pdata.SpanKindServer},
+		testSpan{name: "first", kind: pdata.SpanKindServer},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, 1, stmp.droppedSpans)
+
+	dps := getMetric(t, stmp.buildMetrics(), metricRequests).Sum().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.Equal(t, int64(2), dps.At(0).IntVal())
+	assert.Equal(t, 0, stmp.droppedSpans)
+}
+
+func TestStartSendsMetricsToExporters(t *testing.T) {
+	stmp := newTestProcessor(t, func(cfg *Config) {})
+	sink := new(consumertest.MetricsSink)
+	host := exportersHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.MetricsDataType: {
+				config.NewComponentID("sumologic"): sinkExporter{MetricsSink: sink},
+			},
+		},
+	}
+
+	require.NoError(t, stmp.Start(context.Background(), host))
+	_, err := stmp.ProcessTraces(context.Background(), newTraces("checkout", testSpan{name: "GET /", kind: pdata.SpanKindServer}))
+	require.NoError(t, err)
+	require.NoError(t, stmp.Shutdown(context.Background()))
+
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, 3, sink.AllMetrics()[0].MetricCount())
+}
+
+func TestStartMissingExporter(t *testing.T) {
+	stmp := newTestProcessor(t, func(cfg *Config) {})
+
+	host := exportersHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.TracesDataType: {
+				config.NewComponentID("sumologic"): sinkExporter{MetricsSink: new(consumertest.MetricsSink)},
+			},
+		},
+	}
+	assert.EqualError(t, stmp.Start(context.Background(), host), "metrics exporter sumologic not found")
+}
+
+func TestDefaultSpanKindsAndBuckets(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Exporters = []string{"sumologic"}
+	stmp, err := newSpansToMetricsProcessor(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[pdata.SpanKind]struct{}{
+		pdata.SpanKindServer:   {},
+		pdata.SpanKindConsumer: {},
+	}, stmp.spanKinds)
+	assert.Equal(t, defaultLatencyBuckets, stmp.buckets)
+}
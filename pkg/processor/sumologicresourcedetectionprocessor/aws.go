@@ -0,0 +1,236 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicresourcedetectionprocessor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	ec2DetectorName = "ec2"
+	ecsDetectorName = "ecs"
+	eksDetectorName = "eks"
+
+	// defaultEC2Endpoint is the address of the EC2 instance metadata service
+	defaultEC2Endpoint = "http://169.254.169.254"
+	// ec2TokenTTLSeconds is the lifetime of the IMDSv2 session token,
+	// which is only used while the detector runs
+	ec2TokenTTLSeconds = "60"
+
+	// ecsMetadataURIEnv is set by the ECS agent in the containers of the tasks
+	ecsMetadataURIEnv = "ECS_CONTAINER_METADATA_URI_V4"
+	// kubernetesServiceHostEnv is set by Kubernetes in the containers of the pods
+	kubernetesServiceHostEnv = "KUBERNETES_SERVICE_HOST"
+	// eksClusterNameTag is the tag of the EKS nodes with the name of the cluster,
+	// available in the metadata when the instance tags are allowed in it
+	eksClusterNameTag = "eks:cluster-name"
+)
+
+// ec2IdentityDocument is the instance identity document of the EC2 instance
+type ec2IdentityDocument struct {
+	AccountID        string `json:"accountId"`
+	AvailabilityZone string `json:"availabilityZone"`
+	Region           string `json:"region"`
+	InstanceID       string `json:"instanceId"`
+	InstanceType     string `json:"instanceType"`
+	ImageID          string `json:"imageId"`
+}
+
+// ec2Detector detects the attributes of the EC2 instance from the instance
+// metadata service, using the IMDSv2 session tokens.
+type ec2Detector struct {
+	client   *http.Client
+	endpoint string
+}
+
+func newEC2Detector(client *http.Client) *ec2Detector {
+	return &ec2Detector{
+		client:   client,
+		endpoint: defaultEC2Endpoint,
+	}
+}
+
+func (d *ec2Detector) token(ctx context.Context) (string, error) {
+	token, err := getMetadata(ctx, d.client, http.MethodPut, d.endpoint+"/latest/api/token",
+		map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": ec2TokenTTLSeconds},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the metadata token: %w", err)
+	}
+	return string(token), nil
+}
+
+func (d *ec2Detector) get(ctx context.Context, token string, path string) ([]byte, error) {
+	return getMetadata(ctx, d.client, http.MethodGet, d.endpoint+path,
+		map[string]string{"X-aws-ec2-metadata-token": token},
+	)
+}
+
+func (d *ec2Detector) identityDocument(ctx context.Context, token string) (ec2IdentityDocument, error) {
+	var doc ec2IdentityDocument
+
+	body, err := d.get(ctx, token, "/latest/dynamic/instance-identity/document")
+	if err != nil {
+		return doc, fmt.Errorf("failed to get the instance identity document: %w", err)
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return doc, fmt.Errorf("failed to parse the instance identity document: %w", err)
+	}
+	return doc, nil
+}
+
+func (d *ec2Detector) detect(ctx context.Context) (map[string]string, error) {
+	token, err := d.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := d.identityDocument(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, err := d.get(ctx, token, "/latest/meta-data/hostname")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the hostname: %w", err)
+	}
+
+	attributes := map[string]string{
+		"cloud.provider": "aws",
+		"cloud.platform": "aws_ec2",
+	}
+	setIfNotEmpty(attributes, "cloud.account.id", doc.AccountID)
+	setIfNotEmpty(attributes, "cloud.region", doc.Region)
+	setIfNotEmpty(attributes, "cloud.availability_zone", doc.AvailabilityZone)
+	setIfNotEmpty(attributes, "host.id", doc.InstanceID)
+	setIfNotEmpty(attributes, "host.type", doc.InstanceType)
+	setIfNotEmpty(attributes, "host.image.id", doc.ImageID)
+	setIfNotEmpty(attributes, "host.name", string(hostname))
+	return attributes, nil
+}
+
+// eksDetector detects the attributes of the EKS cluster the collector runs in.
+// The collector is assumed to run in EKS when it runs in Kubernetes on an EC2 instance.
+type eksDetector struct {
+	ec2    *ec2Detector
+	getenv func(string) string
+}
+
+func newEKSDetector(ec2 *ec2Detector, getenv func(string) string) *eksDetector {
+	return &eksDetector{
+		ec2:    ec2,
+		getenv: getenv,
+	}
+}
+
+func (d *eksDetector) detect(ctx context.Context) (map[string]string, error) {
+	if d.getenv(kubernetesServiceHostEnv) == "" {
+		return nil, errNotDetected
+	}
+
+	token, err := d.ec2.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := d.ec2.identityDocument(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := map[string]string{
+		"cloud.provider": "aws",
+		"cloud.platform": "aws_eks",
+	}
+	setIfNotEmpty(attributes, "cloud.account.id", doc.AccountID)
+	setIfNotEmpty(attributes, "cloud.region", doc.Region)
+	setIfNotEmpty(attributes, "cloud.availability_zone", doc.AvailabilityZone)
+
+	// the tags are only available when they are allowed in the instance metadata,
+	// so the cluster name is optional
+	if cluster, err := d.ec2.get(ctx, token, "/latest/meta-data/tags/instance/"+eksClusterNameTag); err == nil {
+		setIfNotEmpty(attributes, "k8s.cluster.name", string(cluster))
+	}
+	return attributes, nil
+}
+
+// ecsTaskMetadata is the part of the ECS task metadata used by the detector
+type ecsTaskMetadata struct {
+	Cluster          string `json:"Cluster"`
+	TaskARN          string `json:"TaskARN"`
+	Family           string `json:"Family"`
+	Revision         string `json:"Revision"`
+	AvailabilityZone string `json:"AvailabilityZone"`
+	LaunchType       string `json:"LaunchType"`
+}
+
+// ecsDetector detects the attributes of the ECS task the collector runs in
+// from the task metadata endpoint v4.
+type ecsDetector struct {
+	client *http.Client
+	getenv func(string) string
+}
+
+func newECSDetector(client *http.Client, getenv func(string) string) *ecsDetector {
+	return &ecsDetector{
+		client: client,
+		getenv: getenv,
+	}
+}
+
+func (d *ecsDetector) detect(ctx context.Context) (map[string]string, error) {
+	endpoint := d.getenv(ecsMetadataURIEnv)
+	if endpoint == "" {
+		return nil, errNotDetected
+	}
+
+	body, err := getMetadata(ctx, d.client, http.MethodGet, endpoint+"/task", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the task metadata: %w", err)
+	}
+
+	var task ecsTaskMetadata
+	if err := json.Unmarshal(body, &task); err != nil {
+		return nil, fmt.Errorf("failed to parse the task metadata: %w", err)
+	}
+
+	attributes := map[string]string{
+		"cloud.provider": "aws",
+		"cloud.platform": "aws_ecs",
+	}
+	setIfNotEmpty(attributes, "aws.ecs.task.arn", task.TaskARN)
+	setIfNotEmpty(attributes, "aws.ecs.task.family", task.Family)
+	setIfNotEmpty(attributes, "aws.ecs.task.revision", task.Revision)
+	setIfNotEmpty(attributes, "aws.ecs.launchtype", strings.ToLower(task.LaunchType))
+	setIfNotEmpty(attributes, "cloud.availability_zone", task.AvailabilityZone)
+
+	// the task ARN has the form of arn:aws:ecs:<region>:<account>:task/<cluster>/<id>
+	if arn := strings.Split(task.TaskARN, ":"); len(arn) == 6 {
+		setIfNotEmpty(attributes, "cloud.region", arn[3])
+		setIfNotEmpty(attributes, "cloud.account.id", arn[4])
+
+		// the cluster is either its ARN or its name, depending on the agent's version
+		if strings.HasPrefix(task.Cluster, "arn:") {
+			attributes["aws.ecs.cluster.arn"] = task.Cluster
+		} else if task.Cluster != "" {
+			attributes["aws.ecs.cluster.arn"] = strings.Join(arn[:5], ":") + ":cluster/" + task.Cluster
+		}
+	}
+	return attributes, nil
+}
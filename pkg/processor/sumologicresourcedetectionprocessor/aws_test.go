@@ -0,0 +1,243 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicresourcedetectionprocessor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testEC2Token            = "test-token"
+	testEC2IdentityDocument = `{
+  "accountId": "123456789012",
+  "availabilityZone": "us-west-2b",
+  "region": "us-west-2",
+  "instanceId": "i-0123456789abcdef0",
+  "instanceType": "m5.large",
+  "imageId": "ami-0abcdef1234567890"
+}`
+)
+
+// newTestEC2Server returns the server responding like the EC2 instance metadata service
+func newTestEC2Server(t *testing.T, tags map[string]string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/latest/api/token", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPut || req.Header.Get("X-aws-ec2-metadata-token-ttl-seconds") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(testEC2Token))
+	})
+
+	withToken := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("X-aws-ec2-metadata-token") != testEC2Token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}
+	}
+	mux.HandleFunc("/latest/dynamic/instance-identity/document", withToken(testEC2IdentityDocument))
+	mux.HandleFunc("/latest/meta-data/hostname", withToken("ip-10-0-0-1.us-west-2.compute.internal"))
+	for k, v := range tags {
+		mux.HandleFunc("/latest/meta-data/tags/instance/"+k, withToken(v))
+	}
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestEC2Detector(t *testing.T) {
+	srv := newTestEC2Server(t, nil)
+
+	d := newEC2Detector(srv.Client())
+	d.endpoint = srv.URL
+
+	attributes, err := d.detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"cloud.provider":          "aws",
+		"cloud.platform":          "aws_ec2",
+		"cloud.account.id":        "123456789012",
+		"cloud.region":            "us-west-2",
+		"cloud.availability_zone": "us-west-2b",
+		"host.id":                 "i-0123456789abcdef0",
+		"host.type":               "m5.large",
+		"host.image.id":           "ami-0abcdef1234567890",
+		"host.name":               "ip-10-0-0-1.us-west-2.compute.internal",
+	}, attributes)
+}
+
+func TestEC2DetectorNoMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+
+	d := newEC2Detector(srv.Client())
+	d.endpoint = srv.URL
+
+	_, err := d.detect(context.Background())
+	assert.EqualError(t, err,
+		"failed to get the metadata token: unexpected status 404 Not Found from "+srv.URL+"/latest/api/token",
+	)
+}
+
+func TestEKSDetector(t *testing.T) {
+	testcases := []struct {
+		name     string
+		env      map[string]string
+		tags     map[string]string
+		expected map[string]string
+		err      error
+	}{
+		{
+			name: "with cluster name tag",
+			env:  map[string]string{"KUBERNETES_SERVICE_HOST": "10.100.0.1"},
+			tags: map[string]string{"eks:cluster-name": "prod"},
+			expected: map[string]string{
+				"cloud.provider":          "aws",
+				"cloud.platform":          "aws_eks",
+				"cloud.account.id":        "123456789012",
+				"cloud.region":            "us-west-2",
+				"cloud.availability_zone": "us-west-2b",
+				"k8s.cluster.name":        "prod",
+			},
+		},
+		{
+			name: "without tags in metadata",
+			env:  map[string]string{"KUBERNETES_SERVICE_HOST": "10.100.0.1"},
+			expected: map[string]string{
+				"cloud.provider":          "aws",
+				"cloud.platform":          "aws_eks",
+				"cloud.account.id":        "123456789012",
+				"cloud.region":            "us-west-2",
+				"cloud.availability_zone": "us-west-2b",
+			},
+		},
+		{
+			name: "not in kubernetes",
+			err:  errNotDetected,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestEC2Server(t, tc.tags)
+
+			ec2 := newEC2Detector(srv.Client())
+			ec2.endpoint = srv.URL
+			d := newEKSDetector(ec2, func(k string) string { return tc.env[k] })
+
+			attributes, err := d.detect(context.Background())
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, attributes)
+		})
+	}
+}
+
+func TestECSDetector(t *testing.T) {
+	testcases := []struct {
+		name     string
+		task     string
+		expected map[string]string
+	}{
+		{
+			name: "cluster arn",
+			task: `{
+  "Cluster": "arn:aws:ecs:us-west-2:123456789012:cluster/default",
+  "TaskARN": "arn:aws:ecs:us-west-2:123456789012:task/default/158d1c8083dd49d6b527399fd6414f5c",
+  "Family": "curltest",
+  "Revision": "26",
+  "AvailabilityZone": "us-west-2d",
+  "LaunchType": "FARGATE"
+}`,
+			expected: map[string]string{
+				"cloud.provider":          "aws",
+				"cloud.platform":          "aws_ecs",
+				"cloud.account.id":        "123456789012",
+				"cloud.region":            "us-west-2",
+				"cloud.availability_zone": "us-west-2d",
+				"aws.ecs.cluster.arn":     "arn:aws:ecs:us-west-2:123456789012:cluster/default",
+				"aws.ecs.task.arn":        "arn:aws:ecs:us-west-2:123456789012:task/default/158d1c8083dd49d6b527399fd6414f5c",
+				"aws.ecs.task.family":     "curltest",
+				"aws.ecs.task.revision":   "26",
+				"aws.ecs.launchtype":      "fargate",
+			},
+		},
+		{
+			name: "cluster name",
+			task: `{
+  "Cluster": "default",
+  "TaskARN": "arn:aws:ecs:eu-central-1:123456789012:task/default/158d1c8083dd49d6b527399fd6414f5c",
+  "Family": "curltest",
+  "Revision": "3",
+  "LaunchType": "EC2"
+}`,
+			expected: map[string]string{
+				"cloud.provider":        "aws",
+				"cloud.platform":        "aws_ecs",
+				"cloud.account.id":      "123456789012",
+				"cloud.region":          "eu-central-1",
+				"aws.ecs.cluster.arn":   "arn:aws:ecs:eu-central-1:123456789012:cluster/default",
+				"aws.ecs.task.arn":      "arn:aws:ecs:eu-central-1:123456789012:task/default/158d1c8083dd49d6b527399fd6414f5c",
+				"aws.ecs.task.family":   "curltest",
+				"aws.ecs.task.revision": "3",
+				"aws.ecs.launchtype":    "ec2",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != "/v4/container/task" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(tc.task))
+			}))
+			t.Cleanup(srv.Close)
+
+			d := newECSDetector(srv.Client(), func(k string) string {
+				if k == ecsMetadataURIEnv {
+					return srv.URL + "/v4/container"
+				}
+				return ""
+			})
+
+			attributes, err := d.detect(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, attributes)
+		})
+	}
+}
+
+func TestECSDetectorNotInECS(t *testing.T) {
+	d := newECSDetector(http.DefaultClient, func(string) string { return "" })
+
+	_, err := d.detect(context.Background())
+	assert.ErrorIs(t, err, errNotDetected)
+}
@@ -0,0 +1,81 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicresourcedetectionprocessor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const (
+	azureDetectorName = "azure"
+
+	// defaultAzureEndpoint is the address of the Azure instance metadata service
+	defaultAzureEndpoint = "http://169.254.169.254"
+	azureComputePath     = "/metadata/instance/compute?api-version=2020-09-01&format=json"
+)
+
+// azureComputeMetadata is the part of the compute metadata used by the detector
+type azureComputeMetadata struct {
+	Location          string `json:"location"`
+	Name              string `json:"name"`
+	VMID              string `json:"vmId"`
+	VMSize            string `json:"vmSize"`
+	SubscriptionID    string `json:"subscriptionId"`
+	ResourceGroupName string `json:"resourceGroupName"`
+	Zone              string `json:"zone"`
+}
+
+// azureDetector detects the attributes of the Azure VM from the instance metadata service
+type azureDetector struct {
+	client   *http.Client
+	endpoint string
+}
+
+func newAzureDetector(client *http.Client) *azureDetector {
+	return &azureDetector{
+		client:   client,
+		endpoint: defaultAzureEndpoint,
+	}
+}
+
+func (d *azureDetector) detect(ctx context.Context) (map[string]string, error) {
+	body, err := getMetadata(ctx, d.client, http.MethodGet, d.endpoint+azureComputePath,
+		map[string]string{"Metadata": "true"},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the compute metadata: %w", err)
+	}
+
+	var compute azureComputeMetadata
+	if err := json.Unmarshal(body, &compute); err != nil {
+		return nil, fmt.Errorf("failed to parse the compute metadata: %w", err)
+	}
+
+	attributes := map[string]string{
+		"cloud.provider": "azure",
+		"cloud.platform": "azure_vm",
+	}
+	setIfNotEmpty(attributes, "cloud.account.id", compute.SubscriptionID)
+	setIfNotEmpty(attributes, "cloud.region", compute.Location)
+	setIfNotEmpty(attributes, "cloud.availability_zone", compute.Zone)
+	setIfNotEmpty(attributes, "host.id", compute.VMID)
+	setIfNotEmpty(attributes, "host.name", compute.Name)
+	setIfNotEmpty(attributes, "host.type", compute.VMSize)
+	setIfNotEmpty(attributes, "azure.resourcegroup.name", compute.ResourceGroupName)
+	return attributes, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicresourcedetectionprocessor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureDetector(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/metadata/instance/compute" || req.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+  "location": "westeurope",
+  "name": "examplevm",
+  "vmId": "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+  "vmSize": "Standard_A3",
+  "subscriptionId": "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx",
+  "resourceGroupName": "macikgo-test-may-23",
+  "zone": "1"
+}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	d := newAzureDetector(srv.Client())
+	d.endpoint = srv.URL
+
+	attributes, err := d.detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"cloud.provider":           "azure",
+		"cloud.platform":           "azure_vm",
+		"cloud.account.id":         "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx",
+		"cloud.region":             "westeurope",
+		"cloud.availability_zone":  "1",
+		"host.id":                  "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+		"host.name":                "examplevm",
+		"host.type":                "Standard_A3",
+		"azure.resourcegroup.name": "macikgo-test-may-23",
+	}, attributes)
+}
@@ -0,0 +1,62 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicresourcedetectionprocessor
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the Sumo Logic resource detection processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:"-"`
+
+	// Detectors is the list of the detectors which are run when the processor starts,
+	// any of host, ec2, ecs, eks, gce and azure. When multiple detectors detect
+	// the same attribute, the value detected by the earlier one is used.
+	// By default only the host detector is run.
+	Detectors []string `mapstructure:"detectors"`
+
+	// Timeout limits the time of running all the detectors.
+	// By default this is 5s.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Override defines whether the detected attributes replace the attributes
+	// already set on the resources.
+	// By default this is true.
+	Override bool `mapstructure:"override"`
+}
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	seen := make(map[string]struct{}, len(cfg.Detectors))
+	for _, name := range cfg.Detectors {
+		if _, ok := detectorFactories[name]; !ok {
+			return fmt.Errorf("unknown detector: %q", name)
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("detector %q is specified more than once", name)
+		}
+		seen[name] = struct{}{}
+	}
+
+	if cfg.Timeout <= 0 {
+		return fmt.Errorf("timeout has to be positive: %s", cfg.Timeout)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicresourcedetectionprocessor
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[factory.Type()] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Processors[config.NewComponentID(typeStr)])
+
+	assert.Equal(t,
+		&Config{
+			ProcessorSettings: config.NewProcessorSettings(config.NewComponentIDWithName(typeStr, "cloud")),
+			Detectors:         []string{"eks", "ec2", "host"},
+			Timeout:           2 * time.Second,
+			Override:          false,
+		},
+		cfg.Processors[config.NewComponentIDWithName(typeStr, "cloud")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name   string
+		modify func(cfg *Config)
+		errMsg string
+	}{
+		{
+			name:   "default",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name: "all detectors",
+			modify: func(cfg *Config) {
+				cfg.Detectors = []string{"host", "ec2", "ecs", "eks", "gce", "azure"}
+			},
+		},
+		{
+			name: "unknown detector",
+			modify: func(cfg *Config) {
+				cfg.Detectors = []string{"host", "openstack"}
+			},
+			errMsg: `unknown detector: "openstack"`,
+		},
+		{
+			name: "duplicated detector",
+			modify: func(cfg *Config) {
+				cfg.Detectors = []string{"ec2", "host", "ec2"}
+			},
+			errMsg: `detector "ec2" is specified more than once`,
+		},
+		{
+			name: "zero timeout",
+			modify: func(cfg *Config) {
+				cfg.Timeout = 0
+			},
+			errMsg: "timeout has to be positive: 0s",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tc.modify(cfg)
+
+			if tc.errMsg == "" {
+				assert.NoError(t, cfg.Validate())
+			} else {
+				assert.EqualError(t, cfg.Validate(), tc.errMsg)
+			}
+		})
+	}
+}
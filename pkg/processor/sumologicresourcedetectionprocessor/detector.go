@@ -0,0 +1,96 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicresourcedetectionprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// errNotDetected is returned by the detectors when the collector
+// doesn't run in the environment the detector is made for.
+var errNotDetected = errors.New("environment not detected")
+
+// detector detects the attributes of the environment the collector runs in.
+type detector interface {
+	// detect returns the detected resource attributes,
+	// named according to the OpenTelemetry conventions.
+	detect(ctx context.Context) (map[string]string, error)
+}
+
+// detectorFactories creates the detectors by their names used in the configuration
+var detectorFactories = map[string]func(client *http.Client) detector{
+	hostDetectorName: func(*http.Client) detector {
+		return newHostDetector()
+	},
+	ec2DetectorName: func(client *http.Client) detector {
+		return newEC2Detector(client)
+	},
+	ecsDetectorName: func(client *http.Client) detector {
+		return newECSDetector(client, os.Getenv)
+	},
+	eksDetectorName: func(client *http.Client) detector {
+		return newEKSDetector(newEC2Detector(client), os.Getenv)
+	},
+	gceDetectorName: func(client *http.Client) detector {
+		return newGCEDetector(client)
+	},
+	azureDetectorName: func(client *http.Client) detector {
+		return newAzureDetector(client)
+	},
+}
+
+// getMetadata sends the request to the metadata endpoint and returns the response body
+func getMetadata(
+	ctx context.Context,
+	client *http.Client,
+	method string,
+	url string,
+	headers map[string]string,
+) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+	return body, nil
+}
+
+// setIfNotEmpty sets the attribute only when the value is not empty
+func setIfNotEmpty(attributes map[string]string, key string, value string) {
+	if value != "" {
+		attributes[key] = value
+	}
+}
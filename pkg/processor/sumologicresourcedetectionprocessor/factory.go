@@ -0,0 +1,102 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicresourcedetectionprocessor
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "sumologic_resource_detection"
+
+	defaultTimeout  = 5 * time.Second
+	defaultOverride = true
+)
+
+// defaultDetectors are run when no detectors are configured
+var defaultDetectors = []string{hostDetectorName}
+
+var processorCapabilities = consumer.Capabilities{MutatesData: true}
+
+// NewFactory returns a new factory for the Sumo Logic resource detection processor.
+func NewFactory() component.ProcessorFactory {
+	return component.NewProcessorFactory(
+		typeStr,
+		createDefaultConfig,
+		component.WithLogsProcessor(createLogsProcessor),
+		component.WithMetricsProcessor(createMetricsProcessor),
+		component.WithTracesProcessor(createTracesProcessor),
+	)
+}
+
+func createDefaultConfig() config.Processor {
+	return &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		Timeout:           defaultTimeout,
+		Override:          defaultOverride,
+	}
+}
+
+func createLogsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateSettings,
+	cfg config.Processor,
+	nextConsumer consumer.Logs,
+) (component.LogsProcessor, error) {
+	rdp := newResourceDetectionProcessor(cfg.(*Config), params.Logger)
+	return processorhelper.NewLogsProcessor(
+		cfg,
+		nextConsumer,
+		rdp.ProcessLogs,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(rdp.Start))
+}
+
+func createMetricsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateSettings,
+	cfg config.Processor,
+	nextConsumer consumer.Metrics,
+) (component.MetricsProcessor, error) {
+	rdp := newResourceDetectionProcessor(cfg.(*Config), params.Logger)
+	return processorhelper.NewMetricsProcessor(
+		cfg,
+		nextConsumer,
+		rdp.ProcessMetrics,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(rdp.Start))
+}
+
+func createTracesProcessor(
+	_ context.Context,
+	params component.ProcessorCreateSettings,
+	cfg config.Processor,
+	nextConsumer consumer.Traces,
+) (component.TracesProcessor, error) {
+	rdp := newResourceDetectionProcessor(cfg.(*Config), params.Logger)
+	return processorhelper.NewTracesProcessor(
+		cfg,
+		nextConsumer,
+		rdp.ProcessTraces,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(rdp.Start))
+}
@@ -0,0 +1,88 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicresourcedetectionprocessor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+const (
+	gceDetectorName = "gce"
+
+	// defaultGCEEndpoint is the address of the GCE metadata server
+	defaultGCEEndpoint = "http://metadata.google.internal"
+)
+
+// gceDetector detects the attributes of the GCE instance from the metadata server
+type gceDetector struct {
+	client   *http.Client
+	endpoint string
+}
+
+func newGCEDetector(client *http.Client) *gceDetector {
+	return &gceDetector{
+		client:   client,
+		endpoint: defaultGCEEndpoint,
+	}
+}
+
+func (d *gceDetector) get(ctx context.Context, metadataPath string) (string, error) {
+	body, err := getMetadata(ctx, d.client, http.MethodGet, d.endpoint+"/computeMetadata/v1/"+metadataPath,
+		map[string]string{"Metadata-Flavor": "Google"},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s: %w", metadataPath, err)
+	}
+	return string(body), nil
+}
+
+func (d *gceDetector) detect(ctx context.Context) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, p := range []string{
+		"project/project-id",
+		"instance/id",
+		"instance/zone",
+		"instance/machine-type",
+		"instance/hostname",
+	} {
+		v, err := d.get(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		values[p] = v
+	}
+
+	attributes := map[string]string{
+		"cloud.provider": "gcp",
+		"cloud.platform": "gcp_compute_engine",
+	}
+	setIfNotEmpty(attributes, "cloud.account.id", values["project/project-id"])
+	setIfNotEmpty(attributes, "host.id", values["instance/id"])
+	setIfNotEmpty(attributes, "host.name", values["instance/hostname"])
+
+	// the zone and the machine type are returned as
+	// projects/<project number>/zones/<zone> and projects/<project number>/machineTypes/<type>
+	zone := path.Base(values["instance/zone"])
+	setIfNotEmpty(attributes, "cloud.availability_zone", zone)
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		attributes["cloud.region"] = zone[:i]
+	}
+	setIfNotEmpty(attributes, "host.type", path.Base(values["instance/machine-type"]))
+	return attributes, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicresourcedetectionprocessor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCEDetector(t *testing.T) {
+	metadata := map[string]string{
+		"/computeMetadata/v1/project/project-id":    "my-project",
+		"/computeMetadata/v1/instance/id":           "4520031799277581759",
+		"/computeMetadata/v1/instance/zone":         "projects/123456789012/zones/us-central1-a",
+		"/computeMetadata/v1/instance/machine-type": "projects/123456789012/machineTypes/e2-medium",
+		"/computeMetadata/v1/instance/hostname":     "instance-1.us-central1-a.c.my-project.internal",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		v, ok := metadata[req.URL.Path]
+		if !ok || req.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(v))
+	}))
+	t.Cleanup(srv.Close)
+
+	d := newGCEDetector(srv.Client())
+	d.endpoint = srv.URL
+
+	attributes, err := d.detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"cloud.provider":          "gcp",
+		"cloud.platform":          "gcp_compute_engine",
+		"cloud.account.id":        "my-project",
+		"cloud.region":            "us-central1",
+		"cloud.availability_zone": "us-central1-a",
+		"host.id":                 "4520031799277581759",
+		"host.type":               "e2-medium",
+		"host.name":               "instance-1.us-central1-a.c.my-project.internal",
+	}, attributes)
+}
@@ -0,0 +1,48 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicresourcedetectionprocessor
+
+import (
+	"context"
+	"os"
+	"runtime"
+)
+
+const hostDetectorName = "host"
+
+// hostDetector detects the attributes of the host from the OS
+type hostDetector struct {
+	hostname func() (string, error)
+	osType   string
+}
+
+func newHostDetector() *hostDetector {
+	return &hostDetector{
+		hostname: os.Hostname,
+		osType:   runtime.GOOS,
+	}
+}
+
+func (d *hostDetector) detect(context.Context) (map[string]string, error) {
+	hostname, err := d.hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"host.name": hostname,
+		"os.type":   d.osType,
+	}, nil
+}
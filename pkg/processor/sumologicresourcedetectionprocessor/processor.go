@@ -0,0 +1,147 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicresourcedetectionprocessor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+// sumoAttributeNames maps the OpenTelemetry names of the detected attributes
+// to the Sumo field names, the same way as the Sumo Logic exporter and
+// the Sumo Logic schema processor translate them.
+var sumoAttributeNames = map[string]string{
+	"cloud.account.id":        "AccountId",
+	"cloud.availability_zone": "AvailabilityZone",
+	"cloud.platform":          "aws_service",
+	"cloud.region":            "Region",
+	"host.id":                 "InstanceId",
+	"host.name":               "host",
+	"host.type":               "InstanceType",
+	"k8s.cluster.name":        "Cluster",
+}
+
+type namedDetector struct {
+	name     string
+	detector detector
+}
+
+type resourceDetectionProcessor struct {
+	logger    *zap.Logger
+	detectors []namedDetector
+	config    *Config
+
+	// attributes are the detected attributes, with the Sumo field names
+	attributes pdata.AttributeMap
+}
+
+func newResourceDetectionProcessor(cfg *Config, logger *zap.Logger) *resourceDetectionProcessor {
+	names := cfg.Detectors
+	if len(names) == 0 {
+		names = defaultDetectors
+	}
+
+	client := &http.Client{}
+	detectors := make([]namedDetector, 0, len(names))
+	for _, name := range names {
+		detectors = append(detectors, namedDetector{
+			name:     name,
+			detector: detectorFactories[name](client),
+		})
+	}
+
+	return &resourceDetectionProcessor{
+		logger:     logger,
+		detectors:  detectors,
+		config:     cfg,
+		attributes: pdata.NewAttributeMap(),
+	}
+}
+
+// Start runs the detectors. The detectors which fail are skipped,
+// so the attributes detected by the other ones are still set.
+func (rdp *resourceDetectionProcessor) Start(ctx context.Context, _ component.Host) error {
+	ctx, cancel := context.WithTimeout(ctx, rdp.config.Timeout)
+	defer cancel()
+
+	for _, d := range rdp.detectors {
+		detected, err := d.detector.detect(ctx)
+		if errors.Is(err, errNotDetected) {
+			rdp.logger.Info("Environment not detected", zap.String("detector", d.name))
+			continue
+		}
+		if err != nil {
+			rdp.logger.Warn("Resource detection failed", zap.String("detector", d.name), zap.Error(err))
+			continue
+		}
+
+		for otKey, value := range detected {
+			key := otKey
+			if sumoKey, ok := sumoAttributeNames[otKey]; ok {
+				key = sumoKey
+			}
+			// the earlier detectors take precedence
+			rdp.attributes.InsertString(key, value)
+		}
+		rdp.logger.Info("Resource detected", zap.String("detector", d.name), zap.Int("attributes", len(detected)))
+	}
+
+	return nil
+}
+
+// processResource sets the detected attributes on the resource
+func (rdp *resourceDetectionProcessor) processResource(resource pdata.Resource) {
+	attributes := resource.Attributes()
+	rdp.attributes.Range(func(k string, v pdata.AttributeValue) bool {
+		if rdp.config.Override {
+			attributes.Upsert(k, v)
+		} else {
+			attributes.Insert(k, v)
+		}
+		return true
+	})
+}
+
+// ProcessLogs processes logs
+func (rdp *resourceDetectionProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rdp.processResource(rls.At(i).Resource())
+	}
+	return ld, nil
+}
+
+// ProcessMetrics processes metrics
+func (rdp *resourceDetectionProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rdp.processResource(rms.At(i).Resource())
+	}
+	return md, nil
+}
+
+// ProcessTraces processes traces
+func (rdp *resourceDetectionProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rdp.processResource(rss.At(i).Resource())
+	}
+	return td, nil
+}
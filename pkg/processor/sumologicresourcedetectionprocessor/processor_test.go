@@ -0,0 +1,150 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicresourcedetectionprocessor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+type testDetector struct {
+	attributes map[string]string
+	err        error
+}
+
+func (d testDetector) detect(context.Context) (map[string]string, error) {
+	return d.attributes, d.err
+}
+
+func newTestProcessor(t *testing.T, override bool, detectors ...detector) *resourceDetectionProcessor {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Override = override
+
+	rdp := newResourceDetectionProcessor(cfg, zap.NewNop())
+	rdp.detectors = nil
+	for _, d := range detectors {
+		rdp.detectors = append(rdp.detectors, namedDetector{name: "test", detector: d})
+	}
+	require.NoError(t, rdp.Start(context.Background(), componenttest.NewNopHost()))
+	return rdp
+}
+
+func TestProcessorSumoAttributeNames(t *testing.T) {
+	rdp := newTestProcessor(t, true,
+		testDetector{attributes: map[string]string{
+			"cloud.provider":          "aws",
+			"cloud.platform":          "aws_ec2",
+			"cloud.account.id":        "123456789012",
+			"cloud.region":            "us-west-2",
+			"cloud.availability_zone": "us-west-2b",
+			"host.id":                 "i-0123456789abcdef0",
+			"host.type":               "m5.large",
+			"host.name":               "ip-10-0-0-1",
+		}},
+	)
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().AppendEmpty()
+
+	md, err := rdp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"cloud.provider":   "aws",
+		"aws_service":      "aws_ec2",
+		"AccountId":        "123456789012",
+		"Region":           "us-west-2",
+		"AvailabilityZone": "us-west-2b",
+		"InstanceId":       "i-0123456789abcdef0",
+		"InstanceType":     "m5.large",
+		"host":             "ip-10-0-0-1",
+	}, md.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+}
+
+func TestProcessorDetectorsPrecedence(t *testing.T) {
+	rdp := newTestProcessor(t, true,
+		testDetector{err: errNotDetected},
+		testDetector{err: errors.New("connection refused")},
+		testDetector{attributes: map[string]string{"k8s.cluster.name": "prod", "cloud.platform": "aws_eks"}},
+		testDetector{attributes: map[string]string{"cloud.platform": "aws_ec2", "host.name": "ip-10-0-0-1"}},
+	)
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().AppendEmpty()
+
+	ld, err := rdp.ProcessLogs(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"Cluster":     "prod",
+		"aws_service": "aws_eks",
+		"host":        "ip-10-0-0-1",
+	}, ld.ResourceLogs().At(0).Resource().Attributes().AsRaw())
+}
+
+func TestProcessorOverride(t *testing.T) {
+	testcases := []struct {
+		name     string
+		override bool
+		expected string
+	}{
+		{
+			name:     "override",
+			override: true,
+			expected: "detected",
+		},
+		{
+			name:     "no override",
+			override: false,
+			expected: "existing",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			rdp := newTestProcessor(t, tc.override,
+				testDetector{attributes: map[string]string{"host.name": "detected"}},
+			)
+
+			td := pdata.NewTraces()
+			td.ResourceSpans().AppendEmpty().Resource().Attributes().InsertString("host", "existing")
+
+			td, err := rdp.ProcessTraces(context.Background(), td)
+			require.NoError(t, err)
+
+			host, ok := td.ResourceSpans().At(0).Resource().Attributes().Get("host")
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, host.StringVal())
+		})
+	}
+}
+
+func TestHostDetector(t *testing.T) {
+	d := newHostDetector()
+	d.hostname = func() (string, error) { return "my-host", nil }
+	d.osType = "linux"
+
+	attributes, err := d.detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"host.name": "my-host",
+		"os.type":   "linux",
+	}, attributes)
+}
@@ -0,0 +1,47 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicschemaprocessor
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the Sumo Logic schema processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:"-"`
+
+	// TranslateAttributes defines whether the resource attributes are translated
+	// from OpenTelemetry conventions to Sumo conventions (for example
+	// `cloud.account.id` => `AccountId`, `k8s.pod.name` => `pod` etc).
+	// By default this is true.
+	TranslateAttributes bool `mapstructure:"translate_attributes"`
+
+	// AttributeTranslations extends or overrides the default translations,
+	// mapping OpenTelemetry attribute names to Sumo attribute names.
+	// An empty Sumo attribute name disables the default translation of the attribute.
+	AttributeTranslations map[string]string `mapstructure:"attribute_translations"`
+}
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	for otKey := range cfg.AttributeTranslations {
+		if otKey == "" {
+			return errors.New("attribute_translations: attribute name cannot be empty")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicschemaprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+type sumologicSchemaProcessor struct {
+	translateAttributes   bool
+	attributeTranslations map[string]string
+}
+
+func newSumologicSchemaProcessor(cfg *Config) *sumologicSchemaProcessor {
+	return &sumologicSchemaProcessor{
+		translateAttributes:   cfg.TranslateAttributes,
+		attributeTranslations: newAttributeTranslations(cfg.AttributeTranslations),
+	}
+}
+
+// processResource applies the Sumo conventions to the resource
+func (sp *sumologicSchemaProcessor) processResource(resource pdata.Resource) {
+	if sp.translateAttributes {
+		translateAttributes(sp.attributeTranslations, resource.Attributes())
+	}
+}
+
+// ProcessLogs processes logs
+func (sp *sumologicSchemaProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		sp.processResource(rls.At(i).Resource())
+	}
+	return ld, nil
+}
+
+// ProcessMetrics processes metrics
+func (sp *sumologicSchemaProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sp.processResource(rms.At(i).Resource())
+	}
+	return md, nil
+}
+
+// ProcessTraces processes traces
+func (sp *sumologicSchemaProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		sp.processResource(rss.At(i).Resource())
+	}
+	return td, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicschemaprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func fillTestResource(attributes pdata.AttributeMap) {
+	attributes.InsertString("k8s.namespace.name", "my-namespace")
+	attributes.InsertString("cloud.platform", "aws_ec2")
+	attributes.InsertString("deployment.environment", "prod")
+}
+
+func TestProcessSignals(t *testing.T) {
+	testcases := []struct {
+		name     string
+		cfg      *Config
+		expected map[string]string
+	}{
+		{
+			name: "default translations",
+			cfg:  createDefaultConfig().(*Config),
+			expected: map[string]string{
+				"namespace":              "my-namespace",
+				"aws_service":            "aws_ec2",
+				"deployment.environment": "prod",
+			},
+		},
+		{
+			name: "custom translations",
+			cfg: &Config{
+				TranslateAttributes: true,
+				AttributeTranslations: map[string]string{
+					"cloud.platform":         "",
+					"deployment.environment": "environment",
+				},
+			},
+			expected: map[string]string{
+				"namespace":      "my-namespace",
+				"cloud.platform": "aws_ec2",
+				"environment":    "prod",
+			},
+		},
+		{
+			name: "translation disabled",
+			cfg: &Config{
+				TranslateAttributes: false,
+			},
+			expected: map[string]string{
+				"k8s.namespace.name":     "my-namespace",
+				"cloud.platform":         "aws_ec2",
+				"deployment.environment": "prod",
+			},
+		},
+	}
+
+	assertResource := func(t *testing.T, expected map[string]string, attributes pdata.AttributeMap) {
+		assert.Equal(t, len(expected), attributes.Len())
+		for key, value := range expected {
+			assertAttribute(t, attributes, key, value)
+		}
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			sp := newSumologicSchemaProcessor(tc.cfg)
+
+			logs := pdata.NewLogs()
+			fillTestResource(logs.ResourceLogs().AppendEmpty().Resource().Attributes())
+			logs, err := sp.ProcessLogs(context.Background(), logs)
+			require.NoError(t, err)
+			assertResource(t, tc.expected, logs.ResourceLogs().At(0).Resource().Attributes())
+
+			metrics := pdata.NewMetrics()
+			fillTestResource(metrics.ResourceMetrics().AppendEmpty().Resource().Attributes())
+			metrics, err = sp.ProcessMetrics(context.Background(), metrics)
+			require.NoError(t, err)
+			assertResource(t, tc.expected, metrics.ResourceMetrics().At(0).Resource().Attributes())
+
+			traces := pdata.NewTraces()
+			fillTestResource(traces.ResourceSpans().AppendEmpty().Resource().Attributes())
+			traces, err = sp.ProcessTraces(context.Background(), traces)
+			require.NoError(t, err)
+			assertResource(t, tc.expected, traces.ResourceSpans().At(0).Resource().Attributes())
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicschemaprocessor
+
+import (
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// defaultAttributeTranslations maps OpenTelemetry attribute names to Sumo attribute names
+var defaultAttributeTranslations = map[string]string{
+	"cloud.account.id":        "AccountId",
+	"cloud.availability_zone": "AvailabilityZone",
+	"cloud.platform":          "aws_service",
+	"cloud.region":            "Region",
+	"host.id":                 "InstanceId",
+	"host.name":               "host",
+	"host.type":               "InstanceType",
+	"k8s.cluster.name":        "Cluster",
+	"k8s.container.name":      "container",
+	"k8s.daemonset.name":      "daemonset",
+	"k8s.deployment.name":     "deployment",
+	"k8s.namespace.name":      "namespace",
+	"k8s.node.name":           "node",
+	"k8s.service.name":        "service",
+	"k8s.pod.hostname":        "host",
+	"k8s.pod.name":            "pod",
+	"k8s.pod.uid":             "pod_id",
+	"k8s.replicaset.name":     "replicaset",
+	"k8s.statefulset.name":    "statefulset",
+	"service.name":            "service",
+	"file.path.resolved":      "_sourceName",
+}
+
+// newAttributeTranslations returns the default translations merged with the configured ones
+func newAttributeTranslations(overrides map[string]string) map[string]string {
+	translations := make(map[string]string, len(defaultAttributeTranslations)+len(overrides))
+	for otKey, sumoKey := range defaultAttributeTranslations {
+		translations[otKey] = sumoKey
+	}
+	for otKey, sumoKey := range overrides {
+		if sumoKey == "" {
+			delete(translations, otKey)
+		} else {
+			translations[otKey] = sumoKey
+		}
+	}
+	return translations
+}
+
+// translateAttributes renames the attributes according to the translations.
+// The attribute is not renamed if the attribute with the target name already exists.
+func translateAttributes(translations map[string]string, attributes pdata.AttributeMap) {
+	ret := pdata.NewAttributeMap()
+	ret.EnsureCapacity(attributes.Len())
+
+	attributes.Range(func(otKey string, value pdata.AttributeValue) bool {
+		if sumoKey, ok := translations[otKey]; ok {
+			// Only insert if it doesn't exist yet to prevent overwriting.
+			// We have to do it this way since the final return value is not
+			// ready yet to rely on .Insert() not overwriting.
+			if _, exists := attributes.Get(sumoKey); !exists {
+				ret.Insert(sumoKey, value)
+			} else {
+				ret.Insert(otKey, value)
+			}
+		} else {
+			ret.Insert(otKey, value)
+		}
+		return true
+	})
+
+	ret.CopyTo(attributes)
+}
@@ -0,0 +1,84 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicschemaprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func assertAttribute(t *testing.T, attributes pdata.AttributeMap, key string, expected string) {
+	value, ok := attributes.Get(key)
+	if expected == "" {
+		assert.False(t, ok, "unexpected attribute %s", key)
+		return
+	}
+	require.True(t, ok, "missing attribute %s", key)
+	assert.Equal(t, expected, value.StringVal())
+}
+
+func TestTranslateAttributes(t *testing.T) {
+	attributes := pdata.NewAttributeMap()
+	attributes.InsertString("host.name", "testing-host")
+	attributes.InsertString("k8s.pod.name", "my-pod")
+	attributes.InsertString("cloud.account.id", "my-account-id")
+	attributes.InsertString("other", "other-value")
+	require.Equal(t, 4, attributes.Len())
+
+	translateAttributes(defaultAttributeTranslations, attributes)
+
+	assert.Equal(t, 4, attributes.Len())
+	assertAttribute(t, attributes, "host", "testing-host")
+	assertAttribute(t, attributes, "host.name", "")
+	assertAttribute(t, attributes, "pod", "my-pod")
+	assertAttribute(t, attributes, "k8s.pod.name", "")
+	assertAttribute(t, attributes, "AccountId", "my-account-id")
+	assertAttribute(t, attributes, "cloud.account.id", "")
+	assertAttribute(t, attributes, "other", "other-value")
+}
+
+func TestTranslateAttributesDoesNotOverwrite(t *testing.T) {
+	attributes := pdata.NewAttributeMap()
+	attributes.InsertString("host.name", "otel-host")
+	attributes.InsertString("host", "sumo-host")
+
+	translateAttributes(defaultAttributeTranslations, attributes)
+
+	assert.Equal(t, 2, attributes.Len())
+	assertAttribute(t, attributes, "host", "sumo-host")
+	assertAttribute(t, attributes, "host.name", "otel-host")
+}
+
+func TestNewAttributeTranslations(t *testing.T) {
+	translations := newAttributeTranslations(map[string]string{
+		"k8s.pod.name":           "pod_name",
+		"cloud.platform":         "",
+		"deployment.environment": "environment",
+	})
+
+	assert.Equal(t, "pod_name", translations["k8s.pod.name"])
+	assert.Equal(t, "environment", translations["deployment.environment"])
+	assert.NotContains(t, translations, "cloud.platform")
+	assert.Equal(t, "host", translations["host.name"])
+	// one translation is added and one is removed
+	assert.Len(t, translations, len(defaultAttributeTranslations))
+
+	// defaults are not modified
+	assert.Equal(t, "pod", defaultAttributeTranslations["k8s.pod.name"])
+	assert.Equal(t, "aws_service", defaultAttributeTranslations["cloud.platform"])
+}
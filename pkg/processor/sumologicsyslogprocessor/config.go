@@ -15,6 +15,9 @@
 package sumologicsyslogprocessor
 
 import (
+	"fmt"
+	"time"
+
 	"go.opentelemetry.io/collector/config"
 )
 
@@ -24,8 +27,47 @@ type Config struct {
 
 	// FacilityAttr is the name of the attribute the facility name should be placed into.
 	FacilityAttr string `mapstructure:"facility_attr"`
+
+	// ParseTimestamp enables parsing the RFC3164 timestamp following the priority
+	// and setting it as the log record timestamp.
+	ParseTimestamp bool `mapstructure:"parse_timestamp"`
+
+	// Location is the IANA time zone name used to interpret RFC3164 timestamps,
+	// which do not carry time zone information.
+	Location string `mapstructure:"location"`
+
+	// YearInference defines how the missing year of RFC3164 timestamps is inferred.
+	// Possible values are `nearest` and `current`.
+	YearInference YearInferenceType `mapstructure:"year_inference"`
+}
+
+func (cfg *Config) Validate() error {
+	switch cfg.YearInference {
+	case YearInferenceNearest:
+	case YearInferenceCurrent:
+	default:
+		return fmt.Errorf("unexpected year inference: %s", cfg.YearInference)
+	}
+
+	if _, err := time.LoadLocation(cfg.Location); err != nil {
+		return fmt.Errorf("failed to load location %q: %w", cfg.Location, err)
+	}
+
+	return nil
 }
 
+// YearInferenceType represents year_inference
+type YearInferenceType string
+
 const (
-	defaultFacilityAttr = "facility"
+	// YearInferenceNearest picks the year which puts the timestamp closest to the
+	// current time, which handles logs sent around New Year.
+	YearInferenceNearest YearInferenceType = "nearest"
+	// YearInferenceCurrent always uses the current year.
+	YearInferenceCurrent YearInferenceType = "current"
+
+	defaultFacilityAttr   = "facility"
+	defaultLocation       = "UTC"
+	defaultYearInference  = YearInferenceNearest
+	defaultParseTimestamp = false
 )
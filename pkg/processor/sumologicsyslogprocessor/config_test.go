@@ -40,5 +40,48 @@ func TestLoadConfig(t *testing.T) {
 		&Config{
 			ProcessorSettings: config.NewProcessorSettings(config.NewComponentID("sumologic_syslog")),
 			FacilityAttr:      "testAttrName",
+			ParseTimestamp:    true,
+			Location:          "Europe/Warsaw",
+			YearInference:     YearInferenceCurrent,
 		})
 }
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		cfg           *Config
+		expectedError string
+	}{
+		{
+			name: "default config",
+			cfg:  createDefaultConfig().(*Config),
+		},
+		{
+			name: "unexpected year inference",
+			cfg: &Config{
+				Location:      "UTC",
+				YearInference: "previous",
+			},
+			expectedError: "unexpected year inference: previous",
+		},
+		{
+			name: "unknown location",
+			cfg: &Config{
+				Location:      "Mars/Olympus_Mons",
+				YearInference: YearInferenceNearest,
+			},
+			expectedError: `failed to load location "Mars/Olympus_Mons": unknown time zone Mars/Olympus_Mons`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -42,6 +42,9 @@ func createDefaultConfig() config.Processor {
 	return &Config{
 		ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
 		FacilityAttr:      defaultFacilityAttr,
+		ParseTimestamp:    defaultParseTimestamp,
+		Location:          defaultLocation,
+		YearInference:     defaultYearInference,
 	}
 }
 
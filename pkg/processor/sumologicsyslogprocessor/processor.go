@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/model/pdata"
 )
@@ -28,11 +30,21 @@ import (
 type sumologicSyslogProcessor struct {
 	syslogFacilityAttrName string
 	syslogFacilityRegex    *regexp.Regexp
+
+	parseTimestamp       bool
+	syslogTimestampRegex *regexp.Regexp
+	location             *time.Location
+	yearInference        YearInferenceType
+	// now returns the current time, it's overridden in tests
+	now func() time.Time
 }
 
 const (
-	syslogSource   = "syslog"
-	facilityRegexp = `^<(?P<number>\d+)>`
+	syslogSource    = "syslog"
+	facilityRegexp  = `^<(?P<number>\d+)>`
+	timestampRegexp = `^<\d+>\s?(?P<timestamp>[A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2})`
+	// timestampLayout is the RFC3164 timestamp layout, days are space padded
+	timestampLayout = "Jan _2 15:04:05"
 )
 
 var facilities = map[int]string{
@@ -68,9 +80,24 @@ func newSumologicSyslogProcessor(cfg *Config) (*sumologicSyslogProcessor, error)
 		return nil, err
 	}
 
+	tr, err := regexp.Compile(timestampRegexp)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(cfg.Location)
+	if err != nil {
+		return nil, err
+	}
+
 	return &sumologicSyslogProcessor{
 		syslogFacilityAttrName: cfg.FacilityAttr,
 		syslogFacilityRegex:    r,
+		parseTimestamp:         cfg.ParseTimestamp,
+		syslogTimestampRegex:   tr,
+		location:               loc,
+		yearInference:          cfg.YearInference,
+		now:                    time.Now,
 	}, nil
 }
 
@@ -111,9 +138,103 @@ func (ssp *sumologicSyslogProcessor) ProcessLogs(ctx context.Context, ld pdata.L
 					}
 				}
 				log.Attributes().UpsertString(ssp.syslogFacilityAttrName, value)
+
+				if ssp.parseTimestamp {
+					if ts, ok := ssp.extractTimestamp(log.Body().StringVal()); ok {
+						log.SetTimestamp(pdata.NewTimestampFromTime(ts))
+					}
+				}
 			}
 		}
 	}
 
 	return ld, nil
 }
+
+// extractTimestamp extracts the RFC3164 timestamp from the syslog line.
+// As RFC3164 timestamps lack the year, it's inferred according to the configured
+// year inference and the timestamp is interpreted in the configured location.
+func (ssp *sumologicSyslogProcessor) extractTimestamp(line string) (time.Time, bool) {
+	match := ssp.syslogTimestampRegex.FindStringSubmatch(line)
+	if match == nil {
+		return time.Time{}, false
+	}
+
+	// time.Parse rejects leap seconds so parse them as the 59th second
+	// and move the result to the next second afterwards.
+	value := match[1]
+	var leapSecond time.Duration
+	if strings.HasSuffix(value, ":60") {
+		value = strings.TrimSuffix(value, ":60") + ":59"
+		leapSecond = time.Second
+	}
+
+	parsed, err := time.ParseInLocation(timestampLayout, value, ssp.location)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	now := ssp.now().In(ssp.location)
+
+	// Pick the year which puts the timestamp closest to now so that e.g.
+	// a December log received in January is assigned to the previous year.
+	// The years in which the date doesn't exist (February 29th) are skipped.
+	var (
+		ts    time.Time
+		found bool
+	)
+	for _, year := range ssp.candidateYears(parsed, now.Year()) {
+		candidate, ok := withYear(parsed, year)
+		if !ok {
+			continue
+		}
+		if !found || absDuration(candidate.Sub(now)) < absDuration(ts.Sub(now)) {
+			ts, found = candidate, true
+		}
+	}
+	if !found {
+		return time.Time{}, false
+	}
+
+	return ts.Add(leapSecond), true
+}
+
+// candidateYears returns the years the timestamp can be assigned to according to the year inference,
+// starting with the current one. With the nearest year inference, February 29th is assigned
+// to the closest leap year.
+func (ssp *sumologicSyslogProcessor) candidateYears(t time.Time, current int) []int {
+	if ssp.yearInference != YearInferenceNearest {
+		return []int{current}
+	}
+	if t.Month() != time.February || t.Day() != 29 {
+		return []int{current, current - 1, current + 1}
+	}
+
+	previous := current
+	for !isLeapYear(previous) {
+		previous--
+	}
+	next := current + 1
+	for !isLeapYear(next) {
+		next++
+	}
+	return []int{previous, next}
+}
+
+// withYear returns the time in the given year, it returns false if the date doesn't exist
+// in that year, i.e. February 29th in a non-leap year.
+func withYear(t time.Time, year int) (time.Time, bool) {
+	ts := time.Date(year, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	return ts, ts.Month() == t.Month() && ts.Day() == t.Day()
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -64,3 +65,153 @@ func TestProcessLogs(t *testing.T) {
 		assert.Equal(t, line, attr.StringVal())
 	}
 }
+
+func TestProcessLogsTimestamp(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name          string
+		line          string
+		now           time.Time
+		location      *time.Location
+		yearInference YearInferenceType
+		expected      time.Time
+		parsed        bool
+	}{
+		{
+			name:          "current year",
+			line:          `<13>Jun 14 10:11:12 host app: Example log`,
+			now:           time.Date(2021, time.June, 14, 12, 0, 0, 0, time.UTC),
+			location:      time.UTC,
+			yearInference: YearInferenceNearest,
+			expected:      time.Date(2021, time.June, 14, 10, 11, 12, 0, time.UTC),
+			parsed:        true,
+		},
+		{
+			name:          "space padded day",
+			line:          `<13>Jun  4 10:11:12 host app: Example log`,
+			now:           time.Date(2021, time.June, 14, 12, 0, 0, 0, time.UTC),
+			location:      time.UTC,
+			yearInference: YearInferenceNearest,
+			expected:      time.Date(2021, time.June, 4, 10, 11, 12, 0, time.UTC),
+			parsed:        true,
+		},
+		{
+			name:          "december log received in january",
+			line:          `<13>Dec 31 23:59:58 host app: Example log`,
+			now:           time.Date(2022, time.January, 1, 0, 0, 5, 0, time.UTC),
+			location:      time.UTC,
+			yearInference: YearInferenceNearest,
+			expected:      time.Date(2021, time.December, 31, 23, 59, 58, 0, time.UTC),
+			parsed:        true,
+		},
+		{
+			name:          "january log received in december",
+			line:          `<13>Jan  1 00:00:01 host app: Example log`,
+			now:           time.Date(2021, time.December, 31, 23, 59, 59, 0, time.UTC),
+			location:      time.UTC,
+			yearInference: YearInferenceNearest,
+			expected:      time.Date(2022, time.January, 1, 0, 0, 1, 0, time.UTC),
+			parsed:        true,
+		},
+		{
+			name:          "december log received in january with current year inference",
+			line:          `<13>Dec 31 23:59:58 host app: Example log`,
+			now:           time.Date(2022, time.January, 1, 0, 0, 5, 0, time.UTC),
+			location:      time.UTC,
+			yearInference: YearInferenceCurrent,
+			expected:      time.Date(2022, time.December, 31, 23, 59, 58, 0, time.UTC),
+			parsed:        true,
+		},
+		{
+			name:          "february 29th in a leap year",
+			line:          `<13>Feb 29 10:11:12 host app: Example log`,
+			now:           time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC),
+			location:      time.UTC,
+			yearInference: YearInferenceCurrent,
+			expected:      time.Date(2024, time.February, 29, 10, 11, 12, 0, time.UTC),
+			parsed:        true,
+		},
+		{
+			name:          "february 29th received in a non-leap year",
+			line:          `<13>Feb 29 23:59:58 host app: Example log`,
+			now:           time.Date(2025, time.March, 1, 0, 0, 5, 0, time.UTC),
+			location:      time.UTC,
+			yearInference: YearInferenceNearest,
+			expected:      time.Date(2024, time.February, 29, 23, 59, 58, 0, time.UTC),
+			parsed:        true,
+		},
+		{
+			name:          "february 29th received in a non-leap year before the next leap year",
+			line:          `<13>Feb 29 10:11:12 host app: Example log`,
+			now:           time.Date(2027, time.December, 31, 12, 0, 0, 0, time.UTC),
+			location:      time.UTC,
+			yearInference: YearInferenceNearest,
+			expected:      time.Date(2028, time.February, 29, 10, 11, 12, 0, time.UTC),
+			parsed:        true,
+		},
+		{
+			name:          "february 29th received in a non-leap year with current year inference",
+			line:          `<13>Feb 29 10:11:12 host app: Example log`,
+			now:           time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC),
+			location:      time.UTC,
+			yearInference: YearInferenceCurrent,
+			parsed:        false,
+		},
+		{
+			name:          "leap second",
+			line:          `<13>Dec 31 23:59:60 host app: Example log`,
+			now:           time.Date(2016, time.December, 31, 23, 59, 59, 0, time.UTC),
+			location:      time.UTC,
+			yearInference: YearInferenceNearest,
+			expected:      time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC),
+			parsed:        true,
+		},
+		{
+			name:          "location",
+			line:          `<13>Jun 14 10:11:12 host app: Example log`,
+			now:           time.Date(2021, time.June, 14, 12, 0, 0, 0, time.UTC),
+			location:      warsaw,
+			yearInference: YearInferenceNearest,
+			expected:      time.Date(2021, time.June, 14, 8, 11, 12, 0, time.UTC),
+			parsed:        true,
+		},
+		{
+			name:          "no timestamp",
+			line:          `<13> Example log`,
+			now:           time.Date(2021, time.June, 14, 12, 0, 0, 0, time.UTC),
+			location:      time.UTC,
+			yearInference: YearInferenceNearest,
+			parsed:        false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			logs := pdata.NewLogs()
+			lr := logs.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().LogRecords().AppendEmpty()
+			lr.Body().SetStringVal(tc.line)
+
+			processor := &sumologicSyslogProcessor{
+				syslogFacilityAttrName: "facility",
+				syslogFacilityRegex:    regexp.MustCompile(facilityRegexp),
+				parseTimestamp:         true,
+				syslogTimestampRegex:   regexp.MustCompile(timestampRegexp),
+				location:               tc.location,
+				yearInference:          tc.yearInference,
+				now:                    func() time.Time { return tc.now },
+			}
+
+			result, err := processor.ProcessLogs(context.Background(), logs)
+			require.NoError(t, err)
+
+			ts := result.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).LogRecords().At(0).Timestamp()
+			if tc.parsed {
+				assert.True(t, tc.expected.Equal(ts.AsTime()), "expected %s, got %s", tc.expected, ts.AsTime())
+			} else {
+				assert.Equal(t, pdata.Timestamp(0), ts)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslogreceiver
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+const (
+	// CommonFormat is the Common Log Format, e.g.
+	// 127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /index.html HTTP/1.0" 200 2326
+	CommonFormat = "common"
+	// CombinedFormat is the Common Log Format followed by the referer and the user agent,
+	// which is the default format of nginx access logs
+	CombinedFormat = "combined"
+
+	// StartAtBeginning makes the receiver read the files found on start from the beginning
+	StartAtBeginning = "beginning"
+	// StartAtEnd makes the receiver read only the lines appended to the files found on start
+	StartAtEnd = "end"
+)
+
+// Config defines configuration for the access log receiver.
+type Config struct {
+	config.ReceiverSettings `mapstructure:",squash"`
+
+	// Include is the list of glob patterns of the access log files to read.
+	Include []string `mapstructure:"include"`
+
+	// Exclude is the list of glob patterns of the files which are not read,
+	// even though they match Include, e.g. the rotated files.
+	Exclude []string `mapstructure:"exclude"`
+
+	// Format is the format of the access log lines, either common or combined.
+	// By default this is combined.
+	Format string `mapstructure:"format"`
+
+	// StartAt defines where to start reading the files found on start which have
+	// no persisted offset, either beginning or end. The files created later
+	// are always read from the beginning.
+	// By default this is end.
+	StartAt string `mapstructure:"start_at"`
+
+	// PollInterval defines how often the files are checked for the new lines.
+	// By default this is 200ms.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+
+	// StorageID is the ID of the storage extension used to persist the offsets of the files,
+	// so the receiver resumes reading after restart.
+	// When not set, the offsets are kept in memory only.
+	StorageID *config.ComponentID `mapstructure:"storage"`
+
+	// MaxBatchSize is the maximum number of the log records passed to the pipeline at once.
+	// By default this is 100.
+	MaxBatchSize int `mapstructure:"max_batch_size"`
+}
+
+var _ config.Receiver = (*Config)(nil)
+
+// Validate checks if the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if len(cfg.Include) == 0 {
+		return errors.New("include cannot be empty")
+	}
+	for _, pattern := range append(append([]string{}, cfg.Include...), cfg.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+	}
+	switch cfg.Format {
+	case CommonFormat, CombinedFormat:
+	default:
+		return fmt.Errorf("unexpected format: %s", cfg.Format)
+	}
+	switch cfg.StartAt {
+	case StartAtBeginning, StartAtEnd:
+	default:
+		return fmt.Errorf("unexpected start_at: %s", cfg.StartAt)
+	}
+	if cfg.PollInterval <= 0 {
+		return fmt.Errorf("poll_interval has to be positive: %s", cfg.PollInterval)
+	}
+	if cfg.MaxBatchSize <= 0 {
+		return fmt.Errorf("max_batch_size has to be positive: %d", cfg.MaxBatchSize)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslogreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Receivers[config.NewComponentID(typeStr)])
+
+	storageID := config.NewComponentID("file_storage")
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: config.NewReceiverSettings(config.NewComponentIDWithName(typeStr, "custom")),
+			Include:          []string{"/var/log/nginx/*.log"},
+			Exclude:          []string{"/var/log/nginx/error.log"},
+			Format:           CommonFormat,
+			StartAt:          StartAtBeginning,
+			PollInterval:     time.Second,
+			StorageID:        &storageID,
+			MaxBatchSize:     50,
+		},
+		cfg.Receivers[config.NewComponentIDWithName(typeStr, "custom")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		modify        func(cfg *Config)
+		expectedError string
+	}{
+		{
+			name:   "include",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:          "empty include",
+			modify:        func(cfg *Config) { cfg.Include = nil },
+			expectedError: "include cannot be empty",
+		},
+		{
+			name:          "invalid exclude pattern",
+			modify:        func(cfg *Config) { cfg.Exclude = []string{"/var/log/[a-"} },
+			expectedError: `invalid glob pattern "/var/log/[a-": syntax error in pattern`,
+		},
+		{
+			name:          "unexpected format",
+			modify:        func(cfg *Config) { cfg.Format = "json" },
+			expectedError: "unexpected format: json",
+		},
+		{
+			name:          "unexpected start_at",
+			modify:        func(cfg *Config) { cfg.StartAt = "middle" },
+			expectedError: "unexpected start_at: middle",
+		},
+		{
+			name:          "non-positive poll_interval",
+			modify:        func(cfg *Config) { cfg.PollInterval = 0 },
+			expectedError: "poll_interval has to be positive: 0s",
+		},
+		{
+			name:          "non-positive max_batch_size",
+			modify:        func(cfg *Config) { cfg.MaxBatchSize = 0 },
+			expectedError: "max_batch_size has to be positive: 0",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Include = []string{"/var/log/nginx/access.log"}
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslogreceiver
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+)
+
+const (
+	// readBufferSize is the size of the chunks the file is read in
+	readBufferSize = 64 * 1024
+	// maxLineSize limits the size of the single line, the longer lines are split
+	maxLineSize = 1024 * 1024
+)
+
+// fileReader reads the complete lines appended to the file
+type fileReader struct {
+	path string
+	file *os.File
+	info os.FileInfo
+	// offset is the offset of the first line which was not read yet
+	offset int64
+}
+
+func openFileReader(path string) (*fileReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &fileReader{
+		path: path,
+		file: file,
+		info: info,
+	}, nil
+}
+
+// size returns the current size of the file
+func (fr *fileReader) size() int64 {
+	if info, err := fr.file.Stat(); err == nil {
+		return info.Size()
+	}
+	return fr.info.Size()
+}
+
+// rotated returns whether the path refers to a different file than the one being read
+func (fr *fileReader) rotated() bool {
+	info, err := os.Stat(fr.path)
+	return err == nil && !os.SameFile(fr.info, info)
+}
+
+// readLines calls consume with every complete line appended to the file since the last call.
+// The line which is not terminated with the new line yet is read by the next call,
+// unless it exceeds maxLineSize. When the file was truncated, it's read from the beginning.
+func (fr *fileReader) readLines(consume func(line string)) error {
+	if fr.size() < fr.offset {
+		fr.offset = 0
+	}
+
+	chunk := make([]byte, readBufferSize)
+	var pending []byte
+	for {
+		n, err := fr.file.ReadAt(chunk, fr.offset+int64(len(pending)))
+		pending = append(pending, chunk[:n]...)
+
+		for {
+			i := bytes.IndexByte(pending, '\n')
+			if i < 0 {
+				break
+			}
+			consume(string(bytes.TrimSuffix(pending[:i], []byte{'\r'})))
+			fr.offset += int64(i + 1)
+			pending = pending[i+1:]
+		}
+		if len(pending) >= maxLineSize {
+			consume(string(pending[:maxLineSize]))
+			fr.offset += maxLineSize
+			pending = pending[maxLineSize:]
+		}
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return err
+		}
+	}
+}
+
+func (fr *fileReader) close() {
+	fr.file.Close()
+}
@@ -0,0 +1,78 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslogreceiver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReaderReadLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	require.NoError(t, os.WriteFile(path, []byte("first\r\nsecond\npart"), 0o600))
+
+	fr, err := openFileReader(path)
+	require.NoError(t, err)
+	defer fr.close()
+
+	var lines []string
+	consume := func(line string) { lines = append(lines, line) }
+
+	require.NoError(t, fr.readLines(consume))
+	assert.Equal(t, []string{"first", "second"}, lines)
+	assert.EqualValues(t, len("first\r\nsecond\n"), fr.offset)
+
+	// the partial line is read once it's complete
+	appendToFile(t, path, "ial\n")
+	lines = nil
+	require.NoError(t, fr.readLines(consume))
+	assert.Equal(t, []string{"partial"}, lines)
+
+	// the truncated file is read from the beginning
+	require.NoError(t, os.Truncate(path, 0))
+	appendToFile(t, path, "new\n")
+	lines = nil
+	require.NoError(t, fr.readLines(consume))
+	assert.Equal(t, []string{"new"}, lines)
+}
+
+func TestFileReaderSplitsLongLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	long := strings.Repeat("a", maxLineSize+10)
+	require.NoError(t, os.WriteFile(path, []byte(long+"\n"), 0o600))
+
+	fr, err := openFileReader(path)
+	require.NoError(t, err)
+	defer fr.close()
+
+	var lines []string
+	require.NoError(t, fr.readLines(func(line string) { lines = append(lines, line) }))
+	require.Len(t, lines, 2)
+	assert.Len(t, lines[0], maxLineSize)
+	assert.Equal(t, strings.Repeat("a", 10), lines[1])
+}
+
+func appendToFile(t *testing.T, path string, data string) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString(data)
+	require.NoError(t, err)
+}
@@ -0,0 +1,157 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslogreceiver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	attributeClientIP         = "http.client_ip"
+	attributeUser             = "enduser.id"
+	attributeMethod           = "http.method"
+	attributeTarget           = "http.target"
+	attributePath             = "http.path"
+	attributeFlavor           = "http.flavor"
+	attributeStatusCode       = "http.status_code"
+	attributeResponseBodySize = "http.response_content_length"
+	attributeReferer          = "http.referer"
+	attributeUserAgent        = "http.user_agent"
+	// attributeRequestTime is the request processing time in seconds, e.g. nginx $request_time
+	attributeRequestTime = "http.request_time"
+	// attributeFilePath is the resource attribute with the path of the access log file
+	attributeFilePath = "log.file.path"
+)
+
+const (
+	// timeLocalLayout is the layout of the time of the request, e.g. 10/Oct/2000:13:55:36 -0700
+	timeLocalLayout = "02/Jan/2006:15:04:05 -0700"
+
+	commonFormatExpression   = `^(?P<client>\S+) \S+ (?P<user>\S+) \[(?P<time>[^\]]+)\] "(?P<request>(?:[^"\\]|\\.)*)" (?P<status>\d{3}) (?P<bytes>\d+|-)`
+	combinedFormatExpression = commonFormatExpression + ` "(?P<referer>(?:[^"\\]|\\.)*)" "(?P<agent>(?:[^"\\]|\\.)*)"`
+	// requestTimeExpression matches the request time which directly follows the format,
+	// the rest of the line is ignored
+	requestTimeExpression = `(?: (?P<request_time>\d+(?:\.\d+)?))?(?:\s.*)?$`
+
+	// unknownValue is logged when the value of the field is not known
+	unknownValue = "-"
+)
+
+// parser converts the access log lines to the log records
+type parser struct {
+	re *regexp.Regexp
+}
+
+func newParser(format string) (*parser, error) {
+	var expr string
+	switch format {
+	case CommonFormat:
+		expr = commonFormatExpression
+	case CombinedFormat:
+		expr = combinedFormatExpression
+	default:
+		return nil, fmt.Errorf("unexpected format: %s", format)
+	}
+	return &parser{re: regexp.MustCompile(expr + requestTimeExpression)}, nil
+}
+
+// parse fills the log record with the line as the body and its fields as the attributes.
+// It returns false when the line doesn't match the format, then only the body is set.
+func (p *parser) parse(line string, record pdata.LogRecord) bool {
+	record.Body().SetStringVal(line)
+
+	match := p.re.FindStringSubmatch(line)
+	if match == nil {
+		return false
+	}
+
+	attrs := record.Attributes()
+	for i, name := range p.re.SubexpNames() {
+		value := match[i]
+		if name == "" || value == "" || value == unknownValue {
+			continue
+		}
+
+		switch name {
+		case "client":
+			attrs.InsertString(attributeClientIP, value)
+		case "user":
+			attrs.InsertString(attributeUser, value)
+		case "time":
+			if ts, err := time.Parse(timeLocalLayout, value); err == nil {
+				record.SetTimestamp(pdata.NewTimestampFromTime(ts))
+			}
+		case "request":
+			insertRequestAttributes(attrs, value)
+		case "status":
+			status, _ := strconv.Atoi(value)
+			attrs.InsertInt(attributeStatusCode, int64(status))
+			setSeverity(record, status)
+		case "bytes":
+			if size, err := strconv.ParseInt(value, 10, 64); err == nil {
+				attrs.InsertInt(attributeResponseBodySize, size)
+			}
+		case "referer":
+			attrs.InsertString(attributeReferer, value)
+		case "agent":
+			attrs.InsertString(attributeUserAgent, value)
+		case "request_time":
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+				attrs.InsertDouble(attributeRequestTime, seconds)
+			}
+		}
+	}
+	return true
+}
+
+// insertRequestAttributes splits the request line, e.g. `GET /index.html?page=1 HTTP/1.1`,
+// into the method, target, path and the HTTP version
+func insertRequestAttributes(attrs pdata.AttributeMap, request string) {
+	parts := strings.Split(request, " ")
+	if len(parts) != 3 {
+		return
+	}
+
+	attrs.InsertString(attributeMethod, parts[0])
+	attrs.InsertString(attributeTarget, parts[1])
+	path := parts[1]
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	attrs.InsertString(attributePath, path)
+	attrs.InsertString(attributeFlavor, strings.TrimPrefix(parts[2], "HTTP/"))
+}
+
+// setSeverity sets the severity based on the response status code,
+// so the server errors and the client errors can be told apart from the other requests
+func setSeverity(record pdata.LogRecord, status int) {
+	switch {
+	case status >= 500:
+		record.SetSeverityNumber(pdata.SeverityNumberERROR)
+		record.SetSeverityText("ERROR")
+	case status >= 400:
+		record.SetSeverityNumber(pdata.SeverityNumberWARN)
+		record.SetSeverityText("WARN")
+	default:
+		record.SetSeverityNumber(pdata.SeverityNumberINFO)
+		record.SetSeverityText("INFO")
+	}
+}
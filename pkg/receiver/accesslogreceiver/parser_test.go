@@ -0,0 +1,121 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslogreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestParse(t *testing.T) {
+	testcases := []struct {
+		name             string
+		format           string
+		line             string
+		expectedAttrs    map[string]interface{}
+		expectedSeverity pdata.SeverityNumber
+	}{
+		{
+			name:   "common",
+			format: CommonFormat,
+			line:   `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326`,
+			expectedAttrs: map[string]interface{}{
+				attributeClientIP:         "127.0.0.1",
+				attributeUser:             "frank",
+				attributeMethod:           "GET",
+				attributeTarget:           "/apache_pb.gif",
+				attributePath:             "/apache_pb.gif",
+				attributeFlavor:           "1.0",
+				attributeStatusCode:       int64(200),
+				attributeResponseBodySize: int64(2326),
+			},
+			expectedSeverity: pdata.SeverityNumberINFO,
+		},
+		{
+			name:   "combined",
+			format: CombinedFormat,
+			line:   `10.1.2.3 - - [10/Oct/2000:13:55:36 -0700] "POST /api/v1/orders?id=5 HTTP/1.1" 503 0 "https://example.com/cart" "Mozilla/5.0 (X11; Linux x86_64)"`,
+			expectedAttrs: map[string]interface{}{
+				attributeClientIP:         "10.1.2.3",
+				attributeMethod:           "POST",
+				attributeTarget:           "/api/v1/orders?id=5",
+				attributePath:             "/api/v1/orders",
+				attributeFlavor:           "1.1",
+				attributeStatusCode:       int64(503),
+				attributeResponseBodySize: int64(0),
+				attributeReferer:          "https://example.com/cart",
+				attributeUserAgent:        "Mozilla/5.0 (X11; Linux x86_64)",
+			},
+			expectedSeverity: pdata.SeverityNumberERROR,
+		},
+		{
+			name:   "combined with request time",
+			format: CombinedFormat,
+			line:   `10.1.2.3 - - [10/Oct/2000:13:55:36 -0700] "GET /missing HTTP/2.0" 404 - "-" "curl/7.68.0" 0.012 "-"`,
+			expectedAttrs: map[string]interface{}{
+				attributeClientIP:    "10.1.2.3",
+				attributeMethod:      "GET",
+				attributeTarget:      "/missing",
+				attributePath:        "/missing",
+				attributeFlavor:      "2.0",
+				attributeStatusCode:  int64(404),
+				attributeUserAgent:   "curl/7.68.0",
+				attributeRequestTime: 0.012,
+			},
+			expectedSeverity: pdata.SeverityNumberWARN,
+		},
+		{
+			name:   "escaped quotes and invalid request",
+			format: CombinedFormat,
+			line:   `10.1.2.3 - - [10/Oct/2000:13:55:36 -0700] "\x16\x03\x01" 400 157 "-" "agent \"quoted\""`,
+			expectedAttrs: map[string]interface{}{
+				attributeClientIP:         "10.1.2.3",
+				attributeStatusCode:       int64(400),
+				attributeResponseBodySize: int64(157),
+				attributeUserAgent:        `agent \"quoted\"`,
+			},
+			expectedSeverity: pdata.SeverityNumberWARN,
+		},
+		{
+			name:          "common line in combined format",
+			format:        CombinedFormat,
+			line:          `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326`,
+			expectedAttrs: map[string]interface{}{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := newParser(tc.format)
+			require.NoError(t, err)
+
+			record := pdata.NewLogRecord()
+			matched := p.parse(tc.line, record)
+			assert.Equal(t, len(tc.expectedAttrs) > 0, matched)
+			assert.Equal(t, tc.line, record.Body().StringVal())
+			assert.Equal(t, tc.expectedAttrs, record.Attributes().AsRaw())
+			assert.Equal(t, tc.expectedSeverity, record.SeverityNumber())
+
+			if matched {
+				expected := time.Date(2000, 10, 10, 20, 55, 36, 0, time.UTC)
+				assert.Equal(t, expected, record.Timestamp().AsTime())
+			}
+		})
+	}
+}
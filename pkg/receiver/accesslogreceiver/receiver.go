@@ -0,0 +1,315 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslogreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+const offsetsStorageKey = "offsets"
+
+type accessLogReceiver struct {
+	config   *Config
+	logger   *zap.Logger
+	consumer consumer.Logs
+	parser   *parser
+
+	// readers are the readers of the files matched in the last poll, by the path
+	readers map[string]*fileReader
+	// offsets are the persisted offsets of the files which are not read yet, by the path
+	offsets map[string]int64
+	// started is false until the first poll, which reads the files according to StartAt
+	started bool
+	batch   *logsBatch
+	client  storage.Client
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ component.LogsReceiver = (*accessLogReceiver)(nil)
+
+func newAccessLogReceiver(cfg *Config, logger *zap.Logger, nextConsumer consumer.Logs) *accessLogReceiver {
+	// the format is validated with the configuration
+	p, _ := newParser(cfg.Format)
+	return &accessLogReceiver{
+		config:   cfg,
+		logger:   logger,
+		consumer: nextConsumer,
+		parser:   p,
+		readers:  make(map[string]*fileReader),
+		offsets:  make(map[string]int64),
+		batch:    newLogsBatch(),
+	}
+}
+
+// Start loads the persisted offsets and starts polling the files
+func (r *accessLogReceiver) Start(ctx context.Context, host component.Host) error {
+	if r.config.StorageID != nil {
+		client, err := getStorageClient(ctx, host, *r.config.StorageID, r.config.ID())
+		if err != nil {
+			return err
+		}
+		r.client = client
+
+		data, err := client.Get(ctx, offsetsStorageKey)
+		if err != nil {
+			return fmt.Errorf("failed to load file offsets: %w", err)
+		}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &r.offsets); err != nil {
+				r.logger.Warn("Ignoring invalid persisted file offsets", zap.Error(err))
+			}
+		}
+	}
+
+	rctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go r.run(rctx)
+	return nil
+}
+
+// Shutdown stops polling the files and closes them
+func (r *accessLogReceiver) Shutdown(ctx context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+
+	for path, fr := range r.readers {
+		fr.close()
+		delete(r.readers, path)
+	}
+
+	if r.client != nil {
+		return r.client.Close(ctx)
+	}
+	return nil
+}
+
+func getStorageClient(ctx context.Context, host component.Host, storageID config.ComponentID, id config.ComponentID) (storage.Client, error) {
+	ext, ok := host.GetExtensions()[storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension %s not found", storageID)
+	}
+	se, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("extension %s is not a storage extension", storageID)
+	}
+	client, err := se.GetClient(ctx, component.KindReceiver, id, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage client: %w", err)
+	}
+	return client, nil
+}
+
+// run polls the files every poll interval
+func (r *accessLogReceiver) run(ctx context.Context) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll reads the new lines of the matched files, passes them to the pipeline
+// and persists the offsets of the files
+func (r *accessLogReceiver) poll(ctx context.Context) {
+	matched := make(map[string]bool)
+	for _, path := range r.matchFiles() {
+		matched[path] = true
+
+		fr, ok := r.readers[path]
+		if ok && fr.rotated() {
+			// the rest of the rotated file is read before the new file
+			r.readLines(ctx, fr)
+			fr.close()
+			ok = false
+		}
+		if !ok {
+			var err error
+			fr, err = r.openFile(path)
+			if err != nil {
+				r.logger.Warn("Failed to open file", zap.String("path", path), zap.Error(err))
+				delete(r.readers, path)
+				continue
+			}
+			r.readers[path] = fr
+		}
+		r.readLines(ctx, fr)
+	}
+
+	for path, fr := range r.readers {
+		if !matched[path] {
+			// the file was removed or renamed, e.g. by the rotation
+			r.readLines(ctx, fr)
+			fr.close()
+			delete(r.readers, path)
+		}
+	}
+
+	r.started = true
+	r.flush(ctx)
+	r.persistOffsets(ctx)
+}
+
+// matchFiles returns the sorted paths of the files which match the include patterns
+// and don't match the exclude patterns
+func (r *accessLogReceiver) matchFiles() []string {
+	unique := make(map[string]bool)
+	for _, pattern := range r.config.Include {
+		// the patterns are validated with the configuration
+		paths, _ := filepath.Glob(pattern)
+	paths:
+		for _, path := range paths {
+			for _, exclude := range r.config.Exclude {
+				if excluded, _ := filepath.Match(exclude, path); excluded {
+					continue paths
+				}
+			}
+			unique[path] = true
+		}
+	}
+
+	paths := make([]string, 0, len(unique))
+	for path := range unique {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// openFile opens the file and sets its offset: the persisted offset, the end of the file
+// when it's found on start and start_at is end, or the beginning of the file otherwise
+func (r *accessLogReceiver) openFile(path string) (*fileReader, error) {
+	fr, err := openFileReader(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset, ok := r.offsets[path]; ok {
+		delete(r.offsets, path)
+		if offset <= fr.size() {
+			fr.offset = offset
+		}
+	} else if !r.started && r.config.StartAt == StartAtEnd {
+		fr.offset = fr.size()
+	}
+	return fr, nil
+}
+
+// readLines parses the new lines of the file and adds them to the batch,
+// which is passed to the pipeline whenever it's full
+func (r *accessLogReceiver) readLines(ctx context.Context, fr *fileReader) {
+	err := fr.readLines(func(line string) {
+		if !r.parser.parse(line, r.batch.add(fr.path)) {
+			r.logger.Debug("Line doesn't match the access log format",
+				zap.String("path", fr.path),
+				zap.String("format", r.config.Format),
+			)
+		}
+		if r.batch.count >= r.config.MaxBatchSize {
+			r.flush(ctx)
+		}
+	})
+	if err != nil {
+		r.logger.Warn("Failed to read file", zap.String("path", fr.path), zap.Error(err))
+	}
+}
+
+// flush passes the batch to the pipeline
+func (r *accessLogReceiver) flush(ctx context.Context) {
+	if r.batch.count == 0 {
+		return
+	}
+
+	if err := r.consumer.ConsumeLogs(ctx, r.batch.logs); err != nil {
+		r.logger.Error("ConsumeLogs() error", zap.Error(err))
+	}
+	r.batch = newLogsBatch()
+}
+
+// persistOffsets persists the offsets of the files read so far
+func (r *accessLogReceiver) persistOffsets(ctx context.Context) {
+	if r.client == nil {
+		return
+	}
+
+	offsets := make(map[string]int64, len(r.readers))
+	for path, fr := range r.readers {
+		offsets[path] = fr.offset
+	}
+	data, err := json.Marshal(offsets)
+	if err != nil {
+		r.logger.Warn("Failed to marshal file offsets", zap.Error(err))
+		return
+	}
+	if err := r.client.Set(ctx, offsetsStorageKey, data); err != nil {
+		r.logger.Warn("Failed to persist file offsets", zap.Error(err))
+	}
+}
+
+// logsBatch groups the log records by the file they were read from,
+// which is added as the resource attribute
+type logsBatch struct {
+	logs    pdata.Logs
+	records map[string]pdata.LogRecordSlice
+	count   int
+}
+
+func newLogsBatch() *logsBatch {
+	return &logsBatch{
+		logs:    pdata.NewLogs(),
+		records: make(map[string]pdata.LogRecordSlice),
+	}
+}
+
+// add returns the new log record of the file
+func (b *logsBatch) add(path string) pdata.LogRecord {
+	records, ok := b.records[path]
+	if !ok {
+		rl := b.logs.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().InsertString(attributeFilePath, path)
+		records = rl.InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+		b.records[path] = records
+	}
+
+	b.count++
+	return records.AppendEmpty()
+}
@@ -0,0 +1,191 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesslogreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+// memoryStorage is a storage extension keeping the data in memory
+type memoryStorage struct {
+	component.Extension
+	lock sync.Mutex
+	data map[string][]byte
+}
+
+func (ms *memoryStorage) GetClient(context.Context, component.Kind, config.ComponentID, string) (storage.Client, error) {
+	return &memoryStorageClient{ms: ms}, nil
+}
+
+func (ms *memoryStorage) get(key string) []byte {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	return ms.data[key]
+}
+
+type memoryStorageClient struct {
+	storage.Client
+	ms *memoryStorage
+}
+
+func (c *memoryStorageClient) Get(_ context.Context, key string) ([]byte, error) {
+	return c.ms.get(key), nil
+}
+
+func (c *memoryStorageClient) Set(_ context.Context, key string, value []byte) error {
+	c.ms.lock.Lock()
+	defer c.ms.lock.Unlock()
+	c.ms.data[key] = value
+	return nil
+}
+
+func (c *memoryStorageClient) Close(context.Context) error {
+	return nil
+}
+
+type storageTestHost struct {
+	component.Host
+	extensions map[config.ComponentID]component.Extension
+}
+
+func (h storageTestHost) GetExtensions() map[config.ComponentID]component.Extension {
+	return h.extensions
+}
+
+const (
+	testLine1 = `10.1.2.3 - - [10/Oct/2000:13:55:36 -0700] "GET /1 HTTP/1.1" 200 12 "-" "curl/7.68.0"`
+	testLine2 = `10.1.2.3 - - [10/Oct/2000:13:55:37 -0700] "GET /2 HTTP/1.1" 200 12 "-" "curl/7.68.0"`
+	testLine3 = `10.1.2.3 - - [10/Oct/2000:13:55:38 -0700] "GET /3 HTTP/1.1" 200 12 "-" "curl/7.68.0"`
+)
+
+func logBodies(sink *consumertest.LogsSink) []string {
+	var bodies []string
+	for _, logs := range sink.AllLogs() {
+		rls := logs.ResourceLogs()
+		for i := 0; i < rls.Len(); i++ {
+			records := rls.At(i).InstrumentationLibraryLogs().At(0).LogRecords()
+			for j := 0; j < records.Len(); j++ {
+				bodies = append(bodies, records.At(j).Body().StringVal())
+			}
+		}
+	}
+	return bodies
+}
+
+func TestReceiverPoll(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+	require.NoError(t, os.WriteFile(path, []byte(testLine1+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "error.log"), []byte("error\n"), 0o600))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{filepath.Join(dir, "*.log")}
+	cfg.Exclude = []string{filepath.Join(dir, "error.log")}
+	cfg.MaxBatchSize = 1
+
+	sink := new(consumertest.LogsSink)
+	r := newAccessLogReceiver(cfg, zap.NewNop(), sink)
+	defer r.Shutdown(context.Background())
+
+	// the lines written before the start are skipped with start_at end
+	r.poll(context.Background())
+	assert.Empty(t, logBodies(sink))
+
+	appendToFile(t, path, testLine2+"\n"+testLine3+"\n")
+	r.poll(context.Background())
+	assert.Equal(t, []string{testLine2, testLine3}, logBodies(sink))
+	assert.Len(t, sink.AllLogs(), 2)
+
+	rl := sink.AllLogs()[0].ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{attributeFilePath: path}, rl.Resource().Attributes().AsRaw())
+	record := rl.InstrumentationLibraryLogs().At(0).LogRecords().At(0)
+	status, ok := record.Attributes().Get(attributeStatusCode)
+	require.True(t, ok)
+	assert.EqualValues(t, 200, status.IntVal())
+}
+
+func TestReceiverReadsRotatedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+	require.NoError(t, os.WriteFile(path, []byte(testLine1+"\n"), 0o600))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{path}
+	cfg.StartAt = StartAtBeginning
+
+	sink := new(consumertest.LogsSink)
+	r := newAccessLogReceiver(cfg, zap.NewNop(), sink)
+	defer r.Shutdown(context.Background())
+
+	r.poll(context.Background())
+	assert.Equal(t, []string{testLine1}, logBodies(sink))
+
+	// the line written just before the rotation is read from the rotated file
+	appendToFile(t, path, testLine2+"\n")
+	require.NoError(t, os.Rename(path, path+".1"))
+	appendToFile(t, path, testLine3+"\n")
+
+	r.poll(context.Background())
+	assert.Equal(t, []string{testLine1, testLine2, testLine3}, logBodies(sink))
+}
+
+func TestReceiverResumesFromOffsets(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+	require.NoError(t, os.WriteFile(path, []byte(testLine1+"\n"+testLine2+"\n"), 0o600))
+
+	storageID := config.NewComponentID("storage")
+	offsets, err := json.Marshal(map[string]int64{path: int64(len(testLine1) + 1)})
+	require.NoError(t, err)
+	ms := &memoryStorage{data: map[string][]byte{offsetsStorageKey: offsets}}
+	host := storageTestHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[config.ComponentID]component.Extension{storageID: ms},
+	}
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{path}
+	cfg.StorageID = &storageID
+	cfg.PollInterval = 10 * time.Millisecond
+
+	sink := new(consumertest.LogsSink)
+	r := newAccessLogReceiver(cfg, zap.NewNop(), sink)
+	require.NoError(t, r.Start(context.Background(), host))
+
+	assert.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{testLine2}, logBodies(sink))
+	expected, err := json.Marshal(map[string]int64{path: int64(len(testLine1) + len(testLine2) + 2)})
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(ms.get(offsetsStorageKey)))
+}
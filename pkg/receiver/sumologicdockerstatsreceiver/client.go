@@ -0,0 +1,175 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicdockerstatsreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// container is the container returned by the Docker API list of containers
+type container struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	Created int64             `json:"Created"`
+	Labels  map[string]string `json:"Labels"`
+}
+
+// name returns the name of the container without the leading slash
+func (c container) name() string {
+	if len(c.Names) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// containerStats is the subset of the container stats returned by the Docker API
+type containerStats struct {
+	Read        time.Time               `json:"read"`
+	CPUStats    cpuStats                `json:"cpu_stats"`
+	PreCPUStats cpuStats                `json:"precpu_stats"`
+	MemoryStats memoryStats             `json:"memory_stats"`
+	Networks    map[string]networkStats `json:"networks"`
+	BlkioStats  blkioStats              `json:"blkio_stats"`
+}
+
+type cpuStats struct {
+	CPUUsage struct {
+		TotalUsage        uint64   `json:"total_usage"`
+		PercpuUsage       []uint64 `json:"percpu_usage"`
+		UsageInKernelmode uint64   `json:"usage_in_kernelmode"`
+		UsageInUsermode   uint64   `json:"usage_in_usermode"`
+	} `json:"cpu_usage"`
+	SystemUsage    uint64 `json:"system_cpu_usage"`
+	OnlineCPUs     uint32 `json:"online_cpus"`
+	ThrottlingData struct {
+		Periods          uint64 `json:"periods"`
+		ThrottledPeriods uint64 `json:"throttled_periods"`
+		ThrottledTime    uint64 `json:"throttled_time"`
+	} `json:"throttling_data"`
+}
+
+type memoryStats struct {
+	Usage uint64            `json:"usage"`
+	Limit uint64            `json:"limit"`
+	Stats map[string]uint64 `json:"stats"`
+}
+
+type networkStats struct {
+	RxBytes   uint64 `json:"rx_bytes"`
+	RxPackets uint64 `json:"rx_packets"`
+	RxErrors  uint64 `json:"rx_errors"`
+	RxDropped uint64 `json:"rx_dropped"`
+	TxBytes   uint64 `json:"tx_bytes"`
+	TxPackets uint64 `json:"tx_packets"`
+	TxErrors  uint64 `json:"tx_errors"`
+	TxDropped uint64 `json:"tx_dropped"`
+}
+
+type blkioStats struct {
+	IoServiceBytesRecursive []blkioStatEntry `json:"io_service_bytes_recursive"`
+}
+
+type blkioStatEntry struct {
+	Major uint64 `json:"major"`
+	Minor uint64 `json:"minor"`
+	Op    string `json:"op"`
+	Value uint64 `json:"value"`
+}
+
+// dockerClient is a minimal client of the Docker Engine API
+type dockerClient struct {
+	client  *http.Client
+	baseURL string
+}
+
+func newDockerClient(endpoint string, apiVersion string, timeout time.Duration) (*dockerClient, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+
+	transport := &http.Transport{}
+	var baseURL string
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}
+		// The host is ignored when dialing the unix socket
+		baseURL = "http://docker"
+	case "tcp":
+		baseURL = "http://" + u.Host
+	case "http", "https":
+		baseURL = u.Scheme + "://" + u.Host
+	default:
+		return nil, fmt.Errorf("unsupported endpoint scheme %q, expected unix, tcp, http or https", u.Scheme)
+	}
+
+	return &dockerClient{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+		},
+		baseURL: baseURL + "/v" + apiVersion,
+	}, nil
+}
+
+// containers returns the running containers
+func (c *dockerClient) containers(ctx context.Context) ([]container, error) {
+	var containers []container
+	if err := c.get(ctx, "/containers/json", &containers); err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	return containers, nil
+}
+
+// stats returns the current stats of the container
+func (c *dockerClient) stats(ctx context.Context, id string) (containerStats, error) {
+	var stats containerStats
+	if err := c.get(ctx, "/containers/"+url.PathEscape(id)+"/stats?stream=false", &stats); err != nil {
+		return stats, fmt.Errorf("failed to get stats of container %s: %w", id, err)
+	}
+	return stats, nil
+}
+
+func (c *dockerClient) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
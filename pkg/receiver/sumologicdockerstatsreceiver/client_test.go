@@ -0,0 +1,136 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicdockerstatsreceiver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDockerTestServer returns the server serving the Docker API responses from testdata
+func newDockerTestServer(t *testing.T) *httptest.Server {
+	containers, err := os.ReadFile(filepath.Join("testdata", "containers.json"))
+	require.NoError(t, err)
+	stats, err := os.ReadFile(filepath.Join("testdata", "stats.json"))
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.URL.Path == "/v1.22/containers/json":
+			_, _ = w.Write(containers)
+		case strings.HasPrefix(req.URL.Path, "/v1.22/containers/") && strings.HasSuffix(req.URL.Path, "/stats"):
+			assert.Equal(t, "false", req.URL.Query().Get("stream"))
+			_, _ = w.Write(stats)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"page not found"}`))
+		}
+	}))
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClientContainers(t *testing.T) {
+	srv := newDockerTestServer(t)
+
+	client, err := newDockerClient(srv.URL, "1.22", time.Second)
+	require.NoError(t, err)
+
+	containers, err := client.containers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, containers, 2)
+	assert.Equal(t, "a1b2c3d4e5f6", containers[0].ID)
+	assert.Equal(t, "web", containers[0].name())
+	assert.Equal(t, "nginx:1.21", containers[0].Image)
+	assert.Equal(t, int64(1650000000), containers[0].Created)
+	assert.Equal(t, "frontend", containers[0].Labels["com.docker.compose.service"])
+}
+
+func TestClientStats(t *testing.T) {
+	srv := newDockerTestServer(t)
+
+	client, err := newDockerClient(srv.URL, "1.22", time.Second)
+	require.NoError(t, err)
+
+	stats, err := client.stats(context.Background(), "a1b2c3d4e5f6")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2022, 4, 15, 10, 0, 10, 0, time.UTC), stats.Read)
+	assert.Equal(t, uint64(2000000000), stats.CPUStats.CPUUsage.TotalUsage)
+	assert.Equal(t, uint64(104857600), stats.MemoryStats.Usage)
+	assert.Equal(t, uint64(100), stats.Networks["eth0"].RxBytes)
+	assert.Len(t, stats.BlkioStats.IoServiceBytesRecursive, 3)
+}
+
+func TestClientUnexpectedStatus(t *testing.T) {
+	srv := newDockerTestServer(t)
+
+	client, err := newDockerClient(srv.URL, "1.40", time.Second)
+	require.NoError(t, err)
+
+	_, err = client.containers(context.Background())
+	assert.EqualError(t, err, `failed to list containers: unexpected status 404 Not Found: {"message":"page not found"}`)
+}
+
+func TestClientUnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "docker")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	srv := newDockerTestServer(t)
+	unixSrv := &http.Server{Handler: srv.Config.Handler}
+	go func() { _ = unixSrv.Serve(listener) }()
+	t.Cleanup(func() { unixSrv.Close() })
+
+	client, err := newDockerClient("unix://"+socket, "1.22", time.Second)
+	require.NoError(t, err)
+
+	containers, err := client.containers(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, containers, 2)
+}
+
+func TestNewClientEndpoints(t *testing.T) {
+	testcases := []struct {
+		endpoint        string
+		expectedBaseURL string
+	}{
+		{endpoint: "unix:///var/run/docker.sock", expectedBaseURL: "http://docker/v1.22"},
+		{endpoint: "tcp://localhost:2375", expectedBaseURL: "http://localhost:2375/v1.22"},
+		{endpoint: "https://docker.example.com:2376", expectedBaseURL: "https://docker.example.com:2376/v1.22"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.endpoint, func(t *testing.T) {
+			client, err := newDockerClient(tc.endpoint, "1.22", time.Second)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedBaseURL, client.baseURL)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicdockerstatsreceiver
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the Docker stats receiver.
+type Config struct {
+	config.ReceiverSettings `mapstructure:",squash"`
+
+	// Endpoint is the address of the Docker daemon, either a unix socket (unix:///var/run/docker.sock)
+	// or a TCP address (tcp://localhost:2375).
+	// By default this is unix:///var/run/docker.sock.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// APIVersion is the version of the Docker API used.
+	// By default this is 1.22.
+	APIVersion string `mapstructure:"api_version"`
+
+	// CollectionInterval defines how often the stats of the containers are collected.
+	// By default this is 10s.
+	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+
+	// Timeout is the timeout of a single request to the Docker API.
+	// By default this is 5s.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// ExcludedImages is the list of the image name patterns (e.g. redis or quay.io/*)
+	// of the containers to be excluded from the collection.
+	ExcludedImages []string `mapstructure:"excluded_images"`
+
+	// ContainerLabelsToAttributes maps the container labels to the resource attributes
+	// set on the metrics of the container.
+	ContainerLabelsToAttributes map[string]string `mapstructure:"container_labels_to_attributes"`
+}
+
+var _ config.Receiver = (*Config)(nil)
+
+// Validate checks if the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.Endpoint == "" {
+		return errors.New("endpoint cannot be empty")
+	}
+	if cfg.APIVersion == "" {
+		return errors.New("api_version cannot be empty")
+	}
+	if cfg.CollectionInterval <= 0 {
+		return fmt.Errorf("collection_interval has to be positive: %s", cfg.CollectionInterval)
+	}
+	if cfg.Timeout <= 0 {
+		return fmt.Errorf("timeout has to be positive: %s", cfg.Timeout)
+	}
+	for _, pattern := range cfg.ExcludedImages {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid excluded_images pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
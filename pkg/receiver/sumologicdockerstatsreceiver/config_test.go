@@ -0,0 +1,108 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicdockerstatsreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Receivers[config.NewComponentID(typeStr)])
+
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings:   config.NewReceiverSettings(config.NewComponentIDWithName(typeStr, "custom")),
+			Endpoint:           "tcp://localhost:2375",
+			APIVersion:         "1.40",
+			CollectionInterval: 30 * time.Second,
+			Timeout:            10 * time.Second,
+			ExcludedImages:     []string{"redis", "quay.io/*"},
+			ContainerLabelsToAttributes: map[string]string{
+				"com.docker.compose.service": "service.name",
+			},
+		},
+		cfg.Receivers[config.NewComponentIDWithName(typeStr, "custom")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		modify        func(cfg *Config)
+		expectedError string
+	}{
+		{
+			name:   "default config",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:          "empty endpoint",
+			modify:        func(cfg *Config) { cfg.Endpoint = "" },
+			expectedError: "endpoint cannot be empty",
+		},
+		{
+			name:          "empty api_version",
+			modify:        func(cfg *Config) { cfg.APIVersion = "" },
+			expectedError: "api_version cannot be empty",
+		},
+		{
+			name:          "non-positive collection_interval",
+			modify:        func(cfg *Config) { cfg.CollectionInterval = 0 },
+			expectedError: "collection_interval has to be positive: 0s",
+		},
+		{
+			name:          "non-positive timeout",
+			modify:        func(cfg *Config) { cfg.Timeout = -time.Second },
+			expectedError: "timeout has to be positive: -1s",
+		},
+		{
+			name:          "invalid excluded image pattern",
+			modify:        func(cfg *Config) { cfg.ExcludedImages = []string{"[redis"} },
+			expectedError: `invalid excluded_images pattern "[redis": syntax error in pattern`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
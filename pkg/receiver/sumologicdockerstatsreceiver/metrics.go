@@ -0,0 +1,232 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicdockerstatsreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	instrumentationLibraryName = "github.com/SumoLogic/sumologic-otel-collector/pkg/receiver/sumologicdockerstatsreceiver"
+
+	attributeContainerID        = "container.id"
+	attributeContainerName      = "container.name"
+	attributeContainerImageName = "container.image.name"
+	attributeContainerImageTag  = "container.image.tag"
+	attributeContainerRuntime   = "container.runtime"
+
+	attributeNetworkInterface = "interface"
+	attributeDevice           = "device"
+
+	containerRuntime = "docker"
+)
+
+// parseImage splits the image reference into the name and the tag,
+// e.g. registry:5000/app:1.0 into registry:5000/app and 1.0
+func parseImage(image string) (string, string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, "latest"
+}
+
+// metricsBuilder appends the metrics of the containers to the pdata.Metrics
+type metricsBuilder struct {
+	metrics         pdata.Metrics
+	labelAttributes map[string]string
+}
+
+func newMetricsBuilder(labelAttributes map[string]string) *metricsBuilder {
+	return &metricsBuilder{
+		metrics:         pdata.NewMetrics(),
+		labelAttributes: labelAttributes,
+	}
+}
+
+// append appends the metrics of the container calculated from its stats
+func (mb *metricsBuilder) append(c container, stats containerStats) {
+	rm := mb.metrics.ResourceMetrics().AppendEmpty()
+	attrs := rm.Resource().Attributes()
+	imageName, imageTag := parseImage(c.Image)
+	attrs.InsertString(attributeContainerID, c.ID)
+	attrs.InsertString(attributeContainerName, c.name())
+	attrs.InsertString(attributeContainerImageName, imageName)
+	attrs.InsertString(attributeContainerImageTag, imageTag)
+	attrs.InsertString(attributeContainerRuntime, containerRuntime)
+	for label, attribute := range mb.labelAttributes {
+		if value, ok := c.Labels[label]; ok {
+			attrs.InsertString(attribute, value)
+		}
+	}
+
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(instrumentationLibraryName)
+	ms := ilm.Metrics()
+
+	ts := pdata.NewTimestampFromTime(stats.Read)
+	start := pdata.NewTimestampFromTime(time.Unix(c.Created, 0))
+
+	cpu := stats.CPUStats
+	appendGauge(ms, "docker_container_cpu_usage_percent", "%", ts, cpuPercent(stats))
+	appendSum(ms, "docker_container_cpu_usage_total", "ns", start, ts, cpu.CPUUsage.TotalUsage)
+	appendSum(ms, "docker_container_cpu_usage_in_kernelmode", "ns", start, ts, cpu.CPUUsage.UsageInKernelmode)
+	appendSum(ms, "docker_container_cpu_usage_in_usermode", "ns", start, ts, cpu.CPUUsage.UsageInUsermode)
+	appendSum(ms, "docker_container_cpu_throttling_periods", "1", start, ts, cpu.ThrottlingData.Periods)
+	appendSum(ms, "docker_container_cpu_throttling_throttled_periods", "1", start, ts, cpu.ThrottlingData.ThrottledPeriods)
+	appendSum(ms, "docker_container_cpu_throttling_throttled_time", "ns", start, ts, cpu.ThrottlingData.ThrottledTime)
+
+	memUsage := memoryUsage(stats.MemoryStats)
+	appendIntGauge(ms, "docker_container_mem_usage", "By", ts, memUsage)
+	appendIntGauge(ms, "docker_container_mem_limit", "By", ts, stats.MemoryStats.Limit)
+	if stats.MemoryStats.Limit > 0 {
+		appendGauge(ms, "docker_container_mem_usage_percent", "%", ts, float64(memUsage)/float64(stats.MemoryStats.Limit)*100)
+	}
+
+	netMetrics := []struct {
+		name  string
+		unit  string
+		value func(networkStats) uint64
+	}{
+		{"docker_container_net_rx_bytes", "By", func(n networkStats) uint64 { return n.RxBytes }},
+		{"docker_container_net_rx_packets", "1", func(n networkStats) uint64 { return n.RxPackets }},
+		{"docker_container_net_rx_errors", "1", func(n networkStats) uint64 { return n.RxErrors }},
+		{"docker_container_net_rx_dropped", "1", func(n networkStats) uint64 { return n.RxDropped }},
+		{"docker_container_net_tx_bytes", "By", func(n networkStats) uint64 { return n.TxBytes }},
+		{"docker_container_net_tx_packets", "1", func(n networkStats) uint64 { return n.TxPackets }},
+		{"docker_container_net_tx_errors", "1", func(n networkStats) uint64 { return n.TxErrors }},
+		{"docker_container_net_tx_dropped", "1", func(n networkStats) uint64 { return n.TxDropped }},
+	}
+	if len(stats.Networks) > 0 {
+		ifaces := make([]string, 0, len(stats.Networks))
+		for iface := range stats.Networks {
+			ifaces = append(ifaces, iface)
+		}
+		sort.Strings(ifaces)
+
+		for _, nm := range netMetrics {
+			values := make([]uint64, 0, len(ifaces))
+			for _, iface := range ifaces {
+				values = append(values, nm.value(stats.Networks[iface]))
+			}
+			appendSumPerAttribute(ms, nm.name, nm.unit, start, ts, attributeNetworkInterface, ifaces, values)
+		}
+	}
+
+	var (
+		readDevices, writeDevices []string
+		readValues, writeValues   []uint64
+	)
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		device := fmt.Sprintf("%d:%d", entry.Major, entry.Minor)
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			readDevices = append(readDevices, device)
+			readValues = append(readValues, entry.Value)
+		case "write":
+			writeDevices = append(writeDevices, device)
+			writeValues = append(writeValues, entry.Value)
+		}
+	}
+	if len(readDevices) > 0 {
+		appendSumPerAttribute(ms, "docker_container_blkio_io_service_bytes_recursive_read", "By", start, ts, attributeDevice, readDevices, readValues)
+	}
+	if len(writeDevices) > 0 {
+		appendSumPerAttribute(ms, "docker_container_blkio_io_service_bytes_recursive_write", "By", start, ts, attributeDevice, writeDevices, writeValues)
+	}
+}
+
+// cpuPercent calculates the CPU usage of the container since the previous stats
+// the same way as docker stats does
+func cpuPercent(stats containerStats) float64 {
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+
+	onlineCPUs := float64(stats.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+// memoryUsage returns the memory usage of the container without the page cache
+// the same way as docker stats does
+func memoryUsage(stats memoryStats) uint64 {
+	// cgroup v1
+	if inactive, ok := stats.Stats["total_inactive_file"]; ok && inactive < stats.Usage {
+		return stats.Usage - inactive
+	}
+	// cgroup v2
+	if inactive, ok := stats.Stats["inactive_file"]; ok && inactive < stats.Usage {
+		return stats.Usage - inactive
+	}
+	return stats.Usage
+}
+
+func appendGauge(ms pdata.MetricSlice, name string, unit string, ts pdata.Timestamp, value float64) {
+	m := ms.AppendEmpty()
+	m.SetName(name)
+	m.SetUnit(unit)
+	m.SetDataType(pdata.MetricDataTypeGauge)
+	dp := m.Gauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetDoubleVal(value)
+}
+
+func appendIntGauge(ms pdata.MetricSlice, name string, unit string, ts pdata.Timestamp, value uint64) {
+	m := ms.AppendEmpty()
+	m.SetName(name)
+	m.SetUnit(unit)
+	m.SetDataType(pdata.MetricDataTypeGauge)
+	dp := m.Gauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(int64(value))
+}
+
+// appendSum appends the cumulative monotonic sum with a single data point
+func appendSum(ms pdata.MetricSlice, name string, unit string, start pdata.Timestamp, ts pdata.Timestamp, value uint64) {
+	appendSumPerAttribute(ms, name, unit, start, ts, "", nil, []uint64{value})
+}
+
+// appendSumPerAttribute appends the cumulative monotonic sum with a data point per value,
+// the data points are distinguished by the attribute when attributeKey is set
+func appendSumPerAttribute(ms pdata.MetricSlice, name string, unit string, start pdata.Timestamp, ts pdata.Timestamp, attributeKey string, attributeValues []string, values []uint64) {
+	m := ms.AppendEmpty()
+	m.SetName(name)
+	m.SetUnit(unit)
+	m.SetDataType(pdata.MetricDataTypeSum)
+	sum := m.Sum()
+	sum.SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	sum.SetIsMonotonic(true)
+	for i, value := range values {
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(start)
+		dp.SetTimestamp(ts)
+		dp.SetIntVal(int64(value))
+		if attributeKey != "" {
+			dp.Attributes().InsertString(attributeKey, attributeValues[i])
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicdockerstatsreceiver
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func loadTestStats(t *testing.T) containerStats {
+	data, err := os.ReadFile(filepath.Join("testdata", "stats.json"))
+	require.NoError(t, err)
+
+	var stats containerStats
+	require.NoError(t, json.Unmarshal(data, &stats))
+	return stats
+}
+
+func TestParseImage(t *testing.T) {
+	testcases := []struct {
+		image        string
+		expectedName string
+		expectedTag  string
+	}{
+		{image: "nginx", expectedName: "nginx", expectedTag: "latest"},
+		{image: "nginx:1.21", expectedName: "nginx", expectedTag: "1.21"},
+		{image: "registry:5000/app", expectedName: "registry:5000/app", expectedTag: "latest"},
+		{image: "registry:5000/app:1.0", expectedName: "registry:5000/app", expectedTag: "1.0"},
+		{image: "app:1.0@sha256:abcdef", expectedName: "app", expectedTag: "1.0"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.image, func(t *testing.T) {
+			name, tag := parseImage(tc.image)
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedTag, tag)
+		})
+	}
+}
+
+func TestCPUPercent(t *testing.T) {
+	stats := loadTestStats(t)
+	assert.InDelta(t, 20.0, cpuPercent(stats), 0.001)
+
+	stats.CPUStats.OnlineCPUs = 0
+	assert.InDelta(t, 20.0, cpuPercent(stats), 0.001, "percpu_usage should be used when online_cpus is missing")
+
+	stats.PreCPUStats = cpuStats{}
+	stats.CPUStats.SystemUsage = 0
+	assert.Equal(t, 0.0, cpuPercent(stats))
+}
+
+func TestMemoryUsage(t *testing.T) {
+	assert.Equal(t, uint64(60), memoryUsage(memoryStats{Usage: 100, Stats: map[string]uint64{"total_inactive_file": 40}}))
+	assert.Equal(t, uint64(70), memoryUsage(memoryStats{Usage: 100, Stats: map[string]uint64{"inactive_file": 30}}))
+	assert.Equal(t, uint64(100), memoryUsage(memoryStats{Usage: 100}))
+}
+
+func TestMetricsBuilder(t *testing.T) {
+	c := container{
+		ID:      "a1b2c3d4e5f6",
+		Names:   []string{"/web"},
+		Image:   "nginx:1.21",
+		Created: 1650000000,
+		Labels:  map[string]string{"com.docker.compose.service": "frontend"},
+	}
+
+	mb := newMetricsBuilder(map[string]string{
+		"com.docker.compose.service": "service.name",
+		"missing":                    "missing",
+	})
+	mb.append(c, loadTestStats(t))
+
+	require.Equal(t, 1, mb.metrics.ResourceMetrics().Len())
+	rm := mb.metrics.ResourceMetrics().At(0)
+	assert.Equal(t, map[string]interface{}{
+		"container.id":         "a1b2c3d4e5f6",
+		"container.name":       "web",
+		"container.image.name": "nginx",
+		"container.image.tag":  "1.21",
+		"container.runtime":    "docker",
+		"service.name":         "frontend",
+	}, rm.Resource().Attributes().AsRaw())
+
+	metrics := map[string]pdata.Metric{}
+	ms := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		metrics[ms.At(i).Name()] = ms.At(i)
+	}
+	assert.Len(t, metrics, ms.Len(), "metric names should be unique")
+
+	ts := pdata.NewTimestampFromTime(time.Date(2022, 4, 15, 10, 0, 10, 0, time.UTC))
+	start := pdata.NewTimestampFromTime(time.Unix(1650000000, 0))
+
+	cpuPercent := metrics["docker_container_cpu_usage_percent"].Gauge().DataPoints().At(0)
+	assert.InDelta(t, 20.0, cpuPercent.DoubleVal(), 0.001)
+	assert.Equal(t, ts, cpuPercent.Timestamp())
+
+	cpuTotal := metrics["docker_container_cpu_usage_total"]
+	assert.Equal(t, pdata.MetricDataTypeSum, cpuTotal.DataType())
+	assert.True(t, cpuTotal.Sum().IsMonotonic())
+	assert.Equal(t, int64(2000000000), cpuTotal.Sum().DataPoints().At(0).IntVal())
+	assert.Equal(t, start, cpuTotal.Sum().DataPoints().At(0).StartTimestamp())
+
+	assert.Equal(t, int64(2), metrics["docker_container_cpu_throttling_throttled_periods"].Sum().DataPoints().At(0).IntVal())
+	assert.Equal(t, int64(52428800), metrics["docker_container_mem_usage"].Gauge().DataPoints().At(0).IntVal())
+	assert.Equal(t, int64(209715200), metrics["docker_container_mem_limit"].Gauge().DataPoints().At(0).IntVal())
+	assert.InDelta(t, 25.0, metrics["docker_container_mem_usage_percent"].Gauge().DataPoints().At(0).DoubleVal(), 0.001)
+
+	rxBytes := metrics["docker_container_net_rx_bytes"].Sum().DataPoints()
+	require.Equal(t, 2, rxBytes.Len())
+	assert.Equal(t, map[string]interface{}{"interface": "eth0"}, rxBytes.At(0).Attributes().AsRaw())
+	assert.Equal(t, int64(100), rxBytes.At(0).IntVal())
+	assert.Equal(t, map[string]interface{}{"interface": "eth1"}, rxBytes.At(1).Attributes().AsRaw())
+	assert.Equal(t, int64(300), rxBytes.At(1).IntVal())
+	assert.Equal(t, "By", metrics["docker_container_net_rx_bytes"].Unit())
+	assert.Equal(t, "1", metrics["docker_container_net_tx_dropped"].Unit())
+
+	read := metrics["docker_container_blkio_io_service_bytes_recursive_read"].Sum().DataPoints()
+	require.Equal(t, 1, read.Len())
+	assert.Equal(t, map[string]interface{}{"device": "8:0"}, read.At(0).Attributes().AsRaw())
+	assert.Equal(t, int64(4096), read.At(0).IntVal())
+	assert.Equal(t, int64(8192), metrics["docker_container_blkio_io_service_bytes_recursive_write"].Sum().DataPoints().At(0).IntVal())
+}
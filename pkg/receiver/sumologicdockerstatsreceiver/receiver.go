@@ -0,0 +1,167 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicdockerstatsreceiver
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.uber.org/zap"
+)
+
+type dockerStatsReceiver struct {
+	config   *Config
+	logger   *zap.Logger
+	consumer consumer.Metrics
+	client   *dockerClient
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ component.MetricsReceiver = (*dockerStatsReceiver)(nil)
+
+func newDockerStatsReceiver(cfg *Config, logger *zap.Logger, nextConsumer consumer.Metrics) (*dockerStatsReceiver, error) {
+	client, err := newDockerClient(cfg.Endpoint, cfg.APIVersion, cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dockerStatsReceiver{
+		config:   cfg,
+		logger:   logger,
+		consumer: nextConsumer,
+		client:   client,
+	}, nil
+}
+
+// Start starts collecting the stats of the containers
+func (r *dockerStatsReceiver) Start(_ context.Context, _ component.Host) error {
+	rctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go r.run(rctx)
+	return nil
+}
+
+// Shutdown stops collecting the stats of the containers
+func (r *dockerStatsReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+	return nil
+}
+
+// run collects the stats every collection interval
+func (r *dockerStatsReceiver) run(ctx context.Context) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.config.CollectionInterval)
+	defer ticker.Stop()
+
+	for {
+		r.collect(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect collects the stats of the running containers and passes them to the pipeline
+func (r *dockerStatsReceiver) collect(ctx context.Context) {
+	containers, err := r.client.containers(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("Failed to collect the container stats", zap.Error(err))
+		}
+		return
+	}
+
+	containers = r.filterContainers(containers)
+	stats := make([]*containerStats, len(containers))
+
+	// Docker waits for the second sample of the CPU usage before it returns the stats,
+	// so the stats of the containers are fetched concurrently
+	var wg sync.WaitGroup
+	for i, c := range containers {
+		wg.Add(1)
+		go func(i int, c container) {
+			defer wg.Done()
+
+			s, err := r.client.stats(ctx, c.ID)
+			if err != nil {
+				if ctx.Err() == nil {
+					r.logger.Warn("Failed to get the container stats",
+						zap.String("container_id", c.ID),
+						zap.String("container_name", c.name()),
+						zap.Error(err),
+					)
+				}
+				return
+			}
+			stats[i] = &s
+		}(i, c)
+	}
+	wg.Wait()
+
+	mb := newMetricsBuilder(r.config.ContainerLabelsToAttributes)
+	for i, c := range containers {
+		if stats[i] != nil {
+			mb.append(c, *stats[i])
+		}
+	}
+	if mb.metrics.ResourceMetrics().Len() == 0 {
+		return
+	}
+
+	if err := r.consumer.ConsumeMetrics(ctx, mb.metrics); err != nil {
+		r.logger.Error("Failed to pass the container stats to the pipeline", zap.Error(err))
+	}
+}
+
+// filterContainers returns the containers whose images are not excluded
+func (r *dockerStatsReceiver) filterContainers(containers []container) []container {
+	if len(r.config.ExcludedImages) == 0 {
+		return containers
+	}
+
+	filtered := containers[:0]
+	for _, c := range containers {
+		if !r.isExcluded(c) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+func (r *dockerStatsReceiver) isExcluded(c container) bool {
+	imageName, _ := parseImage(c.Image)
+	for _, pattern := range r.config.ExcludedImages {
+		// the patterns are validated in the config
+		if ok, _ := path.Match(pattern, imageName); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicdockerstatsreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+)
+
+func TestReceiverCollectsStats(t *testing.T) {
+	srv := newDockerTestServer(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = srv.URL
+	cfg.CollectionInterval = time.Hour
+
+	sink := new(consumertest.MetricsSink)
+	r, err := newDockerStatsReceiver(cfg, zap.NewNop(), sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, r.Shutdown(context.Background())) })
+
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	rms := sink.AllMetrics()[0].ResourceMetrics()
+	require.Equal(t, 2, rms.Len())
+	name, _ := rms.At(0).Resource().Attributes().Get("container.name")
+	assert.Equal(t, "web", name.StringVal())
+	name, _ = rms.At(1).Resource().Attributes().Get("container.name")
+	assert.Equal(t, "cache", name.StringVal())
+}
+
+func TestReceiverExcludedImages(t *testing.T) {
+	srv := newDockerTestServer(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = srv.URL
+	cfg.ExcludedImages = []string{"redis", "quay.io/*"}
+
+	sink := new(consumertest.MetricsSink)
+	r, err := newDockerStatsReceiver(cfg, zap.NewNop(), sink)
+	require.NoError(t, err)
+
+	r.collect(context.Background())
+
+	require.Len(t, sink.AllMetrics(), 1)
+	rms := sink.AllMetrics()[0].ResourceMetrics()
+	require.Equal(t, 1, rms.Len())
+	image, _ := rms.At(0).Resource().Attributes().Get("container.image.name")
+	assert.Equal(t, "nginx", image.StringVal())
+}
+
+func TestReceiverDockerUnavailable(t *testing.T) {
+	srv := newDockerTestServer(t)
+	srv.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = srv.URL
+
+	sink := new(consumertest.MetricsSink)
+	r, err := newDockerStatsReceiver(cfg, zap.NewNop(), sink)
+	require.NoError(t, err)
+
+	r.collect(context.Background())
+	assert.Empty(t, sink.AllMetrics())
+}
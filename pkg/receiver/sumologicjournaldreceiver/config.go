@@ -0,0 +1,100 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicjournaldreceiver
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+const (
+	// StartAtBeginning makes the receiver read the whole journal when there is no persisted cursor
+	StartAtBeginning = "beginning"
+	// StartAtEnd makes the receiver read only the new entries when there is no persisted cursor
+	StartAtEnd = "end"
+)
+
+// Config defines configuration for the journald receiver.
+type Config struct {
+	config.ReceiverSettings `mapstructure:",squash"`
+
+	// JournalctlPath is the path of the journalctl binary used to read the journal.
+	// By default this is journalctl.
+	JournalctlPath string `mapstructure:"journalctl_path"`
+
+	// Directory is the journal directory to read from, e.g. /var/log/journal.
+	// By default the system journal is read.
+	Directory string `mapstructure:"directory"`
+
+	// Files is the list of journal files to read from.
+	// It cannot be used together with Directory.
+	Files []string `mapstructure:"files"`
+
+	// Units limits the entries to the ones of the listed systemd units.
+	Units []string `mapstructure:"units"`
+
+	// Priority is the lowest priority of the entries which are read,
+	// either the name (e.g. info) or the number (e.g. 6).
+	// By default this is info.
+	Priority string `mapstructure:"priority"`
+
+	// StartAt defines where to start reading the journal when there is no persisted cursor,
+	// either beginning or end.
+	// By default this is end.
+	StartAt string `mapstructure:"start_at"`
+
+	// StorageID is the ID of the storage extension used to persist the journal cursor,
+	// so the receiver resumes from the last read entry after restart.
+	// When not set, the cursor is kept in memory only.
+	StorageID *config.ComponentID `mapstructure:"storage"`
+
+	// MaxBatchSize is the maximum number of the journal entries passed to the pipeline at once.
+	// By default this is 100.
+	MaxBatchSize int `mapstructure:"max_batch_size"`
+
+	// RestartDelay defines how long to wait before journalctl is restarted after it exited.
+	// By default this is 5s.
+	RestartDelay time.Duration `mapstructure:"restart_delay"`
+}
+
+var _ config.Receiver = (*Config)(nil)
+
+// Validate checks if the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.JournalctlPath == "" {
+		return errors.New("journalctl_path cannot be empty")
+	}
+	if cfg.Directory != "" && len(cfg.Files) > 0 {
+		return errors.New("only one of directory and files can be specified")
+	}
+	if _, ok := parsePriority(cfg.Priority); !ok {
+		return fmt.Errorf("unexpected priority: %s", cfg.Priority)
+	}
+	switch cfg.StartAt {
+	case StartAtBeginning, StartAtEnd:
+	default:
+		return fmt.Errorf("unexpected start_at: %s", cfg.StartAt)
+	}
+	if cfg.MaxBatchSize <= 0 {
+		return fmt.Errorf("max_batch_size has to be positive: %d", cfg.MaxBatchSize)
+	}
+	if cfg.RestartDelay <= 0 {
+		return fmt.Errorf("restart_delay has to be positive: %s", cfg.RestartDelay)
+	}
+	return nil
+}
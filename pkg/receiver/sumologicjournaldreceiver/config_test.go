@@ -0,0 +1,121 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicjournaldreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Receivers[config.NewComponentID(typeStr)])
+
+	storageID := config.NewComponentID("file_storage")
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: config.NewReceiverSettings(config.NewComponentIDWithName(typeStr, "custom")),
+			JournalctlPath:   "/usr/bin/journalctl",
+			Directory:        "/var/log/journal",
+			Units:            []string{"sshd.service", "cron.service"},
+			Priority:         "warning",
+			StartAt:          StartAtBeginning,
+			StorageID:        &storageID,
+			MaxBatchSize:     50,
+			RestartDelay:     10 * time.Second,
+		},
+		cfg.Receivers[config.NewComponentIDWithName(typeStr, "custom")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		modify        func(cfg *Config)
+		expectedError string
+	}{
+		{
+			name:   "default config",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:   "numeric priority",
+			modify: func(cfg *Config) { cfg.Priority = "4" },
+		},
+		{
+			name:          "empty journalctl path",
+			modify:        func(cfg *Config) { cfg.JournalctlPath = "" },
+			expectedError: "journalctl_path cannot be empty",
+		},
+		{
+			name: "directory and files",
+			modify: func(cfg *Config) {
+				cfg.Directory = "/var/log/journal"
+				cfg.Files = []string{"/var/log/journal/system.journal"}
+			},
+			expectedError: "only one of directory and files can be specified",
+		},
+		{
+			name:          "unexpected priority",
+			modify:        func(cfg *Config) { cfg.Priority = "8" },
+			expectedError: "unexpected priority: 8",
+		},
+		{
+			name:          "unexpected start_at",
+			modify:        func(cfg *Config) { cfg.StartAt = "middle" },
+			expectedError: "unexpected start_at: middle",
+		},
+		{
+			name:          "non-positive max_batch_size",
+			modify:        func(cfg *Config) { cfg.MaxBatchSize = 0 },
+			expectedError: "max_batch_size has to be positive: 0",
+		},
+		{
+			name:          "non-positive restart_delay",
+			modify:        func(cfg *Config) { cfg.RestartDelay = 0 },
+			expectedError: "restart_delay has to be positive: 0s",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicjournaldreceiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	fieldMessage            = "MESSAGE"
+	fieldPriority           = "PRIORITY"
+	fieldCursor             = "__CURSOR"
+	fieldRealtimeTimestamp  = "__REALTIME_TIMESTAMP"
+	fieldMonotonicTimestamp = "__MONOTONIC_TIMESTAMP"
+	fieldHostname           = "_HOSTNAME"
+	fieldSystemdUnit        = "_SYSTEMD_UNIT"
+
+	// attributeHostName is the resource attribute with the hostname of the entry,
+	// translated to `host` by the sumologicexporter
+	attributeHostName = "host.name"
+	// attributeSystemdUnit is the resource attribute with the systemd unit of the entry
+	attributeSystemdUnit = "systemd.unit"
+)
+
+// priorities maps the syslog priority, which is the index, to its name and the log severity
+var priorities = []struct {
+	name     string
+	severity pdata.SeverityNumber
+}{
+	{name: "emerg", severity: pdata.SeverityNumberFATAL4},
+	{name: "alert", severity: pdata.SeverityNumberFATAL3},
+	{name: "crit", severity: pdata.SeverityNumberFATAL},
+	{name: "err", severity: pdata.SeverityNumberERROR},
+	{name: "warning", severity: pdata.SeverityNumberWARN},
+	{name: "notice", severity: pdata.SeverityNumberINFO2},
+	{name: "info", severity: pdata.SeverityNumberINFO},
+	{name: "debug", severity: pdata.SeverityNumberDEBUG},
+}
+
+// parsePriority returns the priority number for its name (e.g. info) or number (e.g. 6)
+func parsePriority(priority string) (int, bool) {
+	for i, p := range priorities {
+		if priority == p.name {
+			return i, true
+		}
+	}
+	i, err := strconv.Atoi(priority)
+	if err != nil || i < 0 || i >= len(priorities) {
+		return 0, false
+	}
+	return i, true
+}
+
+// entry is the journal entry converted to the log record
+type entry struct {
+	cursor   string
+	hostname string
+	unit     string
+	record   pdata.LogRecord
+}
+
+// parseEntry converts the journal entry exported by `journalctl --output=json`
+func parseEntry(line []byte) (entry, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return entry{}, fmt.Errorf("failed to decode journal entry: %w", err)
+	}
+
+	e := entry{
+		record: pdata.NewLogRecord(),
+	}
+	for key, value := range fields {
+		v := fieldValue(value)
+		switch key {
+		case fieldCursor:
+			e.cursor = v
+		case fieldHostname:
+			e.hostname = v
+		case fieldSystemdUnit:
+			e.unit = v
+		case fieldMessage:
+			e.record.Body().SetStringVal(v)
+		case fieldRealtimeTimestamp:
+			us, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return entry{}, fmt.Errorf("failed to parse %s: %w", fieldRealtimeTimestamp, err)
+			}
+			e.record.SetTimestamp(pdata.Timestamp(us * 1000))
+		case fieldPriority:
+			if p, ok := parsePriority(v); ok {
+				e.record.SetSeverityNumber(priorities[p].severity)
+				e.record.SetSeverityText(priorities[p].name)
+			} else {
+				e.record.Attributes().InsertString(key, v)
+			}
+		case fieldMonotonicTimestamp:
+			// not meaningful outside of the boot it comes from
+		default:
+			e.record.Attributes().InsertString(key, v)
+		}
+	}
+
+	if e.cursor == "" {
+		return entry{}, fmt.Errorf("journal entry without %s", fieldCursor)
+	}
+	return e, nil
+}
+
+// fieldValue returns the value of the journal field as string. journalctl exports
+// binary values as arrays of bytes and multiple values of the same field as arrays.
+func fieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		bytes := make([]byte, 0, len(v))
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			switch i := item.(type) {
+			case float64:
+				bytes = append(bytes, byte(i))
+			default:
+				values = append(values, fieldValue(i))
+			}
+		}
+		if len(values) == 0 {
+			return string(bytes)
+		}
+		return strings.Join(values, "\n")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
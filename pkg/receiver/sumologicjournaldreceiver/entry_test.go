@@ -0,0 +1,122 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicjournaldreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestParseEntry(t *testing.T) {
+	e, err := parseEntry([]byte(`{"__CURSOR":"s=1;i=1","__REALTIME_TIMESTAMP":"1650000000000000",` +
+		`"__MONOTONIC_TIMESTAMP":"123","_HOSTNAME":"host-1","_SYSTEMD_UNIT":"sshd.service",` +
+		`"PRIORITY":"3","SYSLOG_IDENTIFIER":"sshd","MESSAGE":"Connection closed"}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "s=1;i=1", e.cursor)
+	assert.Equal(t, "host-1", e.hostname)
+	assert.Equal(t, "sshd.service", e.unit)
+	assert.Equal(t, "Connection closed", e.record.Body().StringVal())
+	assert.Equal(t, pdata.Timestamp(1650000000000000000), e.record.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberERROR, e.record.SeverityNumber())
+	assert.Equal(t, "err", e.record.SeverityText())
+
+	assert.Equal(t, 1, e.record.Attributes().Len())
+	identifier, ok := e.record.Attributes().Get("SYSLOG_IDENTIFIER")
+	require.True(t, ok)
+	assert.Equal(t, "sshd", identifier.StringVal())
+}
+
+func TestParseEntryErrors(t *testing.T) {
+	testcases := []struct {
+		name          string
+		line          string
+		expectedError string
+	}{
+		{
+			name:          "invalid json",
+			line:          `{"MESSAGE":`,
+			expectedError: "failed to decode journal entry: unexpected end of JSON input",
+		},
+		{
+			name:          "no cursor",
+			line:          `{"MESSAGE":"message"}`,
+			expectedError: "journal entry without __CURSOR",
+		},
+		{
+			name:          "invalid timestamp",
+			line:          `{"__CURSOR":"s=1;i=1","__REALTIME_TIMESTAMP":"now"}`,
+			expectedError: `failed to parse __REALTIME_TIMESTAMP: strconv.ParseUint: parsing "now": invalid syntax`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseEntry([]byte(tc.line))
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestFieldValue(t *testing.T) {
+	testcases := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{
+			name:     "string",
+			value:    "value",
+			expected: "value",
+		},
+		{
+			name:     "binary",
+			value:    []interface{}{float64('a'), float64('\n'), float64('b')},
+			expected: "a\nb",
+		},
+		{
+			name:     "multiple values",
+			value:    []interface{}{"a", "b"},
+			expected: "a\nb",
+		},
+		{
+			name:     "null",
+			value:    nil,
+			expected: "",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, fieldValue(tc.value))
+		})
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	p, ok := parsePriority("warning")
+	assert.True(t, ok)
+	assert.Equal(t, 4, p)
+
+	p, ok = parsePriority("7")
+	assert.True(t, ok)
+	assert.Equal(t, 7, p)
+
+	_, ok = parsePriority("verbose")
+	assert.False(t, ok)
+}
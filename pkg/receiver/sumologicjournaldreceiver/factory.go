@@ -0,0 +1,63 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicjournaldreceiver
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+)
+
+const (
+	typeStr = "sumologic_journald"
+
+	defaultJournalctlPath = "journalctl"
+	defaultPriority       = "info"
+	defaultStartAt        = StartAtEnd
+	defaultMaxBatchSize   = 100
+	defaultRestartDelay   = 5 * time.Second
+)
+
+// NewFactory creates a factory for the journald receiver.
+func NewFactory() component.ReceiverFactory {
+	return component.NewReceiverFactory(
+		typeStr,
+		createDefaultConfig,
+		component.WithLogsReceiver(createLogsReceiver),
+	)
+}
+
+func createDefaultConfig() config.Receiver {
+	return &Config{
+		ReceiverSettings: config.NewReceiverSettings(config.NewComponentID(typeStr)),
+		JournalctlPath:   defaultJournalctlPath,
+		Priority:         defaultPriority,
+		StartAt:          defaultStartAt,
+		MaxBatchSize:     defaultMaxBatchSize,
+		RestartDelay:     defaultRestartDelay,
+	}
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateSettings,
+	cfg config.Receiver,
+	nextConsumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	return newJournaldReceiver(cfg.(*Config), params.Logger, nextConsumer), nil
+}
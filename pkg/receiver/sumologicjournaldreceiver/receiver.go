@@ -0,0 +1,295 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicjournaldreceiver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+const (
+	cursorStorageKey = "cursor"
+
+	// maxEntrySize limits the size of the single journal entry exported by journalctl
+	maxEntrySize = 16 * 1024 * 1024
+)
+
+type journaldReceiver struct {
+	config   *Config
+	logger   *zap.Logger
+	consumer consumer.Logs
+
+	// cursor is the cursor of the last entry passed to the pipeline
+	cursor string
+	client storage.Client
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ component.LogsReceiver = (*journaldReceiver)(nil)
+
+func newJournaldReceiver(cfg *Config, logger *zap.Logger, nextConsumer consumer.Logs) *journaldReceiver {
+	return &journaldReceiver{
+		config:   cfg,
+		logger:   logger,
+		consumer: nextConsumer,
+	}
+}
+
+// Start loads the persisted cursor and starts reading the journal
+func (r *journaldReceiver) Start(ctx context.Context, host component.Host) error {
+	if r.config.StorageID != nil {
+		client, err := getStorageClient(ctx, host, *r.config.StorageID, r.config.ID())
+		if err != nil {
+			return err
+		}
+		r.client = client
+
+		data, err := client.Get(ctx, cursorStorageKey)
+		if err != nil {
+			return fmt.Errorf("failed to load journal cursor: %w", err)
+		}
+		r.cursor = string(data)
+	}
+
+	rctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go r.run(rctx)
+	return nil
+}
+
+// Shutdown stops reading the journal
+func (r *journaldReceiver) Shutdown(ctx context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+
+	if r.client != nil {
+		return r.client.Close(ctx)
+	}
+	return nil
+}
+
+func getStorageClient(ctx context.Context, host component.Host, storageID config.ComponentID, id config.ComponentID) (storage.Client, error) {
+	ext, ok := host.GetExtensions()[storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension %s not found", storageID)
+	}
+	se, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("extension %s is not a storage extension", storageID)
+	}
+	client, err := se.GetClient(ctx, component.KindReceiver, id, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage client: %w", err)
+	}
+	return client, nil
+}
+
+// run reads the journal and restarts journalctl whenever it exits
+func (r *journaldReceiver) run(ctx context.Context) {
+	defer r.wg.Done()
+
+	for {
+		read, err := r.readJournal(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if read == 0 && r.cursor != "" {
+			// The entry pointed by the cursor might have been removed by the journal rotation
+			r.logger.Warn("Failed to resume reading the journal from the cursor, starting from start_at",
+				zap.String("start_at", r.config.StartAt),
+				zap.Error(err),
+			)
+			r.cursor = ""
+		} else {
+			r.logger.Error("Reading the journal failed, restarting journalctl",
+				zap.Duration("restart_delay", r.config.RestartDelay),
+				zap.Error(err),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.config.RestartDelay):
+		}
+	}
+}
+
+// readJournal runs journalctl and passes the entries to the pipeline until journalctl exits.
+// It returns the number of the entries read.
+func (r *journaldReceiver) readJournal(ctx context.Context) (int, error) {
+	// journalctl runs with --follow, so it's killed when its output can't be read anymore
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, r.config.JournalctlPath, r.args()...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get journalctl output: %w", err)
+	}
+	if err = cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start journalctl: %w", err)
+	}
+
+	lines := make(chan []byte, r.config.MaxBatchSize)
+	// scanErr is set before lines is closed, so it can be read once all the lines are received
+	var scanErr error
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxEntrySize)
+		for scanner.Scan() {
+			// the scanner reuses the buffer, so the line has to be copied
+			lines <- append([]byte(nil), scanner.Bytes()...)
+		}
+		scanErr = scanner.Err()
+	}()
+
+	read := 0
+	for {
+		batch, ok := r.nextBatch(lines)
+		if len(batch) > 0 {
+			read += len(batch)
+			r.consumeEntries(ctx, batch)
+		}
+		if !ok {
+			break
+		}
+	}
+
+	if scanErr != nil {
+		cancel()
+		_ = cmd.Wait()
+		return read, fmt.Errorf("failed to read journalctl output: %w", scanErr)
+	}
+
+	err = cmd.Wait()
+	if err == nil {
+		err = fmt.Errorf("journalctl exited")
+	}
+	return read, fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+}
+
+// args returns the journalctl arguments
+func (r *journaldReceiver) args() []string {
+	args := []string{"--utc", "--output=json", "--follow", "--no-pager"}
+	if r.config.Directory != "" {
+		args = append(args, "--directory="+r.config.Directory)
+	}
+	for _, file := range r.config.Files {
+		args = append(args, "--file="+file)
+	}
+	for _, unit := range r.config.Units {
+		args = append(args, "--unit="+unit)
+	}
+	args = append(args, "--priority="+r.config.Priority)
+
+	switch {
+	case r.cursor != "":
+		args = append(args, "--after-cursor="+r.cursor)
+	case r.config.StartAt == StartAtBeginning:
+		args = append(args, "--no-tail")
+	default:
+		args = append(args, "--lines=0")
+	}
+	return args
+}
+
+// nextBatch waits for the next entry and returns it with the entries which are already
+// available, up to MaxBatchSize. It returns false when there are no more entries.
+func (r *journaldReceiver) nextBatch(lines <-chan []byte) ([]entry, bool) {
+	batch := make([]entry, 0, r.config.MaxBatchSize)
+
+	line, ok := <-lines
+	for ok {
+		e, err := parseEntry(line)
+		if err != nil {
+			r.logger.Warn("Skipping invalid journal entry", zap.Error(err))
+		} else {
+			batch = append(batch, e)
+		}
+
+		if len(batch) >= r.config.MaxBatchSize {
+			break
+		}
+		select {
+		case line, ok = <-lines:
+		default:
+			return batch, true
+		}
+	}
+	return batch, ok
+}
+
+// consumeEntries passes the entries to the pipeline and persists the cursor of the last one
+func (r *journaldReceiver) consumeEntries(ctx context.Context, entries []entry) {
+	if err := r.consumer.ConsumeLogs(ctx, entriesToLogs(entries)); err != nil {
+		r.logger.Error("ConsumeLogs() error", zap.Error(err))
+	}
+
+	r.cursor = entries[len(entries)-1].cursor
+	if r.client != nil {
+		if err := r.client.Set(ctx, cursorStorageKey, []byte(r.cursor)); err != nil {
+			r.logger.Warn("Failed to persist journal cursor", zap.Error(err))
+		}
+	}
+}
+
+// entriesToLogs groups the entries by the hostname and systemd unit,
+// which are added as resource attributes
+func entriesToLogs(entries []entry) pdata.Logs {
+	logs := pdata.NewLogs()
+	resources := make(map[[2]string]pdata.LogRecordSlice)
+
+	for _, e := range entries {
+		key := [2]string{e.hostname, e.unit}
+		records, ok := resources[key]
+		if !ok {
+			rl := logs.ResourceLogs().AppendEmpty()
+			attrs := rl.Resource().Attributes()
+			if e.hostname != "" {
+				attrs.InsertString(attributeHostName, e.hostname)
+			}
+			if e.unit != "" {
+				attrs.InsertString(attributeSystemdUnit, e.unit)
+			}
+			records = rl.InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+			resources[key] = records
+		}
+		e.record.CopyTo(records.AppendEmpty())
+	}
+	return logs
+}
@@ -0,0 +1,239 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicjournaldreceiver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+// memoryStorage is a storage extension keeping the data in memory
+type memoryStorage struct {
+	component.Extension
+	lock sync.Mutex
+	data map[string][]byte
+}
+
+func (ms *memoryStorage) GetClient(context.Context, component.Kind, config.ComponentID, string) (storage.Client, error) {
+	return &memoryStorageClient{ms: ms}, nil
+}
+
+func (ms *memoryStorage) get(key string) []byte {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	return ms.data[key]
+}
+
+type memoryStorageClient struct {
+	storage.Client
+	ms *memoryStorage
+}
+
+func (c *memoryStorageClient) Get(_ context.Context, key string) ([]byte, error) {
+	return c.ms.get(key), nil
+}
+
+func (c *memoryStorageClient) Set(_ context.Context, key string, value []byte) error {
+	c.ms.lock.Lock()
+	defer c.ms.lock.Unlock()
+	c.ms.data[key] = value
+	return nil
+}
+
+func (c *memoryStorageClient) Close(context.Context) error {
+	return nil
+}
+
+type storageTestHost struct {
+	component.Host
+	extensions map[config.ComponentID]component.Extension
+}
+
+func (h storageTestHost) GetExtensions() map[config.ComponentID]component.Extension {
+	return h.extensions
+}
+
+func TestArgs(t *testing.T) {
+	testcases := []struct {
+		name     string
+		modify   func(cfg *Config)
+		cursor   string
+		expected []string
+	}{
+		{
+			name:     "default config",
+			modify:   func(cfg *Config) {},
+			expected: []string{"--utc", "--output=json", "--follow", "--no-pager", "--priority=info", "--lines=0"},
+		},
+		{
+			name: "start at beginning",
+			modify: func(cfg *Config) {
+				cfg.StartAt = StartAtBeginning
+				cfg.Units = []string{"sshd.service", "cron.service"}
+			},
+			expected: []string{"--utc", "--output=json", "--follow", "--no-pager",
+				"--unit=sshd.service", "--unit=cron.service", "--priority=info", "--no-tail"},
+		},
+		{
+			name: "resume from cursor",
+			modify: func(cfg *Config) {
+				cfg.StartAt = StartAtBeginning
+				cfg.Directory = "/var/log/journal"
+				cfg.Priority = "err"
+			},
+			cursor: "s=1;i=2",
+			expected: []string{"--utc", "--output=json", "--follow", "--no-pager",
+				"--directory=/var/log/journal", "--priority=err", "--after-cursor=s=1;i=2"},
+		},
+		{
+			name: "files",
+			modify: func(cfg *Config) {
+				cfg.Files = []string{"/tmp/a.journal", "/tmp/b.journal"}
+			},
+			expected: []string{"--utc", "--output=json", "--follow", "--no-pager",
+				"--file=/tmp/a.journal", "--file=/tmp/b.journal", "--priority=info", "--lines=0"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tc.modify(cfg)
+
+			r := newJournaldReceiver(cfg, zap.NewNop(), consumertest.NewNop())
+			r.cursor = tc.cursor
+			assert.Equal(t, tc.expected, r.args())
+		})
+	}
+}
+
+func TestEntriesToLogs(t *testing.T) {
+	var entries []entry
+	for _, line := range []string{
+		`{"__CURSOR":"1","_HOSTNAME":"host-1","_SYSTEMD_UNIT":"sshd.service","MESSAGE":"first"}`,
+		`{"__CURSOR":"2","_HOSTNAME":"host-1","_SYSTEMD_UNIT":"cron.service","MESSAGE":"second"}`,
+		`{"__CURSOR":"3","_HOSTNAME":"host-1","_SYSTEMD_UNIT":"sshd.service","MESSAGE":"third"}`,
+		`{"__CURSOR":"4","MESSAGE":"kernel"}`,
+	} {
+		e, err := parseEntry([]byte(line))
+		require.NoError(t, err)
+		entries = append(entries, e)
+	}
+
+	logs := entriesToLogs(entries)
+	require.Equal(t, 3, logs.ResourceLogs().Len())
+	assert.Equal(t, 4, logs.LogRecordCount())
+
+	sshd := logs.ResourceLogs().At(0)
+	host, ok := sshd.Resource().Attributes().Get(attributeHostName)
+	require.True(t, ok)
+	assert.Equal(t, "host-1", host.StringVal())
+	unit, ok := sshd.Resource().Attributes().Get(attributeSystemdUnit)
+	require.True(t, ok)
+	assert.Equal(t, "sshd.service", unit.StringVal())
+
+	records := sshd.InstrumentationLibraryLogs().At(0).LogRecords()
+	require.Equal(t, 2, records.Len())
+	assert.Equal(t, "first", records.At(0).Body().StringVal())
+	assert.Equal(t, "third", records.At(1).Body().StringVal())
+
+	assert.Equal(t, 0, logs.ResourceLogs().At(2).Resource().Attributes().Len())
+}
+
+func TestReceiverResumesFromCursor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake journalctl is a shell script")
+	}
+
+	dir := t.TempDir()
+	entriesFile, err := filepath.Abs(filepath.Join("testdata", "entries.json"))
+	require.NoError(t, err)
+	argsFile := filepath.Join(dir, "args")
+	journalctl := filepath.Join(dir, "journalctl")
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" > %s\ncat %s\n", argsFile, entriesFile)
+	require.NoError(t, os.WriteFile(journalctl, []byte(script), 0o700))
+
+	storageID := config.NewComponentID("storage")
+	ms := &memoryStorage{data: map[string][]byte{cursorStorageKey: []byte("s=1;i=0")}}
+	host := storageTestHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[config.ComponentID]component.Extension{storageID: ms},
+	}
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.JournalctlPath = journalctl
+	cfg.StorageID = &storageID
+	cfg.RestartDelay = time.Minute
+
+	sink := new(consumertest.LogsSink)
+	r := newJournaldReceiver(cfg, zap.NewNop(), sink)
+	require.NoError(t, r.Start(context.Background(), host))
+
+	assert.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(args), "--after-cursor=s=1;i=0")
+	assert.Equal(t, "s=1;i=3", string(ms.get(cursorStorageKey)))
+}
+
+func TestReadJournalOversizedEntry(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake journalctl is a shell script")
+	}
+
+	dir := t.TempDir()
+	journalctl := filepath.Join(dir, "journalctl")
+	// the entry exceeds maxEntrySize and journalctl keeps following the journal afterwards
+	script := fmt.Sprintf("#!/bin/sh\nhead -c %d /dev/zero | tr '\\0' a\necho\nexec sleep 60\n", maxEntrySize+1)
+	require.NoError(t, os.WriteFile(journalctl, []byte(script), 0o700))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.JournalctlPath = journalctl
+
+	r := newJournaldReceiver(cfg, zap.NewNop(), new(consumertest.LogsSink))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.readJournal(context.Background())
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read journalctl output")
+	case <-time.After(30 * time.Second):
+		t.Fatal("readJournal didn't return after the output couldn't be read")
+	}
+}
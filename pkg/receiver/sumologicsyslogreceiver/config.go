@@ -0,0 +1,120 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicsyslogreceiver
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+const (
+	// ProtocolAuto makes the receiver detect the syslog protocol of every connection
+	ProtocolAuto = "auto"
+	// ProtocolRFC3164 makes the receiver parse the messages as BSD syslog
+	ProtocolRFC3164 = "rfc3164"
+	// ProtocolRFC5424 makes the receiver parse the messages as IETF syslog
+	ProtocolRFC5424 = "rfc5424"
+)
+
+// Config defines configuration for the Sumo Logic syslog receiver.
+type Config struct {
+	config.ReceiverSettings `mapstructure:",squash"`
+
+	// TCP configures receiving the messages over TCP.
+	// When not set, the receiver doesn't listen on TCP.
+	TCP *TCPConfig `mapstructure:"tcp"`
+
+	// UDP configures receiving the messages over UDP.
+	// When not set, the receiver doesn't listen on UDP.
+	UDP *UDPConfig `mapstructure:"udp"`
+
+	// Protocol is the syslog protocol of the messages, either auto, rfc3164 or rfc5424.
+	// With auto, the protocol is detected from the first message of every TCP connection
+	// and from every UDP datagram.
+	// By default this is auto.
+	Protocol string `mapstructure:"protocol"`
+
+	// Location is the time zone of the RFC3164 timestamps, which don't contain it.
+	// By default this is UTC.
+	Location string `mapstructure:"location"`
+
+	// MaxMessageSize is the maximum size of the message in bytes, the larger messages are dropped.
+	// By default this is 64 KiB.
+	MaxMessageSize int `mapstructure:"max_message_size"`
+
+	// MaxBatchSize is the maximum number of the messages of the TCP connection
+	// passed to the pipeline at once.
+	// By default this is 100.
+	MaxBatchSize int `mapstructure:"max_batch_size"`
+}
+
+// TCPConfig defines the TCP listener of the receiver.
+type TCPConfig struct {
+	// Endpoint is the address to listen on, e.g. 0.0.0.0:514.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// TLS configures the TLS of the connections.
+	// When not set, the connections are not encrypted.
+	TLS *configtls.TLSServerSetting `mapstructure:"tls"`
+
+	// IdleTimeout defines how long the connection is kept open without receiving any data.
+	// By default this is 0, which means the connection is never closed by the receiver.
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+}
+
+// UDPConfig defines the UDP listener of the receiver.
+type UDPConfig struct {
+	// Endpoint is the address to listen on, e.g. 0.0.0.0:514.
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+var _ config.Receiver = (*Config)(nil)
+
+// Validate checks if the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.TCP == nil && cfg.UDP == nil {
+		return errors.New("at least one of tcp and udp has to be configured")
+	}
+	if cfg.TCP != nil {
+		if cfg.TCP.Endpoint == "" {
+			return errors.New("tcp endpoint cannot be empty")
+		}
+		if cfg.TCP.IdleTimeout < 0 {
+			return fmt.Errorf("tcp idle_timeout cannot be negative: %s", cfg.TCP.IdleTimeout)
+		}
+	}
+	if cfg.UDP != nil && cfg.UDP.Endpoint == "" {
+		return errors.New("udp endpoint cannot be empty")
+	}
+	switch cfg.Protocol {
+	case ProtocolAuto, ProtocolRFC3164, ProtocolRFC5424:
+	default:
+		return fmt.Errorf("unexpected protocol: %s", cfg.Protocol)
+	}
+	if _, err := time.LoadLocation(cfg.Location); err != nil {
+		return fmt.Errorf("unexpected location: %s", cfg.Location)
+	}
+	if cfg.MaxMessageSize <= 0 {
+		return fmt.Errorf("max_message_size has to be positive: %d", cfg.MaxMessageSize)
+	}
+	if cfg.MaxBatchSize <= 0 {
+		return fmt.Errorf("max_batch_size has to be positive: %d", cfg.MaxBatchSize)
+	}
+	return nil
+}
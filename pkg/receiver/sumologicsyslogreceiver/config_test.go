@@ -0,0 +1,151 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicsyslogreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Receivers[config.NewComponentID(typeStr)])
+
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: config.NewReceiverSettings(config.NewComponentIDWithName(typeStr, "custom")),
+			TCP: &TCPConfig{
+				Endpoint: "0.0.0.0:6514",
+				TLS: &configtls.TLSServerSetting{
+					TLSSetting: configtls.TLSSetting{
+						CertFile: "/etc/otelcol-sumo/syslog.crt",
+						KeyFile:  "/etc/otelcol-sumo/syslog.key",
+					},
+				},
+				IdleTimeout: 5 * time.Minute,
+			},
+			UDP: &UDPConfig{
+				Endpoint: "0.0.0.0:514",
+			},
+			Protocol:       ProtocolRFC5424,
+			Location:       "Europe/Warsaw",
+			MaxMessageSize: 8192,
+			MaxBatchSize:   50,
+		},
+		cfg.Receivers[config.NewComponentIDWithName(typeStr, "custom")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		modify        func(cfg *Config)
+		expectedError string
+	}{
+		{
+			name:   "tcp",
+			modify: func(cfg *Config) { cfg.TCP = &TCPConfig{Endpoint: "0.0.0.0:514"} },
+		},
+		{
+			name:   "udp",
+			modify: func(cfg *Config) { cfg.UDP = &UDPConfig{Endpoint: "0.0.0.0:514"} },
+		},
+		{
+			name:          "no listener",
+			modify:        func(cfg *Config) {},
+			expectedError: "at least one of tcp and udp has to be configured",
+		},
+		{
+			name:          "empty tcp endpoint",
+			modify:        func(cfg *Config) { cfg.TCP = &TCPConfig{} },
+			expectedError: "tcp endpoint cannot be empty",
+		},
+		{
+			name: "negative tcp idle_timeout",
+			modify: func(cfg *Config) {
+				cfg.TCP = &TCPConfig{Endpoint: "0.0.0.0:514", IdleTimeout: -time.Second}
+			},
+			expectedError: "tcp idle_timeout cannot be negative: -1s",
+		},
+		{
+			name:          "empty udp endpoint",
+			modify:        func(cfg *Config) { cfg.UDP = &UDPConfig{} },
+			expectedError: "udp endpoint cannot be empty",
+		},
+		{
+			name: "unexpected protocol",
+			modify: func(cfg *Config) {
+				cfg.UDP = &UDPConfig{Endpoint: "0.0.0.0:514"}
+				cfg.Protocol = "rfc6587"
+			},
+			expectedError: "unexpected protocol: rfc6587",
+		},
+		{
+			name: "unexpected location",
+			modify: func(cfg *Config) {
+				cfg.UDP = &UDPConfig{Endpoint: "0.0.0.0:514"}
+				cfg.Location = "Mars/Olympus_Mons"
+			},
+			expectedError: "unexpected location: Mars/Olympus_Mons",
+		},
+		{
+			name: "non-positive max_message_size",
+			modify: func(cfg *Config) {
+				cfg.UDP = &UDPConfig{Endpoint: "0.0.0.0:514"}
+				cfg.MaxMessageSize = 0
+			},
+			expectedError: "max_message_size has to be positive: 0",
+		},
+		{
+			name: "non-positive max_batch_size",
+			modify: func(cfg *Config) {
+				cfg.UDP = &UDPConfig{Endpoint: "0.0.0.0:514"}
+				cfg.MaxBatchSize = 0
+			},
+			expectedError: "max_batch_size has to be positive: 0",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicsyslogreceiver
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxOctetCountDigits limits the length of the octet count of the message
+const maxOctetCountDigits = 10
+
+var errMessageTooLarge = errors.New("message too large")
+
+// frameReader splits the TCP stream into the syslog messages according to RFC6587.
+// The framing is detected for every message: the messages starting with a digit are
+// octet counted (`MSG-LEN SP SYSLOG-MSG`), the others are terminated with a new line.
+type frameReader struct {
+	reader  *bufio.Reader
+	maxSize int
+}
+
+func newFrameReader(r io.Reader, maxSize int) *frameReader {
+	return &frameReader{
+		reader:  bufio.NewReader(r),
+		maxSize: maxSize,
+	}
+}
+
+// next returns the next message. The messages larger than maxSize are skipped
+// and errMessageTooLarge is returned, any other error means the stream cannot be read anymore.
+func (f *frameReader) next() ([]byte, error) {
+	first, err := f.reader.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	if first[0] >= '1' && first[0] <= '9' {
+		return f.nextOctetCounted()
+	}
+	return f.nextLine()
+}
+
+// buffered returns whether there is data already read from the stream,
+// which means more messages are likely available without waiting
+func (f *frameReader) buffered() bool {
+	return f.reader.Buffered() > 0
+}
+
+func (f *frameReader) nextOctetCounted() ([]byte, error) {
+	length, digits := 0, 0
+	for {
+		b, err := f.reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == ' ' {
+			break
+		}
+		digits++
+		if b < '0' || b > '9' || digits > maxOctetCountDigits {
+			return nil, fmt.Errorf("invalid octet count framing")
+		}
+		length = length*10 + int(b-'0')
+	}
+
+	if length > f.maxSize {
+		if _, err := f.reader.Discard(length); err != nil {
+			return nil, err
+		}
+		return nil, errMessageTooLarge
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(f.reader, data); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(data, "\r\n"), nil
+}
+
+func (f *frameReader) nextLine() ([]byte, error) {
+	var data []byte
+	tooLarge := false
+	for {
+		chunk, err := f.reader.ReadSlice('\n')
+		// the new line characters are not counted as a part of the message
+		if !tooLarge && len(data)+len(chunk) > f.maxSize+2 {
+			tooLarge, data = true, nil
+		}
+		if !tooLarge {
+			data = append(data, chunk...)
+		}
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err == io.EOF && len(data) > 0 {
+			// the last message of the stream doesn't have to be terminated
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	data = bytes.TrimRight(data, "\r\n")
+	if tooLarge || len(data) > f.maxSize {
+		return nil, errMessageTooLarge
+	}
+	return data, nil
+}
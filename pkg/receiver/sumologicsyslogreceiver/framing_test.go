@@ -0,0 +1,98 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicsyslogreceiver
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameReader(t *testing.T) {
+	testcases := []struct {
+		name             string
+		stream           string
+		expected         []string
+		expectedTooLarge int
+		expectedError    string
+	}{
+		{
+			name:     "new line terminated",
+			stream:   "<13>first\n<13>second\r\n\n<13>third",
+			expected: []string{"<13>first", "<13>second", "", "<13>third"},
+		},
+		{
+			name:     "octet counted",
+			stream:   "9 <13>first10 <13>second9 <13>third",
+			expected: []string{"<13>first", "<13>second", "<13>third"},
+		},
+		{
+			name:     "mixed framing",
+			stream:   "9 <13>first<13>second\n9 <13>third",
+			expected: []string{"<13>first", "<13>second", "<13>third"},
+		},
+		{
+			name:             "too large",
+			stream:           "<13>first\n<13>too large\n13 <13>too large<13>third\n",
+			expected:         []string{"<13>first", "<13>third"},
+			expectedTooLarge: 2,
+		},
+		{
+			name:          "invalid octet count",
+			stream:        "9 <13>first9x <13>second",
+			expected:      []string{"<13>first"},
+			expectedError: "invalid octet count framing",
+		},
+		{
+			name:          "truncated octet counted message",
+			stream:        "10 <13>first",
+			expectedError: "unexpected EOF",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := newFrameReader(strings.NewReader(tc.stream), 10)
+			var (
+				messages []string
+				tooLarge int
+				err      error
+			)
+			for {
+				var data []byte
+				data, err = reader.next()
+				if errors.Is(err, errMessageTooLarge) {
+					tooLarge++
+					continue
+				}
+				if err != nil {
+					break
+				}
+				messages = append(messages, string(data))
+			}
+
+			assert.Equal(t, tc.expected, messages)
+			assert.Equal(t, tc.expectedTooLarge, tooLarge)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.ErrorIs(t, err, io.EOF)
+			}
+		})
+	}
+}
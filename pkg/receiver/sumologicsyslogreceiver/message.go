@@ -0,0 +1,353 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicsyslogreceiver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	// attributeHostName is the resource attribute with the hostname of the message,
+	// translated to `host` by the sumologicexporter
+	attributeHostName = "host.name"
+	// attributeFacility is the attribute with the facility name, the same as set
+	// by the sumologicsyslogprocessor
+	attributeFacility                = "facility"
+	attributeAppName                 = "appname"
+	attributeProcID                  = "proc_id"
+	attributeMsgID                   = "msg_id"
+	attributeStructuredDataPrefix    = "structured_data."
+	attributePeerIP                  = "net.peer.ip"
+	attributeTransport               = "net.transport"
+	transportTCP                     = "ip_tcp"
+	transportUDP                     = "ip_udp"
+	nilValue                         = "-"
+	rfc3164TimestampLayout           = "Jan _2 15:04:05"
+	rfc3164TimestampLength           = len(rfc3164TimestampLayout)
+	maxPriority                      = 191
+	facilitiesCount, severitiesCount = 24, 8
+)
+
+// rfc5424Header matches the priority and the version of the RFC5424 message,
+// the RFC3164 messages have the timestamp after the priority
+var rfc5424Header = regexp.MustCompile(`^<\d{1,3}>[1-9]\d? `)
+
+// facilities are the facility names, the same as used by the sumologicsyslogprocessor
+var facilities = [facilitiesCount]string{
+	"kernel messages",
+	"user-level messages",
+	"mail system",
+	"system daemons",
+	"security/authorization messages",
+	"messages generated internally by syslogd",
+	"line printer subsystem",
+	"network news subsystem",
+	"UUCP subsystem",
+	"clock daemon",
+	"security/authorization messages",
+	"FTP daemon",
+	"NTP subsystem",
+	"log audit",
+	"log alert",
+	"clock daemon",
+	"local use 0  (local0)",
+	"local use 1  (local1)",
+	"local use 2  (local2)",
+	"local use 3  (local3)",
+	"local use 4  (local4)",
+	"local use 5  (local5)",
+	"local use 6  (local6)",
+	"local use 7  (local7)",
+}
+
+// severities maps the syslog severity, which is the index, to its name and the log severity
+var severities = [severitiesCount]struct {
+	name     string
+	severity pdata.SeverityNumber
+}{
+	{name: "emerg", severity: pdata.SeverityNumberFATAL4},
+	{name: "alert", severity: pdata.SeverityNumberFATAL3},
+	{name: "crit", severity: pdata.SeverityNumberFATAL},
+	{name: "err", severity: pdata.SeverityNumberERROR},
+	{name: "warning", severity: pdata.SeverityNumberWARN},
+	{name: "notice", severity: pdata.SeverityNumberINFO2},
+	{name: "info", severity: pdata.SeverityNumberINFO},
+	{name: "debug", severity: pdata.SeverityNumberDEBUG},
+}
+
+var errTruncatedHeader = errors.New("truncated header")
+
+// detectProtocol returns the syslog protocol of the message
+func detectProtocol(data []byte) string {
+	if rfc5424Header.Match(data) {
+		return ProtocolRFC5424
+	}
+	return ProtocolRFC3164
+}
+
+// message is the syslog message converted to the log record
+type message struct {
+	hostname string
+	record   pdata.LogRecord
+}
+
+type parser struct {
+	location *time.Location
+	// now returns the current time, it's overridden in tests
+	now func() time.Time
+}
+
+// parse converts the syslog message of the given protocol to the log record.
+// The body is the whole message, so it's parsed by Sumo Logic the same way
+// as the messages collected by the syslog sources. When the message cannot be parsed,
+// the error is returned together with the record containing the body only.
+func (p *parser) parse(data []byte, protocol string) (message, error) {
+	m := message{record: pdata.NewLogRecord()}
+	m.record.Body().SetStringVal(string(data))
+
+	err := p.parseHeader(&m, data, protocol)
+	if m.record.Timestamp() == 0 {
+		m.record.SetTimestamp(pdata.NewTimestampFromTime(p.now()))
+	}
+	return m, err
+}
+
+func (p *parser) parseHeader(m *message, data []byte, protocol string) error {
+	priority, rest, err := parsePriority(data)
+	if err != nil {
+		return err
+	}
+	facility, severity := priority/severitiesCount, priority%severitiesCount
+	m.record.Attributes().InsertString(attributeFacility, facilities[facility])
+	m.record.SetSeverityNumber(severities[severity].severity)
+	m.record.SetSeverityText(severities[severity].name)
+
+	if protocol == ProtocolRFC5424 {
+		return p.parseRFC5424(m, rest)
+	}
+	p.parseRFC3164(m, rest)
+	return nil
+}
+
+// parsePriority parses the `<PRI>` part of the message
+func parsePriority(data []byte) (int, []byte, error) {
+	end := bytes.IndexByte(data, '>')
+	if len(data) == 0 || data[0] != '<' || end < 2 || end > 4 {
+		return 0, data, errors.New("missing priority")
+	}
+	priority, err := strconv.Atoi(string(data[1:end]))
+	if err != nil || priority < 0 || priority > maxPriority {
+		return 0, data, fmt.Errorf("unexpected priority: %s", data[1:end])
+	}
+	return priority, data[end+1:], nil
+}
+
+// parseRFC5424 parses the message after the priority:
+// VERSION SP TIMESTAMP SP HOSTNAME SP APP-NAME SP PROCID SP MSGID SP STRUCTURED-DATA [SP MSG]
+func (p *parser) parseRFC5424(m *message, data []byte) error {
+	var fields [6]string
+	for i := range fields {
+		end := bytes.IndexByte(data, ' ')
+		if end < 0 {
+			return errTruncatedHeader
+		}
+		fields[i], data = string(data[:end]), data[end+1:]
+	}
+	timestamp, hostname, appName, procID, msgID := fields[1], fields[2], fields[3], fields[4], fields[5]
+
+	if timestamp != nilValue {
+		ts, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			return fmt.Errorf("unexpected timestamp: %s", timestamp)
+		}
+		m.record.SetTimestamp(pdata.NewTimestampFromTime(ts))
+	}
+	if hostname != nilValue {
+		m.hostname = hostname
+	}
+	attrs := m.record.Attributes()
+	insertNotNil(attrs, attributeAppName, appName)
+	insertNotNil(attrs, attributeProcID, procID)
+	insertNotNil(attrs, attributeMsgID, msgID)
+
+	return parseStructuredData(attrs, data)
+}
+
+// parseStructuredData adds the parameters of the structured data elements
+// as `structured_data.<SD-ID>.<PARAM-NAME>` attributes
+func parseStructuredData(attrs pdata.AttributeMap, data []byte) error {
+	if bytes.HasPrefix(data, []byte(nilValue)) {
+		return nil
+	}
+
+	for len(data) > 0 && data[0] == '[' {
+		end := bytes.IndexAny(data, " ]")
+		if end < 0 {
+			return errors.New("unterminated structured data element")
+		}
+		id := string(data[1:end])
+		data = data[end:]
+		params := 0
+
+		for len(data) > 0 && data[0] == ' ' {
+			data = data[1:]
+			eq := bytes.IndexByte(data, '=')
+			if eq < 0 || eq+1 >= len(data) || data[eq+1] != '"' {
+				return fmt.Errorf("invalid structured data parameter of %s", id)
+			}
+			name := string(data[:eq])
+
+			value, rest, ok := unescapeParamValue(data[eq+2:])
+			if !ok {
+				return fmt.Errorf("unterminated structured data parameter %s of %s", name, id)
+			}
+			attrs.UpsertString(attributeStructuredDataPrefix+id+"."+name, value)
+			params++
+			data = rest
+		}
+
+		if len(data) == 0 || data[0] != ']' {
+			return fmt.Errorf("unterminated structured data element %s", id)
+		}
+		if params == 0 {
+			attrs.UpsertString(attributeStructuredDataPrefix+id, "")
+		}
+		data = data[1:]
+	}
+	return nil
+}
+
+// unescapeParamValue returns the structured data parameter value up to the closing quote
+// and the data after it. The `"`, `\` and `]` characters are escaped with `\` in the value.
+func unescapeParamValue(data []byte) (string, []byte, bool) {
+	value := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			return string(value), data[i+1:], true
+		case '\\':
+			if i+1 < len(data) && (data[i+1] == '"' || data[i+1] == '\\' || data[i+1] == ']') {
+				i++
+			}
+		}
+		value = append(value, data[i])
+	}
+	return "", nil, false
+}
+
+// parseRFC3164 parses the message after the priority: TIMESTAMP SP HOSTNAME SP TAG[PID]: MSG
+// The message without the valid timestamp has no hostname and tag either, as it's
+// the whole content according to RFC3164. Besides the RFC3164 timestamps,
+// the RFC3339 ones sent by some syslog daemons are supported.
+func (p *parser) parseRFC3164(m *message, data []byte) {
+	ts, rest, ok := p.parseRFC3164Timestamp(data)
+	if !ok {
+		return
+	}
+	m.record.SetTimestamp(pdata.NewTimestampFromTime(ts))
+
+	end := bytes.IndexByte(rest, ' ')
+	if end <= 0 {
+		return
+	}
+	m.hostname = string(rest[:end])
+	rest = rest[end+1:]
+
+	end = bytes.IndexAny(rest, "[: ")
+	if end <= 0 || rest[end] == ' ' {
+		return
+	}
+	attrs := m.record.Attributes()
+	attrs.InsertString(attributeAppName, string(rest[:end]))
+	if rest[end] == '[' {
+		if pidEnd := bytes.IndexByte(rest[end:], ']'); pidEnd > 1 {
+			attrs.InsertString(attributeProcID, string(rest[end+1:end+pidEnd]))
+		}
+	}
+}
+
+// parseRFC3164Timestamp parses the timestamp at the beginning of the data.
+// As RFC3164 timestamps lack the year, the one which puts the timestamp closest
+// to now is used, e.g. a December message received in January is assigned to the previous year.
+func (p *parser) parseRFC3164Timestamp(data []byte) (time.Time, []byte, bool) {
+	if end := bytes.IndexByte(data, ' '); end > 0 {
+		if ts, err := time.Parse(time.RFC3339Nano, string(data[:end])); err == nil {
+			return ts, data[end+1:], true
+		}
+	}
+
+	if len(data) <= rfc3164TimestampLength || data[rfc3164TimestampLength] != ' ' {
+		return time.Time{}, data, false
+	}
+	parsed, err := time.ParseInLocation(rfc3164TimestampLayout, string(data[:rfc3164TimestampLength]), p.location)
+	if err != nil {
+		return time.Time{}, data, false
+	}
+
+	now := p.now().In(p.location)
+	ts := withYear(parsed, now.Year())
+	for _, year := range []int{now.Year() - 1, now.Year() + 1} {
+		candidate := withYear(parsed, year)
+		if absDuration(candidate.Sub(now)) < absDuration(ts.Sub(now)) {
+			ts = candidate
+		}
+	}
+	return ts, data[rfc3164TimestampLength+1:], true
+}
+
+func withYear(t time.Time, year int) time.Time {
+	return time.Date(year, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// insertNotNil inserts the attribute unless the value is the RFC5424 nil value
+func insertNotNil(attrs pdata.AttributeMap, key string, value string) {
+	if value != nilValue {
+		attrs.InsertString(key, value)
+	}
+}
+
+// messagesToLogs groups the messages by the hostname, which is added as the resource attribute
+func messagesToLogs(messages []message) pdata.Logs {
+	logs := pdata.NewLogs()
+	resources := make(map[string]pdata.LogRecordSlice)
+
+	for _, m := range messages {
+		records, ok := resources[m.hostname]
+		if !ok {
+			rl := logs.ResourceLogs().AppendEmpty()
+			if m.hostname != "" {
+				rl.Resource().Attributes().InsertString(attributeHostName, m.hostname)
+			}
+			records = rl.InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+			resources[m.hostname] = records
+		}
+		m.record.MoveTo(records.AppendEmpty())
+	}
+	return logs
+}
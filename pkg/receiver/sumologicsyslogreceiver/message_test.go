@@ -0,0 +1,246 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicsyslogreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestDetectProtocol(t *testing.T) {
+	assert.Equal(t, ProtocolRFC5424, detectProtocol([]byte(`<34>1 2003-10-11T22:14:15.003Z mymachine su - ID47 - message`)))
+	assert.Equal(t, ProtocolRFC5424, detectProtocol([]byte(`<34>12 - - - - - -`)))
+	assert.Equal(t, ProtocolRFC3164, detectProtocol([]byte(`<34>Oct 11 22:14:15 mymachine su: message`)))
+	assert.Equal(t, ProtocolRFC3164, detectProtocol([]byte(`<34>2003-10-11T22:14:15.003Z mymachine su: message`)))
+	assert.Equal(t, ProtocolRFC3164, detectProtocol([]byte(`message`)))
+}
+
+func TestParse(t *testing.T) {
+	now := time.Date(2022, time.January, 5, 10, 0, 0, 0, time.UTC)
+	rfc5424Message := `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 ` +
+		`[exampleSDID@32473 iut="3" eventSource="Application"][examplePriority@32473 class="high"] An application event log entry`
+
+	testcases := []struct {
+		name              string
+		data              string
+		protocol          string
+		expectedHostname  string
+		expectedTimestamp time.Time
+		expectedSeverity  pdata.SeverityNumber
+		expectedAttrs     map[string]string
+		expectedError     string
+	}{
+		{
+			name:              "rfc5424",
+			data:              rfc5424Message,
+			protocol:          ProtocolRFC5424,
+			expectedHostname:  "mymachine.example.com",
+			expectedTimestamp: time.Date(2003, time.October, 11, 22, 14, 15, 3000000, time.UTC),
+			expectedSeverity:  pdata.SeverityNumberINFO2,
+			expectedAttrs: map[string]string{
+				"facility":                              "local use 4  (local4)",
+				"appname":                               "evntslog",
+				"msg_id":                                "ID47",
+				"structured_data.exampleSDID@32473.iut": "3",
+				"structured_data.exampleSDID@32473.eventSource": "Application",
+				"structured_data.examplePriority@32473.class":   "high",
+			},
+		},
+		{
+			name:              "rfc5424 escaped structured data",
+			data:              `<34>1 2003-10-11T22:14:15.003+02:00 mymachine su 123 - [meta note="a \"quoted\" \] value" path="C:\\Windows"][origin] 'su root' failed`,
+			protocol:          ProtocolRFC5424,
+			expectedHostname:  "mymachine",
+			expectedTimestamp: time.Date(2003, time.October, 11, 20, 14, 15, 3000000, time.UTC),
+			expectedSeverity:  pdata.SeverityNumberFATAL,
+			expectedAttrs: map[string]string{
+				"facility":                  "security/authorization messages",
+				"appname":                   "su",
+				"proc_id":                   "123",
+				"structured_data.meta.note": `a "quoted" ] value`,
+				"structured_data.meta.path": `C:\Windows`,
+				"structured_data.origin":    "",
+			},
+		},
+		{
+			name:              "rfc5424 nil values",
+			data:              `<14>1 - - - - - -`,
+			protocol:          ProtocolRFC5424,
+			expectedTimestamp: now,
+			expectedSeverity:  pdata.SeverityNumberINFO,
+			expectedAttrs: map[string]string{
+				"facility": "user-level messages",
+			},
+		},
+		{
+			name:              "rfc5424 truncated header",
+			data:              `<15>1 2003-10-11T22:14:15.003Z mymachine`,
+			protocol:          ProtocolRFC5424,
+			expectedTimestamp: now,
+			expectedSeverity:  pdata.SeverityNumberDEBUG,
+			expectedAttrs: map[string]string{
+				"facility": "user-level messages",
+			},
+			expectedError: "truncated header",
+		},
+		{
+			name:              "rfc5424 unterminated structured data",
+			data:              `<15>1 2003-10-11T22:14:15.003Z mymachine app - - [meta note="value] message`,
+			protocol:          ProtocolRFC5424,
+			expectedHostname:  "mymachine",
+			expectedTimestamp: time.Date(2003, time.October, 11, 22, 14, 15, 3000000, time.UTC),
+			expectedSeverity:  pdata.SeverityNumberDEBUG,
+			expectedAttrs: map[string]string{
+				"facility": "user-level messages",
+				"appname":  "app",
+			},
+			expectedError: "unterminated structured data parameter note of meta",
+		},
+		{
+			name:              "rfc3164",
+			data:              `<34>Oct  1 22:14:15 mymachine su[123]: 'su root' failed for lonvick on /dev/pts/8`,
+			protocol:          ProtocolRFC3164,
+			expectedHostname:  "mymachine",
+			expectedTimestamp: time.Date(2021, time.October, 1, 22, 14, 15, 0, time.UTC),
+			expectedSeverity:  pdata.SeverityNumberFATAL,
+			expectedAttrs: map[string]string{
+				"facility": "security/authorization messages",
+				"appname":  "su",
+				"proc_id":  "123",
+			},
+		},
+		{
+			name:              "rfc3164 with rfc3339 timestamp",
+			data:              `<13>2022-01-05T11:00:00+01:00 host-1 app: message`,
+			protocol:          ProtocolRFC3164,
+			expectedHostname:  "host-1",
+			expectedTimestamp: now,
+			expectedSeverity:  pdata.SeverityNumberINFO2,
+			expectedAttrs: map[string]string{
+				"facility": "user-level messages",
+				"appname":  "app",
+			},
+		},
+		{
+			name:              "rfc3164 without tag",
+			data:              `<13>Jan  5 09:00:00 host-1 just a message`,
+			protocol:          ProtocolRFC3164,
+			expectedHostname:  "host-1",
+			expectedTimestamp: time.Date(2022, time.January, 5, 9, 0, 0, 0, time.UTC),
+			expectedSeverity:  pdata.SeverityNumberINFO2,
+			expectedAttrs: map[string]string{
+				"facility": "user-level messages",
+			},
+		},
+		{
+			name:              "rfc3164 without timestamp",
+			data:              `<13>app: just a message`,
+			protocol:          ProtocolRFC3164,
+			expectedTimestamp: now,
+			expectedSeverity:  pdata.SeverityNumberINFO2,
+			expectedAttrs: map[string]string{
+				"facility": "user-level messages",
+			},
+		},
+		{
+			name:              "missing priority",
+			data:              `just a message`,
+			protocol:          ProtocolRFC3164,
+			expectedTimestamp: now,
+			expectedAttrs:     map[string]string{},
+			expectedError:     "missing priority",
+		},
+		{
+			name:              "unexpected priority",
+			data:              `<192>1 - - - - - -`,
+			protocol:          ProtocolRFC5424,
+			expectedTimestamp: now,
+			expectedAttrs:     map[string]string{},
+			expectedError:     "unexpected priority: 192",
+		},
+	}
+
+	p := &parser{
+		location: time.UTC,
+		now:      func() time.Time { return now },
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := p.parse([]byte(tc.data), tc.protocol)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.data, m.record.Body().StringVal())
+			assert.Equal(t, tc.expectedHostname, m.hostname)
+			assert.Equal(t, tc.expectedTimestamp, m.record.Timestamp().AsTime())
+			assert.Equal(t, tc.expectedSeverity, m.record.SeverityNumber())
+
+			attrs := make(map[string]string)
+			m.record.Attributes().Range(func(k string, v pdata.AttributeValue) bool {
+				attrs[k] = v.StringVal()
+				return true
+			})
+			assert.Equal(t, tc.expectedAttrs, attrs)
+		})
+	}
+}
+
+func TestParseRFC3164Location(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Warsaw")
+	require.NoError(t, err)
+	p := &parser{
+		location: location,
+		now:      func() time.Time { return time.Date(2022, time.December, 31, 23, 30, 0, 0, time.UTC) },
+	}
+
+	// it's already January 1st in Warsaw
+	m, err := p.parse([]byte(`<13>Jan  1 00:15:00 host-1 app: message`), ProtocolRFC3164)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2022, time.December, 31, 23, 15, 0, 0, time.UTC), m.record.Timestamp().AsTime())
+}
+
+func TestMessagesToLogs(t *testing.T) {
+	p := &parser{location: time.UTC, now: time.Now}
+	var messages []message
+	for _, data := range []string{
+		`<13>1 - host-1 app - - - first`,
+		`<13>1 - host-2 app - - - second`,
+		`<13>1 - host-1 app - - - third`,
+		`<13>1 - - app - - - fourth`,
+	} {
+		m, err := p.parse([]byte(data), ProtocolRFC5424)
+		require.NoError(t, err)
+		messages = append(messages, m)
+	}
+
+	logs := messagesToLogs(messages)
+	require.Equal(t, 3, logs.ResourceLogs().Len())
+	assert.Equal(t, 4, logs.LogRecordCount())
+
+	host1 := logs.ResourceLogs().At(0)
+	host, ok := host1.Resource().Attributes().Get(attributeHostName)
+	require.True(t, ok)
+	assert.Equal(t, "host-1", host.StringVal())
+	assert.Equal(t, 2, host1.InstrumentationLibraryLogs().At(0).LogRecords().Len())
+
+	assert.Equal(t, 0, logs.ResourceLogs().At(2).Resource().Attributes().Len())
+}
@@ -0,0 +1,308 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicsyslogreceiver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.uber.org/zap"
+)
+
+type syslogReceiver struct {
+	config   *Config
+	logger   *zap.Logger
+	consumer consumer.Logs
+	parser   *parser
+
+	tcpListener net.Listener
+	udpConn     net.PacketConn
+
+	// conns are the open TCP connections, closed on shutdown
+	connsLock sync.Mutex
+	conns     map[net.Conn]struct{}
+	closed    bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ component.LogsReceiver = (*syslogReceiver)(nil)
+
+func newSyslogReceiver(cfg *Config, logger *zap.Logger, nextConsumer consumer.Logs) (*syslogReceiver, error) {
+	location, err := time.LoadLocation(cfg.Location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load location %s: %w", cfg.Location, err)
+	}
+
+	return &syslogReceiver{
+		config:   cfg,
+		logger:   logger,
+		consumer: nextConsumer,
+		parser: &parser{
+			location: location,
+			now:      time.Now,
+		},
+		conns: make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// Start starts listening on the configured endpoints
+func (r *syslogReceiver) Start(ctx context.Context, _ component.Host) error {
+	rctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	if r.config.TCP != nil {
+		if err := r.listenTCP(); err != nil {
+			_ = r.Shutdown(ctx)
+			return err
+		}
+		r.wg.Add(1)
+		go r.acceptTCP(rctx)
+	}
+
+	if r.config.UDP != nil {
+		conn, err := net.ListenPacket("udp", r.config.UDP.Endpoint)
+		if err != nil {
+			_ = r.Shutdown(ctx)
+			return fmt.Errorf("failed to listen on udp %s: %w", r.config.UDP.Endpoint, err)
+		}
+		r.udpConn = conn
+		r.wg.Add(1)
+		go r.receiveUDP(rctx)
+	}
+	return nil
+}
+
+// Shutdown closes the listeners and the open connections
+func (r *syslogReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	if r.tcpListener != nil {
+		_ = r.tcpListener.Close()
+	}
+	if r.udpConn != nil {
+		_ = r.udpConn.Close()
+	}
+
+	r.connsLock.Lock()
+	r.closed = true
+	for conn := range r.conns {
+		_ = conn.Close()
+	}
+	r.connsLock.Unlock()
+
+	r.wg.Wait()
+	return nil
+}
+
+func (r *syslogReceiver) listenTCP() error {
+	listener, err := net.Listen("tcp", r.config.TCP.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to listen on tcp %s: %w", r.config.TCP.Endpoint, err)
+	}
+
+	if r.config.TCP.TLS != nil {
+		tlsConfig, err := r.config.TCP.TLS.LoadTLSConfig()
+		if err != nil {
+			_ = listener.Close()
+			return fmt.Errorf("failed to load tls config: %w", err)
+		}
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	r.tcpListener = listener
+	return nil
+}
+
+// acceptTCP accepts the TCP connections until the listener is closed
+func (r *syslogReceiver) acceptTCP(ctx context.Context) {
+	defer r.wg.Done()
+
+	for {
+		conn, err := r.tcpListener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			r.logger.Error("Failed to accept TCP connection", zap.Error(err))
+			continue
+		}
+
+		if !r.trackConn(conn) {
+			_ = conn.Close()
+			return
+		}
+		r.wg.Add(1)
+		go r.handleTCP(ctx, conn)
+	}
+}
+
+// trackConn adds the connection to the ones closed on shutdown.
+// It returns false when the receiver is already shut down.
+func (r *syslogReceiver) trackConn(conn net.Conn) bool {
+	r.connsLock.Lock()
+	defer r.connsLock.Unlock()
+	if r.closed {
+		return false
+	}
+	r.conns[conn] = struct{}{}
+	return true
+}
+
+func (r *syslogReceiver) untrackConn(conn net.Conn) {
+	r.connsLock.Lock()
+	defer r.connsLock.Unlock()
+	delete(r.conns, conn)
+}
+
+// handleTCP reads the messages of the TCP connection until it's closed. The protocol
+// of the connection is detected from its first message, unless configured explicitly.
+func (r *syslogReceiver) handleTCP(ctx context.Context, conn net.Conn) {
+	defer r.wg.Done()
+	defer r.untrackConn(conn)
+	defer conn.Close()
+
+	reader := newFrameReader(conn, r.config.MaxMessageSize)
+	protocol := r.config.Protocol
+	batch := make([]message, 0, r.config.MaxBatchSize)
+
+	for {
+		if r.config.TCP.IdleTimeout > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(r.config.TCP.IdleTimeout))
+		}
+
+		data, err := reader.next()
+		if errors.Is(err, errMessageTooLarge) {
+			r.logger.Warn("Dropping syslog message larger than max_message_size",
+				zap.Stringer("peer", conn.RemoteAddr()),
+				zap.Int("max_message_size", r.config.MaxMessageSize),
+			)
+			continue
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				r.logger.Warn("Closing TCP connection", zap.Stringer("peer", conn.RemoteAddr()), zap.Error(err))
+			}
+			break
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		if protocol == ProtocolAuto {
+			protocol = detectProtocol(data)
+		}
+		batch = append(batch, r.parseMessage(data, protocol, conn.RemoteAddr(), transportTCP))
+
+		if len(batch) >= r.config.MaxBatchSize || !reader.buffered() {
+			r.consumeMessages(ctx, batch)
+			batch = make([]message, 0, r.config.MaxBatchSize)
+		}
+	}
+
+	if len(batch) > 0 {
+		r.consumeMessages(ctx, batch)
+	}
+}
+
+// receiveUDP reads the UDP datagrams until the connection is closed. Every datagram
+// is a single message and its protocol is detected, unless configured explicitly.
+func (r *syslogReceiver) receiveUDP(ctx context.Context) {
+	defer r.wg.Done()
+
+	// the datagrams larger than the buffer are truncated, so they're detected by its size
+	buf := make([]byte, r.config.MaxMessageSize+1)
+	for {
+		n, addr, err := r.udpConn.ReadFrom(buf)
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			r.logger.Error("Failed to read UDP datagram", zap.Error(err))
+			continue
+		}
+
+		data := bytes.TrimRight(buf[:n], "\r\n\x00")
+		if len(data) > r.config.MaxMessageSize {
+			r.logger.Warn("Dropping syslog message larger than max_message_size",
+				zap.Stringer("peer", addr),
+				zap.Int("max_message_size", r.config.MaxMessageSize),
+			)
+			continue
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		protocol := r.config.Protocol
+		if protocol == ProtocolAuto {
+			protocol = detectProtocol(data)
+		}
+		r.consumeMessages(ctx, []message{r.parseMessage(data, protocol, addr, transportUDP)})
+	}
+}
+
+// parseMessage converts the message to the log record, adding the peer address and the transport
+func (r *syslogReceiver) parseMessage(data []byte, protocol string, peer net.Addr, transport string) message {
+	m, err := r.parser.parse(data, protocol)
+	if err != nil {
+		r.logger.Debug("Failed to parse syslog message, passing it as is",
+			zap.String("protocol", protocol),
+			zap.Error(err),
+		)
+	}
+
+	attrs := m.record.Attributes()
+	if ip := peerIP(peer); ip != "" {
+		attrs.InsertString(attributePeerIP, ip)
+	}
+	attrs.InsertString(attributeTransport, transport)
+	return m
+}
+
+func (r *syslogReceiver) consumeMessages(ctx context.Context, messages []message) {
+	if err := r.consumer.ConsumeLogs(ctx, messagesToLogs(messages)); err != nil {
+		r.logger.Error("ConsumeLogs() error", zap.Error(err))
+	}
+}
+
+// peerIP returns the IP address of the peer
+func peerIP(addr net.Addr) string {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return a.IP.String()
+	case *net.UDPAddr:
+		return a.IP.String()
+	case nil:
+		return ""
+	default:
+		host, _, err := net.SplitHostPort(a.String())
+		if err != nil {
+			return ""
+		}
+		return host
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sumologicsyslogreceiver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+func startReceiver(t *testing.T, cfg *Config) (*syslogReceiver, *consumertest.LogsSink) {
+	sink := new(consumertest.LogsSink)
+	r, err := newSyslogReceiver(cfg, zap.NewNop(), sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	})
+	return r, sink
+}
+
+// records returns the log records received by the sink with the hostnames of their resources
+func records(sink *consumertest.LogsSink) ([]pdata.LogRecord, []string) {
+	var (
+		result    []pdata.LogRecord
+		hostnames []string
+	)
+	for _, logs := range sink.AllLogs() {
+		for i := 0; i < logs.ResourceLogs().Len(); i++ {
+			rl := logs.ResourceLogs().At(i)
+			hostname := ""
+			if v, ok := rl.Resource().Attributes().Get(attributeHostName); ok {
+				hostname = v.StringVal()
+			}
+			lrs := rl.InstrumentationLibraryLogs().At(0).LogRecords()
+			for j := 0; j < lrs.Len(); j++ {
+				result = append(result, lrs.At(j))
+				hostnames = append(hostnames, hostname)
+			}
+		}
+	}
+	return result, hostnames
+}
+
+func attribute(record pdata.LogRecord, key string) string {
+	v, ok := record.Attributes().Get(key)
+	if !ok {
+		return ""
+	}
+	return v.StringVal()
+}
+
+func TestReceiverTCP(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TCP = &TCPConfig{Endpoint: "localhost:0"}
+	r, sink := startReceiver(t, cfg)
+
+	rfc5424 := `<165>1 2003-10-11T22:14:15.003Z host-1 app - ID47 [meta key="value"] octet counted`
+	conn, err := net.Dial("tcp", r.tcpListener.Addr().String())
+	require.NoError(t, err)
+	_, err = fmt.Fprintf(conn, "<165>1 - host-1 app - - - first\n%d %s", len(rfc5424), rfc5424)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the protocol is detected for every connection
+	conn, err = net.Dial("tcp", r.tcpListener.Addr().String())
+	require.NoError(t, err)
+	_, err = fmt.Fprint(conn, "<34>Oct 11 22:14:15 host-2 su[123]: rfc3164\n")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	records, hostnames := records(sink)
+	assert.Equal(t, []string{"host-1", "host-1", "host-2"}, hostnames)
+	assert.Equal(t, "<165>1 - host-1 app - - - first", records[0].Body().StringVal())
+	assert.Equal(t, rfc5424, records[1].Body().StringVal())
+	assert.Equal(t, "value", attribute(records[1], "structured_data.meta.key"))
+	assert.Equal(t, "ID47", attribute(records[1], attributeMsgID))
+	assert.Equal(t, "su", attribute(records[2], attributeAppName))
+	assert.Equal(t, "123", attribute(records[2], attributeProcID))
+	for _, record := range records {
+		assert.Equal(t, "127.0.0.1", attribute(record, attributePeerIP))
+		assert.Equal(t, transportTCP, attribute(record, attributeTransport))
+	}
+}
+
+func TestReceiverUDP(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.UDP = &UDPConfig{Endpoint: "localhost:0"}
+	cfg.MaxMessageSize = 64
+	r, sink := startReceiver(t, cfg)
+
+	conn, err := net.Dial("udp", r.udpConn.LocalAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	for _, datagram := range []string{
+		"<13>1 - host-1 app - - - rfc5424\n",
+		"<13>" + strings.Repeat("x", 64),
+		"<13>Oct 11 22:14:15 host-2 app: rfc3164",
+	} {
+		_, err = conn.Write([]byte(datagram))
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	records, hostnames := records(sink)
+	assert.Equal(t, []string{"host-1", "host-2"}, hostnames)
+	assert.Equal(t, "<13>1 - host-1 app - - - rfc5424", records[0].Body().StringVal())
+	assert.Equal(t, "app", attribute(records[1], attributeAppName))
+	assert.Equal(t, transportUDP, attribute(records[1], attributeTransport))
+}
+
+func TestReceiverTLS(t *testing.T) {
+	certFile, keyFile := writeCertificate(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.TCP = &TCPConfig{
+		Endpoint: "localhost:0",
+		TLS: &configtls.TLSServerSetting{
+			TLSSetting: configtls.TLSSetting{
+				CertFile: certFile,
+				KeyFile:  keyFile,
+			},
+		},
+	}
+	r, sink := startReceiver(t, cfg)
+
+	conn, err := tls.Dial("tcp", r.tcpListener.Addr().String(), &tls.Config{
+		InsecureSkipVerify: true, // #nosec G402 the certificate is self-signed
+	})
+	require.NoError(t, err)
+	_, err = fmt.Fprint(conn, "<13>1 - host-1 app - - - encrypted\n")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestReceiverShutdownClosesConnections(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TCP = &TCPConfig{Endpoint: "localhost:0"}
+	sink := new(consumertest.LogsSink)
+	r, err := newSyslogReceiver(cfg, zap.NewNop(), sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	conn, err := net.Dial("tcp", r.tcpListener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = fmt.Fprint(conn, "<13>1 - host-1 app - - - first\n")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the connection is still open, Shutdown doesn't wait for the client to close it
+	require.NoError(t, r.Shutdown(context.Background()))
+	_, err = conn.Read(make([]byte, 1))
+	assert.Error(t, err)
+}
+
+// writeCertificate writes the self-signed certificate and its key to the temporary directory
+func writeCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
@@ -0,0 +1,132 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telegrafreceiver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	telegrafagent "github.com/influxdata/telegraf/agent"
+	telegrafconfig "github.com/influxdata/telegraf/config"
+)
+
+const (
+	inputsTablePrefix      = "inputs."
+	processorsTablePrefix  = "processors."
+	aggregatorsTablePrefix = "aggregators."
+)
+
+// tableHeaderRegex matches TOML table headers, e.g. `[agent]` or `[[inputs.cpu]] # comment`
+var tableHeaderRegex = regexp.MustCompile(`^\s*\[\[?\s*([A-Za-z0-9_.\-]+)\s*\]\]?\s*(#.*)?$`)
+
+// agentConfig is the telegraf configuration split into the part shared by all
+// input plugins (e.g. [agent] and [global_tags] tables) and the configurations
+// of the individual input plugins, so that each input plugin can be run
+// (and reloaded) by a separate telegraf agent. The processor and aggregator plugins
+// are kept apart, as they are run once for the metrics of all the input plugins.
+type agentConfig struct {
+	common string
+	// pipeline is the configuration of the processor and aggregator plugins
+	pipeline string
+	// inputs maps the key identifying the input plugin configuration to the configuration
+	inputs map[string]string
+}
+
+// parseAgentConfig splits the telegraf configuration per input plugin
+func parseAgentConfig(cfg string) agentConfig {
+	var (
+		common   []string
+		pipeline []string
+		inputs   []string
+		current  []string
+		// inPipeline is set within the processor and aggregator tables
+		inPipeline bool
+	)
+
+	flush := func() {
+		if current != nil {
+			inputs = append(inputs, strings.Join(current, "\n"))
+			current = nil
+		}
+	}
+
+	for _, line := range strings.Split(cfg, "\n") {
+		if m := tableHeaderRegex.FindStringSubmatch(line); m != nil {
+			name := m[1]
+			isArray := strings.HasPrefix(strings.TrimSpace(line), "[[")
+			switch {
+			case isArray && strings.HasPrefix(name, inputsTablePrefix) &&
+				!strings.Contains(strings.TrimPrefix(name, inputsTablePrefix), "."):
+				// [[inputs.<name>]] starts the next input plugin
+				flush()
+				inPipeline = false
+				current = []string{line}
+				continue
+			case current != nil && strings.HasPrefix(name, inputsTablePrefix):
+				// sub-table of the current input plugin, e.g. [inputs.<name>.tags]
+			case strings.HasPrefix(name, processorsTablePrefix) || strings.HasPrefix(name, aggregatorsTablePrefix):
+				// [[processors.<name>]], [[aggregators.<name>]] or their sub-tables
+				flush()
+				inPipeline = true
+			default:
+				flush()
+				inPipeline = false
+			}
+		}
+
+		switch {
+		case current != nil:
+			current = append(current, line)
+		case inPipeline:
+			pipeline = append(pipeline, line)
+		default:
+			common = append(common, line)
+		}
+	}
+	flush()
+
+	ac := agentConfig{
+		common:   strings.TrimSpace(strings.Join(common, "\n")),
+		pipeline: strings.TrimSpace(strings.Join(pipeline, "\n")),
+		inputs:   make(map[string]string, len(inputs)),
+	}
+	for _, input := range inputs {
+		input = strings.TrimSpace(input)
+		// Identical input plugins are allowed, so disambiguate them with a counter
+		key := input
+		for i := 1; ; i++ {
+			if _, ok := ac.inputs[key]; !ok {
+				break
+			}
+			key = fmt.Sprintf("%s#%d", input, i)
+		}
+		ac.inputs[key] = input
+	}
+	return ac
+}
+
+// newAgent creates the telegraf agent running a single input plugin
+func newAgent(common string, input string) (*telegrafagent.Agent, error) {
+	tConfig := telegrafconfig.NewConfig()
+	if err := tConfig.LoadConfigData([]byte(common + "\n" + input)); err != nil {
+		return nil, fmt.Errorf("failed loading telegraf agent config: %w", err)
+	}
+	tAgent, err := telegrafagent.NewAgent(tConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating telegraf agent: %w", err)
+	}
+	return tAgent, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telegrafreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAgentConfig(t *testing.T) {
+	ac := parseAgentConfig(`
+[agent]
+  interval = "2s"
+
+[[inputs.mem]]
+
+[[inputs.cpu]] # all cpus
+  percpu = true
+  [inputs.cpu.tags]
+    team = "infra"
+
+[global_tags]
+  env = "dev"
+
+[[inputs.disk]]
+  mount_points = [
+    "/",
+  ]
+[[inputs.mem]]
+`)
+
+	assert.Equal(t, "[agent]\n  interval = \"2s\"\n\n[global_tags]\n  env = \"dev\"", ac.common)
+	cpu := "[[inputs.cpu]] # all cpus\n  percpu = true\n  [inputs.cpu.tags]\n    team = \"infra\""
+	disk := "[[inputs.disk]]\n  mount_points = [\n    \"/\",\n  ]"
+	assert.Equal(t, map[string]string{
+		"[[inputs.mem]]":   "[[inputs.mem]]",
+		"[[inputs.mem]]#1": "[[inputs.mem]]",
+		cpu:                cpu,
+		disk:               disk,
+	}, ac.inputs)
+}
+
+func TestParseAgentConfigPipeline(t *testing.T) {
+	ac := parseAgentConfig(`
+[agent]
+  interval = "2s"
+
+[[inputs.mem]]
+
+[[aggregators.basicstats]]
+  period = "30s"
+  [aggregators.basicstats.tags]
+    aggregated = "true"
+
+[[processors.rename]]
+  order = 1
+
+[global_tags]
+  env = "dev"
+`)
+
+	assert.Equal(t, "[agent]\n  interval = \"2s\"\n\n[global_tags]\n  env = \"dev\"", ac.common)
+	assert.Equal(t, "[[aggregators.basicstats]]\n  period = \"30s\"\n  [aggregators.basicstats.tags]\n    aggregated = \"true\"\n\n[[processors.rename]]\n  order = 1", ac.pipeline)
+	assert.Equal(t, map[string]string{"[[inputs.mem]]": "[[inputs.mem]]"}, ac.inputs)
+}
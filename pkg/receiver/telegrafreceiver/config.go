@@ -15,6 +15,10 @@
 package telegrafreceiver
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"go.opentelemetry.io/collector/config"
 )
 
@@ -27,8 +31,106 @@ type Config struct {
 	// by them will be passed through to otc pipeline for processing and export.
 	AgentConfig string `mapstructure:"agent_config"`
 
+	// AgentConfigFile is the path of the telegraf configuration file,
+	// it can be used instead of AgentConfig.
+	AgentConfigFile string `mapstructure:"agent_config_file"`
+
+	// Reload configures reloading of AgentConfigFile without restarting the collector.
+	Reload ReloadConfig `mapstructure:"reload"`
+
+	// InternalMetrics configures passing the telegraf internal statistics
+	// (e.g. gather errors and timings per input plugin) to the pipeline.
+	InternalMetrics InternalMetricsConfig `mapstructure:"internal_metrics"`
+
+	// Buffer limits the number of the gathered metrics waiting to be passed to the pipeline.
+	Buffer BufferConfig `mapstructure:"buffer"`
+
 	// SeparateField controls whether the ingested metrics should have a field
 	// concatenated with metric name like e.g. metric=mem_available or maybe rather
 	// have it as a separate label like e.g. metric=mem field=available
 	SeparateField bool `mapstructure:"separate_field"`
+
+	// ResourceAttributes selects, per input plugin, the telegraf tags which are added
+	// as resource attributes. The remaining tags of the plugin's metrics are added as
+	// data point attributes. Metrics of plugins which are not listed have all their
+	// tags added as resource attributes.
+	ResourceAttributes []ResourceAttributesConfig `mapstructure:"resource_attributes"`
+}
+
+// ResourceAttributesConfig defines which tags of the input plugin's metrics are resource attributes.
+type ResourceAttributesConfig struct {
+	// Plugin is the name of the telegraf metric, which is the name of the input plugin
+	// unless it's changed with e.g. name_override.
+	Plugin string `mapstructure:"plugin"`
+
+	// Tags is the list of the tags which are added as resource attributes.
+	Tags []string `mapstructure:"tags"`
+}
+
+// ReloadConfig defines when the telegraf configuration file is reloaded.
+// Only the input plugins which configuration has changed are restarted.
+type ReloadConfig struct {
+	// OnSIGHUP makes the receiver reload the configuration when the collector receives SIGHUP.
+	OnSIGHUP bool `mapstructure:"on_sighup"`
+
+	// CheckInterval defines how often the configuration file is checked for changes.
+	// 0 disables the checks.
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+}
+
+// InternalMetricsConfig defines how the telegraf internal statistics are collected.
+type InternalMetricsConfig struct {
+	// Enabled defines whether the internal statistics are passed to the pipeline.
+	// By default this is false.
+	Enabled bool `mapstructure:"enabled"`
+
+	// CollectionInterval defines how often the internal statistics are collected.
+	// By default this is 1m.
+	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+}
+
+// BufferConfig defines the buffer between the telegraf agents and the pipeline.
+type BufferConfig struct {
+	// MaxSize is the maximum number of telegraf metrics waiting to be passed to the pipeline.
+	// By default this is 1000.
+	MaxSize int `mapstructure:"max_size"`
+
+	// BlockTimeout defines how long the telegraf agents are blocked when the buffer is full,
+	// before the metric is dropped. 0 blocks them until there is room in the buffer,
+	// so no metrics are dropped. By default this is 0.
+	BlockTimeout time.Duration `mapstructure:"block_timeout"`
+}
+
+// Validate checks if the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.AgentConfig != "" && cfg.AgentConfigFile != "" {
+		return errors.New("only one of agent_config and agent_config_file can be specified")
+	}
+	if cfg.Reload.CheckInterval < 0 {
+		return fmt.Errorf("reload: check_interval cannot be negative: %s", cfg.Reload.CheckInterval)
+	}
+	if cfg.AgentConfigFile == "" && (cfg.Reload.OnSIGHUP || cfg.Reload.CheckInterval > 0) {
+		return errors.New("reload requires agent_config_file to be specified")
+	}
+	if cfg.InternalMetrics.Enabled && cfg.InternalMetrics.CollectionInterval <= 0 {
+		return fmt.Errorf("internal_metrics: collection_interval has to be positive: %s", cfg.InternalMetrics.CollectionInterval)
+	}
+	if cfg.Buffer.MaxSize <= 0 {
+		return fmt.Errorf("buffer: max_size has to be positive: %d", cfg.Buffer.MaxSize)
+	}
+	if cfg.Buffer.BlockTimeout < 0 {
+		return fmt.Errorf("buffer: block_timeout cannot be negative: %s", cfg.Buffer.BlockTimeout)
+	}
+
+	plugins := make(map[string]struct{}, len(cfg.ResourceAttributes))
+	for _, ra := range cfg.ResourceAttributes {
+		if ra.Plugin == "" {
+			return fmt.Errorf("resource_attributes: plugin name cannot be empty")
+		}
+		if _, ok := plugins[ra.Plugin]; ok {
+			return fmt.Errorf("resource_attributes: plugin %s is configured more than once", ra.Plugin)
+		}
+		plugins[ra.Plugin] = struct{}{}
+	}
+	return nil
 }
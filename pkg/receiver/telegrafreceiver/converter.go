@@ -32,12 +32,24 @@ type MetricConverter interface {
 
 type metricConverter struct {
 	separateField bool
-	logger        *zap.Logger
+	// resourceTags contains the tags which are resource attributes, per metric name
+	resourceTags map[string]map[string]struct{}
+	logger       *zap.Logger
 }
 
-func newConverter(separateField bool, logger *zap.Logger) MetricConverter {
+func newConverter(separateField bool, resourceAttributes []ResourceAttributesConfig, logger *zap.Logger) MetricConverter {
+	resourceTags := make(map[string]map[string]struct{}, len(resourceAttributes))
+	for _, ra := range resourceAttributes {
+		tags := make(map[string]struct{}, len(ra.Tags))
+		for _, tag := range ra.Tags {
+			tags[tag] = struct{}{}
+		}
+		resourceTags[ra.Plugin] = tags
+	}
+
 	return metricConverter{
 		separateField: separateField,
+		resourceTags:  resourceTags,
 		logger:        logger,
 	}
 }
@@ -48,9 +60,16 @@ func (mc metricConverter) Convert(m telegraf.Metric) (pdata.Metrics, error) {
 	rms := ms.ResourceMetrics()
 	rm := rms.AppendEmpty()
 
-	// Attach tags as resource attributes.
+	// Attach tags as resource attributes, unless the mapping for the plugin
+	// specifies them as data point attributes.
+	resourceTags, mapped := mc.resourceTags[m.Name()]
+	var dataPointTags []*telegraf.Tag
 	rAttributes := rm.Resource().Attributes()
 	for _, t := range m.TagList() {
+		if _, ok := resourceTags[t.Key]; mapped && !ok {
+			dataPointTags = append(dataPointTags, t)
+			continue
+		}
 		rAttributes.InsertString(t.Key, t.Value)
 	}
 
@@ -76,6 +95,9 @@ func (mc metricConverter) Convert(m telegraf.Metric) (pdata.Metrics, error) {
 
 		WithTime(tim),
 	}
+	if len(dataPointTags) > 0 {
+		opts = append(opts, WithTags(dataPointTags))
+	}
 
 	switch t := m.Type(); t {
 	case telegraf.Gauge:
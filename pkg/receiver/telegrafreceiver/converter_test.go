@@ -556,7 +556,7 @@ func TestConverter(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			m := tt.metricsFn()
 
-			mc := newConverter(tt.separateField, zap.NewNop())
+			mc := newConverter(tt.separateField, nil, zap.NewNop())
 			out, err := mc.Convert(m)
 
 			if tt.expectedErr {
@@ -581,6 +581,53 @@ func TestConverter(t *testing.T) {
 	}
 }
 
+func TestConverterResourceAttributes(t *testing.T) {
+	tim := time.Now()
+	tags := map[string]string{
+		"host": "localhost",
+		"cpu":  "cpu0",
+	}
+	fields := map[string]interface{}{
+		"usage_idle": 99.5,
+	}
+
+	mc := newConverter(false, []ResourceAttributesConfig{
+		{Plugin: "cpu", Tags: []string{"host"}},
+	}, zap.NewNop())
+
+	t.Run("mapped_plugin", func(t *testing.T) {
+		m := metric.New("cpu", tags, fields, tim, telegraf.Gauge)
+		out, err := mc.Convert(m)
+		require.NoError(t, err)
+
+		rm := out.ResourceMetrics().At(0)
+		assert.Equal(t, 1, rm.Resource().Attributes().Len())
+		assertResourceAttributes(t, m.TagList(), rm.Resource())
+
+		expected := pdata.NewMetricSlice()
+		newDoubleGauge(99.5,
+			WithName("cpu_usage_idle"),
+			WithTime(tim),
+			WithTag(&telegraf.Tag{Key: "cpu", Value: "cpu0"}),
+		).CopyTo(expected.AppendEmpty())
+
+		actual := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+		require.Equal(t, expected.Len(), actual.Len())
+		pdataMetricSlicesAreEqual(t, expected, actual)
+	})
+
+	t.Run("not_mapped_plugin", func(t *testing.T) {
+		m := metric.New("system", tags, fields, tim, telegraf.Gauge)
+		out, err := mc.Convert(m)
+		require.NoError(t, err)
+
+		rm := out.ResourceMetrics().At(0)
+		assert.Equal(t, 2, rm.Resource().Attributes().Len())
+		assertResourceAttributes(t, m.TagList(), rm.Resource())
+		assert.Equal(t, 0, rm.InstrumentationLibraryMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0).Attributes().Len())
+	})
+}
+
 func assertResourceAttributes(t *testing.T, tags []*telegraf.Tag, resource pdata.Resource) {
 	resource.Attributes().Range(func(k string, v pdata.AttributeValue) bool {
 		var found bool
@@ -17,8 +17,10 @@ package telegrafreceiver
 import (
 	"context"
 	"fmt"
+	"os"
+	"time"
 
-	telegrafagent "github.com/influxdata/telegraf/agent"
+	"github.com/influxdata/telegraf"
 	telegrafconfig "github.com/influxdata/telegraf/config"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
@@ -28,6 +30,9 @@ import (
 const (
 	typeStr    = "telegraf"
 	versionStr = "v0.1"
+
+	defaultInternalMetricsCollectionInterval = time.Minute
+	defaultBufferMaxSize                     = 1000
 )
 
 // NewFactory creates a factory for telegraf receiver.
@@ -47,6 +52,12 @@ func createDefaultConfig() config.Receiver {
 	return &Config{
 		ReceiverSettings: &rs,
 		SeparateField:    false,
+		InternalMetrics: InternalMetricsConfig{
+			CollectionInterval: defaultInternalMetricsCollectionInterval,
+		},
+		Buffer: BufferConfig{
+			MaxSize: defaultBufferMaxSize,
+		},
 	}
 }
 
@@ -62,19 +73,41 @@ func createMetricsReceiver(
 		return nil, fmt.Errorf("failed reading telegraf agent config from otc config")
 	}
 
-	tConfig := telegrafconfig.NewConfig()
-	if err := tConfig.LoadConfigData([]byte(tCfg.AgentConfig)); err != nil {
+	rawConfig := tCfg.AgentConfig
+	if tCfg.AgentConfigFile != "" {
+		data, err := os.ReadFile(tCfg.AgentConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading telegraf agent config: %w", err)
+		}
+		rawConfig = string(data)
+	}
+
+	ac := parseAgentConfig(rawConfig)
+	// Validate the shared part of the configuration even if there are no input plugins
+	if err := telegrafconfig.NewConfig().LoadConfigData([]byte(ac.common)); err != nil {
 		return nil, fmt.Errorf("failed loading telegraf agent config: %w", err)
 	}
-	tAgent, err := telegrafagent.NewAgent(tConfig)
+	agents, err := newAgents(ac)
+	if err != nil {
+		return nil, err
+	}
+	p, err := newPipeline(ac.common, ac.pipeline)
 	if err != nil {
-		return nil, fmt.Errorf("failed creating telegraf agent: %w", err)
+		return nil, err
 	}
 
 	return &telegrafreceiver{
-		agent:           tAgent,
-		consumer:        nextConsumer,
-		logger:          params.Logger,
-		metricConverter: newConverter(tCfg.SeparateField, params.Logger),
+		agentConfigFile:       tCfg.AgentConfigFile,
+		reloadConfig:          tCfg.Reload,
+		internalMetricsConfig: tCfg.InternalMetrics,
+		bufferConfig:          tCfg.Buffer,
+		buffer:                make(chan telegraf.Metric, tCfg.Buffer.MaxSize),
+		rawConfig:             rawConfig,
+		config:                ac,
+		agents:                agents,
+		pipeline:              p,
+		consumer:              nextConsumer,
+		logger:                params.Logger,
+		metricConverter:       newConverter(tCfg.SeparateField, tCfg.ResourceAttributes, params.Logger),
 	}, nil
 }
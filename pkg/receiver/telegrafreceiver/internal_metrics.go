@@ -0,0 +1,60 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telegrafreceiver
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/telegraf/selfstat"
+	"go.uber.org/zap"
+)
+
+// collectInternalMetrics periodically passes the telegraf internal statistics
+// (e.g. internal_gather errors per input plugin) to the pipeline
+func (r *telegrafreceiver) collectInternalMetrics(ctx context.Context) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.internalMetricsConfig.CollectionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.consumeInternalMetrics(ctx)
+		}
+	}
+}
+
+func (r *telegrafreceiver) consumeInternalMetrics(ctx context.Context) {
+	for _, m := range selfstat.Metrics() {
+		ms, err := r.metricConverter.Convert(m)
+		if err != nil {
+			r.logger.Debug("Error converting telegraf internal metric",
+				zap.String("name", m.Name()),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		if err := r.consumer.ConsumeMetrics(ctx, ms); err != nil {
+			r.logger.Error("ConsumeMetrics() error",
+				zap.String("error", err.Error()),
+			)
+		}
+	}
+}
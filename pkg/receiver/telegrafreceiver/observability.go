@@ -0,0 +1,64 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telegrafreceiver
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+func init() {
+	err := view.Register(
+		viewMetricsDropped,
+		viewBufferSize,
+	)
+	if err != nil {
+		fmt.Printf("Error registering telegraf receiver's views: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+var (
+	mMetricsDropped = stats.Int64("otelsvc/telegraf/buffer_metrics_dropped", "Number of telegraf metrics dropped because the buffer was full", "1")
+	mBufferSize     = stats.Int64("otelsvc/telegraf/buffer_size", "Number of telegraf metrics waiting in the buffer", "1")
+)
+
+var viewMetricsDropped = &view.View{
+	Name:        mMetricsDropped.Name(),
+	Description: mMetricsDropped.Description(),
+	Measure:     mMetricsDropped,
+	Aggregation: view.Sum(),
+}
+
+var viewBufferSize = &view.View{
+	Name:        mBufferSize.Name(),
+	Description: mBufferSize.Description(),
+	Measure:     mBufferSize,
+	Aggregation: view.LastValue(),
+}
+
+// recordMetricDropped increments the metric that the telegraf metric was dropped
+func recordMetricDropped() {
+	stats.Record(context.Background(), mMetricsDropped.M(int64(1)))
+}
+
+// recordBufferSize records the number of the telegraf metrics in the buffer
+func recordBufferSize(size int) {
+	stats.Record(context.Background(), mBufferSize.M(int64(size)))
+}
@@ -0,0 +1,300 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telegrafreceiver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	telegrafagent "github.com/influxdata/telegraf/agent"
+	telegrafconfig "github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/models"
+)
+
+// pipelineChannelSize is the size of the channels between the processor and aggregator plugins
+const pipelineChannelSize = 100
+
+// pipeline runs the telegraf processor and aggregator plugins on the metrics gathered
+// by all the input plugins, the same way the telegraf agent does it, before the metrics
+// are converted. Since each input plugin is run by a separate telegraf agent,
+// they are not part of the agents and are shared by all of them instead.
+type pipeline struct {
+	config *telegrafconfig.Config
+	// in receives the metrics gathered by the input plugins,
+	// it has to be closed to stop the pipeline
+	in chan<- telegraf.Metric
+	// done is closed when the pipeline stopped and all the metrics were passed on
+	done chan struct{}
+}
+
+// processorUnit is a processor plugin with its input and output channels
+type processorUnit struct {
+	src       chan telegraf.Metric
+	dst       chan<- telegraf.Metric
+	processor *models.RunningProcessor
+}
+
+// newPipeline creates the pipeline of the processor and aggregator plugins,
+// it returns nil if there are no such plugins in the configuration
+func newPipeline(common string, plugins string) (*pipeline, error) {
+	if plugins == "" {
+		return nil, nil
+	}
+
+	tConfig := telegrafconfig.NewConfig()
+	if err := tConfig.LoadConfigData([]byte(common + "\n" + plugins)); err != nil {
+		return nil, fmt.Errorf("failed loading telegraf processors and aggregators config: %w", err)
+	}
+	for _, processor := range tConfig.Processors {
+		if err := processor.Init(); err != nil {
+			return nil, fmt.Errorf("could not initialize processor %s: %w", processor.LogName(), err)
+		}
+	}
+	for _, aggregator := range tConfig.Aggregators {
+		if err := aggregator.Init(); err != nil {
+			return nil, fmt.Errorf("could not initialize aggregator %s: %w", aggregator.LogName(), err)
+		}
+	}
+	for _, processor := range tConfig.AggProcessors {
+		if err := processor.Init(); err != nil {
+			return nil, fmt.Errorf("could not initialize processor %s: %w", processor.LogName(), err)
+		}
+	}
+
+	return &pipeline{config: tConfig}, nil
+}
+
+// start starts the plugins, which pass the resulting metrics to out.
+// The pipeline runs until its input channel is closed.
+func (p *pipeline) start(out func(telegraf.Metric)) error {
+	outC := make(chan telegraf.Metric, pipelineChannelSize)
+	next := outC
+
+	var (
+		units    []*processorUnit
+		aggUnits []*processorUnit
+		aggSrc   chan telegraf.Metric
+		aggC     chan telegraf.Metric
+		err      error
+	)
+	if len(p.config.Aggregators) > 0 {
+		aggC = next
+		if len(p.config.AggProcessors) > 0 {
+			aggC, aggUnits, err = startProcessors(next, p.config.AggProcessors)
+			if err != nil {
+				return err
+			}
+		}
+		aggSrc = make(chan telegraf.Metric, pipelineChannelSize)
+		next = aggSrc
+	}
+	if len(p.config.Processors) > 0 {
+		next, units, err = startProcessors(next, p.config.Processors)
+		if err != nil {
+			stopProcessors(aggUnits)
+			return err
+		}
+	}
+
+	if aggSrc != nil {
+		go p.runAggregators(aggSrc, aggC, outC)
+	}
+	for _, unit := range append(units, aggUnits...) {
+		go runProcessor(unit)
+	}
+
+	p.in = next
+	p.done = make(chan struct{})
+	go func() {
+		defer close(p.done)
+		for m := range outC {
+			out(m)
+		}
+	}()
+	return nil
+}
+
+// stop closes the input of the pipeline and waits until all the metrics,
+// including the ones pushed by the aggregators on stop, were passed on
+func (p *pipeline) stop() {
+	close(p.in)
+	<-p.done
+}
+
+// startProcessors starts the chain of the processor plugins writing to dst
+// and returns the channel receiving the metrics for the first one
+func startProcessors(dst chan telegraf.Metric, processors models.RunningProcessors) (chan telegraf.Metric, []*processorUnit, error) {
+	var units []*processorUnit
+
+	// Sort from last to first
+	sort.SliceStable(processors, func(i, j int) bool {
+		return processors[i].Config.Order > processors[j].Config.Order
+	})
+
+	src := dst
+	for _, processor := range processors {
+		src = make(chan telegraf.Metric, pipelineChannelSize)
+		if err := processor.Start(telegrafagent.NewAccumulator(processor, dst)); err != nil {
+			stopProcessors(units)
+			return nil, nil, fmt.Errorf("starting processor %s: %w", processor.LogName(), err)
+		}
+
+		units = append(units, &processorUnit{
+			src:       src,
+			dst:       dst,
+			processor: processor,
+		})
+		dst = src
+	}
+	return src, units, nil
+}
+
+// stopProcessors stops the started processor plugins which are not running yet
+func stopProcessors(units []*processorUnit) {
+	for _, unit := range units {
+		unit.processor.Stop()
+	}
+}
+
+// runProcessor passes the metrics through the processor until its source channel is closed
+func runProcessor(unit *processorUnit) {
+	acc := telegrafagent.NewAccumulator(unit.processor, unit.dst)
+	for m := range unit.src {
+		if err := unit.processor.Add(m, acc); err != nil {
+			acc.AddError(err)
+			m.Drop()
+		}
+	}
+	unit.processor.Stop()
+	close(unit.dst)
+}
+
+// runAggregators adds the metrics from src to all the aggregator plugins, which push
+// the aggregates to aggC every period. The original metrics are passed to outC unless
+// an aggregator drops them. It runs until src is closed and then pushes the aggregates
+// one last time.
+func (p *pipeline) runAggregators(src <-chan telegraf.Metric, aggC chan<- telegraf.Metric, outC chan<- telegraf.Metric) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Initialize the aggregation window before adding any metric,
+	// so any metric created after the start is aggregated
+	start := time.Now()
+	for _, agg := range p.config.Aggregators {
+		since, until := updateWindow(start, p.config.Agent.RoundInterval, agg.Period())
+		agg.UpdateWindow(since, until)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		for m := range src {
+			var dropOriginal bool
+			for _, agg := range p.config.Aggregators {
+				if ok := agg.Add(m); ok {
+					dropOriginal = true
+				}
+			}
+
+			if dropOriginal {
+				m.Drop()
+			} else {
+				outC <- m
+			}
+		}
+	}()
+
+	interval := time.Duration(p.config.Agent.Interval)
+	precision := time.Duration(p.config.Agent.Precision)
+	for _, agg := range p.config.Aggregators {
+		wg.Add(1)
+		go func(agg *models.RunningAggregator) {
+			defer wg.Done()
+			acc := telegrafagent.NewAccumulator(agg, aggC)
+			acc.SetPrecision(getPrecision(precision, interval))
+			pushAggregates(ctx, agg, acc)
+		}(agg)
+	}
+
+	wg.Wait()
+
+	// Without the processors for the aggregates, aggC and outC are the same channel,
+	// otherwise the processors close outC when they are done
+	close(aggC)
+}
+
+// pushAggregates pushes the aggregates at the end of every period until the context is cancelled
+func pushAggregates(ctx context.Context, agg *models.RunningAggregator, acc telegraf.Accumulator) {
+	for {
+		// Push is called for each period, even if it already elapsed, without drifting
+		// as only Push updates the end of the period
+		timer := time.NewTimer(time.Until(agg.EndPeriod()))
+		select {
+		case <-timer.C:
+			agg.Push(acc)
+		case <-ctx.Done():
+			timer.Stop()
+			agg.Push(acc)
+			return
+		}
+	}
+}
+
+// updateWindow returns the first aggregation window after start
+func updateWindow(start time.Time, roundInterval bool, period time.Duration) (time.Time, time.Time) {
+	var until time.Time
+	if roundInterval {
+		until = alignTime(start, period)
+		if until == start {
+			until = alignTime(start.Add(time.Nanosecond), period)
+		}
+	} else {
+		until = start.Add(period)
+	}
+	return until.Add(-period), until
+}
+
+// alignTime returns the time of the next interval, or the time itself if it's aligned
+func alignTime(tm time.Time, interval time.Duration) time.Time {
+	truncated := tm.Truncate(interval)
+	if truncated == tm {
+		return tm
+	}
+	return truncated.Add(interval)
+}
+
+// getPrecision returns the precision of the metric timestamps,
+// which defaults to the one matching the collection interval
+func getPrecision(precision, interval time.Duration) time.Duration {
+	if precision > 0 {
+		return precision
+	}
+
+	switch {
+	case interval >= time.Second:
+		return time.Second
+	case interval >= time.Millisecond:
+		return time.Millisecond
+	case interval >= time.Microsecond:
+		return time.Microsecond
+	default:
+		return time.Nanosecond
+	}
+}
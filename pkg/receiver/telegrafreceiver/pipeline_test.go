@@ -0,0 +1,80 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telegrafreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineNotConfigured(t *testing.T) {
+	p, err := newPipeline("[agent]\n  interval = \"2s\"", "")
+	require.NoError(t, err)
+	assert.Nil(t, p)
+}
+
+func TestPipelineInvalid(t *testing.T) {
+	_, err := newPipeline("", "[[aggregators.not_existing]]")
+	assert.Error(t, err)
+}
+
+func TestPipelineAggregators(t *testing.T) {
+	p, err := newPipeline("[agent]\n  interval = \"2s\"", `
+[[aggregators.minmax]]
+  period = "1h"
+  drop_original = true
+`)
+	require.NoError(t, err)
+	require.NotNil(t, p)
+
+	var metrics []telegraf.Metric
+	require.NoError(t, p.start(func(m telegraf.Metric) {
+		metrics = append(metrics, m)
+	}))
+
+	now := time.Now()
+	for _, v := range []int64{3, 1, 2} {
+		p.in <- metric.New("mem", map[string]string{"host": "a"}, map[string]interface{}{"used": v}, now)
+	}
+	// The aggregates are pushed on stop
+	p.stop()
+
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "mem", metrics[0].Name())
+	assert.Equal(t, map[string]interface{}{"used_min": float64(1), "used_max": float64(3)}, metrics[0].Fields())
+}
+
+func TestPipelineKeepsOriginals(t *testing.T) {
+	p, err := newPipeline("", `
+[[aggregators.minmax]]
+  period = "1h"
+`)
+	require.NoError(t, err)
+
+	var names []string
+	require.NoError(t, p.start(func(m telegraf.Metric) {
+		names = append(names, m.Name())
+	}))
+
+	p.in <- metric.New("mem", nil, map[string]interface{}{"used": int64(1)}, time.Now())
+	p.stop()
+
+	assert.ElementsMatch(t, []string{"mem", "mem"}, names)
+}
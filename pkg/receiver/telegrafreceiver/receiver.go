@@ -17,13 +17,17 @@ package telegrafreceiver
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"os/signal"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/influxdata/telegraf"
 	telegrafagent "github.com/influxdata/telegraf/agent"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/model/pdata"
 	"go.uber.org/zap"
 )
 
@@ -39,16 +43,55 @@ type telegrafreceiver struct {
 	wg        sync.WaitGroup
 	cancel    context.CancelFunc
 
-	agent           *telegrafagent.Agent
+	// agentConfigFile is the path of the telegraf configuration, empty if it's provided inline
+	agentConfigFile       string
+	reloadConfig          ReloadConfig
+	internalMetricsConfig InternalMetricsConfig
+	bufferConfig          BufferConfig
+
+	// buffer holds the metrics gathered by all the agents until they are passed to the pipeline
+	buffer chan telegraf.Metric
+	// bufferDone is closed when all the buffered metrics were consumed
+	bufferDone chan struct{}
+
+	// agentsLock guards the fields below which change on configuration reload
+	agentsLock sync.Mutex
+	ctx        context.Context
+	rawConfig  string
+	config     agentConfig
+	// agents maps the key of the input plugin configuration to the agent running it
+	agents map[string]*inputAgent
+	// pipeline runs the processor and aggregator plugins, nil if there are none
+	pipeline *pipeline
+
 	consumer        consumer.Metrics
 	logger          *zap.Logger
 	metricConverter MetricConverter
 }
 
-// Ensure this receiver adheres to required interface.
+// inputAgent is the telegraf agent running a single input plugin
+type inputAgent struct {
+	agent  *telegrafagent.Agent
+	cancel context.CancelFunc
+	// done is closed when the agent stopped and all its metrics were buffered
+	done chan struct{}
+}
+
 var _ component.MetricsReceiver = (*telegrafreceiver)(nil)
 
-// Start tells the receiver to start.
+// newAgents creates the agents for all the input plugins in the configuration
+func newAgents(ac agentConfig) (map[string]*inputAgent, error) {
+	agents := make(map[string]*inputAgent, len(ac.inputs))
+	for key, input := range ac.inputs {
+		agent, err := newAgent(ac.common, input)
+		if err != nil {
+			return nil, err
+		}
+		agents[key] = &inputAgent{agent: agent}
+	}
+	return agents, nil
+}
+
 func (r *telegrafreceiver) Start(ctx context.Context, host component.Host) error {
 	r.logger.Info("Starting telegraf receiver")
 
@@ -61,52 +104,264 @@ func (r *telegrafreceiver) Start(ctx context.Context, host component.Host) error
 		rctx, cancel := context.WithCancel(ctx)
 		r.cancel = cancel
 
-		ch := make(chan telegraf.Metric)
+		r.bufferDone = make(chan struct{})
+		go r.consumeBuffer()
 
-		r.wg.Add(1)
-		go func() {
-			defer r.wg.Done()
-			if rErr := r.agent.RunWithChannel(rctx, ch); rErr != nil {
-				r.logger.Error("Problem starting receiver", zap.Error(rErr))
+		r.agentsLock.Lock()
+		r.ctx = rctx
+		if r.pipeline != nil {
+			if err = r.startPipeline(r.pipeline); err != nil {
+				r.pipeline = nil
+				r.agentsLock.Unlock()
+				cancel()
+				return
 			}
-		}()
-
-		r.wg.Add(1)
-		go func() {
-			var fErr error
-			defer r.wg.Done()
-			// Telegraf expects its input plugins to always be able to write to this channel while running,
-			// and if we stop reading from it while there's still active plugins, we'll get a deadlock.
-			// As such, this loop only exits when the channel is closed by Telegraf itself.
-			for m := range ch {
-				if m == nil {
-					r.logger.Info("got nil from channel")
-					continue
-				}
-
-				var ms pdata.Metrics
-				if ms, fErr = r.metricConverter.Convert(m); fErr != nil {
-					r.logger.Error(
-						"Error converting telegraf.Metric to pdata.Metrics",
-						zap.Error(fErr),
-					)
-					continue
-				}
-
-				if fErr = r.consumer.ConsumeMetrics(rctx, ms); fErr != nil {
-					r.logger.Error("ConsumeMetrics() error",
-						zap.String("error", fErr.Error()),
-					)
-				}
-			}
-		}()
+		}
+		for _, ia := range r.agents {
+			r.runAgent(ia)
+		}
+		r.agentsLock.Unlock()
+
+		if r.agentConfigFile != "" && (r.reloadConfig.OnSIGHUP || r.reloadConfig.CheckInterval > 0) {
+			r.wg.Add(1)
+			go r.watchConfig(rctx)
+		}
+
+		if r.internalMetricsConfig.Enabled {
+			r.wg.Add(1)
+			go r.collectInternalMetrics(rctx)
+		}
 	})
 
 	return err
 }
 
-// Shutdown is invoked during service shutdown.
-func (r *telegrafreceiver) Shutdown(context.Context) error {
+// startPipeline starts the processor and aggregator plugins, which pass the resulting
+// metrics to the buffer. The pipeline is stopped after all the agents feeding it.
+func (r *telegrafreceiver) startPipeline(p *pipeline) error {
+	return p.start(func(m telegraf.Metric) {
+		// The pipeline is flushed on shutdown, so don't drop the metrics
+		// only because the receiver is being stopped
+		r.bufferMetric(context.Background(), m)
+	})
+}
+
+// runAgent starts the agent and consumes the metrics it gathers, it has to be called with agentsLock held
+func (r *telegrafreceiver) runAgent(ia *inputAgent) {
+	actx, cancel := context.WithCancel(r.ctx)
+	p := r.pipeline
+	ia.cancel = cancel
+	ia.done = make(chan struct{})
+
+	ch := make(chan telegraf.Metric)
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if rErr := ia.agent.RunWithChannel(actx, ch); rErr != nil {
+			r.logger.Error("Problem starting receiver", zap.Error(rErr))
+		}
+	}()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer close(ia.done)
+		// Telegraf expects its input plugins to always be able to write to this channel while running,
+		// and if we stop reading from it while there's still active plugins, we'll get a deadlock.
+		// As such, this loop only exits when the channel is closed by Telegraf itself.
+		for m := range ch {
+			if m == nil {
+				r.logger.Info("got nil from channel")
+				continue
+			}
+			if p != nil {
+				p.in <- m
+				continue
+			}
+			r.bufferMetric(actx, m)
+		}
+	}()
+}
+
+// bufferMetric puts the metric in the buffer. When the buffer is full, it blocks
+// the agent until there is room in the buffer or, when the timeout is configured,
+// for up to the timeout and then drops the metric.
+// It returns false if the metric was dropped.
+func (r *telegrafreceiver) bufferMetric(ctx context.Context, m telegraf.Metric) bool {
+	select {
+	case r.buffer <- m:
+		return true
+	default:
+	}
+
+	// timeout is nil, so it never fires, unless the timeout is configured
+	var timeout <-chan time.Time
+	if r.bufferConfig.BlockTimeout > 0 {
+		timer := time.NewTimer(r.bufferConfig.BlockTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case r.buffer <- m:
+		return true
+	case <-timeout:
+	case <-ctx.Done():
+	}
+
+	recordMetricDropped()
+	r.logger.Debug("Telegraf metrics buffer is full, dropping metric",
+		zap.String("name", m.Name()),
+		zap.Int("max_size", r.bufferConfig.MaxSize),
+	)
+	return false
+}
+
+// consumeBuffer passes the buffered metrics to the pipeline until the buffer is closed
+func (r *telegrafreceiver) consumeBuffer() {
+	defer close(r.bufferDone)
+
+	for m := range r.buffer {
+		recordBufferSize(len(r.buffer))
+
+		ms, err := r.metricConverter.Convert(m)
+		if err != nil {
+			r.logger.Error(
+				"Error converting telegraf.Metric to pdata.Metrics",
+				zap.Error(err),
+			)
+			continue
+		}
+
+		if err = r.consumer.ConsumeMetrics(context.Background(), ms); err != nil {
+			r.logger.Error("ConsumeMetrics() error",
+				zap.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// watchConfig reloads the configuration on SIGHUP or when the configuration file changes
+func (r *telegrafreceiver) watchConfig(ctx context.Context) {
+	defer r.wg.Done()
+
+	var sighup chan os.Signal
+	if r.reloadConfig.OnSIGHUP {
+		sighup = make(chan os.Signal, 1)
+		signal.Notify(sighup, syscall.SIGHUP)
+		defer signal.Stop(sighup)
+	}
+
+	var tick <-chan time.Time
+	if r.reloadConfig.CheckInterval > 0 {
+		ticker := time.NewTicker(r.reloadConfig.CheckInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sighup:
+		case <-tick:
+		}
+
+		if err := r.reload(); err != nil {
+			r.logger.Error("Failed to reload telegraf configuration, keeping the previous one",
+				zap.String("file", r.agentConfigFile),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// reload reads the configuration file and restarts the input plugins which configuration
+// has changed. All the plugins are restarted when the configuration shared by them
+// (e.g. the [agent] table) or the configuration of the processor and aggregator plugins
+// has changed.
+func (r *telegrafreceiver) reload() error {
+	data, err := os.ReadFile(r.agentConfigFile)
+	if err != nil {
+		return fmt.Errorf("failed reading telegraf agent config: %w", err)
+	}
+
+	r.agentsLock.Lock()
+	defer r.agentsLock.Unlock()
+
+	if r.ctx == nil || r.ctx.Err() != nil {
+		// not started or already stopped
+		return nil
+	}
+	if string(data) == r.rawConfig {
+		return nil
+	}
+
+	ac := parseAgentConfig(string(data))
+	restartAll := ac.common != r.config.common || ac.pipeline != r.config.pipeline
+
+	// Create all the new agents first, so the running ones are not stopped
+	// when the new configuration is invalid
+	created := make(map[string]*inputAgent)
+	for key, input := range ac.inputs {
+		if _, ok := r.agents[key]; ok && !restartAll {
+			continue
+		}
+		agent, err := newAgent(ac.common, input)
+		if err != nil {
+			return err
+		}
+		created[key] = &inputAgent{agent: agent}
+	}
+
+	p := r.pipeline
+	if restartAll {
+		if p, err = newPipeline(ac.common, ac.pipeline); err != nil {
+			return err
+		}
+		if p != nil {
+			if err = r.startPipeline(p); err != nil {
+				return err
+			}
+		}
+	}
+
+	stopped := 0
+	for key, ia := range r.agents {
+		if _, ok := ac.inputs[key]; ok && !restartAll {
+			continue
+		}
+		ia.cancel()
+		<-ia.done
+		delete(r.agents, key)
+		stopped++
+	}
+
+	if p != r.pipeline && r.pipeline != nil {
+		// All the agents feeding the previous pipeline are stopped
+		r.pipeline.stop()
+	}
+	r.pipeline = p
+
+	for key, ia := range created {
+		r.runAgent(ia)
+		r.agents[key] = ia
+	}
+
+	r.rawConfig = string(data)
+	r.config = ac
+
+	r.logger.Info("Reloaded telegraf configuration",
+		zap.String("file", r.agentConfigFile),
+		zap.Int("stopped_inputs", stopped),
+		zap.Int("started_inputs", len(created)),
+		zap.Int("running_inputs", len(r.agents)),
+	)
+	return nil
+}
+
+func (r *telegrafreceiver) Shutdown(ctx context.Context) error {
 	r.Lock()
 	defer r.Unlock()
 
@@ -116,6 +371,21 @@ func (r *telegrafreceiver) Shutdown(context.Context) error {
 		r.cancel()
 		r.wg.Wait()
 		err = nil
+
+		// All the agents are stopped, so flush the processors and aggregators
+		r.agentsLock.Lock()
+		if r.pipeline != nil {
+			r.pipeline.stop()
+		}
+		r.agentsLock.Unlock()
+
+		// All the agents are stopped, so pass the remaining buffered metrics to the pipeline
+		close(r.buffer)
+		select {
+		case <-r.bufferDone:
+		case <-ctx.Done():
+			err = fmt.Errorf("failed to consume buffered metrics: %w", ctx.Err())
+		}
 	})
 	return err
 }
@@ -16,11 +16,19 @@ package telegrafreceiver
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/selfstat"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
 )
 
 func createTestConfig() *Config {
@@ -42,3 +50,164 @@ func TestStartShutdown(t *testing.T) {
 	require.NoError(t, receiver.Start(ctx, componenttest.NewNopHost()))
 	require.NoError(t, receiver.Shutdown(ctx))
 }
+
+func TestReload(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "telegraf.conf")
+	agentSection := "[agent]\n  interval = \"2s\"\n"
+	require.NoError(t, os.WriteFile(file, []byte(agentSection+"[[inputs.mem]]\n"), 0o600))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.AgentConfigFile = file
+	cfg.Reload.CheckInterval = time.Hour
+	require.NoError(t, cfg.Validate())
+
+	receiver, err := createMetricsReceiver(ctx, componenttest.NewNopReceiverCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	r := receiver.(*telegrafreceiver)
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, r.Shutdown(ctx)) })
+
+	mem := r.agents["[[inputs.mem]]"]
+	require.NotNil(t, mem)
+
+	// Adding an input plugin doesn't restart the other ones
+	require.NoError(t, os.WriteFile(file, []byte(agentSection+"[[inputs.mem]]\n[[inputs.swap]]\n"), 0o600))
+	require.NoError(t, r.reload())
+	assert.Len(t, r.agents, 2)
+	assert.Same(t, mem, r.agents["[[inputs.mem]]"])
+	assert.NotNil(t, r.agents["[[inputs.swap]]"])
+
+	// Invalid configuration keeps the running plugins
+	require.NoError(t, os.WriteFile(file, []byte(agentSection+"[[inputs.mem]]\n[[inputs.not_existing]]\n"), 0o600))
+	assert.Error(t, r.reload())
+	assert.Len(t, r.agents, 2)
+
+	// Changing the agent configuration restarts all the plugins
+	require.NoError(t, os.WriteFile(file, []byte("[agent]\n  interval = \"5s\"\n[[inputs.mem]]\n"), 0o600))
+	require.NoError(t, r.reload())
+	assert.Len(t, r.agents, 1)
+	assert.NotSame(t, mem, r.agents["[[inputs.mem]]"])
+
+	// Adding an aggregator plugin restarts all the plugins
+	mem = r.agents["[[inputs.mem]]"]
+	require.NoError(t, os.WriteFile(file, []byte("[agent]\n  interval = \"5s\"\n[[inputs.mem]]\n[[aggregators.minmax]]\n"), 0o600))
+	require.NoError(t, r.reload())
+	assert.NotNil(t, r.pipeline)
+	assert.NotSame(t, mem, r.agents["[[inputs.mem]]"])
+}
+
+func TestValidateReload(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Reload.OnSIGHUP = true
+	assert.EqualError(t, cfg.Validate(), "reload requires agent_config_file to be specified")
+
+	cfg.AgentConfigFile = "telegraf.conf"
+	assert.EqualError(t, cfg.Validate(), "only one of agent_config and agent_config_file can be specified")
+}
+
+func TestConsumeInternalMetrics(t *testing.T) {
+	selfstat.Register("test_gather", "errors", map[string]string{"input": "test"}).Incr(3)
+
+	sink := new(consumertest.MetricsSink)
+	r := &telegrafreceiver{
+		consumer:        sink,
+		logger:          zap.NewNop(),
+		metricConverter: newConverter(false, nil, zap.NewNop()),
+	}
+	r.consumeInternalMetrics(context.Background())
+
+	var found bool
+	for _, md := range sink.AllMetrics() {
+		rm := md.ResourceMetrics().At(0)
+		metric := rm.InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+		if metric.Name() != "internal_test_gather_errors" {
+			continue
+		}
+		found = true
+
+		input, ok := rm.Resource().Attributes().Get("input")
+		require.True(t, ok)
+		assert.Equal(t, "test", input.StringVal())
+		assert.EqualValues(t, 3, metric.Gauge().DataPoints().At(0).IntVal())
+	}
+	assert.True(t, found, "internal metric not found")
+}
+
+func TestValidateInternalMetrics(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.InternalMetrics.Enabled = true
+	require.NoError(t, cfg.Validate())
+
+	cfg.InternalMetrics.CollectionInterval = 0
+	assert.EqualError(t, cfg.Validate(), "internal_metrics: collection_interval has to be positive: 0s")
+}
+
+func TestBufferMetric(t *testing.T) {
+	r := &telegrafreceiver{
+		logger: zap.NewNop(),
+		bufferConfig: BufferConfig{
+			MaxSize:      1,
+			BlockTimeout: 10 * time.Millisecond,
+		},
+		buffer: make(chan telegraf.Metric, 1),
+	}
+
+	m := metric.New("mem", nil, map[string]interface{}{"available": int64(1)}, time.Now())
+	assert.True(t, r.bufferMetric(context.Background(), m))
+	assert.False(t, r.bufferMetric(context.Background(), m), "metric should be dropped when the buffer is full")
+
+	<-r.buffer
+	assert.True(t, r.bufferMetric(context.Background(), m))
+}
+
+func TestBufferMetricBlocksWithoutTimeout(t *testing.T) {
+	r := &telegrafreceiver{
+		logger:       zap.NewNop(),
+		bufferConfig: BufferConfig{MaxSize: 1},
+		buffer:       make(chan telegraf.Metric, 1),
+	}
+
+	m := metric.New("mem", nil, map[string]interface{}{"available": int64(1)}, time.Now())
+	require.True(t, r.bufferMetric(context.Background(), m))
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		<-r.buffer
+	}()
+	assert.True(t, r.bufferMetric(context.Background(), m), "metric should not be dropped without block_timeout")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, r.bufferMetric(ctx, m), "metric should be dropped when the receiver is stopped")
+}
+
+func TestBufferMetricBlocksUntilConsumed(t *testing.T) {
+	r := &telegrafreceiver{
+		logger: zap.NewNop(),
+		bufferConfig: BufferConfig{
+			MaxSize:      1,
+			BlockTimeout: time.Minute,
+		},
+		buffer: make(chan telegraf.Metric, 1),
+	}
+
+	m := metric.New("mem", nil, map[string]interface{}{"available": int64(1)}, time.Now())
+	require.True(t, r.bufferMetric(context.Background(), m))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-r.buffer
+	}()
+	assert.True(t, r.bufferMetric(context.Background(), m))
+}
+
+func TestValidateBuffer(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Buffer.MaxSize = 0
+	assert.EqualError(t, cfg.Validate(), "buffer: max_size has to be positive: 0")
+
+	cfg = createTestConfig()
+	cfg.Buffer.BlockTimeout = -time.Second
+	assert.EqualError(t, cfg.Validate(), "buffer: block_timeout cannot be negative: -1s")
+}
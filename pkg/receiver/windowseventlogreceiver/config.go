@@ -0,0 +1,89 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowseventlogreceiver
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+const (
+	// StartAtBeginning makes the receiver read all the events of the channel when there is no persisted bookmark
+	StartAtBeginning = "beginning"
+	// StartAtEnd makes the receiver read only the new events when there is no persisted bookmark
+	StartAtEnd = "end"
+)
+
+// Config defines configuration for the Windows Event Log receiver.
+type Config struct {
+	config.ReceiverSettings `mapstructure:",squash"`
+
+	// Channel is the name of the channel to read the events from, e.g. Application.
+	Channel string `mapstructure:"channel"`
+
+	// Query is the XPath query selecting the events of the channel.
+	// By default this is *, which selects all of them.
+	Query string `mapstructure:"query"`
+
+	// StartAt defines where to start reading the channel when there is no persisted bookmark,
+	// either beginning or end.
+	// By default this is end.
+	StartAt string `mapstructure:"start_at"`
+
+	// RenderMessage specifies whether the message of the event is rendered using
+	// the message files of its provider, like in the Event Viewer.
+	// By default this is true.
+	RenderMessage bool `mapstructure:"render_message"`
+
+	// StorageID is the ID of the storage extension used to persist the bookmark of the last
+	// read event, so the receiver resumes from it after restart.
+	// When not set, the bookmark is kept in memory only.
+	StorageID *config.ComponentID `mapstructure:"storage"`
+
+	// MaxReads is the maximum number of the events read and passed to the pipeline at once.
+	// By default this is 100.
+	MaxReads int `mapstructure:"max_reads"`
+
+	// PollInterval defines how often the channel is checked for the new events.
+	// By default this is 1s.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+}
+
+var _ config.Receiver = (*Config)(nil)
+
+// Validate checks if the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.Channel == "" {
+		return errors.New("channel cannot be empty")
+	}
+	if cfg.Query == "" {
+		return errors.New("query cannot be empty")
+	}
+	switch cfg.StartAt {
+	case StartAtBeginning, StartAtEnd:
+	default:
+		return fmt.Errorf("unexpected start_at: %s", cfg.StartAt)
+	}
+	if cfg.MaxReads <= 0 {
+		return fmt.Errorf("max_reads has to be positive: %d", cfg.MaxReads)
+	}
+	if cfg.PollInterval <= 0 {
+		return fmt.Errorf("poll_interval has to be positive: %s", cfg.PollInterval)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowseventlogreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+
+	cfg, err := servicetest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	defaultCfg := factory.CreateDefaultConfig().(*Config)
+	defaultCfg.Channel = "Application"
+	assert.Equal(t, defaultCfg, cfg.Receivers[config.NewComponentID(typeStr)])
+
+	storageID := config.NewComponentID("file_storage")
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: config.NewReceiverSettings(config.NewComponentIDWithName(typeStr, "custom")),
+			Channel:          "Security",
+			Query:            "*[System[(Level=1 or Level=2)]]",
+			StartAt:          StartAtBeginning,
+			RenderMessage:    false,
+			StorageID:        &storageID,
+			MaxReads:         50,
+			PollInterval:     10 * time.Second,
+		},
+		cfg.Receivers[config.NewComponentIDWithName(typeStr, "custom")],
+	)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name          string
+		modify        func(cfg *Config)
+		expectedError string
+	}{
+		{
+			name:   "channel set",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:          "empty channel",
+			modify:        func(cfg *Config) { cfg.Channel = "" },
+			expectedError: "channel cannot be empty",
+		},
+		{
+			name:          "empty query",
+			modify:        func(cfg *Config) { cfg.Query = "" },
+			expectedError: "query cannot be empty",
+		},
+		{
+			name:          "unexpected start_at",
+			modify:        func(cfg *Config) { cfg.StartAt = "middle" },
+			expectedError: "unexpected start_at: middle",
+		},
+		{
+			name:          "zero max reads",
+			modify:        func(cfg *Config) { cfg.MaxReads = 0 },
+			expectedError: "max_reads has to be positive: 0",
+		},
+		{
+			name:          "zero poll interval",
+			modify:        func(cfg *Config) { cfg.PollInterval = 0 },
+			expectedError: "poll_interval has to be positive: 0s",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Channel = "Application"
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
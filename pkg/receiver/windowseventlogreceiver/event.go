@@ -0,0 +1,212 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowseventlogreceiver
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	// attributeHostName is the resource attribute with the computer of the event,
+	// translated to `host` by the sumologicexporter
+	attributeHostName = "host.name"
+
+	attributeChannel      = "windows.channel"
+	attributeProviderName = "windows.provider.name"
+	attributeProviderGUID = "windows.provider.guid"
+	attributeEventID      = "windows.event.id"
+	attributeRecordID     = "windows.event.record_id"
+	attributeTask         = "windows.task"
+	attributeOpcode       = "windows.opcode"
+	attributeKeywords     = "windows.keywords"
+	attributeProcessID    = "windows.process_id"
+	attributeThreadID     = "windows.thread_id"
+	attributeUserID       = "windows.user_id"
+	// attributeEventDataPrefix prefixes the names of the event data items
+	attributeEventDataPrefix = "windows.event_data."
+)
+
+// levels maps the event level, which is the index, to its name and the log severity
+var levels = []struct {
+	name     string
+	severity pdata.SeverityNumber
+}{
+	// the events logged regardless of the level, e.g. the security audit events
+	{name: "Information", severity: pdata.SeverityNumberINFO},
+	{name: "Critical", severity: pdata.SeverityNumberFATAL},
+	{name: "Error", severity: pdata.SeverityNumberERROR},
+	{name: "Warning", severity: pdata.SeverityNumberWARN},
+	{name: "Information", severity: pdata.SeverityNumberINFO},
+	{name: "Verbose", severity: pdata.SeverityNumberDEBUG},
+}
+
+// eventXML is the event rendered as XML, with the rendering info
+// when the event was formatted using the message files of its provider
+type eventXML struct {
+	System struct {
+		Provider struct {
+			Name string `xml:"Name,attr"`
+			GUID string `xml:"Guid,attr"`
+		} `xml:"Provider"`
+		EventID     uint32 `xml:"EventID"`
+		Level       int    `xml:"Level"`
+		Task        string `xml:"Task"`
+		Opcode      string `xml:"Opcode"`
+		Keywords    string `xml:"Keywords"`
+		TimeCreated struct {
+			SystemTime string `xml:"SystemTime,attr"`
+		} `xml:"TimeCreated"`
+		EventRecordID uint64 `xml:"EventRecordID"`
+		Execution     struct {
+			ProcessID string `xml:"ProcessID,attr"`
+			ThreadID  string `xml:"ThreadID,attr"`
+		} `xml:"Execution"`
+		Channel  string `xml:"Channel"`
+		Computer string `xml:"Computer"`
+		Security struct {
+			UserID string `xml:"UserID,attr"`
+		} `xml:"Security"`
+	} `xml:"System"`
+	EventData struct {
+		Data []struct {
+			Name  string `xml:"Name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"Data"`
+	} `xml:"EventData"`
+	RenderingInfo *struct {
+		Message  string   `xml:"Message"`
+		Level    string   `xml:"Level"`
+		Task     string   `xml:"Task"`
+		Opcode   string   `xml:"Opcode"`
+		Keywords []string `xml:"Keywords>Keyword"`
+	} `xml:"RenderingInfo"`
+}
+
+// event is the Windows event converted to the log record
+type event struct {
+	computer string
+	record   pdata.LogRecord
+}
+
+// providerName returns the name of the provider of the event rendered as XML
+func providerName(data string) (string, error) {
+	var e eventXML
+	if err := xml.Unmarshal([]byte(data), &e); err != nil {
+		return "", fmt.Errorf("failed to decode event: %w", err)
+	}
+	return e.System.Provider.Name, nil
+}
+
+// parseEvent converts the event rendered as XML. The body is the rendered message,
+// or the whole XML when the event wasn't formatted using the message files of its provider.
+func parseEvent(data string) (event, error) {
+	var e eventXML
+	if err := xml.Unmarshal([]byte(data), &e); err != nil {
+		return event{}, fmt.Errorf("failed to decode event: %w", err)
+	}
+
+	record := pdata.NewLogRecord()
+	system := e.System
+
+	if system.TimeCreated.SystemTime != "" {
+		timestamp, err := time.Parse(time.RFC3339Nano, system.TimeCreated.SystemTime)
+		if err != nil {
+			return event{}, fmt.Errorf("failed to parse the time of the event: %w", err)
+		}
+		record.SetTimestamp(pdata.NewTimestampFromTime(timestamp))
+	}
+
+	if system.Level >= 0 && system.Level < len(levels) {
+		record.SetSeverityNumber(levels[system.Level].severity)
+		record.SetSeverityText(levels[system.Level].name)
+	}
+
+	attrs := record.Attributes()
+	attrs.InsertString(attributeChannel, system.Channel)
+	attrs.InsertString(attributeProviderName, system.Provider.Name)
+	if system.Provider.GUID != "" {
+		attrs.InsertString(attributeProviderGUID, system.Provider.GUID)
+	}
+	attrs.InsertInt(attributeEventID, int64(system.EventID))
+	attrs.InsertInt(attributeRecordID, int64(system.EventRecordID))
+	insertNotEmpty(attrs, attributeTask, system.Task)
+	insertNotEmpty(attrs, attributeOpcode, system.Opcode)
+	insertNotEmpty(attrs, attributeKeywords, system.Keywords)
+	insertNotEmpty(attrs, attributeProcessID, system.Execution.ProcessID)
+	insertNotEmpty(attrs, attributeThreadID, system.Execution.ThreadID)
+	insertNotEmpty(attrs, attributeUserID, system.Security.UserID)
+
+	for i, data := range e.EventData.Data {
+		name := data.Name
+		if name == "" {
+			// the classic events have the data items without names
+			name = "param" + strconv.Itoa(i+1)
+		}
+		attrs.InsertString(attributeEventDataPrefix+name, data.Value)
+	}
+
+	if info := e.RenderingInfo; info != nil {
+		record.Body().SetStringVal(strings.TrimSpace(info.Message))
+		if info.Level != "" {
+			record.SetSeverityText(info.Level)
+		}
+		insertNotEmpty(attrs, attributeTask, info.Task)
+		insertNotEmpty(attrs, attributeOpcode, info.Opcode)
+		if len(info.Keywords) > 0 {
+			attrs.UpsertString(attributeKeywords, strings.Join(info.Keywords, ", "))
+		}
+	} else {
+		record.Body().SetStringVal(data)
+	}
+
+	return event{
+		computer: system.Computer,
+		record:   record,
+	}, nil
+}
+
+// insertNotEmpty sets the attribute when the value isn't empty,
+// the rendered values override the numeric ones
+func insertNotEmpty(attrs pdata.AttributeMap, key string, value string) {
+	if value != "" {
+		attrs.UpsertString(key, value)
+	}
+}
+
+// eventsToLogs groups the events by the computer, which is added as resource attribute
+func eventsToLogs(events []event) pdata.Logs {
+	logs := pdata.NewLogs()
+	resources := make(map[string]pdata.LogRecordSlice)
+
+	for _, e := range events {
+		records, ok := resources[e.computer]
+		if !ok {
+			rl := logs.ResourceLogs().AppendEmpty()
+			if e.computer != "" {
+				rl.Resource().Attributes().InsertString(attributeHostName, e.computer)
+			}
+			records = rl.InstrumentationLibraryLogs().AppendEmpty().LogRecords()
+			resources[e.computer] = records
+		}
+		e.record.CopyTo(records.AppendEmpty())
+	}
+	return logs
+}
@@ -0,0 +1,135 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowseventlogreceiver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func readTestEvent(t *testing.T, name string) string {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestParseRenderedEvent(t *testing.T) {
+	e, err := parseEvent(readTestEvent(t, "rendered.xml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "WIN-HOST", e.computer)
+	assert.Equal(t, "The Windows Update service entered the running state.", e.record.Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberINFO, e.record.SeverityNumber())
+	assert.Equal(t, "Information", e.record.SeverityText())
+	assert.Equal(t,
+		pdata.NewTimestampFromTime(time.Date(2022, 4, 22, 10, 20, 52, 377862500, time.UTC)),
+		e.record.Timestamp(),
+	)
+
+	assert.Equal(t, map[string]interface{}{
+		attributeChannel:                    "System",
+		attributeProviderName:               "Service Control Manager",
+		attributeProviderGUID:               "{555908d1-a6d7-4695-8e1e-26931d2012f4}",
+		attributeEventID:                    int64(7036),
+		attributeRecordID:                   int64(7468),
+		attributeTask:                       "0",
+		attributeOpcode:                     "0",
+		attributeKeywords:                   "Classic",
+		attributeProcessID:                  "652",
+		attributeThreadID:                   "6340",
+		attributeEventDataPrefix + "param1": "Windows Update",
+		attributeEventDataPrefix + "param2": "running",
+	}, attributesToMap(e.record.Attributes()))
+}
+
+func TestParseRawEvent(t *testing.T) {
+	data := readTestEvent(t, "raw.xml")
+	e, err := parseEvent(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, "WIN-APP", e.computer)
+	// the event without the rendering info is passed as XML
+	assert.Equal(t, data, e.record.Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberERROR, e.record.SeverityNumber())
+	assert.Equal(t, "Error", e.record.SeverityText())
+
+	assert.Equal(t, map[string]interface{}{
+		attributeChannel:                    "Application",
+		attributeProviderName:               "MyApp",
+		attributeEventID:                    int64(1000),
+		attributeRecordID:                   int64(512),
+		attributeTask:                       "0",
+		attributeKeywords:                   "0x80000000000000",
+		attributeUserID:                     "S-1-5-18",
+		attributeEventDataPrefix + "param1": "first value",
+		attributeEventDataPrefix + "param2": "second value",
+	}, attributesToMap(e.record.Attributes()))
+}
+
+func TestParseInvalidEvent(t *testing.T) {
+	_, err := parseEvent("<Event><System>")
+	assert.Error(t, err)
+
+	_, err = parseEvent("<Event><System><TimeCreated SystemTime='yesterday'/></System></Event>")
+	assert.Error(t, err)
+}
+
+func TestProviderName(t *testing.T) {
+	name, err := providerName(readTestEvent(t, "raw.xml"))
+	require.NoError(t, err)
+	assert.Equal(t, "MyApp", name)
+}
+
+func TestEventsToLogs(t *testing.T) {
+	rendered, err := parseEvent(readTestEvent(t, "rendered.xml"))
+	require.NoError(t, err)
+	raw, err := parseEvent(readTestEvent(t, "raw.xml"))
+	require.NoError(t, err)
+
+	logs := eventsToLogs([]event{rendered, raw, rendered})
+	require.Equal(t, 2, logs.ResourceLogs().Len())
+
+	host := logs.ResourceLogs().At(0)
+	hostName, ok := host.Resource().Attributes().Get(attributeHostName)
+	require.True(t, ok)
+	assert.Equal(t, "WIN-HOST", hostName.StringVal())
+	assert.Equal(t, 2, host.InstrumentationLibraryLogs().At(0).LogRecords().Len())
+
+	app := logs.ResourceLogs().At(1)
+	hostName, ok = app.Resource().Attributes().Get(attributeHostName)
+	require.True(t, ok)
+	assert.Equal(t, "WIN-APP", hostName.StringVal())
+	assert.Equal(t, 1, app.InstrumentationLibraryLogs().At(0).LogRecords().Len())
+}
+
+func attributesToMap(attrs pdata.AttributeMap) map[string]interface{} {
+	m := make(map[string]interface{}, attrs.Len())
+	attrs.Range(func(k string, v pdata.AttributeValue) bool {
+		switch v.Type() {
+		case pdata.AttributeValueTypeInt:
+			m[k] = v.IntVal()
+		default:
+			m[k] = v.AsString()
+		}
+		return true
+	})
+	return m
+}
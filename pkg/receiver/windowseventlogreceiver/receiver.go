@@ -0,0 +1,186 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowseventlogreceiver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+const bookmarkStorageKey = "bookmark"
+
+// eventSource reads the events of the channel
+type eventSource interface {
+	// open subscribes to the channel, starting after the bookmark if it's not empty
+	open(bookmark string) error
+	// read returns up to MaxReads events rendered as XML and the bookmark of the last one,
+	// or no events when there are no new ones
+	read() ([]string, string, error)
+	// close releases the subscription
+	close() error
+}
+
+type windowsEventLogReceiver struct {
+	config   *Config
+	logger   *zap.Logger
+	consumer consumer.Logs
+	source   eventSource
+
+	// bookmark is the bookmark of the last event passed to the pipeline
+	bookmark string
+	client   storage.Client
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ component.LogsReceiver = (*windowsEventLogReceiver)(nil)
+
+func newWindowsEventLogReceiver(cfg *Config, logger *zap.Logger, nextConsumer consumer.Logs) *windowsEventLogReceiver {
+	return &windowsEventLogReceiver{
+		config:   cfg,
+		logger:   logger,
+		consumer: nextConsumer,
+		source:   newEventSource(cfg, logger),
+	}
+}
+
+// Start loads the persisted bookmark and starts reading the channel
+func (r *windowsEventLogReceiver) Start(ctx context.Context, host component.Host) error {
+	if r.config.StorageID != nil {
+		client, err := getStorageClient(ctx, host, *r.config.StorageID, r.config.ID())
+		if err != nil {
+			return err
+		}
+		r.client = client
+
+		data, err := client.Get(ctx, bookmarkStorageKey)
+		if err != nil {
+			return fmt.Errorf("failed to load the bookmark: %w", err)
+		}
+		r.bookmark = string(data)
+	}
+
+	if err := r.source.open(r.bookmark); err != nil {
+		return fmt.Errorf("failed to subscribe to channel %s: %w", r.config.Channel, err)
+	}
+
+	rctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go r.run(rctx)
+	return nil
+}
+
+// Shutdown stops reading the channel
+func (r *windowsEventLogReceiver) Shutdown(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+	r.wg.Wait()
+
+	if err := r.source.close(); err != nil {
+		r.logger.Warn("Failed to close the subscription", zap.Error(err))
+	}
+	if r.client != nil {
+		return r.client.Close(ctx)
+	}
+	return nil
+}
+
+func getStorageClient(ctx context.Context, host component.Host, storageID config.ComponentID, id config.ComponentID) (storage.Client, error) {
+	ext, ok := host.GetExtensions()[storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension %s not found", storageID)
+	}
+	se, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("extension %s is not a storage extension", storageID)
+	}
+	client, err := se.GetClient(ctx, component.KindReceiver, id, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage client: %w", err)
+	}
+	return client, nil
+}
+
+// run reads the new events every PollInterval
+func (r *windowsEventLogReceiver) run(ctx context.Context) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.readEvents(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// readEvents passes the events to the pipeline until there are no new ones
+func (r *windowsEventLogReceiver) readEvents(ctx context.Context) {
+	for ctx.Err() == nil {
+		events, bookmark, err := r.source.read()
+		if err != nil {
+			r.logger.Error("Failed to read the events", zap.Error(err))
+			return
+		}
+		if len(events) == 0 {
+			return
+		}
+		r.consumeEvents(ctx, events, bookmark)
+	}
+}
+
+// consumeEvents passes the events to the pipeline and persists the bookmark of the last one
+func (r *windowsEventLogReceiver) consumeEvents(ctx context.Context, data []string, bookmark string) {
+	events := make([]event, 0, len(data))
+	for _, d := range data {
+		e, err := parseEvent(d)
+		if err != nil {
+			r.logger.Warn("Skipping invalid event", zap.Error(err))
+			continue
+		}
+		events = append(events, e)
+	}
+
+	if len(events) > 0 {
+		if err := r.consumer.ConsumeLogs(ctx, eventsToLogs(events)); err != nil {
+			r.logger.Error("ConsumeLogs() error", zap.Error(err))
+		}
+	}
+
+	r.bookmark = bookmark
+	if r.client != nil {
+		if err := r.client.Set(ctx, bookmarkStorageKey, []byte(r.bookmark)); err != nil {
+			r.logger.Warn("Failed to persist the bookmark", zap.Error(err))
+		}
+	}
+}
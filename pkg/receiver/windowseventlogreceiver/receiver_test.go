@@ -0,0 +1,161 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowseventlogreceiver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+// memoryStorage is a storage extension keeping the data in memory
+type memoryStorage struct {
+	component.Extension
+	lock sync.Mutex
+	data map[string][]byte
+}
+
+func (ms *memoryStorage) GetClient(context.Context, component.Kind, config.ComponentID, string) (storage.Client, error) {
+	return &memoryStorageClient{ms: ms}, nil
+}
+
+func (ms *memoryStorage) get(key string) []byte {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	return ms.data[key]
+}
+
+type memoryStorageClient struct {
+	storage.Client
+	ms *memoryStorage
+}
+
+func (c *memoryStorageClient) Get(_ context.Context, key string) ([]byte, error) {
+	return c.ms.get(key), nil
+}
+
+func (c *memoryStorageClient) Set(_ context.Context, key string, value []byte) error {
+	c.ms.lock.Lock()
+	defer c.ms.lock.Unlock()
+	c.ms.data[key] = value
+	return nil
+}
+
+func (c *memoryStorageClient) Close(context.Context) error {
+	return nil
+}
+
+type storageTestHost struct {
+	component.Host
+	extensions map[config.ComponentID]component.Extension
+}
+
+func (h storageTestHost) GetExtensions() map[config.ComponentID]component.Extension {
+	return h.extensions
+}
+
+// fakeSource returns the batches of events, using their number as the bookmark
+type fakeSource struct {
+	lock         sync.Mutex
+	openBookmark string
+	batches      [][]string
+	count        int
+	closed       bool
+}
+
+func (s *fakeSource) open(bookmark string) error {
+	s.openBookmark = bookmark
+	return nil
+}
+
+func (s *fakeSource) read() ([]string, string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.batches) == 0 {
+		return nil, "", nil
+	}
+	batch := s.batches[0]
+	s.batches = s.batches[1:]
+	s.count += len(batch)
+	return batch, fmt.Sprintf("bookmark-%d", s.count), nil
+}
+
+func (s *fakeSource) close() error {
+	s.closed = true
+	return nil
+}
+
+func TestReceiverResumesFromBookmark(t *testing.T) {
+	rendered := readTestEvent(t, "rendered.xml")
+	raw := readTestEvent(t, "raw.xml")
+
+	storageID := config.NewComponentID("storage")
+	ms := &memoryStorage{data: map[string][]byte{bookmarkStorageKey: []byte("bookmark-0")}}
+	host := storageTestHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[config.ComponentID]component.Extension{storageID: ms},
+	}
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Channel = "System"
+	cfg.StorageID = &storageID
+	cfg.PollInterval = time.Minute
+
+	source := &fakeSource{
+		batches: [][]string{
+			{rendered, "invalid event"},
+			{raw},
+		},
+	}
+	sink := new(consumertest.LogsSink)
+	r := newWindowsEventLogReceiver(cfg, zap.NewNop(), sink)
+	r.source = source
+	require.NoError(t, r.Start(context.Background(), host))
+
+	// all the batches available are read at once, without waiting for the poll interval
+	assert.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	assert.Equal(t, "bookmark-0", source.openBookmark)
+	assert.True(t, source.closed)
+	assert.Equal(t, "bookmark-3", string(ms.get(bookmarkStorageKey)))
+}
+
+func TestReceiverWithoutStorage(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Channel = "System"
+
+	source := &fakeSource{}
+	r := newWindowsEventLogReceiver(cfg, zap.NewNop(), consumertest.NewNop())
+	r.source = source
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	assert.Equal(t, "", source.openBookmark)
+}
@@ -0,0 +1,45 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package windowseventlogreceiver
+
+import (
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+var errUnsupportedPlatform = errors.New("the Windows Event Log receiver is supported on Windows only")
+
+// unsupportedSource is used on the platforms without the Windows Event Log
+type unsupportedSource struct{}
+
+func newEventSource(*Config, *zap.Logger) eventSource {
+	return unsupportedSource{}
+}
+
+func (unsupportedSource) open(string) error {
+	return errUnsupportedPlatform
+}
+
+func (unsupportedSource) read() ([]string, string, error) {
+	return nil, "", errUnsupportedPlatform
+}
+
+func (unsupportedSource) close() error {
+	return nil
+}
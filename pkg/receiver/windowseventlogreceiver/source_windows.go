@@ -0,0 +1,238 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+// +build windows
+
+package windowseventlogreceiver
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	"golang.org/x/sys/windows"
+)
+
+// initialBufferSize is the initial size of the buffer for the rendered events, in characters
+const initialBufferSize = 16 * 1024
+
+// subscription pulls the events of the channel using the Windows Event Log API
+type subscription struct {
+	config *Config
+	logger *zap.Logger
+
+	signalEvent windows.Handle
+	handle      evtHandle
+	bookmark    evtHandle
+	// publishers caches the metadata of the providers used to format the messages,
+	// the zero handle is cached for the providers which metadata couldn't be opened
+	publishers map[string]evtHandle
+	buffer     []uint16
+}
+
+func newEventSource(cfg *Config, logger *zap.Logger) eventSource {
+	return &subscription{
+		config:     cfg,
+		logger:     logger,
+		publishers: make(map[string]evtHandle),
+		buffer:     make([]uint16, initialBufferSize),
+	}
+}
+
+func (s *subscription) open(bookmark string) error {
+	channel, err := windows.UTF16PtrFromString(s.config.Channel)
+	if err != nil {
+		return err
+	}
+	query, err := windows.UTF16PtrFromString(s.config.Query)
+	if err != nil {
+		return err
+	}
+
+	var flags uint32 = evtSubscribeToFutureEvents
+	if s.config.StartAt == StartAtBeginning {
+		flags = evtSubscribeStartAtOldestRecord
+	}
+	if bookmark != "" {
+		if s.bookmark, err = createBookmark(bookmark); err != nil {
+			s.logger.Warn("Failed to resume reading the channel from the bookmark, starting from start_at",
+				zap.String("start_at", s.config.StartAt),
+				zap.Error(err),
+			)
+		} else {
+			flags = evtSubscribeStartAfterBookmark
+		}
+	}
+	if s.bookmark == 0 {
+		if s.bookmark, err = evtCreateBookmark(nil); err != nil {
+			return fmt.Errorf("failed to create bookmark: %w", err)
+		}
+	}
+
+	if s.signalEvent, err = windows.CreateEvent(nil, 1, 1, nil); err != nil {
+		return fmt.Errorf("failed to create signal event: %w", err)
+	}
+
+	var subscriptionBookmark evtHandle
+	if flags == evtSubscribeStartAfterBookmark {
+		subscriptionBookmark = s.bookmark
+	}
+	if s.handle, err = evtSubscribe(s.signalEvent, channel, query, subscriptionBookmark, flags); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *subscription) read() ([]string, string, error) {
+	handles := make([]evtHandle, s.config.MaxReads)
+	n, err := evtNext(s.handle, handles)
+	if errors.Is(err, errorNoMoreItems) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get the next events: %w", err)
+	}
+	handles = handles[:n]
+	defer func() {
+		for _, handle := range handles {
+			_ = evtClose(handle)
+		}
+	}()
+
+	events := make([]string, 0, len(handles))
+	for _, handle := range handles {
+		event, err := s.renderEvent(handle)
+		if err != nil {
+			s.logger.Warn("Skipping event which couldn't be rendered", zap.Error(err))
+		} else {
+			events = append(events, event)
+		}
+		if err := evtUpdateBookmark(s.bookmark, handle); err != nil {
+			return nil, "", fmt.Errorf("failed to update the bookmark: %w", err)
+		}
+	}
+
+	bookmark, err := s.render(s.bookmark, evtRenderBookmark)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to render the bookmark: %w", err)
+	}
+	return events, bookmark, nil
+}
+
+func (s *subscription) close() error {
+	var errs []error
+	if s.handle != 0 {
+		errs = append(errs, evtClose(s.handle))
+	}
+	if s.bookmark != 0 {
+		errs = append(errs, evtClose(s.bookmark))
+	}
+	for _, publisher := range s.publishers {
+		if publisher != 0 {
+			errs = append(errs, evtClose(publisher))
+		}
+	}
+	if s.signalEvent != 0 {
+		errs = append(errs, windows.CloseHandle(s.signalEvent))
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderEvent renders the event as XML, with the message formatted using
+// the message files of its provider if RenderMessage is set
+func (s *subscription) renderEvent(event evtHandle) (string, error) {
+	data, err := s.render(event, evtRenderEventXML)
+	if err != nil || !s.config.RenderMessage {
+		return data, err
+	}
+
+	provider, err := providerName(data)
+	if err != nil {
+		return "", err
+	}
+	publisher := s.publisher(provider)
+	if publisher == 0 {
+		return data, nil
+	}
+
+	formatted, err := s.formatMessage(publisher, event)
+	if err != nil {
+		// e.g. the event ID is missing in the message files of the provider
+		s.logger.Debug("Failed to format the message of the event",
+			zap.String("provider", provider),
+			zap.Error(err),
+		)
+		return data, nil
+	}
+	return formatted, nil
+}
+
+// publisher returns the metadata of the provider, or zero handle if it's not available,
+// e.g. the provider is not installed on this computer
+func (s *subscription) publisher(provider string) evtHandle {
+	if publisher, ok := s.publishers[provider]; ok {
+		return publisher
+	}
+
+	var publisher evtHandle
+	name, err := windows.UTF16PtrFromString(provider)
+	if err == nil {
+		publisher, err = evtOpenPublisherMetadata(name)
+	}
+	if err != nil {
+		s.logger.Debug("The messages of the provider won't be rendered", zap.String("provider", provider), zap.Error(err))
+	}
+	s.publishers[provider] = publisher
+	return publisher
+}
+
+func (s *subscription) render(handle evtHandle, flags uint32) (string, error) {
+	used, err := evtRender(handle, flags, s.buffer)
+	if errors.Is(err, errorInsufficientBuffer) {
+		// used is the required size in bytes
+		s.buffer = make([]uint16, used/2+1)
+		used, err = evtRender(handle, flags, s.buffer)
+	}
+	if err != nil {
+		return "", err
+	}
+	return windows.UTF16ToString(s.buffer[:used/2]), nil
+}
+
+func (s *subscription) formatMessage(publisher evtHandle, event evtHandle) (string, error) {
+	used, err := evtFormatMessage(publisher, event, evtFormatMessageXML, s.buffer)
+	if errors.Is(err, errorInsufficientBuffer) {
+		// used is the required size in characters
+		s.buffer = make([]uint16, used)
+		used, err = evtFormatMessage(publisher, event, evtFormatMessageXML, s.buffer)
+	}
+	if err != nil {
+		return "", err
+	}
+	return windows.UTF16ToString(s.buffer[:used]), nil
+}
+
+func createBookmark(bookmark string) (evtHandle, error) {
+	xml, err := windows.UTF16PtrFromString(bookmark)
+	if err != nil {
+		return 0, err
+	}
+	return evtCreateBookmark(xml)
+}
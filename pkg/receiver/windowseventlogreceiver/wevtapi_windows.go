@@ -0,0 +1,174 @@
+// Copyright 2022, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+// +build windows
+
+package windowseventlogreceiver
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// evtHandle is the handle of the object of the Windows Event Log API
+type evtHandle uintptr
+
+// https://docs.microsoft.com/en-us/windows/win32/api/winevt/ne-winevt-evt_subscribe_flags
+const (
+	evtSubscribeToFutureEvents      = 1
+	evtSubscribeStartAtOldestRecord = 2
+	evtSubscribeStartAfterBookmark  = 3
+)
+
+// https://docs.microsoft.com/en-us/windows/win32/api/winevt/ne-winevt-evt_render_flags
+const (
+	evtRenderEventXML = 1
+	evtRenderBookmark = 2
+)
+
+// https://docs.microsoft.com/en-us/windows/win32/api/winevt/ne-winevt-evt_format_message_flags
+const evtFormatMessageXML = 9
+
+const (
+	errorInsufficientBuffer windows.Errno = 122
+	errorNoMoreItems        windows.Errno = 259
+)
+
+var (
+	wevtapi = windows.NewLazySystemDLL("wevtapi.dll")
+
+	procEvtSubscribe             = wevtapi.NewProc("EvtSubscribe")
+	procEvtNext                  = wevtapi.NewProc("EvtNext")
+	procEvtRender                = wevtapi.NewProc("EvtRender")
+	procEvtClose                 = wevtapi.NewProc("EvtClose")
+	procEvtCreateBookmark        = wevtapi.NewProc("EvtCreateBookmark")
+	procEvtUpdateBookmark        = wevtapi.NewProc("EvtUpdateBookmark")
+	procEvtOpenPublisherMetadata = wevtapi.NewProc("EvtOpenPublisherMetadata")
+	procEvtFormatMessage         = wevtapi.NewProc("EvtFormatMessage")
+)
+
+func evtSubscribe(signalEvent windows.Handle, channel *uint16, query *uint16, bookmark evtHandle, flags uint32) (evtHandle, error) {
+	r, _, err := procEvtSubscribe.Call(
+		0, // local session
+		uintptr(signalEvent),
+		uintptr(unsafe.Pointer(channel)),
+		uintptr(unsafe.Pointer(query)),
+		uintptr(bookmark),
+		0, // context
+		0, // callback, the subscription is pulled with EvtNext
+		uintptr(flags),
+	)
+	if r == 0 {
+		return 0, err
+	}
+	return evtHandle(r), nil
+}
+
+// evtNext fills the events with the next ones of the subscription and returns their number
+func evtNext(subscription evtHandle, events []evtHandle) (int, error) {
+	var returned uint32
+	r, _, err := procEvtNext.Call(
+		uintptr(subscription),
+		uintptr(len(events)),
+		uintptr(unsafe.Pointer(&events[0])),
+		0, // timeout, return immediately
+		0, // flags
+		uintptr(unsafe.Pointer(&returned)),
+	)
+	if r == 0 {
+		return 0, err
+	}
+	return int(returned), nil
+}
+
+// evtRender renders the event or bookmark to the buffer and returns the number
+// of the bytes used, or required when the buffer is too small
+func evtRender(fragment evtHandle, flags uint32, buffer []uint16) (uint32, error) {
+	var used, propertyCount uint32
+	r, _, err := procEvtRender.Call(
+		0, // context
+		uintptr(fragment),
+		uintptr(flags),
+		uintptr(len(buffer)*2),
+		uintptr(unsafe.Pointer(&buffer[0])),
+		uintptr(unsafe.Pointer(&used)),
+		uintptr(unsafe.Pointer(&propertyCount)),
+	)
+	if r == 0 {
+		return used, err
+	}
+	return used, nil
+}
+
+// evtFormatMessage formats the event using the message files of the publisher and returns
+// the number of the characters used, or required when the buffer is too small
+func evtFormatMessage(publisher evtHandle, event evtHandle, flags uint32, buffer []uint16) (uint32, error) {
+	var used uint32
+	r, _, err := procEvtFormatMessage.Call(
+		uintptr(publisher),
+		uintptr(event),
+		0, // message ID
+		0, // value count
+		0, // values
+		uintptr(flags),
+		uintptr(len(buffer)),
+		uintptr(unsafe.Pointer(&buffer[0])),
+		uintptr(unsafe.Pointer(&used)),
+	)
+	if r == 0 {
+		return used, err
+	}
+	return used, nil
+}
+
+func evtOpenPublisherMetadata(publisher *uint16) (evtHandle, error) {
+	r, _, err := procEvtOpenPublisherMetadata.Call(
+		0, // local session
+		uintptr(unsafe.Pointer(publisher)),
+		0, // log file path
+		0, // locale of the user
+		0, // flags
+	)
+	if r == 0 {
+		return 0, err
+	}
+	return evtHandle(r), nil
+}
+
+// evtCreateBookmark creates the bookmark from its XML, or the empty one when xml is nil
+func evtCreateBookmark(xml *uint16) (evtHandle, error) {
+	r, _, err := procEvtCreateBookmark.Call(uintptr(unsafe.Pointer(xml)))
+	if r == 0 {
+		return 0, err
+	}
+	return evtHandle(r), nil
+}
+
+func evtUpdateBookmark(bookmark evtHandle, event evtHandle) error {
+	r, _, err := procEvtUpdateBookmark.Call(uintptr(bookmark), uintptr(event))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+func evtClose(handle evtHandle) error {
+	r, _, err := procEvtClose.Call(uintptr(handle))
+	if r == 0 {
+		return err
+	}
+	return nil
+}